@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/repository/gorm"
+	"github.com/traPtitech/traQ/utils/gormzap"
+	"github.com/traPtitech/traQ/utils/optional"
+)
+
+// auditCommand traQ監査ログ操作コマンド
+func auditCommand() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "audit",
+		Short: "manage audit logs",
+	}
+
+	cmd.AddCommand(
+		auditExportCommand(),
+	)
+
+	return &cmd
+}
+
+// auditExportCommand 監査ログをJSON Lines形式で書き出すコマンド
+func auditExportCommand() *cobra.Command {
+	var (
+		since  string
+		until  string
+		action string
+		actor  string
+		output string
+	)
+
+	cmd := cobra.Command{
+		Use:   "export",
+		Short: "export audit logs as JSON Lines",
+		Run: func(cmd *cobra.Command, args []string) {
+			// Logger
+			logger := getCLILogger()
+			if len(output) == 0 {
+				// 標準出力はログの書き出しに使うので、ロガーの出力先を標準エラー出力に変更
+				logger = logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+					return zapcore.NewCore(zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig()), zapcore.Lock(os.Stderr), core)
+				}))
+			}
+			defer logger.Sync()
+
+			query := repository.AuditLogQuery{
+				Action: model.AuditAction(action),
+				Limit:  500,
+				Asc:    true,
+			}
+			if len(since) > 0 {
+				t, err := time.Parse(time.RFC3339, since)
+				if err != nil {
+					logger.Fatal("invalid since", zap.Error(err))
+				}
+				query.Since = optional.TimeFrom(t)
+				query.Inclusive = true
+			}
+			if len(until) > 0 {
+				t, err := time.Parse(time.RFC3339, until)
+				if err != nil {
+					logger.Fatal("invalid until", zap.Error(err))
+				}
+				query.Until = optional.TimeFrom(t)
+			}
+			if len(actor) > 0 {
+				id, err := uuid.FromString(actor)
+				if err != nil {
+					logger.Fatal("invalid actor", zap.Error(err))
+				}
+				query.ActorID = optional.UUIDFrom(id)
+			}
+
+			// Database
+			logger.Info("connecting database...")
+			db, err := c.getDatabase()
+			if err != nil {
+				logger.Fatal("failed to connect database", zap.Error(err))
+			}
+			db.Logger = gormzap.New(logger.Named("gorm"))
+			sqlDB, err := db.DB()
+			if err != nil {
+				logger.Fatal("failed to get *sql.DB", zap.Error(err))
+			}
+			defer sqlDB.Close()
+
+			// Repository
+			repo, _, err := gorm.NewGormRepository(db, hub.New(), logger, false)
+			if err != nil {
+				logger.Fatal("failed to initialize repository", zap.Error(err))
+			}
+
+			var w io.Writer = os.Stdout
+			if len(output) > 0 {
+				f, err := os.Create(output)
+				if err != nil {
+					logger.Fatal("failed to create output file", zap.Error(err))
+				}
+				defer f.Close()
+				w = f
+			}
+			bw := bufio.NewWriter(w)
+			defer bw.Flush()
+			enc := json.NewEncoder(bw)
+
+			count := 0
+			for {
+				logs, more, err := repo.GetAuditLogs(query)
+				if err != nil {
+					logger.Fatal("failed to get audit logs", zap.Error(err))
+				}
+				for _, l := range logs {
+					if err := enc.Encode(l); err != nil {
+						logger.Fatal("failed to write audit log", zap.Error(err))
+					}
+				}
+				count += len(logs)
+				if !more {
+					break
+				}
+				query.Offset += len(logs)
+			}
+			logger.Info("audit logs exported", zap.Int("count", count))
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&since, "since", "", "export logs recorded at or after this time (RFC3339)")
+	flags.StringVar(&until, "until", "", "export logs recorded before this time (RFC3339)")
+	flags.StringVar(&action, "action", "", "export logs of this action only")
+	flags.StringVar(&actor, "actor", "", "export logs by this user ID only")
+	flags.StringVarP(&output, "output", "o", "", "output file path (default: stdout)")
+
+	return &cmd
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"cloud.google.com/go/profiler"
+	"github.com/leandro-lugaresi/hub"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 	"google.golang.org/api/option"
@@ -16,11 +17,13 @@ import (
 	"github.com/traPtitech/traQ/repository"
 	"github.com/traPtitech/traQ/router"
 	"github.com/traPtitech/traQ/router/auth"
+	"github.com/traPtitech/traQ/service/autoarchive"
 	"github.com/traPtitech/traQ/service/channel"
 	"github.com/traPtitech/traQ/service/counter"
 	"github.com/traPtitech/traQ/service/fcm"
 	"github.com/traPtitech/traQ/service/imaging"
 	"github.com/traPtitech/traQ/service/message"
+	"github.com/traPtitech/traQ/service/ratelimit"
 	"github.com/traPtitech/traQ/service/search"
 	"github.com/traPtitech/traQ/service/variable"
 	"github.com/traPtitech/traQ/utils/storage"
@@ -45,6 +48,12 @@ type Config struct {
 	// AllowSignUp ユーザーが自分自身で登録できるかどうか（default: false）
 	AllowSignUp bool `mapstructure:"allowSignUp" yaml:"allowSignUp"`
 
+	// RateLimit APIレート制限設定
+	RateLimit struct {
+		// Enabled 有効かどうか (default: true)
+		Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+	} `mapstructure:"rateLimit" yaml:"rateLimit"`
+
 	// AccessLog HTTPアクセスログ設定
 	AccessLog struct {
 		// Enabled 有効かどうか (default: true)
@@ -189,6 +198,26 @@ type Config struct {
 		} `mapstructure:"keys" yaml:"keys"`
 	} `mapstructure:"jwt" yaml:"jwt"`
 
+	// SCIM SCIMプロビジョニングAPI設定
+	SCIM struct {
+		// Token プロビジョニング用トークン 空の場合はSCIM APIを無効にします
+		Token string `mapstructure:"token" yaml:"token"`
+		// GroupAdmin SCIMで作成したグループの管理者にするユーザーの名前
+		GroupAdmin string `mapstructure:"groupAdmin" yaml:"groupAdmin"`
+	} `mapstructure:"scim" yaml:"scim"`
+
+	// ChannelArchival 非アクティブチャンネルの自動アーカイブ設定
+	ChannelArchival struct {
+		// Enabled 有効かどうか (default: false)
+		Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+		// InactiveDays この日数アクティビティの無い公開チャンネルをアーカイブの対象にします (default: 180)
+		InactiveDays int `mapstructure:"inactiveDays" yaml:"inactiveDays"`
+		// GraceDays アーカイブ予告からアーカイブまでの猶予日数 (default: 14)
+		GraceDays int `mapstructure:"graceDays" yaml:"graceDays"`
+		// Notifier アーカイブ予告メッセージの投稿・アーカイブを行うユーザーの名前 (default: traq)
+		Notifier string `mapstructure:"notifier" yaml:"notifier"`
+	} `mapstructure:"channelArchival" yaml:"channelArchival"`
+
 	// ExternalAuth 外部認証設定
 	ExternalAuth struct {
 		GitHub struct {
@@ -221,6 +250,17 @@ type Config struct {
 			AllowSignUp   bool   `mapstructure:"allowSignUp" yaml:"allowSignUp"`
 			AllowedTeamID string `mapstructure:"allowedTeamId" yaml:"allowedTeamId"`
 		} `mapstructure:"slack" yaml:"slack"`
+		SAML struct {
+			EntityID             string `mapstructure:"entityId" yaml:"entityId"`
+			IdPMetadataURL       string `mapstructure:"idpMetadataUrl" yaml:"idpMetadataUrl"`
+			IdPMetadataFile      string `mapstructure:"idpMetadataFile" yaml:"idpMetadataFile"`
+			Certificate          string `mapstructure:"certificate" yaml:"certificate"`
+			PrivateKey           string `mapstructure:"privateKey" yaml:"privateKey"`
+			NameAttribute        string `mapstructure:"nameAttribute" yaml:"nameAttribute"`
+			DisplayNameAttribute string `mapstructure:"displayNameAttribute" yaml:"displayNameAttribute"`
+			IconAttribute        string `mapstructure:"iconAttribute" yaml:"iconAttribute"`
+			AllowSignUp          bool   `mapstructure:"allowSignUp" yaml:"allowSignUp"`
+		} `mapstructure:"saml" yaml:"saml"`
 	} `mapstructure:"externalAuth" yaml:"externalAuth"`
 }
 
@@ -233,6 +273,7 @@ func init() {
 	viper.SetDefault("port", 3000)
 	viper.SetDefault("gzip", true)
 	viper.SetDefault("allowSignUp", false)
+	viper.SetDefault("rateLimit.enabled", true)
 	viper.SetDefault("accessLog.enabled", true)
 	viper.SetDefault("imagemagick", "")
 	viper.SetDefault("imaging.maxPixels", 2560*1600)
@@ -267,6 +308,12 @@ func init() {
 	viper.SetDefault("externalAuthentication.authPost.successfulCode", 0)
 	viper.SetDefault("externalAuthentication.authPost.formUserNameKey", "")
 	viper.SetDefault("externalAuthentication.authPost.formPasswordKey", "")
+	viper.SetDefault("scim.token", "")
+	viper.SetDefault("scim.groupAdmin", "traq")
+	viper.SetDefault("channelArchival.enabled", false)
+	viper.SetDefault("channelArchival.inactiveDays", 180)
+	viper.SetDefault("channelArchival.graceDays", 14)
+	viper.SetDefault("channelArchival.notifier", "traq")
 	viper.SetDefault("externalAuth.github.clientId", "")
 	viper.SetDefault("externalAuth.github.clientSecret", "")
 	viper.SetDefault("externalAuth.github.allowSignUp", false)
@@ -286,6 +333,15 @@ func init() {
 	viper.SetDefault("externalAuth.slack.clientId", "")
 	viper.SetDefault("externalAuth.slack.clientSecret", "")
 	viper.SetDefault("externalAuth.slack.allowSignUp", false)
+	viper.SetDefault("externalAuth.saml.entityId", "")
+	viper.SetDefault("externalAuth.saml.idpMetadataUrl", "")
+	viper.SetDefault("externalAuth.saml.idpMetadataFile", "")
+	viper.SetDefault("externalAuth.saml.certificate", "")
+	viper.SetDefault("externalAuth.saml.privateKey", "")
+	viper.SetDefault("externalAuth.saml.nameAttribute", "uid")
+	viper.SetDefault("externalAuth.saml.displayNameAttribute", "displayName")
+	viper.SetDefault("externalAuth.saml.iconAttribute", "")
+	viper.SetDefault("externalAuth.saml.allowSignUp", false)
 	viper.SetDefault("externalAuth.slack.allowedTeamId", "")
 	viper.SetDefault("skyway.secretKey", "")
 	viper.SetDefault("jwt.keys.private", "")
@@ -372,9 +428,9 @@ func newFCMClientIfAvailable(repo repository.Repository, logger *zap.Logger, unr
 	return fcm.NewNullClient(), nil
 }
 
-func initSearchServiceIfAvailable(mm message.Manager, cm channel.Manager, repo repository.Repository, logger *zap.Logger, config search.ESEngineConfig) (search.Engine, error) {
+func initSearchServiceIfAvailable(mm message.Manager, cm channel.Manager, repo repository.Repository, hub *hub.Hub, logger *zap.Logger, config search.ESEngineConfig) (search.Engine, error) {
 	if len(config.URL) > 0 {
-		return search.NewESEngine(mm, cm, repo, logger, config)
+		return search.NewESEngine(mm, cm, repo, hub, logger, config)
 	}
 	return search.NewNullEngine(), nil
 }
@@ -393,6 +449,22 @@ func provideESEngineConfig(c *Config) search.ESEngineConfig {
 	}
 }
 
+func provideAutoArchiveConfig(c *Config) autoarchive.Config {
+	return autoarchive.Config{
+		Enabled:        c.ChannelArchival.Enabled,
+		InactivePeriod: time.Duration(c.ChannelArchival.InactiveDays) * time.Hour * 24,
+		GracePeriod:    time.Duration(c.ChannelArchival.GraceDays) * time.Hour * 24,
+		NotifierName:   c.ChannelArchival.Notifier,
+	}
+}
+
+func provideRateLimitStore(c *Config) ratelimit.Store {
+	if !c.RateLimit.Enabled {
+		return nil
+	}
+	return ratelimit.NewMemoryStore()
+}
+
 func provideImageProcessorConfig(c *Config) imaging.Config {
 	return imaging.Config{
 		MaxPixels:        c.Imaging.MaxPixels,
@@ -451,6 +523,23 @@ func provideAuthSlackProviderConfig(c *Config) auth.SlackProviderConfig {
 	}
 }
 
+func provideAuthSAMLProviderConfig(c *Config) auth.SAMLProviderConfig {
+	return auth.SAMLProviderConfig{
+		EntityID:               c.ExternalAuth.SAML.EntityID,
+		MetadataURL:            c.Origin + "/api/auth/saml/metadata",
+		ACSURL:                 c.Origin + "/api/auth/saml/acs",
+		CallbackURL:            c.Origin + "/api/auth/saml/callback",
+		IdPMetadataURL:         c.ExternalAuth.SAML.IdPMetadataURL,
+		IdPMetadataFile:        c.ExternalAuth.SAML.IdPMetadataFile,
+		CertificateFile:        c.ExternalAuth.SAML.Certificate,
+		PrivateKeyFile:         c.ExternalAuth.SAML.PrivateKey,
+		NameAttribute:          c.ExternalAuth.SAML.NameAttribute,
+		DisplayNameAttribute:   c.ExternalAuth.SAML.DisplayNameAttribute,
+		IconAttribute:          c.ExternalAuth.SAML.IconAttribute,
+		RegisterUserIfNotFound: c.ExternalAuth.SAML.AllowSignUp,
+	}
+}
+
 func provideRouterExternalAuthConfig(c *Config) router.ExternalAuthConfig {
 	return router.ExternalAuthConfig{
 		GitHub: provideAuthGithubProviderConfig(c),
@@ -458,6 +547,7 @@ func provideRouterExternalAuthConfig(c *Config) router.ExternalAuthConfig {
 		TraQ:   provideAuthTraQProviderConfig(c),
 		OIDC:   provideAuthOIDCProviderConfig(c),
 		Slack:  provideAuthSlackProviderConfig(c),
+		SAML:   provideAuthSAMLProviderConfig(c),
 	}
 }
 
@@ -473,5 +563,8 @@ func provideRouterConfig(c *Config) *router.Config {
 		IsRefreshEnabled: c.OAuth2.IsRefreshEnabled,
 		SkyWaySecretKey:  c.SkyWay.SecretKey,
 		ExternalAuth:     provideRouterExternalAuthConfig(c),
+		Origin:           c.Origin,
+		SCIMToken:        c.SCIM.Token,
+		SCIMGroupAdmin:   c.SCIM.GroupAdmin,
 	}
 }
@@ -73,6 +73,7 @@ func init() {
 		confCommand(),
 		fileCommand(),
 		stampCommand(),
+		auditCommand(),
 		versionCommand(),
 		healthcheckCommand(),
 	)
@@ -16,6 +16,7 @@ import (
 	"github.com/traPtitech/traQ/event"
 	"github.com/traPtitech/traQ/repository"
 	"github.com/traPtitech/traQ/repository/gorm"
+	"github.com/traPtitech/traQ/router/scim"
 	"github.com/traPtitech/traQ/service"
 	"github.com/traPtitech/traQ/service/file"
 	"github.com/traPtitech/traQ/service/rbac/role"
@@ -149,6 +150,13 @@ func serveCommand() *cobra.Command {
 				logger.Info("data initialization finished")
 			}
 
+			// SCIMグループ管理者の確認
+			if len(c.SCIM.Token) > 0 {
+				if _, err := scim.GetGroupAdmin(repo, c.SCIM.GroupAdmin); err != nil {
+					logger.Fatal("invalid scim.groupAdmin config", zap.Error(err))
+				}
+			}
+
 			go func() {
 				if err := server.Start(fmt.Sprintf(":%d", c.Port)); err != nil {
 					logger.Info("shutting down the server")
@@ -218,6 +226,21 @@ func (s *Server) Shutdown(ctx context.Context) error {
 		s.L.Info("Bot shutdown")
 		return err
 	})
+	eg.Go(func() error {
+		err := s.SS.Webhook.Shutdown(ctx)
+		s.L.Info("Webhook shutdown")
+		return err
+	})
+	eg.Go(func() error {
+		err := s.SS.AutoArchive.Shutdown(ctx)
+		s.L.Info("AutoArchive shutdown")
+		return err
+	})
+	eg.Go(func() error {
+		err := s.SS.Retention.Shutdown(ctx)
+		s.L.Info("Retention shutdown")
+		return err
+	})
 	eg.Go(func() error {
 		err := s.SS.OGP.Shutdown()
 		s.L.Info("OGP shutdown")
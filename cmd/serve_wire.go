@@ -11,7 +11,9 @@ import (
 
 	"github.com/traPtitech/traQ/repository"
 	"github.com/traPtitech/traQ/router"
+	"github.com/traPtitech/traQ/router/utils"
 	"github.com/traPtitech/traQ/service"
+	"github.com/traPtitech/traQ/service/autoarchive"
 	"github.com/traPtitech/traQ/service/bot"
 	botWS "github.com/traPtitech/traQ/service/bot/ws"
 	"github.com/traPtitech/traQ/service/channel"
@@ -23,14 +25,18 @@ import (
 	"github.com/traPtitech/traQ/service/notification"
 	"github.com/traPtitech/traQ/service/ogp"
 	rbac2 "github.com/traPtitech/traQ/service/rbac"
+	"github.com/traPtitech/traQ/service/retention"
 	"github.com/traPtitech/traQ/service/viewer"
+	"github.com/traPtitech/traQ/service/webhook"
 	"github.com/traPtitech/traQ/service/webrtcv3"
 	"github.com/traPtitech/traQ/service/ws"
+	mutil "github.com/traPtitech/traQ/utils/message"
 	"github.com/traPtitech/traQ/utils/storage"
 )
 
 func newServer(hub *hub.Hub, db *gorm.DB, repo repository.Repository, fs storage.FileStorage, logger *zap.Logger, c *Config) (*Server, error) {
 	wire.Build(
+		autoarchive.NewService,
 		bot.NewService,
 		channel.InitChannelManager,
 		file.InitFileManager,
@@ -44,11 +50,15 @@ func newServer(hub *hub.Hub, db *gorm.DB, repo repository.Repository, fs storage
 		notification.NewService,
 		ogp.NewServiceImpl,
 		rbac2.New,
+		retention.NewService,
 		viewer.NewManager,
+		webhook.NewService,
 		webrtcv3.NewManager,
 		ws.NewStreamer,
 		botWS.NewStreamer,
 		router.Setup,
+		utils.NewReplaceMapper,
+		mutil.NewReplacer,
 		newFCMClientIfAvailable,
 		initSearchServiceIfAvailable,
 		provideServerOriginString,
@@ -56,6 +66,8 @@ func newServer(hub *hub.Hub, db *gorm.DB, repo repository.Repository, fs storage
 		provideImageProcessorConfig,
 		provideRouterConfig,
 		provideESEngineConfig,
+		provideAutoArchiveConfig,
+		provideRateLimitStore,
 		wire.Struct(new(service.Services), "*"),
 		wire.Struct(new(Server), "*"),
 		wire.Bind(new(repository.ChannelRepository), new(repository.Repository)),
@@ -1,14 +1,20 @@
 package cmd
 
 import (
+	"archive/zip"
+	"fmt"
+
+	"github.com/gofrs/uuid"
 	"github.com/leandro-lugaresi/hub"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 
+	"github.com/traPtitech/traQ/repository"
 	"github.com/traPtitech/traQ/repository/gorm"
 	"github.com/traPtitech/traQ/service/file"
 	"github.com/traPtitech/traQ/service/imaging"
 	"github.com/traPtitech/traQ/utils/gormzap"
+	"github.com/traPtitech/traQ/utils/stamppack"
 	"github.com/traPtitech/traQ/utils/twemoji"
 )
 
@@ -21,6 +27,7 @@ func stampCommand() *cobra.Command {
 
 	cmd.AddCommand(
 		stampInstallEmojisCommand(),
+		stampImportCommand(),
 	)
 
 	return &cmd
@@ -38,43 +45,130 @@ func stampInstallEmojisCommand() *cobra.Command {
 			logger := getCLILogger()
 			defer logger.Sync()
 
-			// Database
-			logger.Info("connecting database...")
-			db, err := c.getDatabase()
-			if err != nil {
-				logger.Fatal("failed to connect database", zap.Error(err))
-			}
-			db.Logger = gormzap.New(logger.Named("gorm"))
-			sqlDB, err := db.DB()
-			if err != nil {
-				logger.Fatal("failed to get *sql.DB", zap.Error(err))
+			repo, fm, _, closeDB := setupStampCLI(logger)
+			defer closeDB()
+
+			if err := twemoji.Install(repo, fm, logger, update); err != nil {
+				logger.Fatal(err.Error())
 			}
-			defer sqlDB.Close()
+		},
+	}
 
-			// FileStorage
-			fs, err := c.getFileStorage()
+	flags := cmd.Flags()
+	flags.BoolVar(&update, "update", false, "update(replace) existing Unicode emojiMeta stamp's image files and fill in missing categories")
+
+	return &cmd
+}
+
+// stampImportCommand スタンプパックからスタンプをインポートするコマンド
+func stampImportCommand() *cobra.Command {
+	var (
+		dryRun  bool
+		creator string
+	)
+
+	cmd := cobra.Command{
+		Use:   "import [pack.zip]",
+		Short: "import stamps from a stamp pack (or a Slack/Discord emoji export zip)",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			// Logger
+			logger := getCLILogger()
+			defer logger.Sync()
+
+			zr, err := zip.OpenReader(args[0])
 			if err != nil {
-				logger.Fatal("failed to setup file storage", zap.Error(err))
+				logger.Fatal("failed to open stamp pack", zap.Error(err))
 			}
+			defer zr.Close()
 
-			// Repository
-			repo, _, err := gorm.NewGormRepository(db, hub.New(), logger, false)
-			if err != nil {
-				logger.Fatal("failed to initialize repository", zap.Error(err))
+			repo, fm, processor, closeDB := setupStampCLI(logger)
+			defer closeDB()
+
+			creatorID := uuid.Nil
+			if len(creator) > 0 {
+				u, err := repo.GetUserByName(creator, false)
+				if err != nil {
+					if err == repository.ErrNotFound {
+						logger.Fatal("creator not found: " + creator)
+					}
+					logger.Fatal("failed to get creator", zap.Error(err))
+				}
+				creatorID = u.GetID()
 			}
-			fm, err := file.InitFileManager(repo, fs, imaging.NewProcessor(provideImageProcessorConfig(c)), logger)
+
+			results, err := stamppack.Import(repo, fm, processor, &zr.Reader, stamppack.ImportOptions{
+				CreatorID: creatorID,
+				DryRun:    dryRun,
+			})
 			if err != nil {
-				logger.Fatal("failed to initialize file manager", zap.Error(err))
+				// 途中でエラーになった場合も、それまでに作成されたスタンプは残る
+				for _, r := range results {
+					if r.Status == stamppack.ImportStatusCreated {
+						logger.Info(fmt.Sprintf("stamp added: %s (%s)", r.Name, r.StampID.UUID))
+					}
+				}
+				logger.Fatal("failed to import stamp pack", zap.Error(err))
 			}
 
-			if err := twemoji.Install(repo, fm, logger, update); err != nil {
-				logger.Fatal(err.Error())
+			counts := map[stamppack.ImportStatus]int{}
+			for _, r := range results {
+				counts[r.Status]++
+				switch r.Status {
+				case stamppack.ImportStatusCreated:
+					logger.Info(fmt.Sprintf("stamp added: %s (%s)", r.Name, r.StampID.UUID))
+				case stamppack.ImportStatusReady:
+					logger.Info("stamp can be added: " + r.Name)
+				default:
+					logger.Warn(fmt.Sprintf("stamp skipped (%s): %s: %s", r.Status, r.Name, r.Reason))
+				}
 			}
+			logger.Info(fmt.Sprintf("finished importing stamps: %d created, %d ready, %d conflicts, %d invalid",
+				counts[stamppack.ImportStatusCreated],
+				counts[stamppack.ImportStatusReady],
+				counts[stamppack.ImportStatusConflict],
+				counts[stamppack.ImportStatusInvalid],
+			))
 		},
 	}
 
 	flags := cmd.Flags()
-	flags.BoolVar(&update, "update", false, "update(replace) existing Unicode emojiMeta stamp's image files")
+	flags.BoolVar(&dryRun, "dry-run", false, "only validate the pack and report conflicts without creating stamps")
+	flags.StringVar(&creator, "creator", "", "name of the user to be set as the creator of imported stamps")
 
 	return &cmd
 }
+
+// setupStampCLI スタンプ操作コマンド用にリポジトリ・ファイルマネージャー・画像プロセッサーを初期化します
+func setupStampCLI(logger *zap.Logger) (repository.Repository, file.Manager, imaging.Processor, func()) {
+	// Database
+	logger.Info("connecting database...")
+	db, err := c.getDatabase()
+	if err != nil {
+		logger.Fatal("failed to connect database", zap.Error(err))
+	}
+	db.Logger = gormzap.New(logger.Named("gorm"))
+	sqlDB, err := db.DB()
+	if err != nil {
+		logger.Fatal("failed to get *sql.DB", zap.Error(err))
+	}
+
+	// FileStorage
+	fs, err := c.getFileStorage()
+	if err != nil {
+		logger.Fatal("failed to setup file storage", zap.Error(err))
+	}
+
+	// Repository
+	repo, _, err := gorm.NewGormRepository(db, hub.New(), logger, false)
+	if err != nil {
+		logger.Fatal("failed to initialize repository", zap.Error(err))
+	}
+	processor := imaging.NewProcessor(provideImageProcessorConfig(c))
+	fm, err := file.InitFileManager(repo, fs, processor, logger)
+	if err != nil {
+		logger.Fatal("failed to initialize file manager", zap.Error(err))
+	}
+
+	return repo, fm, processor, func() { _ = sqlDB.Close() }
+}
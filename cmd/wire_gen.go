@@ -10,7 +10,9 @@ import (
 	"github.com/leandro-lugaresi/hub"
 	"github.com/traPtitech/traQ/repository"
 	"github.com/traPtitech/traQ/router"
+	"github.com/traPtitech/traQ/router/utils"
 	"github.com/traPtitech/traQ/service"
+	"github.com/traPtitech/traQ/service/autoarchive"
 	"github.com/traPtitech/traQ/service/bot"
 	"github.com/traPtitech/traQ/service/bot/ws"
 	"github.com/traPtitech/traQ/service/channel"
@@ -22,9 +24,12 @@ import (
 	"github.com/traPtitech/traQ/service/notification"
 	"github.com/traPtitech/traQ/service/ogp"
 	"github.com/traPtitech/traQ/service/rbac"
+	"github.com/traPtitech/traQ/service/retention"
 	"github.com/traPtitech/traQ/service/viewer"
+	"github.com/traPtitech/traQ/service/webhook"
 	"github.com/traPtitech/traQ/service/webrtcv3"
 	ws2 "github.com/traPtitech/traQ/service/ws"
+	message2 "github.com/traPtitech/traQ/utils/message"
 	"github.com/traPtitech/traQ/utils/storage"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -42,7 +47,18 @@ func newServer(hub2 *hub.Hub, db *gorm.DB, repo repository.Repository, fs storag
 		return nil, err
 	}
 	webrtcv3Manager := webrtcv3.NewManager(hub2)
-	streamer := ws.NewStreamer(hub2, webrtcv3Manager, logger)
+	messageManager, err := message.NewMessageManager(repo, manager, hub2, logger)
+	if err != nil {
+		return nil, err
+	}
+	rbacRBAC, err := rbac.New(repo)
+	if err != nil {
+		return nil, err
+	}
+	store := provideRateLimitStore(c2)
+	replaceMapper := utils.NewReplaceMapper(repo, manager)
+	replacer := message2.NewReplacer(replaceMapper)
+	streamer := ws.NewStreamer(hub2, webrtcv3Manager, repo, manager, messageManager, rbacRBAC, store, replacer, logger)
 	botService := bot.NewService(repo, manager, hub2, streamer, logger)
 	onlineCounter := counter.NewOnlineCounter(hub2)
 	unreadMessageCounter, err := counter.NewUnreadMessageCounter(db, hub2)
@@ -57,10 +73,6 @@ func newServer(hub2 *hub.Hub, db *gorm.DB, repo repository.Repository, fs storag
 	if err != nil {
 		return nil, err
 	}
-	messageManager, err := message.NewMessageManager(repo, manager, logger)
-	if err != nil {
-		return nil, err
-	}
 	stampThrottler := exevent.NewStampThrottler(hub2, messageManager)
 	firebaseCredentialsFilePathString := provideFirebaseCredentialsFilePathString(c2)
 	client, err := newFCMClientIfAvailable(repo, logger, unreadMessageCounter, firebaseCredentialsFilePathString)
@@ -81,16 +93,20 @@ func newServer(hub2 *hub.Hub, db *gorm.DB, repo repository.Repository, fs storag
 	if err != nil {
 		return nil, err
 	}
-	rbacRBAC, err := rbac.New(repo)
+	esEngineConfig := provideESEngineConfig(c2)
+	engine, err := initSearchServiceIfAvailable(messageManager, manager, repo, hub2, logger, esEngineConfig)
 	if err != nil {
 		return nil, err
 	}
-	esEngineConfig := provideESEngineConfig(c2)
-	engine, err := initSearchServiceIfAvailable(messageManager, manager, repo, logger, esEngineConfig)
+	webhookService, err := webhook.NewService(repo, messageManager, hub2, logger)
 	if err != nil {
 		return nil, err
 	}
+	autoarchiveConfig := provideAutoArchiveConfig(c2)
+	autoarchiveService := autoarchive.NewService(repo, manager, messageManager, logger, autoarchiveConfig)
+	retentionService := retention.NewService(repo, manager, logger)
 	services := &service.Services{
+		AutoArchive:          autoarchiveService,
 		BOT:                  botService,
 		ChannelManager:       manager,
 		OnlineCounter:        onlineCounter,
@@ -104,9 +120,12 @@ func newServer(hub2 *hub.Hub, db *gorm.DB, repo repository.Repository, fs storag
 		MessageManager:       messageManager,
 		Notification:         notificationService,
 		OGP:                  ogpService,
+		RateLimitStore:       store,
 		RBAC:                 rbacRBAC,
+		Retention:            retentionService,
 		Search:               engine,
 		ViewerManager:        viewerManager,
+		Webhook:              webhookService,
 		WebRTCv3:             webrtcv3Manager,
 		WS:                   wsStreamer,
 		BotWS:                streamer,
@@ -85,6 +85,19 @@ const (
 	// 		user_id: uuid.UUID
 	UserGroupAdminRemoved = "user_group.admin.removed"
 
+	// UserRoleCreated ユーザーロールが作成された
+	// 	Fields:
+	// 		role_name: string
+	UserRoleCreated = "user_role.created"
+	// UserRoleUpdated ユーザーロールが更新された
+	// 	Fields:
+	// 		role_name: string
+	UserRoleUpdated = "user_role.updated"
+	// UserRoleDeleted ユーザーロールが削除された
+	// 	Fields:
+	// 		role_name: string
+	UserRoleDeleted = "user_role.deleted"
+
 	// MessageCreated メッセージが作成された
 	// 	Fields:
 	// 		message_id: uuid.UUID
@@ -187,6 +200,17 @@ const (
 	// 	Fields:
 	// 		channel_id: uuid.UUID
 	ChannelSubscribersChanged = "channel.subscribers_changed"
+	// ChannelMembersChanged プライベートチャンネルのメンバーが変化した
+	// 	Fields:
+	// 		channel_id: uuid.UUID
+	// 		added: []uuid.UUID
+	// 		removed: []uuid.UUID
+	ChannelMembersChanged = "channel.members_changed"
+	// ChannelMerged チャンネルが別のチャンネルに統合された
+	// 	Fields:
+	// 		channel_id: uuid.UUID	統合元チャンネルのID
+	// 		target_id: uuid.UUID	統合先チャンネルのID
+	ChannelMerged = "channel.merged"
 
 	// StampCreated スタンプが作成された
 	// 	Fields:
@@ -212,12 +236,24 @@ const (
 	// 	Fields:
 	// 		user_id: uuid.UUID
 	// 		stamp_palette_id: uuid.UUID
+	// 		subscriber_ids: []uuid.UUID (更新前の購読者)
 	StampPaletteUpdated = "stamp_palette.updated"
 	// StampPaletteDeleted スタンプパレットが削除された
 	// 	Fields:
 	// 		user_id: uuid.UUID
 	// 		stamp_palette_id: uuid.UUID
+	// 		subscriber_ids: []uuid.UUID (削除前の購読者)
 	StampPaletteDeleted = "stamp_palette.deleted"
+	// StampPaletteSubscribed スタンプパレットが購読された
+	// 	Fields:
+	// 		user_id: uuid.UUID
+	// 		stamp_palette_id: uuid.UUID
+	StampPaletteSubscribed = "stamp_palette.subscribed"
+	// StampPaletteUnsubscribed スタンプパレットの購読が解除された
+	// 	Fields:
+	// 		user_id: uuid.UUID
+	// 		stamp_palette_id: uuid.UUID
+	StampPaletteUnsubscribed = "stamp_palette.unsubscribed"
 
 	// WebhookCreated Webhookが作成された
 	// 	Fields:
@@ -245,6 +281,7 @@ const (
 	// BotDeleted Botが削除された
 	// 	Fields:
 	// 		bot_id: uuid.UUID
+	// 		bot_user_id: uuid.UUID
 	BotDeleted = "bot.deleted"
 	// BotStateChanged Botの状態が変化した
 	// 	Fields:
@@ -330,6 +367,11 @@ const (
 	// 		clip_folder_message: *model.ClipFolderMessage
 	ClipFolderMessageAdded = "clip_folder_message.added"
 
+	// SidebarUpdated ユーザーのサイドバーセクションが更新された
+	// 	Fields:
+	// 		user_id: uuid.UUID
+	SidebarUpdated = "sidebar.updated"
+
 	// MessageStampsUpdated メッセージに押されているスタンプが変化した。このイベントはスロットリングされています
 	// 	Fields:
 	// 		message_id: uuid.UUID
@@ -41,6 +41,23 @@ func Migrations() []*gormigrate.Migration {
 		v28(), // v28 ユーザーグループにアイコンを追加
 		v29(), // BotにModeを追加、WebSocket Modeを追加
 		v30(), // bot_event_logsにresultを追加
+		v31(), // Outgoing Webhookの追加
+		v32(), // Webhookメッセージテンプレートの追加
+		v33(), // WebhookにJSONペイロード用テンプレートを追加
+		v34(), // 二要素認証の追加
+		v35(), // OAuth2のきめ細かいスコープの追加
+		v36(), // パーソナルアクセストークンの追加
+		v37(), // チャンネルロールの追加
+		v38(), // 監査ログの追加
+		v39(), // グループDMの追加
+		v40(), // チャンネルテンプレートの追加
+		v41(), // 非アクティブチャンネルの自動アーカイブ状態の追加
+		v42(), // チャンネルのメッセージ保持ポリシーの追加
+		v43(), // ユーザー定義のサイドバーセクションの追加
+		v44(), // スタンプのカテゴリー・エイリアス・タグの追加
+		v45(), // スタンプパレットの公開範囲・購読の追加
+		v46(), // TOTPの使用済みタイムステップ・二要素認証のロック状態の追加
+		v47(), // プライベートチャンネルをチャンネル名の一意制約から除外
 	}
 }
 
@@ -53,6 +70,14 @@ func AllTables() []interface{} {
 		&model.UserRole{},
 		&model.RolePermission{},
 		&model.DMChannelMapping{},
+		&model.GroupDMChannelMapping{},
+		&model.ChannelArchivalState{},
+		&model.ChannelRetentionPolicy{},
+		&model.SidebarItem{},
+		&model.SidebarSection{},
+		&model.StampAlias{},
+		&model.StampTag{},
+		&model.StampPaletteSubscription{},
 		&model.ChannelLatestMessage{},
 		&model.BotEventLog{},
 		&model.BotJoinChannel{},
@@ -62,6 +87,7 @@ func AllTables() []interface{} {
 		&model.OAuth2Token{},
 		&model.MessageReport{},
 		&model.WebhookBot{},
+		&model.WebhookDeliveryLog{},
 		&model.Stamp{},
 		&model.UsersTag{},
 		&model.Unread{},
@@ -73,6 +99,7 @@ func AllTables() []interface{} {
 		&model.FileMeta{},
 		&model.UsersPrivateChannel{},
 		&model.UserSubscribeChannel{},
+		&model.ChannelRole{},
 		&model.Tag{},
 		&model.ArchivedMessage{},
 		&model.ClipFolderMessage{},
@@ -82,6 +109,14 @@ func AllTables() []interface{} {
 		&model.UserGroupAdmin{},
 		&model.UserGroupMember{},
 		&model.ExternalProviderUser{},
+		&model.UserTOTP{},
+		&model.UserTwoFactorLockout{},
+		&model.UserRecoveryCode{},
+		&model.WebAuthnCredential{},
+		&model.TwoFactorRequiredRole{},
+		&model.PersonalAccessToken{},
+		&model.AuditLog{},
+		&model.ChannelTemplate{},
 		&model.UserProfile{},
 		&model.Channel{},
 		&model.ClipFolder{},
@@ -0,0 +1,53 @@
+package migration
+
+import (
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// v31 Outgoing Webhookの追加
+func v31() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "31",
+		Migrate: func(db *gorm.DB) error {
+			return db.AutoMigrate(&v31WebhookBot{}, &v31WebhookDeliveryLog{})
+		},
+	}
+}
+
+type v31WebhookBot struct {
+	ID           uuid.UUID      `gorm:"type:char(36);not null;primaryKey"`
+	BotUserID    uuid.UUID      `gorm:"type:char(36);not null;unique"`
+	Description  string         `gorm:"type:text;not null"`
+	Secret       string         `gorm:"type:text;not null"`
+	ChannelID    uuid.UUID      `gorm:"type:char(36);not null"`
+	CreatorID    uuid.UUID      `gorm:"type:char(36);not null"`
+	OutgoingURL  string         `gorm:"type:text;not null"` // added
+	TriggerWords string         `gorm:"type:text;not null"` // added
+	CreatedAt    time.Time      `gorm:"precision:6"`
+	UpdatedAt    time.Time      `gorm:"precision:6"`
+	DeletedAt    gorm.DeletedAt `gorm:"precision:6"`
+}
+
+func (*v31WebhookBot) TableName() string {
+	return "webhook_bots"
+}
+
+type v31WebhookDeliveryLog struct {
+	RequestID uuid.UUID `gorm:"type:char(36);not null;primaryKey"`
+	WebhookID uuid.UUID `gorm:"type:char(36);not null;index:webhook_id_date_time_idx"`
+	MessageID uuid.UUID `gorm:"type:char(36);not null"`
+	Body      string    `gorm:"type:text"`
+	Result    string    `gorm:"type:char(2);not null"`
+	Error     string    `gorm:"type:text"`
+	Code      int       `gorm:"not null;default:0"`
+	Latency   int64     `gorm:"not null;default:0"`
+	DateTime  time.Time `gorm:"precision:6;index:webhook_id_date_time_idx"`
+}
+
+func (*v31WebhookDeliveryLog) TableName() string {
+	return "webhook_delivery_logs"
+}
@@ -0,0 +1,38 @@
+package migration
+
+import (
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// v32 Webhookメッセージテンプレートの追加
+func v32() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "32",
+		Migrate: func(db *gorm.DB) error {
+			return db.AutoMigrate(&v32WebhookBot{})
+		},
+	}
+}
+
+type v32WebhookBot struct {
+	ID           uuid.UUID      `gorm:"type:char(36);not null;primaryKey"`
+	BotUserID    uuid.UUID      `gorm:"type:char(36);not null;unique"`
+	Description  string         `gorm:"type:text;not null"`
+	Secret       string         `gorm:"type:text;not null"`
+	ChannelID    uuid.UUID      `gorm:"type:char(36);not null"`
+	CreatorID    uuid.UUID      `gorm:"type:char(36);not null"`
+	OutgoingURL  string         `gorm:"type:text;not null"`
+	TriggerWords string         `gorm:"type:text;not null"`
+	Templates    string         `gorm:"type:text"` // added
+	CreatedAt    time.Time      `gorm:"precision:6"`
+	UpdatedAt    time.Time      `gorm:"precision:6"`
+	DeletedAt    gorm.DeletedAt `gorm:"precision:6"`
+}
+
+func (*v32WebhookBot) TableName() string {
+	return "webhook_bots"
+}
@@ -0,0 +1,106 @@
+package migration
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// v34 二要素認証の追加
+func v34() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "34",
+		Migrate: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&v34UserTOTP{}, &v34UserRecoveryCode{}, &v34WebAuthnCredential{}, &v34TwoFactorRequiredRole{}); err != nil {
+				return err
+			}
+
+			foreignKeys := [][6]string{
+				// table name, constraint name, field name, references, on delete, on update
+				{"user_totps", "user_totps_user_id_users_id_foreign", "user_id", "users(id)", "CASCADE", "CASCADE"},
+				{"user_recovery_codes", "user_recovery_codes_user_id_users_id_foreign", "user_id", "users(id)", "CASCADE", "CASCADE"},
+				{"user_webauthn_credentials", "user_webauthn_credentials_user_id_users_id_foreign", "user_id", "users(id)", "CASCADE", "CASCADE"},
+			}
+			for _, c := range foreignKeys {
+				if err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s ON DELETE %s ON UPDATE %s", c[0], c[1], c[2], c[3], c[4], c[5])).Error; err != nil {
+					return err
+				}
+			}
+
+			addedRolePermissions := map[string][]string{
+				"user": {
+					"get_my_two_factor_auth",
+					"edit_my_two_factor_auth",
+				},
+			}
+			for role, perms := range addedRolePermissions {
+				for _, perm := range perms {
+					if err := db.Create(&v34RolePermission{Role: role, Permission: perm}).Error; err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+	}
+}
+
+type v34UserTOTP struct {
+	UserID    uuid.UUID `gorm:"type:char(36);not null;primaryKey"`
+	Secret    string    `gorm:"type:varchar(64);not null"`
+	Enabled   bool      `gorm:"type:boolean;not null;default:false"`
+	CreatedAt time.Time `gorm:"precision:6"`
+	UpdatedAt time.Time `gorm:"precision:6"`
+}
+
+func (*v34UserTOTP) TableName() string {
+	return "user_totps"
+}
+
+type v34UserRecoveryCode struct {
+	UserID    uuid.UUID `gorm:"type:char(36);not null;primaryKey"`
+	CodeHash  string    `gorm:"type:char(64);not null;primaryKey"`
+	CreatedAt time.Time `gorm:"precision:6"`
+}
+
+func (*v34UserRecoveryCode) TableName() string {
+	return "user_recovery_codes"
+}
+
+type v34WebAuthnCredential struct {
+	ID              uuid.UUID `gorm:"type:char(36);not null;primaryKey"`
+	UserID          uuid.UUID `gorm:"type:char(36);not null;index"`
+	Name            string    `gorm:"type:varchar(32);not null"`
+	CredentialID    []byte    `gorm:"type:varbinary(255);not null;unique"`
+	PublicKey       []byte    `gorm:"type:blob;not null"`
+	AttestationType string    `gorm:"type:varchar(32);not null"`
+	AAGUID          []byte    `gorm:"type:varbinary(16);not null"`
+	SignCount       uint32    `gorm:"type:int unsigned;not null;default:0"`
+	CreatedAt       time.Time `gorm:"precision:6"`
+	UpdatedAt       time.Time `gorm:"precision:6"`
+}
+
+func (*v34WebAuthnCredential) TableName() string {
+	return "user_webauthn_credentials"
+}
+
+type v34TwoFactorRequiredRole struct {
+	Role      string    `gorm:"type:varchar(30);not null;primaryKey"`
+	CreatedAt time.Time `gorm:"precision:6"`
+}
+
+func (*v34TwoFactorRequiredRole) TableName() string {
+	return "two_factor_required_roles"
+}
+
+type v34RolePermission struct {
+	Role       string `gorm:"type:varchar(30);not null;primaryKey"`
+	Permission string `gorm:"type:varchar(30);not null;primaryKey"`
+}
+
+func (*v34RolePermission) TableName() string {
+	return "user_role_permissions"
+}
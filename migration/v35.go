@@ -0,0 +1,96 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// v35 OAuth2のきめ細かいスコープの追加
+func v35() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "35",
+		Migrate: func(db *gorm.DB) error {
+			addedScopes := map[string][]string{
+				"channels:read": {
+					"get_channel",
+					"get_channel_subscription",
+					"get_channel_star",
+				},
+				"channels:write": {
+					"create_channel",
+					"edit_channel_topic",
+					"edit_channel_subscription",
+					"edit_channel_star",
+				},
+				"messages:read": {
+					"get_message",
+					"get_unread",
+				},
+				"messages:write": {
+					"post_message",
+					"edit_message",
+					"delete_message",
+					"report_message",
+					"create_message_pin",
+					"delete_message_pin",
+					"delete_unread",
+				},
+				"stamps:read": {
+					"get_stamp",
+					"get_my_stamp_history",
+					"get_stamp_palette",
+				},
+				"stamps:write": {
+					"add_message_stamp",
+					"remove_message_stamp",
+					"create_stamp_palette",
+					"edit_stamp_palette",
+					"delete_stamp_palette",
+				},
+				"files:read": {
+					"download_file",
+				},
+				"files:write": {
+					"upload_file",
+					"delete_file",
+				},
+				"users:read": {
+					"get_user",
+					"get_me",
+					"get_user_tag",
+					"get_user_group",
+				},
+			}
+			for role, perms := range addedScopes {
+				if err := db.Create(&v35UserRole{Name: role, Oauth2Scope: true, System: true}).Error; err != nil {
+					return err
+				}
+				for _, perm := range perms {
+					if err := db.Create(&v35RolePermission{Role: role, Permission: perm}).Error; err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+	}
+}
+
+type v35UserRole struct {
+	Name        string `gorm:"type:varchar(30);not null;primaryKey"`
+	Oauth2Scope bool   `gorm:"type:boolean;not null;default:false"`
+	System      bool   `gorm:"type:boolean;not null;default:false"`
+}
+
+func (*v35UserRole) TableName() string {
+	return "user_roles"
+}
+
+type v35RolePermission struct {
+	Role       string `gorm:"type:varchar(30);not null;primaryKey"`
+	Permission string `gorm:"type:varchar(30);not null;primaryKey"`
+}
+
+func (*v35RolePermission) TableName() string {
+	return "user_role_permissions"
+}
@@ -0,0 +1,73 @@
+package migration
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+
+	"github.com/traPtitech/traQ/utils/optional"
+)
+
+// v36 パーソナルアクセストークンの追加
+func v36() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "36",
+		Migrate: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&v36PersonalAccessToken{}); err != nil {
+				return err
+			}
+
+			foreignKeys := [][6]string{
+				// table name, constraint name, field name, references, on delete, on update
+				{"personal_access_tokens", "personal_access_tokens_user_id_users_id_foreign", "user_id", "users(id)", "CASCADE", "CASCADE"},
+			}
+			for _, c := range foreignKeys {
+				if err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s ON DELETE %s ON UPDATE %s", c[0], c[1], c[2], c[3], c[4], c[5])).Error; err != nil {
+					return err
+				}
+			}
+
+			addedRolePermissions := map[string][]string{
+				"user": {
+					"create_my_token",
+				},
+			}
+			for role, perms := range addedRolePermissions {
+				for _, perm := range perms {
+					if err := db.Create(&v36RolePermission{Role: role, Permission: perm}).Error; err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+	}
+}
+
+type v36PersonalAccessToken struct {
+	ID         uuid.UUID     `gorm:"type:char(36);not null;primaryKey"`
+	UserID     uuid.UUID     `gorm:"type:char(36);not null;index"`
+	Name       string        `gorm:"type:varchar(32);not null"`
+	TokenHash  string        `gorm:"type:char(64);not null;unique"`
+	Scopes     string        `gorm:"type:text;not null"`
+	ExpiresAt  optional.Time `gorm:"precision:6"`
+	LastUsedAt optional.Time `gorm:"precision:6"`
+	LastUsedIP string        `gorm:"type:varchar(45);not null;default:''"`
+	CreatedAt  time.Time     `gorm:"precision:6"`
+}
+
+func (*v36PersonalAccessToken) TableName() string {
+	return "personal_access_tokens"
+}
+
+type v36RolePermission struct {
+	Role       string `gorm:"type:varchar(30);not null;primaryKey"`
+	Permission string `gorm:"type:varchar(30);not null;primaryKey"`
+}
+
+func (*v36RolePermission) TableName() string {
+	return "user_role_permissions"
+}
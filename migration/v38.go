@@ -0,0 +1,36 @@
+package migration
+
+import (
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// v38 監査ログの追加
+func v38() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "38",
+		Migrate: func(db *gorm.DB) error {
+			return db.AutoMigrate(&v38AuditLog{})
+		},
+	}
+}
+
+type v38AuditLog struct {
+	ID         uuid.UUID `gorm:"type:char(36);not null;primaryKey"`
+	ActorID    uuid.UUID `gorm:"type:char(36);not null;index"`
+	Action     string    `gorm:"type:varchar(50);not null;index"`
+	TargetType string    `gorm:"type:varchar(30);not null;index:idx_audit_logs_target,priority:1"`
+	TargetID   string    `gorm:"type:varchar(100);not null;index:idx_audit_logs_target,priority:2"`
+	Before     string    `gorm:"type:TEXT COLLATE utf8mb4_bin NOT NULL"`
+	After      string    `gorm:"type:TEXT COLLATE utf8mb4_bin NOT NULL"`
+	IP         string    `gorm:"type:varchar(45);not null;default:''"`
+	UserAgent  string    `gorm:"type:varchar(255);not null;default:''"`
+	CreatedAt  time.Time `gorm:"precision:6;index"`
+}
+
+func (*v38AuditLog) TableName() string {
+	return "audit_logs"
+}
@@ -0,0 +1,29 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// v39 グループDMの追加
+func v39() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "39",
+		Migrate: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&v39GroupDMChannelMapping{}); err != nil {
+				return err
+			}
+			return db.Exec("ALTER TABLE group_dm_channel_mappings ADD CONSTRAINT group_dm_channel_mappings_channel_id_channels_id_foreign FOREIGN KEY (channel_id) REFERENCES channels(id) ON DELETE CASCADE ON UPDATE CASCADE").Error
+		},
+	}
+}
+
+type v39GroupDMChannelMapping struct {
+	ChannelID  uuid.UUID `gorm:"type:char(36);not null;primaryKey"`
+	MembersKey string    `gorm:"type:char(64);not null;uniqueIndex"`
+}
+
+func (*v39GroupDMChannelMapping) TableName() string {
+	return "group_dm_channel_mappings"
+}
@@ -0,0 +1,33 @@
+package migration
+
+import (
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// v40 チャンネルテンプレートの追加
+func v40() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "40",
+		Migrate: func(db *gorm.DB) error {
+			return db.AutoMigrate(&v40ChannelTemplate{})
+		},
+	}
+}
+
+type v40ChannelTemplate struct {
+	ID          uuid.UUID `gorm:"type:char(36);not null;primaryKey"`
+	Name        string    `gorm:"type:varchar(30);not null;unique"`
+	Description string    `gorm:"type:text;not null"`
+	Channels    string    `gorm:"type:TEXT COLLATE utf8mb4_bin NOT NULL"`
+	CreatorID   uuid.UUID `gorm:"type:char(36);not null"`
+	CreatedAt   time.Time `gorm:"precision:6"`
+	UpdatedAt   time.Time `gorm:"precision:6"`
+}
+
+func (*v40ChannelTemplate) TableName() string {
+	return "channel_templates"
+}
@@ -0,0 +1,34 @@
+package migration
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// v41 非アクティブチャンネルの自動アーカイブ状態の追加
+func v41() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "41",
+		Migrate: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&v41ChannelArchivalState{}); err != nil {
+				return err
+			}
+			return db.Exec("ALTER TABLE channel_archival_states ADD CONSTRAINT channel_archival_states_channel_id_channels_id_foreign FOREIGN KEY (channel_id) REFERENCES channels(id) ON DELETE CASCADE ON UPDATE CASCADE").Error
+		},
+	}
+}
+
+type v41ChannelArchivalState struct {
+	ChannelID uuid.UUID    `gorm:"type:char(36);not null;primaryKey"`
+	Exempt    bool         `gorm:"type:boolean;not null;default:false"`
+	WarnedAt  sql.NullTime `gorm:"precision:6"`
+	UpdatedAt time.Time    `gorm:"precision:6"`
+}
+
+func (*v41ChannelArchivalState) TableName() string {
+	return "channel_archival_states"
+}
@@ -0,0 +1,36 @@
+package migration
+
+import (
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// v42 チャンネルのメッセージ保持ポリシーの追加
+func v42() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "42",
+		Migrate: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&v42ChannelRetentionPolicy{}); err != nil {
+				return err
+			}
+			return db.Exec("ALTER TABLE channel_retention_policies ADD CONSTRAINT channel_retention_policies_channel_id_channels_id_foreign FOREIGN KEY (channel_id) REFERENCES channels(id) ON DELETE CASCADE ON UPDATE CASCADE").Error
+		},
+	}
+}
+
+type v42ChannelRetentionPolicy struct {
+	ChannelID     uuid.UUID `gorm:"type:char(36);not null;primaryKey"`
+	RetentionDays int       `gorm:"type:int;not null;default:0"`
+	Purge         bool      `gorm:"type:boolean;not null;default:false"`
+	KeepPinned    bool      `gorm:"type:boolean;not null;default:false"`
+	UpdaterID     uuid.UUID `gorm:"type:char(36);not null"`
+	CreatedAt     time.Time `gorm:"precision:6"`
+	UpdatedAt     time.Time `gorm:"precision:6"`
+}
+
+func (*v42ChannelRetentionPolicy) TableName() string {
+	return "channel_retention_policies"
+}
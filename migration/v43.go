@@ -0,0 +1,67 @@
+package migration
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+
+	"github.com/traPtitech/traQ/utils/optional"
+)
+
+// v43 ユーザー定義のサイドバーセクションの追加
+func v43() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "43",
+		Migrate: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&v43SidebarSection{}, &v43SidebarItem{}); err != nil {
+				return err
+			}
+
+			foreignKeys := [][6]string{
+				// table name, constraint name, field name, references, on delete, on update
+				{"sidebar_sections", "sidebar_sections_user_id_users_id_foreign", "user_id", "users(id)", "CASCADE", "CASCADE"},
+				{"sidebar_items", "sidebar_items_section_id_sidebar_sections_id_foreign", "section_id", "sidebar_sections(id)", "CASCADE", "CASCADE"},
+				{"sidebar_items", "sidebar_items_channel_id_channels_id_foreign", "channel_id", "channels(id)", "CASCADE", "CASCADE"},
+			}
+
+			for _, c := range foreignKeys {
+				if err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s ON DELETE %s ON UPDATE %s", c[0], c[1], c[2], c[3], c[4], c[5])).Error; err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+type v43SidebarSection struct {
+	ID        uuid.UUID `gorm:"type:char(36);not null;primaryKey"`
+	UserID    uuid.UUID `gorm:"type:char(36);not null;index"`
+	Name      string    `gorm:"type:varchar(32);not null"`
+	Position  int       `gorm:"type:int;not null;default:0"`
+	Collapsed bool      `gorm:"type:boolean;not null;default:false"`
+	CreatedAt time.Time `gorm:"precision:6"`
+	UpdatedAt time.Time `gorm:"precision:6"`
+}
+
+func (*v43SidebarSection) TableName() string {
+	return "sidebar_sections"
+}
+
+type v43SidebarItem struct {
+	ID        uuid.UUID     `gorm:"type:char(36);not null;primaryKey"`
+	SectionID uuid.UUID     `gorm:"type:char(36);not null;index"`
+	Position  int           `gorm:"type:int;not null;default:0"`
+	Type      string        `gorm:"type:varchar(10);not null"`
+	ChannelID optional.UUID `gorm:"type:char(36);index"`
+	URL       string        `gorm:"type:varchar(512);not null;default:''"`
+	Title     string        `gorm:"type:varchar(64);not null;default:''"`
+}
+
+func (*v43SidebarItem) TableName() string {
+	return "sidebar_items"
+}
@@ -0,0 +1,72 @@
+package migration
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// v44 スタンプのカテゴリー・エイリアス・タグの追加
+func v44() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "44",
+		Migrate: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&v44Stamp{}, &v44StampAlias{}, &v44StampTag{}); err != nil {
+				return err
+			}
+
+			foreignKeys := [][6]string{
+				// table name, constraint name, field name, references, on delete, on update
+				{"stamp_aliases", "stamp_aliases_stamp_id_stamps_id_foreign", "stamp_id", "stamps(id)", "CASCADE", "CASCADE"},
+				{"stamp_tags", "stamp_tags_stamp_id_stamps_id_foreign", "stamp_id", "stamps(id)", "CASCADE", "CASCADE"},
+			}
+
+			for _, c := range foreignKeys {
+				if err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s ON DELETE %s ON UPDATE %s", c[0], c[1], c[2], c[3], c[4], c[5])).Error; err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+type v44Stamp struct {
+	ID        uuid.UUID      `gorm:"type:char(36);not null;primaryKey"`
+	Name      string         `gorm:"type:varchar(32);not null;unique"`
+	CreatorID uuid.UUID      `gorm:"type:char(36);not null"`
+	FileID    uuid.UUID      `gorm:"type:char(36);not null"`
+	IsUnicode bool           `gorm:"type:boolean;not null;default:false;index"`
+	Category  string         `gorm:"type:varchar(32);not null;default:'';index"` // 追加
+	CreatedAt time.Time      `gorm:"precision:6"`
+	UpdatedAt time.Time      `gorm:"precision:6"`
+	DeletedAt gorm.DeletedAt `gorm:"precision:6"`
+}
+
+func (*v44Stamp) TableName() string {
+	return "stamps"
+}
+
+type v44StampAlias struct {
+	Alias     string    `gorm:"type:varchar(32);not null;primaryKey"`
+	StampID   uuid.UUID `gorm:"type:char(36);not null;index"`
+	CreatedAt time.Time `gorm:"precision:6"`
+}
+
+func (*v44StampAlias) TableName() string {
+	return "stamp_aliases"
+}
+
+type v44StampTag struct {
+	StampID   uuid.UUID `gorm:"type:char(36);not null;primaryKey"`
+	Tag       string    `gorm:"type:varchar(30);not null;primaryKey;index"`
+	CreatedAt time.Time `gorm:"precision:6"`
+}
+
+func (*v44StampTag) TableName() string {
+	return "stamp_tags"
+}
@@ -0,0 +1,61 @@
+package migration
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+// v45 スタンプパレットの公開範囲・購読の追加
+func v45() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "45",
+		Migrate: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&v45StampPalette{}, &v45StampPaletteSubscription{}); err != nil {
+				return err
+			}
+
+			foreignKeys := [][6]string{
+				// table name, constraint name, field name, references, on delete, on update
+				{"stamp_palette_subscriptions", "stamp_palette_subscriptions_user_id_users_id_foreign", "user_id", "users(id)", "CASCADE", "CASCADE"},
+				{"stamp_palette_subscriptions", "stamp_palette_subscriptions_palette_id_stamp_palettes_id_foreign", "palette_id", "stamp_palettes(id)", "CASCADE", "CASCADE"},
+			}
+
+			for _, c := range foreignKeys {
+				if err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s ON DELETE %s ON UPDATE %s", c[0], c[1], c[2], c[3], c[4], c[5])).Error; err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+type v45StampPalette struct {
+	ID          uuid.UUID `gorm:"type:char(36);not null;primaryKey"`
+	Name        string    `gorm:"type:varchar(30);not null"`
+	Description string    `gorm:"type:text;not null"`
+	Stamps      string    `gorm:"type:text;not null"`
+	CreatorID   uuid.UUID `gorm:"type:char(36);not null;index"`
+	Visibility  string    `gorm:"type:varchar(10);not null;default:'private';index"` // 追加
+	CreatedAt   time.Time `gorm:"precision:6"`
+	UpdatedAt   time.Time `gorm:"precision:6"`
+}
+
+func (*v45StampPalette) TableName() string {
+	return "stamp_palettes"
+}
+
+type v45StampPaletteSubscription struct {
+	UserID    uuid.UUID `gorm:"type:char(36);not null;primaryKey"`
+	PaletteID uuid.UUID `gorm:"type:char(36);not null;primaryKey;index"`
+	CreatedAt time.Time `gorm:"precision:6"`
+}
+
+func (*v45StampPaletteSubscription) TableName() string {
+	return "stamp_palette_subscriptions"
+}
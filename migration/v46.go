@@ -0,0 +1,61 @@
+package migration
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+
+	"github.com/traPtitech/traQ/utils/optional"
+)
+
+// v46 TOTPの使用済みタイムステップ・二要素認証のロック状態の追加
+func v46() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "46",
+		Migrate: func(db *gorm.DB) error {
+			if err := db.AutoMigrate(&v46UserTOTP{}, &v46UserTwoFactorLockout{}); err != nil {
+				return err
+			}
+
+			foreignKeys := [][6]string{
+				// table name, constraint name, field name, references, on delete, on update
+				{"user_two_factor_lockouts", "user_two_factor_lockouts_user_id_users_id_foreign", "user_id", "users(id)", "CASCADE", "CASCADE"},
+			}
+
+			for _, c := range foreignKeys {
+				if err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s ON DELETE %s ON UPDATE %s", c[0], c[1], c[2], c[3], c[4], c[5])).Error; err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+type v46UserTOTP struct {
+	UserID       uuid.UUID `gorm:"type:char(36);not null;primaryKey"`
+	Secret       string    `gorm:"type:varchar(64);not null"`
+	Enabled      bool      `gorm:"type:boolean;not null;default:false"`
+	LastUsedStep int64     `gorm:"type:bigint;not null;default:0"` // 追加
+	CreatedAt    time.Time `gorm:"precision:6"`
+	UpdatedAt    time.Time `gorm:"precision:6"`
+}
+
+func (*v46UserTOTP) TableName() string {
+	return "user_totps"
+}
+
+type v46UserTwoFactorLockout struct {
+	UserID      uuid.UUID     `gorm:"type:char(36);not null;primaryKey"`
+	Failures    int           `gorm:"type:int;not null;default:0"`
+	LockedUntil optional.Time `gorm:"precision:6"`
+	UpdatedAt   time.Time     `gorm:"precision:6"`
+}
+
+func (*v46UserTwoFactorLockout) TableName() string {
+	return "user_two_factor_lockouts"
+}
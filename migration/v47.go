@@ -0,0 +1,48 @@
+package migration
+
+import (
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+
+	"github.com/traPtitech/traQ/model"
+)
+
+// v47 プライベートチャンネルをチャンネル名の一意制約から除外
+func v47() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "47",
+		Migrate: func(db *gorm.DB) error {
+			// name_parentをname_scopeを含めて作り直す
+			if err := db.Migrator().DropIndex("channels", "name_parent"); err != nil {
+				return err
+			}
+			if err := db.AutoMigrate(&v47Channel{}); err != nil {
+				return err
+			}
+			return db.Exec("UPDATE channels SET name_scope = id WHERE parent_id = ?", model.PrivateChannelRootID).Error
+		},
+	}
+}
+
+type v47Channel struct {
+	ID        uuid.UUID      `gorm:"type:char(36);not null;primaryKey;index:idx_channel_channels_id_is_public_is_forced,priority:1"`
+	Name      string         `gorm:"type:varchar(20);not null;uniqueIndex:name_parent"`
+	ParentID  uuid.UUID      `gorm:"type:char(36);not null;uniqueIndex:name_parent"`
+	NameScope string         `gorm:"type:char(36);not null;default:'';uniqueIndex:name_parent"` // 追加
+	Topic     string         `gorm:"type:TEXT COLLATE utf8mb4_bin NOT NULL"`
+	IsForced  bool           `gorm:"type:boolean;not null;default:false;index:idx_channel_channels_id_is_public_is_forced,priority:3"`
+	IsPublic  bool           `gorm:"type:boolean;not null;default:false;index:idx_channel_channels_id_is_public_is_forced,priority:2"`
+	IsVisible bool           `gorm:"type:boolean;not null;default:false"`
+	CreatorID uuid.UUID      `gorm:"type:char(36);not null"`
+	UpdaterID uuid.UUID      `gorm:"type:char(36);not null"`
+	CreatedAt time.Time      `gorm:"precision:6"`
+	UpdatedAt time.Time      `gorm:"precision:6"`
+	DeletedAt gorm.DeletedAt `gorm:"precision:6"`
+}
+
+func (*v47Channel) TableName() string {
+	return "channels"
+}
@@ -0,0 +1,130 @@
+package model
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// AuditAction 監査ログの操作の種類
+type AuditAction string
+
+const (
+	// AuditActionUserCreate ユーザーの作成
+	AuditActionUserCreate AuditAction = "user.create"
+	// AuditActionUserEdit ユーザー情報の変更
+	AuditActionUserEdit AuditAction = "user.edit"
+	// AuditActionUserIconChange ユーザーアイコンの変更
+	AuditActionUserIconChange AuditAction = "user.icon.change"
+	// AuditActionUserPasswordChange ユーザーパスワードの変更
+	AuditActionUserPasswordChange AuditAction = "user.password.change"
+	// AuditActionUserTwoFactorReset ユーザーの二要素認証のリセット
+	AuditActionUserTwoFactorReset AuditAction = "user.two_factor.reset"
+	// AuditActionTwoFactorPolicyEdit 二要素認証ポリシーの変更
+	AuditActionTwoFactorPolicyEdit AuditAction = "two_factor_policy.edit"
+	// AuditActionStampDelete スタンプの削除
+	AuditActionStampDelete AuditAction = "stamp.delete"
+	// AuditActionStampImport スタンプパックからのスタンプのインポート
+	AuditActionStampImport AuditAction = "stamp.import"
+	// AuditActionChannelEdit チャンネル情報の変更
+	AuditActionChannelEdit AuditAction = "channel.edit"
+	// AuditActionChannelMerge チャンネルの統合
+	AuditActionChannelMerge AuditAction = "channel.merge"
+	// AuditActionChannelDelete チャンネルの削除
+	AuditActionChannelDelete AuditAction = "channel.delete"
+	// AuditActionChannelArchivalEdit チャンネル自動アーカイブ設定の変更
+	AuditActionChannelArchivalEdit AuditAction = "channel.archival.edit"
+	// AuditActionChannelRetentionEdit チャンネルのメッセージ保持ポリシーの設定
+	AuditActionChannelRetentionEdit AuditAction = "channel.retention.edit"
+	// AuditActionChannelRetentionDelete チャンネルのメッセージ保持ポリシーの削除
+	AuditActionChannelRetentionDelete AuditAction = "channel.retention.delete"
+	// AuditActionChannelTemplateApply チャンネルテンプレートの適用
+	AuditActionChannelTemplateApply AuditAction = "channel_template.apply"
+	// AuditActionBotReissue BOTのトークンの再発行
+	AuditActionBotReissue AuditAction = "bot.reissue"
+	// AuditActionUserRoleCreate ユーザーロールの作成
+	AuditActionUserRoleCreate AuditAction = "user_role.create"
+	// AuditActionUserRoleEdit ユーザーロールの変更
+	AuditActionUserRoleEdit AuditAction = "user_role.edit"
+	// AuditActionUserRoleDelete ユーザーロールの削除
+	AuditActionUserRoleDelete AuditAction = "user_role.delete"
+	// AuditActionChannelRoleSet チャンネルロールの割り当て
+	AuditActionChannelRoleSet AuditAction = "channel_role.set"
+	// AuditActionChannelRoleDelete チャンネルロールの削除
+	AuditActionChannelRoleDelete AuditAction = "channel_role.delete"
+	// AuditActionOAuth2ClientEdit OAuth2クライアント情報の変更
+	AuditActionOAuth2ClientEdit AuditAction = "oauth2_client.edit"
+	// AuditActionOAuth2ClientDelete OAuth2クライアントの削除
+	AuditActionOAuth2ClientDelete AuditAction = "oauth2_client.delete"
+)
+
+// AuditTargetType 監査ログの操作対象の種類
+type AuditTargetType string
+
+const (
+	// AuditTargetUser ユーザー
+	AuditTargetUser AuditTargetType = "user"
+	// AuditTargetStamp スタンプ
+	AuditTargetStamp AuditTargetType = "stamp"
+	// AuditTargetChannel チャンネル
+	AuditTargetChannel AuditTargetType = "channel"
+	// AuditTargetChannelTemplate チャンネルテンプレート
+	AuditTargetChannelTemplate AuditTargetType = "channel_template"
+	// AuditTargetBot BOT
+	AuditTargetBot AuditTargetType = "bot"
+	// AuditTargetUserRole ユーザーロール
+	AuditTargetUserRole AuditTargetType = "user_role"
+	// AuditTargetChannelRole チャンネルロール (対象IDは"チャンネルID/ユーザーID")
+	AuditTargetChannelRole AuditTargetType = "channel_role"
+	// AuditTargetOAuth2Client OAuth2クライアント
+	AuditTargetOAuth2Client AuditTargetType = "oauth2_client"
+	// AuditTargetTwoFactorPolicy 二要素認証ポリシー
+	AuditTargetTwoFactorPolicy AuditTargetType = "two_factor_policy"
+)
+
+// AuditActorSCIM SCIMプロビジョニングAPIによる操作の監査ログの操作者ID
+//
+// SCIMによる操作はユーザーに紐付かないため、ユーザーと衝突しない固定のIDを用います。
+var AuditActorSCIM = uuid.NewV5(uuid.Nil, "traq.scim")
+
+// AuditLog 監査ログ構造体
+//
+// 特権操作の記録です。追記のみ可能で、変更・削除はできません。
+type AuditLog struct {
+	ID         uuid.UUID       `gorm:"type:char(36);not null;primaryKey" json:"id"`
+	ActorID    uuid.UUID       `gorm:"type:char(36);not null;index" json:"actorId"`
+	Action     AuditAction     `gorm:"type:varchar(50);not null;index" json:"action"`
+	TargetType AuditTargetType `gorm:"type:varchar(30);not null;index:idx_audit_logs_target,priority:1" json:"targetType"`
+	TargetID   string          `gorm:"type:varchar(100);not null;index:idx_audit_logs_target,priority:2" json:"targetId"`
+	Before     JSON            `gorm:"type:TEXT COLLATE utf8mb4_bin NOT NULL" json:"before"`
+	After      JSON            `gorm:"type:TEXT COLLATE utf8mb4_bin NOT NULL" json:"after"`
+	IP         string          `gorm:"type:varchar(45);not null;default:''" json:"ip"`
+	UserAgent  string          `gorm:"type:varchar(255);not null;default:''" json:"userAgent"`
+	CreatedAt  time.Time       `gorm:"precision:6;index" json:"createdAt"`
+}
+
+// TableName AuditLog構造体のテーブル名
+func (*AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// AuditDiff 変更前後の値から変更されたフィールドのみを取り出します
+//
+// before, afterはそれぞれ変更前後のフィールドの値で、値が等しいフィールドは取り除かれます。
+func AuditDiff(before, after JSON) (JSON, JSON) {
+	b, a := JSON{}, JSON{}
+	for k, v := range before {
+		if av, ok := after[k]; ok && reflect.DeepEqual(v, av) {
+			continue
+		}
+		b[k] = v
+	}
+	for k, v := range after {
+		if bv, ok := before[k]; ok && reflect.DeepEqual(v, bv) {
+			continue
+		}
+		a[k] = v
+	}
+	return b, a
+}
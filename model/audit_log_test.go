@@ -0,0 +1,27 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditLog_TableName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "audit_logs", (&AuditLog{}).TableName())
+}
+
+func TestAuditDiff(t *testing.T) {
+	t.Parallel()
+
+	before, after := AuditDiff(
+		JSON{"name": "a", "state": 1, "role": "user"},
+		JSON{"name": "a", "state": 0, "role": "admin", "bio": "x"},
+	)
+	assert.Equal(t, JSON{"state": 1, "role": "user"}, before)
+	assert.Equal(t, JSON{"state": 0, "role": "admin", "bio": "x"}, after)
+
+	before, after = AuditDiff(nil, nil)
+	assert.Empty(t, before)
+	assert.Empty(t, after)
+}
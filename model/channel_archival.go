@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/traPtitech/traQ/utils/optional"
+)
+
+// ChannelArchivalState 非アクティブチャンネルの自動アーカイブ状態構造体
+type ChannelArchivalState struct {
+	ChannelID uuid.UUID `gorm:"type:char(36);not null;primaryKey"`
+	// Exempt 自動アーカイブの対象外かどうか
+	Exempt bool `gorm:"type:boolean;not null;default:false"`
+	// WarnedAt アーカイブ予告メッセージを投稿した日時
+	WarnedAt  optional.Time `gorm:"precision:6"`
+	UpdatedAt time.Time     `gorm:"precision:6"`
+
+	Channel *Channel `gorm:"constraint:channel_archival_states_channel_id_channels_id_foreign,OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// TableName ChannelArchivalState構造体のテーブル名
+func (*ChannelArchivalState) TableName() string {
+	return "channel_archival_states"
+}
+
+// IsWarned アーカイブ予告済みかどうか
+func (s *ChannelArchivalState) IsWarned() bool {
+	return s != nil && s.WarnedAt.Valid
+}
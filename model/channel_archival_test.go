@@ -0,0 +1,22 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/traPtitech/traQ/utils/optional"
+)
+
+func TestChannelArchivalState_TableName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "channel_archival_states", (&ChannelArchivalState{}).TableName())
+}
+
+func TestChannelArchivalState_IsWarned(t *testing.T) {
+	t.Parallel()
+	assert.False(t, (*ChannelArchivalState)(nil).IsWarned())
+	assert.False(t, (&ChannelArchivalState{}).IsWarned())
+	assert.True(t, (&ChannelArchivalState{WarnedAt: optional.TimeFrom(time.Now())}).IsWarned())
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// MaxRetentionDays メッセージ保持ポリシーで指定できる最大の保持日数
+const MaxRetentionDays = 36500
+
+// ChannelRetentionPolicy チャンネルのメッセージ保持ポリシー構造体
+//
+// 指定したチャンネルとその子孫チャンネルに適用されます。
+// 子孫チャンネルに別のポリシーが設定されている場合は、そちらが優先されます。
+type ChannelRetentionPolicy struct {
+	ChannelID uuid.UUID `gorm:"type:char(36);not null;primaryKey"`
+	// RetentionDays メッセージの保持日数 0の場合は無期限に保持します
+	RetentionDays int `gorm:"type:int;not null;default:0"`
+	// Purge 保持期間を過ぎたメッセージを物理削除するかどうか
+	Purge bool `gorm:"type:boolean;not null;default:false"`
+	// KeepPinned ピン留めされたメッセージを保持するかどうか
+	KeepPinned bool      `gorm:"type:boolean;not null;default:false"`
+	UpdaterID  uuid.UUID `gorm:"type:char(36);not null"`
+	CreatedAt  time.Time `gorm:"precision:6"`
+	UpdatedAt  time.Time `gorm:"precision:6"`
+
+	Channel *Channel `gorm:"constraint:channel_retention_policies_channel_id_channels_id_foreign,OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// TableName ChannelRetentionPolicy構造体のテーブル名
+func (*ChannelRetentionPolicy) TableName() string {
+	return "channel_retention_policies"
+}
+
+// IsPreserved メッセージを無期限に保持するポリシーかどうか
+func (p *ChannelRetentionPolicy) IsPreserved() bool {
+	return p.RetentionDays <= 0
+}
+
+// RetentionPeriod メッセージの保持期間
+func (p *ChannelRetentionPolicy) RetentionPeriod() time.Duration {
+	return time.Duration(p.RetentionDays) * time.Hour * 24
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChannelRetentionPolicy_TableName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "channel_retention_policies", (&ChannelRetentionPolicy{}).TableName())
+}
+
+func TestChannelRetentionPolicy_IsPreserved(t *testing.T) {
+	t.Parallel()
+	assert.True(t, (&ChannelRetentionPolicy{}).IsPreserved())
+	assert.False(t, (&ChannelRetentionPolicy{RetentionDays: 30}).IsPreserved())
+}
+
+func TestChannelRetentionPolicy_RetentionPeriod(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, 30*24*time.Hour, (&ChannelRetentionPolicy{RetentionDays: 30}).RetentionPeriod())
+}
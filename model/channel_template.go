@@ -0,0 +1,136 @@
+package model
+
+import (
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"time"
+
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gofrs/uuid"
+
+	"github.com/traPtitech/traQ/utils/validator"
+)
+
+// MaxChannelTemplateNodes 1つのチャンネルテンプレートに含められるチャンネルの最大数
+const MaxChannelTemplateNodes = 100
+
+// ChannelTemplate チャンネルテンプレート構造体
+//
+// 公開チャンネルのサブツリーの構成を表します。
+type ChannelTemplate struct {
+	ID          uuid.UUID            `gorm:"type:char(36);not null;primaryKey"`
+	Name        string               `gorm:"type:varchar(30);not null;unique"`
+	Description string               `gorm:"type:text;not null"`
+	Channels    ChannelTemplateNodes `gorm:"type:TEXT COLLATE utf8mb4_bin NOT NULL"`
+	CreatorID   uuid.UUID            `gorm:"type:char(36);not null"`
+	CreatedAt   time.Time            `gorm:"precision:6"`
+	UpdatedAt   time.Time            `gorm:"precision:6"`
+}
+
+// TableName ChannelTemplate構造体のテーブル名
+func (*ChannelTemplate) TableName() string {
+	return "channel_templates"
+}
+
+// ChannelTemplateNode チャンネルテンプレートの各チャンネル
+type ChannelTemplateNode struct {
+	// Name チャンネル名
+	Name string `json:"name"`
+	// Topic チャンネルトピック
+	Topic string `json:"topic"`
+	// Force 強制通知チャンネルかどうか
+	Force bool `json:"force"`
+	// Subscribers 通知購読させるユーザーのUUID
+	Subscribers []uuid.UUID `json:"subscribers"`
+	// Bots 参加させるBOTのUUID
+	Bots []uuid.UUID `json:"bots"`
+	// Children 子チャンネル
+	Children ChannelTemplateNodes `json:"children"`
+}
+
+// Validate 構造体を検証します
+func (n ChannelTemplateNode) Validate() error {
+	return vd.ValidateStruct(&n,
+		vd.Field(&n.Name, validator.ChannelNameRuleRequired...),
+		vd.Field(&n.Topic, vd.RuneLength(0, 200)),
+		vd.Field(&n.Subscribers, vd.Each(validator.NotNilUUID)),
+		vd.Field(&n.Bots, vd.Each(validator.NotNilUUID)),
+		vd.Field(&n.Children),
+	)
+}
+
+// ChannelTemplateNodes チャンネルテンプレートの兄弟チャンネルの配列
+type ChannelTemplateNodes []*ChannelTemplateNode
+
+// Validate 構造体を検証します
+//
+// 兄弟チャンネル間でのチャンネル名の重複(大文字小文字を区別しない)も検証します。
+func (nodes ChannelTemplateNodes) Validate() error {
+	if nodes.Count() > MaxChannelTemplateNodes {
+		return errors.New("too many channels")
+	}
+	names := make(map[string]struct{}, len(nodes))
+	for _, n := range nodes {
+		if n == nil {
+			return errors.New("must not be null")
+		}
+		if err := n.Validate(); err != nil {
+			return err
+		}
+		name := strings.ToLower(n.Name)
+		if _, ok := names[name]; ok {
+			return errors.New("duplicated channel name: " + n.Name)
+		}
+		names[name] = struct{}{}
+	}
+	return nil
+}
+
+// Count サブツリーを含めたチャンネルの総数を返します
+func (nodes ChannelTemplateNodes) Count() int {
+	c := len(nodes)
+	for _, n := range nodes {
+		if n != nil {
+			c += n.Children.Count()
+		}
+	}
+	return c
+}
+
+// Depth サブツリーの深さを返します
+func (nodes ChannelTemplateNodes) Depth() int {
+	d := 0
+	for _, n := range nodes {
+		if n == nil {
+			continue
+		}
+		if cd := n.Children.Depth() + 1; cd > d {
+			d = cd
+		}
+	}
+	return d
+}
+
+// Value database/sql/driver.Valuer 実装
+func (nodes ChannelTemplateNodes) Value() (driver.Value, error) {
+	if nodes == nil {
+		return "[]", nil
+	}
+	return json.MarshalToString(nodes)
+}
+
+// Scan database/sql.Scanner 実装
+func (nodes *ChannelTemplateNodes) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case nil:
+		*nodes = ChannelTemplateNodes{}
+		return nil
+	case string:
+		return json.Unmarshal([]byte(s), nodes)
+	case []byte:
+		return json.Unmarshal(s, nodes)
+	default:
+		return errors.New("failed to scan ChannelTemplateNodes")
+	}
+}
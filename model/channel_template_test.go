@@ -0,0 +1,80 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChannelTemplate_TableName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "channel_templates", (&ChannelTemplate{}).TableName())
+}
+
+func TestChannelTemplateNodes_Validate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name  string
+		nodes ChannelTemplateNodes
+		error bool
+	}{
+		{"empty", ChannelTemplateNodes{}, false},
+		{"valid", ChannelTemplateNodes{
+			{Name: "general", Topic: "雑談", Children: ChannelTemplateNodes{{Name: "random"}}},
+			{Name: "logistics", Force: true, Subscribers: []uuid.UUID{uuid.Must(uuid.NewV4())}},
+		}, false},
+		{"invalid name", ChannelTemplateNodes{{Name: "チャンネル"}}, true},
+		{"invalid child name", ChannelTemplateNodes{{Name: "a", Children: ChannelTemplateNodes{{Name: ""}}}}, true},
+		{"duplicated name", ChannelTemplateNodes{{Name: "a"}, {Name: "A"}}, true},
+		{"nil subscriber", ChannelTemplateNodes{{Name: "a", Subscribers: []uuid.UUID{uuid.Nil}}}, true},
+		{"nil node", ChannelTemplateNodes{nil}, true},
+	}
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.error {
+				assert.Error(t, tt.nodes.Validate())
+			} else {
+				assert.NoError(t, tt.nodes.Validate())
+			}
+		})
+	}
+}
+
+func TestChannelTemplateNodes_CountAndDepth(t *testing.T) {
+	t.Parallel()
+
+	nodes := ChannelTemplateNodes{
+		{Name: "a", Children: ChannelTemplateNodes{
+			{Name: "b", Children: ChannelTemplateNodes{{Name: "c"}}},
+			{Name: "d"},
+		}},
+		{Name: "e"},
+	}
+	assert.Equal(t, 5, nodes.Count())
+	assert.Equal(t, 3, nodes.Depth())
+	assert.Equal(t, 0, ChannelTemplateNodes{}.Depth())
+}
+
+func TestChannelTemplateNodes_Value(t *testing.T) {
+	t.Parallel()
+
+	nodes := ChannelTemplateNodes{{Name: "a", Topic: "t", Children: ChannelTemplateNodes{{Name: "b"}}}}
+	v, err := nodes.Value()
+	if assert.NoError(t, err) {
+		var scanned ChannelTemplateNodes
+		if assert.NoError(t, scanned.Scan(v)) {
+			assert.Equal(t, "a", scanned[0].Name)
+			assert.Equal(t, "t", scanned[0].Topic)
+			assert.Equal(t, "b", scanned[0].Children[0].Name)
+		}
+	}
+
+	v, err = ChannelTemplateNodes(nil).Value()
+	if assert.NoError(t, err) {
+		assert.Equal(t, "[]", v)
+	}
+}
@@ -12,15 +12,24 @@ import (
 const (
 	// DirectMessageChannelRootID ダイレクトメッセージチャンネルの親チャンネルID
 	DirectMessageChannelRootID = "aaaaaaaa-aaaa-4aaa-aaaa-aaaaaaaaaaaa"
+	// PrivateChannelRootID プライベートチャンネルの親チャンネルID
+	PrivateChannelRootID = "bbbbbbbb-bbbb-4bbb-bbbb-bbbbbbbbbbbb"
+	// GroupDirectMessageChannelRootID グループダイレクトメッセージチャンネルの親チャンネルID
+	GroupDirectMessageChannelRootID = "cccccccc-cccc-4ccc-cccc-cccccccccccc"
 )
 
-var dmChannelRootUUID = uuid.Must(uuid.FromString(DirectMessageChannelRootID))
+var (
+	dmChannelRootUUID      = uuid.Must(uuid.FromString(DirectMessageChannelRootID))
+	privateChannelRootUUID = uuid.Must(uuid.FromString(PrivateChannelRootID))
+	groupDMChannelRootUUID = uuid.Must(uuid.FromString(GroupDirectMessageChannelRootID))
+)
 
 // Channel チャンネルの構造体
 type Channel struct {
 	ID        uuid.UUID      `gorm:"type:char(36);not null;primaryKey;index:idx_channel_channels_id_is_public_is_forced,priority:1"`
 	Name      string         `gorm:"type:varchar(20);not null;uniqueIndex:name_parent"`
 	ParentID  uuid.UUID      `gorm:"type:char(36);not null;uniqueIndex:name_parent"`
+	NameScope string         `gorm:"type:char(36);not null;default:'';uniqueIndex:name_parent"` // プライベートチャンネルの場合は自身のID、それ以外は空文字
 	Topic     string         `gorm:"type:TEXT COLLATE utf8mb4_bin NOT NULL"`
 	IsForced  bool           `gorm:"type:boolean;not null;default:false;index:idx_channel_channels_id_is_public_is_forced,priority:3"`
 	IsPublic  bool           `gorm:"type:boolean;not null;default:false;index:idx_channel_channels_id_is_public_is_forced,priority:2"`
@@ -44,6 +53,16 @@ func (ch *Channel) IsDMChannel() bool {
 	return ch.ParentID == dmChannelRootUUID
 }
 
+// IsGroupDMChannel 3人以上のグループダイレクトメッセージ用チャンネルかどうかを返します
+func (ch *Channel) IsGroupDMChannel() bool {
+	return ch.ParentID == groupDMChannelRootUUID
+}
+
+// IsPrivateChannel 招待制のプライベートチャンネルかどうかを返します
+func (ch *Channel) IsPrivateChannel() bool {
+	return ch.ParentID == privateChannelRootUUID
+}
+
 // IsArchived アーカイブされているチャンネルかどうか
 func (ch *Channel) IsArchived() bool {
 	return !ch.IsVisible
@@ -107,6 +126,24 @@ func (usc *UserSubscribeChannel) GetLevel() ChannelSubscribeLevel {
 	}
 }
 
+// ChannelRole チャンネルロール構造体
+//
+// 指定したチャンネルとその子孫チャンネルで、ユーザーにロールの権限を付与します。
+type ChannelRole struct {
+	ChannelID uuid.UUID `gorm:"type:char(36);not null;primaryKey"`
+	UserID    uuid.UUID `gorm:"type:char(36);not null;primaryKey;index"`
+	Role      string    `gorm:"type:varchar(30);not null"`
+	CreatedAt time.Time `gorm:"precision:6"`
+
+	User    *User    `gorm:"constraint:channel_roles_user_id_users_id_foreign,OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Channel *Channel `gorm:"constraint:channel_roles_channel_id_channels_id_foreign,OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// TableName ChannelRole構造体のテーブル名
+func (*ChannelRole) TableName() string {
+	return "channel_roles"
+}
+
 // DMChannelMapping ダイレクトメッセージチャンネルとユーザーのマッピング
 type DMChannelMapping struct {
 	ChannelID uuid.UUID `gorm:"type:char(36);not null;primaryKey"`
@@ -123,6 +160,21 @@ func (*DMChannelMapping) TableName() string {
 	return "dm_channel_mappings"
 }
 
+// GroupDMChannelMapping グループダイレクトメッセージチャンネルとメンバー集合のマッピング
+//
+// MembersKeyはメンバーのUUIDをソートして連結したもののSHA-256ハッシュ(16進数表記)です。
+type GroupDMChannelMapping struct {
+	ChannelID  uuid.UUID `gorm:"type:char(36);not null;primaryKey"`
+	MembersKey string    `gorm:"type:char(64);not null;uniqueIndex"`
+
+	Channel *Channel `gorm:"constraint:group_dm_channel_mappings_channel_id_channels_id_foreign,OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// TableName GroupDMChannelMapping構造体のテーブル名
+func (*GroupDMChannelMapping) TableName() string {
+	return "group_dm_channel_mappings"
+}
+
 // ChannelEventType チャンネルイベントタイプ
 type ChannelEventType string
 
@@ -181,6 +233,25 @@ const (
 	// 	userId    作成者UUID
 	// 	channelId チャンネルUUID
 	ChannelEventChildCreated = ChannelEventType("ChildCreated")
+	// ChannelEventMembersChanged チャンネルイベント プライベートチャンネルメンバー変更
+	//
+	// 	userId  変更者UUID
+	// 	added   追加されたユーザーのUUIDの配列
+	// 	removed 削除されたユーザーのUUIDの配列
+	ChannelEventMembersChanged = ChannelEventType("MembersChanged")
+	// ChannelEventMerged チャンネルイベント チャンネル統合
+	//
+	// 	userId   変更者UUID
+	// 	from     統合元チャンネルUUID
+	// 	to       統合先チャンネルUUID
+	// 	messages 移動したメッセージ数
+	ChannelEventMerged = ChannelEventType("Merged")
+	// ChannelEventChildDeleted チャンネルイベント 子チャンネル削除
+	//
+	// 	userId    削除者UUID
+	// 	channelId チャンネルUUID
+	// 	name      チャンネル名
+	ChannelEventChildDeleted = ChannelEventType("ChildDeleted")
 )
 
 // ChannelEventDetail チャンネルイベント詳細
@@ -18,6 +18,20 @@ func TestChannel_IsDMChannel(t *testing.T) {
 	assert.True(t, (&Channel{ParentID: dmChannelRootUUID}).IsDMChannel())
 }
 
+func TestChannel_IsPrivateChannel(t *testing.T) {
+	t.Parallel()
+	assert.False(t, (&Channel{ParentID: uuid.Nil}).IsPrivateChannel())
+	assert.False(t, (&Channel{ParentID: dmChannelRootUUID}).IsPrivateChannel())
+	assert.True(t, (&Channel{ParentID: privateChannelRootUUID}).IsPrivateChannel())
+}
+
+func TestChannel_IsGroupDMChannel(t *testing.T) {
+	t.Parallel()
+	assert.False(t, (&Channel{ParentID: uuid.Nil}).IsGroupDMChannel())
+	assert.False(t, (&Channel{ParentID: dmChannelRootUUID}).IsGroupDMChannel())
+	assert.True(t, (&Channel{ParentID: groupDMChannelRootUUID}).IsGroupDMChannel())
+}
+
 func TestUsersPrivateChannel_TableName(t *testing.T) {
 	t.Parallel()
 	assert.Equal(t, "users_private_channels", (&UsersPrivateChannel{}).TableName())
@@ -35,6 +49,12 @@ func TestDMChannelMapping_TableName(t *testing.T) {
 	assert.Equal(t, "dm_channel_mappings", (&DMChannelMapping{}).TableName())
 }
 
+func TestGroupDMChannelMapping_TableName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "group_dm_channel_mappings", (&GroupDMChannelMapping{}).TableName())
+}
+
 func TestChannelEventType_String(t *testing.T) {
 	t.Parallel()
 
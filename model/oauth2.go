@@ -10,6 +10,7 @@ import (
 	"time"
 
 	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
 	"github.com/gofrs/uuid"
 	"gorm.io/gorm"
 
@@ -112,7 +113,66 @@ func (arr AccessScopes) StringArray() (r []string) {
 // Validate github.com/go-ozzo/ozzo-validation.Validatable 実装
 func (arr AccessScopes) Validate() error {
 	// TODO カスタムスコープに対応
-	return vd.Validate(arr.StringArray(), vd.Each(vd.Required, vd.In("read", "write", "manage_bot")))
+	return vd.Validate(arr.StringArray(), vd.Each(vd.Required, vd.By(func(value interface{}) error {
+		s := AccessScope(value.(string))
+		if s.IsChannelRestriction() {
+			return vd.Validate(strings.TrimPrefix(string(s), ChannelRestrictionScopePrefix), is.UUID)
+		}
+		return vd.Validate(string(s), vd.In(
+			"openid", "read", "write", "manage_bot",
+			"channels:read", "channels:write",
+			"messages:read", "messages:write",
+			"stamps:read", "stamps:write",
+			"files:read", "files:write",
+			"users:read",
+		))
+	})))
+}
+
+// ChannelRestrictionScopePrefix トークンの操作対象チャンネルを制限するスコープの接頭辞
+//
+// "channel:{チャンネルUUID}" の形式で指定します。
+const ChannelRestrictionScopePrefix = "channel:"
+
+// ChannelRestrictionScope 指定したチャンネルへの制限を表すスコープを返します
+func ChannelRestrictionScope(channelID uuid.UUID) AccessScope {
+	return AccessScope(ChannelRestrictionScopePrefix + channelID.String())
+}
+
+// IsChannelRestriction チャンネル制限スコープかどうか
+func (s AccessScope) IsChannelRestriction() bool {
+	return strings.HasPrefix(string(s), ChannelRestrictionScopePrefix)
+}
+
+// RestrictedChannels スコープによって操作対象が制限されているチャンネルのIDを返します
+//
+// 制限されていない場合はrestrictedがfalseになります。
+func (arr AccessScopes) RestrictedChannels() (channels map[uuid.UUID]struct{}, restricted bool) {
+	for s := range arr {
+		if !s.IsChannelRestriction() {
+			continue
+		}
+		restricted = true
+		id, err := uuid.FromString(strings.TrimPrefix(string(s), ChannelRestrictionScopePrefix))
+		if err != nil {
+			continue
+		}
+		if channels == nil {
+			channels = map[uuid.UUID]struct{}{}
+		}
+		channels[id] = struct{}{}
+	}
+	return channels, restricted
+}
+
+// IsChannelAllowed スコープで指定したチャンネルの操作が許可されているかどうか
+func (arr AccessScopes) IsChannelAllowed(channelID uuid.UUID) bool {
+	channels, restricted := arr.RestrictedChannels()
+	if !restricted {
+		return true
+	}
+	_, ok := channels[channelID]
+	return ok
 }
 
 // OAuth2Authorize OAuth2 認可データの構造体
@@ -185,10 +245,12 @@ func (*OAuth2Client) TableName() string {
 }
 
 // GetAvailableScopes requestで与えられたスコープのうち、利用可能なものを返します
+//
+// チャンネル制限スコープは権限を狭めるものなので、常に利用可能です。
 func (c *OAuth2Client) GetAvailableScopes(request AccessScopes) (result AccessScopes) {
 	result = AccessScopes{}
 	for s := range request {
-		if c.Scopes.Contains(s) {
+		if c.Scopes.Contains(s) || s.IsChannelRestriction() {
 			result.Add(s)
 		}
 	}
@@ -216,6 +278,8 @@ func (*OAuth2Token) TableName() string {
 }
 
 // GetAvailableScopes requestで与えられたスコープのうち、利用可能なものを返します
+//
+// トークンにチャンネル制限スコープが含まれている場合、その制限は常に引き継がれます。
 func (t *OAuth2Token) GetAvailableScopes(request AccessScopes) (result AccessScopes) {
 	result = AccessScopes{}
 	for s := range request {
@@ -223,6 +287,14 @@ func (t *OAuth2Token) GetAvailableScopes(request AccessScopes) (result AccessSco
 			result.Add(s)
 		}
 	}
+	if len(result) == 0 {
+		return
+	}
+	for s := range t.Scopes {
+		if s.IsChannelRestriction() {
+			result.Add(s)
+		}
+	}
 	return
 }
 
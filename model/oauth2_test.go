@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gofrs/uuid"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -226,6 +227,71 @@ func TestOAuth2Token_GetAvailableScopes(t *testing.T) {
 	assert.ElementsMatch(t, expect.StringArray(), token.GetAvailableScopes(test).StringArray())
 }
 
+func TestOAuth2Token_GetAvailableScopes_ChannelRestriction(t *testing.T) {
+	t.Parallel()
+
+	restriction := ChannelRestrictionScope(uuid.Must(uuid.NewV4()))
+	scopes := AccessScopes{}
+	scopes.Add("messages:read", "messages:write", restriction)
+	token := &OAuth2Token{
+		Scopes: scopes,
+	}
+
+	test := AccessScopes{}
+	test.Add("messages:read")
+	assert.ElementsMatch(t, []string{"messages:read", string(restriction)}, token.GetAvailableScopes(test).StringArray())
+
+	test = AccessScopes{}
+	test.Add("write")
+	assert.Len(t, token.GetAvailableScopes(test), 0)
+}
+
+func TestAccessScopes_Validate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		scopes []AccessScope
+		valid  bool
+	}{
+		{[]AccessScope{"read", "write"}, true},
+		{[]AccessScope{"messages:write", "channels:read", "files:read", "stamps:write"}, true},
+		{[]AccessScope{"messages:write", ChannelRestrictionScope(uuid.Must(uuid.NewV4()))}, true},
+		{[]AccessScope{"messages:delete"}, false},
+		{[]AccessScope{"channel:invalid"}, false},
+		{[]AccessScope{""}, false},
+	}
+	for _, v := range cases {
+		s := AccessScopes{}
+		s.Add(v.scopes...)
+		if v.valid {
+			assert.NoError(t, s.Validate(), v.scopes)
+		} else {
+			assert.Error(t, s.Validate(), v.scopes)
+		}
+	}
+}
+
+func TestAccessScopes_IsChannelAllowed(t *testing.T) {
+	t.Parallel()
+
+	ch1 := uuid.Must(uuid.NewV4())
+	ch2 := uuid.Must(uuid.NewV4())
+
+	s := AccessScopes{}
+	s.Add("messages:write")
+	assert.True(t, s.IsChannelAllowed(ch1))
+	_, restricted := s.RestrictedChannels()
+	assert.False(t, restricted)
+
+	s.Add(ChannelRestrictionScope(ch1))
+	assert.True(t, s.IsChannelAllowed(ch1))
+	assert.False(t, s.IsChannelAllowed(ch2))
+
+	s = AccessScopes{}
+	s.Add("channel:invalid")
+	assert.False(t, s.IsChannelAllowed(ch1))
+}
+
 func TestOAuth2Token_IsExpired(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/traPtitech/traQ/utils/optional"
+)
+
+// PersonalAccessTokenPrefix パーソナルアクセストークンの接頭辞
+const PersonalAccessTokenPrefix = "traqpat_"
+
+// PersonalAccessToken パーソナルアクセストークン構造体
+type PersonalAccessToken struct {
+	ID         uuid.UUID     `gorm:"type:char(36);not null;primaryKey"`
+	UserID     uuid.UUID     `gorm:"type:char(36);not null;index"`
+	Name       string        `gorm:"type:varchar(32);not null"`
+	TokenHash  string        `gorm:"type:char(64);not null;unique"`
+	Scopes     AccessScopes  `gorm:"type:text;not null"`
+	ExpiresAt  optional.Time `gorm:"precision:6"`
+	LastUsedAt optional.Time `gorm:"precision:6"`
+	LastUsedIP string        `gorm:"type:varchar(45);not null;default:''"`
+	CreatedAt  time.Time     `gorm:"precision:6"`
+}
+
+// TableName PersonalAccessToken構造体のテーブル名
+func (*PersonalAccessToken) TableName() string {
+	return "personal_access_tokens"
+}
+
+// IsExpired 有効期限が切れているかどうか
+func (t *PersonalAccessToken) IsExpired() bool {
+	return t.ExpiresAt.Valid && t.ExpiresAt.Time.Before(time.Now())
+}
+
+// IsPersonalAccessToken 文字列がパーソナルアクセストークンの形式かどうか
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, PersonalAccessTokenPrefix)
+}
+
+// HashPersonalAccessToken パーソナルアクセストークンの保存用ハッシュを返します
+func HashPersonalAccessToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
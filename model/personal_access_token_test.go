@@ -0,0 +1,30 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/traPtitech/traQ/utils/optional"
+)
+
+func TestPersonalAccessToken_TableName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "personal_access_tokens", (&PersonalAccessToken{}).TableName())
+}
+
+func TestPersonalAccessToken_IsExpired(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, (&PersonalAccessToken{}).IsExpired())
+	assert.False(t, (&PersonalAccessToken{ExpiresAt: optional.TimeFrom(time.Now().Add(time.Hour))}).IsExpired())
+	assert.True(t, (&PersonalAccessToken{ExpiresAt: optional.TimeFrom(time.Now().Add(-time.Hour))}).IsExpired())
+}
+
+func TestIsPersonalAccessToken(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, IsPersonalAccessToken(PersonalAccessTokenPrefix+"abcdef"))
+	assert.False(t, IsPersonalAccessToken("abcdef"))
+}
@@ -0,0 +1,83 @@
+package model
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/traPtitech/traQ/utils/optional"
+)
+
+const (
+	// MaxSidebarSections ユーザーが作成できるサイドバーセクションの最大数
+	MaxSidebarSections = 50
+	// MaxSidebarItems 1つのサイドバーセクションに追加できる項目の最大数
+	MaxSidebarItems = 200
+)
+
+// SidebarItemType サイドバー項目の種類
+type SidebarItemType string
+
+const (
+	// SidebarItemTypeChannel 公開・プライベートチャンネル
+	SidebarItemTypeChannel SidebarItemType = "channel"
+	// SidebarItemTypeDM DM・グループDMチャンネル
+	SidebarItemTypeDM SidebarItemType = "dm"
+	// SidebarItemTypeLink 任意のリンク
+	SidebarItemTypeLink SidebarItemType = "link"
+)
+
+// Valid 有効な種類かどうか
+func (t SidebarItemType) Valid() bool {
+	switch t {
+	case SidebarItemTypeChannel, SidebarItemTypeDM, SidebarItemTypeLink:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsChannel チャンネルを指す項目かどうか
+func (t SidebarItemType) IsChannel() bool {
+	return t == SidebarItemTypeChannel || t == SidebarItemTypeDM
+}
+
+// SidebarSection ユーザー定義のサイドバーセクション構造体
+type SidebarSection struct {
+	ID        uuid.UUID `gorm:"type:char(36);not null;primaryKey"`
+	UserID    uuid.UUID `gorm:"type:char(36);not null;index"`
+	Name      string    `gorm:"type:varchar(32);not null"`
+	Position  int       `gorm:"type:int;not null;default:0"`
+	Collapsed bool      `gorm:"type:boolean;not null;default:false"`
+	CreatedAt time.Time `gorm:"precision:6"`
+	UpdatedAt time.Time `gorm:"precision:6"`
+
+	User  *User          `gorm:"constraint:sidebar_sections_user_id_users_id_foreign,OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Items []*SidebarItem `gorm:"constraint:sidebar_items_section_id_sidebar_sections_id_foreign,OnUpdate:CASCADE,OnDelete:CASCADE;foreignKey:SectionID"`
+}
+
+// TableName SidebarSection構造体のテーブル名
+func (*SidebarSection) TableName() string {
+	return "sidebar_sections"
+}
+
+// SidebarItem サイドバーセクションの項目構造体
+type SidebarItem struct {
+	ID        uuid.UUID       `gorm:"type:char(36);not null;primaryKey"`
+	SectionID uuid.UUID       `gorm:"type:char(36);not null;index"`
+	Position  int             `gorm:"type:int;not null;default:0"`
+	Type      SidebarItemType `gorm:"type:varchar(10);not null"`
+	// ChannelID 項目が指すチャンネルのID Typeがchannel, dmの場合のみ
+	ChannelID optional.UUID `gorm:"type:char(36);index"`
+	// URL リンク先 Typeがlinkの場合のみ
+	URL string `gorm:"type:varchar(512);not null;default:''"`
+	// Title リンクの表示名 Typeがlinkの場合のみ
+	Title string `gorm:"type:varchar(64);not null;default:''"`
+
+	Channel *Channel `gorm:"constraint:sidebar_items_channel_id_channels_id_foreign,OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// TableName SidebarItem構造体のテーブル名
+func (*SidebarItem) TableName() string {
+	return "sidebar_items"
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSidebarSection_TableName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "sidebar_sections", (&SidebarSection{}).TableName())
+}
+
+func TestSidebarItem_TableName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "sidebar_items", (&SidebarItem{}).TableName())
+}
+
+func TestSidebarItemType_Valid(t *testing.T) {
+	t.Parallel()
+	assert.True(t, SidebarItemTypeChannel.Valid())
+	assert.True(t, SidebarItemTypeDM.Valid())
+	assert.True(t, SidebarItemTypeLink.Valid())
+	assert.False(t, SidebarItemType("").Valid())
+	assert.False(t, SidebarItemType("user").Valid())
+}
+
+func TestSidebarItemType_IsChannel(t *testing.T) {
+	t.Parallel()
+	assert.True(t, SidebarItemTypeChannel.IsChannel())
+	assert.True(t, SidebarItemTypeDM.IsChannel())
+	assert.False(t, SidebarItemTypeLink.IsChannel())
+}
@@ -6,14 +6,35 @@ import (
 	"github.com/gofrs/uuid"
 )
 
+// StampPaletteVisibility スタンプパレットの公開範囲
+type StampPaletteVisibility string
+
+const (
+	// StampPaletteVisibilityPrivate 作成者のみ閲覧可能
+	StampPaletteVisibilityPrivate StampPaletteVisibility = "private"
+	// StampPaletteVisibilityPublic 全ユーザーが閲覧・購読可能
+	StampPaletteVisibilityPublic StampPaletteVisibility = "public"
+)
+
+// Valid 有効な公開範囲かどうか
+func (v StampPaletteVisibility) Valid() bool {
+	switch v {
+	case StampPaletteVisibilityPrivate, StampPaletteVisibilityPublic:
+		return true
+	default:
+		return false
+	}
+}
+
 type StampPalette struct {
-	ID          uuid.UUID `gorm:"type:char(36);not null;primaryKey"`
-	Name        string    `gorm:"type:varchar(30);not null"`
-	Description string    `gorm:"type:text;not null"`
-	Stamps      UUIDs     `gorm:"type:text;not null"`
-	CreatorID   uuid.UUID `gorm:"type:char(36);not null;index"`
-	CreatedAt   time.Time `gorm:"precision:6"`
-	UpdatedAt   time.Time `gorm:"precision:6"`
+	ID          uuid.UUID              `gorm:"type:char(36);not null;primaryKey"`
+	Name        string                 `gorm:"type:varchar(30);not null"`
+	Description string                 `gorm:"type:text;not null"`
+	Stamps      UUIDs                  `gorm:"type:text;not null"`
+	CreatorID   uuid.UUID              `gorm:"type:char(36);not null;index"`
+	Visibility  StampPaletteVisibility `gorm:"type:varchar(10);not null;default:'private';index"`
+	CreatedAt   time.Time              `gorm:"precision:6"`
+	UpdatedAt   time.Time              `gorm:"precision:6"`
 
 	Creator User `gorm:"constraint:stamp_palettes_creator_id_users_id_foreign,OnUpdate:CASCADE,OnDelete:CASCADE;foreignKey:CreatorID"`
 }
@@ -22,3 +43,28 @@ type StampPalette struct {
 func (*StampPalette) TableName() string {
 	return "stamp_palettes"
 }
+
+// IsPublic 公開されているスタンプパレットかどうか
+func (sp *StampPalette) IsPublic() bool {
+	return sp.Visibility == StampPaletteVisibilityPublic
+}
+
+// IsVisibleTo 指定したユーザーがスタンプパレットを閲覧できるかどうか
+func (sp *StampPalette) IsVisibleTo(userID uuid.UUID) bool {
+	return sp.IsPublic() || sp.CreatorID == userID
+}
+
+// StampPaletteSubscription スタンプパレットの購読
+type StampPaletteSubscription struct {
+	UserID    uuid.UUID `gorm:"type:char(36);not null;primaryKey"`
+	PaletteID uuid.UUID `gorm:"type:char(36);not null;primaryKey;index"`
+	CreatedAt time.Time `gorm:"precision:6"`
+
+	User    User         `gorm:"constraint:stamp_palette_subscriptions_user_id_users_id_foreign,OnUpdate:CASCADE,OnDelete:CASCADE;foreignKey:UserID"`
+	Palette StampPalette `gorm:"constraint:stamp_palette_subscriptions_palette_id_stamp_palettes_id_foreign,OnUpdate:CASCADE,OnDelete:CASCADE;foreignKey:PaletteID"`
+}
+
+// TableName StampPaletteSubscription構造体のテーブル名
+func (*StampPaletteSubscription) TableName() string {
+	return "stamp_palette_subscriptions"
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStampPalette_TableName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "stamp_palettes", (&StampPalette{}).TableName())
+}
+
+func TestStampPaletteSubscription_TableName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "stamp_palette_subscriptions", (&StampPaletteSubscription{}).TableName())
+}
+
+func TestStampPaletteVisibility_Valid(t *testing.T) {
+	t.Parallel()
+	assert.True(t, StampPaletteVisibilityPrivate.Valid())
+	assert.True(t, StampPaletteVisibilityPublic.Valid())
+	assert.False(t, StampPaletteVisibility("").Valid())
+	assert.False(t, StampPaletteVisibility("internal").Valid())
+}
+
+func TestStampPalette_IsVisibleTo(t *testing.T) {
+	t.Parallel()
+	creator := uuid.Must(uuid.NewV4())
+	other := uuid.Must(uuid.NewV4())
+
+	private := &StampPalette{CreatorID: creator, Visibility: StampPaletteVisibilityPrivate}
+	assert.True(t, private.IsVisibleTo(creator))
+	assert.False(t, private.IsVisibleTo(other))
+
+	public := &StampPalette{CreatorID: creator, Visibility: StampPaletteVisibilityPublic}
+	assert.True(t, public.IsVisibleTo(creator))
+	assert.True(t, public.IsVisibleTo(other))
+}
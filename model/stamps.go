@@ -7,16 +7,29 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	// MaxStampAliases 1つのスタンプに設定できるエイリアスの最大数
+	MaxStampAliases = 10
+	// MaxStampTags 1つのスタンプに設定できるタグの最大数
+	MaxStampTags = 10
+)
+
 // Stamp スタンプ構造体
 type Stamp struct {
-	ID        uuid.UUID      `gorm:"type:char(36);not null;primaryKey"         json:"id"`
-	Name      string         `gorm:"type:varchar(32);not null;unique"          json:"name"`
-	CreatorID uuid.UUID      `gorm:"type:char(36);not null"                    json:"creatorId"`
-	FileID    uuid.UUID      `gorm:"type:char(36);not null"                    json:"fileId"`
-	IsUnicode bool           `gorm:"type:boolean;not null;default:false;index" json:"isUnicode"`
-	CreatedAt time.Time      `gorm:"precision:6"                               json:"createdAt"`
-	UpdatedAt time.Time      `gorm:"precision:6"                               json:"updatedAt"`
-	DeletedAt gorm.DeletedAt `gorm:"precision:6"                               json:"-"`
+	ID        uuid.UUID      `gorm:"type:char(36);not null;primaryKey"          json:"id"`
+	Name      string         `gorm:"type:varchar(32);not null;unique"           json:"name"`
+	CreatorID uuid.UUID      `gorm:"type:char(36);not null"                     json:"creatorId"`
+	FileID    uuid.UUID      `gorm:"type:char(36);not null"                     json:"fileId"`
+	IsUnicode bool           `gorm:"type:boolean;not null;default:false;index"  json:"isUnicode"`
+	Category  string         `gorm:"type:varchar(32);not null;default:'';index" json:"category"`
+	CreatedAt time.Time      `gorm:"precision:6"                                json:"createdAt"`
+	UpdatedAt time.Time      `gorm:"precision:6"                                json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"precision:6"                                json:"-"`
+
+	// Aliases スタンプのエイリアス stamp_aliasesテーブルから読み込まれます
+	Aliases []string `gorm:"-" json:"aliases"`
+	// Tags スタンプのタグ stamp_tagsテーブルから読み込まれます
+	Tags []string `gorm:"-" json:"tags"`
 
 	File *FileMeta `gorm:"constraint:stamps_file_id_files_id_foreign,OnUpdate:CASCADE,OnDelete:NO ACTION;foreignKey:FileID" json:"-"`
 }
@@ -30,3 +43,33 @@ func (*Stamp) TableName() string {
 func (s *Stamp) IsSystemStamp() bool {
 	return s.CreatorID == uuid.Nil && s.ID != uuid.Nil && len(s.Name) > 0
 }
+
+// StampAlias スタンプのエイリアス構造体
+//
+// エイリアスは全てのスタンプ名・エイリアスの中で一意です。
+type StampAlias struct {
+	Alias     string    `gorm:"type:varchar(32);not null;primaryKey"`
+	StampID   uuid.UUID `gorm:"type:char(36);not null;index"`
+	CreatedAt time.Time `gorm:"precision:6"`
+
+	Stamp *Stamp `gorm:"constraint:stamp_aliases_stamp_id_stamps_id_foreign,OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// TableName StampAlias構造体のテーブル名
+func (*StampAlias) TableName() string {
+	return "stamp_aliases"
+}
+
+// StampTag スタンプのタグ構造体
+type StampTag struct {
+	StampID   uuid.UUID `gorm:"type:char(36);not null;primaryKey"`
+	Tag       string    `gorm:"type:varchar(30);not null;primaryKey;index"`
+	CreatedAt time.Time `gorm:"precision:6"`
+
+	Stamp *Stamp `gorm:"constraint:stamp_tags_stamp_id_stamps_id_foreign,OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// TableName StampTag構造体のテーブル名
+func (*StampTag) TableName() string {
+	return "stamp_tags"
+}
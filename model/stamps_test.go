@@ -10,3 +10,13 @@ func TestStamp_TableName(t *testing.T) {
 	t.Parallel()
 	assert.Equal(t, "stamps", (&Stamp{}).TableName())
 }
+
+func TestStampAlias_TableName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "stamp_aliases", (&StampAlias{}).TableName())
+}
+
+func TestStampTag_TableName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "stamp_tags", (&StampTag{}).TableName())
+}
@@ -0,0 +1,101 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/traPtitech/traQ/utils/optional"
+)
+
+// UserTOTP ユーザーのTOTP設定の構造体
+type UserTOTP struct {
+	UserID  uuid.UUID `gorm:"type:char(36);not null;primaryKey"`
+	Secret  string    `gorm:"type:varchar(64);not null"`
+	Enabled bool      `gorm:"type:boolean;not null;default:false"`
+	// LastUsedStep 最後に使用されたコードのタイムステップ (コードの再利用防止用)
+	LastUsedStep int64     `gorm:"type:bigint;not null;default:0"`
+	CreatedAt    time.Time `gorm:"precision:6"`
+	UpdatedAt    time.Time `gorm:"precision:6"`
+
+	User *User `gorm:"constraint:user_totps_user_id_users_id_foreign,OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// TableName UserTOTP構造体のテーブル名
+func (*UserTOTP) TableName() string {
+	return "user_totps"
+}
+
+// UserRecoveryCode ユーザーの二要素認証リカバリーコードの構造体
+type UserRecoveryCode struct {
+	UserID    uuid.UUID `gorm:"type:char(36);not null;primaryKey"`
+	CodeHash  string    `gorm:"type:char(64);not null;primaryKey"`
+	CreatedAt time.Time `gorm:"precision:6"`
+
+	User *User `gorm:"constraint:user_recovery_codes_user_id_users_id_foreign,OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// TableName UserRecoveryCode構造体のテーブル名
+func (*UserRecoveryCode) TableName() string {
+	return "user_recovery_codes"
+}
+
+// HashRecoveryCode リカバリーコードのハッシュ値を返します
+func HashRecoveryCode(code string) string {
+	h := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(h[:])
+}
+
+// WebAuthnCredential ユーザーのWebAuthnクレデンシャルの構造体
+type WebAuthnCredential struct {
+	ID              uuid.UUID `gorm:"type:char(36);not null;primaryKey"`
+	UserID          uuid.UUID `gorm:"type:char(36);not null;index"`
+	Name            string    `gorm:"type:varchar(32);not null"`
+	CredentialID    []byte    `gorm:"type:varbinary(255);not null;unique"`
+	PublicKey       []byte    `gorm:"type:blob;not null"`
+	AttestationType string    `gorm:"type:varchar(32);not null"`
+	AAGUID          []byte    `gorm:"type:varbinary(16);not null"`
+	SignCount       uint32    `gorm:"type:int unsigned;not null;default:0"`
+	CreatedAt       time.Time `gorm:"precision:6"`
+	UpdatedAt       time.Time `gorm:"precision:6"`
+
+	User *User `gorm:"constraint:user_webauthn_credentials_user_id_users_id_foreign,OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// TableName WebAuthnCredential構造体のテーブル名
+func (*WebAuthnCredential) TableName() string {
+	return "user_webauthn_credentials"
+}
+
+// TwoFactorRequiredRole 二要素認証が必須のロールの構造体
+type TwoFactorRequiredRole struct {
+	Role      string    `gorm:"type:varchar(30);not null;primaryKey"`
+	CreatedAt time.Time `gorm:"precision:6"`
+}
+
+// TableName TwoFactorRequiredRole構造体のテーブル名
+func (*TwoFactorRequiredRole) TableName() string {
+	return "two_factor_required_roles"
+}
+
+// UserTwoFactorLockout ユーザーの二要素認証の連続失敗回数・ロック状態の構造体
+type UserTwoFactorLockout struct {
+	UserID      uuid.UUID     `gorm:"type:char(36);not null;primaryKey"`
+	Failures    int           `gorm:"type:int;not null;default:0"`
+	LockedUntil optional.Time `gorm:"precision:6"`
+	UpdatedAt   time.Time     `gorm:"precision:6"`
+
+	User *User `gorm:"constraint:user_two_factor_lockouts_user_id_users_id_foreign,OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// TableName UserTwoFactorLockout構造体のテーブル名
+func (*UserTwoFactorLockout) TableName() string {
+	return "user_two_factor_lockouts"
+}
+
+// IsLocked 指定した時刻にロックされているかどうか
+func (l *UserTwoFactorLockout) IsLocked(now time.Time) bool {
+	return l.LockedUntil.Valid && now.Before(l.LockedUntil.Time)
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/traPtitech/traQ/utils/optional"
+)
+
+func TestUserTOTP_TableName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "user_totps", (&UserTOTP{}).TableName())
+}
+
+func TestUserRecoveryCode_TableName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "user_recovery_codes", (&UserRecoveryCode{}).TableName())
+}
+
+func TestHashRecoveryCode(t *testing.T) {
+	t.Parallel()
+	assert.Len(t, HashRecoveryCode("abcde-12345"), 64)
+	assert.Equal(t, HashRecoveryCode("abcde-12345"), HashRecoveryCode("abcde-12345"))
+	assert.NotEqual(t, HashRecoveryCode("abcde-12345"), HashRecoveryCode("abcde-12346"))
+}
+
+func TestWebAuthnCredential_TableName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "user_webauthn_credentials", (&WebAuthnCredential{}).TableName())
+}
+
+func TestTwoFactorRequiredRole_TableName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "two_factor_required_roles", (&TwoFactorRequiredRole{}).TableName())
+}
+
+func TestUserTwoFactorLockout_TableName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "user_two_factor_lockouts", (&UserTwoFactorLockout{}).TableName())
+}
+
+func TestUserTwoFactorLockout_IsLocked(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	assert.False(t, (&UserTwoFactorLockout{}).IsLocked(now))
+	assert.True(t, (&UserTwoFactorLockout{LockedUntil: optional.TimeFrom(now.Add(time.Minute))}).IsLocked(now))
+	assert.False(t, (&UserTwoFactorLockout{LockedUntil: optional.TimeFrom(now.Add(-time.Minute))}).IsLocked(now))
+}
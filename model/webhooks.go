@@ -1,6 +1,9 @@
 package model
 
 import (
+	"database/sql/driver"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/gofrs/uuid"
@@ -16,21 +19,29 @@ type Webhook interface {
 	GetSecret() string
 	GetChannelID() uuid.UUID
 	GetCreatorID() uuid.UUID
+	GetOutgoingURL() string
+	GetTriggerWords() []string
+	GetTemplates() WebhookTemplates
+	GetPayloadTemplate() string
 	GetCreatedAt() time.Time
 	GetUpdatedAt() time.Time
 }
 
 // WebhookBot DB用WebhookBot構造体
 type WebhookBot struct {
-	ID          uuid.UUID      `gorm:"type:char(36);not null;primaryKey"`
-	BotUserID   uuid.UUID      `gorm:"type:char(36);not null;unique"`
-	Description string         `gorm:"type:text;not null"`
-	Secret      string         `gorm:"type:text;not null"`
-	ChannelID   uuid.UUID      `gorm:"type:char(36);not null"`
-	CreatorID   uuid.UUID      `gorm:"type:char(36);not null"`
-	CreatedAt   time.Time      `gorm:"precision:6"`
-	UpdatedAt   time.Time      `gorm:"precision:6"`
-	DeletedAt   gorm.DeletedAt `gorm:"precision:6"`
+	ID              uuid.UUID        `gorm:"type:char(36);not null;primaryKey"`
+	BotUserID       uuid.UUID        `gorm:"type:char(36);not null;unique"`
+	Description     string           `gorm:"type:text;not null"`
+	Secret          string           `gorm:"type:text;not null"`
+	ChannelID       uuid.UUID        `gorm:"type:char(36);not null"`
+	CreatorID       uuid.UUID        `gorm:"type:char(36);not null"`
+	OutgoingURL     string           `gorm:"type:text;not null"`
+	TriggerWords    string           `gorm:"type:text;not null"`
+	Templates       WebhookTemplates `gorm:"type:text"`
+	PayloadTemplate string           `gorm:"type:text;not null"`
+	CreatedAt       time.Time        `gorm:"precision:6"`
+	UpdatedAt       time.Time        `gorm:"precision:6"`
+	DeletedAt       gorm.DeletedAt   `gorm:"precision:6"`
 
 	BotUser User     `gorm:"constraint:webhook_bots_bot_user_id_users_id_foreign,OnUpdate:CASCADE,OnDelete:CASCADE;foreignkey:BotUserID"`
 	Creator *User    `gorm:"constraint:webhook_bots_creator_id_users_id_foreign,OnUpdate:CASCADE,OnDelete:CASCADE;foreignKey:CreatorID"`
@@ -77,6 +88,26 @@ func (w *WebhookBot) GetCreatorID() uuid.UUID {
 	return w.CreatorID
 }
 
+// GetOutgoingURL Outgoing Webhookの送信先URLを返します
+func (w *WebhookBot) GetOutgoingURL() string {
+	return w.OutgoingURL
+}
+
+// GetTriggerWords Outgoing Webhookのトリガーワードの配列を返します
+func (w *WebhookBot) GetTriggerWords() []string {
+	return strings.Fields(w.TriggerWords)
+}
+
+// GetTemplates Webhookのメッセージテンプレートを返します
+func (w *WebhookBot) GetTemplates() WebhookTemplates {
+	return w.Templates
+}
+
+// GetPayloadTemplate JSONペイロード用のメッセージテンプレートを返します
+func (w *WebhookBot) GetPayloadTemplate() string {
+	return w.PayloadTemplate
+}
+
 // GetCreatedAt Webhookの作成日時を返します
 func (w *WebhookBot) GetCreatedAt() time.Time {
 	return w.CreatedAt
@@ -86,3 +117,53 @@ func (w *WebhookBot) GetCreatedAt() time.Time {
 func (w *WebhookBot) GetUpdatedAt() time.Time {
 	return w.UpdatedAt
 }
+
+// WebhookTemplates Webhookのメッセージテンプレート (テンプレートキー -> テンプレート)
+type WebhookTemplates map[string]string
+
+// Value database/sql/driver.Valuer 実装
+func (t WebhookTemplates) Value() (driver.Value, error) {
+	if len(t) == 0 {
+		return "{}", nil
+	}
+	return json.MarshalToString(t)
+}
+
+// Scan database/sql.Scanner 実装
+func (t *WebhookTemplates) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case nil:
+		*t = WebhookTemplates{}
+		return nil
+	case string:
+		return json.Unmarshal([]byte(s), t)
+	case []byte:
+		return json.Unmarshal(s, t)
+	default:
+		return errors.New("failed to scan WebhookTemplates")
+	}
+}
+
+// Get 指定したキーのテンプレートを返します
+func (t WebhookTemplates) Get(key string) (string, bool) {
+	tmpl, ok := t[key]
+	return tmpl, ok && len(tmpl) > 0
+}
+
+// WebhookDeliveryLog Outgoing Webhook配送ログ
+type WebhookDeliveryLog struct {
+	RequestID uuid.UUID `gorm:"type:char(36);not null;primaryKey"`
+	WebhookID uuid.UUID `gorm:"type:char(36);not null;index:webhook_id_date_time_idx"`
+	MessageID uuid.UUID `gorm:"type:char(36);not null"`
+	Body      string    `gorm:"type:text"`
+	Result    string    `gorm:"type:char(2);not null"`
+	Error     string    `gorm:"type:text"`
+	Code      int       `gorm:"not null;default:0"`
+	Latency   int64     `gorm:"not null;default:0"`
+	DateTime  time.Time `gorm:"precision:6;index:webhook_id_date_time_idx"`
+}
+
+// TableName WebhookDeliveryLogのテーブル名
+func (*WebhookDeliveryLog) TableName() string {
+	return "webhook_delivery_logs"
+}
@@ -66,3 +66,89 @@ func TestWebhookBot_GetUpdatedAt(t *testing.T) {
 	tm := time.Now()
 	assert.Equal(t, tm, (&WebhookBot{UpdatedAt: tm}).GetUpdatedAt())
 }
+
+func TestWebhookBot_GetOutgoingURL(t *testing.T) {
+	t.Parallel()
+	url := "https://example.com/webhook"
+	assert.Equal(t, url, (&WebhookBot{OutgoingURL: url}).GetOutgoingURL())
+}
+
+func TestWebhookBot_GetTriggerWords(t *testing.T) {
+	t.Parallel()
+	assert.Empty(t, (&WebhookBot{}).GetTriggerWords())
+	assert.Equal(t, []string{"!deploy", "!status"}, (&WebhookBot{TriggerWords: " !deploy  !status "}).GetTriggerWords())
+}
+
+func TestWebhookDeliveryLog_TableName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "webhook_delivery_logs", (&WebhookDeliveryLog{}).TableName())
+}
+
+func TestWebhookBot_GetTemplates(t *testing.T) {
+	t.Parallel()
+	tmpls := WebhookTemplates{"github.push": "{{ .ref }}"}
+	assert.Equal(t, tmpls, (&WebhookBot{Templates: tmpls}).GetTemplates())
+}
+
+func TestWebhookBot_GetPayloadTemplate(t *testing.T) {
+	t.Parallel()
+	tmpl := "{{ .text }}"
+	assert.Equal(t, tmpl, (&WebhookBot{PayloadTemplate: tmpl}).GetPayloadTemplate())
+}
+
+func TestWebhookTemplates_Value(t *testing.T) {
+	t.Parallel()
+
+	v, err := WebhookTemplates(nil).Value()
+	if assert.NoError(t, err) {
+		assert.Equal(t, "{}", v)
+	}
+	v, err = WebhookTemplates{"a": "b"}.Value()
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{"a":"b"}`, v)
+	}
+}
+
+func TestWebhookTemplates_Scan(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nil", func(t *testing.T) {
+		t.Parallel()
+		var tmpls WebhookTemplates
+		assert.NoError(t, tmpls.Scan(nil))
+		assert.Len(t, tmpls, 0)
+	})
+
+	t.Run("string", func(t *testing.T) {
+		t.Parallel()
+		var tmpls WebhookTemplates
+		assert.NoError(t, tmpls.Scan(`{"a":"b"}`))
+		assert.EqualValues(t, WebhookTemplates{"a": "b"}, tmpls)
+	})
+
+	t.Run("[]byte", func(t *testing.T) {
+		t.Parallel()
+		var tmpls WebhookTemplates
+		assert.NoError(t, tmpls.Scan([]byte(`{"a":"b"}`)))
+		assert.EqualValues(t, WebhookTemplates{"a": "b"}, tmpls)
+	})
+
+	t.Run("other", func(t *testing.T) {
+		t.Parallel()
+		var tmpls WebhookTemplates
+		assert.Error(t, tmpls.Scan(1))
+	})
+}
+
+func TestWebhookTemplates_Get(t *testing.T) {
+	t.Parallel()
+	tmpls := WebhookTemplates{"a": "b", "c": ""}
+
+	v, ok := tmpls.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "b", v)
+	_, ok = tmpls.Get("c")
+	assert.False(t, ok)
+	_, ok = tmpls.Get("d")
+	assert.False(t, ok)
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/utils/optional"
+)
+
+// AuditLogQuery 監査ログ取得用クエリ
+type AuditLogQuery struct {
+	ActorID    optional.UUID
+	Action     model.AuditAction
+	TargetType model.AuditTargetType
+	TargetID   string
+	Since      optional.Time
+	Until      optional.Time
+	Inclusive  bool
+	Limit      int
+	Offset     int
+	Asc        bool
+}
+
+// AuditLogRepository 監査ログリポジトリ
+//
+// 監査ログは追記のみ可能です。
+type AuditLogRepository interface {
+	// CreateAuditLog 監査ログを記録します
+	//
+	// IDと記録日時は自動で設定されます。
+	// DBによるエラーを返すことがあります。
+	CreateAuditLog(log *model.AuditLog) error
+	// GetAuditLogs 指定したクエリで監査ログを取得します
+	//
+	// 負のoffset, limitは無視されます。
+	// 指定した範囲内にlimitを超えて監査ログが存在していた場合、trueを返します。
+	// DBによるエラーを返すことがあります。
+	GetAuditLogs(query AuditLogQuery) (logs []*model.AuditLog, more bool, err error)
+}
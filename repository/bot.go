@@ -168,6 +168,12 @@ type BotRepository interface {
 	// 存在しないBotを指定した場合、空配列とnilを返します。
 	// DBによるエラーを返すことがあります。
 	GetBotEventLogs(botID uuid.UUID, limit, offset int) ([]*model.BotEventLog, error)
+	// GetBotEventLogsAfter 指定したBotの指定したリクエストIDより後のイベントログを古い順(同時刻の場合はリクエストID順)に取得します
+	//
+	// 成功した場合、イベントログの配列とnilを返します。負のlimitは無視されます。
+	// 指定したリクエストIDのイベントログが存在しない場合、ErrNotFoundを返します。
+	// DBによるエラーを返すことがあります。
+	GetBotEventLogsAfter(botID, requestID uuid.UUID, limit int) ([]*model.BotEventLog, error)
 	// PurgeBotEventLogs 指定した時間以前のBotイベントログを全て消去します
 	//
 	// 成功した場合、nilを返します。
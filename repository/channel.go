@@ -27,6 +27,16 @@ type UpdateChannelArgs struct {
 	Parent             optional.UUID
 }
 
+// CreatePublicChannelArgs 公開チャンネル一括作成の各チャンネルの引数
+type CreatePublicChannelArgs struct {
+	// Channel 作成するチャンネル。IDとParentIDは呼び出し側で設定する必要があります
+	Channel model.Channel
+	// Subscribers 通知購読させるユーザー
+	Subscribers set.UUID
+	// Bots 参加させるBOT
+	Bots set.UUID
+}
+
 // ChannelEventsQuery GetChannelEvents用クエリ
 type ChannelEventsQuery struct {
 	Channel   uuid.UUID
@@ -81,14 +91,40 @@ type ChannelRepository interface {
 	GetPublicChannels() ([]*model.Channel, error)
 	// CreateChannel チャンネルを作成します
 	//
-	// dmがtrueの場合、privateMembersに1人または2人のユーザーが入っている必要があります。
+	// dmがtrueの場合、privateMembersに1人以上のユーザーが入っている必要があります。
+	// 3人以上の場合はグループDMチャンネルとして作成されます。
+	// 同じ親チャンネルに同名のチャンネル、または同じメンバーのグループDMチャンネルが既に存在する場合、ErrAlreadyExistsを返します。
+	// プライベートチャンネルの名前は一意である必要はありません。
 	CreateChannel(ch model.Channel, privateMembers set.UUID, dm bool) (*model.Channel, error)
+	// CreatePublicChannels 公開チャンネルを購読者・参加BOTと共に一括で作成します
+	//
+	// 全てのチャンネルは1つのトランザクションで作成されます。
+	// 親チャンネルが子チャンネルより先に来るようにargsを並べる必要があります。
+	// 存在しないユーザー・BOTは無視されます。
+	// 同じ親チャンネルに同名のチャンネルが既に存在する場合、ErrAlreadyExistsを返します。
+	CreatePublicChannels(args []*CreatePublicChannelArgs) ([]*model.Channel, error)
 	// UpdateChannel 指定したチャンネルの情報を変更します
 	//
 	// 存在しないチャンネルを指定した場合、ErrNotFoundを返します。
+	// 変更後のチャンネル名が同じ親チャンネルの他のチャンネルと重複する場合、ErrAlreadyExistsを返します。
 	UpdateChannel(channelID uuid.UUID, args UpdateChannelArgs) (*model.Channel, error)
 	// ArchiveChannels 指定したチャンネルをアーカイブします
 	ArchiveChannels(ids []uuid.UUID) ([]*model.Channel, error)
+	// MergeChannel 統合元チャンネルの子チャンネル・メッセージ等を統合先チャンネルに移動します
+	//
+	// 子チャンネル、メッセージ(ピン留め・未読を含む)、ファイル、購読、スター、BOTの参加、Webhook、ホームチャンネル設定、
+	// チャンネルロールが1つのトランザクションで移動されます。移動したメッセージのupdated_atは変更されません。
+	// 統合先で既にチャンネルロールが割り当てられているユーザーは、統合先のロールが優先されます。
+	// 移動後の子チャンネルと、移動したメッセージ(削除済みを含む)の数を返します。
+	// 統合先に統合元の子チャンネルと同名の子チャンネルが存在する場合、ErrAlreadyExistsを返します。
+	// 引数にuuid.Nilを指定した場合、ErrNilIDを返します。
+	MergeChannel(srcID, dstID, updaterID uuid.UUID) (children []*model.Channel, movedMessages int64, err error)
+	// DeleteChannel 指定したチャンネルを完全に削除します
+	//
+	// 未削除のメッセージまたはWebhookが存在する場合、ErrForbiddenを返します。
+	// 存在しないチャンネルを指定した場合、ErrNotFoundを返します。
+	// 引数にuuid.Nilを指定した場合、ErrNilIDを返します。
+	DeleteChannel(channelID uuid.UUID) error
 	// GetChannel 指定したチャンネルを取得します
 	//
 	// 存在しないチャンネルを指定した場合、ErrNotFoundを返します。
@@ -99,8 +135,28 @@ type ChannelRepository interface {
 	GetDirectMessageChannel(user1, user2 uuid.UUID) (*model.Channel, error)
 	// GetDirectMessageChannelMapping 指定したユーザーのDMチャンネルのマッピングを取得します
 	GetDirectMessageChannelMapping(userID uuid.UUID) ([]*model.DMChannelMapping, error)
+	// GetGroupDirectMessageChannel 指定したメンバー集合のグループDMチャンネルを取得します
+	//
+	// 存在しなかった場合、ErrNotFoundを返します。
+	GetGroupDirectMessageChannel(members set.UUID) (*model.Channel, error)
+	// GetGroupDirectMessageChannelsByUser 指定したユーザーがメンバーのグループDMチャンネルを全て取得します
+	GetGroupDirectMessageChannelsByUser(userID uuid.UUID) ([]*model.Channel, error)
 	// GetPrivateChannelMemberIDs 指定したプライベートチャンネルのメンバーのUUIDを取得します
 	GetPrivateChannelMemberIDs(channelID uuid.UUID) ([]uuid.UUID, error)
+	// AddPrivateChannelMembers 指定したプライベートチャンネルにメンバーを追加します
+	//
+	// 既にメンバーのユーザー、存在しないユーザーは無視されます。
+	// channelIDにuuid.Nilを指定した場合、ErrNilIDを返します。
+	AddPrivateChannelMembers(channelID uuid.UUID, userIDs set.UUID) (added []uuid.UUID, err error)
+	// RemovePrivateChannelMember 指定したプライベートチャンネルからメンバーを削除します
+	//
+	// メンバーでなかった場合、ErrNotFoundを返します。
+	// 引数にuuid.Nilを指定した場合、ErrNilIDを返します。
+	RemovePrivateChannelMember(channelID, userID uuid.UUID) error
+	// GetPrivateChannelsByUser 指定したユーザーがメンバーの招待制プライベートチャンネルを全て取得します
+	//
+	// DMチャンネルは含まれません。
+	GetPrivateChannelsByUser(userID uuid.UUID) ([]*model.Channel, error)
 	// ChangeChannelSubscription ユーザーのチャンネルの購読を変更します
 	//
 	// channelIDにuuid.Nilを指定した場合、ErrNilIDを返します。
@@ -119,4 +175,18 @@ type ChannelRepository interface {
 	GetChannelStats(channelID uuid.UUID) (*ChannelStats, error)
 	// RecordChannelEvent チャンネルイベントを記録します
 	RecordChannelEvent(channelID uuid.UUID, eventType model.ChannelEventType, detail model.ChannelEventDetail, datetime time.Time) error
+	// SetChannelRole 指定したチャンネルでユーザーにチャンネルロールを割り当てます
+	//
+	// 既にチャンネルロールが割り当てられている場合は置き換えます。
+	// 引数にuuid.Nilを指定するとErrNilIDを返します。
+	SetChannelRole(channelID, userID uuid.UUID, role string) error
+	// DeleteChannelRole 指定したチャンネルでユーザーに割り当てられているチャンネルロールを削除します
+	//
+	// 割り当てられていなかった場合、ErrNotFoundを返します。
+	// 引数にuuid.Nilを指定するとErrNilIDを返します。
+	DeleteChannelRole(channelID, userID uuid.UUID) error
+	// GetChannelRoles 指定したチャンネルで割り当てられている全てのチャンネルロールを取得します
+	GetChannelRoles(channelID uuid.UUID) ([]*model.ChannelRole, error)
+	// GetUserChannelRoles 指定したチャンネルのいずれかでユーザーに割り当てられているチャンネルロールを取得します
+	GetUserChannelRoles(userID uuid.UUID, channelIDs []uuid.UUID) ([]*model.ChannelRole, error)
 }
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"github.com/gofrs/uuid"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/utils/optional"
+)
+
+// ChannelArchivalRepository 非アクティブチャンネルの自動アーカイブ状態リポジトリ
+type ChannelArchivalRepository interface {
+	// GetChannelArchivalState 指定したチャンネルの自動アーカイブ状態を取得します
+	//
+	// 成功した場合、自動アーカイブ状態とnilを返します。
+	// 存在しなかった場合、ErrNotFoundを返します。
+	// DBによるエラーを返すことがあります。
+	GetChannelArchivalState(channelID uuid.UUID) (*model.ChannelArchivalState, error)
+	// GetChannelArchivalStates 全てのチャンネルの自動アーカイブ状態を取得します
+	//
+	// 成功した場合、自動アーカイブ状態の配列とnilを返します。
+	// DBによるエラーを返すことがあります。
+	GetChannelArchivalStates() ([]*model.ChannelArchivalState, error)
+	// SetChannelArchivalExempt 指定したチャンネルを自動アーカイブの対象外にするかどうかを設定します
+	//
+	// 成功した場合、nilを返します。
+	// 対象外にした場合、アーカイブ予告済みの状態は解除されます。
+	// channelIDにuuid.Nilを指定した場合、ErrNilIDを返します。
+	// 存在しないチャンネルの場合、ErrNotFoundを返します。
+	// DBによるエラーを返すことがあります。
+	SetChannelArchivalExempt(channelID uuid.UUID, exempt bool) error
+	// SetChannelArchivalWarnedAt 指定したチャンネルのアーカイブ予告日時を設定します
+	//
+	// 成功した場合、nilを返します。
+	// warnedAtに無効な値を指定した場合、アーカイブ予告済みの状態を解除します。
+	// channelIDにuuid.Nilを指定した場合、ErrNilIDを返します。
+	// 存在しないチャンネルの場合、ErrNotFoundを返します。
+	// DBによるエラーを返すことがあります。
+	SetChannelArchivalWarnedAt(channelID uuid.UUID, warnedAt optional.Time) error
+	// GetAllChannelLatestMessages 全てのチャンネルの最終メッセージ投稿日時を取得します
+	//
+	// 成功した場合、最終メッセージ投稿日時の配列とnilを返します。
+	// DBによるエラーを返すことがあります。
+	GetAllChannelLatestMessages() ([]*model.ChannelLatestMessage, error)
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/traPtitech/traQ/model"
+)
+
+// SetChannelRetentionPolicyArgs チャンネルのメッセージ保持ポリシー設定引数
+type SetChannelRetentionPolicyArgs struct {
+	RetentionDays int
+	Purge         bool
+	KeepPinned    bool
+	UpdaterID     uuid.UUID
+}
+
+// ChannelRetentionRepository チャンネルのメッセージ保持ポリシーリポジトリ
+type ChannelRetentionRepository interface {
+	// SetChannelRetentionPolicy 指定したチャンネルのメッセージ保持ポリシーを設定します
+	//
+	// 成功した場合、設定後の保持ポリシーとnilを返します。
+	// 既にポリシーが設定されている場合は上書きします。
+	// channelID, args.UpdaterIDにuuid.Nilを指定した場合、ErrNilIDを返します。
+	// 存在しないチャンネルの場合、ErrNotFoundを返します。
+	// DBによるエラーを返すことがあります。
+	SetChannelRetentionPolicy(channelID uuid.UUID, args SetChannelRetentionPolicyArgs) (*model.ChannelRetentionPolicy, error)
+	// GetChannelRetentionPolicy 指定したチャンネルに設定されているメッセージ保持ポリシーを取得します
+	//
+	// 成功した場合、保持ポリシーとnilを返します。
+	// 存在しなかった場合、ErrNotFoundを返します。
+	// DBによるエラーを返すことがあります。
+	GetChannelRetentionPolicy(channelID uuid.UUID) (*model.ChannelRetentionPolicy, error)
+	// GetChannelRetentionPolicies 全てのメッセージ保持ポリシーを取得します
+	//
+	// 成功した場合、保持ポリシーの配列とnilを返します。
+	// DBによるエラーを返すことがあります。
+	GetChannelRetentionPolicies() ([]*model.ChannelRetentionPolicy, error)
+	// DeleteChannelRetentionPolicy 指定したチャンネルに設定されているメッセージ保持ポリシーを削除します
+	//
+	// 成功した場合、nilを返します。
+	// 既に存在しない場合、ErrNotFoundを返します。
+	// channelIDにuuid.Nilを指定した場合、ErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	DeleteChannelRetentionPolicy(channelID uuid.UUID) error
+	// DeleteChannelMessagesBefore 指定したチャンネルの指定日時より前に投稿されたメッセージを最大limit件削除します
+	//
+	// 成功した場合、削除したメッセージの数とnilを返します。
+	// メッセージは論理削除され、未読・ピン留め・クリップも削除されます。
+	// keepPinnedがtrueの場合、ピン留めされたメッセージは削除しません。
+	// channelIDにuuid.Nilを指定した場合、ErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	DeleteChannelMessagesBefore(channelID uuid.UUID, before time.Time, keepPinned bool, limit int) (int, error)
+	// PurgeDeletedChannelMessages 指定したチャンネルの論理削除済みメッセージを最大limit件物理削除します
+	//
+	// createdBeforeより前に投稿され、deletedBeforeより前に削除されたメッセージが対象です。
+	// メッセージのスタンプ・編集履歴も削除されます。
+	// 成功した場合、物理削除したメッセージの数とnilを返します。
+	// channelIDにuuid.Nilを指定した場合、ErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	PurgeDeletedChannelMessages(channelID uuid.UUID, createdBefore, deletedBefore time.Time, limit int) (int, error)
+}
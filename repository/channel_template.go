@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"github.com/gofrs/uuid"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/utils/optional"
+)
+
+// UpdateChannelTemplateArgs チャンネルテンプレート更新引数
+type UpdateChannelTemplateArgs struct {
+	Name        optional.String
+	Description optional.String
+	Channels    model.ChannelTemplateNodes
+}
+
+// ChannelTemplateRepository チャンネルテンプレートリポジトリ
+type ChannelTemplateRepository interface {
+	// CreateChannelTemplate チャンネルテンプレートを作成します
+	//
+	// 成功した場合、チャンネルテンプレートとnilを返します。
+	// creatorIDにuuid.Nilを指定した場合、ErrNilIDを返します。
+	// 既に同名のテンプレートが存在する場合、ErrAlreadyExistsを返します。
+	// DBによるエラーを返すことがあります。
+	CreateChannelTemplate(name, description string, channels model.ChannelTemplateNodes, creatorID uuid.UUID) (*model.ChannelTemplate, error)
+	// UpdateChannelTemplate 指定したチャンネルテンプレートを更新します
+	//
+	// 成功した場合、nilを返します。
+	// 存在しないテンプレートの場合、ErrNotFoundを返します。
+	// idにuuid.Nilを指定した場合、ErrNilIDを返します。
+	// 変更後の名前のテンプレートが既に存在する場合、ErrAlreadyExistsを返します。
+	// DBによるエラーを返すことがあります。
+	UpdateChannelTemplate(id uuid.UUID, args UpdateChannelTemplateArgs) error
+	// GetChannelTemplate 指定したIDのチャンネルテンプレートを取得します
+	//
+	// 成功した場合、チャンネルテンプレートとnilを返します。
+	// 存在しなかった場合、ErrNotFoundを返します。
+	// DBによるエラーを返すことがあります。
+	GetChannelTemplate(id uuid.UUID) (*model.ChannelTemplate, error)
+	// GetChannelTemplates 全てのチャンネルテンプレートを取得します
+	//
+	// 成功した場合、名前順のチャンネルテンプレートの配列とnilを返します。
+	// DBによるエラーを返すことがあります。
+	GetChannelTemplates() ([]*model.ChannelTemplate, error)
+	// DeleteChannelTemplate 指定したIDのチャンネルテンプレートを削除します
+	//
+	// 成功した場合、nilを返します。
+	// 既に存在しない場合、ErrNotFoundを返します。
+	// idにuuid.Nilを指定した場合、ErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	DeleteChannelTemplate(id uuid.UUID) error
+}
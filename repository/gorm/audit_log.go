@@ -0,0 +1,75 @@
+package gorm
+
+import (
+	"github.com/gofrs/uuid"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+)
+
+// CreateAuditLog implements AuditLogRepository interface.
+func (repo *Repository) CreateAuditLog(log *model.AuditLog) error {
+	log.ID = uuid.Must(uuid.NewV4())
+	if log.Before == nil {
+		log.Before = model.JSON{}
+	}
+	if log.After == nil {
+		log.After = model.JSON{}
+	}
+	return repo.db.Create(log).Error
+}
+
+// GetAuditLogs implements AuditLogRepository interface.
+func (repo *Repository) GetAuditLogs(query repository.AuditLogQuery) (logs []*model.AuditLog, more bool, err error) {
+	logs = make([]*model.AuditLog, 0)
+
+	tx := repo.db
+	if query.Asc {
+		tx = tx.Order("created_at").Order("id")
+	} else {
+		tx = tx.Order("created_at DESC").Order("id DESC")
+	}
+
+	if query.ActorID.Valid {
+		tx = tx.Where("actor_id = ?", query.ActorID.UUID)
+	}
+	if len(query.Action) > 0 {
+		tx = tx.Where("action = ?", query.Action)
+	}
+	if len(query.TargetType) > 0 {
+		tx = tx.Where("target_type = ?", query.TargetType)
+	}
+	if len(query.TargetID) > 0 {
+		tx = tx.Where("target_id = ?", query.TargetID)
+	}
+
+	if query.Inclusive {
+		if query.Since.Valid {
+			tx = tx.Where("created_at >= ?", query.Since.Time)
+		}
+		if query.Until.Valid {
+			tx = tx.Where("created_at <= ?", query.Until.Time)
+		}
+	} else {
+		if query.Since.Valid {
+			tx = tx.Where("created_at > ?", query.Since.Time)
+		}
+		if query.Until.Valid {
+			tx = tx.Where("created_at < ?", query.Until.Time)
+		}
+	}
+
+	if query.Offset > 0 {
+		tx = tx.Offset(query.Offset)
+	}
+
+	if query.Limit > 0 {
+		err = tx.Limit(query.Limit + 1).Find(&logs).Error
+		if len(logs) > query.Limit {
+			return logs[:len(logs)-1], true, err
+		}
+	} else {
+		err = tx.Find(&logs).Error
+	}
+	return logs, false, err
+}
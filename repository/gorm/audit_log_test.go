@@ -0,0 +1,77 @@
+package gorm
+
+import (
+	"testing"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/utils/optional"
+	"github.com/traPtitech/traQ/utils/random"
+)
+
+func TestGormRepository_CreateAuditLog(t *testing.T) {
+	t.Parallel()
+	repo, assert, _, user := setupWithUser(t, common)
+
+	log := &model.AuditLog{
+		ActorID:    user.GetID(),
+		Action:     model.AuditActionUserEdit,
+		TargetType: model.AuditTargetUser,
+		TargetID:   user.GetID().String(),
+		Before:     model.JSON{"displayName": "a"},
+		After:      model.JSON{"displayName": "b"},
+	}
+	if assert.NoError(repo.CreateAuditLog(log)) {
+		assert.NotEqual(log.ID.String(), "00000000-0000-0000-0000-000000000000")
+		assert.False(log.CreatedAt.IsZero())
+	}
+}
+
+func TestGormRepository_GetAuditLogs(t *testing.T) {
+	t.Parallel()
+	repo, _, require, user := setupWithUser(t, common)
+
+	targetID := random.AlphaNumeric(20)
+	for _, action := range []model.AuditAction{model.AuditActionStampDelete, model.AuditActionStampDelete, model.AuditActionBotReissue} {
+		require.NoError(repo.CreateAuditLog(&model.AuditLog{
+			ActorID:    user.GetID(),
+			Action:     action,
+			TargetType: model.AuditTargetStamp,
+			TargetID:   targetID,
+		}))
+	}
+
+	t.Run("filter by target", func(t *testing.T) {
+		t.Parallel()
+		assert, require := assertAndRequire(t)
+
+		logs, more, err := repo.GetAuditLogs(repository.AuditLogQuery{TargetType: model.AuditTargetStamp, TargetID: targetID})
+		require.NoError(err)
+		assert.False(more)
+		assert.Len(logs, 3)
+	})
+
+	t.Run("filter by action", func(t *testing.T) {
+		t.Parallel()
+		assert, require := assertAndRequire(t)
+
+		logs, more, err := repo.GetAuditLogs(repository.AuditLogQuery{ActorID: optional.UUIDFrom(user.GetID()), Action: model.AuditActionStampDelete, TargetID: targetID})
+		require.NoError(err)
+		assert.False(more)
+		if assert.Len(logs, 2) {
+			assert.Equal(model.JSON{}, logs[0].Before)
+		}
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		t.Parallel()
+		assert, require := assertAndRequire(t)
+
+		logs, more, err := repo.GetAuditLogs(repository.AuditLogQuery{TargetID: targetID, Limit: 2, Asc: true})
+		require.NoError(err)
+		assert.True(more)
+		if assert.Len(logs, 2) {
+			assert.False(logs[0].CreatedAt.After(logs[1].CreatedAt))
+		}
+	})
+}
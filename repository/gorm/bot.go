@@ -340,8 +340,8 @@ func (repo *Repository) DeleteBot(id uuid.UUID) error {
 	if id == uuid.Nil {
 		return repository.ErrNilID
 	}
+	var b model.Bot
 	err := repo.db.Transaction(func(tx *gorm.DB) error {
-		var b model.Bot
 		if err := tx.First(&b, &model.Bot{ID: id}).Error; err != nil {
 			return convertError(err)
 		}
@@ -370,7 +370,8 @@ func (repo *Repository) DeleteBot(id uuid.UUID) error {
 	repo.hub.Publish(hub.Message{
 		Name: event.BotDeleted,
 		Fields: hub.Fields{
-			"bot_id": id,
+			"bot_id":      id,
+			"bot_user_id": b.BotUserID,
 		},
 	})
 	return nil
@@ -447,6 +448,26 @@ func (repo *Repository) GetBotEventLogs(botID uuid.UUID, limit, offset int) ([]*
 		Error
 }
 
+// GetBotEventLogsAfter implements BotRepository interface.
+func (repo *Repository) GetBotEventLogsAfter(botID, requestID uuid.UUID, limit int) ([]*model.BotEventLog, error) {
+	if botID == uuid.Nil || requestID == uuid.Nil {
+		return nil, repository.ErrNotFound
+	}
+	var base model.BotEventLog
+	if err := repo.db.Where(&model.BotEventLog{BotID: botID, RequestID: requestID}).First(&base).Error; err != nil {
+		return nil, convertError(err)
+	}
+
+	// 同一時刻のイベントを取りこぼさないよう(date_time, request_id)の順でページングする
+	logs := make([]*model.BotEventLog, 0)
+	return logs, repo.db.
+		Where("bot_id = ? AND (date_time > ? OR (date_time = ? AND request_id > ?))", botID, base.DateTime, base.DateTime, base.RequestID).
+		Order("date_time, request_id").
+		Scopes(gormutil.LimitAndOffset(limit, 0)).
+		Find(&logs).
+		Error
+}
+
 // PurgeBotEventLogs implements BotRepository interface.
 func (repo *Repository) PurgeBotEventLogs(before time.Time) error {
 	return repo.db.Delete(&model.BotEventLog{}, "date_time < ?", before).Error
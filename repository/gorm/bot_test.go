@@ -0,0 +1,70 @@
+package gorm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+)
+
+func TestRepositoryImpl_GetBotEventLogsAfter(t *testing.T) {
+	t.Parallel()
+	repo, assert, require := setup(t, common)
+
+	botID := uuid.Must(uuid.NewV4())
+	base := time.Now().Truncate(time.Microsecond)
+	// 2件目以降は同一時刻
+	ids := []uuid.UUID{
+		uuid.Must(uuid.FromString("00000000-0000-4000-8000-000000000001")),
+		uuid.Must(uuid.FromString("00000000-0000-4000-8000-000000000002")),
+		uuid.Must(uuid.FromString("00000000-0000-4000-8000-000000000003")),
+		uuid.Must(uuid.FromString("00000000-0000-4000-8000-000000000004")),
+	}
+	times := []time.Time{base, base.Add(time.Second), base.Add(time.Second), base.Add(time.Second)}
+	for i, id := range ids {
+		require.NoError(repo.WriteBotEventLog(&model.BotEventLog{
+			RequestID: id,
+			BotID:     botID,
+			Event:     "PING",
+			Result:    "ok",
+			DateTime:  times[i],
+		}))
+	}
+
+	requestIDs := func(logs []*model.BotEventLog) []uuid.UUID {
+		res := make([]uuid.UUID, len(logs))
+		for i, l := range logs {
+			res[i] = l.RequestID
+		}
+		return res
+	}
+
+	logs, err := repo.GetBotEventLogsAfter(botID, ids[0], 10)
+	if assert.NoError(err) {
+		assert.Equal(ids[1:], requestIDs(logs))
+	}
+
+	// 同一時刻のイベントの途中から
+	logs, err = repo.GetBotEventLogsAfter(botID, ids[1], 10)
+	if assert.NoError(err) {
+		assert.Equal(ids[2:], requestIDs(logs))
+	}
+
+	logs, err = repo.GetBotEventLogsAfter(botID, ids[2], 1)
+	if assert.NoError(err) {
+		assert.Equal(ids[3:], requestIDs(logs))
+	}
+
+	logs, err = repo.GetBotEventLogsAfter(botID, ids[3], 10)
+	if assert.NoError(err) {
+		assert.Empty(logs)
+	}
+
+	_, err = repo.GetBotEventLogsAfter(botID, uuid.Must(uuid.NewV4()), 10)
+	assert.EqualError(err, repository.ErrNotFound.Error())
+	_, err = repo.GetBotEventLogsAfter(uuid.Nil, ids[0], 10)
+	assert.EqualError(err, repository.ErrNotFound.Error())
+}
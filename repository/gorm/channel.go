@@ -2,11 +2,15 @@ package gorm
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
 	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/leandro-lugaresi/hub"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/traPtitech/traQ/event"
 	"github.com/traPtitech/traQ/model"
@@ -15,7 +19,22 @@ import (
 	"github.com/traPtitech/traQ/utils/set"
 )
 
-var dmChannelRootUUID = uuid.Must(uuid.FromString(model.DirectMessageChannelRootID))
+var (
+	dmChannelRootUUID      = uuid.Must(uuid.FromString(model.DirectMessageChannelRootID))
+	privateChannelRootUUID = uuid.Must(uuid.FromString(model.PrivateChannelRootID))
+	groupDMChannelRootUUID = uuid.Must(uuid.FromString(model.GroupDirectMessageChannelRootID))
+)
+
+// groupDMMembersKey グループDMのメンバー集合を一意に表すキーを返します
+func groupDMMembersKey(members set.UUID) string {
+	ids := members.StringArray()
+	sort.Strings(ids)
+	h := sha256.New()
+	for _, id := range ids {
+		h.Write([]byte(id))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
 
 // CreateChannel implements ChannelRepository interface.
 func (repo *Repository) CreateChannel(ch model.Channel, privateMembers set.UUID, dm bool) (*model.Channel, error) {
@@ -24,6 +43,11 @@ func (repo *Repository) CreateChannel(ch model.Channel, privateMembers set.UUID,
 	ch.ID = uuid.Must(uuid.NewV4())
 	ch.IsPublic = true
 	ch.DeletedAt = gorm.DeletedAt{}
+	ch.NameScope = ""
+	if ch.ParentID == privateChannelRootUUID {
+		// プライベートチャンネル名は他のプライベートチャンネルと重複可能
+		ch.NameScope = ch.ID.String()
+	}
 
 	if len(privateMembers) > 0 {
 		ch.IsPublic = false
@@ -36,7 +60,16 @@ func (repo *Repository) CreateChannel(ch model.Channel, privateMembers set.UUID,
 		}
 	}
 
-	if dm {
+	if dm && len(privateMembers) > 2 {
+		ch.ParentID = groupDMChannelRootUUID
+		ch.IsPublic = false
+		ch.IsForced = false
+
+		arr = append(arr, &model.GroupDMChannelMapping{
+			ChannelID:  ch.ID,
+			MembersKey: groupDMMembersKey(privateMembers),
+		})
+	} else if dm {
 		ch.ParentID = dmChannelRootUUID
 		ch.IsPublic = false
 		ch.IsForced = false
@@ -62,7 +95,7 @@ func (repo *Repository) CreateChannel(ch model.Channel, privateMembers set.UUID,
 			m.User1 = users[0]
 			m.User2 = users[1]
 		} else {
-			return nil, repository.ArgError("privateMembers", "length must be 1 or 2")
+			return nil, repository.ArgError("privateMembers", "length must be greater than 0")
 		}
 		arr = append(arr, m)
 	}
@@ -76,6 +109,9 @@ func (repo *Repository) CreateChannel(ch model.Channel, privateMembers set.UUID,
 		return nil
 	})
 	if err != nil {
+		if gormutil.IsMySQLDuplicatedRecordErr(err) {
+			return nil, repository.ErrAlreadyExists
+		}
 		return nil, err
 	}
 	repo.hub.Publish(hub.Message{
@@ -89,6 +125,103 @@ func (repo *Repository) CreateChannel(ch model.Channel, privateMembers set.UUID,
 	return &ch, nil
 }
 
+// CreatePublicChannels implements ChannelRepository interface.
+func (repo *Repository) CreatePublicChannels(args []*repository.CreatePublicChannelArgs) ([]*model.Channel, error) {
+	chs := make([]*model.Channel, len(args))
+	users := set.UUID{}
+	bots := set.UUID{}
+	for i, arg := range args {
+		if arg.Channel.ID == uuid.Nil {
+			return nil, repository.ErrNilID
+		}
+		ch := arg.Channel
+		ch.IsPublic = true
+		ch.DeletedAt = gorm.DeletedAt{}
+		chs[i] = &ch
+		users.Plus(arg.Subscribers)
+		bots.Plus(arg.Bots)
+	}
+
+	subscribed := set.UUID{}
+	joined := make(map[uuid.UUID][]uuid.UUID)
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		// 存在しないユーザー・BOTを除外
+		var existUsers, existBots []uuid.UUID
+		if len(users) > 0 {
+			if err := tx.Model(&model.User{}).Where("id IN ?", users.Array()).Pluck("id", &existUsers).Error; err != nil {
+				return err
+			}
+		}
+		if len(bots) > 0 {
+			if err := tx.Model(&model.Bot{}).Where("id IN ?", bots.Array()).Pluck("id", &existBots).Error; err != nil {
+				return err
+			}
+		}
+		users = set.UUIDSetFromArray(existUsers)
+		bots = set.UUIDSetFromArray(existBots)
+
+		for i, ch := range chs {
+			if err := tx.Create(ch).Error; err != nil {
+				return err
+			}
+			for uid := range args[i].Subscribers {
+				if !users.Contains(uid) {
+					continue
+				}
+				if err := tx.Create(&model.UserSubscribeChannel{UserID: uid, ChannelID: ch.ID, Mark: true, Notify: true}).Error; err != nil {
+					return err
+				}
+				subscribed.Add(ch.ID)
+			}
+			for bid := range args[i].Bots {
+				if !bots.Contains(bid) {
+					continue
+				}
+				if err := tx.Create(&model.BotJoinChannel{BotID: bid, ChannelID: ch.ID}).Error; err != nil {
+					return err
+				}
+				joined[ch.ID] = append(joined[ch.ID], bid)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if gormutil.IsMySQLDuplicatedRecordErr(err) {
+			return nil, repository.ErrAlreadyExists
+		}
+		return nil, err
+	}
+
+	for _, ch := range chs {
+		repo.hub.Publish(hub.Message{
+			Name: event.ChannelCreated,
+			Fields: hub.Fields{
+				"channel_id": ch.ID,
+				"channel":    ch,
+				"private":    false,
+			},
+		})
+		if subscribed.Contains(ch.ID) {
+			repo.hub.Publish(hub.Message{
+				Name: event.ChannelSubscribersChanged,
+				Fields: hub.Fields{
+					"channel_id": ch.ID,
+				},
+			})
+		}
+		for _, bid := range joined[ch.ID] {
+			repo.hub.Publish(hub.Message{
+				Name: event.BotJoined,
+				Fields: hub.Fields{
+					"bot_id":     bid,
+					"channel_id": ch.ID,
+				},
+			})
+		}
+	}
+	return chs, nil
+}
+
 // UpdateChannel implements ChannelRepository interface.
 func (repo *Repository) UpdateChannel(channelID uuid.UUID, args repository.UpdateChannelArgs) (*model.Channel, error) {
 	if channelID == uuid.Nil {
@@ -127,6 +260,9 @@ func (repo *Repository) UpdateChannel(channelID uuid.UUID, args repository.Updat
 		return nil
 	})
 	if err != nil {
+		if gormutil.IsMySQLDuplicatedRecordErr(err) {
+			return nil, repository.ErrAlreadyExists
+		}
 		return nil, err
 	}
 
@@ -189,6 +325,166 @@ func (repo *Repository) ArchiveChannels(ids []uuid.UUID) ([]*model.Channel, erro
 	return changed, nil
 }
 
+// MergeChannel implements ChannelRepository interface.
+func (repo *Repository) MergeChannel(srcID, dstID, updaterID uuid.UUID) (children []*model.Channel, movedMessages int64, err error) {
+	if srcID == uuid.Nil || dstID == uuid.Nil {
+		return nil, 0, repository.ErrNilID
+	}
+
+	children = make([]*model.Channel, 0)
+	err = repo.db.Transaction(func(tx *gorm.DB) error {
+		// 子チャンネル
+		var childIDs []uuid.UUID
+		if err := tx.Model(&model.Channel{}).Where("parent_id = ?", srcID).Pluck("id", &childIDs).Error; err != nil {
+			return err
+		}
+		if len(childIDs) > 0 {
+			if err := tx.Model(&model.Channel{}).
+				Where("id IN ?", childIDs).
+				Updates(map[string]interface{}{"parent_id": dstID, "updater_id": updaterID}).
+				Error; err != nil {
+				return err
+			}
+			if err := tx.Where("id IN ?", childIDs).Find(&children).Error; err != nil {
+				return err
+			}
+		}
+
+		// メッセージ (ピン留め・未読・スタンプはメッセージに紐づいているため一緒に移動する)
+		// メッセージ自体は編集されていないため、updated_atは変更しない
+		if err := tx.Unscoped().Model(&model.Message{}).Where("channel_id = ?", srcID).Count(&movedMessages).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&model.Message{}).Where("channel_id = ?", srcID).UpdateColumn("channel_id", dstID).Error; err != nil {
+			return err
+		}
+
+		// 最新メッセージ
+		var latest model.ChannelLatestMessage
+		err := tx.Where("channel_id IN ?", []uuid.UUID{srcID, dstID}).Order("date_time DESC").First(&latest).Error
+		if err == nil {
+			latest.ChannelID = dstID
+			if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&latest).Error; err != nil {
+				return err
+			}
+		} else if err != gorm.ErrRecordNotFound {
+			return err
+		}
+		if err := tx.Delete(&model.ChannelLatestMessage{}, &model.ChannelLatestMessage{ChannelID: srcID}).Error; err != nil {
+			return err
+		}
+
+		// ファイル・Webhook・ホームチャンネル
+		if err := tx.Model(&model.FileMeta{}).Where("channel_id = ?", srcID).Update("channel_id", dstID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.WebhookBot{}).Where("channel_id = ?", srcID).Update("channel_id", dstID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.UserProfile{}).Where("home_channel = ?", srcID).Update("home_channel", dstID).Error; err != nil {
+			return err
+		}
+
+		// 購読 (統合先で既に購読している場合は購読レベルの高い方を採用する)
+		if err := tx.Exec("INSERT INTO users_subscribe_channels (user_id, channel_id, mark, notify) SELECT user_id, ?, mark, notify FROM users_subscribe_channels WHERE channel_id = ? ON DUPLICATE KEY UPDATE mark = mark OR VALUES(mark), notify = notify OR VALUES(notify)", dstID, srcID).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&model.UserSubscribeChannel{}, &model.UserSubscribeChannel{ChannelID: srcID}).Error; err != nil {
+			return err
+		}
+
+		// スター・BOTの参加
+		if err := tx.Exec("INSERT IGNORE INTO stars (user_id, channel_id) SELECT user_id, ? FROM stars WHERE channel_id = ?", dstID, srcID).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&model.Star{}, &model.Star{ChannelID: srcID}).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("INSERT IGNORE INTO bot_join_channels (channel_id, bot_id) SELECT ?, bot_id FROM bot_join_channels WHERE channel_id = ?", dstID, srcID).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&model.BotJoinChannel{}, &model.BotJoinChannel{ChannelID: srcID}).Error; err != nil {
+			return err
+		}
+
+		// チャンネルロール (統合先で既にロールが割り当てられているユーザーは統合先のロールを優先する)
+		if err := tx.Exec("INSERT IGNORE INTO channel_roles (channel_id, user_id, role, created_at) SELECT ?, user_id, role, created_at FROM channel_roles WHERE channel_id = ?", dstID, srcID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.ChannelRole{}, &model.ChannelRole{ChannelID: srcID}).Error
+	})
+	if err != nil {
+		if gormutil.IsMySQLDuplicatedRecordErr(err) {
+			return nil, 0, repository.ErrAlreadyExists
+		}
+		return nil, 0, err
+	}
+
+	for _, ch := range children {
+		repo.hub.Publish(hub.Message{
+			Name: event.ChannelUpdated,
+			Fields: hub.Fields{
+				"channel_id": ch.ID,
+				"private":    !ch.IsPublic,
+			},
+		})
+	}
+	repo.hub.Publish(hub.Message{
+		Name: event.ChannelMerged,
+		Fields: hub.Fields{
+			"channel_id": srcID,
+			"target_id":  dstID,
+		},
+	})
+	return children, movedMessages, nil
+}
+
+// DeleteChannel implements ChannelRepository interface.
+func (repo *Repository) DeleteChannel(channelID uuid.UUID) error {
+	if channelID == uuid.Nil {
+		return repository.ErrNilID
+	}
+
+	var ch model.Channel
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&ch, &model.Channel{ID: channelID}).Error; err != nil {
+			return convertError(err)
+		}
+
+		if exists, err := gormutil.RecordExists(tx, &model.Message{ChannelID: channelID}); err != nil {
+			return err
+		} else if exists {
+			return repository.ErrForbidden
+		}
+		if exists, err := gormutil.RecordExists(tx, &model.WebhookBot{ChannelID: channelID}); err != nil {
+			return err
+		} else if exists {
+			return repository.ErrForbidden
+		}
+
+		// ホームチャンネル設定はプロフィールごと消えないように外す
+		if err := tx.Model(&model.UserProfile{}).Where("home_channel = ?", channelID).Update("home_channel", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&model.ChannelLatestMessage{}, &model.ChannelLatestMessage{ChannelID: channelID}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&ch).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	repo.hub.Publish(hub.Message{
+		Name: event.ChannelDeleted,
+		Fields: hub.Fields{
+			"channel_id": channelID,
+			"private":    !ch.IsPublic,
+		},
+	})
+	return nil
+}
+
 // GetChannel implements ChannelRepository interface.
 func (repo *Repository) GetChannel(channelID uuid.UUID) (*model.Channel, error) {
 	if channelID == uuid.Nil {
@@ -243,6 +539,35 @@ func (repo *Repository) GetDirectMessageChannelMapping(userID uuid.UUID) (mappin
 		Error
 }
 
+// GetGroupDirectMessageChannel implements ChannelRepository interface.
+func (repo *Repository) GetGroupDirectMessageChannel(members set.UUID) (*model.Channel, error) {
+	if len(members) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	var ch model.Channel
+	err := repo.db.
+		Where("id = (SELECT channel_id FROM group_dm_channel_mappings WHERE members_key = ?)", groupDMMembersKey(members)).
+		First(&ch).
+		Error
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return &ch, nil
+}
+
+// GetGroupDirectMessageChannelsByUser implements ChannelRepository interface.
+func (repo *Repository) GetGroupDirectMessageChannelsByUser(userID uuid.UUID) (channels []*model.Channel, err error) {
+	channels = make([]*model.Channel, 0)
+	if userID == uuid.Nil {
+		return channels, nil
+	}
+	return channels, repo.db.
+		Where("parent_id = ? AND id IN (SELECT channel_id FROM users_private_channels WHERE user_id = ?)", groupDMChannelRootUUID, userID).
+		Order("created_at").
+		Find(&channels).
+		Error
+}
+
 // GetPrivateChannelMemberIDs implements ChannelRepository interface.
 func (repo *Repository) GetPrivateChannelMemberIDs(channelID uuid.UUID) (users []uuid.UUID, err error) {
 	users = make([]uuid.UUID, 0)
@@ -256,6 +581,93 @@ func (repo *Repository) GetPrivateChannelMemberIDs(channelID uuid.UUID) (users [
 		Error
 }
 
+// AddPrivateChannelMembers implements ChannelRepository interface.
+func (repo *Repository) AddPrivateChannelMembers(channelID uuid.UUID, userIDs set.UUID) (added []uuid.UUID, err error) {
+	if channelID == uuid.Nil {
+		return nil, repository.ErrNilID
+	}
+
+	added = make([]uuid.UUID, 0)
+	err = repo.db.Transaction(func(tx *gorm.DB) error {
+		var current []uuid.UUID
+		if err := tx.
+			Model(&model.UsersPrivateChannel{}).
+			Where(&model.UsersPrivateChannel{ChannelID: channelID}).
+			Pluck("user_id", &current).
+			Error; err != nil {
+			return err
+		}
+		members := set.UUIDSetFromArray(current)
+
+		for uid := range userIDs {
+			if members.Contains(uid) {
+				continue // 既にメンバー
+			}
+			if err := tx.Create(&model.UsersPrivateChannel{UserID: uid, ChannelID: channelID}).Error; err != nil {
+				if gormutil.IsMySQLForeignKeyConstraintFailsError(err) {
+					continue // 存在しないユーザーは無視
+				}
+				return err
+			}
+			added = append(added, uid)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(added) > 0 {
+		repo.hub.Publish(hub.Message{
+			Name: event.ChannelMembersChanged,
+			Fields: hub.Fields{
+				"channel_id": channelID,
+				"added":      added,
+				"removed":    []uuid.UUID{},
+			},
+		})
+	}
+	return added, nil
+}
+
+// RemovePrivateChannelMember implements ChannelRepository interface.
+func (repo *Repository) RemovePrivateChannelMember(channelID, userID uuid.UUID) error {
+	if channelID == uuid.Nil || userID == uuid.Nil {
+		return repository.ErrNilID
+	}
+
+	result := repo.db.Delete(&model.UsersPrivateChannel{}, &model.UsersPrivateChannel{UserID: userID, ChannelID: channelID})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+
+	repo.hub.Publish(hub.Message{
+		Name: event.ChannelMembersChanged,
+		Fields: hub.Fields{
+			"channel_id": channelID,
+			"added":      []uuid.UUID{},
+			"removed":    []uuid.UUID{userID},
+		},
+	})
+	return nil
+}
+
+// GetPrivateChannelsByUser implements ChannelRepository interface.
+func (repo *Repository) GetPrivateChannelsByUser(userID uuid.UUID) (channels []*model.Channel, err error) {
+	channels = make([]*model.Channel, 0)
+	if userID == uuid.Nil {
+		return channels, nil
+	}
+	return channels, repo.db.
+		Where("parent_id = ? AND id IN (SELECT channel_id FROM users_private_channels WHERE user_id = ?)", privateChannelRootUUID, userID).
+		Order("created_at").
+		Find(&channels).
+		Error
+}
+
 // ChangeChannelSubscription implements ChannelRepository interface.
 func (repo *Repository) ChangeChannelSubscription(channelID uuid.UUID, args repository.ChangeChannelSubscriptionArgs) (on []uuid.UUID, off []uuid.UUID, err error) {
 	if channelID == uuid.Nil {
@@ -496,3 +908,55 @@ func (repo *Repository) GetChannelStats(channelID uuid.UUID) (*repository.Channe
 	stats.DateTime = time.Now()
 	return &stats, nil
 }
+
+// SetChannelRole implements ChannelRepository interface.
+func (repo *Repository) SetChannelRole(channelID, userID uuid.UUID, role string) error {
+	if channelID == uuid.Nil || userID == uuid.Nil {
+		return repository.ErrNilID
+	}
+
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		var r model.ChannelRole
+		if err := tx.First(&r, &model.ChannelRole{ChannelID: channelID, UserID: userID}).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return tx.Create(&model.ChannelRole{ChannelID: channelID, UserID: userID, Role: role}).Error
+			}
+			return err
+		}
+		return tx.Model(&r).Update("role", role).Error
+	})
+}
+
+// DeleteChannelRole implements ChannelRepository interface.
+func (repo *Repository) DeleteChannelRole(channelID, userID uuid.UUID) error {
+	if channelID == uuid.Nil || userID == uuid.Nil {
+		return repository.ErrNilID
+	}
+
+	result := repo.db.Where(&model.ChannelRole{ChannelID: channelID, UserID: userID}).Delete(&model.ChannelRole{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// GetChannelRoles implements ChannelRepository interface.
+func (repo *Repository) GetChannelRoles(channelID uuid.UUID) ([]*model.ChannelRole, error) {
+	roles := make([]*model.ChannelRole, 0)
+	if channelID == uuid.Nil {
+		return roles, nil
+	}
+	return roles, repo.db.Where(&model.ChannelRole{ChannelID: channelID}).Order("created_at").Find(&roles).Error
+}
+
+// GetUserChannelRoles implements ChannelRepository interface.
+func (repo *Repository) GetUserChannelRoles(userID uuid.UUID, channelIDs []uuid.UUID) ([]*model.ChannelRole, error) {
+	roles := make([]*model.ChannelRole, 0)
+	if userID == uuid.Nil || len(channelIDs) == 0 {
+		return roles, nil
+	}
+	return roles, repo.db.Where("user_id = ? AND channel_id IN ?", userID, channelIDs).Find(&roles).Error
+}
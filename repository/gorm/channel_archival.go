@@ -0,0 +1,75 @@
+package gorm
+
+import (
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/utils/gormutil"
+	"github.com/traPtitech/traQ/utils/optional"
+)
+
+// GetChannelArchivalState implements ChannelArchivalRepository interface.
+func (repo *Repository) GetChannelArchivalState(channelID uuid.UUID) (*model.ChannelArchivalState, error) {
+	if channelID == uuid.Nil {
+		return nil, repository.ErrNotFound
+	}
+	var s model.ChannelArchivalState
+	if err := repo.db.Take(&s, &model.ChannelArchivalState{ChannelID: channelID}).Error; err != nil {
+		return nil, convertError(err)
+	}
+	return &s, nil
+}
+
+// GetChannelArchivalStates implements ChannelArchivalRepository interface.
+func (repo *Repository) GetChannelArchivalStates() ([]*model.ChannelArchivalState, error) {
+	states := make([]*model.ChannelArchivalState, 0)
+	return states, repo.db.Find(&states).Error
+}
+
+// SetChannelArchivalExempt implements ChannelArchivalRepository interface.
+func (repo *Repository) SetChannelArchivalExempt(channelID uuid.UUID, exempt bool) error {
+	if channelID == uuid.Nil {
+		return repository.ErrNilID
+	}
+	changes := map[string]interface{}{
+		"exempt":     exempt,
+		"updated_at": gorm.Expr("now()"),
+	}
+	s := &model.ChannelArchivalState{ChannelID: channelID, Exempt: exempt}
+	if exempt {
+		changes["warned_at"] = nil
+	}
+	return repo.upsertChannelArchivalState(s, changes)
+}
+
+// SetChannelArchivalWarnedAt implements ChannelArchivalRepository interface.
+func (repo *Repository) SetChannelArchivalWarnedAt(channelID uuid.UUID, warnedAt optional.Time) error {
+	if channelID == uuid.Nil {
+		return repository.ErrNilID
+	}
+	changes := map[string]interface{}{
+		"warned_at":  warnedAt,
+		"updated_at": gorm.Expr("now()"),
+	}
+	return repo.upsertChannelArchivalState(&model.ChannelArchivalState{ChannelID: channelID, WarnedAt: warnedAt}, changes)
+}
+
+func (repo *Repository) upsertChannelArchivalState(s *model.ChannelArchivalState, changes map[string]interface{}) error {
+	err := repo.db.
+		Clauses(clause.OnConflict{DoUpdates: clause.Assignments(changes)}).
+		Create(s).
+		Error
+	if gormutil.IsMySQLForeignKeyConstraintFailsError(err) {
+		return repository.ErrNotFound
+	}
+	return err
+}
+
+// GetAllChannelLatestMessages implements ChannelArchivalRepository interface.
+func (repo *Repository) GetAllChannelLatestMessages() ([]*model.ChannelLatestMessage, error) {
+	latests := make([]*model.ChannelLatestMessage, 0)
+	return latests, repo.db.Find(&latests).Error
+}
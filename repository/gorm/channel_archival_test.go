@@ -0,0 +1,124 @@
+package gorm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/utils/optional"
+	random2 "github.com/traPtitech/traQ/utils/random"
+)
+
+func TestRepositoryImpl_SetChannelArchivalExempt(t *testing.T) {
+	t.Parallel()
+	repo, _, _ := setup(t, common2)
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.SetChannelArchivalExempt(uuid.Nil, true), repository.ErrNilID.Error())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.SetChannelArchivalExempt(uuid.Must(uuid.NewV4()), true), repository.ErrNotFound.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		ch := mustMakeChannel(t, repo, random2.AlphaNumeric(20))
+		if assert.NoError(repo.SetChannelArchivalWarnedAt(ch.ID, optional.TimeFrom(time.Now()))) {
+			assert.NoError(repo.SetChannelArchivalExempt(ch.ID, true))
+			s, err := repo.GetChannelArchivalState(ch.ID)
+			if assert.NoError(err) {
+				assert.True(s.Exempt)
+				assert.False(s.WarnedAt.Valid)
+			}
+		}
+
+		if assert.NoError(repo.SetChannelArchivalExempt(ch.ID, false)) {
+			s, err := repo.GetChannelArchivalState(ch.ID)
+			if assert.NoError(err) {
+				assert.False(s.Exempt)
+			}
+		}
+	})
+}
+
+func TestRepositoryImpl_SetChannelArchivalWarnedAt(t *testing.T) {
+	t.Parallel()
+	repo, _, _ := setup(t, common2)
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.SetChannelArchivalWarnedAt(uuid.Nil, optional.Time{}), repository.ErrNilID.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		ch := mustMakeChannel(t, repo, random2.AlphaNumeric(20))
+		now := time.Now().Truncate(time.Microsecond)
+		if assert.NoError(repo.SetChannelArchivalWarnedAt(ch.ID, optional.TimeFrom(now))) {
+			s, err := repo.GetChannelArchivalState(ch.ID)
+			if assert.NoError(err) {
+				assert.False(s.Exempt)
+				assert.WithinDuration(now, s.WarnedAt.Time, time.Millisecond)
+			}
+
+			states, err := repo.GetChannelArchivalStates()
+			if assert.NoError(err) {
+				found := false
+				for _, s := range states {
+					found = found || s.ChannelID == ch.ID
+				}
+				assert.True(found)
+			}
+		}
+
+		if assert.NoError(repo.SetChannelArchivalWarnedAt(ch.ID, optional.Time{})) {
+			s, err := repo.GetChannelArchivalState(ch.ID)
+			if assert.NoError(err) {
+				assert.False(s.WarnedAt.Valid)
+			}
+		}
+	})
+}
+
+func TestRepositoryImpl_GetChannelArchivalState(t *testing.T) {
+	t.Parallel()
+	repo, _, _ := setup(t, common2)
+
+	_, err := repo.GetChannelArchivalState(uuid.Nil)
+	assert.EqualError(t, err, repository.ErrNotFound.Error())
+
+	ch := mustMakeChannel(t, repo, random2.AlphaNumeric(20))
+	_, err = repo.GetChannelArchivalState(ch.ID)
+	assert.EqualError(t, err, repository.ErrNotFound.Error())
+}
+
+func TestRepositoryImpl_GetAllChannelLatestMessages(t *testing.T) {
+	t.Parallel()
+	repo, _, _, user, ch := setupWithUserAndChannel(t, common2)
+
+	m := mustMakeMessage(t, repo, user.GetID(), ch.ID)
+	latests, err := repo.GetAllChannelLatestMessages()
+	if assert.NoError(t, err) {
+		found := false
+		for _, l := range latests {
+			if l.ChannelID == ch.ID {
+				found = true
+				assert.Equal(t, m.ID, l.MessageID)
+			}
+		}
+		assert.True(t, found)
+	}
+}
@@ -0,0 +1,178 @@
+package gorm
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/leandro-lugaresi/hub"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/traPtitech/traQ/event"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/utils/gormutil"
+)
+
+// SetChannelRetentionPolicy implements ChannelRetentionRepository interface.
+func (repo *Repository) SetChannelRetentionPolicy(channelID uuid.UUID, args repository.SetChannelRetentionPolicyArgs) (*model.ChannelRetentionPolicy, error) {
+	if channelID == uuid.Nil || args.UpdaterID == uuid.Nil {
+		return nil, repository.ErrNilID
+	}
+	p := &model.ChannelRetentionPolicy{
+		ChannelID:     channelID,
+		RetentionDays: args.RetentionDays,
+		Purge:         args.Purge,
+		KeepPinned:    args.KeepPinned,
+		UpdaterID:     args.UpdaterID,
+	}
+	err := repo.db.
+		Clauses(clause.OnConflict{
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"retention_days": args.RetentionDays,
+				"purge":          args.Purge,
+				"keep_pinned":    args.KeepPinned,
+				"updater_id":     args.UpdaterID,
+				"updated_at":     gorm.Expr("now()"),
+			}),
+		}).
+		Create(p).
+		Error
+	if err != nil {
+		if gormutil.IsMySQLForeignKeyConstraintFailsError(err) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+	return repo.GetChannelRetentionPolicy(channelID)
+}
+
+// GetChannelRetentionPolicy implements ChannelRetentionRepository interface.
+func (repo *Repository) GetChannelRetentionPolicy(channelID uuid.UUID) (*model.ChannelRetentionPolicy, error) {
+	if channelID == uuid.Nil {
+		return nil, repository.ErrNotFound
+	}
+	var p model.ChannelRetentionPolicy
+	if err := repo.db.Take(&p, &model.ChannelRetentionPolicy{ChannelID: channelID}).Error; err != nil {
+		return nil, convertError(err)
+	}
+	return &p, nil
+}
+
+// GetChannelRetentionPolicies implements ChannelRetentionRepository interface.
+func (repo *Repository) GetChannelRetentionPolicies() ([]*model.ChannelRetentionPolicy, error) {
+	policies := make([]*model.ChannelRetentionPolicy, 0)
+	return policies, repo.db.Find(&policies).Error
+}
+
+// DeleteChannelRetentionPolicy implements ChannelRetentionRepository interface.
+func (repo *Repository) DeleteChannelRetentionPolicy(channelID uuid.UUID) error {
+	if channelID == uuid.Nil {
+		return repository.ErrNilID
+	}
+	result := repo.db.Delete(&model.ChannelRetentionPolicy{ChannelID: channelID})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// DeleteChannelMessagesBefore implements ChannelRetentionRepository interface.
+func (repo *Repository) DeleteChannelMessagesBefore(channelID uuid.UUID, before time.Time, keepPinned bool, limit int) (int, error) {
+	if channelID == uuid.Nil {
+		return 0, repository.ErrNilID
+	}
+
+	var (
+		messages []*model.Message
+		unreads  []*model.Unread
+	)
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		q := tx.Where("channel_id = ? AND created_at < ?", channelID, before)
+		if keepPinned {
+			q = q.Where("id NOT IN (?)", tx.Model(&model.Pin{}).Select("message_id"))
+		}
+		if err := q.Order("created_at").Limit(limit).Find(&messages).Error; err != nil {
+			return err
+		}
+		if len(messages) == 0 {
+			return nil
+		}
+
+		ids := make([]uuid.UUID, len(messages))
+		for i, m := range messages {
+			ids[i] = m.ID
+		}
+		if err := tx.Where("message_id IN ?", ids).Find(&unreads).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("id IN ?", ids).Delete(&model.Message{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("message_id IN ?", ids).Delete(&model.Unread{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("message_id IN ?", ids).Delete(&model.Pin{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("message_id IN ?", ids).Delete(&model.ClipFolderMessage{}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	unreadsByMessage := make(map[uuid.UUID][]*model.Unread, len(messages))
+	for _, u := range unreads {
+		unreadsByMessage[u.MessageID] = append(unreadsByMessage[u.MessageID], u)
+	}
+	for _, m := range messages {
+		repo.hub.Publish(hub.Message{
+			Name: event.MessageDeleted,
+			Fields: hub.Fields{
+				"message_id":      m.ID,
+				"message":         m,
+				"deleted_unreads": unreadsByMessage[m.ID],
+			},
+		})
+	}
+	return len(messages), nil
+}
+
+// PurgeDeletedChannelMessages implements ChannelRetentionRepository interface.
+func (repo *Repository) PurgeDeletedChannelMessages(channelID uuid.UUID, createdBefore, deletedBefore time.Time, limit int) (int, error) {
+	if channelID == uuid.Nil {
+		return 0, repository.ErrNilID
+	}
+
+	var ids []uuid.UUID
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Unscoped().
+			Model(&model.Message{}).
+			Where("channel_id = ? AND created_at < ? AND deleted_at IS NOT NULL AND deleted_at < ?", channelID, createdBefore, deletedBefore).
+			Limit(limit).
+			Pluck("id", &ids).
+			Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		if err := tx.Where("message_id IN ?", ids).Delete(&model.MessageStamp{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("message_id IN ?", ids).Delete(&model.ArchivedMessage{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("id IN ?", ids).Delete(&model.Message{}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
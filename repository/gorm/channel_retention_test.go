@@ -0,0 +1,156 @@
+package gorm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	random2 "github.com/traPtitech/traQ/utils/random"
+)
+
+func TestRepositoryImpl_SetChannelRetentionPolicy(t *testing.T) {
+	t.Parallel()
+	repo, _, _, user := setupWithUser(t, common2)
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := repo.SetChannelRetentionPolicy(uuid.Nil, repository.SetChannelRetentionPolicyArgs{UpdaterID: user.GetID()})
+		assert.EqualError(t, err, repository.ErrNilID.Error())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := repo.SetChannelRetentionPolicy(uuid.Must(uuid.NewV4()), repository.SetChannelRetentionPolicyArgs{UpdaterID: user.GetID()})
+		assert.EqualError(t, err, repository.ErrNotFound.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		ch := mustMakeChannel(t, repo, random2.AlphaNumeric(20))
+		p, err := repo.SetChannelRetentionPolicy(ch.ID, repository.SetChannelRetentionPolicyArgs{RetentionDays: 30, KeepPinned: true, UpdaterID: user.GetID()})
+		if assert.NoError(err) {
+			assert.Equal(ch.ID, p.ChannelID)
+			assert.Equal(30, p.RetentionDays)
+			assert.False(p.Purge)
+			assert.True(p.KeepPinned)
+		}
+
+		p, err = repo.SetChannelRetentionPolicy(ch.ID, repository.SetChannelRetentionPolicyArgs{RetentionDays: 0, Purge: true, UpdaterID: user.GetID()})
+		if assert.NoError(err) {
+			assert.Equal(0, p.RetentionDays)
+			assert.True(p.Purge)
+			assert.False(p.KeepPinned)
+		}
+
+		policies, err := repo.GetChannelRetentionPolicies()
+		if assert.NoError(err) {
+			found := false
+			for _, p := range policies {
+				found = found || p.ChannelID == ch.ID
+			}
+			assert.True(found)
+		}
+	})
+}
+
+func TestRepositoryImpl_DeleteChannelRetentionPolicy(t *testing.T) {
+	t.Parallel()
+	repo, _, _, user := setupWithUser(t, common2)
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.DeleteChannelRetentionPolicy(uuid.Nil), repository.ErrNilID.Error())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.DeleteChannelRetentionPolicy(uuid.Must(uuid.NewV4())), repository.ErrNotFound.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		ch := mustMakeChannel(t, repo, random2.AlphaNumeric(20))
+		_, err := repo.SetChannelRetentionPolicy(ch.ID, repository.SetChannelRetentionPolicyArgs{RetentionDays: 30, UpdaterID: user.GetID()})
+		if assert.NoError(err) {
+			assert.NoError(repo.DeleteChannelRetentionPolicy(ch.ID))
+			_, err := repo.GetChannelRetentionPolicy(ch.ID)
+			assert.EqualError(err, repository.ErrNotFound.Error())
+		}
+	})
+}
+
+func TestRepositoryImpl_DeleteChannelMessagesBefore(t *testing.T) {
+	t.Parallel()
+	repo, assert, _, user, ch := setupWithUserAndChannel(t, common2)
+
+	m1 := mustMakeMessage(t, repo, user.GetID(), ch.ID)
+	m2 := mustMakeMessage(t, repo, user.GetID(), ch.ID)
+	pinned := mustMakeMessage(t, repo, user.GetID(), ch.ID)
+	mustMakePin(t, repo, pinned.ID, user.GetID())
+	mustMakeMessageUnread(t, repo, user.GetID(), m1.ID)
+	before := time.Now().Add(time.Hour)
+
+	_, err := repo.DeleteChannelMessagesBefore(uuid.Nil, before, false, 10)
+	assert.EqualError(err, repository.ErrNilID.Error())
+
+	n, err := repo.DeleteChannelMessagesBefore(ch.ID, time.Now().Add(-time.Hour), false, 10)
+	if assert.NoError(err) {
+		assert.Equal(0, n)
+	}
+
+	n, err = repo.DeleteChannelMessagesBefore(ch.ID, before, true, 10)
+	if assert.NoError(err) {
+		assert.Equal(2, n)
+		for _, id := range []uuid.UUID{m1.ID, m2.ID} {
+			_, err := repo.GetMessageByID(id)
+			assert.EqualError(err, repository.ErrNotFound.Error())
+		}
+		_, err := repo.GetMessageByID(pinned.ID)
+		assert.NoError(err)
+		assert.Equal(0, count(t, getDB(repo).Model(&model.Unread{}).Where(&model.Unread{MessageID: m1.ID})))
+	}
+
+	n, err = repo.DeleteChannelMessagesBefore(ch.ID, before, false, 10)
+	if assert.NoError(err) {
+		assert.Equal(1, n)
+		assert.Equal(0, count(t, getDB(repo).Model(&model.Pin{}).Where(&model.Pin{MessageID: pinned.ID})))
+	}
+}
+
+func TestRepositoryImpl_PurgeDeletedChannelMessages(t *testing.T) {
+	t.Parallel()
+	repo, assert, require, user, ch := setupWithUserAndChannel(t, common2)
+
+	m := mustMakeMessage(t, repo, user.GetID(), ch.ID)
+	alive := mustMakeMessage(t, repo, user.GetID(), ch.ID)
+	require.NoError(repo.DeleteMessage(m.ID))
+	before := time.Now().Add(time.Hour)
+
+	_, err := repo.PurgeDeletedChannelMessages(uuid.Nil, before, before, 10)
+	assert.EqualError(err, repository.ErrNilID.Error())
+
+	n, err := repo.PurgeDeletedChannelMessages(ch.ID, before, time.Now().Add(-time.Hour), 10)
+	if assert.NoError(err) {
+		assert.Equal(0, n)
+	}
+
+	n, err = repo.PurgeDeletedChannelMessages(ch.ID, before, before, 10)
+	if assert.NoError(err) {
+		assert.Equal(1, n)
+		assert.Equal(0, count(t, getDB(repo).Unscoped().Model(&model.Message{}).Where(&model.Message{ID: m.ID})))
+		_, err := repo.GetMessageByID(alive.ID)
+		assert.NoError(err)
+	}
+}
@@ -0,0 +1,99 @@
+package gorm
+
+import (
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/utils/gormutil"
+)
+
+// CreateChannelTemplate implements ChannelTemplateRepository interface.
+func (repo *Repository) CreateChannelTemplate(name, description string, channels model.ChannelTemplateNodes, creatorID uuid.UUID) (*model.ChannelTemplate, error) {
+	if creatorID == uuid.Nil {
+		return nil, repository.ErrNilID
+	}
+	if channels == nil {
+		channels = model.ChannelTemplateNodes{}
+	}
+	t := &model.ChannelTemplate{
+		ID:          uuid.Must(uuid.NewV4()),
+		Name:        name,
+		Description: description,
+		Channels:    channels,
+		CreatorID:   creatorID,
+	}
+	if err := repo.db.Create(t).Error; err != nil {
+		if gormutil.IsMySQLDuplicatedRecordErr(err) {
+			return nil, repository.ErrAlreadyExists
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+// UpdateChannelTemplate implements ChannelTemplateRepository interface.
+func (repo *Repository) UpdateChannelTemplate(id uuid.UUID, args repository.UpdateChannelTemplateArgs) error {
+	if id == uuid.Nil {
+		return repository.ErrNilID
+	}
+	changes := map[string]interface{}{}
+	if args.Name.Valid {
+		changes["name"] = args.Name.String
+	}
+	if args.Description.Valid {
+		changes["description"] = args.Description.String
+	}
+	if args.Channels != nil {
+		changes["channels"] = args.Channels
+	}
+
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var t model.ChannelTemplate
+		if err := tx.First(&t, &model.ChannelTemplate{ID: id}).Error; err != nil {
+			return convertError(err)
+		}
+		if len(changes) > 0 {
+			return tx.Model(&t).Updates(changes).Error
+		}
+		return nil
+	})
+	if gormutil.IsMySQLDuplicatedRecordErr(err) {
+		return repository.ErrAlreadyExists
+	}
+	return err
+}
+
+// GetChannelTemplate implements ChannelTemplateRepository interface.
+func (repo *Repository) GetChannelTemplate(id uuid.UUID) (*model.ChannelTemplate, error) {
+	if id == uuid.Nil {
+		return nil, repository.ErrNotFound
+	}
+	var t model.ChannelTemplate
+	if err := repo.db.Take(&t, &model.ChannelTemplate{ID: id}).Error; err != nil {
+		return nil, convertError(err)
+	}
+	return &t, nil
+}
+
+// GetChannelTemplates implements ChannelTemplateRepository interface.
+func (repo *Repository) GetChannelTemplates() ([]*model.ChannelTemplate, error) {
+	templates := make([]*model.ChannelTemplate, 0)
+	return templates, repo.db.Order("name").Find(&templates).Error
+}
+
+// DeleteChannelTemplate implements ChannelTemplateRepository interface.
+func (repo *Repository) DeleteChannelTemplate(id uuid.UUID) error {
+	if id == uuid.Nil {
+		return repository.ErrNilID
+	}
+	result := repo.db.Delete(&model.ChannelTemplate{ID: id})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
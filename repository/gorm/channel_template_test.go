@@ -0,0 +1,157 @@
+package gorm
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/utils/optional"
+	random2 "github.com/traPtitech/traQ/utils/random"
+)
+
+func TestRepositoryImpl_CreateChannelTemplate(t *testing.T) {
+	t.Parallel()
+	repo, _, _, user := setupWithUser(t, common2)
+
+	t.Run("nil user id", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := repo.CreateChannelTemplate(random2.AlphaNumeric(20), "", nil, uuid.Nil)
+		assert.EqualError(t, err, repository.ErrNilID.Error())
+	})
+
+	t.Run("duplicated name", func(t *testing.T) {
+		t.Parallel()
+
+		name := random2.AlphaNumeric(20)
+		_, err := repo.CreateChannelTemplate(name, "", nil, user.GetID())
+		assert.NoError(t, err)
+		_, err = repo.CreateChannelTemplate(name, "", nil, user.GetID())
+		assert.EqualError(t, err, repository.ErrAlreadyExists.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		name := random2.AlphaNumeric(20)
+		channels := model.ChannelTemplateNodes{
+			{Name: "general", Topic: "topic", Subscribers: []uuid.UUID{user.GetID()}, Children: model.ChannelTemplateNodes{{Name: "random"}}},
+		}
+		ct, err := repo.CreateChannelTemplate(name, "desc", channels, user.GetID())
+		if assert.NoError(err) {
+			assert.NotEmpty(ct.ID)
+			assert.Equal(name, ct.Name)
+
+			got, err := repo.GetChannelTemplate(ct.ID)
+			if assert.NoError(err) {
+				assert.Equal("desc", got.Description)
+				assert.Equal(user.GetID(), got.CreatorID)
+				if assert.Len(got.Channels, 1) {
+					assert.Equal("general", got.Channels[0].Name)
+					assert.Equal([]uuid.UUID{user.GetID()}, got.Channels[0].Subscribers)
+					assert.Equal("random", got.Channels[0].Children[0].Name)
+				}
+			}
+		}
+	})
+}
+
+func TestRepositoryImpl_UpdateChannelTemplate(t *testing.T) {
+	t.Parallel()
+	repo, _, _, user := setupWithUser(t, common2)
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+		assert.EqualError(t, repo.UpdateChannelTemplate(uuid.Nil, repository.UpdateChannelTemplateArgs{}), repository.ErrNilID.Error())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+		assert.EqualError(t, repo.UpdateChannelTemplate(uuid.Must(uuid.NewV4()), repository.UpdateChannelTemplateArgs{}), repository.ErrNotFound.Error())
+	})
+
+	t.Run("duplicated name", func(t *testing.T) {
+		t.Parallel()
+
+		ct1, err := repo.CreateChannelTemplate(random2.AlphaNumeric(20), "", nil, user.GetID())
+		assert.NoError(t, err)
+		ct2, err := repo.CreateChannelTemplate(random2.AlphaNumeric(20), "", nil, user.GetID())
+		assert.NoError(t, err)
+
+		err = repo.UpdateChannelTemplate(ct2.ID, repository.UpdateChannelTemplateArgs{Name: optional.StringFrom(ct1.Name)})
+		assert.EqualError(t, err, repository.ErrAlreadyExists.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		ct, err := repo.CreateChannelTemplate(random2.AlphaNumeric(20), "", nil, user.GetID())
+		assert.NoError(err)
+
+		name := random2.AlphaNumeric(20)
+		err = repo.UpdateChannelTemplate(ct.ID, repository.UpdateChannelTemplateArgs{
+			Name:        optional.StringFrom(name),
+			Description: optional.StringFrom("updated"),
+			Channels:    model.ChannelTemplateNodes{{Name: "a"}, {Name: "b"}},
+		})
+		if assert.NoError(err) {
+			got, err := repo.GetChannelTemplate(ct.ID)
+			if assert.NoError(err) {
+				assert.Equal(name, got.Name)
+				assert.Equal("updated", got.Description)
+				assert.Len(got.Channels, 2)
+			}
+		}
+	})
+}
+
+func TestRepositoryImpl_GetChannelTemplates(t *testing.T) {
+	t.Parallel()
+	repo, _, _, user := setupWithUser(t, common2)
+
+	ct, err := repo.CreateChannelTemplate(random2.AlphaNumeric(20), "", nil, user.GetID())
+	assert.NoError(t, err)
+
+	templates, err := repo.GetChannelTemplates()
+	if assert.NoError(t, err) {
+		found := false
+		for _, v := range templates {
+			if v.ID == ct.ID {
+				found = true
+			}
+		}
+		assert.True(t, found)
+	}
+}
+
+func TestRepositoryImpl_DeleteChannelTemplate(t *testing.T) {
+	t.Parallel()
+	repo, _, _, user := setupWithUser(t, common2)
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+		assert.EqualError(t, repo.DeleteChannelTemplate(uuid.Nil), repository.ErrNilID.Error())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+		assert.EqualError(t, repo.DeleteChannelTemplate(uuid.Must(uuid.NewV4())), repository.ErrNotFound.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		ct, err := repo.CreateChannelTemplate(random2.AlphaNumeric(20), "", nil, user.GetID())
+		assert.NoError(t, err)
+
+		if assert.NoError(t, repo.DeleteChannelTemplate(ct.ID)) {
+			_, err := repo.GetChannelTemplate(ct.ID)
+			assert.EqualError(t, err, repository.ErrNotFound.Error())
+		}
+	})
+}
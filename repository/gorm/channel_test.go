@@ -11,6 +11,8 @@ import (
 	"github.com/traPtitech/traQ/model"
 	"github.com/traPtitech/traQ/repository"
 	"github.com/traPtitech/traQ/utils/optional"
+	"github.com/traPtitech/traQ/utils/random"
+	"github.com/traPtitech/traQ/utils/set"
 )
 
 func TestGormRepository_UpdateChannel(t *testing.T) {
@@ -205,3 +207,388 @@ func TestGormRepository_GetChannelStats(t *testing.T) {
 	})
 
 }
+
+func TestGormRepository_ChannelRole(t *testing.T) {
+	t.Parallel()
+	repo, _, _ := setup(t, common)
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.SetChannelRole(uuid.Nil, uuid.Must(uuid.NewV4()), "moderator"), repository.ErrNilID.Error())
+		assert.EqualError(t, repo.DeleteChannelRole(uuid.Must(uuid.NewV4()), uuid.Nil), repository.ErrNilID.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert, require := assertAndRequire(t)
+
+		parent := mustMakeChannel(t, repo, rand)
+		channel := mustMakeChannel(t, repo, rand)
+		user := mustMakeUser(t, repo, rand)
+
+		require.NoError(repo.SetChannelRole(parent.ID, user.GetID(), "foo"))
+		require.NoError(repo.SetChannelRole(parent.ID, user.GetID(), "moderator"))
+
+		roles, err := repo.GetChannelRoles(parent.ID)
+		require.NoError(err)
+		if assert.Len(roles, 1) {
+			assert.Equal(user.GetID(), roles[0].UserID)
+			assert.Equal("moderator", roles[0].Role)
+		}
+
+		roles, err = repo.GetUserChannelRoles(user.GetID(), []uuid.UUID{channel.ID, parent.ID})
+		require.NoError(err)
+		assert.Len(roles, 1)
+
+		roles, err = repo.GetUserChannelRoles(user.GetID(), []uuid.UUID{channel.ID})
+		require.NoError(err)
+		assert.Empty(roles)
+
+		require.NoError(repo.DeleteChannelRole(parent.ID, user.GetID()))
+		assert.EqualError(repo.DeleteChannelRole(parent.ID, user.GetID()), repository.ErrNotFound.Error())
+
+		roles, err = repo.GetChannelRoles(parent.ID)
+		require.NoError(err)
+		assert.Empty(roles)
+	})
+}
+
+func TestGormRepository_PrivateChannelMembers(t *testing.T) {
+	t.Parallel()
+	repo, _, _ := setup(t, common)
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := repo.AddPrivateChannelMembers(uuid.Nil, set.UUID{})
+		assert.EqualError(t, err, repository.ErrNilID.Error())
+		assert.EqualError(t, repo.RemovePrivateChannelMember(uuid.Must(uuid.NewV4()), uuid.Nil), repository.ErrNilID.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert, require := assertAndRequire(t)
+
+		user1 := mustMakeUser(t, repo, rand)
+		user2 := mustMakeUser(t, repo, rand)
+		ch, err := repo.CreateChannel(model.Channel{
+			Name:      random.AlphaNumeric(20),
+			ParentID:  uuid.Must(uuid.FromString(model.PrivateChannelRootID)),
+			CreatorID: user1.GetID(),
+			UpdaterID: user1.GetID(),
+			IsVisible: true,
+		}, set.UUIDSetFromArray([]uuid.UUID{user1.GetID()}), false)
+		require.NoError(err)
+		assert.False(ch.IsPublic)
+		assert.True(ch.IsPrivateChannel())
+
+		added, err := repo.AddPrivateChannelMembers(ch.ID, set.UUIDSetFromArray([]uuid.UUID{user1.GetID(), user2.GetID(), uuid.Must(uuid.NewV4())}))
+		require.NoError(err)
+		assert.ElementsMatch([]uuid.UUID{user2.GetID()}, added)
+
+		members, err := repo.GetPrivateChannelMemberIDs(ch.ID)
+		require.NoError(err)
+		assert.ElementsMatch([]uuid.UUID{user1.GetID(), user2.GetID()}, members)
+
+		chs, err := repo.GetPrivateChannelsByUser(user2.GetID())
+		require.NoError(err)
+		if assert.Len(chs, 1) {
+			assert.Equal(ch.ID, chs[0].ID)
+		}
+
+		require.NoError(repo.RemovePrivateChannelMember(ch.ID, user2.GetID()))
+		assert.EqualError(repo.RemovePrivateChannelMember(ch.ID, user2.GetID()), repository.ErrNotFound.Error())
+
+		chs, err = repo.GetPrivateChannelsByUser(user2.GetID())
+		require.NoError(err)
+		assert.Empty(chs)
+	})
+
+	t.Run("same name", func(t *testing.T) {
+		t.Parallel()
+		assert, require := assertAndRequire(t)
+
+		user1 := mustMakeUser(t, repo, rand)
+		user2 := mustMakeUser(t, repo, rand)
+		name := random.AlphaNumeric(20)
+		ch1, err := repo.CreateChannel(model.Channel{
+			Name:      name,
+			ParentID:  uuid.Must(uuid.FromString(model.PrivateChannelRootID)),
+			CreatorID: user1.GetID(),
+			UpdaterID: user1.GetID(),
+			IsVisible: true,
+		}, set.UUIDSetFromArray([]uuid.UUID{user1.GetID()}), false)
+		require.NoError(err)
+		ch2, err := repo.CreateChannel(model.Channel{
+			Name:      name,
+			ParentID:  uuid.Must(uuid.FromString(model.PrivateChannelRootID)),
+			CreatorID: user2.GetID(),
+			UpdaterID: user2.GetID(),
+			IsVisible: true,
+		}, set.UUIDSetFromArray([]uuid.UUID{user2.GetID()}), false)
+		require.NoError(err)
+		assert.NotEqual(ch1.ID, ch2.ID)
+		assert.Equal(name, ch2.Name)
+	})
+}
+
+func TestGormRepository_GroupDirectMessageChannel(t *testing.T) {
+	t.Parallel()
+	repo, _, _ := setup(t, common)
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := repo.GetGroupDirectMessageChannel(set.UUIDSetFromArray([]uuid.UUID{uuid.Must(uuid.NewV4()), uuid.Must(uuid.NewV4()), uuid.Must(uuid.NewV4())}))
+		assert.EqualError(t, err, repository.ErrNotFound.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert, require := assertAndRequire(t)
+
+		user1 := mustMakeUser(t, repo, rand)
+		user2 := mustMakeUser(t, repo, rand)
+		user3 := mustMakeUser(t, repo, rand)
+		members := set.UUIDSetFromArray([]uuid.UUID{user1.GetID(), user2.GetID(), user3.GetID()})
+
+		ch, err := repo.CreateChannel(model.Channel{Name: "dm_" + random.AlphaNumeric(17), IsVisible: true}, members, true)
+		require.NoError(err)
+		assert.True(ch.IsGroupDMChannel())
+		assert.False(ch.IsPublic)
+
+		_, err = repo.CreateChannel(model.Channel{Name: "dm_" + random.AlphaNumeric(17), IsVisible: true}, members, true)
+		assert.EqualError(err, repository.ErrAlreadyExists.Error())
+
+		found, err := repo.GetGroupDirectMessageChannel(set.UUIDSetFromArray([]uuid.UUID{user3.GetID(), user1.GetID(), user2.GetID()}))
+		require.NoError(err)
+		assert.Equal(ch.ID, found.ID)
+
+		_, err = repo.GetGroupDirectMessageChannel(set.UUIDSetFromArray([]uuid.UUID{user1.GetID(), user2.GetID()}))
+		assert.EqualError(err, repository.ErrNotFound.Error())
+
+		chs, err := repo.GetGroupDirectMessageChannelsByUser(user2.GetID())
+		require.NoError(err)
+		if assert.Len(chs, 1) {
+			assert.Equal(ch.ID, chs[0].ID)
+		}
+
+		chs, err = repo.GetPrivateChannelsByUser(user2.GetID())
+		require.NoError(err)
+		assert.Empty(chs)
+	})
+}
+
+func TestGormRepository_CreatePublicChannels(t *testing.T) {
+	t.Parallel()
+	repo, _, _ := setup(t, common)
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := repo.CreatePublicChannels([]*repository.CreatePublicChannelArgs{{Channel: model.Channel{Name: "a"}}})
+		assert.EqualError(t, err, repository.ErrNilID.Error())
+	})
+
+	t.Run("duplicated name", func(t *testing.T) {
+		t.Parallel()
+
+		parent := mustMakeChannel(t, repo, rand)
+		existing, err := repo.CreateChannel(model.Channel{Name: random.AlphaNumeric(20), ParentID: parent.ID, IsVisible: true}, nil, false)
+		require.NoError(t, err)
+		newID := uuid.Must(uuid.NewV4())
+		_, err = repo.CreatePublicChannels([]*repository.CreatePublicChannelArgs{
+			{Channel: model.Channel{ID: newID, Name: random.AlphaNumeric(20), ParentID: parent.ID, IsVisible: true}},
+			{Channel: model.Channel{ID: uuid.Must(uuid.NewV4()), Name: existing.Name, ParentID: parent.ID, IsVisible: true}},
+		})
+		assert.EqualError(t, err, repository.ErrAlreadyExists.Error())
+
+		// ロールバックされている
+		_, err = repo.GetChannel(newID)
+		assert.EqualError(t, err, repository.ErrNotFound.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert, require := assertAndRequire(t)
+
+		parent := mustMakeChannel(t, repo, rand)
+		user := mustMakeUser(t, repo, rand)
+		c1 := uuid.Must(uuid.NewV4())
+		c2 := uuid.Must(uuid.NewV4())
+		chs, err := repo.CreatePublicChannels([]*repository.CreatePublicChannelArgs{
+			{
+				Channel:     model.Channel{ID: c1, Name: "general", ParentID: parent.ID, Topic: "topic", IsForced: true, IsVisible: true},
+				Subscribers: set.UUIDSetFromArray([]uuid.UUID{user.GetID(), uuid.Must(uuid.NewV4())}),
+				Bots:        set.UUIDSetFromArray([]uuid.UUID{uuid.Must(uuid.NewV4())}),
+			},
+			{
+				Channel: model.Channel{ID: c2, Name: "random", ParentID: c1, IsVisible: true},
+			},
+		})
+		require.NoError(err)
+		require.Len(chs, 2)
+		assert.True(chs[0].IsPublic)
+
+		ch, err := repo.GetChannel(c1)
+		require.NoError(err)
+		assert.Equal("topic", ch.Topic)
+		assert.True(ch.IsForced)
+
+		ch, err = repo.GetChannel(c2)
+		require.NoError(err)
+		assert.Equal(c1, ch.ParentID)
+
+		subs, err := repo.GetChannelSubscriptions(repository.ChannelSubscriptionQuery{}.SetChannel(c1))
+		require.NoError(err)
+		if assert.Len(subs, 1) {
+			assert.Equal(user.GetID(), subs[0].UserID)
+			assert.True(subs[0].Notify)
+		}
+	})
+}
+
+func TestGormRepository_MergeChannel(t *testing.T) {
+	t.Parallel()
+	repo, _, _ := setup(t, common)
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, err := repo.MergeChannel(uuid.Nil, uuid.Must(uuid.NewV4()), uuid.Nil)
+		assert.EqualError(t, err, repository.ErrNilID.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert, require := assertAndRequire(t)
+
+		src := mustMakeChannel(t, repo, rand)
+		dst := mustMakeChannel(t, repo, rand)
+		user1 := mustMakeUser(t, repo, rand)
+		user2 := mustMakeUser(t, repo, rand)
+		child, err := repo.CreateChannel(model.Channel{Name: random.AlphaNumeric(20), ParentID: src.ID, IsVisible: true}, nil, false)
+		require.NoError(err)
+
+		m1 := mustMakeMessage(t, repo, user1.GetID(), src.ID)
+		deleted := mustMakeMessage(t, repo, user1.GetID(), src.ID)
+		require.NoError(repo.DeleteMessage(deleted.ID))
+		m2 := mustMakeMessage(t, repo, user2.GetID(), src.ID)
+		mustMakeMessageUnread(t, repo, user2.GetID(), m1.ID)
+		mustMakePin(t, repo, m2.ID, user1.GetID())
+		mustChangeChannelSubscription(t, repo, src.ID, user1.GetID())
+		require.NoError(repo.AddStar(user2.GetID(), src.ID))
+		require.NoError(repo.SetChannelRole(src.ID, user1.GetID(), "channel_moderator"))
+		require.NoError(repo.SetChannelRole(src.ID, user2.GetID(), "channel_moderator"))
+		require.NoError(repo.SetChannelRole(dst.ID, user2.GetID(), "channel_viewer"))
+
+		children, moved, err := repo.MergeChannel(src.ID, dst.ID, user1.GetID())
+		require.NoError(err)
+		assert.EqualValues(3, moved) // 削除済みメッセージを含む
+		if assert.Len(children, 1) {
+			assert.Equal(child.ID, children[0].ID)
+			assert.Equal(dst.ID, children[0].ParentID)
+			assert.Equal(user1.GetID(), children[0].UpdaterID)
+		}
+
+		// メッセージのupdated_atは変更されない
+		m, err := repo.GetMessageByID(m1.ID)
+		require.NoError(err)
+		assert.Equal(dst.ID, m.ChannelID)
+		assert.True(m.UpdatedAt.Equal(m1.UpdatedAt))
+
+		assert.Equal(2, count(t, getDB(repo).Model(&model.Message{}).Where(&model.Message{ChannelID: dst.ID})))
+		assert.Equal(0, count(t, getDB(repo).Unscoped().Model(&model.Message{}).Where(&model.Message{ChannelID: src.ID})))
+		assert.Equal(1, count(t, getDB(repo).Model(&model.UserSubscribeChannel{}).Where(&model.UserSubscribeChannel{ChannelID: dst.ID})))
+		assert.Equal(1, count(t, getDB(repo).Model(&model.Star{}).Where(&model.Star{ChannelID: dst.ID})))
+
+		pins, err := repo.GetPinnedMessageByChannelID(dst.ID)
+		require.NoError(err)
+		assert.Len(pins, 1)
+
+		unreads, err := repo.GetUnreadMessagesByUserID(user2.GetID())
+		require.NoError(err)
+		if assert.Len(unreads, 1) {
+			assert.Equal(dst.ID, unreads[0].ChannelID)
+		}
+
+		var latest model.ChannelLatestMessage
+		require.NoError(getDB(repo).First(&latest, &model.ChannelLatestMessage{ChannelID: dst.ID}).Error)
+		assert.Equal(m2.ID, latest.MessageID)
+		assert.Equal(0, count(t, getDB(repo).Model(&model.ChannelLatestMessage{}).Where(&model.ChannelLatestMessage{ChannelID: src.ID})))
+
+		// チャンネルロールは統合先のものが優先される
+		roles, err := repo.GetChannelRoles(dst.ID)
+		require.NoError(err)
+		roleMap := map[uuid.UUID]string{}
+		for _, r := range roles {
+			roleMap[r.UserID] = r.Role
+		}
+		assert.Equal(map[uuid.UUID]string{user1.GetID(): "channel_moderator", user2.GetID(): "channel_viewer"}, roleMap)
+		assert.Equal(0, count(t, getDB(repo).Model(&model.ChannelRole{}).Where(&model.ChannelRole{ChannelID: src.ID})))
+	})
+
+	t.Run("child name conflicts", func(t *testing.T) {
+		t.Parallel()
+		assert, require := assertAndRequire(t)
+
+		src := mustMakeChannel(t, repo, rand)
+		dst := mustMakeChannel(t, repo, rand)
+		user := mustMakeUser(t, repo, rand)
+		name := random.AlphaNumeric(20)
+		_, err := repo.CreateChannel(model.Channel{Name: name, ParentID: src.ID, IsVisible: true}, nil, false)
+		require.NoError(err)
+		_, err = repo.CreateChannel(model.Channel{Name: name, ParentID: dst.ID, IsVisible: true}, nil, false)
+		require.NoError(err)
+		mustMakeMessage(t, repo, user.GetID(), src.ID)
+
+		_, _, err = repo.MergeChannel(src.ID, dst.ID, user.GetID())
+		assert.EqualError(err, repository.ErrAlreadyExists.Error())
+
+		// 何も移動されない
+		assert.Equal(1, count(t, getDB(repo).Model(&model.Message{}).Where(&model.Message{ChannelID: src.ID})))
+	})
+}
+
+func TestGormRepository_DeleteChannel(t *testing.T) {
+	t.Parallel()
+	repo, _, _ := setup(t, common)
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.DeleteChannel(uuid.Nil), repository.ErrNilID.Error())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.DeleteChannel(uuid.Must(uuid.NewV4())), repository.ErrNotFound.Error())
+	})
+
+	t.Run("not empty", func(t *testing.T) {
+		t.Parallel()
+
+		ch := mustMakeChannel(t, repo, rand)
+		user := mustMakeUser(t, repo, rand)
+		mustMakeMessage(t, repo, user.GetID(), ch.ID)
+
+		assert.EqualError(t, repo.DeleteChannel(ch.ID), repository.ErrForbidden.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert, require := assertAndRequire(t)
+
+		ch := mustMakeChannel(t, repo, rand)
+		user := mustMakeUser(t, repo, rand)
+		m := mustMakeMessage(t, repo, user.GetID(), ch.ID)
+		require.NoError(repo.DeleteMessage(m.ID))
+
+		require.NoError(repo.DeleteChannel(ch.ID))
+		_, err := repo.GetChannel(ch.ID)
+		assert.EqualError(err, repository.ErrNotFound.Error())
+		assert.Equal(0, count(t, getDB(repo).Unscoped().Model(&model.Message{}).Where(&model.Message{ChannelID: ch.ID})))
+	})
+}
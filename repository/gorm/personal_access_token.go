@@ -0,0 +1,80 @@
+package gorm
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/utils/optional"
+)
+
+// CreatePersonalAccessToken implements PersonalAccessTokenRepository interface.
+func (repo *Repository) CreatePersonalAccessToken(args repository.CreatePersonalAccessTokenArgs) (*model.PersonalAccessToken, error) {
+	if args.UserID == uuid.Nil {
+		return nil, repository.ErrNilID
+	}
+	t := &model.PersonalAccessToken{
+		ID:        uuid.Must(uuid.NewV4()),
+		UserID:    args.UserID,
+		Name:      args.Name,
+		TokenHash: args.TokenHash,
+		Scopes:    args.Scopes,
+		ExpiresAt: args.ExpiresAt,
+	}
+	if err := repo.db.Create(t).Error; err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// GetPersonalAccessTokenByHash implements PersonalAccessTokenRepository interface.
+func (repo *Repository) GetPersonalAccessTokenByHash(tokenHash string) (*model.PersonalAccessToken, error) {
+	if len(tokenHash) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	var t model.PersonalAccessToken
+	if err := repo.db.Take(&t, &model.PersonalAccessToken{TokenHash: tokenHash}).Error; err != nil {
+		return nil, convertError(err)
+	}
+	return &t, nil
+}
+
+// GetPersonalAccessTokensByUser implements PersonalAccessTokenRepository interface.
+func (repo *Repository) GetPersonalAccessTokensByUser(userID uuid.UUID) ([]*model.PersonalAccessToken, error) {
+	tokens := make([]*model.PersonalAccessToken, 0)
+	if userID == uuid.Nil {
+		return tokens, nil
+	}
+	return tokens, repo.db.Where(&model.PersonalAccessToken{UserID: userID}).Order("created_at").Find(&tokens).Error
+}
+
+// UpdatePersonalAccessTokenLastUsed implements PersonalAccessTokenRepository interface.
+func (repo *Repository) UpdatePersonalAccessTokenLastUsed(id uuid.UUID, at time.Time, ip string) error {
+	if id == uuid.Nil {
+		return nil
+	}
+	return repo.db.
+		Model(&model.PersonalAccessToken{ID: id}).
+		Updates(map[string]interface{}{
+			"last_used_at": optional.TimeFrom(at),
+			"last_used_ip": ip,
+		}).
+		Error
+}
+
+// DeletePersonalAccessToken implements PersonalAccessTokenRepository interface.
+func (repo *Repository) DeletePersonalAccessToken(userID, id uuid.UUID) error {
+	if userID == uuid.Nil || id == uuid.Nil {
+		return repository.ErrNotFound
+	}
+	result := repo.db.Delete(&model.PersonalAccessToken{}, &model.PersonalAccessToken{ID: id, UserID: userID})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package gorm
+
+import (
+	"github.com/gofrs/uuid"
+	"github.com/leandro-lugaresi/hub"
+	"gorm.io/gorm"
+
+	"github.com/traPtitech/traQ/event"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/utils/gormutil"
+	"github.com/traPtitech/traQ/utils/optional"
+)
+
+// GetSidebarSections implements SidebarRepository interface.
+func (repo *Repository) GetSidebarSections(userID uuid.UUID) ([]*model.SidebarSection, error) {
+	return getSidebarSections(repo.db, userID)
+}
+
+// ReplaceSidebarSections implements SidebarRepository interface.
+func (repo *Repository) ReplaceSidebarSections(userID uuid.UUID, sections []*repository.SidebarSectionArgs) ([]*model.SidebarSection, error) {
+	if userID == uuid.Nil {
+		return nil, repository.ErrNilID
+	}
+	if len(sections) > model.MaxSidebarSections {
+		return nil, repository.ArgError("sections", "too many sections")
+	}
+
+	newSections := make([]*model.SidebarSection, len(sections))
+	for i, s := range sections {
+		if len(s.Items) > model.MaxSidebarItems {
+			return nil, repository.ArgError("sections", "too many items in a section")
+		}
+		section := &model.SidebarSection{
+			ID:        uuid.Must(uuid.NewV4()),
+			UserID:    userID,
+			Name:      s.Name,
+			Position:  i,
+			Collapsed: s.Collapsed,
+			Items:     make([]*model.SidebarItem, len(s.Items)),
+		}
+		for j, item := range s.Items {
+			if !item.Type.Valid() {
+				return nil, repository.ArgError("sections", "invalid item type")
+			}
+			newItem := &model.SidebarItem{
+				ID:        uuid.Must(uuid.NewV4()),
+				SectionID: section.ID,
+				Position:  j,
+				Type:      item.Type,
+			}
+			if item.Type.IsChannel() {
+				if item.ChannelID == uuid.Nil {
+					return nil, repository.ArgError("sections", "channel item must have channel id")
+				}
+				newItem.ChannelID = optional.UUIDFrom(item.ChannelID)
+			} else {
+				newItem.URL = item.URL
+				newItem.Title = item.Title
+			}
+			section.Items[j] = newItem
+		}
+		newSections[i] = section
+	}
+
+	var result []*model.SidebarSection
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		// 項目はFKによりカスケード削除される
+		if err := tx.Where(&model.SidebarSection{UserID: userID}).Delete(&model.SidebarSection{}).Error; err != nil {
+			return err
+		}
+		for _, section := range newSections {
+			if err := tx.Omit("Items").Create(section).Error; err != nil {
+				return err
+			}
+			if len(section.Items) > 0 {
+				if err := tx.Create(section.Items).Error; err != nil {
+					if gormutil.IsMySQLForeignKeyConstraintFailsError(err) {
+						return repository.ArgError("sections", "channel not found")
+					}
+					return err
+				}
+			}
+		}
+
+		var err error
+		result, err = getSidebarSections(tx, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	repo.hub.Publish(hub.Message{
+		Name: event.SidebarUpdated,
+		Fields: hub.Fields{
+			"user_id": userID,
+		},
+	})
+	return result, nil
+}
+
+// UpdateSidebarSection implements SidebarRepository interface.
+func (repo *Repository) UpdateSidebarSection(userID, sectionID uuid.UUID, args repository.UpdateSidebarSectionArgs) error {
+	if userID == uuid.Nil || sectionID == uuid.Nil {
+		return repository.ErrNilID
+	}
+
+	changes := map[string]interface{}{}
+	if args.Name.Valid {
+		changes["name"] = args.Name.String
+	}
+	if args.Collapsed.Valid {
+		changes["collapsed"] = args.Collapsed.Bool
+	}
+
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var s model.SidebarSection
+		if err := tx.Take(&s, &model.SidebarSection{ID: sectionID, UserID: userID}).Error; err != nil {
+			return convertError(err)
+		}
+		if len(changes) > 0 {
+			return tx.Model(&s).Updates(changes).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(changes) > 0 {
+		repo.hub.Publish(hub.Message{
+			Name: event.SidebarUpdated,
+			Fields: hub.Fields{
+				"user_id": userID,
+			},
+		})
+	}
+	return nil
+}
+
+func getSidebarSections(tx *gorm.DB, userID uuid.UUID) ([]*model.SidebarSection, error) {
+	sections := make([]*model.SidebarSection, 0)
+	if userID == uuid.Nil {
+		return sections, nil
+	}
+	err := tx.
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position")
+		}).
+		Where(&model.SidebarSection{UserID: userID}).
+		Order("position").
+		Find(&sections).
+		Error
+	return sections, err
+}
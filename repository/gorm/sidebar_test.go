@@ -0,0 +1,163 @@
+package gorm
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/utils/optional"
+	random2 "github.com/traPtitech/traQ/utils/random"
+)
+
+func TestRepositoryImpl_ReplaceSidebarSections(t *testing.T) {
+	t.Parallel()
+	repo, _, _ := setup(t, common2)
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := repo.ReplaceSidebarSections(uuid.Nil, nil)
+		assert.EqualError(t, err, repository.ErrNilID.Error())
+	})
+
+	t.Run("invalid item type", func(t *testing.T) {
+		t.Parallel()
+		user := mustMakeUser(t, repo, rand)
+
+		_, err := repo.ReplaceSidebarSections(user.GetID(), []*repository.SidebarSectionArgs{
+			{Name: "a", Items: []*repository.SidebarItemArgs{{Type: "user"}}},
+		})
+		assert.True(t, repository.IsArgError(err))
+	})
+
+	t.Run("channel not found", func(t *testing.T) {
+		t.Parallel()
+		user := mustMakeUser(t, repo, rand)
+
+		_, err := repo.ReplaceSidebarSections(user.GetID(), []*repository.SidebarSectionArgs{
+			{Name: "a", Items: []*repository.SidebarItemArgs{{Type: model.SidebarItemTypeChannel, ChannelID: uuid.Must(uuid.NewV4())}}},
+		})
+		assert.True(t, repository.IsArgError(err))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+		user := mustMakeUser(t, repo, rand)
+		ch := mustMakeChannel(t, repo, random2.AlphaNumeric(20))
+
+		sections, err := repo.ReplaceSidebarSections(user.GetID(), []*repository.SidebarSectionArgs{
+			{
+				Name: "first",
+				Items: []*repository.SidebarItemArgs{
+					{Type: model.SidebarItemTypeLink, URL: "https://example.com", Title: "example"},
+					{Type: model.SidebarItemTypeChannel, ChannelID: ch.ID},
+				},
+			},
+			{Name: "second", Collapsed: true},
+		})
+		if assert.NoError(err) && assert.Len(sections, 2) {
+			assert.Equal("first", sections[0].Name)
+			assert.False(sections[0].Collapsed)
+			if assert.Len(sections[0].Items, 2) {
+				assert.Equal(model.SidebarItemTypeLink, sections[0].Items[0].Type)
+				assert.Equal("https://example.com", sections[0].Items[0].URL)
+				assert.False(sections[0].Items[0].ChannelID.Valid)
+				assert.Equal(model.SidebarItemTypeChannel, sections[0].Items[1].Type)
+				assert.Equal(ch.ID, sections[0].Items[1].ChannelID.UUID)
+			}
+			assert.Equal("second", sections[1].Name)
+			assert.True(sections[1].Collapsed)
+			assert.Len(sections[1].Items, 0)
+		}
+
+		sections, err = repo.ReplaceSidebarSections(user.GetID(), []*repository.SidebarSectionArgs{{Name: "only"}})
+		if assert.NoError(err) && assert.Len(sections, 1) {
+			assert.Equal("only", sections[0].Name)
+		}
+		assert.EqualValues(0, count(t, getDB(repo).Model(&model.SidebarItem{}).Joins("JOIN sidebar_sections ON sidebar_sections.id = sidebar_items.section_id").Where("sidebar_sections.user_id = ?", user.GetID())))
+	})
+}
+
+func TestRepositoryImpl_GetSidebarSections(t *testing.T) {
+	t.Parallel()
+	repo, _, _ := setup(t, common2)
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+
+		sections, err := repo.GetSidebarSections(uuid.Nil)
+		if assert.NoError(t, err) {
+			assert.Len(t, sections, 0)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+		user := mustMakeUser(t, repo, rand)
+
+		_, err := repo.ReplaceSidebarSections(user.GetID(), []*repository.SidebarSectionArgs{{Name: "a"}, {Name: "b"}, {Name: "c"}})
+		if assert.NoError(err) {
+			sections, err := repo.GetSidebarSections(user.GetID())
+			if assert.NoError(err) && assert.Len(sections, 3) {
+				assert.Equal("a", sections[0].Name)
+				assert.Equal("b", sections[1].Name)
+				assert.Equal("c", sections[2].Name)
+			}
+		}
+	})
+}
+
+func TestRepositoryImpl_UpdateSidebarSection(t *testing.T) {
+	t.Parallel()
+	repo, _, _ := setup(t, common2)
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.UpdateSidebarSection(uuid.Nil, uuid.Nil, repository.UpdateSidebarSectionArgs{}), repository.ErrNilID.Error())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+		user := mustMakeUser(t, repo, rand)
+
+		assert.EqualError(t, repo.UpdateSidebarSection(user.GetID(), uuid.Must(uuid.NewV4()), repository.UpdateSidebarSectionArgs{}), repository.ErrNotFound.Error())
+	})
+
+	t.Run("other user's section", func(t *testing.T) {
+		t.Parallel()
+		user := mustMakeUser(t, repo, rand)
+		other := mustMakeUser(t, repo, rand)
+
+		sections, err := repo.ReplaceSidebarSections(other.GetID(), []*repository.SidebarSectionArgs{{Name: "a"}})
+		if assert.NoError(t, err) {
+			assert.EqualError(t, repo.UpdateSidebarSection(user.GetID(), sections[0].ID, repository.UpdateSidebarSectionArgs{Collapsed: optional.BoolFrom(true)}), repository.ErrNotFound.Error())
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+		user := mustMakeUser(t, repo, rand)
+
+		sections, err := repo.ReplaceSidebarSections(user.GetID(), []*repository.SidebarSectionArgs{{Name: "a"}})
+		if assert.NoError(err) {
+			err := repo.UpdateSidebarSection(user.GetID(), sections[0].ID, repository.UpdateSidebarSectionArgs{
+				Name:      optional.StringFrom("renamed"),
+				Collapsed: optional.BoolFrom(true),
+			})
+			if assert.NoError(err) {
+				sections, err := repo.GetSidebarSections(user.GetID())
+				if assert.NoError(err) && assert.Len(sections, 1) {
+					assert.Equal("renamed", sections[0].Name)
+					assert.True(sections[0].Collapsed)
+				}
+			}
+		}
+	})
+}
@@ -22,6 +22,8 @@ import (
 type stampRepository struct {
 	stamps  *sc.Cache[struct{}, map[uuid.UUID]*model.Stamp]
 	perType *sc.Cache[repository.StampType, []*model.Stamp]
+	aliases *sc.Cache[struct{}, map[string]*model.Stamp]
+	stats   *sc.Cache[struct{}, map[uuid.UUID]*repository.StampStats]
 }
 
 func makeStampRepository(db *gorm.DB) *stampRepository {
@@ -29,6 +31,8 @@ func makeStampRepository(db *gorm.DB) *stampRepository {
 	r := &stampRepository{}
 	r.stamps = sc.NewMust(r.loadFunc(db), 365*24*time.Hour, 365*24*time.Hour)
 	r.perType = sc.NewMust(r.filterFunc(), 365*24*time.Hour, 365*24*time.Hour)
+	r.aliases = sc.NewMust(r.aliasFunc(), 365*24*time.Hour, 365*24*time.Hour)
+	r.stats = sc.NewMust(r.statsFunc(db), time.Hour, time.Hour)
 	return r
 }
 
@@ -38,14 +42,74 @@ func (r *stampRepository) loadFunc(db *gorm.DB) func(context.Context, struct{})
 		if err := db.Find(&stamps).Error; err != nil {
 			return nil, err
 		}
+		var aliases []*model.StampAlias
+		if err := db.Order("alias").Find(&aliases).Error; err != nil {
+			return nil, err
+		}
+		var tags []*model.StampTag
+		if err := db.Order("tag").Find(&tags).Error; err != nil {
+			return nil, err
+		}
+
 		stampsMap := make(map[uuid.UUID]*model.Stamp, len(stamps))
 		for _, s := range stamps {
+			s.Aliases = make([]string, 0)
+			s.Tags = make([]string, 0)
 			stampsMap[s.ID] = s
 		}
+		for _, a := range aliases {
+			if s, ok := stampsMap[a.StampID]; ok {
+				s.Aliases = append(s.Aliases, a.Alias)
+			}
+		}
+		for _, t := range tags {
+			if s, ok := stampsMap[t.StampID]; ok {
+				s.Tags = append(s.Tags, t.Tag)
+			}
+		}
 		return stampsMap, nil
 	}
 }
 
+func (r *stampRepository) aliasFunc() func(context.Context, struct{}) (map[string]*model.Stamp, error) {
+	return func(ctx context.Context, _ struct{}) (map[string]*model.Stamp, error) {
+		stamps, err := r.stamps.Get(ctx, struct{}{})
+		if err != nil {
+			return nil, err
+		}
+		aliases := make(map[string]*model.Stamp)
+		for _, s := range stamps {
+			for _, a := range s.Aliases {
+				aliases[a] = s
+			}
+		}
+		return aliases, nil
+	}
+}
+
+func (r *stampRepository) statsFunc(db *gorm.DB) func(context.Context, struct{}) (map[uuid.UUID]*repository.StampStats, error) {
+	return func(_ context.Context, _ struct{}) (map[uuid.UUID]*repository.StampStats, error) {
+		var rows []struct {
+			StampID uuid.UUID
+			repository.StampStats
+		}
+		if err := db.
+			Unscoped().
+			Model(&model.MessageStamp{}).
+			Select("stamp_id", "COUNT(stamp_id) AS count", "SUM(count) AS total_count").
+			Group("stamp_id").
+			Find(&rows).
+			Error; err != nil {
+			return nil, err
+		}
+		stats := make(map[uuid.UUID]*repository.StampStats, len(rows))
+		for _, row := range rows {
+			stats[row.StampID] = &repository.StampStats{Count: row.Count, TotalCount: row.TotalCount}
+		}
+		return stats, nil
+	}
+}
+
 func (r *stampRepository) filterFunc() func(_ context.Context, stampType repository.StampType) ([]*model.Stamp, error) {
 	return func(ctx context.Context, stampType repository.StampType) ([]*model.Stamp, error) {
 		stamps, err := r.stamps.Get(ctx, struct{}{})
@@ -84,6 +148,7 @@ func (r *stampRepository) filterFunc() func(_ context.Context, stampType reposit
 func (r *stampRepository) Purge() {
 	r.stamps.Purge()
 	r.perType.Purge()
+	r.aliases.Purge()
 }
 
 func (r *stampRepository) GetStamp(id uuid.UUID) (s *model.Stamp, ok bool, err error) {
@@ -116,6 +181,9 @@ func (repo *Repository) CreateStamp(args repository.CreateStampArgs) (s *model.S
 		FileID:    args.FileID,
 		CreatorID: args.CreatorID, // uuid.Nilを許容する
 		IsUnicode: args.IsUnicode,
+		Category:  args.Category,
+		Aliases:   make([]string, 0),
+		Tags:      make([]string, 0),
 	}
 
 	err = repo.db.Transaction(func(tx *gorm.DB) error {
@@ -124,11 +192,15 @@ func (repo *Repository) CreateStamp(args repository.CreateStampArgs) (s *model.S
 			return repository.ArgError("name", "Name must be 1-32 characters of a-zA-Z0-9_-")
 		}
 		// 名前重複チェック
-		if exists, err := gormutil.RecordExists(tx, &model.Stamp{Name: stamp.Name}); err != nil {
+		if exists, err := stampNameExists(tx, stamp.Name); err != nil {
 			return err
 		} else if exists {
 			return repository.ErrAlreadyExists
 		}
+		// カテゴリーチェック
+		if err := vd.Validate(stamp.Category, validator.StampCategoryRule...); err != nil {
+			return repository.ArgError("category", "Category must be 0-32 characters")
+		}
 		// ファイル存在チェック
 		if stamp.FileID == uuid.Nil {
 			return repository.ArgError("fileID", "FileID's file is not found")
@@ -139,7 +211,27 @@ func (repo *Repository) CreateStamp(args repository.CreateStampArgs) (s *model.S
 			return repository.ArgError("fileID", "fileID's file is not found")
 		}
 
-		return tx.Create(stamp).Error
+		if err := tx.Create(stamp).Error; err != nil {
+			return err
+		}
+
+		if len(args.Aliases) > 0 {
+			if err := replaceStampAliases(tx, stamp.ID, args.Aliases); err != nil {
+				return err
+			}
+			if err := tx.Model(&model.StampAlias{}).Where(&model.StampAlias{StampID: stamp.ID}).Order("alias").Pluck("alias", &stamp.Aliases).Error; err != nil {
+				return err
+			}
+		}
+		if len(args.Tags) > 0 {
+			if err := replaceStampTags(tx, stamp.ID, args.Tags); err != nil {
+				return err
+			}
+			if err := tx.Model(&model.StampTag{}).Where(&model.StampTag{StampID: stamp.ID}).Order("tag").Pluck("tag", &stamp.Tags).Error; err != nil {
+				return err
+			}
+		}
+		return nil
 	})
 	if err != nil {
 		return nil, err
@@ -165,6 +257,7 @@ func (repo *Repository) UpdateStamp(id uuid.UUID, args repository.UpdateStampArg
 
 	var s model.Stamp
 	changes := map[string]interface{}{}
+	extraChanged := false
 	err := repo.db.Transaction(func(tx *gorm.DB) error {
 		if err := tx.First(&s, &model.Stamp{ID: id}).Error; err != nil {
 			return convertError(err)
@@ -176,13 +269,19 @@ func (repo *Repository) UpdateStamp(id uuid.UUID, args repository.UpdateStampArg
 			}
 
 			// 重複チェック
-			if exists, err := gormutil.RecordExists(tx, &model.Stamp{Name: args.Name.String}); err != nil {
+			if exists, err := stampNameExists(tx, args.Name.String); err != nil {
 				return err
 			} else if exists {
 				return repository.ErrAlreadyExists
 			}
 			changes["name"] = args.Name.String
 		}
+		if args.Category.Valid {
+			if err := vd.Validate(args.Category.String, validator.StampCategoryRule...); err != nil {
+				return repository.ArgError("args.Category", "Category must be 0-32 characters")
+			}
+			changes["category"] = args.Category.String
+		}
 		if args.FileID.Valid {
 			// 存在チェック
 			if args.FileID.UUID == uuid.Nil {
@@ -200,6 +299,19 @@ func (repo *Repository) UpdateStamp(id uuid.UUID, args repository.UpdateStampArg
 			changes["creator_id"] = args.CreatorID.UUID
 		}
 
+		if args.Aliases != nil {
+			if err := replaceStampAliases(tx, id, args.Aliases); err != nil {
+				return err
+			}
+			extraChanged = true
+		}
+		if args.Tags != nil {
+			if err := replaceStampTags(tx, id, args.Tags); err != nil {
+				return err
+			}
+			extraChanged = true
+		}
+
 		if len(changes) > 0 {
 			return tx.Model(&s).Updates(changes).Error
 		}
@@ -208,7 +320,7 @@ func (repo *Repository) UpdateStamp(id uuid.UUID, args repository.UpdateStampArg
 	if err != nil {
 		return err
 	}
-	if len(changes) > 0 {
+	if len(changes) > 0 || extraChanged {
 		repo.stamps.Purge()
 		repo.hub.Publish(hub.Message{
 			Name: event.StampUpdated,
@@ -248,6 +360,22 @@ func (repo *Repository) GetStampByName(name string) (s *model.Stamp, err error)
 	return s, nil
 }
 
+// GetStampByAlias implements StampRepository interface.
+func (repo *Repository) GetStampByAlias(alias string) (s *model.Stamp, err error) {
+	if len(alias) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	aliases, err := repo.stamps.aliases.Get(context.Background(), struct{}{})
+	if err != nil {
+		return nil, err
+	}
+	s, ok := aliases[alias]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return s, nil
+}
+
 // DeleteStamp implements StampRepository interface.
 func (repo *Repository) DeleteStamp(id uuid.UUID) (err error) {
 	if id == uuid.Nil {
@@ -344,3 +472,84 @@ func (repo *Repository) GetStampStats(stampID uuid.UUID) (*repository.StampStats
 	}
 	return &stats, nil
 }
+
+// GetAllStampStats implements StampRepository interface
+func (repo *Repository) GetAllStampStats() (map[uuid.UUID]*repository.StampStats, error) {
+	return repo.stamps.stats.Get(context.Background(), struct{}{})
+}
+
+// stampNameExists 指定した名前がスタンプ名・エイリアスとして使われているかどうか
+//
+// 削除済みのスタンプの名前も使われているものとして扱います。
+func stampNameExists(tx *gorm.DB, name string) (bool, error) {
+	if exists, err := gormutil.RecordExists(tx.Unscoped(), &model.Stamp{Name: name}); err != nil || exists {
+		return exists, err
+	}
+	return gormutil.RecordExists(tx, &model.StampAlias{Alias: name})
+}
+
+func replaceStampAliases(tx *gorm.DB, stampID uuid.UUID, aliases []string) error {
+	if len(aliases) > model.MaxStampAliases {
+		return repository.ArgError("args.Aliases", "too many aliases")
+	}
+	seen := make(map[string]struct{}, len(aliases))
+	for _, alias := range aliases {
+		if err := vd.Validate(alias, validator.StampNameRuleRequired...); err != nil {
+			return repository.ArgError("args.Aliases", "Alias must be 1-32 characters of a-zA-Z0-9_-")
+		}
+		if _, ok := seen[alias]; ok {
+			return repository.ArgError("args.Aliases", "duplicated aliases")
+		}
+		seen[alias] = struct{}{}
+
+		// 重複チェック
+		if exists, err := gormutil.RecordExists(tx.Unscoped(), &model.Stamp{Name: alias}); err != nil {
+			return err
+		} else if exists {
+			return repository.ErrAlreadyExists
+		}
+		if exists, err := gormutil.Exists(tx.Model(&model.StampAlias{}).Where("alias = ? AND stamp_id <> ?", alias, stampID)); err != nil {
+			return err
+		} else if exists {
+			return repository.ErrAlreadyExists
+		}
+	}
+
+	if err := tx.Delete(&model.StampAlias{}, &model.StampAlias{StampID: stampID}).Error; err != nil {
+		return err
+	}
+	if len(aliases) == 0 {
+		return nil
+	}
+	records := make([]*model.StampAlias, len(aliases))
+	for i, alias := range aliases {
+		records[i] = &model.StampAlias{Alias: alias, StampID: stampID}
+	}
+	return tx.Create(records).Error
+}
+
+func replaceStampTags(tx *gorm.DB, stampID uuid.UUID, tags []string) error {
+	if len(tags) > model.MaxStampTags {
+		return repository.ArgError("args.Tags", "too many tags")
+	}
+	records := make([]*model.StampTag, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		if err := vd.Validate(tag, validator.StampTagRuleRequired...); err != nil {
+			return repository.ArgError("args.Tags", "Tag must be 1-30 characters")
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		records = append(records, &model.StampTag{StampID: stampID, Tag: tag})
+	}
+
+	if err := tx.Delete(&model.StampTag{}, &model.StampTag{StampID: stampID}).Error; err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	return tx.Create(records).Error
+}
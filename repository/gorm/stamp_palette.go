@@ -9,6 +9,7 @@ import (
 	"github.com/traPtitech/traQ/event"
 	"github.com/traPtitech/traQ/model"
 	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/utils/gormutil"
 	"github.com/traPtitech/traQ/utils/validator"
 )
 
@@ -23,6 +24,7 @@ func (repo *Repository) CreateStampPalette(name, description string, stamps mode
 		Description: description,
 		Stamps:      stamps,
 		CreatorID:   userID,
+		Visibility:  model.StampPaletteVisibilityPrivate,
 	}
 
 	err = repo.db.Transaction(func(tx *gorm.DB) error {
@@ -67,13 +69,17 @@ func (repo *Repository) UpdateStampPalette(id uuid.UUID, args repository.UpdateS
 	if id == uuid.Nil {
 		return repository.ErrNilID
 	}
-	var userID uuid.UUID
+	var (
+		userID        uuid.UUID
+		subscriberIDs []uuid.UUID
+	)
 	changes := map[string]interface{}{}
 	err := repo.db.Transaction(func(tx *gorm.DB) error {
 		var sp model.StampPalette
 		if err := tx.First(&sp, &model.StampPalette{ID: id}).Error; err != nil {
 			return convertError(err)
 		}
+		userID = sp.CreatorID
 
 		if args.Name.Valid {
 			if err := vd.Validate(args.Name.String, validator.StampNameRuleRequired...); err != nil {
@@ -97,12 +103,28 @@ func (repo *Repository) UpdateStampPalette(id uuid.UUID, args repository.UpdateS
 			}
 			changes["stamps"] = args.Stamps
 		}
+		if args.Visibility.Valid {
+			if !model.StampPaletteVisibility(args.Visibility.String).Valid() {
+				return repository.ArgError("args.Visibility", "Visibility must be private or public")
+			}
+			changes["visibility"] = args.Visibility.String
+		}
 
-		if len(changes) > 0 {
-			return tx.Model(&sp).Updates(changes).Error
+		if len(changes) == 0 {
+			return nil
 		}
-		userID = sp.CreatorID
-		return nil
+		var err error
+		subscriberIDs, err = getStampPaletteSubscriberIDs(tx, id)
+		if err != nil {
+			return err
+		}
+		if model.StampPaletteVisibility(args.Visibility.String) == model.StampPaletteVisibilityPrivate {
+			// 非公開にした場合は購読を解除
+			if err := tx.Delete(&model.StampPaletteSubscription{}, &model.StampPaletteSubscription{PaletteID: id}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&sp).Updates(changes).Error
 	})
 	if err != nil {
 		return err
@@ -113,6 +135,7 @@ func (repo *Repository) UpdateStampPalette(id uuid.UUID, args repository.UpdateS
 			Fields: hub.Fields{
 				"user_id":          userID,
 				"stamp_palette_id": id,
+				"subscriber_ids":   subscriberIDs,
 			},
 		})
 	}
@@ -140,6 +163,11 @@ func (repo *Repository) DeleteStampPalette(id uuid.UUID) (err error) {
 	if err != nil {
 		return err
 	}
+	subscriberIDs, err := getStampPaletteSubscriberIDs(repo.db, id)
+	if err != nil {
+		return err
+	}
+	// 購読はFKによりカスケード削除される
 	result := repo.db.Delete(&model.StampPalette{ID: id})
 	if result.Error != nil {
 		return result.Error
@@ -150,6 +178,7 @@ func (repo *Repository) DeleteStampPalette(id uuid.UUID) (err error) {
 			Fields: hub.Fields{
 				"user_id":          stampPalette.CreatorID,
 				"stamp_palette_id": id,
+				"subscriber_ids":   subscriberIDs,
 			},
 		})
 		return nil
@@ -163,3 +192,84 @@ func (repo *Repository) GetStampPalettes(userID uuid.UUID) (sps []*model.StampPa
 	tx := repo.db
 	return sps, tx.Where("creator_id = ?", userID).Find(&sps).Error
 }
+
+// GetPublicStampPalettes implements StampPaletteRepository interface.
+func (repo *Repository) GetPublicStampPalettes() (sps []*model.StampPalette, err error) {
+	sps = make([]*model.StampPalette, 0)
+	return sps, repo.db.Where(&model.StampPalette{Visibility: model.StampPaletteVisibilityPublic}).Find(&sps).Error
+}
+
+// SubscribeStampPalette implements StampPaletteRepository interface.
+func (repo *Repository) SubscribeStampPalette(userID, paletteID uuid.UUID) error {
+	if userID == uuid.Nil || paletteID == uuid.Nil {
+		return repository.ErrNilID
+	}
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var sp model.StampPalette
+		if err := tx.Take(&sp, &model.StampPalette{ID: paletteID}).Error; err != nil {
+			return convertError(err)
+		}
+		if !sp.IsPublic() {
+			return repository.ArgError("paletteID", "the stamp palette is not public")
+		}
+		if sp.CreatorID == userID {
+			return repository.ArgError("paletteID", "you cannot subscribe your own stamp palette")
+		}
+
+		var s model.StampPaletteSubscription
+		return tx.FirstOrCreate(&s, &model.StampPaletteSubscription{UserID: userID, PaletteID: paletteID}).Error
+	})
+	if err != nil {
+		if gormutil.IsMySQLDuplicatedRecordErr(err) {
+			return nil
+		}
+		return err
+	}
+	repo.hub.Publish(hub.Message{
+		Name: event.StampPaletteSubscribed,
+		Fields: hub.Fields{
+			"user_id":          userID,
+			"stamp_palette_id": paletteID,
+		},
+	})
+	return nil
+}
+
+// UnsubscribeStampPalette implements StampPaletteRepository interface.
+func (repo *Repository) UnsubscribeStampPalette(userID, paletteID uuid.UUID) error {
+	if userID == uuid.Nil || paletteID == uuid.Nil {
+		return repository.ErrNilID
+	}
+	result := repo.db.Delete(&model.StampPaletteSubscription{}, &model.StampPaletteSubscription{UserID: userID, PaletteID: paletteID})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		repo.hub.Publish(hub.Message{
+			Name: event.StampPaletteUnsubscribed,
+			Fields: hub.Fields{
+				"user_id":          userID,
+				"stamp_palette_id": paletteID,
+			},
+		})
+	}
+	return nil
+}
+
+// GetSubscribedStampPalettes implements StampPaletteRepository interface.
+func (repo *Repository) GetSubscribedStampPalettes(userID uuid.UUID) (sps []*model.StampPalette, err error) {
+	sps = make([]*model.StampPalette, 0)
+	if userID == uuid.Nil {
+		return sps, nil
+	}
+	return sps, repo.db.
+		Joins("JOIN stamp_palette_subscriptions ON stamp_palette_subscriptions.palette_id = stamp_palettes.id").
+		Where("stamp_palette_subscriptions.user_id = ?", userID).
+		Find(&sps).
+		Error
+}
+
+func getStampPaletteSubscriberIDs(tx *gorm.DB, paletteID uuid.UUID) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0)
+	return ids, tx.Model(&model.StampPaletteSubscription{}).Where(&model.StampPaletteSubscription{PaletteID: paletteID}).Pluck("user_id", &ids).Error
+}
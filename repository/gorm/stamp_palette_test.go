@@ -5,7 +5,9 @@ import (
 
 	"github.com/gofrs/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/traPtitech/traQ/model"
 	"github.com/traPtitech/traQ/repository"
 	"github.com/traPtitech/traQ/utils/optional"
 	random2 "github.com/traPtitech/traQ/utils/random"
@@ -94,6 +96,33 @@ func TestRepositoryImpl_UpdateStampPalette(t *testing.T) {
 			assert.Equal(newName, newStampPalette.Name)
 		}
 	})
+
+	t.Run("invalid visibility", func(t *testing.T) {
+		t.Parallel()
+
+		err := repo.UpdateStampPalette(stampPalette.ID, repository.UpdateStampPaletteArgs{Visibility: optional.StringFrom("internal")})
+		assert.True(t, repository.IsArgError(err))
+	})
+
+	t.Run("success (make private)", func(t *testing.T) {
+		t.Parallel()
+		assert, require := assertAndRequire(t)
+		subscriber := mustMakeUser(t, repo, rand)
+
+		stampPalette := mustMakeStampPalette(t, repo, rand, rand, make([]uuid.UUID, 0), user.GetID())
+		require.NoError(repo.UpdateStampPalette(stampPalette.ID, repository.UpdateStampPaletteArgs{Visibility: optional.StringFrom(string(model.StampPaletteVisibilityPublic))}))
+		require.NoError(repo.SubscribeStampPalette(subscriber.GetID(), stampPalette.ID))
+
+		if assert.NoError(repo.UpdateStampPalette(stampPalette.ID, repository.UpdateStampPaletteArgs{Visibility: optional.StringFrom(string(model.StampPaletteVisibilityPrivate))})) {
+			newStampPalette, err := repo.GetStampPalette(stampPalette.ID)
+			require.NoError(err)
+			assert.Equal(model.StampPaletteVisibilityPrivate, newStampPalette.Visibility)
+
+			sps, err := repo.GetSubscribedStampPalettes(subscriber.GetID())
+			require.NoError(err)
+			assert.Len(sps, 0)
+		}
+	})
 }
 
 func TestRepositoryImpl_GetStampPalette(t *testing.T) {
@@ -180,3 +209,111 @@ func TestRepositoryImpl_GetStampPalettes(t *testing.T) {
 		}
 	}
 }
+
+func TestRepositoryImpl_GetPublicStampPalettes(t *testing.T) {
+	t.Parallel()
+	repo, assert, require, user := setupWithUser(t, common2)
+
+	public := mustMakeStampPalette(t, repo, rand, rand, make([]uuid.UUID, 0), user.GetID())
+	require.NoError(repo.UpdateStampPalette(public.ID, repository.UpdateStampPaletteArgs{Visibility: optional.StringFrom(string(model.StampPaletteVisibilityPublic))}))
+	private := mustMakeStampPalette(t, repo, rand, rand, make([]uuid.UUID, 0), user.GetID())
+
+	arr, err := repo.GetPublicStampPalettes()
+	if assert.NoError(err) {
+		ids := make([]uuid.UUID, len(arr))
+		for i, sp := range arr {
+			assert.Equal(model.StampPaletteVisibilityPublic, sp.Visibility)
+			ids[i] = sp.ID
+		}
+		assert.Contains(ids, public.ID)
+		assert.NotContains(ids, private.ID)
+	}
+}
+
+func TestRepositoryImpl_SubscribeStampPalette(t *testing.T) {
+	t.Parallel()
+	repo, _, _, user := setupWithUser(t, common2)
+	other := mustMakeUser(t, repo, rand)
+
+	public := mustMakeStampPalette(t, repo, rand, rand, make([]uuid.UUID, 0), other.GetID())
+	require.NoError(t, repo.UpdateStampPalette(public.ID, repository.UpdateStampPaletteArgs{Visibility: optional.StringFrom(string(model.StampPaletteVisibilityPublic))}))
+	private := mustMakeStampPalette(t, repo, rand, rand, make([]uuid.UUID, 0), other.GetID())
+	own := mustMakeStampPalette(t, repo, rand, rand, make([]uuid.UUID, 0), user.GetID())
+	require.NoError(t, repo.UpdateStampPalette(own.ID, repository.UpdateStampPaletteArgs{Visibility: optional.StringFrom(string(model.StampPaletteVisibilityPublic))}))
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.SubscribeStampPalette(uuid.Nil, public.ID), repository.ErrNilID.Error())
+		assert.EqualError(t, repo.SubscribeStampPalette(user.GetID(), uuid.Nil), repository.ErrNilID.Error())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.SubscribeStampPalette(user.GetID(), uuid.Must(uuid.NewV4())), repository.ErrNotFound.Error())
+	})
+
+	t.Run("private", func(t *testing.T) {
+		t.Parallel()
+
+		assert.True(t, repository.IsArgError(repo.SubscribeStampPalette(user.GetID(), private.ID)))
+	})
+
+	t.Run("own", func(t *testing.T) {
+		t.Parallel()
+
+		assert.True(t, repository.IsArgError(repo.SubscribeStampPalette(user.GetID(), own.ID)))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+		subscriber := mustMakeUser(t, repo, rand)
+
+		if assert.NoError(repo.SubscribeStampPalette(subscriber.GetID(), public.ID)) {
+			// 既に購読している場合も成功する
+			assert.NoError(repo.SubscribeStampPalette(subscriber.GetID(), public.ID))
+
+			sps, err := repo.GetSubscribedStampPalettes(subscriber.GetID())
+			if assert.NoError(err) && assert.Len(sps, 1) {
+				assert.Equal(public.ID, sps[0].ID)
+			}
+		}
+	})
+}
+
+func TestRepositoryImpl_UnsubscribeStampPalette(t *testing.T) {
+	t.Parallel()
+	repo, _, _, user := setupWithUser(t, common2)
+	other := mustMakeUser(t, repo, rand)
+
+	public := mustMakeStampPalette(t, repo, rand, rand, make([]uuid.UUID, 0), other.GetID())
+	require.NoError(t, repo.UpdateStampPalette(public.ID, repository.UpdateStampPaletteArgs{Visibility: optional.StringFrom(string(model.StampPaletteVisibilityPublic))}))
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+
+		assert.EqualError(t, repo.UnsubscribeStampPalette(uuid.Nil, public.ID), repository.ErrNilID.Error())
+	})
+
+	t.Run("not subscribed", func(t *testing.T) {
+		t.Parallel()
+
+		assert.NoError(t, repo.UnsubscribeStampPalette(user.GetID(), uuid.Must(uuid.NewV4())))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+		subscriber := mustMakeUser(t, repo, rand)
+
+		require.NoError(t, repo.SubscribeStampPalette(subscriber.GetID(), public.ID))
+		if assert.NoError(repo.UnsubscribeStampPalette(subscriber.GetID(), public.ID)) {
+			sps, err := repo.GetSubscribedStampPalettes(subscriber.GetID())
+			if assert.NoError(err) {
+				assert.Len(sps, 0)
+			}
+		}
+	})
+}
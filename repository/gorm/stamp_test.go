@@ -5,6 +5,7 @@ import (
 
 	"github.com/gofrs/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/traPtitech/traQ/model"
 	"github.com/traPtitech/traQ/repository"
@@ -48,6 +49,17 @@ func TestRepositoryImpl_CreateStamp(t *testing.T) {
 		assert.Error(t, err)
 	})
 
+	t.Run("duplicate alias", func(t *testing.T) {
+		t.Parallel()
+		s := mustMakeStamp(t, repo, rand, uuid.Nil)
+		alias := random2.AlphaNumeric(20)
+		require.NoError(t, repo.UpdateStamp(s.ID, repository.UpdateStampArgs{Aliases: []string{alias}}))
+		fid := mustMakeDummyFile(t, repo).ID
+
+		_, err := repo.CreateStamp(repository.CreateStampArgs{Name: alias, FileID: fid, CreatorID: user.GetID()})
+		assert.EqualError(t, err, repository.ErrAlreadyExists.Error())
+	})
+
 	t.Run("success", func(t *testing.T) {
 		t.Parallel()
 		assert := assert.New(t)
@@ -64,6 +76,24 @@ func TestRepositoryImpl_CreateStamp(t *testing.T) {
 			assert.False(s.DeletedAt.Valid)
 		}
 	})
+
+	t.Run("success (aliases, tags)", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		name := random2.AlphaNumeric(20)
+		alias := random2.AlphaNumeric(20)
+		s, err := repo.CreateStamp(repository.CreateStampArgs{Name: name, FileID: fid, CreatorID: user.GetID(), Aliases: []string{alias}, Tags: []string{"b", "a", "b"}})
+		if assert.NoError(err) {
+			assert.Equal([]string{alias}, s.Aliases)
+			assert.Equal([]string{"a", "b"}, s.Tags)
+
+			s, err := repo.GetStampByAlias(alias)
+			if assert.NoError(err) {
+				assert.Equal(name, s.Name)
+			}
+		}
+	})
 }
 
 func TestRepositoryImpl_UpdateStamp(t *testing.T) {
@@ -134,6 +164,88 @@ func TestRepositoryImpl_UpdateStamp(t *testing.T) {
 			assert.Equal(newName, a.Name)
 		}
 	})
+	t.Run("invalid alias", func(t *testing.T) {
+		t.Parallel()
+
+		err := repo.UpdateStamp(s.ID, repository.UpdateStampArgs{Aliases: []string{"あ"}})
+		assert.True(t, repository.IsArgError(err))
+	})
+
+	t.Run("duplicate alias", func(t *testing.T) {
+		t.Parallel()
+		s1 := mustMakeStamp(t, repo, rand, uuid.Nil)
+		s2 := mustMakeStamp(t, repo, rand, uuid.Nil)
+		alias := random2.AlphaNumeric(20)
+		require.NoError(t, repo.UpdateStamp(s1.ID, repository.UpdateStampArgs{Aliases: []string{alias}}))
+
+		assert.EqualError(t, repo.UpdateStamp(s2.ID, repository.UpdateStampArgs{Aliases: []string{alias}}), repository.ErrAlreadyExists.Error())
+		assert.EqualError(t, repo.UpdateStamp(s2.ID, repository.UpdateStampArgs{Aliases: []string{s1.Name}}), repository.ErrAlreadyExists.Error())
+		assert.EqualError(t, repo.UpdateStamp(s2.ID, repository.UpdateStampArgs{Name: optional.StringFrom(alias)}), repository.ErrAlreadyExists.Error())
+	})
+
+	t.Run("success (category, aliases, tags)", func(t *testing.T) {
+		t.Parallel()
+		assert, require := assertAndRequire(t)
+
+		s := mustMakeStamp(t, repo, rand, uuid.Nil)
+		alias1 := random2.AlphaNumeric(20)
+		alias2 := random2.AlphaNumeric(20)
+
+		if assert.NoError(repo.UpdateStamp(s.ID, repository.UpdateStampArgs{
+			Category: optional.StringFrom("food"),
+			Aliases:  []string{alias1, alias2},
+			Tags:     []string{"sweet", "sweet", "yellow"},
+		})) {
+			a, err := repo.GetStamp(s.ID)
+			require.NoError(err)
+			assert.Equal("food", a.Category)
+			assert.ElementsMatch([]string{alias1, alias2}, a.Aliases)
+			assert.ElementsMatch([]string{"sweet", "yellow"}, a.Tags)
+		}
+
+		if assert.NoError(repo.UpdateStamp(s.ID, repository.UpdateStampArgs{Aliases: []string{alias2}})) {
+			a, err := repo.GetStamp(s.ID)
+			require.NoError(err)
+			assert.Equal([]string{alias2}, a.Aliases)
+			assert.ElementsMatch([]string{"sweet", "yellow"}, a.Tags)
+		}
+	})
+}
+
+func TestRepositoryImpl_GetStampByAlias(t *testing.T) {
+	t.Parallel()
+	repo, _, _ := setup(t, common2)
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := repo.GetStampByAlias("")
+		assert.EqualError(t, err, repository.ErrNotFound.Error())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := repo.GetStampByAlias(random2.AlphaNumeric(20))
+		assert.EqualError(t, err, repository.ErrNotFound.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		s := mustMakeStamp(t, repo, rand, uuid.Nil)
+		alias := random2.AlphaNumeric(20)
+		require.NoError(t, repo.UpdateStamp(s.ID, repository.UpdateStampArgs{Aliases: []string{alias}}))
+
+		a, err := repo.GetStampByAlias(alias)
+		if assert.NoError(t, err) {
+			assert.Equal(t, s.ID, a.ID)
+			assert.Equal(t, s.Name, a.Name)
+		}
+
+		// スタンプ名はエイリアスとして扱わない
+		_, err = repo.GetStampByAlias(s.Name)
+		assert.EqualError(t, err, repository.ErrNotFound.Error())
+	})
 }
 
 func TestRepositoryImpl_GetStamp(t *testing.T) {
@@ -356,3 +468,29 @@ func TestGormRepository_GetStampStats(t *testing.T) {
 	})
 
 }
+
+func TestGormRepository_GetAllStampStats(t *testing.T) {
+	t.Parallel()
+	repo, _, _ := setup(t, ex1)
+
+	channel := mustMakeChannel(t, repo, rand)
+	user := mustMakeUser(t, repo, rand)
+	stamp := mustMakeStamp(t, repo, rand, user.GetID())
+	unused := mustMakeStamp(t, repo, rand, user.GetID())
+
+	for i := 0; i < 5; i++ {
+		m := mustMakeMessage(t, repo, user.GetID(), channel.ID)
+		for j := 0; j < 2; j++ {
+			mustAddMessageStamp(t, repo, m.ID, stamp.ID, user.GetID())
+		}
+	}
+
+	stats, err := repo.GetAllStampStats()
+	if assert.NoError(t, err) {
+		if assert.Contains(t, stats, stamp.ID) {
+			assert.EqualValues(t, 5, stats[stamp.ID].Count)
+			assert.EqualValues(t, 10, stats[stamp.ID].TotalCount)
+		}
+		assert.NotContains(t, stats, unused.ID)
+	}
+}
@@ -0,0 +1,269 @@
+package gorm
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/utils/gormutil"
+	"github.com/traPtitech/traQ/utils/optional"
+)
+
+// GetUserTOTP implements TwoFactorRepository interface.
+func (repo *Repository) GetUserTOTP(userID uuid.UUID) (*model.UserTOTP, error) {
+	if userID == uuid.Nil {
+		return nil, repository.ErrNotFound
+	}
+	var t model.UserTOTP
+	if err := repo.db.First(&t, &model.UserTOTP{UserID: userID}).Error; err != nil {
+		return nil, convertError(err)
+	}
+	return &t, nil
+}
+
+// SaveUserTOTP implements TwoFactorRepository interface.
+func (repo *Repository) SaveUserTOTP(userID uuid.UUID, secret string) error {
+	if userID == uuid.Nil {
+		return repository.ErrNilID
+	}
+	return repo.db.
+		Clauses(clause.OnConflict{
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"secret":     secret,
+				"enabled":    false,
+				"updated_at": gorm.Expr("now()"),
+			}),
+		}).
+		Create(&model.UserTOTP{UserID: userID, Secret: secret, Enabled: false}).
+		Error
+}
+
+// EnableUserTOTP implements TwoFactorRepository interface.
+func (repo *Repository) EnableUserTOTP(userID uuid.UUID) error {
+	if userID == uuid.Nil {
+		return repository.ErrNilID
+	}
+	result := repo.db.Model(&model.UserTOTP{UserID: userID}).Update("enabled", true)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// DeleteUserTOTP implements TwoFactorRepository interface.
+func (repo *Repository) DeleteUserTOTP(userID uuid.UUID) error {
+	if userID == uuid.Nil {
+		return repository.ErrNilID
+	}
+	result := repo.db.Delete(&model.UserTOTP{UserID: userID})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// UseUserTOTPStep implements TwoFactorRepository interface.
+func (repo *Repository) UseUserTOTPStep(userID uuid.UUID, step int64) error {
+	if userID == uuid.Nil {
+		return repository.ErrNilID
+	}
+	result := repo.db.
+		Model(&model.UserTOTP{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+	if _, err := repo.GetUserTOTP(userID); err != nil {
+		return err
+	}
+	return repository.ErrAlreadyExists
+}
+
+// GetTwoFactorLockout implements TwoFactorRepository interface.
+func (repo *Repository) GetTwoFactorLockout(userID uuid.UUID) (*model.UserTwoFactorLockout, error) {
+	if userID == uuid.Nil {
+		return nil, repository.ErrNotFound
+	}
+	var l model.UserTwoFactorLockout
+	if err := repo.db.First(&l, &model.UserTwoFactorLockout{UserID: userID}).Error; err != nil {
+		return nil, convertError(err)
+	}
+	return &l, nil
+}
+
+// RecordTwoFactorFailure implements TwoFactorRepository interface.
+func (repo *Repository) RecordTwoFactorFailure(userID uuid.UUID, maxFailures int, lockDuration time.Duration) (*model.UserTwoFactorLockout, error) {
+	if userID == uuid.Nil {
+		return nil, repository.ErrNilID
+	}
+	var l model.UserTwoFactorLockout
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&l, &model.UserTwoFactorLockout{UserID: userID}).Error
+		switch {
+		case err == nil:
+		case err == gorm.ErrRecordNotFound:
+			l = model.UserTwoFactorLockout{UserID: userID}
+		default:
+			return err
+		}
+
+		l.Failures++
+		if l.Failures >= maxFailures {
+			l.Failures = 0
+			l.LockedUntil = optional.TimeFrom(time.Now().Add(lockDuration))
+		}
+		return tx.Save(&l).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// ResetTwoFactorFailures implements TwoFactorRepository interface.
+func (repo *Repository) ResetTwoFactorFailures(userID uuid.UUID) error {
+	if userID == uuid.Nil {
+		return nil
+	}
+	return repo.db.Delete(&model.UserTwoFactorLockout{UserID: userID}).Error
+}
+
+// ReplaceRecoveryCodes implements TwoFactorRepository interface.
+func (repo *Repository) ReplaceRecoveryCodes(userID uuid.UUID, codeHashes []string) error {
+	if userID == uuid.Nil {
+		return repository.ErrNilID
+	}
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.UserRecoveryCode{}, &model.UserRecoveryCode{UserID: userID}).Error; err != nil {
+			return err
+		}
+		if len(codeHashes) == 0 {
+			return nil
+		}
+
+		codes := make([]*model.UserRecoveryCode, len(codeHashes))
+		for i, hash := range codeHashes {
+			codes[i] = &model.UserRecoveryCode{UserID: userID, CodeHash: hash}
+		}
+		return tx.Create(codes).Error
+	})
+}
+
+// GetRecoveryCodeCount implements TwoFactorRepository interface.
+func (repo *Repository) GetRecoveryCodeCount(userID uuid.UUID) (int, error) {
+	if userID == uuid.Nil {
+		return 0, nil
+	}
+	var count int64
+	err := repo.db.Model(&model.UserRecoveryCode{}).Where(&model.UserRecoveryCode{UserID: userID}).Count(&count).Error
+	return int(count), err
+}
+
+// UseRecoveryCode implements TwoFactorRepository interface.
+func (repo *Repository) UseRecoveryCode(userID uuid.UUID, codeHash string) error {
+	if userID == uuid.Nil || len(codeHash) == 0 {
+		return repository.ErrNotFound
+	}
+	result := repo.db.Delete(&model.UserRecoveryCode{UserID: userID, CodeHash: codeHash})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// CreateWebAuthnCredential implements TwoFactorRepository interface.
+func (repo *Repository) CreateWebAuthnCredential(cred *model.WebAuthnCredential) error {
+	if cred.UserID == uuid.Nil {
+		return repository.ErrNilID
+	}
+	if cred.ID == uuid.Nil {
+		cred.ID = uuid.Must(uuid.NewV4())
+	}
+	if err := repo.db.Create(cred).Error; err != nil {
+		if gormutil.IsMySQLDuplicatedRecordErr(err) {
+			return repository.ErrAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+// GetWebAuthnCredentials implements TwoFactorRepository interface.
+func (repo *Repository) GetWebAuthnCredentials(userID uuid.UUID) ([]*model.WebAuthnCredential, error) {
+	creds := make([]*model.WebAuthnCredential, 0)
+	if userID == uuid.Nil {
+		return creds, nil
+	}
+	return creds, repo.db.Where(&model.WebAuthnCredential{UserID: userID}).Order("created_at").Find(&creds).Error
+}
+
+// UpdateWebAuthnCredentialSignCount implements TwoFactorRepository interface.
+func (repo *Repository) UpdateWebAuthnCredentialSignCount(id uuid.UUID, signCount uint32) error {
+	if id == uuid.Nil {
+		return repository.ErrNilID
+	}
+	result := repo.db.Model(&model.WebAuthnCredential{ID: id}).Update("sign_count", signCount)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// DeleteWebAuthnCredential implements TwoFactorRepository interface.
+func (repo *Repository) DeleteWebAuthnCredential(userID, id uuid.UUID) error {
+	if userID == uuid.Nil || id == uuid.Nil {
+		return repository.ErrNilID
+	}
+	result := repo.db.Where(&model.WebAuthnCredential{UserID: userID}).Delete(&model.WebAuthnCredential{ID: id})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// GetTwoFactorRequiredRoles implements TwoFactorRepository interface.
+func (repo *Repository) GetTwoFactorRequiredRoles() ([]string, error) {
+	roles := make([]string, 0)
+	return roles, repo.db.Model(&model.TwoFactorRequiredRole{}).Order("role").Pluck("role", &roles).Error
+}
+
+// SetTwoFactorRequiredRoles implements TwoFactorRepository interface.
+func (repo *Repository) SetTwoFactorRequiredRoles(roles []string) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&model.TwoFactorRequiredRole{}).Error; err != nil {
+			return err
+		}
+		if len(roles) == 0 {
+			return nil
+		}
+
+		records := make([]*model.TwoFactorRequiredRole, len(roles))
+		for i, role := range roles {
+			records[i] = &model.TwoFactorRequiredRole{Role: role}
+		}
+		return tx.Create(records).Error
+	})
+}
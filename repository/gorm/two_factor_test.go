@@ -0,0 +1,144 @@
+package gorm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+)
+
+func TestRepositoryImpl_UserTOTP(t *testing.T) {
+	t.Parallel()
+	repo, assert, require := setup(t, common)
+
+	userID := mustMakeUser(t, repo, rand).GetID()
+
+	_, err := repo.GetUserTOTP(userID)
+	assert.EqualError(err, repository.ErrNotFound.Error())
+	assert.EqualError(repo.EnableUserTOTP(userID), repository.ErrNotFound.Error())
+
+	require.NoError(repo.SaveUserTOTP(userID, "secret1"))
+	require.NoError(repo.SaveUserTOTP(userID, "secret2"))
+	totp, err := repo.GetUserTOTP(userID)
+	if assert.NoError(err) {
+		assert.Equal("secret2", totp.Secret)
+		assert.False(totp.Enabled)
+	}
+
+	require.NoError(repo.EnableUserTOTP(userID))
+	totp, err = repo.GetUserTOTP(userID)
+	if assert.NoError(err) {
+		assert.True(totp.Enabled)
+	}
+
+	assert.NoError(repo.UseUserTOTPStep(userID, 100))
+	assert.EqualError(repo.UseUserTOTPStep(userID, 100), repository.ErrAlreadyExists.Error())
+	assert.EqualError(repo.UseUserTOTPStep(userID, 99), repository.ErrAlreadyExists.Error())
+	assert.NoError(repo.UseUserTOTPStep(userID, 101))
+
+	assert.NoError(repo.DeleteUserTOTP(userID))
+	assert.EqualError(repo.DeleteUserTOTP(userID), repository.ErrNotFound.Error())
+	assert.EqualError(repo.UseUserTOTPStep(userID, 102), repository.ErrNotFound.Error())
+	assert.EqualError(repo.SaveUserTOTP(uuid.Nil, "secret"), repository.ErrNilID.Error())
+}
+
+func TestRepositoryImpl_TwoFactorLockout(t *testing.T) {
+	t.Parallel()
+	repo, assert, require := setup(t, common)
+
+	userID := mustMakeUser(t, repo, rand).GetID()
+
+	_, err := repo.GetTwoFactorLockout(userID)
+	assert.EqualError(err, repository.ErrNotFound.Error())
+
+	for i := 1; i < 3; i++ {
+		l, err := repo.RecordTwoFactorFailure(userID, 3, time.Minute)
+		require.NoError(err)
+		assert.Equal(i, l.Failures)
+		assert.False(l.IsLocked(time.Now()))
+	}
+	l, err := repo.RecordTwoFactorFailure(userID, 3, time.Minute)
+	require.NoError(err)
+	assert.Equal(0, l.Failures)
+	assert.True(l.IsLocked(time.Now()))
+
+	l, err = repo.GetTwoFactorLockout(userID)
+	if assert.NoError(err) {
+		assert.True(l.IsLocked(time.Now()))
+		assert.False(l.IsLocked(time.Now().Add(2 * time.Minute)))
+	}
+
+	require.NoError(repo.ResetTwoFactorFailures(userID))
+	_, err = repo.GetTwoFactorLockout(userID)
+	assert.EqualError(err, repository.ErrNotFound.Error())
+	_, err = repo.RecordTwoFactorFailure(uuid.Nil, 3, time.Minute)
+	assert.EqualError(err, repository.ErrNilID.Error())
+}
+
+func TestRepositoryImpl_RecoveryCodes(t *testing.T) {
+	t.Parallel()
+	repo, assert, require := setup(t, common)
+
+	userID := mustMakeUser(t, repo, rand).GetID()
+
+	require.NoError(repo.ReplaceRecoveryCodes(userID, []string{model.HashRecoveryCode("a"), model.HashRecoveryCode("b")}))
+	n, err := repo.GetRecoveryCodeCount(userID)
+	if assert.NoError(err) {
+		assert.Equal(2, n)
+	}
+
+	require.NoError(repo.ReplaceRecoveryCodes(userID, []string{model.HashRecoveryCode("c"), model.HashRecoveryCode("d"), model.HashRecoveryCode("e")}))
+	assert.EqualError(repo.UseRecoveryCode(userID, model.HashRecoveryCode("a")), repository.ErrNotFound.Error())
+	assert.NoError(repo.UseRecoveryCode(userID, model.HashRecoveryCode("c")))
+	assert.EqualError(repo.UseRecoveryCode(userID, model.HashRecoveryCode("c")), repository.ErrNotFound.Error())
+
+	n, err = repo.GetRecoveryCodeCount(userID)
+	if assert.NoError(err) {
+		assert.Equal(2, n)
+	}
+
+	require.NoError(repo.ReplaceRecoveryCodes(userID, nil))
+	n, err = repo.GetRecoveryCodeCount(userID)
+	if assert.NoError(err) {
+		assert.Equal(0, n)
+	}
+}
+
+func TestRepositoryImpl_WebAuthnCredentials(t *testing.T) {
+	t.Parallel()
+	repo, assert, require := setup(t, common)
+
+	userID := mustMakeUser(t, repo, rand).GetID()
+	cred := &model.WebAuthnCredential{
+		UserID:       userID,
+		Name:         "key",
+		CredentialID: []byte("credential-id"),
+		PublicKey:    []byte("public-key"),
+		AAGUID:       make([]byte, 16),
+	}
+	require.NoError(repo.CreateWebAuthnCredential(cred))
+	assert.NotEqual(uuid.Nil, cred.ID)
+	assert.EqualError(repo.CreateWebAuthnCredential(&model.WebAuthnCredential{
+		UserID:       userID,
+		Name:         "dup",
+		CredentialID: []byte("credential-id"),
+		PublicKey:    []byte("public-key"),
+		AAGUID:       make([]byte, 16),
+	}), repository.ErrAlreadyExists.Error())
+
+	require.NoError(repo.UpdateWebAuthnCredentialSignCount(cred.ID, 3))
+	creds, err := repo.GetWebAuthnCredentials(userID)
+	if assert.NoError(err) && assert.Len(creds, 1) {
+		assert.EqualValues(3, creds[0].SignCount)
+	}
+
+	assert.EqualError(repo.DeleteWebAuthnCredential(uuid.Must(uuid.NewV4()), cred.ID), repository.ErrNotFound.Error())
+	assert.NoError(repo.DeleteWebAuthnCredential(userID, cred.ID))
+	creds, err = repo.GetWebAuthnCredentials(userID)
+	if assert.NoError(err) {
+		assert.Len(creds, 0)
+	}
+}
@@ -33,6 +33,10 @@ func (repo *Repository) CreateUser(args repository.CreateUserArgs) (model.UserIn
 		Profile:     &model.UserProfile{UserID: uid},
 	}
 
+	if args.Deactivated {
+		user.Status = model.UserAccountStatusDeactivated
+	}
+
 	if len(args.Password) > 0 {
 		salt := random.Salt()
 		user.Password = hex.EncodeToString(utils.HashPassword(args.Password, salt))
@@ -346,6 +350,59 @@ func (repo *Repository) UnlinkExternalUserAccount(userID uuid.UUID, providerName
 	return nil
 }
 
+// ReplaceExternalUserAccount implements UserRepository interface.
+func (repo *Repository) ReplaceExternalUserAccount(userID uuid.UUID, args repository.LinkExternalUserAccountArgs) error {
+	if userID == uuid.Nil {
+		return repository.ErrNilID
+	}
+	if len(args.ProviderName) == 0 {
+		return repository.ArgError("args.ProviderName", "ProviderName must not be empty")
+	}
+	if len(args.ExternalID) == 0 {
+		return repository.ArgError("args.ExternalID", "ExternalID must not be empty")
+	}
+
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if exist, err := gormutil.RecordExists(tx, &model.User{ID: userID}); err != nil {
+			return err
+		} else if !exist {
+			return repository.ErrNotFound
+		}
+
+		var current model.ExternalProviderUser
+		if err := tx.First(&current, &model.ExternalProviderUser{ProviderName: args.ProviderName, ExternalID: args.ExternalID}).Error; err == nil {
+			if current.UserID != userID {
+				return repository.ErrAlreadyExists
+			}
+		} else if err != gorm.ErrRecordNotFound {
+			return err
+		}
+
+		if err := tx.Delete(model.ExternalProviderUser{}, &model.ExternalProviderUser{UserID: userID, ProviderName: args.ProviderName}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&model.ExternalProviderUser{
+			UserID:       userID,
+			ProviderName: args.ProviderName,
+			ExternalID:   args.ExternalID,
+			Extra:        args.Extra,
+		}).Error
+	})
+}
+
+// GetExternalUserAccountsByProvider implements UserRepository interface.
+func (repo *Repository) GetExternalUserAccountsByProvider(providerName string, userIDs []uuid.UUID) ([]*model.ExternalProviderUser, error) {
+	result := make([]*model.ExternalProviderUser, 0)
+	if len(providerName) == 0 || len(userIDs) == 0 {
+		return result, nil
+	}
+	return result, repo.db.
+		Where(&model.ExternalProviderUser{ProviderName: providerName}).
+		Where("user_id IN ?", userIDs).
+		Find(&result).
+		Error
+}
+
 // GetUserStats implements UserRepository interface
 func (repo *Repository) GetUserStats(userID uuid.UUID) (*repository.UserStats, error) {
 	if userID == uuid.Nil {
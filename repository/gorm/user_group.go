@@ -15,6 +15,11 @@ import (
 
 // CreateUserGroup implements UserGroupRepository interface.
 func (repo *Repository) CreateUserGroup(name, description, gType string, adminID, iconFileID uuid.UUID) (*model.UserGroup, error) {
+	return repo.CreateUserGroupWithMembers(name, description, gType, adminID, iconFileID, nil)
+}
+
+// CreateUserGroupWithMembers implements UserGroupRepository interface.
+func (repo *Repository) CreateUserGroupWithMembers(name, description, gType string, adminID, iconFileID uuid.UUID, memberIDs []uuid.UUID) (*model.UserGroup, error) {
 	g := &model.UserGroup{
 		ID:          uuid.Must(uuid.NewV4()),
 		Name:        name,
@@ -28,12 +33,27 @@ func (repo *Repository) CreateUserGroup(name, description, gType string, adminID
 			return repository.ErrAlreadyExists
 		}
 
-		return tx.Create(&model.UserGroupAdmin{GroupID: g.ID, UserID: adminID}).Error
+		if err := tx.Create(&model.UserGroupAdmin{GroupID: g.ID, UserID: adminID}).Error; err != nil {
+			return err
+		}
+
+		g.Members = make([]*model.UserGroupMember, 0, len(memberIDs))
+		added := make(map[uuid.UUID]struct{}, len(memberIDs))
+		for _, id := range memberIDs {
+			if _, ok := added[id]; ok {
+				continue
+			}
+			added[id] = struct{}{}
+			g.Members = append(g.Members, &model.UserGroupMember{GroupID: g.ID, UserID: id})
+		}
+		if len(g.Members) > 0 {
+			return tx.Create(&g.Members).Error
+		}
+		return nil
 	})
 	if err != nil {
 		return nil, err
 	}
-	g.Members = make([]*model.UserGroupMember, 0)
 	g.Admins = []*model.UserGroupAdmin{{GroupID: g.ID, UserID: adminID}}
 	repo.hub.Publish(hub.Message{
 		Name: event.UserGroupCreated,
@@ -169,6 +189,26 @@ func (repo *Repository) GetUserBelongingGroupIDs(userID uuid.UUID) ([]uuid.UUID,
 	return groups, err
 }
 
+// GetUsersBelongingGroupIDs implements UserGroupRepository interface.
+func (repo *Repository) GetUsersBelongingGroupIDs(userIDs []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error) {
+	result := make(map[uuid.UUID][]uuid.UUID)
+	if len(userIDs) == 0 {
+		return result, nil
+	}
+	var members []*model.UserGroupMember
+	if err := repo.db.
+		Select("user_id", "group_id").
+		Where("user_id IN ?", userIDs).
+		Find(&members).
+		Error; err != nil {
+		return nil, err
+	}
+	for _, m := range members {
+		result[m.UserID] = append(result[m.UserID], m.GroupID)
+	}
+	return result, nil
+}
+
 // GetAllUserGroups implements UserGroupRepository interface.
 func (repo *Repository) GetAllUserGroups() ([]*model.UserGroup, error) {
 	groups := make([]*model.UserGroup, 0)
@@ -31,6 +31,39 @@ func TestRepositoryImpl_CreateUserGroup(t *testing.T) {
 	})
 }
 
+func TestRepositoryImpl_CreateUserGroupWithMembers(t *testing.T) {
+	t.Parallel()
+	repo, _, _, user := setupWithUser(t, common3)
+	file := mustMakeDummyFile(t, repo)
+	user2 := mustMakeUser(t, repo, rand)
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		g, err := repo.CreateUserGroupWithMembers(random2.AlphaNumeric(20), "", "", user.GetID(), file.ID, []uuid.UUID{user.GetID(), user2.GetID(), user2.GetID()})
+		if assert.NoError(t, err) {
+			assert.Len(t, g.Members, 2)
+
+			g, err := repo.GetUserGroup(g.ID)
+			require.NoError(t, err)
+			assert.True(t, g.IsMember(user.GetID()))
+			assert.True(t, g.IsMember(user2.GetID()))
+		}
+	})
+
+	t.Run("unknown member", func(t *testing.T) {
+		t.Parallel()
+
+		// メンバーの追加に失敗した場合はグループも作成されない
+		name := random2.AlphaNumeric(20)
+		_, err := repo.CreateUserGroupWithMembers(name, "", "", user.GetID(), file.ID, []uuid.UUID{user.GetID(), uuid.Must(uuid.NewV4())})
+		assert.Error(t, err)
+
+		_, err = repo.GetUserGroupByName(name)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+}
+
 func TestRepositoryImpl_UpdateUserGroup(t *testing.T) {
 	t.Parallel()
 	repo, _, _, user := setupWithUser(t, common3)
@@ -212,6 +245,41 @@ func TestRepositoryImpl_GetUserBelongingGroups(t *testing.T) {
 	})
 }
 
+func TestRepositoryImpl_GetUsersBelongingGroupIDs(t *testing.T) {
+	t.Parallel()
+	repo, _, _, user := setupWithUser(t, common3)
+
+	user2 := mustMakeUser(t, repo, rand)
+	user3 := mustMakeUser(t, repo, rand)
+	g1 := mustMakeUserGroup(t, repo, rand, user.GetID())
+	g2 := mustMakeUserGroup(t, repo, rand, user.GetID())
+
+	mustAddUserToGroup(t, repo, user.GetID(), g1.ID)
+	mustAddUserToGroup(t, repo, user.GetID(), g2.ID)
+	mustAddUserToGroup(t, repo, user2.GetID(), g1.ID)
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		gs, err := repo.GetUsersBelongingGroupIDs(nil)
+		if assert.NoError(t, err) {
+			assert.Empty(t, gs)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		gs, err := repo.GetUsersBelongingGroupIDs([]uuid.UUID{user.GetID(), user2.GetID(), user3.GetID()})
+		if assert.NoError(t, err) {
+			assert.Len(t, gs, 2)
+			assert.ElementsMatch(t, gs[user.GetID()], []uuid.UUID{g1.ID, g2.ID})
+			assert.ElementsMatch(t, gs[user2.GetID()], []uuid.UUID{g1.ID})
+			assert.NotContains(t, gs, user3.GetID())
+		}
+	})
+}
+
 func TestRepositoryImpl_GetAllUserGroups(t *testing.T) {
 	t.Parallel()
 	repo, assert, _, user := setupWithUser(t, ex1)
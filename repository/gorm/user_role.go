@@ -1,6 +1,14 @@
 package gorm
 
-import "github.com/traPtitech/traQ/model"
+import (
+	"github.com/leandro-lugaresi/hub"
+	"gorm.io/gorm"
+
+	"github.com/traPtitech/traQ/event"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/utils/gormutil"
+)
 
 // CreateUserRoles implements UserRoleRepository interface.
 func (repo *Repository) CreateUserRoles(roles ...*model.UserRole) error {
@@ -13,3 +21,117 @@ func (repo *Repository) GetAllUserRoles() ([]*model.UserRole, error) {
 	err := repo.db.Preload("Inheritances").Preload("Permissions").Find(&roles).Error
 	return roles, err
 }
+
+// GetUserRole implements UserRoleRepository interface.
+func (repo *Repository) GetUserRole(name string) (*model.UserRole, error) {
+	if len(name) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	var role model.UserRole
+	if err := repo.db.Preload("Inheritances").Preload("Permissions").Take(&role, &model.UserRole{Name: name}).Error; err != nil {
+		return nil, convertError(err)
+	}
+	return &role, nil
+}
+
+// CreateUserRole implements UserRoleRepository interface.
+func (repo *Repository) CreateUserRole(role *model.UserRole) error {
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		if exists, err := gormutil.RecordExists(tx, &model.UserRole{Name: role.Name}); err != nil {
+			return err
+		} else if exists {
+			return repository.ErrAlreadyExists
+		}
+		return tx.Omit("Inheritances.*").Create(role).Error
+	})
+	if err != nil {
+		return err
+	}
+	repo.hub.Publish(hub.Message{
+		Name: event.UserRoleCreated,
+		Fields: hub.Fields{
+			"role_name": role.Name,
+		},
+	})
+	return nil
+}
+
+// UpdateUserRole implements UserRoleRepository interface.
+func (repo *Repository) UpdateUserRole(name string, args repository.UpdateUserRoleArgs) error {
+	if len(name) == 0 {
+		return repository.ErrNotFound
+	}
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var role model.UserRole
+		if err := tx.Take(&role, &model.UserRole{Name: name}).Error; err != nil {
+			return convertError(err)
+		}
+
+		if args.Permissions != nil {
+			if err := tx.Delete(&model.RolePermission{}, &model.RolePermission{Role: name}).Error; err != nil {
+				return err
+			}
+			if len(args.Permissions) > 0 {
+				perms := make([]*model.RolePermission, len(args.Permissions))
+				for i, p := range args.Permissions {
+					perms[i] = &model.RolePermission{Role: name, Permission: p}
+				}
+				if err := tx.Create(perms).Error; err != nil {
+					return err
+				}
+			}
+		}
+		if args.Inheritances != nil {
+			inheritances := make([]*model.UserRole, len(args.Inheritances))
+			for i, r := range args.Inheritances {
+				inheritances[i] = &model.UserRole{Name: r}
+			}
+			if err := tx.Model(&role).Omit("Inheritances.*").Association("Inheritances").Replace(inheritances); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	repo.hub.Publish(hub.Message{
+		Name: event.UserRoleUpdated,
+		Fields: hub.Fields{
+			"role_name": name,
+		},
+	})
+	return nil
+}
+
+// DeleteUserRole implements UserRoleRepository interface.
+func (repo *Repository) DeleteUserRole(name string) error {
+	if len(name) == 0 {
+		return repository.ErrNotFound
+	}
+	err := repo.db.Transaction(func(tx *gorm.DB) error {
+		var role model.UserRole
+		if err := tx.Take(&role, &model.UserRole{Name: name}).Error; err != nil {
+			return convertError(err)
+		}
+		if role.System {
+			return repository.ErrForbidden
+		}
+		if assigned, err := gormutil.RecordExists(tx, &model.User{Role: name}); err != nil {
+			return err
+		} else if assigned {
+			return repository.ErrForbidden
+		}
+		return tx.Delete(&role).Error
+	})
+	if err != nil {
+		return err
+	}
+	repo.hub.Publish(hub.Message{
+		Name: event.UserRoleDeleted,
+		Fields: hub.Fields{
+			"role_name": name,
+		},
+	})
+	return nil
+}
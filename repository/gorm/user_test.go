@@ -122,6 +122,26 @@ func TestRepositoryImpl_GetUsers(t *testing.T) {
 	})
 }
 
+func TestRepositoryImpl_CreateUser(t *testing.T) {
+	t.Parallel()
+	repo, assert, require := setup(t, common2)
+
+	u, err := repo.CreateUser(repository.CreateUserArgs{Name: random2.AlphaNumeric(20), Role: "user"})
+	require.NoError(err)
+	assert.Equal(model.UserAccountStatusActive, u.GetState())
+
+	u, err = repo.CreateUser(repository.CreateUserArgs{Name: random2.AlphaNumeric(20), Role: "user", Deactivated: true})
+	require.NoError(err)
+	assert.Equal(model.UserAccountStatusDeactivated, u.GetState())
+	u, err = repo.GetUser(u.GetID(), false)
+	if assert.NoError(err) {
+		assert.Equal(model.UserAccountStatusDeactivated, u.GetState())
+	}
+
+	_, err = repo.CreateUser(repository.CreateUserArgs{Name: u.GetName(), Role: "user"})
+	assert.EqualError(err, repository.ErrAlreadyExists.Error())
+}
+
 func TestRepositoryImpl_GetUser(t *testing.T) {
 	t.Parallel()
 	repo, assert, _, user := setupWithUser(t, common2)
@@ -253,6 +273,100 @@ func TestRepositoryImpl_UpdateUser(t *testing.T) {
 	})
 }
 
+func TestRepositoryImpl_ReplaceExternalUserAccount(t *testing.T) {
+	t.Parallel()
+	repo, _, _ := setup(t, common2)
+
+	const provider = "test"
+	link := func(externalID string) repository.LinkExternalUserAccountArgs {
+		return repository.LinkExternalUserAccountArgs{ProviderName: provider, ExternalID: externalID, Extra: model.JSON{}}
+	}
+	externalID := func(t *testing.T, userID uuid.UUID) string {
+		t.Helper()
+		accounts, err := repo.GetExternalUserAccountsByProvider(provider, []uuid.UUID{userID})
+		require.NoError(t, err)
+		require.Len(t, accounts, 1)
+		return accounts[0].ExternalID
+	}
+
+	t.Run("nil id", func(t *testing.T) {
+		t.Parallel()
+		assert.ErrorIs(t, repo.ReplaceExternalUserAccount(uuid.Nil, link("a")), repository.ErrNilID)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+		assert.ErrorIs(t, repo.ReplaceExternalUserAccount(uuid.Must(uuid.NewV4()), link(random2.AlphaNumeric(20))), repository.ErrNotFound)
+	})
+
+	t.Run("link and replace", func(t *testing.T) {
+		t.Parallel()
+		user := mustMakeUser(t, repo, rand)
+		id1, id2 := random2.AlphaNumeric(20), random2.AlphaNumeric(20)
+
+		require.NoError(t, repo.ReplaceExternalUserAccount(user.GetID(), link(id1)))
+		assert.Equal(t, id1, externalID(t, user.GetID()))
+
+		require.NoError(t, repo.ReplaceExternalUserAccount(user.GetID(), link(id2)))
+		assert.Equal(t, id2, externalID(t, user.GetID()))
+
+		// 同じIDでの置き換えは成功する
+		assert.NoError(t, repo.ReplaceExternalUserAccount(user.GetID(), link(id2)))
+	})
+
+	t.Run("used by another user", func(t *testing.T) {
+		t.Parallel()
+		user1 := mustMakeUser(t, repo, rand)
+		user2 := mustMakeUser(t, repo, rand)
+		id1, id2 := random2.AlphaNumeric(20), random2.AlphaNumeric(20)
+		require.NoError(t, repo.ReplaceExternalUserAccount(user1.GetID(), link(id1)))
+		require.NoError(t, repo.ReplaceExternalUserAccount(user2.GetID(), link(id2)))
+
+		assert.ErrorIs(t, repo.ReplaceExternalUserAccount(user2.GetID(), link(id1)), repository.ErrAlreadyExists)
+		// 失敗した場合、既存の関連付けは残る
+		assert.Equal(t, id2, externalID(t, user2.GetID()))
+	})
+}
+
+func TestRepositoryImpl_GetExternalUserAccountsByProvider(t *testing.T) {
+	t.Parallel()
+	repo, _, _ := setup(t, common2)
+
+	user1 := mustMakeUser(t, repo, rand)
+	user2 := mustMakeUser(t, repo, rand)
+	user3 := mustMakeUser(t, repo, rand)
+	for _, args := range []struct {
+		userID   uuid.UUID
+		provider string
+	}{
+		{user1.GetID(), "a"},
+		{user2.GetID(), "a"},
+		{user2.GetID(), "b"},
+		{user3.GetID(), "a"},
+	} {
+		require.NoError(t, repo.LinkExternalUserAccount(args.userID, repository.LinkExternalUserAccountArgs{
+			ProviderName: args.provider,
+			ExternalID:   random2.AlphaNumeric(20),
+			Extra:        model.JSON{},
+		}))
+	}
+
+	accounts, err := repo.GetExternalUserAccountsByProvider("a", []uuid.UUID{user1.GetID(), user2.GetID()})
+	if assert.NoError(t, err) {
+		userIDs := make([]uuid.UUID, len(accounts))
+		for i, a := range accounts {
+			assert.Equal(t, "a", a.ProviderName)
+			userIDs[i] = a.UserID
+		}
+		assert.ElementsMatch(t, []uuid.UUID{user1.GetID(), user2.GetID()}, userIDs)
+	}
+
+	accounts, err = repo.GetExternalUserAccountsByProvider("a", nil)
+	if assert.NoError(t, err) {
+		assert.Empty(t, accounts)
+	}
+}
+
 func TestGormRepository_GetUserStats(t *testing.T) {
 	t.Parallel()
 	repo, _, _ := setup(t, common)
@@ -2,6 +2,7 @@ package gorm
 
 import (
 	"encoding/base64"
+	"time"
 	"unicode/utf8"
 
 	"github.com/gofrs/uuid"
@@ -12,6 +13,7 @@ import (
 	"github.com/traPtitech/traQ/model"
 	"github.com/traPtitech/traQ/repository"
 	"github.com/traPtitech/traQ/service/rbac/role"
+	"github.com/traPtitech/traQ/utils/gormutil"
 )
 
 // CreateWebhook implements WebhookRepository interface.
@@ -119,6 +121,18 @@ func (repo *Repository) UpdateWebhook(id uuid.UUID, args repository.UpdateWebhoo
 		if args.Secret.Valid {
 			changes["secret"] = args.Secret.String
 		}
+		if args.OutgoingURL.Valid {
+			changes["outgoing_url"] = args.OutgoingURL.String
+		}
+		if args.TriggerWords.Valid {
+			changes["trigger_words"] = args.TriggerWords.String
+		}
+		if args.Templates != nil {
+			changes["templates"] = args.Templates
+		}
+		if args.PayloadTemplate.Valid {
+			changes["payload_template"] = args.PayloadTemplate.String
+		}
 		if args.CreatorID.Valid {
 			// 作成者検証
 			user, err := getUser(tx, false, "id = ?", args.CreatorID.UUID)
@@ -261,3 +275,29 @@ func (repo *Repository) GetWebhooksByCreator(creatorID uuid.UUID) (arr []model.W
 	}
 	return arr, nil
 }
+
+// WriteWebhookDeliveryLog implements WebhookRepository interface.
+func (repo *Repository) WriteWebhookDeliveryLog(log *model.WebhookDeliveryLog) error {
+	if log == nil || log.RequestID == uuid.Nil {
+		return nil
+	}
+	return repo.db.Create(log).Error
+}
+
+// GetWebhookDeliveryLogs implements WebhookRepository interface.
+func (repo *Repository) GetWebhookDeliveryLogs(webhookID uuid.UUID, limit, offset int) ([]*model.WebhookDeliveryLog, error) {
+	logs := make([]*model.WebhookDeliveryLog, 0)
+	if webhookID == uuid.Nil {
+		return logs, nil
+	}
+	return logs, repo.db.Where(&model.WebhookDeliveryLog{WebhookID: webhookID}).
+		Order("date_time DESC").
+		Scopes(gormutil.LimitAndOffset(limit, offset)).
+		Find(&logs).
+		Error
+}
+
+// PurgeWebhookDeliveryLogs implements WebhookRepository interface.
+func (repo *Repository) PurgeWebhookDeliveryLogs(before time.Time) error {
+	return repo.db.Delete(&model.WebhookDeliveryLog{}, "date_time < ?", before).Error
+}
@@ -154,6 +154,21 @@ func (mr *MockBotRepositoryMockRecorder) GetBotEventLogs(botID, limit, offset in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBotEventLogs", reflect.TypeOf((*MockBotRepository)(nil).GetBotEventLogs), botID, limit, offset)
 }
 
+// GetBotEventLogsAfter mocks base method.
+func (m *MockBotRepository) GetBotEventLogsAfter(botID, requestID uuid.UUID, limit int) ([]*model.BotEventLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBotEventLogsAfter", botID, requestID, limit)
+	ret0, _ := ret[0].([]*model.BotEventLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBotEventLogsAfter indicates an expected call of GetBotEventLogsAfter.
+func (mr *MockBotRepositoryMockRecorder) GetBotEventLogsAfter(botID, requestID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBotEventLogsAfter", reflect.TypeOf((*MockBotRepository)(nil).GetBotEventLogsAfter), botID, requestID, limit)
+}
+
 // GetBots mocks base method.
 func (m *MockBotRepository) GetBots(query repository.BotsQuery) ([]*model.Bot, error) {
 	m.ctrl.T.Helper()
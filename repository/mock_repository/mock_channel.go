@@ -38,6 +38,21 @@ func (m *MockChannelRepository) EXPECT() *MockChannelRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AddPrivateChannelMembers mocks base method.
+func (m *MockChannelRepository) AddPrivateChannelMembers(channelID uuid.UUID, userIDs set.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPrivateChannelMembers", channelID, userIDs)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddPrivateChannelMembers indicates an expected call of AddPrivateChannelMembers.
+func (mr *MockChannelRepositoryMockRecorder) AddPrivateChannelMembers(channelID, userIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPrivateChannelMembers", reflect.TypeOf((*MockChannelRepository)(nil).AddPrivateChannelMembers), channelID, userIDs)
+}
+
 // ArchiveChannels mocks base method.
 func (m *MockChannelRepository) ArchiveChannels(ids []uuid.UUID) ([]*model.Channel, error) {
 	m.ctrl.T.Helper()
@@ -84,6 +99,49 @@ func (mr *MockChannelRepositoryMockRecorder) CreateChannel(ch, privateMembers, d
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChannel", reflect.TypeOf((*MockChannelRepository)(nil).CreateChannel), ch, privateMembers, dm)
 }
 
+// CreatePublicChannels mocks base method.
+func (m *MockChannelRepository) CreatePublicChannels(args []*repository.CreatePublicChannelArgs) ([]*model.Channel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePublicChannels", args)
+	ret0, _ := ret[0].([]*model.Channel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePublicChannels indicates an expected call of CreatePublicChannels.
+func (mr *MockChannelRepositoryMockRecorder) CreatePublicChannels(args interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePublicChannels", reflect.TypeOf((*MockChannelRepository)(nil).CreatePublicChannels), args)
+}
+
+// DeleteChannel mocks base method.
+func (m *MockChannelRepository) DeleteChannel(channelID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteChannel", channelID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteChannel indicates an expected call of DeleteChannel.
+func (mr *MockChannelRepositoryMockRecorder) DeleteChannel(channelID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteChannel", reflect.TypeOf((*MockChannelRepository)(nil).DeleteChannel), channelID)
+}
+
+// DeleteChannelRole mocks base method.
+func (m *MockChannelRepository) DeleteChannelRole(channelID, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteChannelRole", channelID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteChannelRole indicates an expected call of DeleteChannelRole.
+func (mr *MockChannelRepositoryMockRecorder) DeleteChannelRole(channelID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteChannelRole", reflect.TypeOf((*MockChannelRepository)(nil).DeleteChannelRole), channelID, userID)
+}
+
 // GetChannel mocks base method.
 func (m *MockChannelRepository) GetChannel(channelID uuid.UUID) (*model.Channel, error) {
 	m.ctrl.T.Helper()
@@ -115,6 +173,21 @@ func (mr *MockChannelRepositoryMockRecorder) GetChannelEvents(query interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannelEvents", reflect.TypeOf((*MockChannelRepository)(nil).GetChannelEvents), query)
 }
 
+// GetChannelRoles mocks base method.
+func (m *MockChannelRepository) GetChannelRoles(channelID uuid.UUID) ([]*model.ChannelRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChannelRoles", channelID)
+	ret0, _ := ret[0].([]*model.ChannelRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChannelRoles indicates an expected call of GetChannelRoles.
+func (mr *MockChannelRepositoryMockRecorder) GetChannelRoles(channelID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannelRoles", reflect.TypeOf((*MockChannelRepository)(nil).GetChannelRoles), channelID)
+}
+
 // GetChannelStats mocks base method.
 func (m *MockChannelRepository) GetChannelStats(channelID uuid.UUID) (*repository.ChannelStats, error) {
 	m.ctrl.T.Helper()
@@ -175,6 +248,36 @@ func (mr *MockChannelRepositoryMockRecorder) GetDirectMessageChannelMapping(user
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDirectMessageChannelMapping", reflect.TypeOf((*MockChannelRepository)(nil).GetDirectMessageChannelMapping), userID)
 }
 
+// GetGroupDirectMessageChannel mocks base method.
+func (m *MockChannelRepository) GetGroupDirectMessageChannel(members set.UUID) (*model.Channel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupDirectMessageChannel", members)
+	ret0, _ := ret[0].(*model.Channel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupDirectMessageChannel indicates an expected call of GetGroupDirectMessageChannel.
+func (mr *MockChannelRepositoryMockRecorder) GetGroupDirectMessageChannel(members interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupDirectMessageChannel", reflect.TypeOf((*MockChannelRepository)(nil).GetGroupDirectMessageChannel), members)
+}
+
+// GetGroupDirectMessageChannelsByUser mocks base method.
+func (m *MockChannelRepository) GetGroupDirectMessageChannelsByUser(userID uuid.UUID) ([]*model.Channel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupDirectMessageChannelsByUser", userID)
+	ret0, _ := ret[0].([]*model.Channel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupDirectMessageChannelsByUser indicates an expected call of GetGroupDirectMessageChannelsByUser.
+func (mr *MockChannelRepositoryMockRecorder) GetGroupDirectMessageChannelsByUser(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupDirectMessageChannelsByUser", reflect.TypeOf((*MockChannelRepository)(nil).GetGroupDirectMessageChannelsByUser), userID)
+}
+
 // GetPrivateChannelMemberIDs mocks base method.
 func (m *MockChannelRepository) GetPrivateChannelMemberIDs(channelID uuid.UUID) ([]uuid.UUID, error) {
 	m.ctrl.T.Helper()
@@ -190,6 +293,21 @@ func (mr *MockChannelRepositoryMockRecorder) GetPrivateChannelMemberIDs(channelI
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrivateChannelMemberIDs", reflect.TypeOf((*MockChannelRepository)(nil).GetPrivateChannelMemberIDs), channelID)
 }
 
+// GetPrivateChannelsByUser mocks base method.
+func (m *MockChannelRepository) GetPrivateChannelsByUser(userID uuid.UUID) ([]*model.Channel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrivateChannelsByUser", userID)
+	ret0, _ := ret[0].([]*model.Channel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPrivateChannelsByUser indicates an expected call of GetPrivateChannelsByUser.
+func (mr *MockChannelRepositoryMockRecorder) GetPrivateChannelsByUser(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrivateChannelsByUser", reflect.TypeOf((*MockChannelRepository)(nil).GetPrivateChannelsByUser), userID)
+}
+
 // GetPublicChannels mocks base method.
 func (m *MockChannelRepository) GetPublicChannels() ([]*model.Channel, error) {
 	m.ctrl.T.Helper()
@@ -205,6 +323,37 @@ func (mr *MockChannelRepositoryMockRecorder) GetPublicChannels() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublicChannels", reflect.TypeOf((*MockChannelRepository)(nil).GetPublicChannels))
 }
 
+// GetUserChannelRoles mocks base method.
+func (m *MockChannelRepository) GetUserChannelRoles(userID uuid.UUID, channelIDs []uuid.UUID) ([]*model.ChannelRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserChannelRoles", userID, channelIDs)
+	ret0, _ := ret[0].([]*model.ChannelRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserChannelRoles indicates an expected call of GetUserChannelRoles.
+func (mr *MockChannelRepositoryMockRecorder) GetUserChannelRoles(userID, channelIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserChannelRoles", reflect.TypeOf((*MockChannelRepository)(nil).GetUserChannelRoles), userID, channelIDs)
+}
+
+// MergeChannel mocks base method.
+func (m *MockChannelRepository) MergeChannel(srcID, dstID, updaterID uuid.UUID) ([]*model.Channel, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeChannel", srcID, dstID, updaterID)
+	ret0, _ := ret[0].([]*model.Channel)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// MergeChannel indicates an expected call of MergeChannel.
+func (mr *MockChannelRepositoryMockRecorder) MergeChannel(srcID, dstID, updaterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeChannel", reflect.TypeOf((*MockChannelRepository)(nil).MergeChannel), srcID, dstID, updaterID)
+}
+
 // RecordChannelEvent mocks base method.
 func (m *MockChannelRepository) RecordChannelEvent(channelID uuid.UUID, eventType model.ChannelEventType, detail model.ChannelEventDetail, datetime time.Time) error {
 	m.ctrl.T.Helper()
@@ -219,6 +368,34 @@ func (mr *MockChannelRepositoryMockRecorder) RecordChannelEvent(channelID, event
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordChannelEvent", reflect.TypeOf((*MockChannelRepository)(nil).RecordChannelEvent), channelID, eventType, detail, datetime)
 }
 
+// RemovePrivateChannelMember mocks base method.
+func (m *MockChannelRepository) RemovePrivateChannelMember(channelID, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemovePrivateChannelMember", channelID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemovePrivateChannelMember indicates an expected call of RemovePrivateChannelMember.
+func (mr *MockChannelRepositoryMockRecorder) RemovePrivateChannelMember(channelID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemovePrivateChannelMember", reflect.TypeOf((*MockChannelRepository)(nil).RemovePrivateChannelMember), channelID, userID)
+}
+
+// SetChannelRole mocks base method.
+func (m *MockChannelRepository) SetChannelRole(channelID, userID uuid.UUID, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetChannelRole", channelID, userID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetChannelRole indicates an expected call of SetChannelRole.
+func (mr *MockChannelRepositoryMockRecorder) SetChannelRole(channelID, userID, role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetChannelRole", reflect.TypeOf((*MockChannelRepository)(nil).SetChannelRole), channelID, userID, role)
+}
+
 // UpdateChannel mocks base method.
 func (m *MockChannelRepository) UpdateChannel(channelID uuid.UUID, args repository.UpdateChannelArgs) (*model.Channel, error) {
 	m.ctrl.T.Helper()
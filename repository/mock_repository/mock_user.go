@@ -51,6 +51,21 @@ func (mr *MockUserRepositoryMockRecorder) CreateUser(args interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUserRepository)(nil).CreateUser), args)
 }
 
+// GetExternalUserAccountsByProvider mocks base method.
+func (m *MockUserRepository) GetExternalUserAccountsByProvider(providerName string, userIDs []uuid.UUID) ([]*model.ExternalProviderUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExternalUserAccountsByProvider", providerName, userIDs)
+	ret0, _ := ret[0].([]*model.ExternalProviderUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExternalUserAccountsByProvider indicates an expected call of GetExternalUserAccountsByProvider.
+func (mr *MockUserRepositoryMockRecorder) GetExternalUserAccountsByProvider(providerName, userIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExternalUserAccountsByProvider", reflect.TypeOf((*MockUserRepository)(nil).GetExternalUserAccountsByProvider), providerName, userIDs)
+}
+
 // GetLinkedExternalUserAccounts mocks base method.
 func (m *MockUserRepository) GetLinkedExternalUserAccounts(userID uuid.UUID) ([]*model.ExternalProviderUser, error) {
 	m.ctrl.T.Helper()
@@ -170,6 +185,20 @@ func (mr *MockUserRepositoryMockRecorder) LinkExternalUserAccount(userID, args i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkExternalUserAccount", reflect.TypeOf((*MockUserRepository)(nil).LinkExternalUserAccount), userID, args)
 }
 
+// ReplaceExternalUserAccount mocks base method.
+func (m *MockUserRepository) ReplaceExternalUserAccount(userID uuid.UUID, args repository.LinkExternalUserAccountArgs) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceExternalUserAccount", userID, args)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceExternalUserAccount indicates an expected call of ReplaceExternalUserAccount.
+func (mr *MockUserRepositoryMockRecorder) ReplaceExternalUserAccount(userID, args interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceExternalUserAccount", reflect.TypeOf((*MockUserRepository)(nil).ReplaceExternalUserAccount), userID, args)
+}
+
 // UnlinkExternalUserAccount mocks base method.
 func (m *MockUserRepository) UnlinkExternalUserAccount(userID uuid.UUID, providerName string) error {
 	m.ctrl.T.Helper()
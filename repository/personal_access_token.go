@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/utils/optional"
+)
+
+// CreatePersonalAccessTokenArgs パーソナルアクセストークン作成引数
+type CreatePersonalAccessTokenArgs struct {
+	UserID    uuid.UUID
+	Name      string
+	TokenHash string
+	Scopes    model.AccessScopes
+	ExpiresAt optional.Time
+}
+
+// PersonalAccessTokenRepository パーソナルアクセストークンリポジトリ
+type PersonalAccessTokenRepository interface {
+	// CreatePersonalAccessToken パーソナルアクセストークンを作成します
+	//
+	// 成功した場合、トークンとnilを返します。
+	// DBによるエラーを返すことがあります。
+	CreatePersonalAccessToken(args CreatePersonalAccessTokenArgs) (*model.PersonalAccessToken, error)
+	// GetPersonalAccessTokenByHash 指定したハッシュのパーソナルアクセストークンを取得します
+	//
+	// 成功した場合、トークンとnilを返します。
+	// 存在しなかった場合、ErrNotFoundを返します。
+	// DBによるエラーを返すことがあります。
+	GetPersonalAccessTokenByHash(tokenHash string) (*model.PersonalAccessToken, error)
+	// GetPersonalAccessTokensByUser 指定したユーザーのパーソナルアクセストークンを全て取得します
+	//
+	// 成功した場合、トークンの配列とnilを返します。
+	// 存在しないユーザーを指定した場合、空配列とnilを返します。
+	// DBによるエラーを返すことがあります。
+	GetPersonalAccessTokensByUser(userID uuid.UUID) ([]*model.PersonalAccessToken, error)
+	// UpdatePersonalAccessTokenLastUsed 指定したパーソナルアクセストークンの最終使用日時・IPアドレスを更新します
+	//
+	// 成功した、或いは既に存在しない場合、nilを返します。
+	// DBによるエラーを返すことがあります。
+	UpdatePersonalAccessTokenLastUsed(id uuid.UUID, at time.Time, ip string) error
+	// DeletePersonalAccessToken 指定したユーザーのパーソナルアクセストークンを削除します
+	//
+	// 成功した場合、nilを返します。
+	// 存在しなかった場合、ErrNotFoundを返します。
+	// DBによるエラーを返すことがあります。
+	DeletePersonalAccessToken(userID, id uuid.UUID) error
+}
@@ -21,4 +21,11 @@ type Repository interface {
 	BotRepository
 	ClipRepository
 	OgpCacheRepository
+	TwoFactorRepository
+	PersonalAccessTokenRepository
+	AuditLogRepository
+	ChannelTemplateRepository
+	ChannelArchivalRepository
+	ChannelRetentionRepository
+	SidebarRepository
 }
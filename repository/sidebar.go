@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"github.com/gofrs/uuid"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/utils/optional"
+)
+
+// SidebarItemArgs サイドバー項目引数
+type SidebarItemArgs struct {
+	Type      model.SidebarItemType
+	ChannelID uuid.UUID
+	URL       string
+	Title     string
+}
+
+// SidebarSectionArgs サイドバーセクション引数
+type SidebarSectionArgs struct {
+	Name      string
+	Collapsed bool
+	Items     []*SidebarItemArgs
+}
+
+// UpdateSidebarSectionArgs サイドバーセクション更新引数
+type UpdateSidebarSectionArgs struct {
+	Name      optional.String
+	Collapsed optional.Bool
+}
+
+// SidebarRepository サイドバーセクションリポジトリ
+type SidebarRepository interface {
+	// GetSidebarSections 指定したユーザーのサイドバーセクションを全て取得します
+	//
+	// 成功した場合、項目を含む並び順のセクションの配列とnilを返します。
+	// DBによるエラーを返すことがあります。
+	GetSidebarSections(userID uuid.UUID) ([]*model.SidebarSection, error)
+	// ReplaceSidebarSections 指定したユーザーのサイドバーセクションを全て置き換えます
+	//
+	// 成功した場合、置き換え後のセクションの配列とnilを返します。
+	// セクション・項目の並び順は引数の順番になります。
+	// userIDにuuid.Nilを指定した場合、ErrNilIDを返します。
+	// 引数に問題がある場合、ArgumentErrorを返します。
+	// DBによるエラーを返すことがあります。
+	ReplaceSidebarSections(userID uuid.UUID, sections []*SidebarSectionArgs) ([]*model.SidebarSection, error)
+	// UpdateSidebarSection 指定したユーザーのサイドバーセクションの情報を更新します
+	//
+	// 成功した場合、nilを返します。
+	// 存在しないセクション、または他のユーザーのセクションの場合、ErrNotFoundを返します。
+	// userID, sectionIDにuuid.Nilを指定した場合、ErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	UpdateSidebarSection(userID, sectionID uuid.UUID, args UpdateSidebarSectionArgs) error
+}
@@ -15,6 +15,9 @@ type CreateStampArgs struct {
 	FileID    uuid.UUID
 	CreatorID uuid.UUID
 	IsUnicode bool
+	Category  string
+	Aliases   []string
+	Tags      []string
 }
 
 // UpdateStampArgs スタンプ情報更新引数
@@ -22,6 +25,11 @@ type UpdateStampArgs struct {
 	Name      optional.String
 	FileID    optional.UUID
 	CreatorID optional.UUID
+	Category  optional.String
+	// Aliases 置き換え後のエイリアス nilの場合は変更しません
+	Aliases []string
+	// Tags 置き換え後のタグ nilの場合は変更しません
+	Tags []string
 }
 
 // UserStampHistory スタンプ履歴構造体
@@ -54,7 +62,7 @@ type StampRepository interface {
 	//
 	// 成功した場合、スタンプとnilを返します。
 	// 引数に問題がある場合、ArgumentErrorを返します。
-	// 既にNameが使われている場合、ErrAlreadyExistsを返します。
+	// 既にName・Aliasesがスタンプ名・エイリアスとして使われている場合、ErrAlreadyExistsを返します。
 	// DBによるエラーを返すことがあります。
 	CreateStamp(args CreateStampArgs) (s *model.Stamp, err error)
 	// UpdateStamp 指定したスタンプの情報を更新します
@@ -63,7 +71,7 @@ type StampRepository interface {
 	// 存在しないスタンプの場合、ErrNotFoundを返します。
 	// idにuuid.Nilを指定した場合、ErrNilIDを返します。
 	// 更新内容に問題がある場合、ArgumentErrorを返します。
-	// 変更後のNameが既に使われている場合、ErrAlreadyExistsを返します。
+	// 変更後のName・Aliasesが既にスタンプ名・エイリアスとして使われている場合、ErrAlreadyExistsを返します。
 	// DBによるエラーを返すことがあります。
 	UpdateStamp(id uuid.UUID, args UpdateStampArgs) error
 	// GetStamp 指定したIDのスタンプを取得します
@@ -78,6 +86,12 @@ type StampRepository interface {
 	// 存在しなかった場合、ErrNotFoundを返します。
 	// DBによるエラーを返すことがあります。
 	GetStampByName(name string) (s *model.Stamp, err error)
+	// GetStampByAlias 指定したエイリアスが設定されているスタンプを取得します
+	//
+	// 成功した場合、スタンプとnilを返します。
+	// 存在しなかった場合、ErrNotFoundを返します。
+	// DBによるエラーを返すことがあります。
+	GetStampByAlias(alias string) (s *model.Stamp, err error)
 	// DeleteStamp 指定したIDのスタンプを削除します
 	//
 	// 成功した場合、nilを返します。
@@ -114,4 +128,11 @@ type StampRepository interface {
 	// stampIDにNILを渡した場合、(nil, ErrNilID)を返します。
 	// DBによるエラーを返すことがあります。
 	GetStampStats(stampID uuid.UUID) (*StampStats, error)
+	// GetAllStampStats 全てのスタンプの統計情報を取得します
+	//
+	// 成功した場合、スタンプIDをキーとした統計情報のマップとnilを返します。
+	// 一度も使われていないスタンプは含まれません。
+	// 結果は最大1時間キャッシュされます。
+	// DBによるエラーを返すことがあります。
+	GetAllStampStats() (map[uuid.UUID]*StampStats, error)
 }
@@ -12,6 +12,8 @@ type UpdateStampPaletteArgs struct {
 	Name        optional.String
 	Description optional.String
 	Stamps      model.UUIDs
+	// Visibility 公開範囲 非公開にした場合、作成者以外の購読は解除されます
+	Visibility optional.String
 }
 
 // StampPaletteRepository スタンプパレットリポジトリ
@@ -49,4 +51,29 @@ type StampPaletteRepository interface {
 	// 成功した場合、スタンプパレットの配列とnilを返します。
 	// DBによるエラーを返すことがあります。
 	GetStampPalettes(userID uuid.UUID) (sps []*model.StampPalette, err error)
+	// GetPublicStampPalettes 公開されているスタンプパレットを全て取得します
+	//
+	// 成功した場合、スタンプパレットの配列とnilを返します。
+	// DBによるエラーを返すことがあります。
+	GetPublicStampPalettes() (sps []*model.StampPalette, err error)
+	// SubscribeStampPalette スタンプパレットを購読します
+	//
+	// 成功した、或いは既に購読していた場合にnilを返します。
+	// 引数にuuid.Nilを指定するとErrNilIDを返します。
+	// 存在しないスタンプパレットの場合、ErrNotFoundを返します。
+	// 公開されていない、或いは自分が作成したスタンプパレットの場合、ArgumentErrorを返します。
+	// DBによるエラーを返すことがあります。
+	SubscribeStampPalette(userID, paletteID uuid.UUID) error
+	// UnsubscribeStampPalette スタンプパレットの購読を解除します
+	//
+	// 成功した、或いは既に解除されていた場合にnilを返します。
+	// 引数にuuid.Nilを指定するとErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	UnsubscribeStampPalette(userID, paletteID uuid.UUID) error
+	// GetSubscribedStampPalettes ユーザーが購読しているスタンプパレットを取得します
+	//
+	// 成功した場合、スタンプパレットの配列とnilを返します。
+	// 存在しないユーザーを指定した場合は空配列とnilを返します。
+	// DBによるエラーを返すことがあります。
+	GetSubscribedStampPalettes(userID uuid.UUID) (sps []*model.StampPalette, err error)
 }
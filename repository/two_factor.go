@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/traPtitech/traQ/model"
+)
+
+// TwoFactorRepository 二要素認証リポジトリ
+type TwoFactorRepository interface {
+	// GetUserTOTP 指定したユーザーのTOTP設定を取得します
+	//
+	// 成功した場合、TOTP設定とnilを返します。
+	// 存在しない場合、ErrNotFoundを返します。
+	// DBによるエラーを返すことがあります。
+	GetUserTOTP(userID uuid.UUID) (*model.UserTOTP, error)
+	// SaveUserTOTP 指定したユーザーのTOTPシークレットを無効状態で保存します
+	//
+	// 既に設定が存在する場合は上書きします。
+	// 成功した場合、nilを返します。
+	// 引数にuuid.Nilを指定した場合、ErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	SaveUserTOTP(userID uuid.UUID, secret string) error
+	// EnableUserTOTP 指定したユーザーのTOTPを有効にします
+	//
+	// 成功した場合、nilを返します。
+	// 存在しない場合、ErrNotFoundを返します。
+	// 引数にuuid.Nilを指定した場合、ErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	EnableUserTOTP(userID uuid.UUID) error
+	// DeleteUserTOTP 指定したユーザーのTOTP設定を削除します
+	//
+	// 成功した場合、nilを返します。
+	// 存在しない場合、ErrNotFoundを返します。
+	// 引数にuuid.Nilを指定した場合、ErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	DeleteUserTOTP(userID uuid.UUID) error
+	// UseUserTOTPStep 指定したユーザーのTOTPコードのタイムステップを使用済みにします
+	//
+	// 成功した場合、nilを返します。
+	// 指定したステップ以降のステップのコードが既に使用されている場合、ErrAlreadyExistsを返します。
+	// TOTP設定が存在しない場合、ErrNotFoundを返します。
+	// 引数にuuid.Nilを指定した場合、ErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	UseUserTOTPStep(userID uuid.UUID, step int64) error
+	// GetTwoFactorLockout 指定したユーザーの二要素認証のロック状態を取得します
+	//
+	// 成功した場合、ロック状態とnilを返します。
+	// 失敗が記録されていない場合、ErrNotFoundを返します。
+	// DBによるエラーを返すことがあります。
+	GetTwoFactorLockout(userID uuid.UUID) (*model.UserTwoFactorLockout, error)
+	// RecordTwoFactorFailure 指定したユーザーの二要素認証の失敗を記録します
+	//
+	// 連続失敗回数がmaxFailuresに達した場合、失敗回数をリセットし、lockDurationの間ロックします。
+	// 成功した場合、記録後のロック状態とnilを返します。
+	// 引数にuuid.Nilを指定した場合、ErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	RecordTwoFactorFailure(userID uuid.UUID, maxFailures int, lockDuration time.Duration) (*model.UserTwoFactorLockout, error)
+	// ResetTwoFactorFailures 指定したユーザーの二要素認証の連続失敗回数をリセットします
+	//
+	// 成功した場合、nilを返します。
+	// DBによるエラーを返すことがあります。
+	ResetTwoFactorFailures(userID uuid.UUID) error
+	// ReplaceRecoveryCodes 指定したユーザーのリカバリーコードを置き換えます
+	//
+	// codeHashesにはmodel.HashRecoveryCodeでハッシュ化したコードを指定します。
+	// 空配列を指定した場合、全てのリカバリーコードを削除します。
+	// 成功した場合、nilを返します。
+	// 引数にuuid.Nilを指定した場合、ErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	ReplaceRecoveryCodes(userID uuid.UUID, codeHashes []string) error
+	// GetRecoveryCodeCount 指定したユーザーの未使用のリカバリーコードの数を返します
+	//
+	// 成功した場合、コードの数とnilを返します。
+	// DBによるエラーを返すことがあります。
+	GetRecoveryCodeCount(userID uuid.UUID) (int, error)
+	// UseRecoveryCode 指定したユーザーのリカバリーコードを使用済みにします
+	//
+	// 成功した場合、nilを返します。使用したコードは削除されます。
+	// 存在しない場合、ErrNotFoundを返します。
+	// DBによるエラーを返すことがあります。
+	UseRecoveryCode(userID uuid.UUID, codeHash string) error
+	// CreateWebAuthnCredential WebAuthnクレデンシャルを作成します
+	//
+	// 成功した場合、nilを返します。
+	// 同じクレデンシャルIDが既に登録されている場合、ErrAlreadyExistsを返します。
+	// DBによるエラーを返すことがあります。
+	CreateWebAuthnCredential(cred *model.WebAuthnCredential) error
+	// GetWebAuthnCredentials 指定したユーザーのWebAuthnクレデンシャルを全て取得します
+	//
+	// 成功した場合、クレデンシャルの配列とnilを返します。
+	// 存在しないユーザーを指定した場合は空配列とnilを返します。
+	// DBによるエラーを返すことがあります。
+	GetWebAuthnCredentials(userID uuid.UUID) ([]*model.WebAuthnCredential, error)
+	// UpdateWebAuthnCredentialSignCount 指定したWebAuthnクレデンシャルの署名カウンタを更新します
+	//
+	// 成功した場合、nilを返します。
+	// 存在しない場合、ErrNotFoundを返します。
+	// 引数にuuid.Nilを指定した場合、ErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	UpdateWebAuthnCredentialSignCount(id uuid.UUID, signCount uint32) error
+	// DeleteWebAuthnCredential 指定したユーザーのWebAuthnクレデンシャルを削除します
+	//
+	// 成功した場合、nilを返します。
+	// 存在しない場合、ErrNotFoundを返します。
+	// 引数にuuid.Nilを指定した場合、ErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	DeleteWebAuthnCredential(userID, id uuid.UUID) error
+	// GetTwoFactorRequiredRoles 二要素認証が必須のロール名を全て取得します
+	//
+	// 成功した場合、ロール名の配列とnilを返します。
+	// DBによるエラーを返すことがあります。
+	GetTwoFactorRequiredRoles() ([]string, error)
+	// SetTwoFactorRequiredRoles 二要素認証が必須のロールを設定します
+	//
+	// 既存の設定は全て置き換えられます。
+	// 成功した場合、nilを返します。
+	// DBによるエラーを返すことがあります。
+	SetTwoFactorRequiredRoles(roles []string) error
+}
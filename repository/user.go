@@ -18,6 +18,8 @@ type CreateUserArgs struct {
 	IconFileID    uuid.UUID
 	Password      string
 	ExternalLogin *model.ExternalProviderUser
+	// Deactivated 凍結状態で作成するかどうか
+	Deactivated bool
 }
 
 // UpdateUserArgs User情報更新引数
@@ -184,6 +186,21 @@ type UserRepository interface {
 	// 引数にuuid.Nilを指定した場合、ErrNilIDを返します。
 	// DBによるエラーを返すことがあります。
 	UnlinkExternalUserAccount(userID uuid.UUID, providerName string) error
+	// ReplaceExternalUserAccount 指定したユーザーの指定した外部プロバイダのログインアカウントの関連付けを置き換えます
+	//
+	// 既存の関連付けの解除と新たな関連付けは同一トランザクションで行われます。
+	// 成功した場合、nilを返します。
+	// 存在しないユーザーの場合、ErrNotFoundを返します。
+	// 指定した外部アカウントが既に他のユーザーに関連付けられている場合、ErrAlreadyExistsを返します。
+	// 引数に問題がある場合、ArgumentErrorを返します。
+	// 引数にuuid.Nilを指定した場合、ErrNilIDを返します。
+	// DBによるエラーを返すことがあります。
+	ReplaceExternalUserAccount(userID uuid.UUID, args LinkExternalUserAccountArgs) error
+	// GetExternalUserAccountsByProvider 指定したユーザー達に関連づけられている指定した外部プロバイダのログインアカウントの配列を返します
+	//
+	// 成功した場合、外部ログインアカウントの配列とnilを返します。
+	// DBによるエラーを返すことがあります。
+	GetExternalUserAccountsByProvider(providerName string, userIDs []uuid.UUID) ([]*model.ExternalProviderUser, error)
 	// GetUserStats 成功した場合、(統計情報, nil)を返します。
 	//
 	// ユーザーが存在しない場合、(nil, ErrNotFound)を返します。
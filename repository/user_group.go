@@ -23,6 +23,13 @@ type UserGroupRepository interface {
 	// 既にNameが使われている場合、ErrAlreadyExistsを返します。
 	// DBによるエラーを返すことがあります。
 	CreateUserGroup(name, description, gType string, adminID, iconFileID uuid.UUID) (*model.UserGroup, error)
+	// CreateUserGroupWithMembers 指定したユーザーをメンバーとしてユーザーグループを作成します
+	//
+	// グループの作成とメンバーの追加は同一トランザクションで行われます。
+	// 成功した場合、ユーザーグループとnilを返します。
+	// 既にNameが使われている場合、ErrAlreadyExistsを返します。
+	// DBによるエラーを返すことがあります。
+	CreateUserGroupWithMembers(name, description, gType string, adminID, iconFileID uuid.UUID, memberIDs []uuid.UUID) (*model.UserGroup, error)
 	// UpdateUserGroup 指定したユーザーグループを更新します
 	//
 	// 成功した場合、nilを返します。
@@ -56,6 +63,12 @@ type UserGroupRepository interface {
 	// 存在しないユーザーを指定した場合は空配列とnilを返します。
 	// DBによるエラーを返すことがあります。
 	GetUserBelongingGroupIDs(userID uuid.UUID) ([]uuid.UUID, error)
+	// GetUsersBelongingGroupIDs 指定したユーザー達が所属しているグループのUUIDをユーザー毎に取得します
+	//
+	// 成功した場合、ユーザーのUUIDをキーとするグループのUUIDの配列のマップとnilを返します。
+	// どのグループにも所属していないユーザーはマップに含まれません。
+	// DBによるエラーを返すことがあります。
+	GetUsersBelongingGroupIDs(userIDs []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error)
 	// GetAllUserGroups 全てのグループを取得します
 	//
 	// 成功した場合、ユーザーグループの配列とnilを返します。
@@ -3,6 +3,14 @@ package repository
 
 import "github.com/traPtitech/traQ/model"
 
+// UpdateUserRoleArgs ユーザーロール更新引数
+type UpdateUserRoleArgs struct {
+	// Permissions ロールに直接付与する権限 (nilの場合は変更しません)
+	Permissions []string
+	// Inheritances 継承するロール (nilの場合は変更しません)
+	Inheritances []string
+}
+
 type UserRoleRepository interface {
 	// CreateUserRoles ユーザーロールを作成します
 	//
@@ -14,4 +22,29 @@ type UserRoleRepository interface {
 	// 成功した場合、ユーザーロールの配列とnilを返します。
 	// DBによるエラーを返すことがあります。
 	GetAllUserRoles() ([]*model.UserRole, error)
+	// GetUserRole 指定した名前のユーザーロールを返します
+	//
+	// 成功した場合、ユーザーロールとnilを返します。
+	// 存在しない場合、ErrNotFoundを返します。
+	// DBによるエラーを返すことがあります。
+	GetUserRole(name string) (*model.UserRole, error)
+	// CreateUserRole カスタムユーザーロールを作成します
+	//
+	// 成功した場合、nilを返します。
+	// 既に同名のロールが存在する場合、ErrAlreadyExistsを返します。
+	// DBによるエラーを返すことがあります。
+	CreateUserRole(role *model.UserRole) error
+	// UpdateUserRole 指定したユーザーロールの権限・継承ロールを更新します
+	//
+	// 成功した場合、nilを返します。
+	// 存在しない場合、ErrNotFoundを返します。
+	// DBによるエラーを返すことがあります。
+	UpdateUserRole(name string, args UpdateUserRoleArgs) error
+	// DeleteUserRole 指定したユーザーロールを削除します
+	//
+	// 成功した場合、nilを返します。
+	// 存在しない場合、ErrNotFoundを返します。
+	// システムロール、或いはユーザーに割り当てられているロールを指定した場合、ErrForbiddenを返します。
+	// DBによるエラーを返すことがあります。
+	DeleteUserRole(name string) error
 }
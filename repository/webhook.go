@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/gofrs/uuid"
 
 	"github.com/traPtitech/traQ/model"
@@ -9,11 +11,15 @@ import (
 
 // UpdateWebhookArgs Webhook情報更新引数
 type UpdateWebhookArgs struct {
-	Name        optional.String
-	Description optional.String
-	ChannelID   optional.UUID
-	Secret      optional.String
-	CreatorID   optional.UUID
+	Name            optional.String
+	Description     optional.String
+	ChannelID       optional.UUID
+	Secret          optional.String
+	CreatorID       optional.UUID
+	OutgoingURL     optional.String
+	TriggerWords    optional.String
+	Templates       model.WebhookTemplates
+	PayloadTemplate optional.String
 }
 
 // WebhookRepository Webhookボットリポジトリ
@@ -62,4 +68,20 @@ type WebhookRepository interface {
 	// 存在しないユーザーを指定した場合は空配列とnilを返します。
 	// DBによるエラーを返すことがあります。
 	GetWebhooksByCreator(creatorID uuid.UUID) ([]model.Webhook, error)
+	// WriteWebhookDeliveryLog Outgoing Webhook配送ログを書き込みます
+	//
+	// 成功した場合、nilを返します。
+	// DBによるエラーを返すことがあります。
+	WriteWebhookDeliveryLog(log *model.WebhookDeliveryLog) error
+	// GetWebhookDeliveryLogs 指定したWebhookのOutgoing Webhook配送ログを取得します
+	//
+	// 成功した場合、配送ログの配列とnilを返します。負のoffset, limitは無視されます。
+	// 存在しないWebhookを指定した場合、空配列とnilを返します。
+	// DBによるエラーを返すことがあります。
+	GetWebhookDeliveryLogs(webhookID uuid.UUID, limit, offset int) ([]*model.WebhookDeliveryLog, error)
+	// PurgeWebhookDeliveryLogs 指定した時間以前のOutgoing Webhook配送ログを全て消去します
+	//
+	// 成功した場合、nilを返します。
+	// DBによるエラーを返すことがあります。
+	PurgeWebhookDeliveryLogs(before time.Time) error
 }
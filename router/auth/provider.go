@@ -19,6 +19,7 @@ import (
 	"github.com/traPtitech/traQ/repository"
 	"github.com/traPtitech/traQ/router/consts"
 	"github.com/traPtitech/traQ/router/extension/herror"
+	"github.com/traPtitech/traQ/router/middlewares"
 	"github.com/traPtitech/traQ/router/session"
 	"github.com/traPtitech/traQ/service/file"
 	"github.com/traPtitech/traQ/service/rbac/role"
@@ -30,15 +31,22 @@ const (
 	cookieName         = "traq_ext_auth_cookie"
 	cookieMaxAge       = 60 * 5
 	accountLinkingFlag = "__account_linking"
+	// twoFactorLoginPath 二要素認証待ちの場合のリダイレクト先
+	twoFactorLoginPath = "/login/two-factor"
 )
 
 type Provider interface {
-	FetchUserInfo(t *oauth2.Token) (UserInfo, error)
 	LoginHandler(c echo.Context) error
 	CallbackHandler(c echo.Context) error
 	L() *zap.Logger
 }
 
+// OAuth2Provider OAuth2によって外部ユーザー情報を取得するProvider
+type OAuth2Provider interface {
+	Provider
+	FetchUserInfo(t *oauth2.Token) (UserInfo, error)
+}
+
 type UserInfo interface {
 	GetProviderName() string
 	GetID() string
@@ -51,44 +59,65 @@ type UserInfo interface {
 
 func defaultLoginHandler(sessStore session.Store, oac *oauth2.Config) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		if len(c.Request().Header.Get(echo.HeaderAuthorization)) > 0 {
-			return herror.BadRequest("Authorization Header must not be set.")
-		}
-
-		sess, err := sessStore.GetSession(c)
+		state, err := beginExternalLogin(c, sessStore)
 		if err != nil {
-			return herror.InternalServerError(err)
+			return err
 		}
+		return c.Redirect(http.StatusFound, oac.AuthCodeURL(state))
+	}
+}
 
-		if isTrue(c.QueryParam("link")) {
-			// アカウント関連付けモード
-			if sess == nil || sess.UserID() == uuid.Nil {
-				return herror.Unauthorized("You are not logged in. Please login.")
-			}
-			if err := sess.Set(accountLinkingFlag, true); err != nil {
-				return herror.InternalServerError(err)
-			}
-		} else {
-			// ログインモード
-			if sess != nil && sess.UserID() != uuid.Nil {
-				return herror.BadRequest("You have already logged in. Please logout once.")
-			}
+// beginExternalLogin 外部認証のログイン・アカウント関連付けを開始し、発行したstateを返します
+func beginExternalLogin(c echo.Context, sessStore session.Store) (string, error) {
+	if len(c.Request().Header.Get(echo.HeaderAuthorization)) > 0 {
+		return "", herror.BadRequest("Authorization Header must not be set.")
+	}
+
+	sess, err := sessStore.GetSession(c)
+	if err != nil {
+		return "", herror.InternalServerError(err)
+	}
+
+	if isTrue(c.QueryParam("link")) {
+		// アカウント関連付けモード
+		if sess == nil || sess.UserID() == uuid.Nil {
+			return "", herror.Unauthorized("You are not logged in. Please login.")
+		}
+		if err := sess.Set(accountLinkingFlag, true); err != nil {
+			return "", herror.InternalServerError(err)
 		}
+	} else {
+		// ログインモード
+		if sess != nil && sess.UserID() != uuid.Nil {
+			return "", herror.BadRequest("You have already logged in. Please logout once.")
+		}
+	}
 
-		state := random.SecureAlphaNumeric(32)
-		c.SetCookie(&http.Cookie{
-			Name:     cookieName,
-			Value:    state,
-			Path:     "/",
-			Expires:  time.Now().Add(cookieMaxAge * time.Second),
-			MaxAge:   cookieMaxAge,
-			HttpOnly: true,
-		})
-		return c.Redirect(http.StatusFound, oac.AuthCodeURL(state))
+	state := random.SecureAlphaNumeric(32)
+	c.SetCookie(&http.Cookie{
+		Name:     cookieName,
+		Value:    state,
+		Path:     "/",
+		Expires:  time.Now().Add(cookieMaxAge * time.Second),
+		MaxAge:   cookieMaxAge,
+		HttpOnly: true,
+	})
+	return state, nil
+}
+
+// verifyState コールバックで受け取ったstateがログイン開始時に発行したものか検証します
+func verifyState(c echo.Context, state string) error {
+	cookie, err := c.Cookie(cookieName)
+	if err != nil {
+		return herror.BadRequest("missing cookie")
 	}
+	if cookie.Value != state {
+		return herror.BadRequest("invalid state")
+	}
+	return nil
 }
 
-func defaultCallbackHandler(p Provider, oac *oauth2.Config, repo repository.Repository, fm file.Manager, sessStore session.Store, allowSignUp bool) echo.HandlerFunc {
+func defaultCallbackHandler(p OAuth2Provider, oac *oauth2.Config, repo repository.Repository, fm file.Manager, sessStore session.Store, allowSignUp bool) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		if len(c.Request().Header.Get(echo.HeaderAuthorization)) > 0 {
 			return herror.BadRequest("Authorization Header must not be set.")
@@ -99,13 +128,8 @@ func defaultCallbackHandler(p Provider, oac *oauth2.Config, repo repository.Repo
 		if len(code) == 0 || len(state) == 0 {
 			return herror.BadRequest("missing code or state")
 		}
-
-		cookie, err := c.Cookie(cookieName)
-		if err != nil {
-			return herror.BadRequest("missing cookie")
-		}
-		if cookie.Value != state {
-			return herror.BadRequest("invalid state")
+		if err := verifyState(c, state); err != nil {
+			return err
 		}
 
 		t, err := oac.Exchange(context.Background(), code)
@@ -118,136 +142,183 @@ func defaultCallbackHandler(p Provider, oac *oauth2.Config, repo repository.Repo
 			return herror.InternalServerError(err)
 		}
 
-		if !tu.IsLoginAllowedUser() {
-			return c.String(http.StatusForbidden, "You are not permitted to access traQ")
-		}
-
-		sess, err := sessStore.GetSession(c)
-		if err != nil {
-			return herror.InternalServerError(err)
-		}
-		if sess != nil {
-			if v, err := sess.Get(accountLinkingFlag); err != nil {
-				return herror.InternalServerError(err)
-			} else if v == true {
-				// アカウント関連付けモード
-				if err := sess.Delete(accountLinkingFlag); err != nil {
-					return herror.InternalServerError(err)
-				}
-				if sess.UserID() == uuid.Nil {
-					return herror.Unauthorized("You are not logged in. Please login.")
-				}
-
-				// ユーザーアカウント状態を確認
-				user, err := repo.GetUser(sess.UserID(), false)
-				if err != nil {
-					return herror.InternalServerError(err)
-				}
-				if !user.IsActive() {
-					return herror.Forbidden("this account is currently suspended")
-				}
-
-				// アカウントにリンク
-				if err := repo.LinkExternalUserAccount(user.GetID(), repository.LinkExternalUserAccountArgs{
-					ProviderName: tu.GetProviderName(),
-					ExternalID:   tu.GetID(),
-					Extra:        model.JSON{"externalName": tu.GetRawName()},
-				}); err != nil {
-					switch err {
-					case repository.ErrAlreadyExists:
-						return herror.BadRequest("this account has already been linked")
-					default:
-						return herror.InternalServerError(err)
-					}
-				}
-				p.L().Info("an external user account has been linked to traQ user",
-					zap.Stringer("id", user.GetID()),
-					zap.String("name", user.GetName()),
-					zap.String("providerName", tu.GetProviderName()),
-					zap.String("externalId", tu.GetID()),
-					zap.String("externalName", tu.GetRawName()))
-
-				return c.Redirect(http.StatusFound, "/") // TODO リダイレクト先を設定画面に
-			}
-		}
-
-		// ログインモード
+		return loginWithExternalUser(c, p, tu, repo, fm, sessStore, allowSignUp)
+	}
+}
 
-		// ログインしていないことを確認
-		if sess != nil && sess.UserID() != uuid.Nil {
-			return herror.BadRequest("You have already logged in. Please logout once.")
-		}
+// loginWithExternalUser 外部ユーザー情報を用いて、ログイン・サインアップ・アカウント関連付けを行います
+func loginWithExternalUser(c echo.Context, p Provider, tu UserInfo, repo repository.Repository, fm file.Manager, sessStore session.Store, allowSignUp bool) error {
+	if !tu.IsLoginAllowedUser() {
+		return c.String(http.StatusForbidden, "You are not permitted to access traQ")
+	}
 
-		user, err := repo.GetUserByExternalID(tu.GetProviderName(), tu.GetID(), false)
-		if err != nil {
-			if err != repository.ErrNotFound {
+	sess, err := sessStore.GetSession(c)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	if sess != nil {
+		if v, err := sess.Get(accountLinkingFlag); err != nil {
+			return herror.InternalServerError(err)
+		} else if v == true {
+			// アカウント関連付けモード
+			if err := sess.Delete(accountLinkingFlag); err != nil {
 				return herror.InternalServerError(err)
 			}
-
-			if !allowSignUp {
-				return herror.Unauthorized("You are not a member of traQ")
+			if sess.UserID() == uuid.Nil {
+				return herror.Unauthorized("You are not logged in. Please login.")
 			}
 
-			args := repository.CreateUserArgs{
-				Name:        tu.GetName(),
-				DisplayName: tu.GetDisplayName(),
-				Role:        role.User,
-				ExternalLogin: &model.ExternalProviderUser{
-					ProviderName: tu.GetProviderName(),
-					ExternalID:   tu.GetID(),
-					Extra:        model.JSON{"externalName": tu.GetRawName()},
-				},
+			// ユーザーアカウント状態を確認
+			user, err := repo.GetUser(sess.UserID(), false)
+			if err != nil {
+				return herror.InternalServerError(err)
 			}
-			if err := vd.Validate(args.Name, validator.UserNameRuleRequired...); err != nil {
-				return herror.BadRequest("Your name doesn't match with traQ ID format")
+			if !user.IsActive() {
+				return herror.Forbidden("this account is currently suspended")
 			}
 
-			if b, err := tu.GetProfileImage(); err == nil && b != nil {
-				fid, err := processProfileIcon(fm, b)
-				if err == nil {
-					args.IconFileID = fid
-				}
-			}
-			if args.IconFileID == uuid.Nil {
-				fid, err := file.GenerateIconFile(fm, tu.GetName())
-				if err != nil {
+			// アカウントにリンク
+			if err := repo.LinkExternalUserAccount(user.GetID(), repository.LinkExternalUserAccountArgs{
+				ProviderName: tu.GetProviderName(),
+				ExternalID:   tu.GetID(),
+				Extra:        model.JSON{"externalName": tu.GetRawName()},
+			}); err != nil {
+				switch err {
+				case repository.ErrAlreadyExists:
+					return herror.BadRequest("this account has already been linked")
+				default:
 					return herror.InternalServerError(err)
 				}
-				args.IconFileID = fid
-			}
-
-			user, err = repo.CreateUser(args)
-			if err != nil {
-				if err == repository.ErrAlreadyExists {
-					return herror.Conflict("name conflicts") // TODO 名前被りをどうするか
-				}
-				return herror.InternalServerError(err)
 			}
-			p.L().Info("New user was created by external auth",
+			p.L().Info("an external user account has been linked to traQ user",
 				zap.Stringer("id", user.GetID()),
 				zap.String("name", user.GetName()),
 				zap.String("providerName", tu.GetProviderName()),
 				zap.String("externalId", tu.GetID()),
 				zap.String("externalName", tu.GetRawName()))
+
+			return c.Redirect(http.StatusFound, "/") // TODO リダイレクト先を設定画面に
 		}
+	}
+
+	// ログインモード
+
+	// ログインしていないことを確認
+	if sess != nil && sess.UserID() != uuid.Nil {
+		return herror.BadRequest("You have already logged in. Please logout once.")
+	}
 
-		// ユーザーのアカウント状態の確認
-		if !user.IsActive() {
-			return herror.Forbidden("this account is currently suspended")
+	user, err := repo.GetUserByExternalID(tu.GetProviderName(), tu.GetID(), false)
+	if err != nil {
+		if err != repository.ErrNotFound {
+			return herror.InternalServerError(err)
 		}
 
-		if _, err := sessStore.RenewSession(c, user.GetID()); err != nil {
+		if !allowSignUp {
+			return herror.Unauthorized("You are not a member of traQ")
+		}
+
+		args := repository.CreateUserArgs{
+			Name:        tu.GetName(),
+			DisplayName: tu.GetDisplayName(),
+			Role:        role.User,
+			ExternalLogin: &model.ExternalProviderUser{
+				ProviderName: tu.GetProviderName(),
+				ExternalID:   tu.GetID(),
+				Extra:        model.JSON{"externalName": tu.GetRawName()},
+			},
+		}
+		if err := vd.Validate(args.Name, validator.UserNameRuleRequired...); err != nil {
+			return herror.BadRequest("Your name doesn't match with traQ ID format")
+		}
+
+		if b, err := tu.GetProfileImage(); err == nil && b != nil {
+			fid, err := processProfileIcon(fm, b)
+			if err == nil {
+				args.IconFileID = fid
+			}
+		}
+		if args.IconFileID == uuid.Nil {
+			fid, err := file.GenerateIconFile(fm, tu.GetName())
+			if err != nil {
+				return herror.InternalServerError(err)
+			}
+			args.IconFileID = fid
+		}
+
+		user, err = repo.CreateUser(args)
+		if err != nil {
+			if err == repository.ErrAlreadyExists {
+				return herror.Conflict("name conflicts") // TODO 名前被りをどうするか
+			}
 			return herror.InternalServerError(err)
 		}
-		p.L().Info("User was logged in by external auth",
+		p.L().Info("New user was created by external auth",
 			zap.Stringer("id", user.GetID()),
 			zap.String("name", user.GetName()),
 			zap.String("providerName", tu.GetProviderName()),
 			zap.String("externalId", tu.GetID()),
 			zap.String("externalName", tu.GetRawName()))
+	}
+
+	// ユーザーのアカウント状態の確認
+	if !user.IsActive() {
+		return herror.Forbidden("this account is currently suspended")
+	}
+
+	// 二要素認証
+	// 外部認証のみでログインを完了させず、パスワードログインと同様に二要素認証待ちのセッションを開始する
+	enrolled, err := middlewares.HasTwoFactorAuth(repo, user.GetID())
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	if enrolled {
+		if err := session.StartTwoFactorLogin(c, sessStore, user.GetID()); err != nil {
+			return herror.InternalServerError(err)
+		}
+		p.L().Info("External auth login requires two-factor authentication",
+			zap.Stringer("id", user.GetID()),
+			zap.String("name", user.GetName()),
+			zap.String("providerName", tu.GetProviderName()))
+		return c.Redirect(http.StatusFound, twoFactorLoginPath)
+	}
+	required, err := isTwoFactorRequiredRole(repo, user.GetRole())
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
 
-		return c.Redirect(http.StatusFound, "/")
+	sess, err = sessStore.RenewSession(c, user.GetID())
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	if required {
+		// 二要素認証の登録が完了するまで、登録用API以外は利用できない
+		if err := sess.Set(session.KeyTwoFactorEnrollmentRequired, true); err != nil {
+			return herror.InternalServerError(err)
+		}
+	}
+	p.L().Info("User was logged in by external auth",
+		zap.Stringer("id", user.GetID()),
+		zap.String("name", user.GetName()),
+		zap.String("providerName", tu.GetProviderName()),
+		zap.String("externalId", tu.GetID()),
+		zap.String("externalName", tu.GetRawName()))
+
+	return c.Redirect(http.StatusFound, "/")
+}
+
+// isTwoFactorRequiredRole 指定したロールに二要素認証が必須かどうか
+func isTwoFactorRequiredRole(repo repository.Repository, role string) (bool, error) {
+	roles, err := repo.GetTwoFactorRequiredRoles()
+	if err != nil {
+		return false, err
+	}
+	for _, r := range roles {
+		if r == role {
+			return true, nil
+		}
 	}
+	return false, nil
 }
 
 func processProfileIcon(m file.Manager, src []byte) (uuid.UUID, error) {
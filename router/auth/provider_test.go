@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/router/session"
+)
+
+type testRepository struct {
+	repository.Repository
+	users         map[string]*model.User // externalID -> user
+	totp          map[uuid.UUID]*model.UserTOTP
+	requiredRoles []string
+}
+
+func (r *testRepository) GetUserByExternalID(_, externalID string, _ bool) (model.UserInfo, error) {
+	u, ok := r.users[externalID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return u, nil
+}
+
+func (r *testRepository) GetUserTOTP(userID uuid.UUID) (*model.UserTOTP, error) {
+	t, ok := r.totp[userID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return t, nil
+}
+
+func (r *testRepository) GetWebAuthnCredentials(uuid.UUID) ([]*model.WebAuthnCredential, error) {
+	return []*model.WebAuthnCredential{}, nil
+}
+
+func (r *testRepository) GetTwoFactorRequiredRoles() ([]string, error) {
+	return r.requiredRoles, nil
+}
+
+type testSession struct {
+	session.Session
+	userID uuid.UUID
+	data   map[string]interface{}
+}
+
+func (s *testSession) UserID() uuid.UUID { return s.userID }
+func (s *testSession) LoggedIn() bool    { return s.userID != uuid.Nil }
+func (s *testSession) Get(key string) (interface{}, error) {
+	return s.data[key], nil
+}
+func (s *testSession) Set(key string, value interface{}) error {
+	s.data[key] = value
+	return nil
+}
+func (s *testSession) Delete(key string) error {
+	delete(s.data, key)
+	return nil
+}
+
+// testSessionStore 最後に発行したセッションを保持するセッションストア
+type testSessionStore struct {
+	session.Store
+	current *testSession
+}
+
+func (s *testSessionStore) GetSession(echo.Context) (session.Session, error) {
+	if s.current == nil {
+		return nil, nil
+	}
+	return s.current, nil
+}
+
+func (s *testSessionStore) RenewSession(_ echo.Context, userID uuid.UUID) (session.Session, error) {
+	s.current = &testSession{userID: userID, data: map[string]interface{}{}}
+	return s.current, nil
+}
+
+func TestLoginWithExternalUser(t *testing.T) {
+	t.Parallel()
+
+	var (
+		normal   = &model.User{ID: uuid.Must(uuid.NewV4()), Name: "normal", Role: "user", Status: model.UserAccountStatusActive}
+		enrolled = &model.User{ID: uuid.Must(uuid.NewV4()), Name: "enrolled", Role: "user", Status: model.UserAccountStatusActive}
+		required = &model.User{ID: uuid.Must(uuid.NewV4()), Name: "required", Role: "admin", Status: model.UserAccountStatusActive}
+	)
+	repo := &testRepository{
+		users: map[string]*model.User{
+			"normal":   normal,
+			"enrolled": enrolled,
+			"required": required,
+		},
+		totp: map[uuid.UUID]*model.UserTOTP{
+			enrolled.ID: {UserID: enrolled.ID, Enabled: true},
+		},
+		requiredRoles: []string{"admin"},
+	}
+	p := &SAMLProvider{logger: zap.NewNop()}
+
+	login := func(t *testing.T, externalID string) (*httptest.ResponseRecorder, *testSession) {
+		t.Helper()
+		store := &testSessionStore{}
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/api/auth/saml/callback", nil), rec)
+		err := loginWithExternalUser(c, p, &samlUserInfo{nameID: externalID, name: externalID}, repo, nil, store, false)
+		require.NoError(t, err)
+		require.NotNil(t, store.current)
+		return rec, store.current
+	}
+
+	t.Run("without two-factor", func(t *testing.T) {
+		t.Parallel()
+		rec, sess := login(t, "normal")
+		assert.Equal(t, http.StatusFound, rec.Code)
+		assert.Equal(t, "/", rec.Header().Get(echo.HeaderLocation))
+		assert.Equal(t, normal.ID, sess.UserID())
+		assert.Nil(t, sess.data[session.KeyTwoFactorEnrollmentRequired])
+	})
+
+	t.Run("enrolled user", func(t *testing.T) {
+		t.Parallel()
+		rec, sess := login(t, "enrolled")
+		assert.Equal(t, http.StatusFound, rec.Code)
+		assert.Equal(t, twoFactorLoginPath, rec.Header().Get(echo.HeaderLocation))
+		// 二要素認証を完了するまでログイン済みにならない
+		assert.False(t, sess.LoggedIn())
+		assert.Equal(t, enrolled.ID.String(), sess.data[session.KeyTwoFactorUserID])
+		assert.NotNil(t, sess.data[session.KeyTwoFactorExpiresAt])
+	})
+
+	t.Run("required but not enrolled user", func(t *testing.T) {
+		t.Parallel()
+		rec, sess := login(t, "required")
+		assert.Equal(t, http.StatusFound, rec.Code)
+		assert.Equal(t, required.ID, sess.UserID())
+		assert.Equal(t, true, sess.data[session.KeyTwoFactorEnrollmentRequired])
+	})
+}
@@ -0,0 +1,420 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crewjam/saml"
+	"github.com/crewjam/saml/samlsp"
+	"github.com/labstack/echo/v4"
+	dsig "github.com/russellhaering/goxmldsig"
+	"go.uber.org/zap"
+	"golang.org/x/exp/utf8string"
+
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/router/extension/herror"
+	"github.com/traPtitech/traQ/router/session"
+	"github.com/traPtitech/traQ/service/file"
+)
+
+const (
+	SAMLProviderName          = "saml"
+	samlAPIRequestErrorFormat = "saml api request error: %w"
+	samlStateExpiration       = cookieMaxAge * time.Second
+	samlHTTPTimeout           = 10 * time.Second
+	// samlIconMaxFileSize アイコン画像の最大サイズ アップロード時の上限と同じ
+	samlIconMaxFileSize = 2 << 20 // 2MB
+)
+
+var (
+	samlHTTPClient      = &http.Client{Timeout: samlHTTPTimeout}
+	errSAMLIconTooLarge = errors.New("too large image")
+)
+
+type SAMLProvider struct {
+	config    SAMLProviderConfig
+	repo      repository.Repository
+	fm        file.Manager
+	logger    *zap.Logger
+	sessStore session.Store
+	sp        *saml.ServiceProvider
+	states    *samlStateStore
+}
+
+type SAMLProviderConfig struct {
+	// EntityID SPのエンティティID 空の場合はMetadataURLを使用します
+	EntityID string
+	// MetadataURL SPメタデータのURL
+	MetadataURL string
+	// ACSURL SPのAssertion Consumer ServiceのURL
+	ACSURL string
+	// CallbackURL ACSで検証したアサーションを受け取るコールバックのURL
+	CallbackURL string
+	// IdPMetadataURL IdPメタデータのURL
+	IdPMetadataURL string
+	// IdPMetadataFile IdPメタデータのファイルパス IdPMetadataURLが指定されている場合は無視されます
+	IdPMetadataFile string
+	// CertificateFile SPの証明書(PEM)のファイルパス
+	CertificateFile string
+	// PrivateKeyFile SPのRSA秘密鍵(PEM)のファイルパス
+	PrivateKeyFile string
+	// NameAttribute traQ IDとして用いる属性名
+	NameAttribute string
+	// DisplayNameAttribute 表示名として用いる属性名
+	DisplayNameAttribute string
+	// IconAttribute アイコン画像(URLまたはBase64)として用いる属性名
+	IconAttribute string
+	// RegisterUserIfNotFound 未登録のユーザーをサインアップさせるかどうか
+	RegisterUserIfNotFound bool
+}
+
+func (c SAMLProviderConfig) Valid() bool {
+	return len(c.MetadataURL) > 0 && len(c.ACSURL) > 0 && len(c.CallbackURL) > 0 &&
+		(len(c.IdPMetadataURL) > 0 || len(c.IdPMetadataFile) > 0) &&
+		len(c.CertificateFile) > 0 && len(c.PrivateKeyFile) > 0
+}
+
+type samlUserInfo struct {
+	nameID      string
+	name        string
+	displayName string
+	icon        string
+}
+
+func (u *samlUserInfo) GetProviderName() string {
+	return SAMLProviderName
+}
+
+func (u *samlUserInfo) GetID() string {
+	return u.nameID
+}
+
+func (u *samlUserInfo) GetRawName() string {
+	return u.name
+}
+
+func (u *samlUserInfo) GetName() string {
+	s := strings.ReplaceAll(u.name, " ", "")
+	regex := regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+	s = regex.ReplaceAllLiteralString(s, "_")
+	if us := utf8string.NewString(s); us.RuneCount() > 32 {
+		s = us.Slice(0, 32)
+	}
+	return s
+}
+
+func (u *samlUserInfo) GetDisplayName() string {
+	name := u.displayName
+	if len(name) == 0 {
+		name = u.name
+	}
+	if s := utf8string.NewString(name); s.RuneCount() > 64 {
+		return s.Slice(0, 64)
+	}
+	return name
+}
+
+func (u *samlUserInfo) GetProfileImage() ([]byte, error) {
+	if len(u.icon) == 0 {
+		return nil, nil
+	}
+	if !strings.HasPrefix(u.icon, "https://") && !strings.HasPrefix(u.icon, "http://") {
+		// jpegPhoto属性などは画像がBase64で直接入っている
+		b, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(u.icon), ""))
+		if err != nil {
+			return nil, fmt.Errorf(samlAPIRequestErrorFormat, err)
+		}
+		if len(b) > samlIconMaxFileSize {
+			return nil, fmt.Errorf(samlAPIRequestErrorFormat, errSAMLIconTooLarge)
+		}
+		return b, nil
+	}
+
+	resp, err := samlHTTPClient.Get(u.icon)
+	if err != nil {
+		return nil, fmt.Errorf(samlAPIRequestErrorFormat, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(samlAPIRequestErrorFormat, fmt.Errorf("invalid status code: %d", resp.StatusCode))
+	}
+	if resp.ContentLength > samlIconMaxFileSize {
+		return nil, fmt.Errorf(samlAPIRequestErrorFormat, errSAMLIconTooLarge)
+	}
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, samlIconMaxFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf(samlAPIRequestErrorFormat, err)
+	}
+	if len(b) > samlIconMaxFileSize {
+		return nil, fmt.Errorf(samlAPIRequestErrorFormat, errSAMLIconTooLarge)
+	}
+	return b, nil
+}
+
+func (u *samlUserInfo) IsLoginAllowedUser() bool {
+	return true
+}
+
+func NewSAMLProvider(repo repository.Repository, fm file.Manager, logger *zap.Logger, sessStore session.Store, config SAMLProviderConfig) (*SAMLProvider, error) {
+	keyPair, err := tls.LoadX509KeyPair(config.CertificateFile, config.PrivateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load SP key pair: %w", err)
+	}
+	key, ok := keyPair.PrivateKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("SP private key must be RSA key")
+	}
+	cert, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SP certificate: %w", err)
+	}
+
+	var idpMetadata *saml.EntityDescriptor
+	if len(config.IdPMetadataURL) > 0 {
+		u, err := url.Parse(config.IdPMetadataURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IdP metadata url: %w", err)
+		}
+		idpMetadata, err = samlsp.FetchMetadata(context.Background(), samlHTTPClient, *u)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch IdP metadata: %w", err)
+		}
+	} else {
+		b, err := ioutil.ReadFile(config.IdPMetadataFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read IdP metadata: %w", err)
+		}
+		idpMetadata, err = samlsp.ParseMetadata(b)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse IdP metadata: %w", err)
+		}
+	}
+
+	metadataURL, err := url.Parse(config.MetadataURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid metadata url: %w", err)
+	}
+	acsURL, err := url.Parse(config.ACSURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid acs url: %w", err)
+	}
+
+	return &SAMLProvider{
+		config:    config,
+		repo:      repo,
+		fm:        fm,
+		logger:    logger,
+		sessStore: sessStore,
+		sp: &saml.ServiceProvider{
+			EntityID:          config.EntityID,
+			Key:               key,
+			Certificate:       cert,
+			MetadataURL:       *metadataURL,
+			AcsURL:            *acsURL,
+			IDPMetadata:       idpMetadata,
+			AuthnNameIDFormat: saml.PersistentNameIDFormat,
+			SignatureMethod:   dsig.RSASHA256SignatureMethod,
+		},
+		states: newSAMLStateStore(),
+	}, nil
+}
+
+// MetadataHandler SPメタデータを返すハンドラ
+func (p *SAMLProvider) MetadataHandler(c echo.Context) error {
+	b, err := xml.MarshalIndent(p.sp.Metadata(), "", "  ")
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	return c.Blob(http.StatusOK, "application/samlmetadata+xml", b)
+}
+
+func (p *SAMLProvider) LoginHandler(c echo.Context) error {
+	state, err := beginExternalLogin(c, p.sessStore)
+	if err != nil {
+		return err
+	}
+
+	req, err := p.sp.MakeAuthenticationRequest(p.sp.GetSSOBindingLocation(saml.HTTPRedirectBinding), saml.HTTPRedirectBinding, saml.HTTPPostBinding)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	u, err := req.Redirect(state, p.sp)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	p.states.add(state, req.ID)
+
+	return c.Redirect(http.StatusFound, u.String())
+}
+
+// ACSHandler IdPからのSAMLResponseを受け取るハンドラ
+//
+// IdPからのPOSTリクエストにはSameSite=Laxのクッキーが付与されないため、
+// ここではアサーションの検証のみを行い、ログイン処理はCallbackHandlerへリダイレクトした後に行います。
+func (p *SAMLProvider) ACSHandler(c echo.Context) error {
+	state := c.FormValue("RelayState")
+	if len(state) == 0 {
+		return herror.BadRequest("missing RelayState")
+	}
+	requestID, ok := p.states.requestID(state)
+	if !ok {
+		return herror.BadRequest("invalid RelayState")
+	}
+
+	assertion, err := p.sp.ParseResponse(c.Request(), []string{requestID})
+	if err != nil {
+		var ire *saml.InvalidResponseError
+		if errors.As(err, &ire) {
+			p.L().Info("invalid SAML response", zap.Error(ire.PrivateErr))
+		}
+		return herror.BadRequest("invalid SAMLResponse")
+	}
+
+	tu, err := p.userInfoFromAssertion(assertion)
+	if err != nil {
+		return herror.BadRequest(err)
+	}
+	p.states.setUser(state, tu)
+
+	return c.Redirect(http.StatusSeeOther, p.config.CallbackURL+"?state="+url.QueryEscape(state))
+}
+
+func (p *SAMLProvider) CallbackHandler(c echo.Context) error {
+	if len(c.Request().Header.Get(echo.HeaderAuthorization)) > 0 {
+		return herror.BadRequest("Authorization Header must not be set.")
+	}
+
+	state := c.QueryParam("state")
+	if len(state) == 0 {
+		return herror.BadRequest("missing state")
+	}
+	if err := verifyState(c, state); err != nil {
+		return err
+	}
+	tu, ok := p.states.popUser(state)
+	if !ok {
+		return herror.BadRequest("invalid state")
+	}
+
+	return loginWithExternalUser(c, p, tu, p.repo, p.fm, p.sessStore, p.config.RegisterUserIfNotFound)
+}
+
+func (p *SAMLProvider) userInfoFromAssertion(assertion *saml.Assertion) (*samlUserInfo, error) {
+	if assertion.Subject == nil || assertion.Subject.NameID == nil || len(assertion.Subject.NameID.Value) == 0 {
+		return nil, errors.New("missing NameID")
+	}
+
+	ui := &samlUserInfo{
+		nameID:      assertion.Subject.NameID.Value,
+		name:        samlAttributeValue(assertion, p.config.NameAttribute),
+		displayName: samlAttributeValue(assertion, p.config.DisplayNameAttribute),
+		icon:        samlAttributeValue(assertion, p.config.IconAttribute),
+	}
+	if len(ui.name) == 0 {
+		return nil, fmt.Errorf("missing %s attribute", p.config.NameAttribute)
+	}
+	return ui, nil
+}
+
+func (p *SAMLProvider) L() *zap.Logger {
+	return p.logger
+}
+
+// samlAttributeValue アサーションから指定した名前の属性の最初の値を取得します
+func samlAttributeValue(assertion *saml.Assertion, name string) string {
+	if len(name) == 0 {
+		return ""
+	}
+	for _, statement := range assertion.AttributeStatements {
+		for _, attr := range statement.Attributes {
+			if attr.Name != name && attr.FriendlyName != name {
+				continue
+			}
+			for _, v := range attr.Values {
+				if len(v.Value) > 0 {
+					return v.Value
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// samlStateStore SAMLログインの途中状態をstateごとに保持するストア
+type samlStateStore struct {
+	mu     sync.Mutex
+	states map[string]*samlState
+}
+
+type samlState struct {
+	requestID string
+	user      *samlUserInfo
+	expiresAt time.Time
+}
+
+func newSAMLStateStore() *samlStateStore {
+	return &samlStateStore{states: map[string]*samlState{}}
+}
+
+func (s *samlStateStore) add(state, requestID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for k, v := range s.states {
+		if now.After(v.expiresAt) {
+			delete(s.states, k)
+		}
+	}
+	s.states[state] = &samlState{requestID: requestID, expiresAt: now.Add(samlStateExpiration)}
+}
+
+func (s *samlStateStore) requestID(state string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.states[state]
+	if !ok || time.Now().After(v.expiresAt) || v.user != nil {
+		return "", false
+	}
+	return v.requestID, true
+}
+
+func (s *samlStateStore) setUser(state string, user *samlUserInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if v, ok := s.states[state]; ok {
+		v.user = user
+	}
+}
+
+func (s *samlStateStore) popUser(state string) (*samlUserInfo, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.states[state]
+	if !ok || v.user == nil {
+		return nil, false
+	}
+	delete(s.states, state)
+	if time.Now().After(v.expiresAt) {
+		return nil, false
+	}
+	return v.user, true
+}
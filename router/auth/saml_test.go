@@ -0,0 +1,403 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crewjam/saml"
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/router/session"
+)
+
+func generateTestKeyPair(t *testing.T, cn string) (*rsa.PrivateKey, *x509.Certificate) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return key, cert
+}
+
+func mustParseURL(t *testing.T, s string) url.URL {
+	t.Helper()
+	u, err := url.Parse(s)
+	require.NoError(t, err)
+	return *u
+}
+
+func TestSAMLUserInfo(t *testing.T) {
+	t.Parallel()
+
+	t.Run("name", func(t *testing.T) {
+		t.Parallel()
+		u := &samlUserInfo{nameID: "id", name: "Taro Yamada.01"}
+		assert.Equal(t, "id", u.GetID())
+		assert.Equal(t, "Taro Yamada.01", u.GetRawName())
+		assert.Equal(t, "TaroYamada_01", u.GetName())
+		assert.Equal(t, "Taro Yamada.01", u.GetDisplayName())
+
+		u = &samlUserInfo{name: strings.Repeat("a", 40), displayName: strings.Repeat("あ", 70)}
+		assert.Equal(t, strings.Repeat("a", 32), u.GetName())
+		assert.Equal(t, strings.Repeat("あ", 64), u.GetDisplayName())
+	})
+
+	t.Run("no icon", func(t *testing.T) {
+		t.Parallel()
+		b, err := (&samlUserInfo{}).GetProfileImage()
+		assert.NoError(t, err)
+		assert.Nil(t, b)
+	})
+
+	t.Run("base64 icon", func(t *testing.T) {
+		t.Parallel()
+		encoded := base64.StdEncoding.EncodeToString([]byte("image"))
+		b, err := (&samlUserInfo{icon: encoded[:4] + "\n " + encoded[4:]}).GetProfileImage()
+		if assert.NoError(t, err) {
+			assert.Equal(t, []byte("image"), b)
+		}
+
+		_, err = (&samlUserInfo{icon: "!!!"}).GetProfileImage()
+		assert.Error(t, err)
+
+		large := base64.StdEncoding.EncodeToString(make([]byte, samlIconMaxFileSize+1))
+		_, err = (&samlUserInfo{icon: large}).GetProfileImage()
+		assert.ErrorIs(t, err, errSAMLIconTooLarge)
+	})
+
+	t.Run("url icon", func(t *testing.T) {
+		t.Parallel()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/icon":
+				_, _ = w.Write([]byte("image"))
+			case "/large":
+				// Content-Lengthを付けずに上限を超えるデータを返す
+				w.(http.Flusher).Flush()
+				_, _ = w.Write(make([]byte, samlIconMaxFileSize+1))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		t.Cleanup(server.Close)
+
+		b, err := (&samlUserInfo{icon: server.URL + "/icon"}).GetProfileImage()
+		if assert.NoError(t, err) {
+			assert.Equal(t, []byte("image"), b)
+		}
+
+		_, err = (&samlUserInfo{icon: server.URL + "/large"}).GetProfileImage()
+		assert.ErrorIs(t, err, errSAMLIconTooLarge)
+
+		_, err = (&samlUserInfo{icon: server.URL + "/notfound"}).GetProfileImage()
+		assert.Error(t, err)
+	})
+}
+
+func TestSAMLProvider_userInfoFromAssertion(t *testing.T) {
+	t.Parallel()
+
+	p := &SAMLProvider{config: SAMLProviderConfig{
+		NameAttribute:        "uid",
+		DisplayNameAttribute: "urn:oid:2.5.4.3",
+		IconAttribute:        "jpegPhoto",
+	}}
+	attr := func(name, friendlyName string, values ...string) saml.Attribute {
+		a := saml.Attribute{Name: name, FriendlyName: friendlyName}
+		for _, v := range values {
+			a.Values = append(a.Values, saml.AttributeValue{Value: v})
+		}
+		return a
+	}
+	subject := &saml.Subject{NameID: &saml.NameID{Value: "persistent-id"}}
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		ui, err := p.userInfoFromAssertion(&saml.Assertion{
+			Subject: subject,
+			AttributeStatements: []saml.AttributeStatement{{Attributes: []saml.Attribute{
+				attr("urn:oid:0.9.2342.19200300.100.1.1", "uid", "", "alice"),
+				attr("urn:oid:2.5.4.3", "cn", "Alice"),
+			}}},
+		})
+		if assert.NoError(t, err) {
+			assert.Equal(t, &samlUserInfo{nameID: "persistent-id", name: "alice", displayName: "Alice"}, ui)
+		}
+	})
+
+	t.Run("missing NameID", func(t *testing.T) {
+		t.Parallel()
+		_, err := p.userInfoFromAssertion(&saml.Assertion{
+			AttributeStatements: []saml.AttributeStatement{{Attributes: []saml.Attribute{attr("uid", "", "alice")}}},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("missing name attribute", func(t *testing.T) {
+		t.Parallel()
+		_, err := p.userInfoFromAssertion(&saml.Assertion{
+			Subject:             subject,
+			AttributeStatements: []saml.AttributeStatement{{Attributes: []saml.Attribute{attr("uid", "")}}},
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestSAMLStateStore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("flow", func(t *testing.T) {
+		t.Parallel()
+		s := newSAMLStateStore()
+		s.add("state", "request")
+
+		_, ok := s.popUser("state")
+		assert.False(t, ok, "user is not set yet")
+
+		id, ok := s.requestID("state")
+		if assert.True(t, ok) {
+			assert.Equal(t, "request", id)
+		}
+		_, ok = s.requestID("unknown")
+		assert.False(t, ok)
+
+		user := &samlUserInfo{nameID: "id"}
+		s.setUser("state", user)
+		_, ok = s.requestID("state")
+		assert.False(t, ok, "assertion cannot be consumed twice")
+
+		u, ok := s.popUser("state")
+		if assert.True(t, ok) {
+			assert.Equal(t, user, u)
+		}
+		_, ok = s.popUser("state")
+		assert.False(t, ok, "user cannot be popped twice")
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		t.Parallel()
+		s := newSAMLStateStore()
+		s.add("expired", "request")
+		s.add("alive", "request")
+		s.states["expired"].expiresAt = time.Now().Add(-time.Second)
+
+		_, ok := s.requestID("expired")
+		assert.False(t, ok)
+		s.setUser("expired", &samlUserInfo{})
+		_, ok = s.popUser("expired")
+		assert.False(t, ok)
+
+		// 追加時に期限切れのstateは破棄される
+		s.states["alive"].expiresAt = time.Now().Add(-time.Second)
+		s.add("new", "request")
+		assert.NotContains(t, s.states, "alive")
+		assert.Contains(t, s.states, "new")
+	})
+}
+
+func TestSAMLProvider_ACSHandler(t *testing.T) {
+	t.Parallel()
+
+	const (
+		acsURL      = "https://traq.example.com/api/auth/saml/acs"
+		callbackURL = "https://traq.example.com/api/auth/saml/callback"
+	)
+
+	idpKey, idpCert := generateTestKeyPair(t, "idp")
+	idp := &saml.IdentityProvider{
+		Key:             idpKey,
+		Certificate:     idpCert,
+		MetadataURL:     mustParseURL(t, "https://idp.example.com/metadata"),
+		SSOURL:          mustParseURL(t, "https://idp.example.com/sso"),
+		SignatureMethod: dsig.RSASHA256SignatureMethod,
+	}
+	spKey, spCert := generateTestKeyPair(t, "sp")
+	p := &SAMLProvider{
+		config: SAMLProviderConfig{
+			CallbackURL:          callbackURL,
+			NameAttribute:        "uid",
+			DisplayNameAttribute: "cn",
+		},
+		logger: zap.NewNop(),
+		sp: &saml.ServiceProvider{
+			Key:               spKey,
+			Certificate:       spCert,
+			MetadataURL:       mustParseURL(t, "https://traq.example.com/api/auth/saml/metadata"),
+			AcsURL:            mustParseURL(t, acsURL),
+			IDPMetadata:       idp.Metadata(),
+			AuthnNameIDFormat: saml.PersistentNameIDFormat,
+			SignatureMethod:   dsig.RSASHA256SignatureMethod,
+		},
+		states: newSAMLStateStore(),
+	}
+
+	// IdPが発行するSAMLResponseを生成
+	makeResponse := func(t *testing.T, requestID string) string {
+		t.Helper()
+		now := saml.TimeNow()
+		req := &saml.IdpAuthnRequest{
+			IDP:                     idp,
+			HTTPRequest:             httptest.NewRequest(http.MethodGet, "/sso", nil),
+			Request:                 saml.AuthnRequest{ID: requestID, IssueInstant: now},
+			ServiceProviderMetadata: p.sp.Metadata(),
+			SPSSODescriptor:         &saml.SPSSODescriptor{},
+			ACSEndpoint:             &saml.IndexedEndpoint{Binding: saml.HTTPPostBinding, Location: acsURL},
+			Now:                     now,
+		}
+		err := saml.DefaultAssertionMaker{}.MakeAssertion(req, &saml.Session{
+			NameID:         "persistent-id",
+			NameIDFormat:   string(saml.PersistentNameIDFormat),
+			UserName:       "alice",
+			UserCommonName: "Alice",
+		})
+		require.NoError(t, err)
+		form, err := req.PostBinding()
+		require.NoError(t, err)
+		return form.SAMLResponse
+	}
+	acs := func(t *testing.T, form url.Values) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, acsURL, strings.NewReader(form.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+		if err := p.ACSHandler(c); err != nil {
+			he, ok := err.(*echo.HTTPError)
+			require.True(t, ok, err)
+			rec.Code = he.Code
+		}
+		return rec
+	}
+
+	t.Run("missing RelayState", func(t *testing.T) {
+		t.Parallel()
+		rec := acs(t, url.Values{"SAMLResponse": {makeResponse(t, "id-missing")}})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("unknown RelayState", func(t *testing.T) {
+		t.Parallel()
+		rec := acs(t, url.Values{"SAMLResponse": {makeResponse(t, "id-unknown")}, "RelayState": {"unknown"}})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("response to another request", func(t *testing.T) {
+		t.Parallel()
+		p.states.add("state-another", "id-another")
+		rec := acs(t, url.Values{"SAMLResponse": {makeResponse(t, "id-other")}, "RelayState": {"state-another"}})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("tampered response", func(t *testing.T) {
+		t.Parallel()
+		p.states.add("state-tampered", "id-tampered")
+		b, err := base64.StdEncoding.DecodeString(makeResponse(t, "id-tampered"))
+		require.NoError(t, err)
+		tampered := base64.StdEncoding.EncodeToString([]byte(strings.Replace(string(b), "alice", "admin", 1)))
+		rec := acs(t, url.Values{"SAMLResponse": {tampered}, "RelayState": {"state-tampered"}})
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		_, ok := p.states.popUser("state-tampered")
+		assert.False(t, ok)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		p.states.add("state-success", "id-success")
+		form := url.Values{"SAMLResponse": {makeResponse(t, "id-success")}, "RelayState": {"state-success"}}
+		rec := acs(t, form)
+		if assert.Equal(t, http.StatusSeeOther, rec.Code) {
+			assert.Equal(t, callbackURL+"?state=state-success", rec.Header().Get(echo.HeaderLocation))
+		}
+		u, ok := p.states.popUser("state-success")
+		if assert.True(t, ok) {
+			assert.Equal(t, &samlUserInfo{nameID: "persistent-id", name: "alice", displayName: "Alice"}, u)
+		}
+
+		// 同じレスポンスは再利用できない
+		rec = acs(t, form)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestSAMLProvider_CallbackHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		normal   = &model.User{ID: uuid.Must(uuid.NewV4()), Name: "alice", Role: "user", Status: model.UserAccountStatusActive}
+		enrolled = &model.User{ID: uuid.Must(uuid.NewV4()), Name: "bob", Role: "user", Status: model.UserAccountStatusActive}
+	)
+	repo := &testRepository{
+		users: map[string]*model.User{
+			"alice-id": normal,
+			"bob-id":   enrolled,
+		},
+		totp: map[uuid.UUID]*model.UserTOTP{
+			enrolled.ID: {UserID: enrolled.ID, Enabled: true},
+		},
+	}
+
+	callback := func(t *testing.T, user *samlUserInfo) (*httptest.ResponseRecorder, *testSessionStore) {
+		t.Helper()
+		store := &testSessionStore{}
+		p := &SAMLProvider{
+			repo:      repo,
+			logger:    zap.NewNop(),
+			sessStore: store,
+			states:    newSAMLStateStore(),
+		}
+		p.states.add("state", "id")
+		p.states.setUser("state", user)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/auth/saml/callback?state=state", nil)
+		req.AddCookie(&http.Cookie{Name: cookieName, Value: "state"})
+		rec := httptest.NewRecorder()
+		require.NoError(t, p.CallbackHandler(echo.New().NewContext(req, rec)))
+		return rec, store
+	}
+
+	t.Run("user without two-factor", func(t *testing.T) {
+		t.Parallel()
+		rec, store := callback(t, &samlUserInfo{nameID: "alice-id", name: "alice"})
+		assert.Equal(t, http.StatusFound, rec.Code)
+		assert.Equal(t, "/", rec.Header().Get(echo.HeaderLocation))
+		if assert.NotNil(t, store.current) {
+			assert.Equal(t, normal.ID, store.current.UserID())
+		}
+	})
+
+	t.Run("enrolled user", func(t *testing.T) {
+		t.Parallel()
+		rec, store := callback(t, &samlUserInfo{nameID: "bob-id", name: "bob"})
+		assert.Equal(t, http.StatusFound, rec.Code)
+		assert.Equal(t, twoFactorLoginPath, rec.Header().Get(echo.HeaderLocation))
+		// SAMLでの認証のみではログイン済みのセッションは発行されない
+		if assert.NotNil(t, store.current) {
+			assert.False(t, store.current.LoggedIn())
+			assert.Equal(t, enrolled.ID.String(), store.current.data[session.KeyTwoFactorUserID])
+		}
+	})
+}
@@ -3,6 +3,7 @@ package router
 import (
 	"github.com/traPtitech/traQ/router/auth"
 	"github.com/traPtitech/traQ/router/oauth2"
+	"github.com/traPtitech/traQ/router/scim"
 	v3 "github.com/traPtitech/traQ/router/v3"
 )
 
@@ -28,6 +29,12 @@ type Config struct {
 	SkyWaySecretKey string
 	// ExternalAuth 外部認証設定
 	ExternalAuth ExternalAuthConfig
+	// Origin サーバーオリジン
+	Origin string
+	// SCIMToken SCIMプロビジョニングAPIのトークン 空の場合はSCIM APIを無効にします
+	SCIMToken string
+	// SCIMGroupAdmin SCIMで作成したグループの管理者にするユーザーの名前
+	SCIMGroupAdmin string
 }
 
 // ExternalAuthConfig 外部認証設定
@@ -42,6 +49,8 @@ type ExternalAuthConfig struct {
 	OIDC auth.OIDCProviderConfig
 	// Slack Slack OAuth2
 	Slack auth.SlackProviderConfig
+	// SAML SAML 2.0
+	SAML auth.SAMLProviderConfig
 }
 
 func (c ExternalAuthConfig) ValidProviders() map[string]bool {
@@ -61,6 +70,9 @@ func (c ExternalAuthConfig) ValidProviders() map[string]bool {
 	if c.Slack.Valid() {
 		res[auth.SlackProviderName] = true
 	}
+	if c.SAML.Valid() {
+		res[auth.SAMLProviderName] = true
+	}
 	return res
 }
 
@@ -68,6 +80,15 @@ func provideOAuth2Config(c *Config) oauth2.Config {
 	return oauth2.Config{
 		AccessTokenExp:   c.AccessTokenExp,
 		IsRefreshEnabled: c.IsRefreshEnabled,
+		Origin:           c.Origin,
+	}
+}
+
+func provideSCIMConfig(c *Config) scim.Config {
+	return scim.Config{
+		Token:      c.SCIMToken,
+		GroupAdmin: c.SCIMGroupAdmin,
+		Origin:     c.Origin,
 	}
 }
 
@@ -78,5 +99,6 @@ func provideV3Config(c *Config) v3.Config {
 		SkyWaySecretKey:                 c.SkyWaySecretKey,
 		AllowSignUp:                     c.AllowSignUp,
 		EnabledExternalAccountProviders: c.ExternalAuth.ValidProviders(),
+		Origin:                          c.Origin,
 	}
 }
@@ -1,16 +1,19 @@
 package consts
 
 const (
-	HeaderCacheControl      = "Cache-Control"
-	HeaderETag              = "ETag"
-	HeaderIfMatch           = "If-Match"
-	HeaderIfNoneMatch       = "If-None-Match"
-	HeaderIfModifiedSince   = "If-Modified-Since"
-	HeaderIfUnmodifiedSince = "If-Unmodified-Since"
-	HeaderFileMetaType      = "X-TRAQ-FILE-TYPE"
-	HeaderCacheFile         = "X-TRAQ-FILE-CACHE"
-	HeaderSignature         = "X-TRAQ-Signature"
-	HeaderChannelID         = "X-TRAQ-Channel-Id"
-	HeaderMore              = "X-TRAQ-More"
-	HeaderVersion           = "X-TRAQ-VERSION"
+	HeaderCacheControl       = "Cache-Control"
+	HeaderETag               = "ETag"
+	HeaderIfMatch            = "If-Match"
+	HeaderIfNoneMatch        = "If-None-Match"
+	HeaderIfModifiedSince    = "If-Modified-Since"
+	HeaderIfUnmodifiedSince  = "If-Unmodified-Since"
+	HeaderFileMetaType       = "X-TRAQ-FILE-TYPE"
+	HeaderCacheFile          = "X-TRAQ-FILE-CACHE"
+	HeaderSignature          = "X-TRAQ-Signature"
+	HeaderChannelID          = "X-TRAQ-Channel-Id"
+	HeaderMore               = "X-TRAQ-More"
+	HeaderVersion            = "X-TRAQ-VERSION"
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
 )
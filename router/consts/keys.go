@@ -4,6 +4,7 @@ const (
 	KeyUserID             = "userID"
 	KeyUser               = "user"
 	KeyOAuth2AccessScopes = "scopes"
+	KeyOAuth2ClientID     = "oauth2ClientID"
 	KeyParamStamp         = "paramStamp"
 	KeyParamStampPalette  = "paramStampPalette"
 	KeyParamGroup         = "paramGroup"
@@ -15,6 +16,7 @@ const (
 	KeyParamChannel       = "paramChannel"
 	KeyParamFile          = "paramFile"
 	KeyParamClipFolder    = "paramClipFolder"
+	KeyParamTemplate      = "paramTemplate"
 	KeyRepo               = "_repo"
 	KeyChannelManager     = "_cm"
 )
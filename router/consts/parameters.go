@@ -17,5 +17,9 @@ const (
 	ParamBotID          = "botID"
 	ParamClientID       = "clientID"
 	ParamClipFolderID   = "folderID"
+	ParamTemplateID     = "templateID"
+	ParamCredentialID   = "credentialID"
+	ParamSectionID      = "sectionID"
+	ParamRoleName       = "roleName"
 	ParamURL            = "url"
 )
@@ -28,6 +28,10 @@ func Unauthorized(err ...interface{}) error {
 	return HTTPError(http.StatusUnauthorized, err)
 }
 
+func TooManyRequests(err ...interface{}) error {
+	return HTTPError(http.StatusTooManyRequests, err)
+}
+
 func HTTPError(code int, err interface{}) error {
 	switch v := err.(type) {
 	case []interface{}:
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/gofrs/uuid"
 	"github.com/labstack/echo/v4"
 
 	"github.com/traPtitech/traQ/model"
@@ -14,6 +15,7 @@ import (
 	"github.com/traPtitech/traQ/service/message"
 	"github.com/traPtitech/traQ/service/rbac"
 	"github.com/traPtitech/traQ/service/rbac/permission"
+	"github.com/traPtitech/traQ/service/rbac/role"
 )
 
 // AccessControlMiddlewareGenerator アクセスコントロールミドルウェアのジェネレーターを返します
@@ -22,13 +24,8 @@ func AccessControlMiddlewareGenerator(r rbac.RBAC) func(p ...permission.Permissi
 		return func(next echo.HandlerFunc) echo.HandlerFunc {
 			return func(c echo.Context) error {
 				// OAuth2スコープ権限検証
-				if scopes, ok := c.Get(consts.KeyOAuth2AccessScopes).(model.AccessScopes); ok {
-					for _, v := range p {
-						if !r.IsAnyGranted(scopes.StringArray(), v) {
-							// NG
-							return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("you are not permitted to request to '%s'", c.Request().URL.Path))
-						}
-					}
+				if err := checkOAuth2Scopes(c, r, p); err != nil {
+					return err
 				}
 
 				// ユーザー権限検証
@@ -46,6 +43,126 @@ func AccessControlMiddlewareGenerator(r rbac.RBAC) func(p ...permission.Permissi
 	}
 }
 
+// ChannelAccessControlMiddlewareGenerator チャンネルロールを考慮したアクセスコントロールミドルウェアのジェネレーターを返します
+//
+// ユーザーロールで権限が付与されていない場合でも、対象チャンネルまたはその祖先チャンネルで
+// 割り当てられたチャンネルロールで権限が付与されていれば、アクセスを許可します。
+// 対象チャンネルはParamRetrieverで取得したチャンネルまたはメッセージから決定します。
+func ChannelAccessControlMiddlewareGenerator(r rbac.RBAC, cm channel.Manager) func(p ...permission.Permission) echo.MiddlewareFunc {
+	return func(p ...permission.Permission) echo.MiddlewareFunc {
+		return func(next echo.HandlerFunc) echo.HandlerFunc {
+			return func(c echo.Context) error {
+				// OAuth2スコープ権限検証
+				if err := checkOAuth2Scopes(c, r, p); err != nil {
+					return err
+				}
+
+				// ユーザー権限検証
+				user := c.Get(consts.KeyUser).(model.UserInfo)
+				var channelRoles []string
+				for _, v := range p {
+					if r.IsGranted(user.GetRole(), v) {
+						continue
+					}
+
+					// チャンネルロール権限検証
+					if channelRoles == nil {
+						channelID, ok := requestChannelID(c)
+						if !ok {
+							return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("you are not permitted to request to '%s'", c.Request().URL.Path))
+						}
+						roles, err := cm.GetUserChannelRoles(user.GetID(), channelID)
+						if err != nil {
+							return herror.InternalServerError(err)
+						}
+						channelRoles = roles
+					}
+					if !role.IsChannelRoleGranted(channelRoles, v) {
+						// NG
+						return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("you are not permitted to request to '%s'", c.Request().URL.Path))
+					}
+				}
+
+				return next(c) // OK
+			}
+		}
+	}
+}
+
+// checkOAuth2Scopes リクエストのOAuth2トークンのスコープで権限が付与されているかどうかを検証します
+func checkOAuth2Scopes(c echo.Context, r rbac.RBAC, p []permission.Permission) error {
+	scopes, ok := c.Get(consts.KeyOAuth2AccessScopes).(model.AccessScopes)
+	if !ok {
+		return nil
+	}
+
+	for _, v := range p {
+		if !r.IsAnyGranted(scopes.StringArray(), v) {
+			// NG
+			return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("you are not permitted to request to '%s'", c.Request().URL.Path))
+		}
+	}
+
+	// チャンネル制限付きトークンは、制限外のチャンネルや、チャンネル・メッセージを特定できないAPIでチャンネルの内容を操作できない
+	if _, restricted := scopes.RestrictedChannels(); restricted && !isChannelAllowedRequest(c, scopes) {
+		for _, v := range p {
+			if channelScopedPermissions.Contains(v) {
+				return echo.NewHTTPError(http.StatusForbidden, fmt.Sprintf("this token is restricted to specific channels and not permitted to request to '%s'", c.Request().URL.Path))
+			}
+		}
+	}
+	return nil
+}
+
+// requestChannelID リクエストの対象チャンネルのIDを返します
+func requestChannelID(c echo.Context) (uuid.UUID, bool) {
+	if ch, ok := c.Get(consts.KeyParamChannel).(*model.Channel); ok {
+		return ch.ID, true
+	}
+	if m, ok := c.Get(consts.KeyParamMessage).(message.Message); ok {
+		return m.GetChannelID(), true
+	}
+	return uuid.Nil, false
+}
+
+// channelScopedPermissions チャンネル制限付きトークンで対象チャンネルの検証が必要な権限
+var channelScopedPermissions = permission.PermissionsFromArray([]permission.Permission{
+	permission.GetMessage,
+	permission.PostMessage,
+	permission.EditMessage,
+	permission.DeleteMessage,
+	permission.ReportMessage,
+	permission.CreateMessagePin,
+	permission.DeleteMessagePin,
+	permission.AddMessageStamp,
+	permission.RemoveMessageStamp,
+	permission.GetUnread,
+	permission.DeleteUnread,
+	permission.EditChannelTopic,
+	permission.EditChannelSubscription,
+	// 通知ストリームは全チャンネルのイベントを配信するため、制限付きトークンでは接続できない
+	permission.ConnectNotificationStream,
+})
+
+// isChannelAllowedRequest 制限付きトークンで操作可能なチャンネルを対象とするリクエストかどうか
+//
+// メッセージを対象とするリクエストの場合、チャンネルの検証はCheckMessageAccessPermで行います。
+func isChannelAllowedRequest(c echo.Context, scopes model.AccessScopes) bool {
+	if cid := c.Param(consts.ParamChannelID); len(cid) > 0 {
+		return scopes.IsChannelAllowed(uuid.FromStringOrNil(cid))
+	}
+	return len(c.Param(consts.ParamMessageID)) > 0
+}
+
+// isChannelAllowedForToken リクエストのトークンで指定したチャンネルの操作が許可されているかどうか
+func isChannelAllowedForToken(c echo.Context, channelID uuid.UUID) bool {
+	scopes, ok := c.Get(consts.KeyOAuth2AccessScopes).(model.AccessScopes)
+	if !ok {
+		return true
+	}
+	return scopes.IsChannelAllowed(channelID)
+}
+
 // BlockBot Botのリクエストを制限するミドルウェア
 func BlockBot() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
@@ -124,6 +241,11 @@ func CheckFileAccessPerm(fm file.Manager) echo.MiddlewareFunc {
 				return next(c)
 			}
 
+			// トークンのチャンネル制限確認
+			if cid := f.GetUploadChannelID(); cid.Valid && !isChannelAllowedForToken(c, cid.UUID) {
+				return herror.Forbidden()
+			}
+
 			// アクセス権確認
 			if ok, err := fm.Accessible(f.GetID(), userID); err != nil {
 				return herror.InternalServerError(err)
@@ -160,6 +282,11 @@ func CheckMessageAccessPerm(cm channel.Manager) echo.MiddlewareFunc {
 			userID := c.Get(consts.KeyUser).(model.UserInfo).GetID()
 			channelID := c.Get(consts.KeyParamMessage).(message.Message).GetChannelID()
 
+			// トークンのチャンネル制限確認
+			if !isChannelAllowedForToken(c, channelID) {
+				return herror.NotFound()
+			}
+
 			// アクセス権確認
 			if ok, err := cm.IsChannelAccessibleToUser(userID, channelID); err != nil {
 				return herror.InternalServerError(err)
@@ -179,6 +306,11 @@ func CheckChannelAccessPerm(cm channel.Manager) echo.MiddlewareFunc {
 			userID := c.Get(consts.KeyUser).(model.UserInfo).GetID()
 			ch := c.Get(consts.KeyParamChannel).(*model.Channel)
 
+			// トークンのチャンネル制限確認
+			if !isChannelAllowedForToken(c, ch.ID) {
+				return herror.NotFound()
+			}
+
 			// アクセス権確認
 			if ok, err := cm.IsChannelAccessibleToUser(userID, ch.ID); err != nil {
 				return herror.InternalServerError(err)
@@ -0,0 +1,70 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/router/consts"
+	"github.com/traPtitech/traQ/service/rbac"
+	"github.com/traPtitech/traQ/service/rbac/permission"
+)
+
+type testRBAC struct {
+	rbac.RBAC
+}
+
+func (r *testRBAC) IsGranted(string, permission.Permission) bool { return true }
+func (r *testRBAC) IsAnyGranted([]string, permission.Permission) bool {
+	return true
+}
+
+func TestAccessControlMiddlewareGenerator_ChannelRestrictedToken(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{ID: uuid.Must(uuid.NewV4()), Role: "user"}
+	channelID := uuid.Must(uuid.NewV4())
+	requires := AccessControlMiddlewareGenerator(&testRBAC{})
+	next := func(c echo.Context) error { return c.NoContent(http.StatusNoContent) }
+
+	do := func(t *testing.T, scopes model.AccessScopes, path string, p permission.Permission) int {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, path, nil), rec)
+		c.Set(consts.KeyUser, user)
+		if scopes != nil {
+			c.Set(consts.KeyOAuth2AccessScopes, scopes)
+		}
+		if err := requires(p)(next)(c); err != nil {
+			return err.(*echo.HTTPError).Code
+		}
+		return rec.Code
+	}
+	restricted := model.AccessScopes{"read": {}}
+	restricted.Add(model.ChannelRestrictionScope(channelID))
+
+	t.Run("notification stream with restricted token", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, http.StatusForbidden, do(t, restricted, "/api/v3/ws", permission.ConnectNotificationStream))
+	})
+
+	t.Run("notification stream with unrestricted token", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, http.StatusNoContent, do(t, model.AccessScopes{"read": {}}, "/api/v3/ws", permission.ConnectNotificationStream))
+	})
+
+	t.Run("notification stream with session", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, http.StatusNoContent, do(t, nil, "/api/v3/ws", permission.ConnectNotificationStream))
+	})
+
+	t.Run("non channel scoped permission with restricted token", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, http.StatusNoContent, do(t, restricted, "/api/v3/users/me", permission.GetMe))
+	})
+}
@@ -158,6 +158,13 @@ func (pr *ParamRetriever) StampPalettesID() echo.MiddlewareFunc {
 	})
 }
 
+// ChannelTemplateID リクエストURLの`templateID`パラメータからChannelTemplateを取り出す
+func (pr *ParamRetriever) ChannelTemplateID() echo.MiddlewareFunc {
+	return pr.byUUID(consts.ParamTemplateID, consts.KeyParamTemplate, func(c echo.Context, v uuid.UUID) (interface{}, error) {
+		return pr.repo.GetChannelTemplate(v)
+	})
+}
+
 // UserID リクエストURLの`userID`パラメータからUserを取り出す
 func (pr *ParamRetriever) UserID(checkOnly bool) echo.MiddlewareFunc {
 	if checkOnly {
@@ -0,0 +1,69 @@
+package middlewares
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/router/consts"
+	"github.com/traPtitech/traQ/router/extension/herror"
+	"github.com/traPtitech/traQ/service/ratelimit"
+)
+
+// RateLimitMiddlewareGenerator レート制限ミドルウェアのジェネレーターを返します
+//
+// storeがnilの場合、レート制限は行われません。
+// レート制限はOAuth2クライアントID・ユーザーID・IPアドレスの順で決定したキー毎に行われます。
+func RateLimitMiddlewareGenerator(store ratelimit.Store) func(rule ratelimit.Rule) echo.MiddlewareFunc {
+	return func(rule ratelimit.Rule) echo.MiddlewareFunc {
+		return func(next echo.HandlerFunc) echo.HandlerFunc {
+			if store == nil {
+				return next
+			}
+			return func(c echo.Context) error {
+				key, role := rateLimitKey(c)
+				rate := rule.RateFor(role)
+				if !rate.Valid() {
+					return next(c)
+				}
+
+				res, err := store.Take(rule.Key(key), rate)
+				if err != nil {
+					return herror.InternalServerError(err)
+				}
+
+				h := c.Response().Header()
+				h.Set(consts.HeaderRateLimitLimit, strconv.Itoa(res.Limit))
+				h.Set(consts.HeaderRateLimitRemaining, strconv.Itoa(res.Remaining))
+				h.Set(consts.HeaderRateLimitReset, strconv.FormatInt(ceilSeconds(res.Reset), 10))
+				if !res.Allowed {
+					h.Set(echo.HeaderRetryAfter, strconv.FormatInt(ceilSeconds(res.RetryAfter), 10))
+					return herror.TooManyRequests("rate limit exceeded")
+				}
+				return next(c)
+			}
+		}
+	}
+}
+
+// rateLimitKey リクエストのレート制限キーとロールを返します
+func rateLimitKey(c echo.Context) (key string, role string) {
+	user, ok := c.Get(consts.KeyUser).(model.UserInfo)
+	if !ok {
+		return "ip:" + c.RealIP(), ""
+	}
+	if clientID, ok := c.Get(consts.KeyOAuth2ClientID).(string); ok && len(clientID) > 0 {
+		return "client:" + clientID + ":" + user.GetID().String(), user.GetRole()
+	}
+	return ratelimit.UserKey(user.GetID()), user.GetRole()
+}
+
+func ceilSeconds(d time.Duration) int64 {
+	if d <= 0 {
+		return 0
+	}
+	return int64(math.Ceil(d.Seconds()))
+}
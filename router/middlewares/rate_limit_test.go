@@ -0,0 +1,136 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/router/consts"
+	"github.com/traPtitech/traQ/service/ratelimit"
+)
+
+type testRateLimitStore struct {
+	keys   []string
+	result ratelimit.Result
+	err    error
+}
+
+func (s *testRateLimitStore) Take(key string, _ ratelimit.Rate) (ratelimit.Result, error) {
+	s.keys = append(s.keys, key)
+	return s.result, s.err
+}
+
+func TestRateLimitMiddlewareGenerator(t *testing.T) {
+	t.Parallel()
+
+	rule := ratelimit.Rule{
+		Name:    "test",
+		Default: ratelimit.Rate{Limit: 2, Period: time.Minute},
+		Roles: map[string]ratelimit.Rate{
+			"unlimited": {},
+		},
+	}
+	user := &model.User{ID: uuid.Must(uuid.NewV4()), Role: "user"}
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusNoContent) }
+
+	newContext := func(setup func(c echo.Context)) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set(echo.HeaderXRealIP, "192.0.2.1")
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+		if setup != nil {
+			setup(c)
+		}
+		return c, rec
+	}
+
+	t.Run("nil store", func(t *testing.T) {
+		t.Parallel()
+		h := RateLimitMiddlewareGenerator(nil)(rule)(ok)
+		c, rec := newContext(nil)
+		if assert.NoError(t, h(c)) {
+			assert.Equal(t, http.StatusNoContent, rec.Code)
+			assert.Empty(t, rec.Header().Get(consts.HeaderRateLimitLimit))
+		}
+	})
+
+	t.Run("keys", func(t *testing.T) {
+		t.Parallel()
+		store := &testRateLimitStore{result: ratelimit.Result{Allowed: true}}
+		h := RateLimitMiddlewareGenerator(store)(rule)(ok)
+
+		c, _ := newContext(nil)
+		assert.NoError(t, h(c))
+		c, _ = newContext(func(c echo.Context) { c.Set(consts.KeyUser, user) })
+		assert.NoError(t, h(c))
+		c, _ = newContext(func(c echo.Context) {
+			c.Set(consts.KeyUser, user)
+			c.Set(consts.KeyOAuth2ClientID, "client")
+		})
+		assert.NoError(t, h(c))
+
+		assert.Equal(t, []string{
+			"test:ip:192.0.2.1",
+			"test:user:" + user.ID.String(),
+			"test:client:client:" + user.ID.String(),
+		}, store.keys)
+	})
+
+	t.Run("unlimited role", func(t *testing.T) {
+		t.Parallel()
+		store := &testRateLimitStore{}
+		h := RateLimitMiddlewareGenerator(store)(rule)(ok)
+		c, rec := newContext(func(c echo.Context) {
+			c.Set(consts.KeyUser, &model.User{ID: uuid.Must(uuid.NewV4()), Role: "unlimited"})
+		})
+		if assert.NoError(t, h(c)) {
+			assert.Equal(t, http.StatusNoContent, rec.Code)
+			assert.Empty(t, store.keys)
+		}
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		t.Parallel()
+		store := &testRateLimitStore{err: errors.New("store error")}
+		h := RateLimitMiddlewareGenerator(store)(rule)(ok)
+		c, _ := newContext(nil)
+		assert.Error(t, h(c))
+	})
+
+	t.Run("too many requests", func(t *testing.T) {
+		t.Parallel()
+		h := RateLimitMiddlewareGenerator(ratelimit.NewMemoryStore())(rule)(ok)
+
+		for i := 0; i < rule.Default.Limit; i++ {
+			c, rec := newContext(func(c echo.Context) { c.Set(consts.KeyUser, user) })
+			if assert.NoError(t, h(c)) {
+				assert.Equal(t, http.StatusNoContent, rec.Code)
+				assert.Equal(t, "2", rec.Header().Get(consts.HeaderRateLimitLimit))
+				assert.Empty(t, rec.Header().Get(echo.HeaderRetryAfter))
+			}
+		}
+
+		c, rec := newContext(func(c echo.Context) { c.Set(consts.KeyUser, user) })
+		err := h(c)
+		var he *echo.HTTPError
+		if assert.ErrorAs(t, err, &he) {
+			assert.Equal(t, http.StatusTooManyRequests, he.Code)
+		}
+		assert.Equal(t, "0", rec.Header().Get(consts.HeaderRateLimitRemaining))
+		assert.Equal(t, "30", rec.Header().Get(echo.HeaderRetryAfter))
+		assert.Equal(t, "60", rec.Header().Get(consts.HeaderRateLimitReset))
+
+		// 別のIPアドレスからの未ログインのリクエストは制限されない
+		c, rec = newContext(nil)
+		if assert.NoError(t, h(c)) {
+			assert.Equal(t, http.StatusNoContent, rec.Code)
+		}
+	})
+}
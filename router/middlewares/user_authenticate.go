@@ -2,6 +2,8 @@ package middlewares
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/labstack/echo/v4"
@@ -15,7 +17,12 @@ import (
 	"github.com/traPtitech/traQ/router/session"
 )
 
-const authScheme = "Bearer"
+const (
+	authScheme = "Bearer"
+
+	// personalAccessTokenLastUsedInterval パーソナルアクセストークンの最終使用日時を記録する最小間隔
+	personalAccessTokenLastUsedInterval = time.Minute
+)
 
 // UserAuthenticate リクエスト認証ミドルウェア
 func UserAuthenticate(repo repository.Repository, sessStore session.Store) echo.MiddlewareFunc {
@@ -34,24 +41,53 @@ func UserAuthenticate(repo repository.Repository, sessStore session.Store) echo.
 					return herror.Unauthorized("invalid authorization scheme")
 				}
 
-				// OAuth2 Token検証
-				token, err := repo.GetTokenByAccess(ah[l+1:])
-				if err != nil {
-					switch err {
-					case repository.ErrNotFound:
+				if model.IsPersonalAccessToken(ah[l+1:]) {
+					// パーソナルアクセストークン検証
+					pat, err := repo.GetPersonalAccessTokenByHash(model.HashPersonalAccessToken(ah[l+1:]))
+					if err != nil {
+						switch err {
+						case repository.ErrNotFound:
+							return herror.Unauthorized("invalid token")
+						default:
+							return herror.InternalServerError(err)
+						}
+					}
+
+					// tokenの有効期限の検証
+					if pat.IsExpired() {
 						return herror.Unauthorized("invalid token")
-					default:
-						return herror.InternalServerError(err)
 					}
-				}
 
-				// tokenの有効期限の検証
-				if token.IsExpired() {
-					return herror.Unauthorized("invalid token")
-				}
+					// 最終使用日時・IPアドレスの記録
+					if ip := c.RealIP(); !pat.LastUsedAt.Valid || time.Since(pat.LastUsedAt.Time) > personalAccessTokenLastUsedInterval || pat.LastUsedIP != ip {
+						if err := repo.UpdatePersonalAccessTokenLastUsed(pat.ID, time.Now(), ip); err != nil {
+							return herror.InternalServerError(err)
+						}
+					}
+
+					c.Set(consts.KeyOAuth2AccessScopes, pat.Scopes)
+					uid = pat.UserID
+				} else {
+					// OAuth2 Token検証
+					token, err := repo.GetTokenByAccess(ah[l+1:])
+					if err != nil {
+						switch err {
+						case repository.ErrNotFound:
+							return herror.Unauthorized("invalid token")
+						default:
+							return herror.InternalServerError(err)
+						}
+					}
+
+					// tokenの有効期限の検証
+					if token.IsExpired() {
+						return herror.Unauthorized("invalid token")
+					}
 
-				c.Set(consts.KeyOAuth2AccessScopes, token.Scopes)
-				uid = token.UserID
+					c.Set(consts.KeyOAuth2AccessScopes, token.Scopes)
+					c.Set(consts.KeyOAuth2ClientID, token.ClientID)
+					uid = token.UserID
+				}
 			} else {
 				// Authorizationヘッダーがないためセッションを確認する
 				sess, err := sessStore.GetSession(c)
@@ -63,6 +99,21 @@ func UserAuthenticate(repo repository.Repository, sessStore session.Store) echo.
 				}
 
 				uid = sess.UserID()
+
+				// 二要素認証の登録が必要なセッションは登録用APIのみ利用可能
+				if required, _ := sess.Get(session.KeyTwoFactorEnrollmentRequired); required == true {
+					enrolled, err := HasTwoFactorAuth(repo, uid)
+					if err != nil {
+						return herror.InternalServerError(err)
+					}
+					if enrolled {
+						if err := sess.Delete(session.KeyTwoFactorEnrollmentRequired); err != nil {
+							return herror.InternalServerError(err)
+						}
+					} else if !isTwoFactorEnrollmentPath(c.Path()) {
+						return herror.Forbidden("two-factor authentication is required for your account. Please enroll it first.")
+					}
+				}
 			}
 
 			// ユーザー取得
@@ -84,3 +135,24 @@ func UserAuthenticate(repo repository.Repository, sessStore session.Store) echo.
 		}
 	}
 }
+
+// HasTwoFactorAuth ユーザーが二要素認証を登録済みかどうか
+func HasTwoFactorAuth(repo repository.Repository, userID uuid.UUID) (bool, error) {
+	totp, err := repo.GetUserTOTP(userID)
+	if err != nil && err != repository.ErrNotFound {
+		return false, err
+	}
+	if totp != nil && totp.Enabled {
+		return true, nil
+	}
+	creds, err := repo.GetWebAuthnCredentials(userID)
+	if err != nil {
+		return false, err
+	}
+	return len(creds) > 0, nil
+}
+
+// isTwoFactorEnrollmentPath 二要素認証の登録に必要なAPIのパスかどうか
+func isTwoFactorEnrollmentPath(path string) bool {
+	return strings.HasSuffix(path, "/users/me") || strings.Contains(path, "/users/me/two-factor")
+}
@@ -51,6 +51,8 @@ type Config struct {
 	AccessTokenExp int
 	// IsRefreshEnabled リフレッシュトークンを発行するかどうか
 	IsRefreshEnabled bool
+	// Origin サーバーオリジン (OpenID ProviderのIssuer識別子に使用)
+	Origin string
 }
 
 func (h *Handler) Setup(e *echo.Group) {
@@ -59,6 +61,10 @@ func (h *Handler) Setup(e *echo.Group) {
 	e.POST("/authorize", h.AuthorizationEndpointHandler)
 	e.POST("/token", h.TokenEndpointHandler)
 	e.POST("/revoke", h.RevokeTokenEndpointHandler)
+	e.GET("/userinfo", h.UserInfoEndpointHandler)
+	e.POST("/userinfo", h.UserInfoEndpointHandler)
+	e.GET("/jwks", h.JWKSHandler)
+	e.GET("/.well-known/openid-configuration", h.DiscoveryHandler)
 }
 
 // splitAndValidateScope スペース区切りのスコープ文字列を分解し、検証します
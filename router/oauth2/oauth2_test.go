@@ -27,6 +27,7 @@ import (
 	"github.com/traPtitech/traQ/router/session"
 	"github.com/traPtitech/traQ/service/rbac/role"
 	"github.com/traPtitech/traQ/testutils"
+	"github.com/traPtitech/traQ/utils/jwt"
 	"github.com/traPtitech/traQ/utils/random"
 )
 
@@ -35,6 +36,8 @@ const (
 	db1      = "db1"
 	db2      = "db2"
 	rand     = "random"
+
+	testOrigin = "http://test"
 )
 
 var envs = map[string]*Env{}
@@ -48,6 +51,10 @@ func TestMain(m *testing.M) {
 		db1,
 		db2,
 	}
+	privRaw, _ := random.GenerateECDSAKey()
+	if err := jwt.SetupSigner(privRaw); err != nil {
+		panic(err)
+	}
 	if err := migration.CreateDatabasesIfNotExists("mysql", fmt.Sprintf("%s:%s@tcp(%s:%s)/?charset=utf8mb4&parseTime=true", user, pass, host, port), dbPrefix, dbs...); err != nil {
 		panic(err)
 	}
@@ -103,6 +110,7 @@ func TestMain(m *testing.M) {
 			Config: Config{
 				AccessTokenExp:   1000,
 				IsRefreshEnabled: true,
+				Origin:           testOrigin,
 			},
 		}
 		config.Setup(e.Group("/oauth2"))
@@ -0,0 +1,203 @@
+package oauth2
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/service/rbac/role"
+	jwt2 "github.com/traPtitech/traQ/utils/jwt"
+)
+
+const (
+	scopeOpenID = model.AccessScope("openid")
+
+	// idTokenExp IDトークンの有効時間(秒)
+	idTokenExp = 60 * 60
+
+	errInvalidToken      = "invalid_token"
+	errInsufficientScope = "insufficient_scope"
+)
+
+// idTokenClaims IDトークンのクレーム
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce             string `json:"nonce,omitempty"`
+	AccessTokenHash   string `json:"at_hash,omitempty"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+	Picture           string `json:"picture"`
+	UpdatedAt         int64  `json:"updated_at"`
+}
+
+type userInfoResponse struct {
+	Subject           string `json:"sub"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+	Picture           string `json:"picture"`
+	UpdatedAt         int64  `json:"updated_at"`
+}
+
+type discoveryResponse struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserInfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	RevocationEndpoint                string   `json:"revocation_endpoint"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
+
+// supportedScopes 利用可能なスコープ
+var supportedScopes = []string{
+	string(scopeOpenID), role.Read, role.Write, role.ManageBot,
+	role.ChannelsRead, role.ChannelsWrite,
+	role.MessagesRead, role.MessagesWrite,
+	role.StampsRead, role.StampsWrite,
+	role.FilesRead, role.FilesWrite,
+	role.UsersRead,
+}
+
+// issuer OpenID ProviderのIssuer識別子を返します
+func (h *Handler) issuer() string {
+	return h.Origin + "/api/v3/oauth2"
+}
+
+// DiscoveryHandler OpenID Provider Configurationのハンドラ
+func (h *Handler) DiscoveryHandler(c echo.Context) error {
+	iss := h.issuer()
+	return c.JSON(http.StatusOK, &discoveryResponse{
+		Issuer:                            iss,
+		AuthorizationEndpoint:             iss + "/authorize",
+		TokenEndpoint:                     iss + "/token",
+		UserInfoEndpoint:                  iss + "/userinfo",
+		JWKSURI:                           iss + "/jwks",
+		RevocationEndpoint:                iss + "/revoke",
+		ScopesSupported:                   supportedScopes,
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{grantTypeAuthorizationCode, grantTypePassword, grantTypeClientCredentials, grantTypeRefreshToken},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{jwt2.SigningAlgorithm()},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{"plain", "S256"},
+		ClaimsSupported:                   []string{"iss", "sub", "aud", "exp", "iat", "nonce", "at_hash", "name", "preferred_username", "picture", "updated_at"},
+	})
+}
+
+// JWKSHandler JWK Setのハンドラ
+func (h *Handler) JWKSHandler(c echo.Context) error {
+	return c.JSON(http.StatusOK, jwt2.JWKS())
+}
+
+// UserInfoEndpointHandler UserInfoエンドポイントのハンドラ
+func (h *Handler) UserInfoEndpointHandler(c echo.Context) error {
+	ah := c.Request().Header.Get(echo.HeaderAuthorization)
+	l := len(authScheme)
+	if !(len(ah) > l+1 && ah[:l] == authScheme) {
+		c.Response().Header().Set(echo.HeaderWWWAuthenticate, authScheme)
+		return c.NoContent(http.StatusUnauthorized)
+	}
+
+	token, err := h.Repo.GetTokenByAccess(ah[l+1:])
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return bearerError(c, http.StatusUnauthorized, errInvalidToken)
+		default:
+			h.L(c).Error(err.Error(), zap.Error(err))
+			return c.NoContent(http.StatusInternalServerError)
+		}
+	}
+	if token.IsExpired() || token.UserID == uuid.Nil {
+		return bearerError(c, http.StatusUnauthorized, errInvalidToken)
+	}
+	if !token.Scopes.Contains(scopeOpenID) {
+		return bearerError(c, http.StatusForbidden, errInsufficientScope)
+	}
+
+	user, err := h.Repo.GetUser(token.UserID, false)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return bearerError(c, http.StatusUnauthorized, errInvalidToken)
+		default:
+			h.L(c).Error(err.Error(), zap.Error(err))
+			return c.NoContent(http.StatusInternalServerError)
+		}
+	}
+	if !user.IsActive() {
+		return bearerError(c, http.StatusUnauthorized, errInvalidToken)
+	}
+
+	c.Response().Header().Set("Cache-Control", "no-store")
+	return c.JSON(http.StatusOK, &userInfoResponse{
+		Subject:           user.GetID().String(),
+		Name:              user.GetResponseDisplayName(),
+		PreferredUsername: user.GetName(),
+		Picture:           h.userPictureURL(user),
+		UpdatedAt:         user.GetUpdatedAt().Unix(),
+	})
+}
+
+func bearerError(c echo.Context, code int, errType string) error {
+	c.Response().Header().Set(echo.HeaderWWWAuthenticate, fmt.Sprintf(`%s error="%s"`, authScheme, errType))
+	return c.NoContent(code)
+}
+
+func (h *Handler) userPictureURL(user model.UserInfo) string {
+	return h.Origin + "/api/v3/public/icon/" + user.GetName()
+}
+
+// issueIDToken IDトークンを発行します
+//
+// トークンのスコープにopenidが含まれない場合、またはユーザーに紐づかないトークンの場合は空文字列を返します。
+func (h *Handler) issueIDToken(token *model.OAuth2Token, nonce string) (string, error) {
+	if !token.Scopes.Contains(scopeOpenID) || token.UserID == uuid.Nil {
+		return "", nil
+	}
+
+	user, err := h.Repo.GetUser(token.UserID, false)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	return jwt2.Sign(&idTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    h.issuer(),
+			Subject:   user.GetID().String(),
+			Audience:  jwt.ClaimStrings{token.ClientID},
+			ExpiresAt: jwt.NewNumericDate(now.Add(idTokenExp * time.Second)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+		Nonce:             nonce,
+		AccessTokenHash:   accessTokenHash(token.AccessToken),
+		Name:              user.GetResponseDisplayName(),
+		PreferredUsername: user.GetName(),
+		Picture:           h.userPictureURL(user),
+		UpdatedAt:         user.GetUpdatedAt().Unix(),
+	})
+}
+
+// accessTokenHash at_hashクレームの値を計算します
+func accessTokenHash(accessToken string) string {
+	hash := jwt2.HashAlgorithm().New()
+	_, _ = hash.Write([]byte(accessToken))
+	sum := hash.Sum(nil)
+	return base64.RawURLEncoding.EncodeToString(sum[:len(sum)/2])
+}
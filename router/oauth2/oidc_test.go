@@ -0,0 +1,192 @@
+package oauth2
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/traPtitech/traQ/model"
+	jwt2 "github.com/traPtitech/traQ/utils/jwt"
+	"github.com/traPtitech/traQ/utils/random"
+)
+
+func TestHandler_DiscoveryHandler(t *testing.T) {
+	t.Parallel()
+	env := Setup(t, db1)
+
+	e := env.R(t)
+	obj := e.GET("/oauth2/.well-known/openid-configuration").
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object()
+
+	obj.Value("issuer").String().Equal(testOrigin + "/api/v3/oauth2")
+	obj.Value("authorization_endpoint").String().Equal(testOrigin + "/api/v3/oauth2/authorize")
+	obj.Value("token_endpoint").String().Equal(testOrigin + "/api/v3/oauth2/token")
+	obj.Value("userinfo_endpoint").String().Equal(testOrigin + "/api/v3/oauth2/userinfo")
+	obj.Value("jwks_uri").String().Equal(testOrigin + "/api/v3/oauth2/jwks")
+	obj.Value("scopes_supported").Array().Contains("openid", "read", "write", "manage_bot", "messages:read", "files:write", "users:read")
+	obj.Value("id_token_signing_alg_values_supported").Array().ContainsOnly("ES256")
+}
+
+func TestHandler_JWKSHandler(t *testing.T) {
+	t.Parallel()
+	env := Setup(t, db1)
+
+	e := env.R(t)
+	keys := e.GET("/oauth2/jwks").
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object().
+		Value("keys").
+		Array()
+
+	keys.Length().Equal(1)
+	key := keys.First().Object()
+	key.Value("kty").String().Equal("EC")
+	key.Value("alg").String().Equal("ES256")
+	key.Value("use").String().Equal("sig")
+	key.Value("kid").String().Equal(jwt2.JWKS().Keys[0].KeyID)
+}
+
+func TestHandler_UserInfoEndpointHandler(t *testing.T) {
+	t.Parallel()
+	env := Setup(t, db1)
+	user := env.CreateUser(t, rand)
+
+	scopesOpenID := model.AccessScopes{}
+	scopesOpenID.Add("openid", "read")
+	client := &model.OAuth2Client{
+		ID:           random.AlphaNumeric(36),
+		Name:         "test client",
+		Confidential: false,
+		CreatorID:    uuid.Must(uuid.NewV4()),
+		Secret:       random.AlphaNumeric(36),
+		RedirectURI:  "http://example.com",
+		Scopes:       scopesOpenID,
+	}
+	require.NoError(t, env.Repository.SaveClient(client))
+	scopesRead := model.AccessScopes{}
+	scopesRead.Add("read")
+	clientRead := &model.OAuth2Client{
+		ID:           random.AlphaNumeric(36),
+		Name:         "test client",
+		Confidential: false,
+		CreatorID:    uuid.Must(uuid.NewV4()),
+		Secret:       random.AlphaNumeric(36),
+		RedirectURI:  "http://example.com",
+		Scopes:       scopesRead,
+	}
+	require.NoError(t, env.Repository.SaveClient(clientRead))
+
+	token := env.IssueToken(t, client, user.GetID(), false)
+	tokenRead := env.IssueToken(t, clientRead, user.GetID(), false)
+
+	t.Run("no token", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET("/oauth2/userinfo").
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET("/oauth2/userinfo").
+			WithHeader("Authorization", authScheme+" "+random.AlphaNumeric(36)).
+			Expect().
+			Status(http.StatusUnauthorized).
+			Header("WWW-Authenticate").
+			Equal(`Bearer error="invalid_token"`)
+	})
+
+	t.Run("insufficient scope", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET("/oauth2/userinfo").
+			WithHeader("Authorization", authScheme+" "+tokenRead.AccessToken).
+			Expect().
+			Status(http.StatusForbidden).
+			Header("WWW-Authenticate").
+			Equal(`Bearer error="insufficient_scope"`)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		obj := e.GET("/oauth2/userinfo").
+			WithHeader("Authorization", authScheme+" "+token.AccessToken).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Object()
+
+		obj.Value("sub").String().Equal(user.GetID().String())
+		obj.Value("preferred_username").String().Equal(user.GetName())
+		obj.Value("name").String().Equal(user.GetResponseDisplayName())
+		obj.Value("picture").String().Equal(testOrigin + "/api/v3/public/icon/" + user.GetName())
+	})
+}
+
+func TestHandlers_TokenEndpointAuthorizationCodeHandler_IDToken(t *testing.T) {
+	t.Parallel()
+	env := Setup(t, db2)
+	user := env.CreateUser(t, rand)
+
+	scopes := model.AccessScopes{}
+	scopes.Add("openid", "read")
+	client := &model.OAuth2Client{
+		ID:           random.AlphaNumeric(36),
+		Name:         "test client",
+		Confidential: false,
+		CreatorID:    uuid.Must(uuid.NewV4()),
+		Secret:       random.AlphaNumeric(36),
+		RedirectURI:  "http://example.com",
+		Scopes:       scopes,
+	}
+	require.NoError(t, env.Repository.SaveClient(client))
+
+	authorize := &model.OAuth2Authorize{
+		Code:           random.AlphaNumeric(36),
+		ClientID:       client.ID,
+		UserID:         user.GetID(),
+		CreatedAt:      time.Now(),
+		ExpiresIn:      1000,
+		RedirectURI:    "http://example.com",
+		Scopes:         scopes,
+		OriginalScopes: scopes,
+		Nonce:          "nonce",
+	}
+	require.NoError(t, env.Repository.SaveAuthorize(authorize))
+
+	e := env.R(t)
+	obj := e.POST("/oauth2/token").
+		WithFormField("grant_type", grantTypeAuthorizationCode).
+		WithFormField("code", authorize.Code).
+		WithFormField("redirect_uri", "http://example.com").
+		WithFormField("client_id", client.ID).
+		Expect().
+		Status(http.StatusOK).
+		JSON().
+		Object()
+
+	accessToken := obj.Value("access_token").String().Raw()
+	idToken := obj.Value("id_token").String().NotEmpty().Raw()
+
+	var claims idTokenClaims
+	require.NoError(t, jwt2.Parse(idToken, &claims))
+	assert.Equal(t, testOrigin+"/api/v3/oauth2", claims.Issuer)
+	assert.Equal(t, user.GetID().String(), claims.Subject)
+	assert.Equal(t, jwt.ClaimStrings{client.ID}, claims.Audience)
+	assert.Equal(t, "nonce", claims.Nonce)
+	assert.Equal(t, accessTokenHash(accessToken), claims.AccessTokenHash)
+	assert.Equal(t, user.GetName(), claims.PreferredUsername)
+}
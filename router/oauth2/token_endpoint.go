@@ -8,8 +8,10 @@ import (
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
 
+	"github.com/traPtitech/traQ/model"
 	"github.com/traPtitech/traQ/repository"
 	"github.com/traPtitech/traQ/router/extension"
+	"github.com/traPtitech/traQ/router/middlewares"
 )
 
 type oauth2ErrorResponse struct {
@@ -24,6 +26,7 @@ type tokenResponse struct {
 	ExpiresIn    int    `json:"expires_in,omitempty"`
 	RefreshToken string `json:"refresh_token,omitempty"`
 	Scope        string `json:"scope,omitempty"`
+	IDToken      string `json:"id_token,omitempty"`
 }
 
 // TokenEndpointHandler トークンエンドポイントのハンドラ
@@ -136,6 +139,10 @@ func (h *Handler) tokenEndpointAuthorizationCodeHandler(c echo.Context) error {
 	if newToken.IsRefreshEnabled() {
 		res.RefreshToken = newToken.RefreshToken
 	}
+	if res.IDToken, err = h.issueIDToken(newToken, code.Nonce); err != nil {
+		h.L(c).Error(err.Error(), zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, oauth2ErrorResponse{ErrorType: errServerError})
+	}
 	return c.JSON(http.StatusOK, res)
 }
 
@@ -199,6 +206,17 @@ func (h *Handler) tokenEndpointPasswordHandler(c echo.Context) error {
 		return c.JSON(http.StatusUnauthorized, oauth2ErrorResponse{ErrorType: errInvalidGrant})
 	}
 
+	// 二要素認証を登録済み・必須のユーザーはパスワードのみでは認可しない
+	if required, err := h.isTwoFactorRequired(user); err != nil {
+		h.L(c).Error(err.Error(), zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, oauth2ErrorResponse{ErrorType: errServerError})
+	} else if required {
+		return c.JSON(http.StatusUnauthorized, oauth2ErrorResponse{
+			ErrorType:        errInvalidGrant,
+			ErrorDescription: "two-factor authentication is required. use the authorization code grant instead",
+		})
+	}
+
 	// 要求スコープ確認
 	reqScopes, err := h.splitAndValidateScope(req.Scope)
 	if err != nil {
@@ -229,6 +247,10 @@ func (h *Handler) tokenEndpointPasswordHandler(c echo.Context) error {
 	if newToken.IsRefreshEnabled() {
 		res.RefreshToken = newToken.RefreshToken
 	}
+	if res.IDToken, err = h.issueIDToken(newToken, ""); err != nil {
+		h.L(c).Error(err.Error(), zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, oauth2ErrorResponse{ErrorType: errServerError})
+	}
 	return c.JSON(http.StatusOK, res)
 }
 
@@ -389,5 +411,27 @@ func (h *Handler) tokenEndpointRefreshTokenHandler(c echo.Context) error {
 	if newToken.IsRefreshEnabled() {
 		res.RefreshToken = newToken.RefreshToken
 	}
+	if res.IDToken, err = h.issueIDToken(newToken, ""); err != nil {
+		h.L(c).Error(err.Error(), zap.Error(err))
+		return c.JSON(http.StatusInternalServerError, oauth2ErrorResponse{ErrorType: errServerError})
+	}
 	return c.JSON(http.StatusOK, res)
 }
+
+// isTwoFactorRequired ユーザーがログインに二要素認証を必要とするかどうか
+func (h *Handler) isTwoFactorRequired(user model.UserInfo) (bool, error) {
+	enrolled, err := middlewares.HasTwoFactorAuth(h.Repo, user.GetID())
+	if err != nil || enrolled {
+		return enrolled, err
+	}
+	roles, err := h.Repo.GetTwoFactorRequiredRoles()
+	if err != nil {
+		return false, err
+	}
+	for _, r := range roles {
+		if r == user.GetRole() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
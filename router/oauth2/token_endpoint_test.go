@@ -400,6 +400,24 @@ func TestHandlers_TokenEndpointPasswordHandler(t *testing.T) {
 		res.JSON().Object().Value("error").String().Equal(errInvalidGrant)
 	})
 
+	t.Run("Invalid Grant (Two-factor authentication enabled)", func(t *testing.T) {
+		t.Parallel()
+		user2 := env.CreateUser(t, rand)
+		require.NoError(t, env.Repository.SaveUserTOTP(user2.GetID(), "JBSWY3DPEHPK3PXP"))
+		require.NoError(t, env.Repository.EnableUserTOTP(user2.GetID()))
+
+		e := env.R(t)
+		res := e.POST("/oauth2/token").
+			WithFormField("grant_type", grantTypePassword).
+			WithFormField("username", user2.GetName()).
+			WithFormField("password", "testtesttesttest").
+			WithBasicAuth(client.ID, client.Secret).
+			Expect()
+
+		res.Status(http.StatusUnauthorized)
+		res.JSON().Object().Value("error").String().Equal(errInvalidGrant)
+	})
+
 	t.Run("Invalid Client (No client credentials)", func(t *testing.T) {
 		t.Parallel()
 		e := env.R(t)
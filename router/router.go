@@ -17,6 +17,7 @@ import (
 	"github.com/traPtitech/traQ/router/extension"
 	"github.com/traPtitech/traQ/router/middlewares"
 	"github.com/traPtitech/traQ/router/oauth2"
+	"github.com/traPtitech/traQ/router/scim"
 	"github.com/traPtitech/traQ/router/session"
 	"github.com/traPtitech/traQ/router/v1"
 	"github.com/traPtitech/traQ/router/v3"
@@ -30,6 +31,7 @@ type Router struct {
 	v1        *v1.Handlers
 	v3        *v3.Handlers
 	oauth2    *oauth2.Handler
+	scim      *scim.Handler
 }
 
 func Setup(hub *hub.Hub, db *gorm.DB, repo repository.Repository, ss *service.Services, logger *zap.Logger, config *Config) *echo.Echo {
@@ -42,6 +44,9 @@ func Setup(hub *hub.Hub, db *gorm.DB, repo repository.Repository, ss *service.Se
 	r.v3.Setup(api)
 	r.oauth2.Setup(api.Group("/oauth2"))
 	r.oauth2.Setup(api.Group("/v3/oauth2"))
+	if r.scim.Enabled() {
+		r.scim.Setup(api.Group("/scim/v2"))
+	}
 
 	// 外部authハンドラ
 	extAuth := api.Group("/auth")
@@ -73,6 +78,16 @@ func Setup(hub *hub.Hub, db *gorm.DB, repo repository.Repository, ss *service.Se
 		extAuth.GET("/slack", p.LoginHandler)
 		extAuth.GET("/slack/callback", p.CallbackHandler)
 	}
+	if config.ExternalAuth.SAML.Valid() {
+		p, err := auth.NewSAMLProvider(repo, ss.FileManager, logger.Named("ext_auth"), r.sessStore, config.ExternalAuth.SAML)
+		if err != nil {
+			panic(err)
+		}
+		extAuth.GET("/saml", p.LoginHandler)
+		extAuth.GET("/saml/metadata", p.MetadataHandler)
+		extAuth.POST("/saml/acs", p.ACSHandler)
+		extAuth.GET("/saml/callback", p.CallbackHandler)
+	}
 
 	return r.e
 }
@@ -96,7 +111,7 @@ func newEcho(logger *zap.Logger, config *Config, repo repository.Repository, cm
 	e.Use(extension.Wrap(repo, cm))
 	e.Use(middlewares.RequestCounter())
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		ExposeHeaders: []string{consts.HeaderVersion, consts.HeaderCacheFile, consts.HeaderFileMetaType, consts.HeaderMore, echo.HeaderXRequestID},
+		ExposeHeaders: []string{consts.HeaderVersion, consts.HeaderCacheFile, consts.HeaderFileMetaType, consts.HeaderMore, echo.HeaderXRequestID, consts.HeaderRateLimitLimit, consts.HeaderRateLimitRemaining, consts.HeaderRateLimitReset, echo.HeaderRetryAfter},
 		AllowHeaders:  []string{echo.HeaderContentType, echo.HeaderAuthorization, consts.HeaderSignature, consts.HeaderChannelID},
 		MaxAge:        3600,
 	}))
@@ -0,0 +1,401 @@
+package scim
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/service/file"
+	"github.com/traPtitech/traQ/utils/optional"
+	"github.com/traPtitech/traQ/utils/set"
+	"github.com/traPtitech/traQ/utils/validator"
+)
+
+// groupResource SCIM Groupリソース
+type groupResource struct {
+	Schemas     []string      `json:"schemas"`
+	ID          string        `json:"id"`
+	DisplayName string        `json:"displayName"`
+	Members     []memberValue `json:"members"`
+	Meta        meta          `json:"meta"`
+}
+
+// groupRequest POST, PUT /Groups リクエストボディ
+type groupRequest struct {
+	DisplayName string        `json:"displayName"`
+	Members     []memberValue `json:"members"`
+}
+
+// groupAttributes SCIMで操作可能なグループの属性
+type groupAttributes struct {
+	DisplayName string
+	Members     set.UUID
+}
+
+func (r *groupRequest) attributes() (groupAttributes, error) {
+	members, err := parseMembers(r.Members)
+	if err != nil {
+		return groupAttributes{}, err
+	}
+	return groupAttributes{DisplayName: r.DisplayName, Members: members}, nil
+}
+
+func (a groupAttributes) validate() error {
+	if err := vd.Validate(a.DisplayName, validator.UserGroupNameRuleRequired...); err != nil {
+		return badRequest(errInvalidValue, "displayName: "+err.Error())
+	}
+	return nil
+}
+
+// apply PATCH操作を適用します
+func (a *groupAttributes) apply(op patchOperation) error {
+	if len(op.Path) == 0 {
+		attrs, err := op.attributes()
+		if err != nil {
+			return err
+		}
+		for name, v := range attrs {
+			if err := a.apply(patchOperation{Op: op.Op, Path: name, Value: v}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	attr, filterValue := splitValueFilterPath(op.Path)
+	switch strings.ToLower(strings.TrimPrefix(attr, schemaGroup+":")) {
+	case "displayname":
+		if op.Op == patchOpRemove {
+			return badRequest(errMutability, "displayName cannot be removed")
+		}
+		name, err := decodeString(op.Value)
+		if err != nil {
+			return err
+		}
+		a.DisplayName = name
+	case "members":
+		if len(filterValue) > 0 {
+			if op.Op != patchOpRemove {
+				return badRequest(errInvalidPath, "value filter is only supported by remove operation")
+			}
+			a.Members.Remove(uuid.FromStringOrNil(filterValue))
+			return nil
+		}
+
+		var members set.UUID
+		if len(op.Value) > 0 {
+			values, err := decodeMembers(op.Value)
+			if err != nil {
+				return err
+			}
+			if members, err = parseMembers(values); err != nil {
+				return err
+			}
+		}
+		switch op.Op {
+		case patchOpAdd:
+			for id := range members {
+				a.Members.Add(id)
+			}
+		case patchOpReplace:
+			a.Members = members
+		case patchOpRemove:
+			if len(op.Value) == 0 {
+				a.Members = set.UUID{}
+			}
+			for id := range members {
+				a.Members.Remove(id)
+			}
+		}
+	default:
+		return badRequest(errInvalidPath, "unsupported path: "+op.Path)
+	}
+	return nil
+}
+
+// parseMembers メンバーの参照をUUIDの集合に変換します
+func parseMembers(members []memberValue) (set.UUID, error) {
+	ids := set.UUID{}
+	for _, m := range members {
+		id, err := uuid.FromString(m.Value)
+		if err != nil {
+			return nil, badRequest(errInvalidValue, "invalid member: "+m.Value)
+		}
+		ids.Add(id)
+	}
+	return ids, nil
+}
+
+// GetGroups GET /Groups
+func (h *Handler) GetGroups(c echo.Context) error {
+	p, err := parsePagination(c)
+	if err != nil {
+		return h.respondError(c, err)
+	}
+	f, err := parseFilter(c.QueryParam("filter"))
+	if err != nil {
+		return h.respondError(c, err)
+	}
+
+	groups, err := h.findGroups(f)
+	if err != nil {
+		return h.respondError(c, err)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].CreatedAt.Before(groups[j].CreatedAt)
+	})
+
+	start, end := p.bounds(len(groups))
+	resources := make([]*groupResource, 0, end-start)
+	for _, g := range groups[start:end] {
+		resources = append(resources, h.formatGroup(g))
+	}
+	return jsonResponse(c, http.StatusOK, listResponse{
+		Schemas:      []string{schemaListResponse},
+		TotalResults: len(groups),
+		StartIndex:   p.StartIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+// findGroups フィルターに一致するグループを取得します
+func (h *Handler) findGroups(f *filter) ([]*model.UserGroup, error) {
+	if f == nil {
+		return h.Repo.GetAllUserGroups()
+	}
+
+	var (
+		g   *model.UserGroup
+		err error
+	)
+	switch strings.ToLower(f.Attribute) {
+	case "displayname":
+		g, err = h.Repo.GetUserGroupByName(f.Value)
+	case "id":
+		g, err = h.Repo.GetUserGroup(uuid.FromStringOrNil(f.Value))
+	default:
+		return nil, badRequest(errInvalidFilter, "unsupported filter attribute: "+f.Attribute)
+	}
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return []*model.UserGroup{}, nil
+		}
+		return nil, err
+	}
+	return []*model.UserGroup{g}, nil
+}
+
+// CreateGroup POST /Groups
+func (h *Handler) CreateGroup(c echo.Context) error {
+	var req groupRequest
+	if err := bindJSON(c, &req); err != nil {
+		return h.respondError(c, err)
+	}
+	attrs, err := req.attributes()
+	if err != nil {
+		return h.respondError(c, err)
+	}
+	if err := attrs.validate(); err != nil {
+		return h.respondError(c, err)
+	}
+	if err := h.checkMembersExist(attrs.Members); err != nil {
+		return h.respondError(c, err)
+	}
+
+	adminID, err := h.getGroupAdminID()
+	if err != nil {
+		return h.respondError(c, err)
+	}
+	iconFileID, err := file.GenerateIconFile(h.FileManager, attrs.DisplayName)
+	if err != nil {
+		return h.respondError(c, err)
+	}
+	g, err := h.Repo.CreateUserGroupWithMembers(attrs.DisplayName, "", "", adminID, iconFileID, attrs.Members.Array())
+	if err != nil {
+		if err == repository.ErrAlreadyExists {
+			return h.respondError(c, conflict("displayName is already used"))
+		}
+		return h.respondError(c, err)
+	}
+
+	return h.respondGroup(c, http.StatusCreated, g.ID)
+}
+
+// GetGroup GET /Groups/:id
+func (h *Handler) GetGroup(c echo.Context) error {
+	return h.respondGroup(c, http.StatusOK, uuid.FromStringOrNil(c.Param("id")))
+}
+
+// ReplaceGroup PUT /Groups/:id
+func (h *Handler) ReplaceGroup(c echo.Context) error {
+	g, current, err := h.getGroupAttributes(c.Param("id"))
+	if err != nil {
+		return h.respondError(c, err)
+	}
+
+	var req groupRequest
+	if err := bindJSON(c, &req); err != nil {
+		return h.respondError(c, err)
+	}
+	attrs, err := req.attributes()
+	if err != nil {
+		return h.respondError(c, err)
+	}
+
+	if err := h.updateGroup(g, current, attrs); err != nil {
+		return h.respondError(c, err)
+	}
+	return h.respondGroup(c, http.StatusOK, g.ID)
+}
+
+// PatchGroup PATCH /Groups/:id
+func (h *Handler) PatchGroup(c echo.Context) error {
+	g, current, err := h.getGroupAttributes(c.Param("id"))
+	if err != nil {
+		return h.respondError(c, err)
+	}
+
+	var req patchRequest
+	if err := bindJSON(c, &req); err != nil {
+		return h.respondError(c, err)
+	}
+	if err := req.validate(); err != nil {
+		return h.respondError(c, err)
+	}
+
+	attrs := groupAttributes{DisplayName: current.DisplayName, Members: current.Members.Clone()}
+	for _, op := range req.Operations {
+		if err := attrs.apply(op); err != nil {
+			return h.respondError(c, err)
+		}
+	}
+
+	if err := h.updateGroup(g, current, attrs); err != nil {
+		return h.respondError(c, err)
+	}
+	return h.respondGroup(c, http.StatusOK, g.ID)
+}
+
+// DeleteGroup DELETE /Groups/:id
+func (h *Handler) DeleteGroup(c echo.Context) error {
+	if err := h.Repo.DeleteUserGroup(uuid.FromStringOrNil(c.Param("id"))); err != nil {
+		if err == repository.ErrNotFound || err == repository.ErrNilID {
+			return h.respondError(c, notFound("group not found"))
+		}
+		return h.respondError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// getGroupAttributes 指定したIDのグループとその現在の属性を取得します
+func (h *Handler) getGroupAttributes(id string) (*model.UserGroup, groupAttributes, error) {
+	g, err := h.Repo.GetUserGroup(uuid.FromStringOrNil(id))
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, groupAttributes{}, notFound("group not found")
+		}
+		return nil, groupAttributes{}, err
+	}
+
+	members := set.UUID{}
+	for _, m := range g.Members {
+		members.Add(m.UserID)
+	}
+	return g, groupAttributes{DisplayName: g.Name, Members: members}, nil
+}
+
+// updateGroup グループの属性をcurrentからnextに更新します
+func (h *Handler) updateGroup(g *model.UserGroup, current, next groupAttributes) error {
+	if err := next.validate(); err != nil {
+		return err
+	}
+
+	added := make([]uuid.UUID, 0)
+	for id := range next.Members {
+		if !current.Members.Contains(id) {
+			added = append(added, id)
+		}
+	}
+	if err := h.checkMembersExist(set.UUIDSetFromArray(added)); err != nil {
+		return err
+	}
+
+	if next.DisplayName != current.DisplayName {
+		if err := h.Repo.UpdateUserGroup(g.ID, repository.UpdateUserGroupArgs{Name: optional.StringFrom(next.DisplayName)}); err != nil {
+			if err == repository.ErrAlreadyExists {
+				return conflict("displayName is already used")
+			}
+			return err
+		}
+	}
+	for _, id := range added {
+		if err := h.Repo.AddUserToGroup(id, g.ID, ""); err != nil {
+			return err
+		}
+	}
+	for id := range current.Members {
+		if !next.Members.Contains(id) {
+			if err := h.Repo.RemoveUserFromGroup(id, g.ID); err != nil && err != repository.ErrNotFound {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkMembersExist 指定したユーザーが全て存在することを確認します
+func (h *Handler) checkMembersExist(ids set.UUID) error {
+	for id := range ids {
+		ok, err := h.Repo.UserExists(id)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return badRequest(errInvalidValue, "unknown member: "+id.String())
+		}
+	}
+	return nil
+}
+
+// respondGroup 指定したグループのリソースをレスポンスとして返します
+func (h *Handler) respondGroup(c echo.Context, status int, id uuid.UUID) error {
+	g, err := h.Repo.GetUserGroup(id)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return h.respondError(c, notFound("group not found"))
+		}
+		return h.respondError(c, err)
+	}
+
+	res := h.formatGroup(g)
+	if status == http.StatusCreated {
+		c.Response().Header().Set(echo.HeaderLocation, res.Meta.Location)
+	}
+	return jsonResponse(c, status, res)
+}
+
+func (h *Handler) formatGroup(g *model.UserGroup) *groupResource {
+	members := make([]memberValue, len(g.Members))
+	for i, m := range g.Members {
+		members[i] = memberValue{Value: m.UserID.String(), Ref: h.location("Users", m.UserID.String())}
+	}
+	return &groupResource{
+		Schemas:     []string{schemaGroup},
+		ID:          g.ID.String(),
+		DisplayName: g.Name,
+		Members:     members,
+		Meta: meta{
+			ResourceType: "Group",
+			Created:      g.CreatedAt,
+			LastModified: g.UpdatedAt,
+			Location:     h.location("Groups", g.ID.String()),
+		},
+	}
+}
@@ -0,0 +1,124 @@
+package scim
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	patchOpAdd     = "add"
+	patchOpReplace = "replace"
+	patchOpRemove  = "remove"
+)
+
+// patchRequest PATCHリクエストボディ
+type patchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []patchOperation `json:"Operations"`
+}
+
+// patchOperation PATCH操作
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+func (r *patchRequest) validate() error {
+	if len(r.Schemas) > 0 && r.Schemas[0] != schemaPatchOp {
+		return badRequest(errInvalidSyntax, "unknown schema: "+r.Schemas[0])
+	}
+	if len(r.Operations) == 0 {
+		return badRequest(errInvalidSyntax, "no operations")
+	}
+	for i, op := range r.Operations {
+		op.Op = strings.ToLower(op.Op)
+		switch op.Op {
+		case patchOpAdd, patchOpReplace:
+			if len(op.Value) == 0 {
+				return badRequest(errInvalidSyntax, "missing value")
+			}
+		case patchOpRemove:
+			if len(op.Path) == 0 {
+				return badRequest(errNoTarget, "missing path")
+			}
+		default:
+			return badRequest(errInvalidSyntax, "unknown op: "+op.Op)
+		}
+		r.Operations[i] = op
+	}
+	return nil
+}
+
+// attributes パスを指定しない操作の値を属性名とその値に分解します
+func (op patchOperation) attributes() (map[string]json.RawMessage, error) {
+	var attrs map[string]json.RawMessage
+	if err := json.Unmarshal(op.Value, &attrs); err != nil {
+		return nil, badRequest(errInvalidValue, "value must be an object when path is omitted")
+	}
+	return attrs, nil
+}
+
+// valueFilterPathRegex `attribute[value eq "xxx"]` 形式のパス
+var valueFilterPathRegex = regexp.MustCompile(`(?i)^\s*([a-zA-Z]+)\s*\[\s*value\s+eq\s+"((?:[^"\\]|\\.)*)"\s*]\s*$`)
+
+// splitValueFilterPath `members[value eq "xxx"]` 形式のパスを属性名と値に分解します
+//
+// 該当しない形式の場合、パスそのものと空文字列を返します。
+func splitValueFilterPath(path string) (attr string, value string) {
+	m := valueFilterPathRegex.FindStringSubmatch(path)
+	if m == nil {
+		return path, ""
+	}
+	return m[1], m[2]
+}
+
+// decodeString 値を文字列としてデコードします
+func decodeString(v json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(v, &s); err != nil {
+		return "", badRequest(errInvalidValue, "value must be a string")
+	}
+	return s, nil
+}
+
+// decodeBool 値を真偽値としてデコードします
+//
+// 一部のIdPは真偽値を文字列で送信するため、"True"や"false"などの文字列も受け付けます。
+func decodeBool(v json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(v, &b); err == nil {
+		return b, nil
+	}
+	s, err := decodeString(v)
+	if err != nil {
+		return false, badRequest(errInvalidValue, "value must be a boolean")
+	}
+	b, err = strconv.ParseBool(s)
+	if err != nil {
+		return false, badRequest(errInvalidValue, "value must be a boolean")
+	}
+	return b, nil
+}
+
+// memberValue グループメンバーの参照
+type memberValue struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// decodeMembers 値をグループメンバーの配列としてデコードします
+func decodeMembers(v json.RawMessage) ([]memberValue, error) {
+	var members []memberValue
+	if err := json.Unmarshal(v, &members); err == nil {
+		return members, nil
+	}
+	var member memberValue
+	if err := json.Unmarshal(v, &member); err != nil {
+		return nil, badRequest(errInvalidValue, "value must be an array of members")
+	}
+	return []memberValue{member}, nil
+}
@@ -0,0 +1,377 @@
+package scim
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/router/extension"
+	"github.com/traPtitech/traQ/service/file"
+)
+
+const (
+	schemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	schemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	schemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	schemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	schemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	schemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	schemaResourceType          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+
+	errInvalidFilter = "invalidFilter"
+	errInvalidSyntax = "invalidSyntax"
+	errInvalidPath   = "invalidPath"
+	errInvalidValue  = "invalidValue"
+	errMutability    = "mutability"
+	errNoTarget      = "noTarget"
+	errUniqueness    = "uniqueness"
+
+	// externalProviderName SCIMのexternalIdを外部アカウントとして保存する際のプロバイダ名
+	externalProviderName = "scim"
+
+	mimeSCIM     = "application/scim+json"
+	authScheme   = "Bearer"
+	defaultCount = 100
+	maxCount     = 1000
+)
+
+// Handler SCIM 2.0 プロビジョニングAPIハンドラ
+type Handler struct {
+	Repo        repository.Repository
+	FileManager file.Manager
+	Logger      *zap.Logger
+	Config
+
+	groupAdminID uuid.UUID  `wire:"-"`
+	groupAdminMu sync.Mutex `wire:"-"`
+}
+
+// Config SCIM設定
+type Config struct {
+	// Token プロビジョニング用トークン
+	Token string
+	// GroupAdmin SCIMで作成したグループの管理者にするユーザーの名前
+	GroupAdmin string
+	// Origin サーバーオリジン
+	Origin string
+}
+
+// Enabled SCIM APIが有効かどうか
+func (c Config) Enabled() bool {
+	return len(c.Token) > 0
+}
+
+// GetGroupAdmin 設定されたグループ管理者のユーザーを取得します
+//
+// ユーザーが存在しない場合やBOTの場合はエラーを返します。サーバー起動時の設定の検証に用います。
+func GetGroupAdmin(repo repository.UserRepository, name string) (model.UserInfo, error) {
+	u, err := repo.GetUserByName(name, false)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, fmt.Errorf("scim group admin user '%s' was not found", name)
+		}
+		return nil, err
+	}
+	if u.IsBot() {
+		return nil, fmt.Errorf("scim group admin user '%s' must not be a bot", name)
+	}
+	return u, nil
+}
+
+// getGroupAdminID グループ管理者のUUIDを取得します
+//
+// 設定はサーバー起動時に検証済みのため、一度取得したUUIDを使い回します。
+func (h *Handler) getGroupAdminID() (uuid.UUID, error) {
+	h.groupAdminMu.Lock()
+	defer h.groupAdminMu.Unlock()
+
+	if h.groupAdminID == uuid.Nil {
+		u, err := GetGroupAdmin(h.Repo, h.GroupAdmin)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		h.groupAdminID = u.GetID()
+	}
+	return h.groupAdminID, nil
+}
+
+func (h *Handler) Setup(e *echo.Group) {
+	e.Use(h.authenticate)
+	e.GET("/ServiceProviderConfig", h.GetServiceProviderConfig)
+	e.GET("/ResourceTypes", h.GetResourceTypes)
+	e.GET("/Users", h.GetUsers)
+	e.POST("/Users", h.CreateUser)
+	e.GET("/Users/:id", h.GetUser)
+	e.PUT("/Users/:id", h.ReplaceUser)
+	e.PATCH("/Users/:id", h.PatchUser)
+	e.DELETE("/Users/:id", h.DeleteUser)
+	e.GET("/Groups", h.GetGroups)
+	e.POST("/Groups", h.CreateGroup)
+	e.GET("/Groups/:id", h.GetGroup)
+	e.PUT("/Groups/:id", h.ReplaceGroup)
+	e.PATCH("/Groups/:id", h.PatchGroup)
+	e.DELETE("/Groups/:id", h.DeleteGroup)
+}
+
+// authenticate プロビジョニング用トークンによる認証を行うミドルウェア
+func (h *Handler) authenticate(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ah := c.Request().Header.Get(echo.HeaderAuthorization)
+		if len(ah) <= len(authScheme)+1 || !strings.EqualFold(ah[:len(authScheme)], authScheme) {
+			return errorResponse(c, http.StatusUnauthorized, "", "missing provisioning token")
+		}
+		if subtle.ConstantTimeCompare([]byte(ah[len(authScheme)+1:]), []byte(h.Token)) != 1 {
+			return errorResponse(c, http.StatusUnauthorized, "", "invalid provisioning token")
+		}
+		return next(c)
+	}
+}
+
+// GetServiceProviderConfig GET /ServiceProviderConfig
+func (h *Handler) GetServiceProviderConfig(c echo.Context) error {
+	return jsonResponse(c, http.StatusOK, echo.Map{
+		"schemas":        []string{schemaServiceProviderConfig},
+		"patch":          echo.Map{"supported": true},
+		"bulk":           echo.Map{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         echo.Map{"supported": true, "maxResults": maxCount},
+		"changePassword": echo.Map{"supported": true},
+		"sort":           echo.Map{"supported": false},
+		"etag":           echo.Map{"supported": false},
+		"authenticationSchemes": []echo.Map{{
+			"type":        "oauthbearertoken",
+			"name":        "Provisioning Token",
+			"description": "Authentication with the provisioning token configured on the server",
+		}},
+		"meta": echo.Map{"resourceType": "ServiceProviderConfig", "location": h.location("ServiceProviderConfig")},
+	})
+}
+
+// GetResourceTypes GET /ResourceTypes
+func (h *Handler) GetResourceTypes(c echo.Context) error {
+	resources := []echo.Map{
+		{
+			"schemas":  []string{schemaResourceType},
+			"id":       "User",
+			"name":     "User",
+			"endpoint": "/Users",
+			"schema":   schemaUser,
+			"meta":     echo.Map{"resourceType": "ResourceType", "location": h.location("ResourceTypes", "User")},
+		},
+		{
+			"schemas":  []string{schemaResourceType},
+			"id":       "Group",
+			"name":     "Group",
+			"endpoint": "/Groups",
+			"schema":   schemaGroup,
+			"meta":     echo.Map{"resourceType": "ResourceType", "location": h.location("ResourceTypes", "Group")},
+		},
+	}
+	return jsonResponse(c, http.StatusOK, listResponse{
+		Schemas:      []string{schemaListResponse},
+		TotalResults: len(resources),
+		StartIndex:   1,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+// location 指定したリソースのURLを返します
+func (h *Handler) location(elem ...string) string {
+	return h.Origin + "/api/scim/v2/" + strings.Join(elem, "/")
+}
+
+// scimError SCIMのエラーレスポンスとして返すエラー
+type scimError struct {
+	Status   int
+	SCIMType string
+	Detail   string
+}
+
+func (e *scimError) Error() string {
+	return e.Detail
+}
+
+func badRequest(scimType, detail string) error {
+	return &scimError{Status: http.StatusBadRequest, SCIMType: scimType, Detail: detail}
+}
+
+func notFound(detail string) error {
+	return &scimError{Status: http.StatusNotFound, Detail: detail}
+}
+
+func conflict(detail string) error {
+	return &scimError{Status: http.StatusConflict, SCIMType: errUniqueness, Detail: detail}
+}
+
+// respondError エラーレスポンスを返します
+//
+// scimError以外のエラーは内部エラーとしてログに出力します。
+func (h *Handler) respondError(c echo.Context, err error) error {
+	var se *scimError
+	if errors.As(err, &se) {
+		return errorResponse(c, se.Status, se.SCIMType, se.Detail)
+	}
+	h.Logger.Error("scim internal error", zap.Error(err), zap.String("requestId", extension.GetRequestID(c)))
+	return errorResponse(c, http.StatusInternalServerError, "", http.StatusText(http.StatusInternalServerError))
+}
+
+// recordAuditLog SCIMによる操作の監査ログを記録します
+//
+// 操作者はmodel.AuditActorSCIMになります。
+// 操作自体は完了しているため、記録に失敗した場合はエラーログを出力するのみです。
+func (h *Handler) recordAuditLog(c echo.Context, action model.AuditAction, targetType model.AuditTargetType, targetID string, before, after model.JSON) {
+	before, after = model.AuditDiff(before, after)
+	userAgent := c.Request().UserAgent()
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	log := &model.AuditLog{
+		ActorID:    model.AuditActorSCIM,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Before:     before,
+		After:      after,
+		IP:         c.RealIP(),
+		UserAgent:  userAgent,
+	}
+	if err := h.Repo.CreateAuditLog(log); err != nil {
+		h.Logger.Error("failed to record audit log", zap.Error(err), zap.String("action", string(action)), zap.String("targetType", string(targetType)), zap.String("targetId", targetID))
+	}
+}
+
+type meta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location"`
+}
+
+type listResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int         `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    interface{} `json:"Resources"`
+}
+
+type errorBody struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	SCIMType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+}
+
+func jsonResponse(c echo.Context, status int, body interface{}) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return c.Blob(status, mimeSCIM, b)
+}
+
+func errorResponse(c echo.Context, status int, scimType, detail string) error {
+	return jsonResponse(c, status, errorBody{
+		Schemas:  []string{schemaError},
+		Status:   strconv.Itoa(status),
+		SCIMType: scimType,
+		Detail:   detail,
+	})
+}
+
+// bindJSON リクエストボディをデコードします
+//
+// SCIMクライアントはContent-Typeにapplication/scim+jsonを指定するため、echoのBinderは使用しません。
+func bindJSON(c echo.Context, v interface{}) error {
+	if err := json.NewDecoder(c.Request().Body).Decode(v); err != nil {
+		return badRequest(errInvalidSyntax, "malformed request body")
+	}
+	return nil
+}
+
+// pagination リストリクエストのページネーション
+type pagination struct {
+	StartIndex int
+	Count      int
+}
+
+func parsePagination(c echo.Context) (pagination, error) {
+	p := pagination{StartIndex: 1, Count: defaultCount}
+	if s := c.QueryParam("startIndex"); len(s) > 0 {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return p, badRequest(errInvalidValue, "invalid startIndex")
+		}
+		if v > 1 {
+			p.StartIndex = v
+		}
+	}
+	if s := c.QueryParam("count"); len(s) > 0 {
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return p, badRequest(errInvalidValue, "invalid count")
+		}
+		switch {
+		case v < 0:
+			p.Count = 0
+		case v > maxCount:
+			p.Count = maxCount
+		default:
+			p.Count = v
+		}
+	}
+	return p, nil
+}
+
+// bounds 全件数totalに対してページに含まれる範囲[start, end)を返します
+func (p pagination) bounds(total int) (start, end int) {
+	start = p.StartIndex - 1
+	if start > total {
+		start = total
+	}
+	end = start + p.Count
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
+// filter `attribute eq "value"` 形式のフィルター
+type filter struct {
+	Attribute string
+	Value     string
+}
+
+var filterRegex = regexp.MustCompile(`(?i)^\s*([a-zA-Z][a-zA-Z0-9.]*)\s+eq\s+"((?:[^"\\]|\\.)*)"\s*$`)
+
+// parseFilter filterクエリをパースします
+//
+// 空文字列の場合はnilを返します。`eq`演算子による単一の比較のみをサポートします。
+func parseFilter(s string) (*filter, error) {
+	if len(strings.TrimSpace(s)) == 0 {
+		return nil, nil
+	}
+	m := filterRegex.FindStringSubmatch(s)
+	if m == nil {
+		return nil, badRequest(errInvalidFilter, fmt.Sprintf("unsupported filter: %s", s))
+	}
+	var value string
+	if err := json.Unmarshal([]byte(`"`+m[2]+`"`), &value); err != nil {
+		return nil, badRequest(errInvalidFilter, fmt.Sprintf("invalid filter value: %s", m[2]))
+	}
+	return &filter{Attribute: m[1], Value: value}, nil
+}
@@ -0,0 +1,237 @@
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/utils/set"
+)
+
+type testRepository struct {
+	repository.Repository
+	updated   []repository.UpdateUserArgs
+	auditLogs []*model.AuditLog
+}
+
+func (r *testRepository) UpdateUser(_ uuid.UUID, args repository.UpdateUserArgs) error {
+	r.updated = append(r.updated, args)
+	return nil
+}
+
+func (r *testRepository) CreateAuditLog(log *model.AuditLog) error {
+	r.auditLogs = append(r.auditLogs, log)
+	return nil
+}
+
+func TestParseFilter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		filter  string
+		want    *filter
+		wantErr bool
+	}{
+		{"empty", "", nil, false},
+		{"eq", `userName eq "takashi"`, &filter{Attribute: "userName", Value: "takashi"}, false},
+		{"case insensitive operator", `displayName EQ "group"`, &filter{Attribute: "displayName", Value: "group"}, false},
+		{"escaped", `externalId eq "a\"b"`, &filter{Attribute: "externalId", Value: `a"b`}, false},
+		{"unsupported operator", `userName sw "ta"`, nil, true},
+		{"logical expression", `userName eq "a" and active eq "true"`, nil, true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			f, err := parseFilter(tt.filter)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, f)
+			}
+		})
+	}
+}
+
+func TestPagination_Bounds(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		p         pagination
+		total     int
+		wantStart int
+		wantEnd   int
+	}{
+		{"first page", pagination{StartIndex: 1, Count: 10}, 25, 0, 10},
+		{"last page", pagination{StartIndex: 21, Count: 10}, 25, 20, 25},
+		{"out of range", pagination{StartIndex: 30, Count: 10}, 25, 25, 25},
+		{"zero count", pagination{StartIndex: 1, Count: 0}, 25, 0, 0},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			start, end := tt.p.bounds(tt.total)
+			assert.Equal(t, tt.wantStart, start)
+			assert.Equal(t, tt.wantEnd, end)
+		})
+	}
+}
+
+func TestDecodeBool(t *testing.T) {
+	t.Parallel()
+
+	for raw, want := range map[string]bool{`true`: true, `false`: false, `"True"`: true, `"false"`: false} {
+		b, err := decodeBool(json.RawMessage(raw))
+		if assert.NoError(t, err, raw) {
+			assert.Equal(t, want, b, raw)
+		}
+	}
+	_, err := decodeBool(json.RawMessage(`"yes please"`))
+	assert.Error(t, err)
+}
+
+func TestUserAttributes_Apply(t *testing.T) {
+	t.Parallel()
+
+	t.Run("replace with path", func(t *testing.T) {
+		t.Parallel()
+		a := userAttributes{UserName: "user", Active: true}
+		require.NoError(t, a.apply(patchOperation{Op: patchOpReplace, Path: "active", Value: json.RawMessage(`false`)}))
+		assert.False(t, a.Active)
+	})
+
+	t.Run("replace without path", func(t *testing.T) {
+		t.Parallel()
+		a := userAttributes{UserName: "user", Active: true}
+		require.NoError(t, a.apply(patchOperation{Op: patchOpReplace, Value: json.RawMessage(`{"active":"False","displayName":"User","name.givenName":"ignored"}`)}))
+		assert.False(t, a.Active)
+		assert.Equal(t, "User", a.DisplayName)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		t.Parallel()
+		a := userAttributes{UserName: "user", ExternalID: "ext"}
+		require.NoError(t, a.apply(patchOperation{Op: patchOpRemove, Path: "externalId"}))
+		assert.Empty(t, a.ExternalID)
+		assert.Error(t, a.apply(patchOperation{Op: patchOpRemove, Path: "userName"}))
+	})
+}
+
+func TestGroupAttributes_Apply(t *testing.T) {
+	t.Parallel()
+
+	u1 := uuid.Must(uuid.NewV4())
+	u2 := uuid.Must(uuid.NewV4())
+	u3 := uuid.Must(uuid.NewV4())
+	members := func(ids ...uuid.UUID) json.RawMessage {
+		values := make([]memberValue, len(ids))
+		for i, id := range ids {
+			values[i] = memberValue{Value: id.String()}
+		}
+		b, _ := json.Marshal(values)
+		return b
+	}
+
+	t.Run("add members", func(t *testing.T) {
+		t.Parallel()
+		a := groupAttributes{DisplayName: "g", Members: set.UUIDSetFromArray([]uuid.UUID{u1})}
+		require.NoError(t, a.apply(patchOperation{Op: patchOpAdd, Path: "members", Value: members(u2, u3)}))
+		assert.ElementsMatch(t, []uuid.UUID{u1, u2, u3}, a.Members.Array())
+	})
+
+	t.Run("replace members", func(t *testing.T) {
+		t.Parallel()
+		a := groupAttributes{DisplayName: "g", Members: set.UUIDSetFromArray([]uuid.UUID{u1, u2})}
+		require.NoError(t, a.apply(patchOperation{Op: patchOpReplace, Path: "members", Value: members(u3)}))
+		assert.ElementsMatch(t, []uuid.UUID{u3}, a.Members.Array())
+	})
+
+	t.Run("remove member by value filter", func(t *testing.T) {
+		t.Parallel()
+		a := groupAttributes{DisplayName: "g", Members: set.UUIDSetFromArray([]uuid.UUID{u1, u2})}
+		require.NoError(t, a.apply(patchOperation{Op: patchOpRemove, Path: `members[value eq "` + u1.String() + `"]`}))
+		assert.ElementsMatch(t, []uuid.UUID{u2}, a.Members.Array())
+	})
+
+	t.Run("remove members by value", func(t *testing.T) {
+		t.Parallel()
+		a := groupAttributes{DisplayName: "g", Members: set.UUIDSetFromArray([]uuid.UUID{u1, u2})}
+		require.NoError(t, a.apply(patchOperation{Op: patchOpRemove, Path: "members", Value: members(u2)}))
+		assert.ElementsMatch(t, []uuid.UUID{u1}, a.Members.Array())
+	})
+
+	t.Run("replace display name without path", func(t *testing.T) {
+		t.Parallel()
+		a := groupAttributes{DisplayName: "g", Members: set.UUID{}}
+		require.NoError(t, a.apply(patchOperation{Op: patchOpReplace, Value: json.RawMessage(`{"displayName":"renamed"}`)}))
+		assert.Equal(t, "renamed", a.DisplayName)
+	})
+
+	t.Run("invalid member", func(t *testing.T) {
+		t.Parallel()
+		a := groupAttributes{DisplayName: "g", Members: set.UUID{}}
+		assert.Error(t, a.apply(patchOperation{Op: patchOpAdd, Path: "members", Value: json.RawMessage(`[{"value":"invalid"}]`)}))
+	})
+}
+
+func TestHandler_updateUser_AuditLog(t *testing.T) {
+	t.Parallel()
+
+	user := &model.User{ID: uuid.Must(uuid.NewV4()), Name: "alice", DisplayName: "Alice", Status: model.UserAccountStatusActive}
+	current := userAttributes{UserName: "alice", DisplayName: "Alice", Active: true}
+	update := func(t *testing.T, next userAttributes) *testRepository {
+		t.Helper()
+		repo := &testRepository{}
+		h := &Handler{Repo: repo, Logger: zap.NewNop()}
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodPatch, "/scim/v2/Users/"+user.ID.String(), nil), httptest.NewRecorder())
+		require.NoError(t, h.updateUser(c, user, current, next))
+		return repo
+	}
+
+	t.Run("deactivate", func(t *testing.T) {
+		t.Parallel()
+		next := current
+		next.Active = false
+		repo := update(t, next)
+		require.Len(t, repo.updated, 1)
+		if assert.Len(t, repo.auditLogs, 1) {
+			l := repo.auditLogs[0]
+			assert.Equal(t, model.AuditActorSCIM, l.ActorID)
+			assert.Equal(t, model.AuditActionUserEdit, l.Action)
+			assert.Equal(t, user.ID.String(), l.TargetID)
+			assert.EqualValues(t, model.JSON{"state": 1}, l.Before)
+			assert.EqualValues(t, model.JSON{"state": 0}, l.After)
+		}
+	})
+
+	t.Run("change password", func(t *testing.T) {
+		t.Parallel()
+		next := current
+		next.Password = "newpassword1234"
+		repo := update(t, next)
+		require.Len(t, repo.updated, 1)
+		if assert.Len(t, repo.auditLogs, 1) {
+			assert.Equal(t, model.AuditActorSCIM, repo.auditLogs[0].ActorID)
+			assert.Equal(t, model.AuditActionUserPasswordChange, repo.auditLogs[0].Action)
+		}
+	})
+
+	t.Run("no change", func(t *testing.T) {
+		t.Parallel()
+		repo := update(t, current)
+		assert.Empty(t, repo.updated)
+		assert.Empty(t, repo.auditLogs)
+	})
+}
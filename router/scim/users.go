@@ -0,0 +1,525 @@
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/service/file"
+	"github.com/traPtitech/traQ/service/rbac/role"
+	"github.com/traPtitech/traQ/utils/optional"
+	"github.com/traPtitech/traQ/utils/validator"
+)
+
+// userResource SCIM Userリソース
+type userResource struct {
+	Schemas     []string      `json:"schemas"`
+	ID          string        `json:"id"`
+	ExternalID  string        `json:"externalId,omitempty"`
+	UserName    string        `json:"userName"`
+	DisplayName string        `json:"displayName"`
+	Active      bool          `json:"active"`
+	Groups      []memberValue `json:"groups"`
+	Meta        meta          `json:"meta"`
+}
+
+// userRequest POST, PUT /Users リクエストボディ
+type userRequest struct {
+	ExternalID  string          `json:"externalId"`
+	UserName    string          `json:"userName"`
+	DisplayName string          `json:"displayName"`
+	Active      json.RawMessage `json:"active"`
+	Password    string          `json:"password"`
+}
+
+// userAttributes SCIMで操作可能なユーザーの属性
+type userAttributes struct {
+	ExternalID  string
+	UserName    string
+	DisplayName string
+	Active      bool
+	Password    string
+}
+
+func (r *userRequest) attributes() (userAttributes, error) {
+	attrs := userAttributes{
+		ExternalID:  r.ExternalID,
+		UserName:    r.UserName,
+		DisplayName: r.DisplayName,
+		Active:      true,
+		Password:    r.Password,
+	}
+	if len(r.Active) > 0 {
+		active, err := decodeBool(r.Active)
+		if err != nil {
+			return attrs, err
+		}
+		attrs.Active = active
+	}
+	return attrs, nil
+}
+
+func (a userAttributes) validate() error {
+	if err := vd.Validate(a.UserName, validator.UserNameRuleRequired...); err != nil {
+		return badRequest(errInvalidValue, "userName: "+err.Error())
+	}
+	if err := vd.Validate(a.DisplayName, vd.RuneLength(0, 64)); err != nil {
+		return badRequest(errInvalidValue, "displayName: "+err.Error())
+	}
+	if err := vd.Validate(a.Password, validator.PasswordRule...); err != nil {
+		return badRequest(errInvalidValue, "password: "+err.Error())
+	}
+	return nil
+}
+
+// set 属性名nameの値をvに設定します
+//
+// traQで扱わない属性は無視します。
+func (a *userAttributes) set(name string, v json.RawMessage) (err error) {
+	switch strings.ToLower(strings.TrimPrefix(name, schemaUser+":")) {
+	case "externalid":
+		a.ExternalID, err = decodeString(v)
+	case "username":
+		a.UserName, err = decodeString(v)
+	case "displayname":
+		a.DisplayName, err = decodeString(v)
+	case "active":
+		a.Active, err = decodeBool(v)
+	case "password":
+		a.Password, err = decodeString(v)
+	}
+	return err
+}
+
+// remove 属性名nameの値を削除します
+func (a *userAttributes) remove(name string) error {
+	switch strings.ToLower(strings.TrimPrefix(name, schemaUser+":")) {
+	case "externalid":
+		a.ExternalID = ""
+	case "displayname":
+		a.DisplayName = ""
+	case "username", "active":
+		return badRequest(errMutability, name+" cannot be removed")
+	}
+	return nil
+}
+
+// apply PATCH操作を適用します
+func (a *userAttributes) apply(op patchOperation) error {
+	if op.Op == patchOpRemove {
+		return a.remove(op.Path)
+	}
+	if len(op.Path) > 0 {
+		return a.set(op.Path, op.Value)
+	}
+	attrs, err := op.attributes()
+	if err != nil {
+		return err
+	}
+	for name, v := range attrs {
+		if err := a.set(name, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetUsers GET /Users
+func (h *Handler) GetUsers(c echo.Context) error {
+	p, err := parsePagination(c)
+	if err != nil {
+		return h.respondError(c, err)
+	}
+	f, err := parseFilter(c.QueryParam("filter"))
+	if err != nil {
+		return h.respondError(c, err)
+	}
+
+	users, err := h.findUsers(f)
+	if err != nil {
+		return h.respondError(c, err)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].GetCreatedAt().Before(users[j].GetCreatedAt())
+	})
+
+	start, end := p.bounds(len(users))
+	resources, err := h.formatUsers(users[start:end])
+	if err != nil {
+		return h.respondError(c, err)
+	}
+	return jsonResponse(c, http.StatusOK, listResponse{
+		Schemas:      []string{schemaListResponse},
+		TotalResults: len(users),
+		StartIndex:   p.StartIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+// findUsers フィルターに一致するBOTでないユーザーを取得します
+func (h *Handler) findUsers(f *filter) ([]model.UserInfo, error) {
+	if f == nil {
+		return h.Repo.GetUsers(repository.UsersQuery{}.NotBot())
+	}
+
+	var (
+		u   model.UserInfo
+		err error
+	)
+	switch strings.ToLower(f.Attribute) {
+	case "username":
+		u, err = h.Repo.GetUserByName(f.Value, false)
+	case "externalid":
+		u, err = h.Repo.GetUserByExternalID(externalProviderName, f.Value, false)
+	case "id":
+		id, uerr := uuid.FromString(f.Value)
+		if uerr != nil {
+			return []model.UserInfo{}, nil
+		}
+		u, err = h.Repo.GetUser(id, false)
+	default:
+		return nil, badRequest(errInvalidFilter, "unsupported filter attribute: "+f.Attribute)
+	}
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return []model.UserInfo{}, nil
+		}
+		return nil, err
+	}
+	if u.IsBot() {
+		return []model.UserInfo{}, nil
+	}
+	return []model.UserInfo{u}, nil
+}
+
+// CreateUser POST /Users
+func (h *Handler) CreateUser(c echo.Context) error {
+	var req userRequest
+	if err := bindJSON(c, &req); err != nil {
+		return h.respondError(c, err)
+	}
+	attrs, err := req.attributes()
+	if err != nil {
+		return h.respondError(c, err)
+	}
+	if err := attrs.validate(); err != nil {
+		return h.respondError(c, err)
+	}
+
+	args := repository.CreateUserArgs{
+		Name:        attrs.UserName,
+		DisplayName: attrs.DisplayName,
+		Role:        role.User,
+		Password:    attrs.Password,
+		Deactivated: !attrs.Active,
+	}
+	if len(attrs.ExternalID) > 0 {
+		if err := h.checkExternalIDUnused(attrs.ExternalID, uuid.Nil); err != nil {
+			return h.respondError(c, err)
+		}
+		args.ExternalLogin = &model.ExternalProviderUser{
+			ProviderName: externalProviderName,
+			ExternalID:   attrs.ExternalID,
+			Extra:        model.JSON{},
+		}
+	}
+	iconFileID, err := file.GenerateIconFile(h.FileManager, attrs.UserName)
+	if err != nil {
+		return h.respondError(c, err)
+	}
+	args.IconFileID = iconFileID
+
+	user, err := h.Repo.CreateUser(args)
+	if err != nil {
+		if err == repository.ErrAlreadyExists {
+			return h.respondError(c, conflict("userName is already used"))
+		}
+		return h.respondError(c, err)
+	}
+	h.recordAuditLog(c, model.AuditActionUserCreate, model.AuditTargetUser, user.GetID().String(), nil, model.JSON{"name": user.GetName(), "role": user.GetRole(), "state": user.GetState().Int()})
+
+	return h.respondUser(c, http.StatusCreated, user.GetID())
+}
+
+// GetUser GET /Users/:id
+func (h *Handler) GetUser(c echo.Context) error {
+	return h.respondUser(c, http.StatusOK, uuid.FromStringOrNil(c.Param("id")))
+}
+
+// ReplaceUser PUT /Users/:id
+func (h *Handler) ReplaceUser(c echo.Context) error {
+	user, current, err := h.getUserAttributes(c.Param("id"))
+	if err != nil {
+		return h.respondError(c, err)
+	}
+
+	var req userRequest
+	if err := bindJSON(c, &req); err != nil {
+		return h.respondError(c, err)
+	}
+	attrs, err := req.attributes()
+	if err != nil {
+		return h.respondError(c, err)
+	}
+
+	if err := h.updateUser(c, user, current, attrs); err != nil {
+		return h.respondError(c, err)
+	}
+	return h.respondUser(c, http.StatusOK, user.GetID())
+}
+
+// PatchUser PATCH /Users/:id
+func (h *Handler) PatchUser(c echo.Context) error {
+	user, current, err := h.getUserAttributes(c.Param("id"))
+	if err != nil {
+		return h.respondError(c, err)
+	}
+
+	var req patchRequest
+	if err := bindJSON(c, &req); err != nil {
+		return h.respondError(c, err)
+	}
+	if err := req.validate(); err != nil {
+		return h.respondError(c, err)
+	}
+
+	attrs := current
+	attrs.Password = ""
+	for _, op := range req.Operations {
+		if err := attrs.apply(op); err != nil {
+			return h.respondError(c, err)
+		}
+	}
+
+	if err := h.updateUser(c, user, current, attrs); err != nil {
+		return h.respondError(c, err)
+	}
+	return h.respondUser(c, http.StatusOK, user.GetID())
+}
+
+// DeleteUser DELETE /Users/:id
+//
+// traQではユーザーを削除できないため、アカウントを凍結します。
+func (h *Handler) DeleteUser(c echo.Context) error {
+	user, current, err := h.getUserAttributes(c.Param("id"))
+	if err != nil {
+		return h.respondError(c, err)
+	}
+
+	attrs := current
+	attrs.Active = false
+	if err := h.updateUser(c, user, current, attrs); err != nil {
+		return h.respondError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// getUserAttributes 指定したIDのBOTでないユーザーとその現在の属性を取得します
+func (h *Handler) getUserAttributes(id string) (model.UserInfo, userAttributes, error) {
+	user, err := h.Repo.GetUser(uuid.FromStringOrNil(id), false)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil, userAttributes{}, notFound("user not found")
+		}
+		return nil, userAttributes{}, err
+	}
+	if user.IsBot() {
+		return nil, userAttributes{}, notFound("user not found")
+	}
+
+	externalID, err := h.getExternalID(user.GetID())
+	if err != nil {
+		return nil, userAttributes{}, err
+	}
+	return user, userAttributes{
+		ExternalID:  externalID,
+		UserName:    user.GetName(),
+		DisplayName: user.GetDisplayName(),
+		Active:      user.IsActive(),
+	}, nil
+}
+
+// updateUser ユーザーの属性をcurrentからnextに更新します
+func (h *Handler) updateUser(c echo.Context, user model.UserInfo, current, next userAttributes) error {
+	if len(next.UserName) == 0 {
+		next.UserName = current.UserName
+	}
+	if next.UserName != current.UserName {
+		return badRequest(errMutability, "userName cannot be changed")
+	}
+	if err := next.validate(); err != nil {
+		return err
+	}
+
+	var (
+		args          repository.UpdateUserArgs
+		changed       bool
+		before, after = model.JSON{}, model.JSON{}
+	)
+	if next.DisplayName != current.DisplayName {
+		args.DisplayName = optional.StringFrom(next.DisplayName)
+		before["displayName"], after["displayName"] = current.DisplayName, next.DisplayName
+		changed = true
+	}
+	if next.Active != current.Active {
+		args.UserState.Valid = true
+		if next.Active {
+			args.UserState.State = model.UserAccountStatusActive
+		} else {
+			args.UserState.State = model.UserAccountStatusDeactivated
+		}
+		before["state"], after["state"] = user.GetState().Int(), args.UserState.State.Int()
+		changed = true
+	}
+	if len(next.Password) > 0 {
+		args.Password = optional.StringFrom(next.Password)
+		changed = true
+	}
+	if next.ExternalID != current.ExternalID && len(next.ExternalID) > 0 {
+		if err := h.checkExternalIDUnused(next.ExternalID, user.GetID()); err != nil {
+			return err
+		}
+	}
+
+	if changed {
+		if err := h.Repo.UpdateUser(user.GetID(), args); err != nil {
+			return err
+		}
+		if len(after) > 0 {
+			h.recordAuditLog(c, model.AuditActionUserEdit, model.AuditTargetUser, user.GetID().String(), before, after)
+		}
+		if args.Password.Valid {
+			h.recordAuditLog(c, model.AuditActionUserPasswordChange, model.AuditTargetUser, user.GetID().String(), nil, nil)
+		}
+	}
+	if next.ExternalID != current.ExternalID {
+		if len(next.ExternalID) > 0 {
+			// 関連付けの解除と再設定の間に失敗してexternalIdが失われないよう、置き換えは一括で行う
+			if err := h.Repo.ReplaceExternalUserAccount(user.GetID(), repository.LinkExternalUserAccountArgs{
+				ProviderName: externalProviderName,
+				ExternalID:   next.ExternalID,
+				Extra:        model.JSON{},
+			}); err != nil {
+				if err == repository.ErrAlreadyExists {
+					return conflict("externalId is already used")
+				}
+				return err
+			}
+		} else if err := h.Repo.UnlinkExternalUserAccount(user.GetID(), externalProviderName); err != nil && err != repository.ErrNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkExternalIDUnused externalIdが指定したユーザー以外に使われていないことを確認します
+func (h *Handler) checkExternalIDUnused(externalID string, userID uuid.UUID) error {
+	u, err := h.Repo.GetUserByExternalID(externalProviderName, externalID, false)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return nil
+		}
+		return err
+	}
+	if u.GetID() != userID {
+		return conflict("externalId is already used")
+	}
+	return nil
+}
+
+// getExternalID ユーザーに関連付けられたexternalIdを取得します
+func (h *Handler) getExternalID(userID uuid.UUID) (string, error) {
+	accounts, err := h.Repo.GetLinkedExternalUserAccounts(userID)
+	if err != nil {
+		return "", err
+	}
+	for _, a := range accounts {
+		if a.ProviderName == externalProviderName {
+			return a.ExternalID, nil
+		}
+	}
+	return "", nil
+}
+
+// respondUser 指定したユーザーのリソースをレスポンスとして返します
+func (h *Handler) respondUser(c echo.Context, status int, id uuid.UUID) error {
+	user, err := h.Repo.GetUser(id, false)
+	if err != nil {
+		if err == repository.ErrNotFound {
+			return h.respondError(c, notFound("user not found"))
+		}
+		return h.respondError(c, err)
+	}
+	if user.IsBot() {
+		return h.respondError(c, notFound("user not found"))
+	}
+
+	resources, err := h.formatUsers([]model.UserInfo{user})
+	if err != nil {
+		return h.respondError(c, err)
+	}
+	res := resources[0]
+	if status == http.StatusCreated {
+		c.Response().Header().Set(echo.HeaderLocation, res.Meta.Location)
+	}
+	return jsonResponse(c, status, res)
+}
+
+// formatUsers ユーザーのリソースを生成します
+//
+// externalIdと所属グループはユーザー数に依らず一括で取得します。
+func (h *Handler) formatUsers(users []model.UserInfo) ([]*userResource, error) {
+	ids := make([]uuid.UUID, len(users))
+	for i, u := range users {
+		ids[i] = u.GetID()
+	}
+
+	accounts, err := h.Repo.GetExternalUserAccountsByProvider(externalProviderName, ids)
+	if err != nil {
+		return nil, err
+	}
+	externalIDs := make(map[uuid.UUID]string, len(accounts))
+	for _, a := range accounts {
+		externalIDs[a.UserID] = a.ExternalID
+	}
+	groupIDs, err := h.Repo.GetUsersBelongingGroupIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]*userResource, len(users))
+	for i, u := range users {
+		resources[i] = h.formatUser(u, externalIDs[u.GetID()], groupIDs[u.GetID()])
+	}
+	return resources, nil
+}
+
+func (h *Handler) formatUser(user model.UserInfo, externalID string, groupIDs []uuid.UUID) *userResource {
+	groups := make([]memberValue, len(groupIDs))
+	for i, id := range groupIDs {
+		groups[i] = memberValue{Value: id.String(), Ref: h.location("Groups", id.String())}
+	}
+	return &userResource{
+		Schemas:     []string{schemaUser},
+		ID:          user.GetID().String(),
+		ExternalID:  externalID,
+		UserName:    user.GetName(),
+		DisplayName: user.GetDisplayName(),
+		Active:      user.IsActive(),
+		Groups:      groups,
+		Meta: meta{
+			ResourceType: "User",
+			Created:      user.GetCreatedAt(),
+			LastModified: user.GetUpdatedAt(),
+			Location:     h.location("Users", user.GetID().String()),
+		},
+	}
+}
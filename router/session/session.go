@@ -14,6 +14,16 @@ const (
 	sessionMaxAge  = 60 * 60 * 24 * 14 // 2 weeks
 	sessionKeepAge = 60 * 60 * 24 * 14 // 2 weeks
 	cacheSize      = 2048
+
+	// KeyTwoFactorEnrollmentRequired 二要素認証の登録が必要なセッションであることを示すセッションデータのキー
+	KeyTwoFactorEnrollmentRequired = "two_factor_enrollment_required"
+	// KeyTwoFactorUserID 二要素認証待ちのユーザーIDを示すセッションデータのキー
+	KeyTwoFactorUserID = "two_factor_user_id"
+	// KeyTwoFactorExpiresAt 二要素認証待ちの期限(unix time)を示すセッションデータのキー
+	KeyTwoFactorExpiresAt = "two_factor_expires_at"
+
+	// TwoFactorLoginExp 一次認証後、二要素認証を完了するまでの猶予時間
+	TwoFactorLoginExp = 5 * time.Minute
 )
 
 var ErrSessionNotFound = errors.New("session not found")
@@ -43,3 +53,17 @@ type Store interface {
 	RenewSession(c echo.Context, userID uuid.UUID) (Session, error)
 	IssueSession(userID uuid.UUID, data map[string]interface{}) (Session, error)
 }
+
+// StartTwoFactorLogin 一次認証に成功したユーザーの二要素認証待ちセッションを開始します
+//
+// 開始されたセッションはログイン済みとはみなされません。
+func StartTwoFactorLogin(c echo.Context, store Store, userID uuid.UUID) error {
+	sess, err := store.RenewSession(c, uuid.Nil)
+	if err != nil {
+		return err
+	}
+	if err := sess.Set(KeyTwoFactorUserID, userID.String()); err != nil {
+		return err
+	}
+	return sess.Set(KeyTwoFactorExpiresAt, time.Now().Add(TwoFactorLoginExp).Unix())
+}
@@ -34,6 +34,14 @@ func (m *replaceMapperImpl) User(name string) (uuid.UUID, bool) {
 	return u.GetID(), true
 }
 
+func (m *replaceMapperImpl) Stamp(alias string) (string, bool) {
+	s, err := m.repo.GetStampByAlias(alias)
+	if err != nil {
+		return "", false
+	}
+	return s.Name, true
+}
+
 func NewReplaceMapper(repo repository.Repository, cm channel.Manager) message.ReplaceMapper {
 	return &replaceMapperImpl{
 		repo: repo,
@@ -56,7 +56,7 @@ func TestMain(m *testing.M) {
 		env.SessStore = session.NewMemorySessionStore()
 		env.RBAC = testutils.NewTestRBAC()
 		env.ChannelManager, _ = channel.InitChannelManager(env.Repository, zap.NewNop())
-		env.MessageManager, _ = message.NewMessageManager(env.Repository, env.ChannelManager, zap.NewNop())
+		env.MessageManager, _ = message.NewMessageManager(env.Repository, env.ChannelManager, env.Hub, zap.NewNop())
 		env.ImageProcessor = imaging.NewProcessor(imaging.Config{
 			MaxPixels:        1000 * 1000,
 			Concurrency:      1,
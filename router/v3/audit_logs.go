@@ -0,0 +1,100 @@
+package v3
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/router/consts"
+	"github.com/traPtitech/traQ/router/extension/herror"
+	"github.com/traPtitech/traQ/utils/optional"
+)
+
+// recordAuditLog 特権操作の監査ログを記録します
+//
+// before, afterには操作対象のフィールドの変更前後の値を指定します。値が変化していないフィールドは記録されません。
+// 操作自体は完了しているため、記録に失敗した場合はエラーログを出力するのみです。
+func (h *Handlers) recordAuditLog(c echo.Context, action model.AuditAction, targetType model.AuditTargetType, targetID string, before, after model.JSON) {
+	before, after = model.AuditDiff(before, after)
+	userAgent := c.Request().UserAgent()
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	log := &model.AuditLog{
+		ActorID:    getRequestUserID(c),
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Before:     before,
+		After:      after,
+		IP:         c.RealIP(),
+		UserAgent:  userAgent,
+	}
+	if err := h.Repo.CreateAuditLog(log); err != nil {
+		h.L(c).Error("failed to record audit log", zap.Error(err), zap.String("action", string(action)), zap.String("targetType", string(targetType)), zap.String("targetId", targetID))
+	}
+}
+
+// GetAuditLogsRequest GET /audit-logs リクエストクエリ
+type GetAuditLogsRequest struct {
+	Actor      optional.UUID `query:"actor"`
+	Action     string        `query:"action"`
+	TargetType string        `query:"targetType"`
+	TargetID   string        `query:"targetId"`
+	Limit      int           `query:"limit"`
+	Offset     int           `query:"offset"`
+	Since      optional.Time `query:"since"`
+	Until      optional.Time `query:"until"`
+	Inclusive  bool          `query:"inclusive"`
+	Order      string        `query:"order"`
+}
+
+func (r *GetAuditLogsRequest) Validate() error {
+	if r.Limit == 0 {
+		r.Limit = 50
+	}
+	return vd.ValidateStruct(r,
+		vd.Field(&r.Action, vd.RuneLength(0, 50)),
+		vd.Field(&r.TargetType, vd.RuneLength(0, 30)),
+		vd.Field(&r.TargetID, vd.RuneLength(0, 100)),
+		vd.Field(&r.Limit, vd.Min(1), vd.Max(200)),
+		vd.Field(&r.Offset, vd.Min(0)),
+	)
+}
+
+func (r *GetAuditLogsRequest) convert() repository.AuditLogQuery {
+	return repository.AuditLogQuery{
+		ActorID:    r.Actor,
+		Action:     model.AuditAction(r.Action),
+		TargetType: model.AuditTargetType(r.TargetType),
+		TargetID:   r.TargetID,
+		Since:      r.Since,
+		Until:      r.Until,
+		Inclusive:  r.Inclusive,
+		Limit:      r.Limit,
+		Offset:     r.Offset,
+		Asc:        strings.ToLower(r.Order) == "asc",
+	}
+}
+
+// GetAuditLogs GET /audit-logs
+func (h *Handlers) GetAuditLogs(c echo.Context) error {
+	var req GetAuditLogsRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	logs, more, err := h.Repo.GetAuditLogs(req.convert())
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	c.Response().Header().Set(consts.HeaderMore, strconv.FormatBool(more))
+	return c.JSON(http.StatusOK, logs)
+}
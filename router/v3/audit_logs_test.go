@@ -0,0 +1,75 @@
+package v3
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/router/consts"
+	"github.com/traPtitech/traQ/router/session"
+)
+
+func TestHandlers_GetAuditLogs(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/audit-logs"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	admin := env.CreateAdmin(t, rand)
+	s := env.S(t, user.GetID())
+	adminSession := env.S(t, admin.GetID())
+
+	e := env.R(t)
+	e.PATCH("/api/v3/users/{userId}", user.GetID()).
+		WithCookie(session.CookieName, adminSession).
+		WithJSON(map[string]interface{}{"displayName": "audited"}).
+		Expect().
+		Status(http.StatusNoContent)
+
+	t.Run("not logged in", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET(path).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET(path).
+			WithCookie(session.CookieName, s).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
+	t.Run("bad request", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET(path).
+			WithCookie(session.CookieName, adminSession).
+			WithQuery("limit", 1000).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		res := e.GET(path).
+			WithCookie(session.CookieName, adminSession).
+			WithQuery("targetType", model.AuditTargetUser).
+			WithQuery("targetId", user.GetID()).
+			Expect().
+			Status(http.StatusOK)
+		res.Header(consts.HeaderMore).Equal("false")
+
+		arr := res.JSON().Array()
+		arr.Length().Equal(1)
+		obj := arr.First().Object()
+		obj.Value("actorId").String().Equal(admin.GetID().String())
+		obj.Value("action").String().Equal(string(model.AuditActionUserEdit))
+		obj.Value("after").Object().Value("displayName").String().Equal("audited")
+		obj.Value("after").Object().NotContainsKey("state")
+	})
+}
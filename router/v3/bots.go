@@ -306,6 +306,7 @@ func (h *Handlers) ReissueBot(c echo.Context) error {
 	if err != nil {
 		return herror.InternalServerError(err)
 	}
+	h.recordAuditLog(c, model.AuditActionBotReissue, model.AuditTargetBot, b.ID.String(), nil, nil)
 
 	t, err := h.Repo.GetTokenByID(b.AccessTokenID)
 	if err != nil {
@@ -0,0 +1,65 @@
+package v3
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/router/extension/herror"
+)
+
+// GetChannelArchivalReport GET /channels/archival-report
+func (h *Handlers) GetChannelArchivalReport(c echo.Context) error {
+	candidates, err := h.AutoArchive.Report()
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, formatChannelArchivalCandidates(candidates, h.ChannelManager.PublicChannelTree()))
+}
+
+// GetChannelArchival GET /channels/:channelID/archival
+func (h *Handlers) GetChannelArchival(c echo.Context) error {
+	ch := getParamChannel(c)
+	if !ch.IsPublic {
+		return herror.BadRequest("not a public channel")
+	}
+
+	state, err := h.Repo.GetChannelArchivalState(ch.ID)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			state = &model.ChannelArchivalState{ChannelID: ch.ID}
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	return c.JSON(http.StatusOK, formatChannelArchival(state))
+}
+
+// PutChannelArchivalRequest PUT /channels/:channelID/archival リクエストボディ
+type PutChannelArchivalRequest struct {
+	Exempt bool `json:"exempt"`
+}
+
+// EditChannelArchival PUT /channels/:channelID/archival
+func (h *Handlers) EditChannelArchival(c echo.Context) error {
+	ch := getParamChannel(c)
+
+	var req PutChannelArchivalRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	if !ch.IsPublic {
+		return herror.BadRequest("not a public channel")
+	}
+
+	if err := h.Repo.SetChannelArchivalExempt(ch.ID, req.Exempt); err != nil {
+		return herror.InternalServerError(err)
+	}
+	h.recordAuditLog(c, model.AuditActionChannelArchivalEdit, model.AuditTargetChannel, ch.ID.String(), model.JSON{}, model.JSON{"exempt": req.Exempt})
+
+	return c.NoContent(http.StatusNoContent)
+}
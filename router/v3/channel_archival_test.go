@@ -0,0 +1,188 @@
+package v3
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/traPtitech/traQ/router/session"
+	"github.com/traPtitech/traQ/service/autoarchive"
+	"github.com/traPtitech/traQ/service/rbac/role"
+)
+
+func TestHandlers_GetChannelArchivalReport(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/channels/archival-report"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	admin := env.CreateAdmin(t, rand)
+	ch := env.CreateChannel(t, rand)
+	s := env.S(t, user.GetID())
+	adminSession := env.S(t, admin.GetID())
+
+	t.Run("not logged in", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET(path).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET(path).
+			WithCookie(session.CookieName, s).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		arr := e.GET(path).
+			WithCookie(session.CookieName, adminSession).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Array()
+
+		found := false
+		for _, v := range arr.Iter() {
+			obj := v.Object()
+			if obj.Value("channelId").String().Raw() != ch.ID.String() {
+				continue
+			}
+			found = true
+			obj.Value("path").String().Equal(ch.Name)
+			obj.Value("action").String().Equal(string(autoarchive.ActionWarn))
+			obj.Value("warnedAt").Null()
+			obj.Value("archiveAt").String().NotEmpty()
+		}
+		assert.True(t, found)
+	})
+}
+
+func TestHandlers_GetChannelArchival(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/channels/{channelId}/archival"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	ch := env.CreateChannel(t, rand)
+	exempt := env.CreateChannel(t, rand)
+	s := env.S(t, user.GetID())
+	require.NoError(t, env.Repository.SetChannelArchivalExempt(exempt.ID, true))
+
+	t.Run("not logged in", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET(path, ch.ID).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("success (no state)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		obj := e.GET(path, ch.ID).
+			WithCookie(session.CookieName, s).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Object()
+
+		obj.Value("exempt").Boolean().False()
+		obj.Value("warnedAt").Null()
+	})
+
+	t.Run("success (exempt)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		obj := e.GET(path, exempt.ID).
+			WithCookie(session.CookieName, s).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Object()
+
+		obj.Value("exempt").Boolean().True()
+	})
+}
+
+func TestHandlers_EditChannelArchival(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/channels/{channelId}/archival"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	moderator := env.CreateUser(t, rand)
+	admin := env.CreateAdmin(t, rand)
+	ch := env.CreateChannel(t, rand)
+	ch2 := env.CreateChannel(t, rand)
+	dm := env.CreateDMChannel(t, user.GetID(), admin.GetID())
+	s := env.S(t, user.GetID())
+	moderatorSession := env.S(t, moderator.GetID())
+	adminSession := env.S(t, admin.GetID())
+	require.NoError(t, env.Repository.SetChannelRole(ch2.ID, moderator.GetID(), role.ChannelModerator))
+
+	t.Run("not logged in", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PUT(path, ch.ID).
+			WithJSON(&PutChannelArchivalRequest{Exempt: true}).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PUT(path, ch.ID).
+			WithCookie(session.CookieName, s).
+			WithJSON(&PutChannelArchivalRequest{Exempt: true}).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
+	t.Run("dm channel", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PUT(path, dm.ID).
+			WithCookie(session.CookieName, adminSession).
+			WithJSON(&PutChannelArchivalRequest{Exempt: true}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("success (admin)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PUT(path, ch.ID).
+			WithCookie(session.CookieName, adminSession).
+			WithJSON(&PutChannelArchivalRequest{Exempt: true}).
+			Expect().
+			Status(http.StatusNoContent)
+
+		state, err := env.Repository.GetChannelArchivalState(ch.ID)
+		require.NoError(t, err)
+		assert.True(t, state.Exempt)
+	})
+
+	t.Run("success (channel moderator)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PUT(path, ch2.ID).
+			WithCookie(session.CookieName, moderatorSession).
+			WithJSON(&PutChannelArchivalRequest{Exempt: true}).
+			Expect().
+			Status(http.StatusNoContent)
+
+		state, err := env.Repository.GetChannelArchivalState(ch2.ID)
+		require.NoError(t, err)
+		assert.True(t, state.Exempt)
+	})
+}
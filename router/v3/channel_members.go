@@ -0,0 +1,96 @@
+package v3
+
+import (
+	"net/http"
+
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/labstack/echo/v4"
+
+	"github.com/traPtitech/traQ/router/extension/herror"
+	"github.com/traPtitech/traQ/service/channel"
+	"github.com/traPtitech/traQ/service/rbac/permission"
+	"github.com/traPtitech/traQ/utils/set"
+)
+
+// GetChannelMembers GET /channels/:channelID/members
+func (h *Handlers) GetChannelMembers(c echo.Context) error {
+	ch := getParamChannel(c)
+	if !ch.IsPrivateChannel() {
+		return herror.BadRequest("not a private channel")
+	}
+
+	members, err := h.ChannelManager.GetPrivateChannelMembers(ch.ID)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, members)
+}
+
+// PostChannelMembersRequest POST /channels/:channelID/members リクエストボディ
+type PostChannelMembersRequest struct {
+	UserIDs set.UUID `json:"userIds"`
+}
+
+func (r PostChannelMembersRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.UserIDs, vd.Required),
+	)
+}
+
+// AddChannelMembers POST /channels/:channelID/members
+func (h *Handlers) AddChannelMembers(c echo.Context) error {
+	ch := getParamChannel(c)
+
+	var req PostChannelMembersRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	for id := range req.UserIDs {
+		ok, err := h.Repo.UserExists(id)
+		if err != nil {
+			return herror.InternalServerError(err)
+		}
+		if !ok {
+			return herror.BadRequest("unknown user: " + id.String())
+		}
+	}
+
+	if err := h.ChannelManager.AddPrivateChannelMembers(ch.ID, req.UserIDs, getRequestUserID(c)); err != nil {
+		switch err {
+		case channel.ErrInvalidChannel:
+			return herror.BadRequest("not a private channel")
+		case channel.ErrChannelArchived:
+			return herror.BadRequest("channel has been archived")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// RemoveChannelMember DELETE /channels/:channelID/members/:userID
+//
+// 自分自身を指定した場合はチャンネルからの退出になります。
+// 他のメンバーを削除できるのは、チャンネルの作成者とチャンネル情報変更権限を持つユーザーのみです。
+func (h *Handlers) RemoveChannelMember(c echo.Context) error {
+	ch := getParamChannel(c)
+	user := getParamUser(c)
+	me := getRequestUser(c)
+
+	if user.GetID() != me.GetID() && ch.CreatorID != me.GetID() && !h.RBAC.IsGranted(me.GetRole(), permission.EditChannel) {
+		return herror.Forbidden("you are not permitted to remove other members")
+	}
+
+	if err := h.ChannelManager.RemovePrivateChannelMember(ch.ID, user.GetID(), me.GetID()); err != nil {
+		switch err {
+		case channel.ErrInvalidChannel:
+			return herror.BadRequest("not a private channel")
+		case channel.ErrNotChannelMember:
+			return herror.NotFound("the user is not a member of this channel")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	return c.NoContent(http.StatusNoContent)
+}
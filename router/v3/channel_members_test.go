@@ -0,0 +1,205 @@
+package v3
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/traPtitech/traQ/router/session"
+	"github.com/traPtitech/traQ/utils/set"
+)
+
+func TestHandlers_PrivateChannel(t *testing.T) {
+	t.Parallel()
+
+	env := Setup(t, common1)
+	creator := env.CreateUser(t, rand)
+	member := env.CreateUser(t, rand)
+	outsider := env.CreateUser(t, rand)
+	ch := env.CreatePrivateChannel(t, rand, creator.GetID(), member.GetID())
+	creatorSession := env.S(t, creator.GetID())
+	memberSession := env.S(t, member.GetID())
+	outsiderSession := env.S(t, outsider.GetID())
+
+	t.Run("create", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		obj := e.POST("/api/v3/channels").
+			WithCookie(session.CookieName, creatorSession).
+			WithJSON(&PostChannelRequest{Name: "secret", Private: true}).
+			Expect().
+			Status(http.StatusCreated).
+			JSON().
+			Object()
+
+		obj.Value("name").String().Equal("secret")
+		obj.Value("parentId").Null()
+
+		id := uuid.FromStringOrNil(obj.Value("id").String().Raw())
+		assert.False(t, env.CM.IsPublicChannel(id))
+		members, err := env.CM.GetPrivateChannelMembers(id)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []uuid.UUID{creator.GetID()}, members)
+	})
+
+	t.Run("not listed in public channels", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		obj := e.GET("/api/v3/channels").
+			WithCookie(session.CookieName, outsiderSession).
+			WithQuery("include-private", true).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Object()
+
+		for _, v := range obj.Value("public").Array().Iter() {
+			v.Object().Value("id").String().NotEqual(ch.ID.String())
+		}
+		obj.Value("private").Array().Length().Equal(0)
+	})
+
+	t.Run("listed for members", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		obj := e.GET("/api/v3/channels").
+			WithCookie(session.CookieName, memberSession).
+			WithQuery("include-private", true).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Object()
+
+		private := obj.Value("private").Array()
+		private.Length().Equal(1)
+		private.First().Object().Value("id").String().Equal(ch.ID.String())
+	})
+
+	t.Run("outsider cannot access", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET("/api/v3/channels/{channelId}/members", ch.ID).
+			WithCookie(session.CookieName, outsiderSession).
+			Expect().
+			Status(http.StatusNotFound)
+	})
+
+	t.Run("members", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET("/api/v3/channels/{channelId}/members", ch.ID).
+			WithCookie(session.CookieName, memberSession).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Array().
+			ContainsOnly(creator.GetID().String(), member.GetID().String())
+	})
+
+	t.Run("same name as another user's private channel", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+
+		// 他ユーザーのプライベートチャンネルの存在が漏れないよう、同名でも作成できる
+		e.POST("/api/v3/channels").
+			WithCookie(session.CookieName, outsiderSession).
+			WithJSON(&PostChannelRequest{Name: ch.Name, Private: true}).
+			Expect().
+			Status(http.StatusCreated).
+			JSON().
+			Object().
+			Value("name").
+			String().
+			Equal(ch.Name)
+	})
+}
+
+func TestHandlers_PrivateChannelMembership(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/channels/{channelId}/members"
+	env := Setup(t, common1)
+	creator := env.CreateUser(t, rand)
+	member := env.CreateUser(t, rand)
+	invitee := env.CreateUser(t, rand)
+	creatorSession := env.S(t, creator.GetID())
+	memberSession := env.S(t, member.GetID())
+	public := env.CreateChannel(t, rand)
+
+	t.Run("not a private channel", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path, public.ID).
+			WithCookie(session.CookieName, creatorSession).
+			WithJSON(&PostChannelMembersRequest{UserIDs: set.UUIDSetFromArray([]uuid.UUID{invitee.GetID()})}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		t.Parallel()
+		ch := env.CreatePrivateChannel(t, rand, creator.GetID())
+		e := env.R(t)
+		e.POST(path, ch.ID).
+			WithCookie(session.CookieName, creatorSession).
+			WithJSON(&PostChannelMembersRequest{UserIDs: set.UUIDSetFromArray([]uuid.UUID{uuid.Must(uuid.NewV4())})}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("invite", func(t *testing.T) {
+		t.Parallel()
+		ch := env.CreatePrivateChannel(t, rand, creator.GetID(), member.GetID())
+		e := env.R(t)
+		e.POST(path, ch.ID).
+			WithCookie(session.CookieName, memberSession).
+			WithJSON(&PostChannelMembersRequest{UserIDs: set.UUIDSetFromArray([]uuid.UUID{invitee.GetID()})}).
+			Expect().
+			Status(http.StatusNoContent)
+
+		ok, err := env.CM.IsChannelAccessibleToUser(invitee.GetID(), ch.ID)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("kick (forbidden)", func(t *testing.T) {
+		t.Parallel()
+		ch := env.CreatePrivateChannel(t, rand, creator.GetID(), member.GetID())
+		e := env.R(t)
+		e.DELETE(path+"/{userId}", ch.ID, creator.GetID()).
+			WithCookie(session.CookieName, memberSession).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
+	t.Run("kick", func(t *testing.T) {
+		t.Parallel()
+		ch := env.CreatePrivateChannel(t, rand, creator.GetID(), member.GetID())
+		e := env.R(t)
+		e.DELETE(path+"/{userId}", ch.ID, member.GetID()).
+			WithCookie(session.CookieName, creatorSession).
+			Expect().
+			Status(http.StatusNoContent)
+
+		ok, err := env.CM.IsChannelAccessibleToUser(member.GetID(), ch.ID)
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("leave", func(t *testing.T) {
+		t.Parallel()
+		ch := env.CreatePrivateChannel(t, rand, creator.GetID(), member.GetID())
+		e := env.R(t)
+		e.DELETE(path+"/{userId}", ch.ID, member.GetID()).
+			WithCookie(session.CookieName, memberSession).
+			Expect().
+			Status(http.StatusNoContent)
+
+		members, err := env.CM.GetPrivateChannelMembers(ch.ID)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []uuid.UUID{creator.GetID()}, members)
+	})
+}
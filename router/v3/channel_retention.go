@@ -0,0 +1,123 @@
+package v3
+
+import (
+	"net/http"
+
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/labstack/echo/v4"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/router/extension/herror"
+)
+
+// GetChannelRetentionPolicies GET /channels/retention-policies
+func (h *Handlers) GetChannelRetentionPolicies(c echo.Context) error {
+	policies, err := h.Repo.GetChannelRetentionPolicies()
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, formatChannelRetentionPolicies(policies))
+}
+
+// GetChannelRetention GET /channels/:channelID/retention
+func (h *Handlers) GetChannelRetention(c echo.Context) error {
+	ch := getParamChannel(c)
+	if !ch.IsPublic {
+		return herror.BadRequest("not a public channel")
+	}
+
+	policy, err := h.Repo.GetChannelRetentionPolicy(ch.ID)
+	if err != nil && err != repository.ErrNotFound {
+		return herror.InternalServerError(err)
+	}
+	effective, err := h.Retention.GetEffectivePolicy(ch.ID)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, &ChannelRetention{
+		Policy:    formatChannelRetentionPolicy(policy),
+		Effective: formatChannelRetentionPolicy(effective),
+	})
+}
+
+// PutChannelRetentionRequest PUT /channels/:channelID/retention リクエストボディ
+type PutChannelRetentionRequest struct {
+	RetentionDays int  `json:"retentionDays"`
+	Purge         bool `json:"purge"`
+	KeepPinned    bool `json:"keepPinned"`
+}
+
+func (r PutChannelRetentionRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.RetentionDays, vd.Min(0), vd.Max(model.MaxRetentionDays)),
+	)
+}
+
+// EditChannelRetention PUT /channels/:channelID/retention
+func (h *Handlers) EditChannelRetention(c echo.Context) error {
+	ch := getParamChannel(c)
+
+	var req PutChannelRetentionRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	if !ch.IsPublic {
+		return herror.BadRequest("not a public channel")
+	}
+
+	before, err := h.Repo.GetChannelRetentionPolicy(ch.ID)
+	if err != nil && err != repository.ErrNotFound {
+		return herror.InternalServerError(err)
+	}
+	p, err := h.Repo.SetChannelRetentionPolicy(ch.ID, repository.SetChannelRetentionPolicyArgs{
+		RetentionDays: req.RetentionDays,
+		Purge:         req.Purge,
+		KeepPinned:    req.KeepPinned,
+		UpdaterID:     getRequestUserID(c),
+	})
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	h.recordAuditLog(c, model.AuditActionChannelRetentionEdit, model.AuditTargetChannel, ch.ID.String(), retentionPolicyAuditDetail(before), retentionPolicyAuditDetail(p))
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DeleteChannelRetention DELETE /channels/:channelID/retention
+func (h *Handlers) DeleteChannelRetention(c echo.Context) error {
+	ch := getParamChannel(c)
+
+	before, err := h.Repo.GetChannelRetentionPolicy(ch.ID)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return herror.NotFound("retention policy is not set")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	if err := h.Repo.DeleteChannelRetentionPolicy(ch.ID); err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return herror.NotFound("retention policy is not set")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	h.recordAuditLog(c, model.AuditActionChannelRetentionDelete, model.AuditTargetChannel, ch.ID.String(), retentionPolicyAuditDetail(before), model.JSON{})
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func retentionPolicyAuditDetail(p *model.ChannelRetentionPolicy) model.JSON {
+	if p == nil {
+		return model.JSON{}
+	}
+	return model.JSON{
+		"retentionDays": p.RetentionDays,
+		"purge":         p.Purge,
+		"keepPinned":    p.KeepPinned,
+	}
+}
@@ -0,0 +1,252 @@
+package v3
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/router/session"
+	"github.com/traPtitech/traQ/utils/random"
+)
+
+func TestHandlers_GetChannelRetentionPolicies(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/channels/retention-policies"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	admin := env.CreateAdmin(t, rand)
+	ch := env.CreateChannel(t, rand)
+	s := env.S(t, user.GetID())
+	adminSession := env.S(t, admin.GetID())
+	_, err := env.Repository.SetChannelRetentionPolicy(ch.ID, repository.SetChannelRetentionPolicyArgs{RetentionDays: 30, UpdaterID: admin.GetID()})
+	require.NoError(t, err)
+
+	t.Run("not logged in", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET(path).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET(path).
+			WithCookie(session.CookieName, s).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		arr := e.GET(path).
+			WithCookie(session.CookieName, adminSession).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Array()
+
+		found := false
+		for _, v := range arr.Iter() {
+			obj := v.Object()
+			if obj.Value("channelId").String().Raw() != ch.ID.String() {
+				continue
+			}
+			found = true
+			obj.Value("retentionDays").Number().Equal(30)
+			obj.Value("purge").Boolean().False()
+			obj.Value("keepPinned").Boolean().False()
+			obj.Value("updaterId").String().Equal(admin.GetID().String())
+		}
+		assert.True(t, found)
+	})
+}
+
+func TestHandlers_GetChannelRetention(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/channels/{channelId}/retention"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	parent := env.CreateChannel(t, rand)
+	child, err := env.CM.CreatePublicChannel(random.AlphaNumeric(20), parent.ID, user.GetID())
+	require.NoError(t, err)
+	other := env.CreateChannel(t, rand)
+	s := env.S(t, user.GetID())
+	_, err = env.Repository.SetChannelRetentionPolicy(parent.ID, repository.SetChannelRetentionPolicyArgs{RetentionDays: 30, KeepPinned: true, UpdaterID: user.GetID()})
+	require.NoError(t, err)
+
+	t.Run("not logged in", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET(path, parent.ID).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("success (own policy)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		obj := e.GET(path, parent.ID).
+			WithCookie(session.CookieName, s).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Object()
+
+		obj.Value("policy").Object().Value("channelId").String().Equal(parent.ID.String())
+		obj.Value("effective").Object().Value("channelId").String().Equal(parent.ID.String())
+	})
+
+	t.Run("success (inherited policy)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		obj := e.GET(path, child.ID).
+			WithCookie(session.CookieName, s).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Object()
+
+		obj.Value("policy").Null()
+		effective := obj.Value("effective").Object()
+		effective.Value("channelId").String().Equal(parent.ID.String())
+		effective.Value("retentionDays").Number().Equal(30)
+		effective.Value("keepPinned").Boolean().True()
+	})
+
+	t.Run("success (no policy)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		obj := e.GET(path, other.ID).
+			WithCookie(session.CookieName, s).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Object()
+
+		obj.Value("policy").Null()
+		obj.Value("effective").Null()
+	})
+}
+
+func TestHandlers_EditChannelRetention(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/channels/{channelId}/retention"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	admin := env.CreateAdmin(t, rand)
+	ch := env.CreateChannel(t, rand)
+	dm := env.CreateDMChannel(t, user.GetID(), admin.GetID())
+	s := env.S(t, user.GetID())
+	adminSession := env.S(t, admin.GetID())
+
+	t.Run("not logged in", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PUT(path, ch.ID).
+			WithJSON(&PutChannelRetentionRequest{RetentionDays: 30}).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PUT(path, ch.ID).
+			WithCookie(session.CookieName, s).
+			WithJSON(&PutChannelRetentionRequest{RetentionDays: 30}).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
+	t.Run("bad request (negative days)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PUT(path, ch.ID).
+			WithCookie(session.CookieName, adminSession).
+			WithJSON(&PutChannelRetentionRequest{RetentionDays: -1}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("bad request (dm channel)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PUT(path, dm.ID).
+			WithCookie(session.CookieName, adminSession).
+			WithJSON(&PutChannelRetentionRequest{RetentionDays: 30}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PUT(path, ch.ID).
+			WithCookie(session.CookieName, adminSession).
+			WithJSON(&PutChannelRetentionRequest{RetentionDays: 30, Purge: true, KeepPinned: true}).
+			Expect().
+			Status(http.StatusNoContent)
+
+		p, err := env.Repository.GetChannelRetentionPolicy(ch.ID)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 30, p.RetentionDays)
+			assert.True(t, p.Purge)
+			assert.True(t, p.KeepPinned)
+			assert.Equal(t, admin.GetID(), p.UpdaterID)
+		}
+	})
+}
+
+func TestHandlers_DeleteChannelRetention(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/channels/{channelId}/retention"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	admin := env.CreateAdmin(t, rand)
+	ch := env.CreateChannel(t, rand)
+	ch2 := env.CreateChannel(t, rand)
+	s := env.S(t, user.GetID())
+	adminSession := env.S(t, admin.GetID())
+	_, err := env.Repository.SetChannelRetentionPolicy(ch.ID, repository.SetChannelRetentionPolicyArgs{RetentionDays: 30, UpdaterID: admin.GetID()})
+	require.NoError(t, err)
+
+	t.Run("forbidden", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.DELETE(path, ch.ID).
+			WithCookie(session.CookieName, s).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.DELETE(path, ch2.ID).
+			WithCookie(session.CookieName, adminSession).
+			Expect().
+			Status(http.StatusNotFound)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.DELETE(path, ch.ID).
+			WithCookie(session.CookieName, adminSession).
+			Expect().
+			Status(http.StatusNoContent)
+
+		_, err := env.Repository.GetChannelRetentionPolicy(ch.ID)
+		assert.EqualError(t, err, repository.ErrNotFound.Error())
+	})
+}
@@ -0,0 +1,92 @@
+package v3
+
+import (
+	"net/http"
+
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/router/extension/herror"
+	"github.com/traPtitech/traQ/service/rbac/role"
+)
+
+// GetChannelRoles GET /channels/:channelID/roles
+func (h *Handlers) GetChannelRoles(c echo.Context) error {
+	ch := getParamChannel(c)
+
+	roles, err := h.Repo.GetChannelRoles(ch.ID)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, formatChannelRoles(roles))
+}
+
+// PutChannelRoleRequest PUT /channels/:channelID/roles/:userID リクエストボディ
+type PutChannelRoleRequest struct {
+	Role string `json:"role"`
+}
+
+func (r PutChannelRoleRequest) Validate() error {
+	channelRoles := role.ChannelRoles()
+	valid := make([]interface{}, len(channelRoles))
+	for i, name := range channelRoles {
+		valid[i] = name
+	}
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Role, vd.Required, vd.In(valid...)),
+	)
+}
+
+// SetChannelRole PUT /channels/:channelID/roles/:userID
+func (h *Handlers) SetChannelRole(c echo.Context) error {
+	ch := getParamChannel(c)
+	user := getParamUser(c)
+
+	var req PutChannelRoleRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	if !ch.IsPublic {
+		return herror.BadRequest("channel roles can be assigned only in public channels")
+	}
+	if user.IsBot() {
+		return herror.BadRequest("channel roles cannot be assigned to bots")
+	}
+
+	current, err := h.Repo.GetUserChannelRoles(user.GetID(), []uuid.UUID{ch.ID})
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	if err := h.Repo.SetChannelRole(ch.ID, user.GetID(), req.Role); err != nil {
+		return herror.InternalServerError(err)
+	}
+	before := model.JSON{}
+	if len(current) > 0 {
+		before["role"] = current[0].Role
+	}
+	h.recordAuditLog(c, model.AuditActionChannelRoleSet, model.AuditTargetChannelRole, ch.ID.String()+"/"+user.GetID().String(), before, model.JSON{"role": req.Role})
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DeleteChannelRole DELETE /channels/:channelID/roles/:userID
+func (h *Handlers) DeleteChannelRole(c echo.Context) error {
+	ch := getParamChannel(c)
+	user := getParamUser(c)
+
+	if err := h.Repo.DeleteChannelRole(ch.ID, user.GetID()); err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return herror.NotFound("the user has no role in this channel")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	h.recordAuditLog(c, model.AuditActionChannelRoleDelete, model.AuditTargetChannelRole, ch.ID.String()+"/"+user.GetID().String(), nil, nil)
+
+	return c.NoContent(http.StatusNoContent)
+}
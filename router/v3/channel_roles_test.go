@@ -0,0 +1,196 @@
+package v3
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/traPtitech/traQ/router/session"
+	"github.com/traPtitech/traQ/service/rbac/role"
+	"github.com/traPtitech/traQ/utils/random"
+)
+
+func TestHandlers_GetChannelRoles(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/channels/{channelId}/roles"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	moderator := env.CreateUser(t, rand)
+	ch := env.CreateChannel(t, rand)
+	s := env.S(t, user.GetID())
+	require.NoError(t, env.Repository.SetChannelRole(ch.ID, moderator.GetID(), role.ChannelModerator))
+
+	t.Run("not logged in", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET(path, ch.ID).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		arr := e.GET(path, ch.ID).
+			WithCookie(session.CookieName, s).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Array()
+
+		arr.Length().Equal(1)
+		obj := arr.First().Object()
+		obj.Value("userId").String().Equal(moderator.GetID().String())
+		obj.Value("role").String().Equal(role.ChannelModerator)
+	})
+}
+
+func TestHandlers_SetChannelRole(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/channels/{channelId}/roles/{userId}"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	admin := env.CreateAdmin(t, rand)
+	ch := env.CreateChannel(t, rand)
+	dm := env.CreateDMChannel(t, user.GetID(), admin.GetID())
+	s := env.S(t, user.GetID())
+	adminSession := env.S(t, admin.GetID())
+
+	t.Run("not logged in", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PUT(path, ch.ID, user.GetID()).
+			WithJSON(&PutChannelRoleRequest{Role: role.ChannelModerator}).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PUT(path, ch.ID, user.GetID()).
+			WithCookie(session.CookieName, s).
+			WithJSON(&PutChannelRoleRequest{Role: role.ChannelModerator}).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
+	t.Run("invalid role", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PUT(path, ch.ID, user.GetID()).
+			WithCookie(session.CookieName, adminSession).
+			WithJSON(&PutChannelRoleRequest{Role: random.AlphaNumeric(10)}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("dm channel", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PUT(path, dm.ID, user.GetID()).
+			WithCookie(session.CookieName, adminSession).
+			WithJSON(&PutChannelRoleRequest{Role: role.ChannelModerator}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PUT(path, ch.ID, user.GetID()).
+			WithCookie(session.CookieName, adminSession).
+			WithJSON(&PutChannelRoleRequest{Role: role.ChannelModerator}).
+			Expect().
+			Status(http.StatusNoContent)
+
+		roles, err := env.Repository.GetChannelRoles(ch.ID)
+		require.NoError(t, err)
+		if assert.Len(t, roles, 1) {
+			assert.Equal(t, user.GetID(), roles[0].UserID)
+			assert.Equal(t, role.ChannelModerator, roles[0].Role)
+		}
+	})
+}
+
+func TestHandlers_DeleteChannelRole(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/channels/{channelId}/roles/{userId}"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	admin := env.CreateAdmin(t, rand)
+	ch := env.CreateChannel(t, rand)
+	adminSession := env.S(t, admin.GetID())
+	require.NoError(t, env.Repository.SetChannelRole(ch.ID, user.GetID(), role.ChannelModerator))
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.DELETE(path, ch.ID, admin.GetID()).
+			WithCookie(session.CookieName, adminSession).
+			Expect().
+			Status(http.StatusNotFound)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.DELETE(path, ch.ID, user.GetID()).
+			WithCookie(session.CookieName, adminSession).
+			Expect().
+			Status(http.StatusNoContent)
+
+		roles, err := env.Repository.GetChannelRoles(ch.ID)
+		require.NoError(t, err)
+		assert.Empty(t, roles)
+	})
+}
+
+func TestHandlers_ChannelModerator(t *testing.T) {
+	t.Parallel()
+
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	moderator := env.CreateUser(t, rand)
+	parent := env.CreateChannel(t, rand)
+	child, err := env.CM.CreatePublicChannel(rand, parent.ID, user.GetID())
+	require.NoError(t, err)
+	other := env.CreateChannel(t, rand)
+	require.NoError(t, env.Repository.SetChannelRole(parent.ID, moderator.GetID(), role.ChannelModerator))
+	s := env.S(t, moderator.GetID())
+
+	t.Run("delete others' message in subtree", func(t *testing.T) {
+		t.Parallel()
+		m := env.CreateMessage(t, user.GetID(), child.ID, rand)
+		e := env.R(t)
+		e.DELETE("/api/v3/messages/{messageId}", m.GetID()).
+			WithCookie(session.CookieName, s).
+			Expect().
+			Status(http.StatusNoContent)
+	})
+
+	t.Run("delete others' message outside subtree", func(t *testing.T) {
+		t.Parallel()
+		m := env.CreateMessage(t, user.GetID(), other.ID, rand)
+		e := env.R(t)
+		e.DELETE("/api/v3/messages/{messageId}", m.GetID()).
+			WithCookie(session.CookieName, s).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
+	t.Run("delete others' message as normal user", func(t *testing.T) {
+		t.Parallel()
+		m := env.CreateMessage(t, moderator.GetID(), child.ID, rand)
+		e := env.R(t)
+		e.DELETE("/api/v3/messages/{messageId}", m.GetID()).
+			WithCookie(session.CookieName, env.S(t, user.GetID())).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+}
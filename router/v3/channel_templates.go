@@ -0,0 +1,162 @@
+package v3
+
+import (
+	"net/http"
+
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/router/extension"
+	"github.com/traPtitech/traQ/router/extension/herror"
+	"github.com/traPtitech/traQ/service/channel"
+	"github.com/traPtitech/traQ/utils/optional"
+)
+
+// GetChannelTemplates GET /channel-templates
+func (h *Handlers) GetChannelTemplates(c echo.Context) error {
+	templates, err := h.Repo.GetChannelTemplates()
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	return extension.ServeJSONWithETag(c, formatChannelTemplates(templates))
+}
+
+// PostChannelTemplateRequest POST /channel-templates リクエストボディ
+type PostChannelTemplateRequest struct {
+	Name        string                     `json:"name"`
+	Description string                     `json:"description"`
+	Channels    model.ChannelTemplateNodes `json:"channels"`
+}
+
+func (r PostChannelTemplateRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Name, vd.Required, vd.RuneLength(1, 30)),
+		vd.Field(&r.Description, vd.RuneLength(0, 1000)),
+		vd.Field(&r.Channels, vd.NotNil),
+	)
+}
+
+// CreateChannelTemplate POST /channel-templates
+func (h *Handlers) CreateChannelTemplate(c echo.Context) error {
+	var req PostChannelTemplateRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	t, err := h.Repo.CreateChannelTemplate(req.Name, req.Description, req.Channels, getRequestUserID(c))
+	if err != nil {
+		switch err {
+		case repository.ErrAlreadyExists:
+			return herror.Conflict("this name has already been used")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	return c.JSON(http.StatusCreated, formatChannelTemplate(t))
+}
+
+// GetChannelTemplate GET /channel-templates/:templateID
+func (h *Handlers) GetChannelTemplate(c echo.Context) error {
+	return c.JSON(http.StatusOK, formatChannelTemplate(getParamChannelTemplate(c)))
+}
+
+// PatchChannelTemplateRequest PATCH /channel-templates/:templateID リクエストボディ
+type PatchChannelTemplateRequest struct {
+	Name        optional.String            `json:"name"`
+	Description optional.String            `json:"description"`
+	Channels    model.ChannelTemplateNodes `json:"channels"`
+}
+
+func (r PatchChannelTemplateRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Name, vd.RuneLength(1, 30)),
+		vd.Field(&r.Description, vd.RuneLength(0, 1000)),
+		vd.Field(&r.Channels),
+	)
+}
+
+// EditChannelTemplate PATCH /channel-templates/:templateID
+func (h *Handlers) EditChannelTemplate(c echo.Context) error {
+	t := getParamChannelTemplate(c)
+
+	var req PatchChannelTemplateRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	if err := h.Repo.UpdateChannelTemplate(t.ID, repository.UpdateChannelTemplateArgs{
+		Name:        req.Name,
+		Description: req.Description,
+		Channels:    req.Channels,
+	}); err != nil {
+		switch err {
+		case repository.ErrAlreadyExists:
+			return herror.Conflict("this name has already been used")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DeleteChannelTemplate DELETE /channel-templates/:templateID
+func (h *Handlers) DeleteChannelTemplate(c echo.Context) error {
+	t := getParamChannelTemplate(c)
+
+	if err := h.Repo.DeleteChannelTemplate(t.ID); err != nil {
+		return herror.InternalServerError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// PostApplyChannelTemplateRequest POST /channel-templates/:templateID/apply リクエストボディ
+type PostApplyChannelTemplateRequest struct {
+	Parent optional.UUID `json:"parent"`
+}
+
+// ApplyChannelTemplate POST /channel-templates/:templateID/apply
+func (h *Handlers) ApplyChannelTemplate(c echo.Context) error {
+	t := getParamChannelTemplate(c)
+
+	var req PostApplyChannelTemplateRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+	if len(t.Channels) == 0 {
+		return herror.BadRequest("the template has no channels")
+	}
+
+	chs, err := h.ChannelManager.CreatePublicChannelsFromTemplate(req.Parent.UUID, t.Channels, getRequestUserID(c))
+	if err != nil {
+		switch err {
+		case channel.ErrInvalidChannelName:
+			return herror.BadRequest("invalid channel name in the template")
+		case channel.ErrInvalidParentChannel:
+			return herror.BadRequest("invalid parent channel")
+		case channel.ErrChannelArchived:
+			return herror.BadRequest("the parent channel has been archived")
+		case channel.ErrTooDeepChannel:
+			return herror.BadRequest("channel depth limit exceeded")
+		case channel.ErrChannelNameConflicts:
+			return herror.Conflict("channel name conflicts")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+
+	ids := make([]uuid.UUID, len(chs))
+	for i, ch := range chs {
+		ids[i] = ch.ID
+	}
+	h.recordAuditLog(c, model.AuditActionChannelTemplateApply, model.AuditTargetChannelTemplate, t.ID.String(), nil, model.JSON{"parent": req.Parent.UUID.String(), "channels": ids})
+
+	tree := h.ChannelManager.PublicChannelTree()
+	res := make([]*Channel, len(chs))
+	for i, ch := range chs {
+		res[i] = formatChannel(ch, tree.GetChildrenIDs(ch.ID))
+	}
+	return c.JSON(http.StatusCreated, res)
+}
@@ -0,0 +1,210 @@
+package v3
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/router/session"
+	"github.com/traPtitech/traQ/utils/optional"
+	"github.com/traPtitech/traQ/utils/random"
+)
+
+func TestPostChannelTemplateRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		req     PostChannelTemplateRequest
+		wantErr bool
+	}{
+		{"empty name", PostChannelTemplateRequest{Name: "", Channels: model.ChannelTemplateNodes{}}, true},
+		{"nil channels", PostChannelTemplateRequest{Name: "a"}, true},
+		{"invalid channel name", PostChannelTemplateRequest{Name: "a", Channels: model.ChannelTemplateNodes{{Name: "あ"}}}, true},
+		{"duplicated channel name", PostChannelTemplateRequest{Name: "a", Channels: model.ChannelTemplateNodes{{Name: "x"}, {Name: "x"}}}, true},
+		{"success", PostChannelTemplateRequest{Name: "a", Channels: model.ChannelTemplateNodes{{Name: "x", Children: model.ChannelTemplateNodes{{Name: "x"}}}}}, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.req.Validate(); tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestHandlers_CreateChannelTemplate(t *testing.T) {
+	t.Parallel()
+	path := "/api/v3/channel-templates"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	admin := env.CreateAdmin(t, rand)
+	userSession := env.S(t, user.GetID())
+	adminSession := env.S(t, admin.GetID())
+
+	t.Run("forbidden", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path).
+			WithCookie(session.CookieName, userSession).
+			WithJSON(&PostChannelTemplateRequest{Name: random.AlphaNumeric(20), Channels: model.ChannelTemplateNodes{}}).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
+	t.Run("bad request", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path).
+			WithCookie(session.CookieName, adminSession).
+			WithJSON(&PostChannelTemplateRequest{Name: random.AlphaNumeric(20), Channels: model.ChannelTemplateNodes{{Name: "チャンネル"}}}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("success and conflict", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		name := random.AlphaNumeric(20)
+		req := &PostChannelTemplateRequest{Name: name, Channels: model.ChannelTemplateNodes{{Name: "general", Topic: "topic"}}}
+		obj := e.POST(path).
+			WithCookie(session.CookieName, adminSession).
+			WithJSON(req).
+			Expect().
+			Status(http.StatusCreated).
+			JSON().
+			Object()
+		obj.Value("name").String().Equal(name)
+		obj.Value("creatorId").String().Equal(admin.GetID().String())
+		obj.Value("channels").Array().First().Object().Value("topic").String().Equal("topic")
+
+		e.POST(path).
+			WithCookie(session.CookieName, adminSession).
+			WithJSON(req).
+			Expect().
+			Status(http.StatusConflict)
+	})
+}
+
+func TestHandlers_EditChannelTemplate(t *testing.T) {
+	t.Parallel()
+	path := "/api/v3/channel-templates/{templateId}"
+	env := Setup(t, common1)
+	admin := env.CreateAdmin(t, rand)
+	adminSession := env.S(t, admin.GetID())
+
+	ct, err := env.Repository.CreateChannelTemplate(random.AlphaNumeric(20), "", model.ChannelTemplateNodes{}, admin.GetID())
+	require.NoError(t, err)
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PATCH(path, uuid.Must(uuid.NewV4())).
+			WithCookie(session.CookieName, adminSession).
+			WithJSON(&PatchChannelTemplateRequest{Description: optional.StringFrom("a")}).
+			Expect().
+			Status(http.StatusNotFound)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PATCH(path, ct.ID).
+			WithCookie(session.CookieName, adminSession).
+			WithJSON(&PatchChannelTemplateRequest{Description: optional.StringFrom("updated"), Channels: model.ChannelTemplateNodes{{Name: "a"}}}).
+			Expect().
+			Status(http.StatusNoContent)
+
+		got, err := env.Repository.GetChannelTemplate(ct.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "updated", got.Description)
+		assert.Len(t, got.Channels, 1)
+	})
+}
+
+func TestHandlers_ApplyChannelTemplate(t *testing.T) {
+	t.Parallel()
+	path := "/api/v3/channel-templates/{templateId}/apply"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	admin := env.CreateAdmin(t, rand)
+	userSession := env.S(t, user.GetID())
+	adminSession := env.S(t, admin.GetID())
+
+	ct, err := env.Repository.CreateChannelTemplate(random.AlphaNumeric(20), "", model.ChannelTemplateNodes{
+		{Name: "general", Force: true, Subscribers: []uuid.UUID{user.GetID()}, Children: model.ChannelTemplateNodes{
+			{Name: "logistics"},
+			{Name: "design"},
+		}},
+	}, admin.GetID())
+	require.NoError(t, err)
+	empty, err := env.Repository.CreateChannelTemplate(random.AlphaNumeric(20), "", model.ChannelTemplateNodes{}, admin.GetID())
+	require.NoError(t, err)
+
+	t.Run("forbidden", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path, ct.ID).
+			WithCookie(session.CookieName, userSession).
+			WithJSON(&PostApplyChannelTemplateRequest{Parent: optional.UUIDFrom(env.CreateChannel(t, rand).ID)}).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
+	t.Run("bad request (empty template)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path, empty.ID).
+			WithCookie(session.CookieName, adminSession).
+			WithJSON(&PostApplyChannelTemplateRequest{Parent: optional.UUIDFrom(env.CreateChannel(t, rand).ID)}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("bad request (invalid parent)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path, ct.ID).
+			WithCookie(session.CookieName, adminSession).
+			WithJSON(&PostApplyChannelTemplateRequest{Parent: optional.UUIDFrom(uuid.Must(uuid.NewV4()))}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("success and conflict", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		parent := env.CreateChannel(t, rand)
+		arr := e.POST(path, ct.ID).
+			WithCookie(session.CookieName, adminSession).
+			WithJSON(&PostApplyChannelTemplateRequest{Parent: optional.UUIDFrom(parent.ID)}).
+			Expect().
+			Status(http.StatusCreated).
+			JSON().
+			Array()
+		arr.Length().Equal(3)
+		general := arr.First().Object()
+		general.Value("name").String().Equal("general")
+		general.Value("force").Boolean().True()
+		general.Value("parentId").String().Equal(parent.ID.String())
+		general.Value("children").Array().Length().Equal(2)
+
+		tree := env.CM.PublicChannelTree()
+		id := tree.GetChannelIDFromPath(tree.GetChannelPath(parent.ID) + "/general/design")
+		assert.NotEqual(t, uuid.Nil, id)
+
+		e.POST(path, ct.ID).
+			WithCookie(session.CookieName, adminSession).
+			WithJSON(&PostApplyChannelTemplateRequest{Parent: optional.UUIDFrom(parent.ID)}).
+			Expect().
+			Status(http.StatusConflict)
+	})
+}
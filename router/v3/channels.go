@@ -34,14 +34,22 @@ func (h *Handlers) GetChannels(c echo.Context) error {
 		}
 		res["dm"] = formatDMChannels(mapping)
 	}
+	if isTrue(c.QueryParam("include-private")) {
+		chs, err := h.ChannelManager.GetUserPrivateChannels(getRequestUserID(c))
+		if err != nil {
+			return herror.InternalServerError(err)
+		}
+		res["private"] = formatChannels(chs)
+	}
 
 	return extension.ServeJSONWithETag(c, res)
 }
 
 // PostChannelRequest POST /channels リクエストボディ
 type PostChannelRequest struct {
-	Name   string        `json:"name"`
-	Parent optional.UUID `json:"parent"`
+	Name    string        `json:"name"`
+	Parent  optional.UUID `json:"parent"`
+	Private bool          `json:"private"`
 }
 
 func (r PostChannelRequest) Validate() error {
@@ -59,7 +67,18 @@ func (h *Handlers) CreateChannels(c echo.Context) error {
 		return err
 	}
 
-	ch, err := h.ChannelManager.CreatePublicChannel(req.Name, req.Parent.UUID, userID)
+	var (
+		ch  *model.Channel
+		err error
+	)
+	if req.Private {
+		if req.Parent.UUID != uuid.Nil {
+			return herror.BadRequest("private channel cannot have a parent")
+		}
+		ch, err = h.ChannelManager.CreatePrivateChannel(req.Name, userID, nil)
+	} else {
+		ch, err = h.ChannelManager.CreatePublicChannel(req.Name, req.Parent.UUID, userID)
+	}
 	if err != nil {
 		switch err {
 		case channel.ErrChannelArchived:
@@ -151,6 +170,83 @@ func (h *Handlers) EditChannel(c echo.Context) error {
 			return herror.InternalServerError(err)
 		}
 	}
+
+	ch := getParamChannel(c)
+	before, after := model.JSON{}, model.JSON{}
+	if req.Name.Valid {
+		before["name"], after["name"] = ch.Name, req.Name.String
+	}
+	if req.Archived.Valid {
+		before["archived"], after["archived"] = ch.IsArchived(), req.Archived.Bool
+	}
+	if req.Force.Valid {
+		before["force"], after["force"] = ch.IsForced, req.Force.Bool
+	}
+	if req.Parent.Valid {
+		before["parent"], after["parent"] = ch.ParentID.String(), req.Parent.UUID.String()
+	}
+	h.recordAuditLog(c, model.AuditActionChannelEdit, model.AuditTargetChannel, channelID.String(), before, after)
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DeleteChannel DELETE /channels/:channelID
+func (h *Handlers) DeleteChannel(c echo.Context) error {
+	ch := getParamChannel(c)
+
+	if err := h.ChannelManager.DeleteChannel(ch.ID, getRequestUserID(c)); err != nil {
+		switch err {
+		case channel.ErrInvalidChannel:
+			return herror.BadRequest("only public channels can be deleted")
+		case channel.ErrChannelNotEmpty:
+			return herror.BadRequest("the channel has child channels, messages or webhooks")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+
+	h.recordAuditLog(c, model.AuditActionChannelDelete, model.AuditTargetChannel, ch.ID.String(), model.JSON{"name": ch.Name, "parent": ch.ParentID.String()}, model.JSON{})
+	return c.NoContent(http.StatusNoContent)
+}
+
+// PostChannelMergeRequest POST /channels/:channelID/merge リクエストボディ
+type PostChannelMergeRequest struct {
+	Target uuid.UUID `json:"target"`
+}
+
+func (r PostChannelMergeRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Target, vd.Required, validator.NotNilUUID),
+	)
+}
+
+// MergeChannel POST /channels/:channelID/merge
+func (h *Handlers) MergeChannel(c echo.Context) error {
+	ch := getParamChannel(c)
+
+	var req PostChannelMergeRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	if err := h.ChannelManager.MergeChannel(ch.ID, req.Target, getRequestUserID(c)); err != nil {
+		switch err {
+		case channel.ErrInvalidChannel:
+			return herror.BadRequest("invalid target channel")
+		case channel.ErrChannelArchived:
+			return herror.BadRequest("the target channel has been archived")
+		case channel.ErrInvalidParentChannel:
+			return herror.BadRequest("the target channel is a descendant of the channel")
+		case channel.ErrTooDeepChannel:
+			return herror.BadRequest("channel depth limit exceeded")
+		case channel.ErrChannelNameConflicts:
+			return herror.Conflict("child channel name conflicts")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+
+	h.recordAuditLog(c, model.AuditActionChannelMerge, model.AuditTargetChannel, ch.ID.String(), model.JSON{}, model.JSON{"target": req.Target.String()})
 	return c.NoContent(http.StatusNoContent)
 }
 
@@ -386,3 +482,56 @@ func (h *Handlers) GetUserDMChannel(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, &DMChannel{ID: ch.ID, UserID: userID})
 }
+
+// GetMyGroupDMChannels GET /users/me/group-dm-channels
+func (h *Handlers) GetMyGroupDMChannels(c echo.Context) error {
+	mapping, err := h.ChannelManager.GetGroupDMChannelMapping(getRequestUserID(c))
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, formatGroupDMChannels(mapping))
+}
+
+// PostGroupDMChannelRequest POST /users/me/group-dm-channels リクエストボディ
+type PostGroupDMChannelRequest struct {
+	UserIDs set.UUID `json:"userIds"`
+}
+
+func (r PostGroupDMChannelRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.UserIDs, vd.Required),
+	)
+}
+
+// PostMyGroupDMChannel POST /users/me/group-dm-channels
+//
+// 指定したユーザーと自分からなるグループDMチャンネルを取得します。存在しない場合は作成します。
+func (h *Handlers) PostMyGroupDMChannel(c echo.Context) error {
+	var req PostGroupDMChannelRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	for id := range req.UserIDs {
+		ok, err := h.Repo.UserExists(id)
+		if err != nil {
+			return herror.InternalServerError(err)
+		}
+		if !ok {
+			return herror.BadRequest("unknown user: " + id.String())
+		}
+	}
+
+	members := req.UserIDs.Clone()
+	members.Add(getRequestUserID(c))
+	ch, err := h.ChannelManager.GetGroupDMChannel(members)
+	if err != nil {
+		switch err {
+		case channel.ErrInvalidDMMembers:
+			return herror.BadRequest("invalid number of members")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	return c.JSON(http.StatusOK, formatGroupDMChannel(ch.ID, members.Array()))
+}
@@ -482,6 +482,146 @@ func TestHandlers_EditChannel(t *testing.T) {
 	})
 }
 
+func TestHandlers_DeleteChannel(t *testing.T) {
+	t.Parallel()
+	path := "/api/v3/channels/{channelId}"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	admin := env.CreateAdmin(t, rand)
+	userSession := env.S(t, user.GetID())
+	adminSession := env.S(t, admin.GetID())
+
+	channel := env.CreateChannel(t, rand)
+	withMessage := env.CreateChannel(t, rand)
+	env.CreateMessage(t, user.GetID(), withMessage.ID, rand)
+
+	t.Run("not logged in", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.DELETE(path, channel.ID).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.DELETE(path, channel.ID).
+			WithCookie(session.CookieName, userSession).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.DELETE(path, uuid.Must(uuid.NewV4()).String()).
+			WithCookie(session.CookieName, adminSession).
+			Expect().
+			Status(http.StatusNotFound)
+	})
+
+	t.Run("bad request (not empty)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.DELETE(path, withMessage.ID).
+			WithCookie(session.CookieName, adminSession).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.DELETE(path, channel.ID).
+			WithCookie(session.CookieName, adminSession).
+			Expect().
+			Status(http.StatusNoContent)
+
+		_, err := env.CM.GetChannel(channel.ID)
+		assert.Error(t, err)
+	})
+}
+
+func TestHandlers_MergeChannel(t *testing.T) {
+	t.Parallel()
+	path := "/api/v3/channels/{channelId}/merge"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	admin := env.CreateAdmin(t, rand)
+	userSession := env.S(t, user.GetID())
+	adminSession := env.S(t, admin.GetID())
+
+	src := env.CreateChannel(t, rand)
+	dst := env.CreateChannel(t, rand)
+	archived := env.CreateChannel(t, rand)
+	require.NoError(t, env.CM.ArchiveChannel(archived.ID, admin.GetID()))
+	m := env.CreateMessage(t, user.GetID(), src.ID, rand)
+
+	t.Run("not logged in", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path, src.ID).
+			WithJSON(&PostChannelMergeRequest{Target: dst.ID}).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path, src.ID).
+			WithCookie(session.CookieName, userSession).
+			WithJSON(&PostChannelMergeRequest{Target: dst.ID}).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
+	t.Run("bad request (nil target)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path, src.ID).
+			WithCookie(session.CookieName, adminSession).
+			WithJSON(&PostChannelMergeRequest{Target: uuid.Nil}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("bad request (same channel)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path, src.ID).
+			WithCookie(session.CookieName, adminSession).
+			WithJSON(&PostChannelMergeRequest{Target: src.ID}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("bad request (archived target)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path, src.ID).
+			WithCookie(session.CookieName, adminSession).
+			WithJSON(&PostChannelMergeRequest{Target: archived.ID}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path, src.ID).
+			WithCookie(session.CookieName, adminSession).
+			WithJSON(&PostChannelMergeRequest{Target: dst.ID}).
+			Expect().
+			Status(http.StatusNoContent)
+
+		moved, err := env.Repository.GetMessageByID(m.GetID())
+		require.NoError(t, err)
+		assert.EqualValues(t, dst.ID, moved.ChannelID)
+	})
+}
+
 func TestHandlers_GetChannelStats(t *testing.T) {
 	t.Parallel()
 	path := "/api/v3/channels/{channelId}/stats"
@@ -1121,3 +1261,81 @@ func TestHandlers_GetUserDMChannel(t *testing.T) {
 		obj.Value("userId").String().Equal(user3.GetID().String())
 	})
 }
+
+func TestHandlers_GroupDMChannels(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/users/me/group-dm-channels"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	user2 := env.CreateUser(t, rand)
+	user3 := env.CreateUser(t, rand)
+	user4 := env.CreateUser(t, rand)
+	commonSession := env.S(t, user.GetID())
+
+	t.Run("not logged in", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET(path).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("too few members", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path).
+			WithCookie(session.CookieName, commonSession).
+			WithJSON(&PostGroupDMChannelRequest{UserIDs: set.UUIDSetFromArray([]uuid.UUID{user2.GetID()})}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path).
+			WithCookie(session.CookieName, commonSession).
+			WithJSON(&PostGroupDMChannelRequest{UserIDs: set.UUIDSetFromArray([]uuid.UUID{user2.GetID(), uuid.Must(uuid.NewV4())})}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		obj := e.POST(path).
+			WithCookie(session.CookieName, commonSession).
+			WithJSON(&PostGroupDMChannelRequest{UserIDs: set.UUIDSetFromArray([]uuid.UUID{user3.GetID(), user4.GetID()})}).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Object()
+
+		obj.Value("members").Array().ContainsOnly(user.GetID().String(), user3.GetID().String(), user4.GetID().String())
+		id := obj.Value("id").String().Raw()
+
+		// 同じメンバー集合では同じチャンネルが返る
+		e.POST(path).
+			WithCookie(session.CookieName, env.S(t, user3.GetID())).
+			WithJSON(&PostGroupDMChannelRequest{UserIDs: set.UUIDSetFromArray([]uuid.UUID{user.GetID(), user4.GetID()})}).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Object().
+			Value("id").String().Equal(id)
+
+		arr := e.GET(path).
+			WithCookie(session.CookieName, env.S(t, user4.GetID())).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Array()
+		arr.Length().Equal(1)
+		arr.First().Object().Value("id").String().Equal(id)
+
+		ok, err := env.CM.IsChannelAccessibleToUser(user2.GetID(), uuid.FromStringOrNil(id))
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
@@ -125,6 +125,21 @@ func (h *Handlers) EditClient(c echo.Context) error {
 		return herror.InternalServerError(err)
 	}
 
+	before, after := model.JSON{}, model.JSON{}
+	if req.Name.Valid {
+		before["name"], after["name"] = oc.Name, req.Name.String
+	}
+	if req.Description.Valid {
+		before["description"], after["description"] = oc.Description, req.Description.String
+	}
+	if req.CallbackURL.Valid {
+		before["callbackUrl"], after["callbackUrl"] = oc.RedirectURI, req.CallbackURL.String
+	}
+	if req.DeveloperID.Valid {
+		before["developerId"], after["developerId"] = oc.CreatorID.String(), req.DeveloperID.UUID.String()
+	}
+	h.recordAuditLog(c, model.AuditActionOAuth2ClientEdit, model.AuditTargetOAuth2Client, oc.ID, before, after)
+
 	return c.NoContent(http.StatusNoContent)
 }
 
@@ -136,6 +151,7 @@ func (h *Handlers) DeleteClient(c echo.Context) error {
 	if err := h.Repo.DeleteClient(oc.ID); err != nil {
 		return herror.InternalServerError(err)
 	}
+	h.recordAuditLog(c, model.AuditActionOAuth2ClientDelete, model.AuditTargetOAuth2Client, oc.ID, model.JSON{"name": oc.Name, "developerId": oc.CreatorID.String()}, nil)
 
 	return c.NoContent(http.StatusNoContent)
 }
@@ -62,8 +62,15 @@ func (h *Handlers) GetFiles(c echo.Context) error {
 	if req.Mine {
 		q.UploaderID = optional.UUIDFrom(getRequestUserID(c))
 	}
+	if _, restricted := getRequestRestrictedChannels(c); restricted && req.ChannelID == uuid.Nil {
+		// チャンネル制限付きトークンでは制限外のチャンネルのファイルが含まれないよう、チャンネルの指定を必須とする
+		return herror.BadRequest("channelId is required for channel-restricted tokens")
+	}
 	if req.ChannelID != uuid.Nil {
 		// チャンネルアクセス権確認
+		if !isChannelAllowedForRequest(c, req.ChannelID) {
+			return herror.BadRequest("invalid channelId")
+		}
 		if ok, err := h.ChannelManager.IsChannelAccessibleToUser(getRequestUserID(c), req.ChannelID); err != nil {
 			return herror.InternalServerError(err)
 		} else if !ok {
@@ -105,6 +112,9 @@ func (h *Handlers) PostFile(c echo.Context) error {
 
 	// チャンネルアクセス権確認
 	channelID := uuid.FromStringOrNil(c.FormValue("channelId"))
+	if !isChannelAllowedForRequest(c, channelID) {
+		return herror.BadRequest("invalid channelId")
+	}
 	if ok, err := h.ChannelManager.IsChannelAccessibleToUser(userID, channelID); err != nil {
 		return herror.InternalServerError(err)
 	} else if !ok {
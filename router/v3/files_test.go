@@ -144,7 +144,10 @@ func TestHandlers_GetFiles(t *testing.T) {
 	f1 := env.CreateFile(t, user.GetID(), uuid.Nil)
 	f2 := env.CreateFile(t, uuid.Nil, ch.ID)
 	env.CreateFile(t, uuid.Nil, dm.ID)
+	ch2 := env.CreateChannel(t, rand)
 	s := env.S(t, user.GetID())
+	client := env.CreateOAuth2Client(t, rand, user.GetID())
+	token := env.IssueChannelRestrictedToken(t, client, user.GetID(), ch2.ID)
 
 	t.Run("not logged in", func(t *testing.T) {
 		t.Parallel()
@@ -173,6 +176,40 @@ func TestHandlers_GetFiles(t *testing.T) {
 			Status(http.StatusBadRequest)
 	})
 
+	t.Run("bad request (restricted token without channelId)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET(path).
+			WithHeader(echo.HeaderAuthorization, "Bearer "+token.AccessToken).
+			WithQuery("mine", true).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("bad request (restricted token with other channel)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET(path).
+			WithHeader(echo.HeaderAuthorization, "Bearer "+token.AccessToken).
+			WithQuery("channelId", ch.ID.String()).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("success (restricted token)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET(path).
+			WithHeader(echo.HeaderAuthorization, "Bearer "+token.AccessToken).
+			WithQuery("channelId", ch2.ID.String()).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Array().
+			Length().
+			Equal(0)
+	})
+
 	t.Run("success (mine)", func(t *testing.T) {
 		t.Parallel()
 		e := env.R(t)
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gofrs/uuid"
 	"github.com/labstack/echo/v4"
 
 	"github.com/traPtitech/traQ/model"
@@ -12,6 +13,8 @@ import (
 	"github.com/traPtitech/traQ/router/consts"
 	"github.com/traPtitech/traQ/router/extension/herror"
 	"github.com/traPtitech/traQ/service/message"
+	"github.com/traPtitech/traQ/service/rbac/permission"
+	"github.com/traPtitech/traQ/service/rbac/role"
 	"github.com/traPtitech/traQ/service/search"
 )
 
@@ -131,43 +134,14 @@ func (h *Handlers) DeleteMessage(c echo.Context) error {
 	m := getParamMessage(c)
 
 	if muid := m.GetUserID(); muid != userID {
-		mUser, err := h.Repo.GetUser(muid, false)
+		// チャンネルロールによる削除権限の確認
+		channelRoles, err := h.ChannelManager.GetUserChannelRoles(userID, m.GetChannelID())
 		if err != nil {
 			return herror.InternalServerError(err)
 		}
-
-		switch mUser.GetUserType() {
-		case model.UserTypeHuman:
-			return herror.Forbidden("you are not allowed to delete this message")
-		case model.UserTypeBot:
-			// BOTのメッセージの削除権限の確認
-			wh, err := h.Repo.GetBotByBotUserID(mUser.GetID())
-			if err != nil {
-				switch err {
-				case repository.ErrNotFound: // deleted bot
-					return herror.Forbidden("you are not allowed to delete this message")
-				default:
-					return herror.InternalServerError(err)
-				}
-			}
-
-			if wh.CreatorID != userID {
-				return herror.Forbidden("you are not allowed to delete this message")
-			}
-		case model.UserTypeWebhook:
-			// Webhookのメッセージの削除権限の確認
-			wh, err := h.Repo.GetWebhookByBotUserID(mUser.GetID())
-			if err != nil {
-				switch err {
-				case repository.ErrNotFound: // deleted webhook
-					return herror.Forbidden("you are not allowed to delete this message")
-				default:
-					return herror.InternalServerError(err)
-				}
-			}
-
-			if wh.GetCreatorID() != userID {
-				return herror.Forbidden("you are not allowed to delete this message")
+		if !role.IsChannelRoleGranted(channelRoles, permission.DeleteMessage) {
+			if err := h.checkOthersMessageDeletable(userID, muid); err != nil {
+				return err
 			}
 		}
 	}
@@ -183,6 +157,50 @@ func (h *Handlers) DeleteMessage(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// checkOthersMessageDeletable 他人(muid)が投稿したメッセージをユーザーが削除できるかどうかを確認します
+func (h *Handlers) checkOthersMessageDeletable(userID, muid uuid.UUID) error {
+	mUser, err := h.Repo.GetUser(muid, false)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+
+	switch mUser.GetUserType() {
+	case model.UserTypeHuman:
+		return herror.Forbidden("you are not allowed to delete this message")
+	case model.UserTypeBot:
+		// BOTのメッセージの削除権限の確認
+		wh, err := h.Repo.GetBotByBotUserID(mUser.GetID())
+		if err != nil {
+			switch err {
+			case repository.ErrNotFound: // deleted bot
+				return herror.Forbidden("you are not allowed to delete this message")
+			default:
+				return herror.InternalServerError(err)
+			}
+		}
+
+		if wh.CreatorID != userID {
+			return herror.Forbidden("you are not allowed to delete this message")
+		}
+	case model.UserTypeWebhook:
+		// Webhookのメッセージの削除権限の確認
+		wh, err := h.Repo.GetWebhookByBotUserID(mUser.GetID())
+		if err != nil {
+			switch err {
+			case repository.ErrNotFound: // deleted webhook
+				return herror.Forbidden("you are not allowed to delete this message")
+			default:
+				return herror.InternalServerError(err)
+			}
+		}
+
+		if wh.GetCreatorID() != userID {
+			return herror.Forbidden("you are not allowed to delete this message")
+		}
+	}
+	return nil
+}
+
 // GetPin GET /messages/:messageID/pin
 func (h *Handlers) GetPin(c echo.Context) error {
 	m := getParamMessage(c)
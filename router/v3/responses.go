@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/service/autoarchive"
+	"github.com/traPtitech/traQ/service/channel"
 	"github.com/traPtitech/traQ/utils/optional"
 
 	"github.com/gofrs/uuid"
@@ -24,7 +26,7 @@ func formatChannel(channel *model.Channel, childrenID []uuid.UUID) *Channel {
 	return &Channel{
 		ID:       channel.ID,
 		Name:     channel.Name,
-		ParentID: optional.NewUUID(channel.ParentID, channel.ParentID != uuid.Nil),
+		ParentID: optional.NewUUID(channel.ParentID, channel.ParentID != uuid.Nil && !channel.IsPrivateChannel()),
 		Topic:    channel.Topic,
 		Children: childrenID,
 		Archived: channel.IsArchived(),
@@ -32,6 +34,14 @@ func formatChannel(channel *model.Channel, childrenID []uuid.UUID) *Channel {
 	}
 }
 
+func formatChannels(channels []*model.Channel) []*Channel {
+	res := make([]*Channel, len(channels))
+	for i, ch := range channels {
+		res[i] = formatChannel(ch, ch.ChildrenID)
+	}
+	return res
+}
+
 type DMChannel struct {
 	ID     uuid.UUID `json:"id"`
 	UserID uuid.UUID `json:"userId"`
@@ -50,6 +60,33 @@ func formatDMChannels(dmcs map[uuid.UUID]uuid.UUID) []*DMChannel {
 	return res
 }
 
+type GroupDMChannel struct {
+	ID      uuid.UUID   `json:"id"`
+	Members []uuid.UUID `json:"members"`
+}
+
+func formatGroupDMChannel(cid uuid.UUID, members []uuid.UUID) *GroupDMChannel {
+	sorted := make([]uuid.UUID, len(members))
+	copy(sorted, members)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].String() < sorted[j].String()
+	})
+	return &GroupDMChannel{ID: cid, Members: sorted}
+}
+
+// formatGroupDMChannels ソートされたものを返す
+func formatGroupDMChannels(gdmcs map[uuid.UUID][]uuid.UUID) []*GroupDMChannel {
+	res := make([]*GroupDMChannel, 0, len(gdmcs))
+	for cid, members := range gdmcs {
+		res = append(res, formatGroupDMChannel(cid, members))
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].ID.String() < res[j].ID.String()
+	})
+	return res
+}
+
 type UserTag struct {
 	ID        uuid.UUID `json:"tagId"`
 	Tag       string    `json:"tag"`
@@ -142,28 +179,40 @@ func formatUserDetail(user model.UserInfo, uts []model.UserTag, g []uuid.UUID) *
 }
 
 type Webhook struct {
-	WebhookID   string    `json:"id"`
-	BotUserID   string    `json:"botUserId"`
-	DisplayName string    `json:"displayName"`
-	Description string    `json:"description"`
-	Secure      bool      `json:"secure"`
-	ChannelID   string    `json:"channelId"`
-	OwnerID     string    `json:"ownerId"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	WebhookID       string            `json:"id"`
+	BotUserID       string            `json:"botUserId"`
+	DisplayName     string            `json:"displayName"`
+	Description     string            `json:"description"`
+	Secure          bool              `json:"secure"`
+	ChannelID       string            `json:"channelId"`
+	OwnerID         string            `json:"ownerId"`
+	OutgoingURL     string            `json:"outgoingUrl"`
+	TriggerWords    []string          `json:"triggerWords"`
+	Templates       map[string]string `json:"templates"`
+	PayloadTemplate string            `json:"payloadTemplate"`
+	CreatedAt       time.Time         `json:"createdAt"`
+	UpdatedAt       time.Time         `json:"updatedAt"`
 }
 
 func formatWebhook(w model.Webhook) *Webhook {
+	tmpls := w.GetTemplates()
+	if tmpls == nil {
+		tmpls = model.WebhookTemplates{}
+	}
 	return &Webhook{
-		WebhookID:   w.GetID().String(),
-		BotUserID:   w.GetBotUserID().String(),
-		DisplayName: w.GetName(),
-		Description: w.GetDescription(),
-		Secure:      len(w.GetSecret()) > 0,
-		ChannelID:   w.GetChannelID().String(),
-		OwnerID:     w.GetCreatorID().String(),
-		CreatedAt:   w.GetCreatedAt(),
-		UpdatedAt:   w.GetUpdatedAt(),
+		WebhookID:       w.GetID().String(),
+		BotUserID:       w.GetBotUserID().String(),
+		DisplayName:     w.GetName(),
+		Description:     w.GetDescription(),
+		Secure:          len(w.GetSecret()) > 0,
+		ChannelID:       w.GetChannelID().String(),
+		OwnerID:         w.GetCreatorID().String(),
+		OutgoingURL:     w.GetOutgoingURL(),
+		TriggerWords:    w.GetTriggerWords(),
+		Templates:       tmpls,
+		PayloadTemplate: w.GetPayloadTemplate(),
+		CreatedAt:       w.GetCreatedAt(),
+		UpdatedAt:       w.GetUpdatedAt(),
 	}
 }
 
@@ -175,6 +224,58 @@ func formatWebhooks(ws []model.Webhook) []*Webhook {
 	return res
 }
 
+type webhookDeliveryLogResponse struct {
+	RequestID uuid.UUID `json:"requestId"`
+	WebhookID uuid.UUID `json:"webhookId"`
+	MessageID uuid.UUID `json:"messageId"`
+	Result    string    `json:"result"`
+	Code      int       `json:"code"`
+	DateTime  time.Time `json:"datetime"`
+}
+
+func formatWebhookDeliveryLog(log *model.WebhookDeliveryLog) *webhookDeliveryLogResponse {
+	return &webhookDeliveryLogResponse{
+		RequestID: log.RequestID,
+		WebhookID: log.WebhookID,
+		MessageID: log.MessageID,
+		Result:    log.Result,
+		Code:      log.Code,
+		DateTime:  log.DateTime,
+	}
+}
+
+func formatWebhookDeliveryLogs(logs []*model.WebhookDeliveryLog) []*webhookDeliveryLogResponse {
+	res := make([]*webhookDeliveryLogResponse, len(logs))
+	for i, log := range logs {
+		res[i] = formatWebhookDeliveryLog(log)
+	}
+	return res
+}
+
+type webAuthnCredential struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func formatWebAuthnCredential(cred *model.WebAuthnCredential) *webAuthnCredential {
+	return &webAuthnCredential{
+		ID:        cred.ID,
+		Name:      cred.Name,
+		CreatedAt: cred.CreatedAt,
+		UpdatedAt: cred.UpdatedAt,
+	}
+}
+
+func formatWebAuthnCredentials(creds []*model.WebAuthnCredential) []*webAuthnCredential {
+	res := make([]*webAuthnCredential, len(creds))
+	for i, cred := range creds {
+		res[i] = formatWebAuthnCredential(cred)
+	}
+	return res
+}
+
 type Bot struct {
 	ID              uuid.UUID           `json:"id"`
 	BotUserID       uuid.UUID           `json:"botUserId"`
@@ -593,13 +694,14 @@ func formatClipFolderMessages(cfms []*model.ClipFolderMessage) []*ClipFolderMess
 }
 
 type StampPalette struct {
-	ID          uuid.UUID   `json:"id"`
-	Name        string      `json:"name"`
-	Description string      `json:"description"`
-	Stamps      model.UUIDs `json:"stamps"`
-	CreatorID   uuid.UUID   `json:"creatorId"`
-	CreatedAt   time.Time   `json:"createdAt"`
-	UpdatedAt   time.Time   `json:"updatedAt"`
+	ID          uuid.UUID                    `json:"id"`
+	Name        string                       `json:"name"`
+	Description string                       `json:"description"`
+	Stamps      model.UUIDs                  `json:"stamps"`
+	CreatorID   uuid.UUID                    `json:"creatorId"`
+	Visibility  model.StampPaletteVisibility `json:"visibility"`
+	CreatedAt   time.Time                    `json:"createdAt"`
+	UpdatedAt   time.Time                    `json:"updatedAt"`
 }
 
 func formatStampPalette(cf *model.StampPalette) *StampPalette {
@@ -609,6 +711,7 @@ func formatStampPalette(cf *model.StampPalette) *StampPalette {
 		Description: cf.Description,
 		Stamps:      cf.Stamps,
 		CreatorID:   cf.CreatorID,
+		Visibility:  cf.Visibility,
 		CreatedAt:   cf.CreatedAt,
 		UpdatedAt:   cf.UpdatedAt,
 	}
@@ -623,3 +726,207 @@ func formatStampPalettes(cfs []*model.StampPalette) []*StampPalette {
 	sort.Slice(res, func(i, j int) bool { return res[i].ID.String() < res[j].ID.String() })
 	return res
 }
+
+type StampRankingEntry struct {
+	StampID    uuid.UUID     `json:"stampId"`
+	Score      float64       `json:"score"`
+	Count      int64         `json:"count"`
+	LastUsedAt optional.Time `json:"lastUsedAt"`
+}
+
+type ChannelTemplate struct {
+	ID          uuid.UUID                  `json:"id"`
+	Name        string                     `json:"name"`
+	Description string                     `json:"description"`
+	Channels    model.ChannelTemplateNodes `json:"channels"`
+	CreatorID   uuid.UUID                  `json:"creatorId"`
+	CreatedAt   time.Time                  `json:"createdAt"`
+	UpdatedAt   time.Time                  `json:"updatedAt"`
+}
+
+func formatChannelTemplate(t *model.ChannelTemplate) *ChannelTemplate {
+	return &ChannelTemplate{
+		ID:          t.ID,
+		Name:        t.Name,
+		Description: t.Description,
+		Channels:    t.Channels,
+		CreatorID:   t.CreatorID,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	}
+}
+
+func formatChannelTemplates(ts []*model.ChannelTemplate) []*ChannelTemplate {
+	res := make([]*ChannelTemplate, len(ts))
+	for i, t := range ts {
+		res[i] = formatChannelTemplate(t)
+	}
+	return res
+}
+
+type userRole struct {
+	Name         string   `json:"name"`
+	OAuth2Scope  bool     `json:"oauth2Scope"`
+	System       bool     `json:"system"`
+	Permissions  []string `json:"permissions"`
+	Inheritances []string `json:"inheritances"`
+}
+
+func formatUserRole(r *model.UserRole) *userRole {
+	res := &userRole{
+		Name:         r.Name,
+		OAuth2Scope:  r.Oauth2Scope,
+		System:       r.System,
+		Permissions:  make([]string, len(r.Permissions)),
+		Inheritances: make([]string, len(r.Inheritances)),
+	}
+	for i, p := range r.Permissions {
+		res.Permissions[i] = p.Permission
+	}
+	for i, sub := range r.Inheritances {
+		res.Inheritances[i] = sub.Name
+	}
+	sort.Strings(res.Permissions)
+	sort.Strings(res.Inheritances)
+	return res
+}
+
+func formatUserRoles(roles []*model.UserRole) []*userRole {
+	res := make([]*userRole, len(roles))
+	for i, r := range roles {
+		res[i] = formatUserRole(r)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+type channelRole struct {
+	UserID    uuid.UUID `json:"userId"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func formatChannelRoles(roles []*model.ChannelRole) []*channelRole {
+	res := make([]*channelRole, len(roles))
+	for i, r := range roles {
+		res[i] = &channelRole{
+			UserID:    r.UserID,
+			Role:      r.Role,
+			CreatedAt: r.CreatedAt,
+		}
+	}
+	return res
+}
+
+type ChannelArchival struct {
+	Exempt   bool          `json:"exempt"`
+	WarnedAt optional.Time `json:"warnedAt"`
+}
+
+func formatChannelArchival(s *model.ChannelArchivalState) *ChannelArchival {
+	return &ChannelArchival{
+		Exempt:   s.Exempt,
+		WarnedAt: s.WarnedAt,
+	}
+}
+
+type ChannelArchivalCandidate struct {
+	ChannelID    uuid.UUID          `json:"channelId"`
+	Path         string             `json:"path"`
+	Action       autoarchive.Action `json:"action"`
+	LastActivity time.Time          `json:"lastActivity"`
+	WarnedAt     optional.Time      `json:"warnedAt"`
+	ArchiveAt    optional.Time      `json:"archiveAt"`
+}
+
+func formatChannelArchivalCandidates(cs []*autoarchive.Candidate, tree channel.Tree) []*ChannelArchivalCandidate {
+	res := make([]*ChannelArchivalCandidate, len(cs))
+	for i, c := range cs {
+		res[i] = &ChannelArchivalCandidate{
+			ChannelID:    c.ChannelID,
+			Path:         tree.GetChannelPath(c.ChannelID),
+			Action:       c.Action,
+			LastActivity: c.LastActivity,
+			WarnedAt:     c.WarnedAt,
+			ArchiveAt:    c.ArchiveAt,
+		}
+	}
+	return res
+}
+
+type ChannelRetentionPolicy struct {
+	ChannelID     uuid.UUID `json:"channelId"`
+	RetentionDays int       `json:"retentionDays"`
+	Purge         bool      `json:"purge"`
+	KeepPinned    bool      `json:"keepPinned"`
+	UpdaterID     uuid.UUID `json:"updaterId"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+type ChannelRetention struct {
+	Policy    *ChannelRetentionPolicy `json:"policy"`
+	Effective *ChannelRetentionPolicy `json:"effective"`
+}
+
+func formatChannelRetentionPolicy(p *model.ChannelRetentionPolicy) *ChannelRetentionPolicy {
+	if p == nil {
+		return nil
+	}
+	return &ChannelRetentionPolicy{
+		ChannelID:     p.ChannelID,
+		RetentionDays: p.RetentionDays,
+		Purge:         p.Purge,
+		KeepPinned:    p.KeepPinned,
+		UpdaterID:     p.UpdaterID,
+		UpdatedAt:     p.UpdatedAt,
+	}
+}
+
+func formatChannelRetentionPolicies(ps []*model.ChannelRetentionPolicy) []*ChannelRetentionPolicy {
+	res := make([]*ChannelRetentionPolicy, len(ps))
+	for i, p := range ps {
+		res[i] = formatChannelRetentionPolicy(p)
+	}
+	return res
+}
+
+type SidebarItem struct {
+	ID        uuid.UUID             `json:"id"`
+	Type      model.SidebarItemType `json:"type"`
+	ChannelID optional.UUID         `json:"channelId"`
+	URL       string                `json:"url"`
+	Title     string                `json:"title"`
+}
+
+type SidebarSection struct {
+	ID        uuid.UUID      `json:"id"`
+	Name      string         `json:"name"`
+	Collapsed bool           `json:"collapsed"`
+	Items     []*SidebarItem `json:"items"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+}
+
+// formatSidebarSections 並び順を保持したものを返す
+func formatSidebarSections(ss []*model.SidebarSection) []*SidebarSection {
+	res := make([]*SidebarSection, len(ss))
+	for i, s := range ss {
+		items := make([]*SidebarItem, len(s.Items))
+		for j, item := range s.Items {
+			items[j] = &SidebarItem{
+				ID:        item.ID,
+				Type:      item.Type,
+				ChannelID: item.ChannelID,
+				URL:       item.URL,
+				Title:     item.Title,
+			}
+		}
+		res[i] = &SidebarSection{
+			ID:        s.ID,
+			Name:      s.Name,
+			Collapsed: s.Collapsed,
+			Items:     items,
+			UpdatedAt: s.UpdatedAt,
+		}
+	}
+	return res
+}
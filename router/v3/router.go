@@ -9,6 +9,7 @@ import (
 	"github.com/traPtitech/traQ/router/extension"
 	"github.com/traPtitech/traQ/router/middlewares"
 	"github.com/traPtitech/traQ/router/session"
+	"github.com/traPtitech/traQ/service/autoarchive"
 	botWS "github.com/traPtitech/traQ/service/bot/ws"
 	"github.com/traPtitech/traQ/service/channel"
 	"github.com/traPtitech/traQ/service/counter"
@@ -16,8 +17,10 @@ import (
 	"github.com/traPtitech/traQ/service/imaging"
 	"github.com/traPtitech/traQ/service/message"
 	"github.com/traPtitech/traQ/service/ogp"
+	"github.com/traPtitech/traQ/service/ratelimit"
 	"github.com/traPtitech/traQ/service/rbac"
 	"github.com/traPtitech/traQ/service/rbac/permission"
+	"github.com/traPtitech/traQ/service/retention"
 	"github.com/traPtitech/traQ/service/search"
 	"github.com/traPtitech/traQ/service/viewer"
 	"github.com/traPtitech/traQ/service/webrtcv3"
@@ -43,6 +46,9 @@ type Handlers struct {
 	MessageManager message.Manager
 	FileManager    file.Manager
 	Replacer       *mutil.Replacer
+	RateLimitStore ratelimit.Store
+	AutoArchive    autoarchive.Service
+	Retention      retention.Service
 	Config
 }
 
@@ -58,17 +64,22 @@ type Config struct {
 
 	// EnabledExternalAccountLink リンク可能な外部認証アカウントのプロバイダ
 	EnabledExternalAccountProviders map[string]bool
+
+	// Origin サーバーオリジン (WebAuthnのRelying Party識別子に使用)
+	Origin string
 }
 
 // Setup APIルーティングを行います
 func (h *Handlers) Setup(e *echo.Group) {
 	// middleware preparation
 	requires := middlewares.AccessControlMiddlewareGenerator(h.RBAC)
+	requiresInChannel := middlewares.ChannelAccessControlMiddlewareGenerator(h.RBAC, h.ChannelManager)
 	bodyLimit := middlewares.RequestBodyLengthLimit
 	retrieve := middlewares.NewParamRetriever(h.Repo, h.ChannelManager, h.FileManager, h.MessageManager)
 	blockBot := middlewares.BlockBot()
 	blockNonBot := middlewares.BlockNonBot()
 	nologin := middlewares.NoLogin(h.SessStore, h.Repo)
+	rateLimit := middlewares.RateLimitMiddlewareGenerator(h.RateLimitStore)
 
 	requiresBotAccessPerm := middlewares.CheckBotAccessPerm(h.RBAC)
 	requiresWebhookAccessPerm := middlewares.CheckWebhookAccessPerm(h.RBAC)
@@ -94,10 +105,11 @@ func (h *Handlers) Setup(e *echo.Group) {
 				apiUsersUID.GET("/dm-channel", h.GetUserDMChannel, requires(permission.GetChannel))
 				apiUsersUID.GET("/messages", h.GetDirectMessages, requires(permission.GetMessage))
 				apiUsersUID.GET("/stats", h.GetUserStats, requires(permission.GetUser))
-				apiUsersUID.POST("/messages", h.PostDirectMessage, bodyLimit(100), requires(permission.PostMessage))
+				apiUsersUID.POST("/messages", h.PostDirectMessage, rateLimit(ratelimit.PostMessage), bodyLimit(100), requires(permission.PostMessage))
 				apiUsersUID.GET("/icon", h.GetUserIcon, requires(permission.DownloadFile))
 				apiUsersUID.PUT("/icon", h.ChangeUserIcon, requires(permission.EditOtherUsers))
 				apiUsersUID.PUT("/password", h.ChangeUserPassword, requires(permission.EditOtherUsers))
+				apiUsersUID.DELETE("/two-factor", h.ResetUserTwoFactorAuth, requires(permission.EditOtherUsers))
 				apiUsersUIDTags := apiUsersUID.Group("/tags")
 				{
 					apiUsersUIDTags.GET("", h.GetUserTags, requires(permission.GetUserTag))
@@ -114,12 +126,15 @@ func (h *Handlers) Setup(e *echo.Group) {
 				apiUsersMe.GET("", h.GetMe, requires(permission.GetMe))
 				apiUsersMe.PATCH("", h.EditMe, requires(permission.EditMe))
 				apiUsersMe.GET("/stamp-history", h.GetMyStampHistory, requires(permission.GetMyStampHistory))
+				apiUsersMe.GET("/stamp-ranking", h.GetMyStampRanking, requires(permission.GetMyStampHistory, permission.GetStamp))
 				apiUsersMe.GET("/qr-code", h.GetMyQRCode, requires(permission.GetUserQRCode), blockBot)
 				apiUsersMe.GET("/icon", h.GetMyIcon, requires(permission.DownloadFile))
 				apiUsersMe.PUT("/icon", h.ChangeMyIcon, requires(permission.ChangeMyIcon))
 				apiUsersMe.PUT("/password", h.PutMyPassword, requires(permission.ChangeMyPassword), blockBot)
 				apiUsersMe.POST("/fcm-device", h.PostMyFCMDevice, requires(permission.RegisterFCMDevice), blockBot)
 				apiUsersMe.GET("/view-states", h.GetMyViewStates, requires(permission.ConnectNotificationStream), blockBot)
+				apiUsersMe.GET("/group-dm-channels", h.GetMyGroupDMChannels, requires(permission.GetChannel))
+				apiUsersMe.POST("/group-dm-channels", h.PostMyGroupDMChannel, requires(permission.GetChannel))
 				apiUsersMeTags := apiUsersMe.Group("/tags")
 				{
 					apiUsersMeTags.GET("", h.GetMyUserTags, requires(permission.GetUserTag))
@@ -130,12 +145,24 @@ func (h *Handlers) Setup(e *echo.Group) {
 						apiUsersMeTagsTID.DELETE("", h.RemoveMyUserTag, requires(permission.EditUserTag))
 					}
 				}
+				apiUsersMeStampPaletteSubscriptions := apiUsersMe.Group("/stamp-palette-subscriptions", blockBot)
+				{
+					apiUsersMeStampPaletteSubscriptions.GET("", h.GetMyStampPaletteSubscriptions, requires(permission.GetStampPalette))
+					apiUsersMeStampPaletteSubscriptions.POST("", h.PostStampPaletteSubscription, requires(permission.EditStampPalette))
+					apiUsersMeStampPaletteSubscriptions.DELETE("/:paletteID", h.RemoveMyStampPaletteSubscription, requires(permission.EditStampPalette))
+				}
 				apiUsersMeStars := apiUsersMe.Group("/stars", blockBot)
 				{
 					apiUsersMeStars.GET("", h.GetMyStars, requires(permission.GetChannelStar))
 					apiUsersMeStars.POST("", h.PostStar, requires(permission.EditChannelStar))
 					apiUsersMeStars.DELETE("/:channelID", h.RemoveMyStar, requires(permission.EditChannelStar))
 				}
+				apiUsersMeSidebar := apiUsersMe.Group("/sidebar", blockBot)
+				{
+					apiUsersMeSidebar.GET("", h.GetMySidebar, requires(permission.GetChannelStar))
+					apiUsersMeSidebar.PUT("", h.PutMySidebar, requires(permission.EditChannelStar))
+					apiUsersMeSidebar.PATCH("/sections/:sectionID", h.EditMySidebarSection, requires(permission.EditChannelStar))
+				}
 				apiUsersMeUnread := apiUsersMe.Group("/unread", blockBot)
 				{
 					apiUsersMeUnread.GET("", h.GetMyUnreadChannels, requires(permission.GetUnread))
@@ -154,6 +181,7 @@ func (h *Handlers) Setup(e *echo.Group) {
 				apiUsersMeTokens := apiUsersMe.Group("/tokens", blockBot)
 				{
 					apiUsersMeTokens.GET("", h.GetMyTokens, requires(permission.GetMyTokens))
+					apiUsersMeTokens.POST("", h.CreateMyToken, requires(permission.CreateMyToken))
 					apiUsersMeTokens.DELETE("/:tokenID", h.RevokeMyToken, requires(permission.RevokeMyToken))
 				}
 				apiUsersMeExAccounts := apiUsersMe.Group("/ex-accounts", blockBot)
@@ -162,6 +190,17 @@ func (h *Handlers) Setup(e *echo.Group) {
 					apiUsersMeExAccounts.POST("/link", h.LinkExternalAccount, requires(permission.EditMyExternalAccount))
 					apiUsersMeExAccounts.POST("/unlink", h.UnlinkExternalAccount, requires(permission.EditMyExternalAccount))
 				}
+				apiUsersMeTwoFactor := apiUsersMe.Group("/two-factor", blockBot)
+				{
+					apiUsersMeTwoFactor.GET("", h.GetMyTwoFactorAuth, requires(permission.GetMyTwoFactorAuth))
+					apiUsersMeTwoFactor.POST("/totp", h.SetupMyTOTP, requires(permission.EditMyTwoFactorAuth))
+					apiUsersMeTwoFactor.POST("/totp/verify", h.VerifyMyTOTP, requires(permission.EditMyTwoFactorAuth))
+					apiUsersMeTwoFactor.DELETE("/totp", h.DisableMyTOTP, requires(permission.EditMyTwoFactorAuth))
+					apiUsersMeTwoFactor.POST("/recovery-codes", h.RegenerateMyRecoveryCodes, requires(permission.EditMyTwoFactorAuth))
+					apiUsersMeTwoFactor.POST("/webauthn/register/begin", h.BeginMyWebAuthnRegistration, requires(permission.EditMyTwoFactorAuth))
+					apiUsersMeTwoFactor.POST("/webauthn/register/finish", h.FinishMyWebAuthnRegistration, requires(permission.EditMyTwoFactorAuth))
+					apiUsersMeTwoFactor.DELETE("/webauthn/:credentialID", h.DeleteMyWebAuthnCredential, requires(permission.EditMyTwoFactorAuth))
+				}
 				apiUsersMeSettings := apiUsersMe.Group("/settings", blockBot)
 				{
 					apiUsersMeSettings.GET("", h.GetMySettings, requires(permission.GetMe))
@@ -174,22 +213,40 @@ func (h *Handlers) Setup(e *echo.Group) {
 		{
 			apiChannels.GET("", h.GetChannels, requires(permission.GetChannel))
 			apiChannels.POST("", h.CreateChannels, requires(permission.CreateChannel))
+			apiChannels.GET("/archival-report", h.GetChannelArchivalReport, requires(permission.ManageChannelArchival))
+			apiChannels.GET("/retention-policies", h.GetChannelRetentionPolicies, requires(permission.ManageMessageRetention))
 			apiChannelsCID := apiChannels.Group("/:channelID", retrieve.ChannelID(), requiresChannelAccessPerm)
 			{
 				apiChannelsCID.GET("", h.GetChannel, requires(permission.GetChannel))
 				apiChannelsCID.PATCH("", h.EditChannel, requires(permission.EditChannel))
+				apiChannelsCID.DELETE("", h.DeleteChannel, requires(permission.DeleteChannel))
+				apiChannelsCID.POST("/merge", h.MergeChannel, requires(permission.DeleteChannel))
 				apiChannelsCID.GET("/messages", h.GetMessages, requires(permission.GetMessage))
-				apiChannelsCID.POST("/messages", h.PostMessage, bodyLimit(100), requires(permission.PostMessage))
+				apiChannelsCID.POST("/messages", h.PostMessage, rateLimit(ratelimit.PostMessage), bodyLimit(100), requires(permission.PostMessage))
 				apiChannelsCID.GET("/stats", h.GetChannelStats, requires(permission.GetChannel))
 				apiChannelsCID.GET("/topic", h.GetChannelTopic, requires(permission.GetChannel))
-				apiChannelsCID.PUT("/topic", h.EditChannelTopic, requires(permission.EditChannelTopic))
+				apiChannelsCID.PUT("/topic", h.EditChannelTopic, requiresInChannel(permission.EditChannelTopic))
+				apiChannelsCID.GET("/archival", h.GetChannelArchival, requires(permission.GetChannel))
+				apiChannelsCID.PUT("/archival", h.EditChannelArchival, requiresInChannel(permission.ManageChannelArchival))
+				apiChannelsCID.GET("/retention", h.GetChannelRetention, requires(permission.GetChannel))
+				apiChannelsCID.PUT("/retention", h.EditChannelRetention, requires(permission.ManageMessageRetention))
+				apiChannelsCID.DELETE("/retention", h.DeleteChannelRetention, requires(permission.ManageMessageRetention))
 				apiChannelsCID.GET("/viewers", h.GetChannelViewers, requires(permission.GetChannel))
 				apiChannelsCID.GET("/pins", h.GetChannelPins, requires(permission.GetMessage))
 				apiChannelsCID.GET("/subscribers", h.GetChannelSubscribers, requires(permission.GetChannelSubscription))
-				apiChannelsCID.PUT("/subscribers", h.SetChannelSubscribers, requires(permission.EditChannelSubscription))
-				apiChannelsCID.PATCH("/subscribers", h.EditChannelSubscribers, requires(permission.EditChannelSubscription))
+				apiChannelsCID.PUT("/subscribers", h.SetChannelSubscribers, requiresInChannel(permission.EditChannelSubscription))
+				apiChannelsCID.PATCH("/subscribers", h.EditChannelSubscribers, requiresInChannel(permission.EditChannelSubscription))
 				apiChannelsCID.GET("/bots", h.GetChannelBots, requires(permission.GetChannel))
 				apiChannelsCID.GET("/events", h.GetChannelEvents, requires(permission.GetChannel))
+				apiChannelsCID.GET("/roles", h.GetChannelRoles, requires(permission.GetChannel))
+				apiChannelsCIDRolesUID := apiChannelsCID.Group("/roles/:userID", retrieve.UserID(false))
+				{
+					apiChannelsCIDRolesUID.PUT("", h.SetChannelRole, requires(permission.ManageChannelRole))
+					apiChannelsCIDRolesUID.DELETE("", h.DeleteChannelRole, requires(permission.ManageChannelRole))
+				}
+				apiChannelsCID.GET("/members", h.GetChannelMembers, requires(permission.GetChannel))
+				apiChannelsCID.POST("/members", h.AddChannelMembers, requires(permission.EditPrivateChannelMember))
+				apiChannelsCID.DELETE("/members/:userID", h.RemoveChannelMember, requires(permission.EditPrivateChannelMember), retrieve.UserID(false))
 			}
 		}
 		apiMessages := api.Group("/messages")
@@ -198,18 +255,18 @@ func (h *Handlers) Setup(e *echo.Group) {
 			apiMessagesMID := apiMessages.Group("/:messageID", retrieve.MessageID(), requiresMessageAccessPerm)
 			{
 				apiMessagesMID.GET("", h.GetMessage, requires(permission.GetMessage))
-				apiMessagesMID.PUT("", h.EditMessage, bodyLimit(100), requires(permission.EditMessage))
-				apiMessagesMID.DELETE("", h.DeleteMessage, requires(permission.DeleteMessage))
+				apiMessagesMID.PUT("", h.EditMessage, rateLimit(ratelimit.PostMessage), bodyLimit(100), requires(permission.EditMessage))
+				apiMessagesMID.DELETE("", h.DeleteMessage, requiresInChannel(permission.DeleteMessage))
 				apiMessagesMID.GET("/pin", h.GetPin, requires(permission.GetMessage))
-				apiMessagesMID.POST("/pin", h.CreatePin, requires(permission.CreateMessagePin))
-				apiMessagesMID.DELETE("/pin", h.RemovePin, requires(permission.DeleteMessagePin))
+				apiMessagesMID.POST("/pin", h.CreatePin, requiresInChannel(permission.CreateMessagePin))
+				apiMessagesMID.DELETE("/pin", h.RemovePin, requiresInChannel(permission.DeleteMessagePin))
 				apiMessagesMID.GET("/clips", h.GetMessageClips, requires(permission.GetClipFolder))
 				apiMessagesMIDStamps := apiMessagesMID.Group("/stamps")
 				{
 					apiMessagesMIDStamps.GET("", h.GetMessageStamps, requires(permission.GetMessage))
 					apiMessagesMIDStampsSID := apiMessagesMIDStamps.Group("/:stampID", retrieve.StampID(true))
 					{
-						apiMessagesMIDStampsSID.POST("", h.AddMessageStamp, requires(permission.AddMessageStamp))
+						apiMessagesMIDStampsSID.POST("", h.AddMessageStamp, rateLimit(ratelimit.AddMessageStamp), requires(permission.AddMessageStamp))
 						apiMessagesMIDStampsSID.DELETE("", h.RemoveMessageStamp, requires(permission.RemoveMessageStamp))
 					}
 				}
@@ -248,15 +305,36 @@ func (h *Handlers) Setup(e *echo.Group) {
 				apiStampsSID.PUT("/image", h.ChangeStampImage, requires(permission.EditStamp))
 			}
 		}
-		apiStampPalettes := api.Group("/stamp-palettes", blockBot)
+		apiStampPacks := api.Group("/stamp-packs")
 		{
-			apiStampPalettes.GET("", h.GetStampPalettes, requires(permission.GetStampPalette))
-			apiStampPalettes.POST("", h.CreateStampPalette, requires(permission.CreateStampPalette))
+			apiStampPacks.POST("/import", h.ImportStampPack, bodyLimit(stampPackMaxFileSize>>10), requires(permission.ImportStampPack))
+			apiStampPacks.POST("/export", h.ExportStampPack, requires(permission.GetStamp, permission.DownloadFile))
+		}
+		apiChannelTemplates := api.Group("/channel-templates", blockBot)
+		{
+			apiChannelTemplates.GET("", h.GetChannelTemplates, requires(permission.GetChannelTemplate))
+			apiChannelTemplates.POST("", h.CreateChannelTemplate, requires(permission.ManageChannelTemplate))
+			apiChannelTemplatesTID := apiChannelTemplates.Group("/:templateID", retrieve.ChannelTemplateID())
+			{
+				apiChannelTemplatesTID.GET("", h.GetChannelTemplate, requires(permission.GetChannelTemplate))
+				apiChannelTemplatesTID.PATCH("", h.EditChannelTemplate, requires(permission.ManageChannelTemplate))
+				apiChannelTemplatesTID.DELETE("", h.DeleteChannelTemplate, requires(permission.ManageChannelTemplate))
+				apiChannelTemplatesTID.POST("/apply", h.ApplyChannelTemplate, requires(permission.ManageChannelTemplate))
+			}
+		}
+		apiStampPalettes := api.Group("/stamp-palettes")
+		{
+			// 公開パレットの閲覧はBOTにも許可
+			apiStampPalettes.GET("", h.GetStampPalettes, requires(permission.GetStampPalette), blockBot)
+			apiStampPalettes.POST("", h.CreateStampPalette, requires(permission.CreateStampPalette), blockBot)
+			apiStampPalettes.GET("/public", h.GetPublicStampPalettes, requires(permission.GetStampPalette))
 			apiStampPalettesPID := apiStampPalettes.Group("/:paletteID", retrieve.StampPalettesID())
 			{
 				apiStampPalettesPID.GET("", h.GetStampPalette, requires(permission.GetStampPalette))
-				apiStampPalettesPID.PATCH("", h.EditStampPalette, requires(permission.EditStampPalette))
-				apiStampPalettesPID.DELETE("", h.DeleteStampPalette, requires(permission.DeleteStampPalette))
+				apiStampPalettesPID.PATCH("", h.EditStampPalette, requires(permission.EditStampPalette), blockBot)
+				apiStampPalettesPID.DELETE("", h.DeleteStampPalette, requires(permission.DeleteStampPalette), blockBot)
+				apiStampPalettesPID.GET("/export", h.ExportStampPalette, requires(permission.GetStampPalette, permission.DownloadFile))
+				apiStampPalettesPID.POST("/copy", h.CopyStampPalette, requires(permission.CreateStampPalette), blockBot)
 			}
 		}
 		apiWebhooks := api.Group("/webhooks", blockBot)
@@ -271,6 +349,8 @@ func (h *Handlers) Setup(e *echo.Group) {
 				apiWebhooksWID.GET("/icon", h.GetWebhookIcon, requires(permission.GetWebhook))
 				apiWebhooksWID.PUT("/icon", h.ChangeWebhookIcon, requires(permission.EditWebhook))
 				apiWebhooksWID.GET("/messages", h.GetWebhookMessages, requires(permission.GetWebhook))
+				apiWebhooksWID.GET("/logs", h.GetWebhookLogs, requires(permission.GetWebhook))
+				apiWebhooksWID.POST("/dry-run", h.PostWebhookDryRun, requires(permission.EditWebhook))
 			}
 		}
 		apiGroups := api.Group("/groups")
@@ -364,6 +444,23 @@ func (h *Handlers) Setup(e *echo.Group) {
 				}
 			}
 		}
+		apiTwoFactorPolicy := api.Group("/two-factor-policy", blockBot)
+		{
+			apiTwoFactorPolicy.GET("", h.GetTwoFactorPolicy, requires(permission.ManageTwoFactorPolicy))
+			apiTwoFactorPolicy.PUT("", h.PutTwoFactorPolicy, requires(permission.ManageTwoFactorPolicy))
+		}
+		apiRoles := api.Group("/roles", blockBot)
+		{
+			apiRoles.GET("", h.GetUserRoles, requires(permission.ManageUserRole))
+			apiRoles.POST("", h.CreateUserRole, requires(permission.ManageUserRole))
+			apiRolesRName := apiRoles.Group("/:roleName")
+			{
+				apiRolesRName.GET("", h.GetUserRole, requires(permission.ManageUserRole))
+				apiRolesRName.PATCH("", h.EditUserRole, requires(permission.ManageUserRole))
+				apiRolesRName.DELETE("", h.DeleteUserRole, requires(permission.ManageUserRole))
+			}
+		}
+		api.GET("/audit-logs", h.GetAuditLogs, requires(permission.GetAuditLog), blockBot)
 		api.GET("/ws", echo.WrapHandler(h.WS), requires(permission.ConnectNotificationStream), blockBot)
 		api.GET("/ogp", h.GetOgp, blockBot)
 	}
@@ -372,11 +469,18 @@ func (h *Handlers) Setup(e *echo.Group) {
 	{
 		apiNoAuth.GET("/version", h.GetVersion)
 		if h.Config.AllowSignUp {
-			apiNoAuth.POST("/users", h.CreateUser, nologin)
+			apiNoAuth.POST("/users", h.CreateUser, rateLimit(ratelimit.Login), nologin)
+		}
+		apiNoAuth.POST("/login", h.Login, rateLimit(ratelimit.Login), nologin)
+		apiNoAuthLoginTwoFactor := apiNoAuth.Group("/login/two-factor", rateLimit(ratelimit.Login), nologin)
+		{
+			apiNoAuthLoginTwoFactor.POST("/totp", h.LoginWithTOTP)
+			apiNoAuthLoginTwoFactor.POST("/recovery", h.LoginWithRecoveryCode)
+			apiNoAuthLoginTwoFactor.POST("/webauthn/begin", h.BeginWebAuthnLogin)
+			apiNoAuthLoginTwoFactor.POST("/webauthn/finish", h.FinishWebAuthnLogin)
 		}
-		apiNoAuth.POST("/login", h.Login, nologin)
 		apiNoAuth.POST("/logout", h.Logout)
-		apiNoAuth.POST("/webhooks/:webhookID", h.PostWebhook, retrieve.WebhookID())
+		apiNoAuth.POST("/webhooks/:webhookID", h.PostWebhook, rateLimit(ratelimit.PostWebhook), retrieve.WebhookID())
 		apiNoAuthPublic := apiNoAuth.Group("/public")
 		{
 			apiNoAuthPublic.GET("/icon/:username", h.GetPublicUserIcon)
@@ -25,16 +25,19 @@ import (
 	gorm2 "github.com/traPtitech/traQ/repository/gorm"
 	"github.com/traPtitech/traQ/router/extension"
 	"github.com/traPtitech/traQ/router/session"
+	"github.com/traPtitech/traQ/service/autoarchive"
 	"github.com/traPtitech/traQ/service/channel"
 	"github.com/traPtitech/traQ/service/file"
 	"github.com/traPtitech/traQ/service/imaging"
 	"github.com/traPtitech/traQ/service/message"
 	"github.com/traPtitech/traQ/service/rbac"
 	"github.com/traPtitech/traQ/service/rbac/role"
+	"github.com/traPtitech/traQ/service/retention"
 	"github.com/traPtitech/traQ/service/search"
 	"github.com/traPtitech/traQ/utils/gormzap"
 	"github.com/traPtitech/traQ/utils/optional"
 	"github.com/traPtitech/traQ/utils/random"
+	"github.com/traPtitech/traQ/utils/set"
 	"github.com/traPtitech/traQ/utils/storage"
 )
 
@@ -104,7 +107,7 @@ func TestMain(m *testing.M) {
 		env.Repository = repo
 
 		env.CM, _ = channel.InitChannelManager(repo, l.Named("CM"))
-		env.MM, _ = message.NewMessageManager(repo, env.CM, l.Named("MM"))
+		env.MM, _ = message.NewMessageManager(repo, env.CM, env.Hub, l.Named("MM"))
 		env.IP = imaging.NewProcessor(imaging.Config{
 			MaxPixels:        1000 * 1000,
 			Concurrency:      1,
@@ -112,6 +115,13 @@ func TestMain(m *testing.M) {
 			ImageMagickPath:  "",
 		})
 		env.FM, _ = file.InitFileManager(repo, storage.NewInMemoryFileStorage(), env.IP, l.Named("FM"))
+		env.AA = autoarchive.NewService(repo, env.CM, env.MM, l.Named("AA"), autoarchive.Config{
+			Enabled:        false,
+			InactivePeriod: 0,
+			GracePeriod:    time.Hour * 24,
+			NotifierName:   "traq",
+		})
+		env.RS = retention.NewService(repo, env.CM, l.Named("RS"))
 
 		// テスト用サーバー作成
 		e := echo.New()
@@ -134,6 +144,8 @@ func TestMain(m *testing.M) {
 			FileManager:    env.FM,
 			Logger:         l,
 			Imaging:        env.IP,
+			AutoArchive:    env.AA,
+			Retention:      env.RS,
 			Config: Config{
 				Version:         "version",
 				Revision:        "revision",
@@ -171,6 +183,8 @@ type Env struct {
 	MM         message.Manager
 	FM         file.Manager
 	IP         imaging.Processor
+	AA         autoarchive.Service
+	RS         retention.Service
 	SE         search.Engine
 	Hub        *hub.Hub
 	SessStore  session.Store
@@ -295,6 +309,17 @@ func (env *Env) CreateDMChannel(t *testing.T, user1, user2 uuid.UUID) *model.Cha
 	return dm
 }
 
+// CreatePrivateChannel プライベートチャンネルを必ず作成します
+func (env *Env) CreatePrivateChannel(t *testing.T, name string, creatorID uuid.UUID, members ...uuid.UUID) *model.Channel {
+	t.Helper()
+	if name == rand {
+		name = random.AlphaNumeric(20)
+	}
+	ch, err := env.CM.CreatePrivateChannel(name, creatorID, set.UUIDSetFromArray(members))
+	require.NoError(t, err)
+	return ch
+}
+
 // CreateMessage メッセージを必ず作成します
 func (env *Env) CreateMessage(t *testing.T, userID, channelID uuid.UUID, text string) message.Message {
 	t.Helper()
@@ -339,6 +364,15 @@ func (env *Env) CreateStampPalette(t *testing.T, creator uuid.UUID, name string,
 	return sp
 }
 
+// CreatePublicStampPalette 公開スタンプパレットを必ず作成します
+func (env *Env) CreatePublicStampPalette(t *testing.T, creator uuid.UUID, name string, stamps model.UUIDs) *model.StampPalette {
+	t.Helper()
+	sp := env.CreateStampPalette(t, creator, name, stamps)
+	require.NoError(t, env.Repository.UpdateStampPalette(sp.ID, repository.UpdateStampPaletteArgs{Visibility: optional.StringFrom(string(model.StampPaletteVisibilityPublic))}))
+	sp.Visibility = model.StampPaletteVisibilityPublic
+	return sp
+}
+
 // AddStampToMessage メッセージにスタンプを必ず押します
 func (env *Env) AddStampToMessage(t *testing.T, messageID, stampID, userID uuid.UUID) {
 	t.Helper()
@@ -436,6 +470,18 @@ func (env *Env) IssueToken(t *testing.T, client *model.OAuth2Client, userID uuid
 	return tok
 }
 
+// IssueChannelRestrictedToken 指定したチャンネルに制限されたOAuth2トークンを必ず発行します
+func (env *Env) IssueChannelRestrictedToken(t *testing.T, client *model.OAuth2Client, userID uuid.UUID, channelIDs ...uuid.UUID) *model.OAuth2Token {
+	t.Helper()
+	scopes := model.AccessScopes{"read": {}}
+	for _, id := range channelIDs {
+		scopes.Add(model.ChannelRestrictionScope(id))
+	}
+	tok, err := env.Repository.IssueToken(client, userID, "https://example.com", scopes, 86400, false)
+	require.NoError(t, err)
+	return tok
+}
+
 // CreateClipFolder クリップフォルダを必ず作成します
 func (env *Env) CreateClipFolder(t *testing.T, name, desc string, creatorID uuid.UUID) *model.ClipFolder {
 	t.Helper()
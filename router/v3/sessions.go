@@ -1,6 +1,7 @@
 package v3
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
@@ -13,6 +14,9 @@ import (
 	"github.com/traPtitech/traQ/repository"
 	"github.com/traPtitech/traQ/router/consts"
 	"github.com/traPtitech/traQ/router/extension/herror"
+	"github.com/traPtitech/traQ/router/session"
+	"github.com/traPtitech/traQ/utils/optional"
+	"github.com/traPtitech/traQ/utils/random"
 	"github.com/traPtitech/traQ/utils/validator"
 )
 
@@ -60,11 +64,32 @@ func (h *Handlers) Login(c echo.Context) error {
 		h.L(c).Info("an api login attempt failed: wrong password", zap.String("username", req.Name))
 		return echo.NewHTTPError(http.StatusUnauthorized, err)
 	}
+
+	// 二要素認証
+	methods, err := h.getTwoFactorMethods(user.GetID())
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	if len(methods) > 0 {
+		h.L(c).Info("an api login attempt requires two-factor authentication", zap.String("username", req.Name))
+		return h.startTwoFactorLogin(c, user, methods)
+	}
+	required, err := h.isTwoFactorRequired(user)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
 	h.L(c).Info("an api login attempt succeeded", zap.String("username", req.Name))
 
-	if _, err := h.SessStore.RenewSession(c, user.GetID()); err != nil {
+	sess, err := h.SessStore.RenewSession(c, user.GetID())
+	if err != nil {
 		return herror.InternalServerError(err)
 	}
+	if required {
+		// 二要素認証の登録が完了するまで、登録用API以外は利用できない
+		if err := sess.Set(session.KeyTwoFactorEnrollmentRequired, true); err != nil {
+			return herror.InternalServerError(err)
+		}
+	}
 
 	if redirect := c.QueryParam("redirect"); len(redirect) > 0 {
 		return c.Redirect(http.StatusFound, redirect)
@@ -138,27 +163,109 @@ func (h *Handlers) GetMyTokens(c echo.Context) error {
 	if err != nil {
 		return herror.InternalServerError(err)
 	}
+	pats, err := h.Repo.GetPersonalAccessTokensByUser(userID)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
 
 	type response struct {
-		ID       uuid.UUID          `json:"id"`
-		ClientID string             `json:"clientId"`
-		Scopes   model.AccessScopes `json:"scopes"`
-		IssuedAt time.Time          `json:"issuedAt"`
+		ID         uuid.UUID          `json:"id"`
+		Type       string             `json:"type"`
+		ClientID   string             `json:"clientId"`
+		Name       string             `json:"name,omitempty"`
+		Scopes     model.AccessScopes `json:"scopes"`
+		IssuedAt   time.Time          `json:"issuedAt"`
+		ExpiresAt  *time.Time         `json:"expiresAt,omitempty"`
+		LastUsedAt *time.Time         `json:"lastUsedAt,omitempty"`
+		LastUsedIP string             `json:"lastUsedIp,omitempty"`
 	}
 
-	res := make([]response, len(ot))
-	for i, v := range ot {
-		res[i] = response{
+	res := make([]response, 0, len(ot)+len(pats))
+	for _, v := range ot {
+		res = append(res, response{
 			ID:       v.ID,
+			Type:     tokenTypeOAuth2,
 			ClientID: v.ClientID,
 			Scopes:   v.Scopes,
 			IssuedAt: v.CreatedAt,
+		})
+	}
+	for _, v := range pats {
+		r := response{
+			ID:         v.ID,
+			Type:       tokenTypePersonal,
+			Name:       v.Name,
+			Scopes:     v.Scopes,
+			IssuedAt:   v.CreatedAt,
+			LastUsedIP: v.LastUsedIP,
+		}
+		if v.ExpiresAt.Valid {
+			r.ExpiresAt = &v.ExpiresAt.Time
+		}
+		if v.LastUsedAt.Valid {
+			r.LastUsedAt = &v.LastUsedAt.Time
 		}
+		res = append(res, r)
 	}
 
 	return c.JSON(http.StatusOK, res)
 }
 
+const (
+	tokenTypeOAuth2   = "oauth2"
+	tokenTypePersonal = "personal"
+)
+
+// PostMyTokenRequest POST /users/me/tokens リクエストボディ
+type PostMyTokenRequest struct {
+	Name      string             `json:"name"`
+	Scopes    model.AccessScopes `json:"scopes"`
+	ExpiresAt optional.Time      `json:"expiresAt"`
+}
+
+func (r PostMyTokenRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Name, vd.Required, vd.RuneLength(1, 32)),
+		vd.Field(&r.Scopes, vd.Required),
+		vd.Field(&r.ExpiresAt, vd.By(func(value interface{}) error {
+			if t := value.(optional.Time); t.Valid && !t.Time.After(time.Now()) {
+				return errors.New("must be a future time")
+			}
+			return nil
+		})),
+	)
+}
+
+// CreateMyToken POST /users/me/tokens
+func (h *Handlers) CreateMyToken(c echo.Context) error {
+	var req PostMyTokenRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	token := model.PersonalAccessTokenPrefix + random.SecureAlphaNumeric(40)
+	pat, err := h.Repo.CreatePersonalAccessToken(repository.CreatePersonalAccessTokenArgs{
+		UserID:    getRequestUserID(c),
+		Name:      req.Name,
+		TokenHash: model.HashPersonalAccessToken(token),
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+
+	return c.JSON(http.StatusCreated, echo.Map{
+		"id":        pat.ID,
+		"type":      tokenTypePersonal,
+		"name":      pat.Name,
+		"scopes":    pat.Scopes,
+		"issuedAt":  pat.CreatedAt,
+		"expiresAt": pat.ExpiresAt,
+		"token":     token,
+	})
+}
+
 // RevokeMyToken DELETE /users/me/tokens/:tokenID
 func (h *Handlers) RevokeMyToken(c echo.Context) error {
 	tokenID := getParamAsUUID(c, consts.ParamTokenID)
@@ -168,7 +275,16 @@ func (h *Handlers) RevokeMyToken(c echo.Context) error {
 	if err != nil {
 		switch err {
 		case repository.ErrNotFound:
-			return herror.NotFound()
+			// パーソナルアクセストークン
+			if err := h.Repo.DeletePersonalAccessToken(userID, tokenID); err != nil {
+				switch err {
+				case repository.ErrNotFound:
+					return herror.NotFound()
+				default:
+					return herror.InternalServerError(err)
+				}
+			}
+			return c.NoContent(http.StatusNoContent)
 		default:
 			return herror.InternalServerError(err)
 		}
@@ -378,6 +378,71 @@ func TestHandlers_GetMyTokens(t *testing.T) {
 	})
 }
 
+func TestHandlers_CreateMyToken(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/users/me/tokens"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	s := env.S(t, user.GetID())
+
+	t.Run("not logged in", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path).
+			WithJSON(&PostMyTokenRequest{Name: "test", Scopes: model.AccessScopes{"users:read": {}}}).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("bad request (invalid scope)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path).
+			WithCookie(session.CookieName, s).
+			WithJSON(&PostMyTokenRequest{Name: "test", Scopes: model.AccessScopes{"invalid": {}}}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		obj := e.POST(path).
+			WithCookie(session.CookieName, s).
+			WithJSON(&PostMyTokenRequest{Name: "test", Scopes: model.AccessScopes{"users:read": {}}}).
+			Expect().
+			Status(http.StatusCreated).
+			JSON().
+			Object()
+
+		obj.Value("type").String().Equal("personal")
+		obj.Value("name").String().Equal("test")
+		token := obj.Value("token").String().Raw()
+		assert.True(t, model.IsPersonalAccessToken(token))
+
+		e.GET("/api/v3/users/me").
+			WithHeader(echo.HeaderAuthorization, "Bearer "+token).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Object().
+			Value("id").String().Equal(user.GetID().String())
+
+		// トークンでトークンは発行できない
+		e.POST(path).
+			WithHeader(echo.HeaderAuthorization, "Bearer "+token).
+			WithJSON(&PostMyTokenRequest{Name: "test2", Scopes: model.AccessScopes{"users:read": {}}}).
+			Expect().
+			Status(http.StatusForbidden)
+
+		pat, err := env.Repository.GetPersonalAccessTokenByHash(model.HashPersonalAccessToken(token))
+		require.NoError(t, err)
+		assert.True(t, pat.LastUsedAt.Valid)
+		assert.NotEmpty(t, pat.LastUsedIP)
+	})
+}
+
 func TestHandlers_RevokeMyToken(t *testing.T) {
 	t.Parallel()
 
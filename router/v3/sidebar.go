@@ -0,0 +1,171 @@
+package v3
+
+import (
+	"net/http"
+
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/router/consts"
+	"github.com/traPtitech/traQ/router/extension"
+	"github.com/traPtitech/traQ/router/extension/herror"
+	"github.com/traPtitech/traQ/service/channel"
+	"github.com/traPtitech/traQ/utils/optional"
+	"github.com/traPtitech/traQ/utils/validator"
+)
+
+// GetMySidebar GET /users/me/sidebar
+func (h *Handlers) GetMySidebar(c echo.Context) error {
+	sections, err := h.Repo.GetSidebarSections(getRequestUserID(c))
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	return extension.ServeJSONWithETag(c, formatSidebarSections(sections))
+}
+
+// PutSidebarItem PUT /users/me/sidebar リクエストボディの項目
+type PutSidebarItem struct {
+	Type      model.SidebarItemType `json:"type"`
+	ChannelID uuid.UUID             `json:"channelId"`
+	URL       string                `json:"url"`
+	Title     string                `json:"title"`
+}
+
+func (r PutSidebarItem) Validate() error {
+	isLink := r.Type == model.SidebarItemTypeLink
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Type, vd.Required, vd.In(model.SidebarItemTypeChannel, model.SidebarItemTypeDM, model.SidebarItemTypeLink)),
+		vd.Field(&r.ChannelID, vd.When(!isLink, vd.Required, validator.NotNilUUID)),
+		vd.Field(&r.URL, vd.When(isLink, vd.Required, vd.RuneLength(1, 512), is.URL)),
+		vd.Field(&r.Title, vd.When(isLink, vd.Required, vd.RuneLength(1, 64))),
+	)
+}
+
+// PutSidebarSection PUT /users/me/sidebar リクエストボディのセクション
+type PutSidebarSection struct {
+	Name      string           `json:"name"`
+	Collapsed bool             `json:"collapsed"`
+	Items     []PutSidebarItem `json:"items"`
+}
+
+func (r PutSidebarSection) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Name, vd.Required, vd.RuneLength(1, 32)),
+		vd.Field(&r.Items, vd.Length(0, model.MaxSidebarItems)),
+	)
+}
+
+// PutMySidebarRequest PUT /users/me/sidebar リクエストボディ
+type PutMySidebarRequest struct {
+	Sections []PutSidebarSection `json:"sections"`
+}
+
+func (r PutMySidebarRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Sections, vd.NotNil, vd.Length(0, model.MaxSidebarSections)),
+	)
+}
+
+// PutMySidebar PUT /users/me/sidebar
+func (h *Handlers) PutMySidebar(c echo.Context) error {
+	userID := getRequestUserID(c)
+
+	var req PutMySidebarRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	args := make([]*repository.SidebarSectionArgs, len(req.Sections))
+	for i, s := range req.Sections {
+		items := make([]*repository.SidebarItemArgs, len(s.Items))
+		for j, item := range s.Items {
+			if item.Type.IsChannel() {
+				// アクセスできるチャンネルで、種類が一致しているか
+				if err := h.checkSidebarChannel(userID, item.Type, item.ChannelID); err != nil {
+					return err
+				}
+				items[j] = &repository.SidebarItemArgs{Type: item.Type, ChannelID: item.ChannelID}
+			} else {
+				items[j] = &repository.SidebarItemArgs{Type: item.Type, URL: item.URL, Title: item.Title}
+			}
+		}
+		args[i] = &repository.SidebarSectionArgs{
+			Name:      s.Name,
+			Collapsed: s.Collapsed,
+			Items:     items,
+		}
+	}
+
+	sections, err := h.Repo.ReplaceSidebarSections(userID, args)
+	if err != nil {
+		switch {
+		case repository.IsArgError(err):
+			return herror.BadRequest(err)
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	return c.JSON(http.StatusOK, formatSidebarSections(sections))
+}
+
+func (h *Handlers) checkSidebarChannel(userID uuid.UUID, t model.SidebarItemType, channelID uuid.UUID) error {
+	ch, err := h.ChannelManager.GetChannel(channelID)
+	if err != nil {
+		switch err {
+		case channel.ErrChannelNotFound:
+			return herror.BadRequest("invalid channelId")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	isDM := ch.IsDMChannel() || ch.IsGroupDMChannel()
+	if isDM != (t == model.SidebarItemTypeDM) {
+		return herror.BadRequest("item type does not match the channel")
+	}
+	if ok, err := h.ChannelManager.IsChannelAccessibleToUser(userID, channelID); err != nil {
+		return herror.InternalServerError(err)
+	} else if !ok {
+		return herror.BadRequest("invalid channelId")
+	}
+	return nil
+}
+
+// PatchMySidebarSectionRequest PATCH /users/me/sidebar/sections/:sectionID リクエストボディ
+type PatchMySidebarSectionRequest struct {
+	Name      optional.String `json:"name"`
+	Collapsed optional.Bool   `json:"collapsed"`
+}
+
+func (r PatchMySidebarSectionRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Name, validator.RequiredIfValid, vd.RuneLength(1, 32)),
+	)
+}
+
+// EditMySidebarSection PATCH /users/me/sidebar/sections/:sectionID
+func (h *Handlers) EditMySidebarSection(c echo.Context) error {
+	sectionID := getParamAsUUID(c, consts.ParamSectionID)
+
+	var req PatchMySidebarSectionRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	args := repository.UpdateSidebarSectionArgs{
+		Name:      req.Name,
+		Collapsed: req.Collapsed,
+	}
+	if err := h.Repo.UpdateSidebarSection(getRequestUserID(c), sectionID, args); err != nil {
+		switch err {
+		case repository.ErrNotFound, repository.ErrNilID:
+			return herror.NotFound()
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	return c.NoContent(http.StatusNoContent)
+}
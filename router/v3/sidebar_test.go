@@ -0,0 +1,244 @@
+package v3
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/router/session"
+	"github.com/traPtitech/traQ/utils/optional"
+)
+
+func TestHandlers_GetMySidebar(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/users/me/sidebar"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	ch := env.CreateChannel(t, rand)
+	_, err := env.Repository.ReplaceSidebarSections(user.GetID(), []*repository.SidebarSectionArgs{
+		{Name: "first", Items: []*repository.SidebarItemArgs{{Type: model.SidebarItemTypeChannel, ChannelID: ch.ID}}},
+		{Name: "second", Collapsed: true},
+	})
+	require.NoError(t, err)
+	s := env.S(t, user.GetID())
+
+	t.Run("not logged in", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET(path).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		arr := e.GET(path).
+			WithCookie(session.CookieName, s).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Array()
+		arr.Length().Equal(2)
+
+		first := arr.Element(0).Object()
+		first.Value("name").String().Equal("first")
+		first.Value("collapsed").Boolean().False()
+		item := first.Value("items").Array().Element(0).Object()
+		item.Value("type").String().Equal("channel")
+		item.Value("channelId").String().Equal(ch.ID.String())
+
+		second := arr.Element(1).Object()
+		second.Value("name").String().Equal("second")
+		second.Value("collapsed").Boolean().True()
+		second.Value("items").Array().Length().Equal(0)
+	})
+}
+
+func TestHandlers_PutMySidebar(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/users/me/sidebar"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	user2 := env.CreateUser(t, rand)
+	user3 := env.CreateUser(t, rand)
+	ch := env.CreateChannel(t, rand)
+	dm := env.CreateDMChannel(t, user.GetID(), user2.GetID())
+	othersDM := env.CreateDMChannel(t, user2.GetID(), user3.GetID())
+	s := env.S(t, user.GetID())
+
+	t.Run("not logged in", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PUT(path).
+			WithJSON(&PutMySidebarRequest{Sections: []PutSidebarSection{}}).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("bad request (invalid link)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PUT(path).
+			WithCookie(session.CookieName, s).
+			WithJSON(&PutMySidebarRequest{Sections: []PutSidebarSection{
+				{Name: "links", Items: []PutSidebarItem{{Type: model.SidebarItemTypeLink, URL: "not a url", Title: "x"}}},
+			}}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("bad request (null section)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PUT(path).
+			WithCookie(session.CookieName, s).
+			WithJSON(map[string]interface{}{"sections": []interface{}{nil}}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("bad request (null item)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PUT(path).
+			WithCookie(session.CookieName, s).
+			WithJSON(map[string]interface{}{"sections": []interface{}{map[string]interface{}{"name": "a", "items": []interface{}{nil}}}}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("bad request (type mismatch)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PUT(path).
+			WithCookie(session.CookieName, s).
+			WithJSON(&PutMySidebarRequest{Sections: []PutSidebarSection{
+				{Name: "dm", Items: []PutSidebarItem{{Type: model.SidebarItemTypeChannel, ChannelID: dm.ID}}},
+			}}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("bad request (inaccessible channel)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PUT(path).
+			WithCookie(session.CookieName, s).
+			WithJSON(&PutMySidebarRequest{Sections: []PutSidebarSection{
+				{Name: "dm", Items: []PutSidebarItem{{Type: model.SidebarItemTypeDM, ChannelID: othersDM.ID}}},
+			}}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("bad request (unknown channel)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PUT(path).
+			WithCookie(session.CookieName, s).
+			WithJSON(&PutMySidebarRequest{Sections: []PutSidebarSection{
+				{Name: "ch", Items: []PutSidebarItem{{Type: model.SidebarItemTypeChannel, ChannelID: uuid.Must(uuid.NewV4())}}},
+			}}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		arr := e.PUT(path).
+			WithCookie(session.CookieName, s).
+			WithJSON(&PutMySidebarRequest{Sections: []PutSidebarSection{
+				{Name: "work", Items: []PutSidebarItem{
+					{Type: model.SidebarItemTypeChannel, ChannelID: ch.ID},
+					{Type: model.SidebarItemTypeDM, ChannelID: dm.ID},
+					{Type: model.SidebarItemTypeLink, URL: "https://example.com", Title: "example"},
+				}},
+				{Name: "empty", Collapsed: true},
+			}}).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Array()
+		arr.Length().Equal(2)
+		items := arr.Element(0).Object().Value("items").Array()
+		items.Length().Equal(3)
+		items.Element(1).Object().Value("type").String().Equal("dm")
+		items.Element(2).Object().Value("url").String().Equal("https://example.com")
+
+		sections, err := env.Repository.GetSidebarSections(user.GetID())
+		require.NoError(t, err)
+		if assert.Len(t, sections, 2) {
+			assert.Equal(t, "work", sections[0].Name)
+			assert.True(t, sections[1].Collapsed)
+		}
+	})
+}
+
+func TestHandlers_EditMySidebarSection(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/users/me/sidebar/sections/{sectionID}"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	user2 := env.CreateUser(t, rand)
+	sections, err := env.Repository.ReplaceSidebarSections(user.GetID(), []*repository.SidebarSectionArgs{{Name: "section"}})
+	require.NoError(t, err)
+	section := sections[0]
+	s := env.S(t, user.GetID())
+	s2 := env.S(t, user2.GetID())
+
+	t.Run("not logged in", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PATCH(path, section.ID).
+			WithJSON(&PatchMySidebarSectionRequest{Collapsed: optional.BoolFrom(true)}).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("bad request", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PATCH(path, section.ID).
+			WithCookie(session.CookieName, s).
+			WithJSON(&PatchMySidebarSectionRequest{Name: optional.StringFrom("")}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("not found (others)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PATCH(path, section.ID).
+			WithCookie(session.CookieName, s2).
+			WithJSON(&PatchMySidebarSectionRequest{Collapsed: optional.BoolFrom(true)}).
+			Expect().
+			Status(http.StatusNotFound)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PATCH(path, section.ID).
+			WithCookie(session.CookieName, s).
+			WithJSON(&PatchMySidebarSectionRequest{Collapsed: optional.BoolFrom(true)}).
+			Expect().
+			Status(http.StatusNoContent)
+
+		sections, err := env.Repository.GetSidebarSections(user.GetID())
+		require.NoError(t, err)
+		if assert.Len(t, sections, 1) {
+			assert.True(t, sections[0].Collapsed)
+			assert.Equal(t, "section", sections[0].Name)
+		}
+	})
+}
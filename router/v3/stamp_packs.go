@@ -0,0 +1,137 @@
+package v3
+
+import (
+	"archive/zip"
+	"errors"
+	"net/http"
+
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/router/consts"
+	"github.com/traPtitech/traQ/router/extension/herror"
+	"github.com/traPtitech/traQ/utils/stamppack"
+	"github.com/traPtitech/traQ/utils/validator"
+)
+
+// stampPackMaxFileSize インポートできるスタンプパックの最大サイズ (POST /files と同じ)
+//
+// インポートはリクエスト中に同期的に行われるため、リクエストボディの大きさもこれで制限されます。
+const stampPackMaxFileSize = 30 << 20 // 30MB
+
+// ImportStampPack POST /stamp-packs/import
+func (h *Handlers) ImportStampPack(c echo.Context) error {
+	src, fh, err := c.Request().FormFile("file")
+	if err != nil {
+		return herror.BadRequest(err)
+	}
+	defer src.Close()
+	if fh.Size > stampPackMaxFileSize {
+		return herror.BadRequest("too large file")
+	}
+
+	zr, err := zip.NewReader(src, fh.Size)
+	if err != nil {
+		return herror.BadRequest("invalid zip file")
+	}
+
+	results, err := stamppack.Import(h.Repo, h.FileManager, h.Imaging, zr, stamppack.ImportOptions{
+		CreatorID: getRequestUserID(c),
+		DryRun:    isTrue(c.FormValue("dryRun")),
+	})
+	// 途中でエラーになった場合も、それまでに作成されたスタンプは残るため記録する
+	for _, r := range results {
+		if r.Status == stamppack.ImportStatusCreated {
+			h.recordAuditLog(c, model.AuditActionStampImport, model.AuditTargetStamp, r.StampID.UUID.String(), nil, model.JSON{"name": r.Name, "pack": fh.Filename})
+		}
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, stamppack.ErrInvalidPack):
+			return herror.BadRequest(err)
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	return c.JSON(http.StatusOK, results)
+}
+
+// ExportStampPackRequest POST /stamp-packs/export リクエストボディ
+type ExportStampPackRequest struct {
+	Stamps []uuid.UUID `json:"stamps"`
+}
+
+func (r ExportStampPackRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Stamps, vd.Required, vd.Length(1, stamppack.MaxStamps), vd.Each(validator.NotNilUUID)),
+	)
+}
+
+// ExportStampPack POST /stamp-packs/export
+func (h *Handlers) ExportStampPack(c echo.Context) error {
+	var req ExportStampPackRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	stamps := make([]*model.Stamp, 0, len(req.Stamps))
+	seen := make(map[uuid.UUID]struct{}, len(req.Stamps))
+	for _, id := range req.Stamps {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+
+		s, err := h.Repo.GetStamp(id)
+		if err != nil {
+			switch err {
+			case repository.ErrNotFound:
+				return herror.BadRequest("stamp not found")
+			default:
+				return herror.InternalServerError(err)
+			}
+		}
+		stamps = append(stamps, s)
+	}
+	return h.serveStampPack(c, "stamps.zip", stamps)
+}
+
+// ExportStampPalette GET /stamp-palettes/:paletteID/export
+func (h *Handlers) ExportStampPalette(c echo.Context) error {
+	palette := getParamStampPalette(c)
+
+	// 他のユーザーの非公開パレットはエクスポートできない
+	if !palette.IsVisibleTo(getRequestUserID(c)) {
+		return herror.NotFound()
+	}
+
+	stamps := make([]*model.Stamp, 0, len(palette.Stamps))
+	for _, id := range palette.Stamps {
+		s, err := h.Repo.GetStamp(id)
+		if err != nil {
+			switch err {
+			case repository.ErrNotFound:
+				// 削除されたスタンプは含めない
+				continue
+			default:
+				return herror.InternalServerError(err)
+			}
+		}
+		stamps = append(stamps, s)
+	}
+	return h.serveStampPack(c, "stamp-palette-"+palette.ID.String()+".zip", stamps)
+}
+
+func (h *Handlers) serveStampPack(c echo.Context, filename string, stamps []*model.Stamp) error {
+	// メモリ上に溜めずにそのままレスポンスに書き出す
+	// 書き出し開始後のエラーはレスポンスが途中で切れる
+	c.Response().Header().Set(echo.HeaderContentType, consts.MimeZip)
+	c.Response().Header().Set(echo.HeaderContentDisposition, "attachment; filename=\""+filename+"\"")
+	if err := stamppack.Export(c.Response(), h.FileManager, stamps); err != nil {
+		return herror.InternalServerError(err)
+	}
+	return nil
+}
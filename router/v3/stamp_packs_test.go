@@ -0,0 +1,259 @@
+package v3
+
+import (
+	"archive/zip"
+	"bytes"
+	"image"
+	"image/png"
+	"net/http"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/router/session"
+	"github.com/traPtitech/traQ/utils/random"
+	"github.com/traPtitech/traQ/utils/stamppack"
+)
+
+func makeStampPack(t *testing.T, names ...string) []byte {
+	t.Helper()
+	var img bytes.Buffer
+	require.NoError(t, png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 32, 32))))
+
+	var b bytes.Buffer
+	zw := zip.NewWriter(&b)
+	for _, name := range names {
+		w, err := zw.Create(name + ".png")
+		require.NoError(t, err)
+		_, err = w.Write(img.Bytes())
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return b.Bytes()
+}
+
+func TestHandlers_ImportStampPack(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/stamp-packs/import"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	admin := env.CreateAdmin(t, rand)
+	existing := env.CreateStamp(t, user.GetID(), rand)
+	s := env.S(t, user.GetID())
+	adminSession := env.S(t, admin.GetID())
+
+	t.Run("not logged in", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path).
+			WithMultipart().
+			WithFileBytes("file", "pack.zip", makeStampPack(t, random.AlphaNumeric(20))).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path).
+			WithCookie(session.CookieName, s).
+			WithMultipart().
+			WithFileBytes("file", "pack.zip", makeStampPack(t, random.AlphaNumeric(20))).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
+	t.Run("bad request (not zip)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path).
+			WithCookie(session.CookieName, adminSession).
+			WithMultipart().
+			WithFileBytes("file", "pack.zip", []byte("not a zip")).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("request entity too large", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path).
+			WithCookie(session.CookieName, adminSession).
+			WithMultipart().
+			WithFileBytes("file", "pack.zip", make([]byte, stampPackMaxFileSize+1)).
+			Expect().
+			Status(http.StatusRequestEntityTooLarge)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		name := random.AlphaNumeric(20)
+		arr := e.POST(path).
+			WithCookie(session.CookieName, adminSession).
+			WithMultipart().
+			WithFileBytes("file", "pack.zip", makeStampPack(t, name)).
+			WithFormField("dryRun", "true").
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Array()
+		arr.Length().Equal(1)
+		arr.Element(0).Object().Value("status").String().Equal(string(stamppack.ImportStatusReady))
+
+		_, err := env.Repository.GetStampByName(name)
+		assert.Error(t, err)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		name := random.AlphaNumeric(20)
+		arr := e.POST(path).
+			WithCookie(session.CookieName, adminSession).
+			WithMultipart().
+			WithFileBytes("file", "pack.zip", makeStampPack(t, name, existing.Name)).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Array()
+		arr.Length().Equal(2)
+		for _, v := range arr.Iter() {
+			obj := v.Object()
+			switch obj.Value("name").String().Raw() {
+			case name:
+				obj.Value("status").String().Equal(string(stamppack.ImportStatusCreated))
+				obj.Value("stampId").String().NotEmpty()
+			case existing.Name:
+				obj.Value("status").String().Equal(string(stamppack.ImportStatusConflict))
+				obj.Value("stampId").Null()
+			}
+		}
+
+		stamp, err := env.Repository.GetStampByName(name)
+		if assert.NoError(t, err) {
+			assert.Equal(t, admin.GetID(), stamp.CreatorID)
+		}
+	})
+}
+
+func TestHandlers_ExportStampPack(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/stamp-packs/export"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	stamp := env.CreateStamp(t, user.GetID(), rand)
+	s := env.S(t, user.GetID())
+
+	t.Run("not logged in", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path).
+			WithJSON(&ExportStampPackRequest{Stamps: []uuid.UUID{stamp.ID}}).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("bad request (empty)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path).
+			WithCookie(session.CookieName, s).
+			WithJSON(&ExportStampPackRequest{Stamps: []uuid.UUID{}}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("bad request (unknown stamp)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path).
+			WithCookie(session.CookieName, s).
+			WithJSON(&ExportStampPackRequest{Stamps: []uuid.UUID{uuid.Must(uuid.NewV4())}}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		res := e.POST(path).
+			WithCookie(session.CookieName, s).
+			WithJSON(&ExportStampPackRequest{Stamps: []uuid.UUID{stamp.ID, stamp.ID}}).
+			Expect().
+			Status(http.StatusOK)
+		res.ContentType("application/zip")
+
+		b := []byte(res.Body().Raw())
+		zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+		require.NoError(t, err)
+		m, err := stamppack.ReadManifest(zr)
+		if assert.NoError(t, err) && assert.Len(t, m.Stamps, 1) {
+			assert.Equal(t, stamp.Name, m.Stamps[0].Name)
+		}
+	})
+}
+
+func TestHandlers_ExportStampPalette(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/stamp-palettes/{paletteId}/export"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	stamp1 := env.CreateStamp(t, user.GetID(), rand)
+	stamp2 := env.CreateStamp(t, user.GetID(), rand)
+	sp := env.CreateStampPalette(t, user.GetID(), rand, model.UUIDs{stamp1.ID, stamp2.ID})
+	s := env.S(t, user.GetID())
+	user2 := env.CreateUser(t, rand)
+	s2 := env.S(t, user2.GetID())
+
+	t.Run("not logged in", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET(path, sp.ID).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET(path, uuid.Must(uuid.NewV4())).
+			WithCookie(session.CookieName, s).
+			Expect().
+			Status(http.StatusNotFound)
+	})
+
+	t.Run("other user's palette", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET(path, sp.ID).
+			WithCookie(session.CookieName, s2).
+			Expect().
+			Status(http.StatusNotFound)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		res := e.GET(path, sp.ID).
+			WithCookie(session.CookieName, s).
+			Expect().
+			Status(http.StatusOK)
+		res.ContentType("application/zip")
+
+		b := []byte(res.Body().Raw())
+		zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+		require.NoError(t, err)
+		m, err := stamppack.ReadManifest(zr)
+		if assert.NoError(t, err) && assert.Len(t, m.Stamps, 2) {
+			assert.Equal(t, stamp1.Name, m.Stamps[0].Name)
+			assert.Equal(t, stamp2.Name, m.Stamps[1].Name)
+		}
+	})
+}
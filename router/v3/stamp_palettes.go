@@ -7,10 +7,12 @@ import (
 	"github.com/traPtitech/traQ/utils/optional"
 
 	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gofrs/uuid"
 	"github.com/labstack/echo/v4"
 
 	"github.com/traPtitech/traQ/model"
 	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/router/consts"
 	"github.com/traPtitech/traQ/router/extension/herror"
 	"github.com/traPtitech/traQ/utils/validator"
 )
@@ -73,12 +75,14 @@ type PatchStampPaletteRequest struct {
 	Name        optional.String `json:"name"`
 	Description optional.String `json:"description"`
 	Stamps      model.UUIDs     `json:"stamps"`
+	Visibility  optional.String `json:"visibility"`
 }
 
 func (r PatchStampPaletteRequest) Validate() error {
 	err := vd.ValidateStruct(&r,
 		vd.Field(&r.Name, append(validator.StampPaletteNameRule, validator.RequiredIfValid)...),
 		vd.Field(&r.Description, validator.StampPaletteDescriptionRule...),
+		vd.Field(&r.Visibility, validator.RequiredIfValid, vd.In(string(model.StampPaletteVisibilityPrivate), string(model.StampPaletteVisibilityPublic))),
 	)
 	// model.UUIDsがsql.Valuerを実装しているので別でvalidateしている
 	if err != nil {
@@ -105,6 +109,7 @@ func (h *Handlers) EditStampPalette(c echo.Context) error {
 		Name:        req.Name,
 		Description: req.Description,
 		Stamps:      req.Stamps,
+		Visibility:  req.Visibility,
 	}
 
 	// スタンプパレット更新
@@ -121,7 +126,13 @@ func (h *Handlers) EditStampPalette(c echo.Context) error {
 
 // GetStampPalette GET /stamp-palette/:paletteID
 func (h *Handlers) GetStampPalette(c echo.Context) error {
-	return c.JSON(http.StatusOK, formatStampPalette(getParamStampPalette(c)))
+	stampPalette := getParamStampPalette(c)
+
+	// 他ユーザーの非公開パレットは存在しないものとして扱う
+	if !stampPalette.IsVisibleTo(getRequestUserID(c)) {
+		return herror.NotFound()
+	}
+	return c.JSON(http.StatusOK, formatStampPalette(stampPalette))
 }
 
 // DeleteStampPalette DELETE /stamp-palette/:paletteID
@@ -140,3 +151,97 @@ func (h *Handlers) DeleteStampPalette(c echo.Context) error {
 
 	return c.NoContent(http.StatusNoContent)
 }
+
+// GetPublicStampPalettes GET /stamp-palettes/public
+func (h *Handlers) GetPublicStampPalettes(c echo.Context) error {
+	palettes, err := h.Repo.GetPublicStampPalettes()
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+
+	return extension.ServeJSONWithETag(c, formatStampPalettes(palettes))
+}
+
+// CopyStampPalette POST /stamp-palettes/:paletteID/copy
+func (h *Handlers) CopyStampPalette(c echo.Context) error {
+	userID := getRequestUserID(c)
+	stampPalette := getParamStampPalette(c)
+
+	if !stampPalette.IsVisibleTo(userID) {
+		return herror.NotFound()
+	}
+
+	// 削除されたスタンプは含めない
+	stamps := make(model.UUIDs, 0, len(stampPalette.Stamps))
+	for _, id := range stampPalette.Stamps {
+		ok, err := h.Repo.StampExists(id)
+		if err != nil {
+			return herror.InternalServerError(err)
+		}
+		if ok {
+			stamps = append(stamps, id)
+		}
+	}
+
+	sp, err := h.Repo.CreateStampPalette(stampPalette.Name, stampPalette.Description, stamps, userID)
+	if err != nil {
+		switch {
+		case repository.IsArgError(err):
+			return herror.BadRequest(err)
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	return c.JSON(http.StatusCreated, formatStampPalette(sp))
+}
+
+// GetMyStampPaletteSubscriptions GET /users/me/stamp-palette-subscriptions
+func (h *Handlers) GetMyStampPaletteSubscriptions(c echo.Context) error {
+	palettes, err := h.Repo.GetSubscribedStampPalettes(getRequestUserID(c))
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+
+	return extension.ServeJSONWithETag(c, formatStampPalettes(palettes))
+}
+
+// PostStampPaletteSubscriptionRequest POST /users/me/stamp-palette-subscriptions リクエストボディ
+type PostStampPaletteSubscriptionRequest struct {
+	PaletteID uuid.UUID `json:"paletteId"`
+}
+
+func (r PostStampPaletteSubscriptionRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.PaletteID, vd.Required, validator.NotNilUUID),
+	)
+}
+
+// PostStampPaletteSubscription POST /users/me/stamp-palette-subscriptions
+func (h *Handlers) PostStampPaletteSubscription(c echo.Context) error {
+	var req PostStampPaletteSubscriptionRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	if err := h.Repo.SubscribeStampPalette(getRequestUserID(c), req.PaletteID); err != nil {
+		switch {
+		case err == repository.ErrNotFound:
+			return herror.BadRequest("stamp palette not found")
+		case repository.IsArgError(err):
+			return herror.BadRequest(err)
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// RemoveMyStampPaletteSubscription DELETE /users/me/stamp-palette-subscriptions/:paletteID
+func (h *Handlers) RemoveMyStampPaletteSubscription(c echo.Context) error {
+	paletteID := getParamAsUUID(c, consts.ParamStampPaletteID)
+
+	if err := h.Repo.UnsubscribeStampPalette(getRequestUserID(c), paletteID); err != nil {
+		return herror.InternalServerError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -22,6 +22,7 @@ func stampPaletteEquals(t *testing.T, expect *model.StampPalette, actual *httpex
 	actual.Value("name").String().Equal(expect.Name)
 	actual.Value("description").String().Equal(expect.Description)
 	actual.Value("creatorId").String().Equal(expect.CreatorID.String())
+	actual.Value("visibility").String().Equal(string(expect.Visibility))
 	actual.Value("createdAt").String().NotEmpty()
 	actual.Value("updatedAt").String().NotEmpty()
 
@@ -177,6 +178,7 @@ func TestPatchStampPaletteRequest_Validate(t *testing.T) {
 		Name        optional.String
 		Description optional.String
 		Stamps      model.UUIDs
+		Visibility  optional.String
 	}
 	tests := []struct {
 		name    string
@@ -198,12 +200,18 @@ func TestPatchStampPaletteRequest_Validate(t *testing.T) {
 			fields{Name: optional.StringFrom(strings.Repeat("a", 50))},
 			true,
 		},
+		{
+			"invalid visibility",
+			fields{Visibility: optional.StringFrom("internal")},
+			true,
+		},
 		{
 			"success",
 			fields{
 				Name:        optional.StringFrom("test"),
 				Description: optional.StringFrom("description"),
 				Stamps:      model.UUIDs{uuid.Must(uuid.NewV4())},
+				Visibility:  optional.StringFrom("public"),
 			},
 			false,
 		},
@@ -214,6 +222,7 @@ func TestPatchStampPaletteRequest_Validate(t *testing.T) {
 				Name:        tt.fields.Name,
 				Description: tt.fields.Description,
 				Stamps:      tt.fields.Stamps,
+				Visibility:  tt.fields.Visibility,
 			}
 			if err := r.Validate(); (err != nil) != tt.wantErr {
 				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
@@ -305,9 +314,12 @@ func TestHandlers_GetStampPalette(t *testing.T) {
 	path := "/api/v3/stamp-palettes/{paletteId}"
 	env := Setup(t, common1)
 	user := env.CreateUser(t, rand)
+	user2 := env.CreateUser(t, rand)
 	stamp := env.CreateStamp(t, user.GetID(), rand)
 	sp := env.CreateStampPalette(t, user.GetID(), rand, model.UUIDs{stamp.ID})
+	public := env.CreatePublicStampPalette(t, user.GetID(), rand, model.UUIDs{stamp.ID})
 	s := env.S(t, user.GetID())
+	s2 := env.S(t, user2.GetID())
 
 	t.Run("not logged in", func(t *testing.T) {
 		t.Parallel()
@@ -326,6 +338,28 @@ func TestHandlers_GetStampPalette(t *testing.T) {
 			Status(http.StatusNotFound)
 	})
 
+	t.Run("not found (others' private palette)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET(path, sp.ID).
+			WithCookie(session.CookieName, s2).
+			Expect().
+			Status(http.StatusNotFound)
+	})
+
+	t.Run("success (others' public palette)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		obj := e.GET(path, public.ID).
+			WithCookie(session.CookieName, s2).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Object()
+
+		stampPaletteEquals(t, public, obj)
+	})
+
 	t.Run("success", func(t *testing.T) {
 		t.Parallel()
 		e := env.R(t)
@@ -390,3 +424,226 @@ func TestHandlers_DeleteStampPalette(t *testing.T) {
 		assert.ErrorIs(t, err, repository.ErrNotFound)
 	})
 }
+
+func TestHandlers_GetPublicStampPalettes(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/stamp-palettes/public"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	user2 := env.CreateUser(t, rand)
+	public := env.CreatePublicStampPalette(t, user.GetID(), rand, model.UUIDs{})
+	private := env.CreateStampPalette(t, user.GetID(), rand, model.UUIDs{})
+	s2 := env.S(t, user2.GetID())
+
+	t.Run("not logged in", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET(path).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		arr := e.GET(path).
+			WithCookie(session.CookieName, s2).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Array()
+
+		ids := make([]string, 0)
+		for _, v := range arr.Iter() {
+			obj := v.Object()
+			obj.Value("visibility").String().Equal(string(model.StampPaletteVisibilityPublic))
+			ids = append(ids, obj.Value("id").String().Raw())
+		}
+		assert.Contains(t, ids, public.ID.String())
+		assert.NotContains(t, ids, private.ID.String())
+	})
+}
+
+func TestHandlers_CopyStampPalette(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/stamp-palettes/{paletteId}/copy"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	user2 := env.CreateUser(t, rand)
+	stamp := env.CreateStamp(t, user.GetID(), rand)
+	deleted := env.CreateStamp(t, user.GetID(), rand)
+	public := env.CreatePublicStampPalette(t, user.GetID(), rand, model.UUIDs{stamp.ID, deleted.ID})
+	private := env.CreateStampPalette(t, user.GetID(), rand, model.UUIDs{})
+	require.NoError(t, env.Repository.DeleteStamp(deleted.ID))
+	s2 := env.S(t, user2.GetID())
+
+	t.Run("not logged in", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path, public.ID).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("not found (others' private palette)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path, private.ID).
+			WithCookie(session.CookieName, s2).
+			Expect().
+			Status(http.StatusNotFound)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		obj := e.POST(path, public.ID).
+			WithCookie(session.CookieName, s2).
+			Expect().
+			Status(http.StatusCreated).
+			JSON().
+			Object()
+
+		obj.Value("id").String().NotEqual(public.ID.String())
+		obj.Value("name").String().Equal(public.Name)
+		obj.Value("creatorId").String().Equal(user2.GetID().String())
+		obj.Value("visibility").String().Equal(string(model.StampPaletteVisibilityPrivate))
+		obj.Value("stamps").Array().Elements(stamp.ID.String())
+	})
+}
+
+func TestHandlers_GetMyStampPaletteSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/users/me/stamp-palette-subscriptions"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	user2 := env.CreateUser(t, rand)
+	public := env.CreatePublicStampPalette(t, user.GetID(), rand, model.UUIDs{})
+	require.NoError(t, env.Repository.SubscribeStampPalette(user2.GetID(), public.ID))
+	s2 := env.S(t, user2.GetID())
+
+	t.Run("not logged in", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET(path).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		arr := e.GET(path).
+			WithCookie(session.CookieName, s2).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Array()
+		arr.Length().Equal(1)
+		stampPaletteEquals(t, public, arr.Element(0).Object())
+	})
+}
+
+func TestHandlers_PostStampPaletteSubscription(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/users/me/stamp-palette-subscriptions"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	user2 := env.CreateUser(t, rand)
+	public := env.CreatePublicStampPalette(t, user.GetID(), rand, model.UUIDs{})
+	private := env.CreateStampPalette(t, user.GetID(), rand, model.UUIDs{})
+	s := env.S(t, user.GetID())
+	s2 := env.S(t, user2.GetID())
+
+	t.Run("not logged in", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path).
+			WithJSON(&PostStampPaletteSubscriptionRequest{PaletteID: public.ID}).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("bad request (not found)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path).
+			WithCookie(session.CookieName, s2).
+			WithJSON(&PostStampPaletteSubscriptionRequest{PaletteID: uuid.Must(uuid.NewV4())}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("bad request (private)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path).
+			WithCookie(session.CookieName, s2).
+			WithJSON(&PostStampPaletteSubscriptionRequest{PaletteID: private.ID}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("bad request (own)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path).
+			WithCookie(session.CookieName, s).
+			WithJSON(&PostStampPaletteSubscriptionRequest{PaletteID: public.ID}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path).
+			WithCookie(session.CookieName, s2).
+			WithJSON(&PostStampPaletteSubscriptionRequest{PaletteID: public.ID}).
+			Expect().
+			Status(http.StatusNoContent)
+
+		sps, err := env.Repository.GetSubscribedStampPalettes(user2.GetID())
+		require.NoError(t, err)
+		if assert.Len(t, sps, 1) {
+			assert.Equal(t, public.ID, sps[0].ID)
+		}
+	})
+}
+
+func TestHandlers_RemoveMyStampPaletteSubscription(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/users/me/stamp-palette-subscriptions/{paletteId}"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	user2 := env.CreateUser(t, rand)
+	public := env.CreatePublicStampPalette(t, user.GetID(), rand, model.UUIDs{})
+	require.NoError(t, env.Repository.SubscribeStampPalette(user2.GetID(), public.ID))
+	s2 := env.S(t, user2.GetID())
+
+	t.Run("not logged in", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.DELETE(path, public.ID).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.DELETE(path, public.ID).
+			WithCookie(session.CookieName, s2).
+			Expect().
+			Status(http.StatusNoContent)
+
+		sps, err := env.Repository.GetSubscribedStampPalettes(user2.GetID())
+		require.NoError(t, err)
+		assert.Len(t, sps, 0)
+	})
+}
@@ -3,12 +3,17 @@ package v3
 import (
 	"context"
 	"errors"
+	"math"
 	"net/http"
+	"sort"
 	"strconv"
+	"time"
 
 	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gofrs/uuid"
 	"github.com/labstack/echo/v4"
 
+	"github.com/traPtitech/traQ/model"
 	"github.com/traPtitech/traQ/repository"
 	"github.com/traPtitech/traQ/router/consts"
 	"github.com/traPtitech/traQ/router/extension"
@@ -84,7 +89,7 @@ func (h *Handlers) CreateStamp(c echo.Context) error {
 	}
 
 	// スタンプ作成
-	s, err := h.Repo.CreateStamp(repository.CreateStampArgs{Name: c.FormValue("name"), FileID: fileID, CreatorID: userID})
+	s, err := h.Repo.CreateStamp(repository.CreateStampArgs{Name: c.FormValue("name"), FileID: fileID, CreatorID: userID, Category: c.FormValue("category")})
 	if err != nil {
 		switch {
 		case repository.IsArgError(err):
@@ -107,12 +112,18 @@ func (h *Handlers) GetStamp(c echo.Context) error {
 type PatchStampRequest struct {
 	Name      optional.String `json:"name"`
 	CreatorID optional.UUID   `json:"creatorId"`
+	Category  optional.String `json:"category"`
+	Aliases   []string        `json:"aliases"`
+	Tags      []string        `json:"tags"`
 }
 
 func (r PatchStampRequest) ValidateWithContext(ctx context.Context) error {
 	return vd.ValidateStructWithContext(ctx, &r,
 		vd.Field(&r.Name, append(validator.StampNameRule, validator.RequiredIfValid)...),
 		vd.Field(&r.CreatorID, validator.NotNilUUID, utils.IsActiveHumanUserID),
+		vd.Field(&r.Category, validator.StampCategoryRule...),
+		vd.Field(&r.Aliases, vd.Length(0, model.MaxStampAliases), vd.Each(validator.StampNameRuleRequired...)),
+		vd.Field(&r.Tags, vd.Length(0, model.MaxStampTags), vd.Each(validator.StampTagRuleRequired...)),
 	)
 }
 
@@ -134,6 +145,9 @@ func (h *Handlers) EditStamp(c echo.Context) error {
 	args := repository.UpdateStampArgs{
 		Name:      req.Name,
 		CreatorID: req.CreatorID,
+		Category:  req.Category,
+		Aliases:   req.Aliases,
+		Tags:      req.Tags,
 	}
 
 	// 更新
@@ -152,11 +166,12 @@ func (h *Handlers) EditStamp(c echo.Context) error {
 
 // DeleteStamp DELETE /stamps/:stampID
 func (h *Handlers) DeleteStamp(c echo.Context) error {
-	stampID := getParamAsUUID(c, consts.ParamStampID)
+	stamp := getParamStamp(c)
 
-	if err := h.Repo.DeleteStamp(stampID); err != nil {
+	if err := h.Repo.DeleteStamp(stamp.ID); err != nil {
 		return herror.InternalServerError(err)
 	}
+	h.recordAuditLog(c, model.AuditActionStampDelete, model.AuditTargetStamp, stamp.ID.String(), model.JSON{"name": stamp.Name, "creatorId": stamp.CreatorID.String(), "fileId": stamp.FileID.String()}, nil)
 
 	return c.NoContent(http.StatusNoContent)
 }
@@ -222,3 +237,103 @@ func (h *Handlers) GetStampStats(c echo.Context) error {
 	}
 	return c.JSON(http.StatusOK, stats)
 }
+
+const (
+	// stampRankingHistorySize スタンプランキングの計算に使うユーザーのスタンプ履歴の件数
+	stampRankingHistorySize = 100
+	// stampRankingPersonalWeight スタンプランキングのスコアにおけるユーザーのスタンプ履歴の重み
+	stampRankingPersonalWeight = 0.7
+)
+
+// GetMyStampRankingRequest GET /users/me/stamp-ranking リクエストクエリ
+type GetMyStampRankingRequest struct {
+	Limit int `query:"limit"`
+}
+
+func (r *GetMyStampRankingRequest) Validate() error {
+	if r.Limit == 0 {
+		r.Limit = 50
+	}
+	return vd.ValidateStruct(r,
+		vd.Field(&r.Limit, vd.Min(1), vd.Max(200)),
+	)
+}
+
+// GetMyStampRanking GET /users/me/stamp-ranking
+func (h *Handlers) GetMyStampRanking(c echo.Context) error {
+	var req GetMyStampRankingRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	stamps, err := h.Repo.GetAllStamps(repository.StampTypeAll)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	stats, err := h.Repo.GetAllStampStats()
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	history, err := h.Repo.GetUserStampHistory(getRequestUserID(c), stampRankingHistorySize)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+
+	return c.JSON(http.StatusOK, rankStamps(stamps, stats, history, req.Limit))
+}
+
+// rankStamps 全体の使用回数とユーザーの使用履歴からスタンプのランキングを計算します
+//
+// スコアは0から1の値で、ユーザーの直近の使用順位と全体の使用回数(対数)の加重和です。
+// 一度も使われていないスタンプは含まれません。
+func rankStamps(stamps []*model.Stamp, stats map[uuid.UUID]*repository.StampStats, history []*repository.UserStampHistory, limit int) []*StampRankingEntry {
+	var maxCount int64
+	for _, st := range stats {
+		if st.Count > maxCount {
+			maxCount = st.Count
+		}
+	}
+	personal := make(map[uuid.UUID]float64, len(history))
+	lastUsed := make(map[uuid.UUID]time.Time, len(history))
+	for i, h := range history {
+		// historyは使用日時の降順
+		personal[h.StampID] = 1 - float64(i)/float64(len(history))
+		lastUsed[h.StampID] = h.Datetime
+	}
+
+	res := make([]*StampRankingEntry, 0)
+	for _, s := range stamps {
+		var count int64
+		if st, ok := stats[s.ID]; ok {
+			count = st.Count
+		}
+		p, used := personal[s.ID]
+		if count == 0 && !used {
+			continue
+		}
+		var global float64
+		if maxCount > 0 {
+			global = math.Log1p(float64(count)) / math.Log1p(float64(maxCount))
+		}
+		res = append(res, &StampRankingEntry{
+			StampID:    s.ID,
+			Score:      stampRankingPersonalWeight*p + (1-stampRankingPersonalWeight)*global,
+			Count:      count,
+			LastUsedAt: optional.NewTime(lastUsed[s.ID], used),
+		})
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Score != res[j].Score {
+			return res[i].Score > res[j].Score
+		}
+		if res[i].Count != res[j].Count {
+			return res[i].Count > res[j].Count
+		}
+		return res[i].StampID.String() < res[j].StampID.String()
+	})
+	if len(res) > limit {
+		res = res[:limit]
+	}
+	return res
+}
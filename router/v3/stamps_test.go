@@ -3,6 +3,7 @@ package v3
 import (
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/gavv/httpexpect/v2"
 	"github.com/gofrs/uuid"
@@ -269,6 +270,46 @@ func TestHandlers_EditStamp(t *testing.T) {
 		assert.EqualValues(t, user2.GetID().String(), stamp.CreatorID.String())
 		assert.EqualValues(t, "test123123", stamp.Name)
 	})
+	t.Run("bad request (invalid alias)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PATCH(path, stamp3.ID).
+			WithCookie(session.CookieName, s).
+			WithJSON(&PatchStampRequest{Aliases: []string{"invalid alias"}}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("conflict (alias)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PATCH(path, stamp3.ID).
+			WithCookie(session.CookieName, s).
+			WithJSON(&PatchStampRequest{Aliases: []string{"409_conflict"}}).
+			Expect().
+			Status(http.StatusConflict)
+	})
+
+	t.Run("success (category, aliases, tags)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		stamp := env.CreateStamp(t, user.GetID(), rand)
+		e.PATCH(path, stamp.ID).
+			WithCookie(session.CookieName, s).
+			WithJSON(&PatchStampRequest{Category: optional.StringFrom("food"), Aliases: []string{stamp.Name + "_alias"}, Tags: []string{"sweet"}}).
+			Expect().
+			Status(http.StatusNoContent)
+
+		obj := e.GET("/api/v3/stamps/{stampId}", stamp.ID).
+			WithCookie(session.CookieName, s).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Object()
+		obj.Value("category").String().Equal("food")
+		obj.Value("aliases").Array().ContainsOnly(stamp.Name + "_alias")
+		obj.Value("tags").Array().ContainsOnly("sweet")
+	})
 }
 
 func TestHandlers_DeleteStamp(t *testing.T) {
@@ -357,3 +398,124 @@ func TestHandlers_GetStampStats(t *testing.T) {
 		obj.Value("totalCount").Number().Equal(2)
 	})
 }
+
+func TestHandlers_GetMyStampRanking(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/users/me/stamp-ranking"
+	env := Setup(t, s1)
+	user := env.CreateUser(t, rand)
+	user2 := env.CreateUser(t, rand)
+	popular := env.CreateStamp(t, user.GetID(), rand)
+	mine := env.CreateStamp(t, user.GetID(), rand)
+	ch := env.CreateChannel(t, rand)
+	for i := 0; i < 5; i++ {
+		m := env.CreateMessage(t, user2.GetID(), ch.ID, rand)
+		env.AddStampToMessage(t, m.GetID(), popular.ID, user2.GetID())
+	}
+	m := env.CreateMessage(t, user.GetID(), ch.ID, rand)
+	env.AddStampToMessage(t, m.GetID(), mine.ID, user.GetID())
+	s := env.S(t, user.GetID())
+
+	t.Run("not logged in", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET(path).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("bad request", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET(path).
+			WithCookie(session.CookieName, s).
+			WithQuery("limit", 1000).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		arr := e.GET(path).
+			WithCookie(session.CookieName, s).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Array()
+
+		// 自分が使ったスタンプが全体でよく使われているスタンプより上位になる
+		mineIndex, popularIndex := -1, -1
+		for i, v := range arr.Raw() {
+			switch v.(map[string]interface{})["stampId"] {
+			case mine.ID.String():
+				mineIndex = i
+			case popular.ID.String():
+				popularIndex = i
+			}
+		}
+		require.NotEqual(t, -1, mineIndex)
+		require.NotEqual(t, -1, popularIndex)
+		assert.Less(t, mineIndex, popularIndex)
+
+		mineObj := arr.Element(mineIndex).Object()
+		mineObj.Value("count").Number().Equal(1)
+		mineObj.Value("lastUsedAt").String().NotEmpty()
+		popularObj := arr.Element(popularIndex).Object()
+		popularObj.Value("count").Number().Equal(5)
+		popularObj.Value("lastUsedAt").Null()
+	})
+}
+
+func TestRankStamps(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	st1 := &model.Stamp{ID: uuid.Must(uuid.NewV4())}
+	st2 := &model.Stamp{ID: uuid.Must(uuid.NewV4())}
+	st3 := &model.Stamp{ID: uuid.Must(uuid.NewV4())}
+	unused := &model.Stamp{ID: uuid.Must(uuid.NewV4())}
+	stamps := []*model.Stamp{st1, st2, st3, unused}
+	stats := map[uuid.UUID]*repository.StampStats{
+		st1.ID: {Count: 100, TotalCount: 150},
+		st2.ID: {Count: 10, TotalCount: 10},
+		st3.ID: {Count: 1, TotalCount: 1},
+	}
+
+	t.Run("global only", func(t *testing.T) {
+		t.Parallel()
+		res := rankStamps(stamps, stats, nil, 10)
+		if assert.Len(t, res, 3) {
+			assert.Equal(t, st1.ID, res[0].StampID)
+			assert.Equal(t, st2.ID, res[1].StampID)
+			assert.Equal(t, st3.ID, res[2].StampID)
+			assert.InDelta(t, 0.3, res[0].Score, 1e-9)
+			assert.False(t, res[0].LastUsedAt.Valid)
+		}
+	})
+
+	t.Run("personal history first", func(t *testing.T) {
+		t.Parallel()
+		history := []*repository.UserStampHistory{
+			{StampID: st3.ID, Datetime: now},
+			{StampID: st2.ID, Datetime: now.Add(-time.Hour)},
+		}
+		res := rankStamps(stamps, stats, history, 10)
+		if assert.Len(t, res, 3) {
+			assert.Equal(t, st3.ID, res[0].StampID)
+			assert.Equal(t, st2.ID, res[1].StampID)
+			assert.Equal(t, st1.ID, res[2].StampID)
+			assert.True(t, res[0].LastUsedAt.Valid)
+			assert.Equal(t, now, res[0].LastUsedAt.Time)
+		}
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		t.Parallel()
+		res := rankStamps(stamps, stats, nil, 1)
+		if assert.Len(t, res, 1) {
+			assert.Equal(t, st1.ID, res[0].StampID)
+		}
+	})
+}
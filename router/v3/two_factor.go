@@ -0,0 +1,907 @@
+package v3
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"go.uber.org/zap"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/router/consts"
+	"github.com/traPtitech/traQ/router/extension/herror"
+	"github.com/traPtitech/traQ/router/session"
+	"github.com/traPtitech/traQ/utils/random"
+)
+
+const (
+	twoFactorMethodTOTP     = "totp"
+	twoFactorMethodWebAuthn = "webauthn"
+	twoFactorMethodRecovery = "recovery"
+
+	sessionKeyWebAuthnLogin            = "webauthn_login"
+	sessionKeyWebAuthnRegistration     = "webauthn_registration"
+	sessionKeyWebAuthnRegistrationName = "webauthn_registration_name"
+
+	// twoFactorMaxFailures ユーザーがロックされるまでに許容される二要素認証の連続失敗回数
+	twoFactorMaxFailures = 5
+	// twoFactorLockDuration 二要素認証の連続失敗によるロック時間
+	twoFactorLockDuration = 15 * time.Minute
+	// totpPeriod TOTPのタイムステップの長さ(秒)
+	totpPeriod = 30
+	// recoveryCodeCount 発行するリカバリーコードの数
+	recoveryCodeCount = 10
+)
+
+// webAuthnUser webauthn.User 実装
+type webAuthnUser struct {
+	user  model.UserInfo
+	creds []*model.WebAuthnCredential
+}
+
+func (u *webAuthnUser) WebAuthnID() []byte {
+	return u.user.GetID().Bytes()
+}
+
+func (u *webAuthnUser) WebAuthnName() string {
+	return u.user.GetName()
+}
+
+func (u *webAuthnUser) WebAuthnDisplayName() string {
+	return u.user.GetResponseDisplayName()
+}
+
+func (u *webAuthnUser) WebAuthnIcon() string {
+	return ""
+}
+
+func (u *webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	result := make([]webauthn.Credential, len(u.creds))
+	for i, cred := range u.creds {
+		result[i] = webauthn.Credential{
+			ID:              cred.CredentialID,
+			PublicKey:       cred.PublicKey,
+			AttestationType: cred.AttestationType,
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    cred.AAGUID,
+				SignCount: cred.SignCount,
+			},
+		}
+	}
+	return result
+}
+
+func (u *webAuthnUser) credentialDescriptors() []protocol.CredentialDescriptor {
+	result := make([]protocol.CredentialDescriptor, len(u.creds))
+	for i, cred := range u.creds {
+		result[i] = protocol.CredentialDescriptor{
+			Type:         protocol.PublicKeyCredentialType,
+			CredentialID: cred.CredentialID,
+		}
+	}
+	return result
+}
+
+func (u *webAuthnUser) findCredential(id []byte) *model.WebAuthnCredential {
+	for _, cred := range u.creds {
+		if bytes.Equal(cred.CredentialID, id) {
+			return cred
+		}
+	}
+	return nil
+}
+
+// webAuthn WebAuthnのRelying Partyを返します
+func (h *Handlers) webAuthn() (*webauthn.WebAuthn, error) {
+	u, err := url.Parse(h.Origin)
+	if err != nil {
+		return nil, err
+	}
+	return webauthn.New(&webauthn.Config{
+		RPDisplayName: "traQ",
+		RPID:          u.Hostname(),
+		RPOrigin:      h.Origin,
+	})
+}
+
+func (h *Handlers) getWebAuthnUser(user model.UserInfo) (*webAuthnUser, error) {
+	creds, err := h.Repo.GetWebAuthnCredentials(user.GetID())
+	if err != nil {
+		return nil, err
+	}
+	return &webAuthnUser{user: user, creds: creds}, nil
+}
+
+// getTwoFactorMethods ユーザーが利用可能な二要素認証の方式を返します
+func (h *Handlers) getTwoFactorMethods(userID uuid.UUID) ([]string, error) {
+	methods := make([]string, 0, 3)
+
+	t, err := h.Repo.GetUserTOTP(userID)
+	if err != nil && err != repository.ErrNotFound {
+		return nil, err
+	}
+	if t != nil && t.Enabled {
+		methods = append(methods, twoFactorMethodTOTP)
+	}
+
+	creds, err := h.Repo.GetWebAuthnCredentials(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(creds) > 0 {
+		methods = append(methods, twoFactorMethodWebAuthn)
+	}
+
+	if len(methods) > 0 {
+		count, err := h.Repo.GetRecoveryCodeCount(userID)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			methods = append(methods, twoFactorMethodRecovery)
+		}
+	}
+	return methods, nil
+}
+
+// isTwoFactorRequired ユーザーのロールに二要素認証が必須かどうか
+func (h *Handlers) isTwoFactorRequired(user model.UserInfo) (bool, error) {
+	roles, err := h.Repo.GetTwoFactorRequiredRoles()
+	if err != nil {
+		return false, err
+	}
+	for _, r := range roles {
+		if r == user.GetRole() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// issueRecoveryCodes 新しいリカバリーコードを発行し、既存のものと置き換えます
+func (h *Handlers) issueRecoveryCodes(userID uuid.UUID) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		c := strings.ToLower(random.SecureAlphaNumeric(10))
+		codes[i] = c[:5] + "-" + c[5:]
+		hashes[i] = model.HashRecoveryCode(normalizeRecoveryCode(codes[i]))
+	}
+	if err := h.Repo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// issueRecoveryCodesIfNotExists リカバリーコードが存在しない場合のみ発行します
+func (h *Handlers) issueRecoveryCodesIfNotExists(userID uuid.UUID) ([]string, error) {
+	count, err := h.Repo.GetRecoveryCodeCount(userID)
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return []string{}, nil
+	}
+	return h.issueRecoveryCodes(userID)
+}
+
+// cleanupRecoveryCodes 二要素認証が全て解除された場合にリカバリーコードを削除します
+func (h *Handlers) cleanupRecoveryCodes(userID uuid.UUID) error {
+	methods, err := h.getTwoFactorMethods(userID)
+	if err != nil {
+		return err
+	}
+	if len(methods) > 0 {
+		return nil
+	}
+	return h.Repo.ReplaceRecoveryCodes(userID, nil)
+}
+
+// checkTwoFactorRemovable 指定した方式を解除してもポリシーに違反しないかどうかを確認します
+func (h *Handlers) checkTwoFactorRemovable(user model.UserInfo, method string, remainingCreds int) error {
+	required, err := h.isTwoFactorRequired(user)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	if !required {
+		return nil
+	}
+
+	methods, err := h.getTwoFactorMethods(user.GetID())
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	for _, m := range methods {
+		if m == twoFactorMethodRecovery {
+			continue // リカバリーコードのみでは二要素認証を登録しているとみなさない
+		}
+		if m == method && (m != twoFactorMethodWebAuthn || remainingCreds == 0) {
+			continue
+		}
+		return nil
+	}
+	return herror.Forbidden("two-factor authentication is required for your role")
+}
+
+// useTOTPCode TOTPコードを検証し、正しい場合はそのタイムステップを使用済みにします
+//
+// 既に使用したタイムステップ以前のコードは再利用できません。
+func (h *Handlers) useTOTPCode(userID uuid.UUID, secret, code string) (bool, error) {
+	step, ok := validateTOTP(secret, code, time.Now())
+	if !ok {
+		return false, nil
+	}
+	if err := h.Repo.UseUserTOTPStep(userID, step); err != nil {
+		switch err {
+		case repository.ErrAlreadyExists, repository.ErrNotFound:
+			return false, nil
+		default:
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// validateTOTP TOTPコードを検証し、一致したタイムステップを返します
+//
+// totp.Validateと同様に前後1ステップのずれを許容します。
+func validateTOTP(secret, code string, now time.Time) (int64, bool) {
+	opts := totp.ValidateOpts{Period: totpPeriod, Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1}
+	current := now.Unix() / totpPeriod
+	for _, step := range []int64{current - 1, current, current + 1} {
+		expected, err := totp.GenerateCodeCustom(secret, time.Unix(step*totpPeriod, 0).UTC(), opts)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(code), []byte(expected)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+// startTwoFactorLogin パスワード認証に成功したユーザーの二要素認証待ちセッションを開始します
+func (h *Handlers) startTwoFactorLogin(c echo.Context, user model.UserInfo, methods []string) error {
+	if err := session.StartTwoFactorLogin(c, h.SessStore, user.GetID()); err != nil {
+		return herror.InternalServerError(err)
+	}
+
+	type response struct {
+		Methods []string `json:"methods"`
+	}
+	return c.JSON(http.StatusOK, &response{Methods: methods})
+}
+
+// getPendingTwoFactorLogin 二要素認証待ちのセッションとユーザーを取得します
+func (h *Handlers) getPendingTwoFactorLogin(c echo.Context) (session.Session, model.UserInfo, error) {
+	sess, err := h.SessStore.GetSession(c)
+	if err != nil {
+		return nil, nil, herror.InternalServerError(err)
+	}
+	if sess == nil {
+		return nil, nil, herror.Unauthorized("there is no pending login")
+	}
+
+	uidI, _ := sess.Get(session.KeyTwoFactorUserID)
+	expI, _ := sess.Get(session.KeyTwoFactorExpiresAt)
+	uidStr, ok1 := uidI.(string)
+	exp, ok2 := expI.(int64)
+	if !ok1 || !ok2 || time.Now().Unix() > exp {
+		return nil, nil, herror.Unauthorized("there is no pending login")
+	}
+
+	user, err := h.Repo.GetUser(uuid.FromStringOrNil(uidStr), false)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return nil, nil, herror.Unauthorized("there is no pending login")
+		default:
+			return nil, nil, herror.InternalServerError(err)
+		}
+	}
+	if !user.IsActive() {
+		return nil, nil, herror.Forbidden("this account is currently suspended")
+	}
+
+	// 連続失敗によりロックされている間は、どの方式でも認証できない
+	lockout, err := h.Repo.GetTwoFactorLockout(user.GetID())
+	if err != nil && err != repository.ErrNotFound {
+		return nil, nil, herror.InternalServerError(err)
+	}
+	if lockout != nil && lockout.IsLocked(time.Now()) {
+		return nil, nil, herror.TooManyRequests("too many failed attempts. Please try again later.")
+	}
+	return sess, user, nil
+}
+
+// failTwoFactorLogin 二要素認証の失敗を記録します
+//
+// 失敗回数はユーザー毎に数えられ、連続で一定回数失敗した場合はユーザーを一定時間ロックします。
+func (h *Handlers) failTwoFactorLogin(c echo.Context, user model.UserInfo, method string) error {
+	h.L(c).Info("an api two-factor login attempt failed", zap.String("username", user.GetName()), zap.String("method", method))
+
+	lockout, err := h.Repo.RecordTwoFactorFailure(user.GetID(), twoFactorMaxFailures, twoFactorLockDuration)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	if lockout.IsLocked(time.Now()) {
+		h.L(c).Info("two-factor login is locked due to too many failed attempts", zap.String("username", user.GetName()))
+		if err := h.SessStore.RevokeSession(c); err != nil {
+			return herror.InternalServerError(err)
+		}
+		return herror.TooManyRequests("too many failed attempts. Please try again later.")
+	}
+	return herror.Unauthorized("invalid code")
+}
+
+// completeTwoFactorLogin 二要素認証に成功したユーザーのセッションを発行します
+func (h *Handlers) completeTwoFactorLogin(c echo.Context, user model.UserInfo, method string) error {
+	h.L(c).Info("an api login attempt succeeded", zap.String("username", user.GetName()), zap.String("method", method))
+
+	if err := h.Repo.ResetTwoFactorFailures(user.GetID()); err != nil {
+		return herror.InternalServerError(err)
+	}
+
+	if _, err := h.SessStore.RenewSession(c, user.GetID()); err != nil {
+		return herror.InternalServerError(err)
+	}
+
+	if redirect := c.QueryParam("redirect"); len(redirect) > 0 {
+		if !isSameOriginPath(redirect) {
+			redirect = "/"
+		}
+		return c.Redirect(http.StatusFound, redirect)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// isSameOriginPath 同一オリジンの相対パスかどうか
+//
+// "//"や"/\"で始まるものはブラウザによって別オリジンとして解釈されるため除外します。
+func isSameOriginPath(p string) bool {
+	return strings.HasPrefix(p, "/") && !strings.HasPrefix(p, "//") && !strings.HasPrefix(p, "/\\")
+}
+
+// PostTwoFactorCodeRequest POST /login/two-factor/totp, /login/two-factor/recovery リクエストボディ
+type PostTwoFactorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+func (r PostTwoFactorCodeRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Code, vd.Required, vd.RuneLength(0, 32)),
+	)
+}
+
+// LoginWithTOTP POST /login/two-factor/totp
+func (h *Handlers) LoginWithTOTP(c echo.Context) error {
+	var req PostTwoFactorCodeRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	_, user, err := h.getPendingTwoFactorLogin(c)
+	if err != nil {
+		return err
+	}
+
+	t, err := h.Repo.GetUserTOTP(user.GetID())
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return herror.BadRequest("TOTP is not enabled")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	if !t.Enabled {
+		return herror.BadRequest("TOTP is not enabled")
+	}
+
+	if ok, err := h.useTOTPCode(user.GetID(), t.Secret, req.Code); err != nil {
+		return herror.InternalServerError(err)
+	} else if !ok {
+		return h.failTwoFactorLogin(c, user, twoFactorMethodTOTP)
+	}
+	return h.completeTwoFactorLogin(c, user, twoFactorMethodTOTP)
+}
+
+// LoginWithRecoveryCode POST /login/two-factor/recovery
+func (h *Handlers) LoginWithRecoveryCode(c echo.Context) error {
+	var req PostTwoFactorCodeRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	_, user, err := h.getPendingTwoFactorLogin(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.Repo.UseRecoveryCode(user.GetID(), model.HashRecoveryCode(normalizeRecoveryCode(req.Code))); err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return h.failTwoFactorLogin(c, user, twoFactorMethodRecovery)
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	return h.completeTwoFactorLogin(c, user, twoFactorMethodRecovery)
+}
+
+// BeginWebAuthnLogin POST /login/two-factor/webauthn/begin
+func (h *Handlers) BeginWebAuthnLogin(c echo.Context) error {
+	sess, user, err := h.getPendingTwoFactorLogin(c)
+	if err != nil {
+		return err
+	}
+
+	wu, err := h.getWebAuthnUser(user)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	if len(wu.creds) == 0 {
+		return herror.BadRequest("WebAuthn is not enabled")
+	}
+
+	w, err := h.webAuthn()
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	options, data, err := w.BeginLogin(wu)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	if err := sess.Set(sessionKeyWebAuthnLogin, b); err != nil {
+		return herror.InternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, options)
+}
+
+// FinishWebAuthnLogin POST /login/two-factor/webauthn/finish
+func (h *Handlers) FinishWebAuthnLogin(c echo.Context) error {
+	sess, user, err := h.getPendingTwoFactorLogin(c)
+	if err != nil {
+		return err
+	}
+
+	dataI, _ := sess.Get(sessionKeyWebAuthnLogin)
+	b, ok := dataI.([]byte)
+	if !ok {
+		return herror.BadRequest("WebAuthn login has not been started")
+	}
+	var data webauthn.SessionData
+	if err := json.Unmarshal(b, &data); err != nil {
+		return herror.InternalServerError(err)
+	}
+	if err := sess.Delete(sessionKeyWebAuthnLogin); err != nil {
+		return herror.InternalServerError(err)
+	}
+
+	wu, err := h.getWebAuthnUser(user)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	w, err := h.webAuthn()
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	cred, err := w.FinishLogin(wu, data, c.Request())
+	if err != nil || cred.Authenticator.CloneWarning {
+		return h.failTwoFactorLogin(c, user, twoFactorMethodWebAuthn)
+	}
+
+	if stored := wu.findCredential(cred.ID); stored != nil {
+		if err := h.Repo.UpdateWebAuthnCredentialSignCount(stored.ID, cred.Authenticator.SignCount); err != nil {
+			return herror.InternalServerError(err)
+		}
+	}
+	return h.completeTwoFactorLogin(c, user, twoFactorMethodWebAuthn)
+}
+
+// GetMyTwoFactorAuth GET /users/me/two-factor
+func (h *Handlers) GetMyTwoFactorAuth(c echo.Context) error {
+	user := getRequestUser(c)
+
+	t, err := h.Repo.GetUserTOTP(user.GetID())
+	if err != nil && err != repository.ErrNotFound {
+		return herror.InternalServerError(err)
+	}
+	creds, err := h.Repo.GetWebAuthnCredentials(user.GetID())
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	count, err := h.Repo.GetRecoveryCodeCount(user.GetID())
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	required, err := h.isTwoFactorRequired(user)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+
+	type response struct {
+		Required      bool                  `json:"required"`
+		TOTP          bool                  `json:"totp"`
+		WebAuthn      []*webAuthnCredential `json:"webauthn"`
+		RecoveryCodes int                   `json:"recoveryCodes"`
+	}
+	return c.JSON(http.StatusOK, &response{
+		Required:      required,
+		TOTP:          t != nil && t.Enabled,
+		WebAuthn:      formatWebAuthnCredentials(creds),
+		RecoveryCodes: count,
+	})
+}
+
+// SetupMyTOTP POST /users/me/two-factor/totp
+func (h *Handlers) SetupMyTOTP(c echo.Context) error {
+	user := getRequestUser(c)
+
+	t, err := h.Repo.GetUserTOTP(user.GetID())
+	if err != nil && err != repository.ErrNotFound {
+		return herror.InternalServerError(err)
+	}
+	if t != nil && t.Enabled {
+		return herror.BadRequest("TOTP is already enabled")
+	}
+
+	issuer := "traQ"
+	if u, err := url.Parse(h.Origin); err == nil && len(u.Host) > 0 {
+		issuer = u.Host
+	}
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      issuer,
+		AccountName: user.GetName(),
+	})
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	if err := h.Repo.SaveUserTOTP(user.GetID(), key.Secret()); err != nil {
+		return herror.InternalServerError(err)
+	}
+
+	type response struct {
+		Secret string `json:"secret"`
+		URL    string `json:"url"`
+	}
+	return c.JSON(http.StatusCreated, &response{
+		Secret: key.Secret(),
+		URL:    key.URL(),
+	})
+}
+
+// VerifyMyTOTP POST /users/me/two-factor/totp/verify
+func (h *Handlers) VerifyMyTOTP(c echo.Context) error {
+	var req PostTwoFactorCodeRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+	userID := getRequestUserID(c)
+
+	t, err := h.Repo.GetUserTOTP(userID)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return herror.BadRequest("TOTP setup has not been started")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	if t.Enabled {
+		return herror.BadRequest("TOTP is already enabled")
+	}
+	if ok, err := h.useTOTPCode(userID, t.Secret, req.Code); err != nil {
+		return herror.InternalServerError(err)
+	} else if !ok {
+		return herror.BadRequest("invalid code")
+	}
+
+	if err := h.Repo.EnableUserTOTP(userID); err != nil {
+		return herror.InternalServerError(err)
+	}
+	codes, err := h.issueRecoveryCodesIfNotExists(userID)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, &recoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableMyTOTP DELETE /users/me/two-factor/totp
+func (h *Handlers) DisableMyTOTP(c echo.Context) error {
+	user := getRequestUser(c)
+
+	if err := h.checkTwoFactorRemovable(user, twoFactorMethodTOTP, 0); err != nil {
+		return err
+	}
+	if err := h.Repo.DeleteUserTOTP(user.GetID()); err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return herror.BadRequest("TOTP is not enabled")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	if err := h.cleanupRecoveryCodes(user.GetID()); err != nil {
+		return herror.InternalServerError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// RegenerateMyRecoveryCodes POST /users/me/two-factor/recovery-codes
+func (h *Handlers) RegenerateMyRecoveryCodes(c echo.Context) error {
+	userID := getRequestUserID(c)
+
+	methods, err := h.getTwoFactorMethods(userID)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	if len(methods) == 0 {
+		return herror.BadRequest("two-factor authentication is not enabled")
+	}
+
+	codes, err := h.issueRecoveryCodes(userID)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, &recoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// PostWebAuthnRegistrationRequest POST /users/me/two-factor/webauthn/register/begin リクエストボディ
+type PostWebAuthnRegistrationRequest struct {
+	Name string `json:"name"`
+}
+
+func (r PostWebAuthnRegistrationRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Name, vd.Required, vd.RuneLength(1, 32)),
+	)
+}
+
+// BeginMyWebAuthnRegistration POST /users/me/two-factor/webauthn/register/begin
+func (h *Handlers) BeginMyWebAuthnRegistration(c echo.Context) error {
+	var req PostWebAuthnRegistrationRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	wu, err := h.getWebAuthnUser(getRequestUser(c))
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	w, err := h.webAuthn()
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	options, data, err := w.BeginRegistration(wu, webauthn.WithExclusions(wu.credentialDescriptors()))
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+
+	sess, err := h.SessStore.GetSession(c)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	if sess == nil {
+		return herror.BadRequest("WebAuthn registration requires a login session")
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	if err := sess.Set(sessionKeyWebAuthnRegistration, b); err != nil {
+		return herror.InternalServerError(err)
+	}
+	if err := sess.Set(sessionKeyWebAuthnRegistrationName, req.Name); err != nil {
+		return herror.InternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, options)
+}
+
+// FinishMyWebAuthnRegistration POST /users/me/two-factor/webauthn/register/finish
+func (h *Handlers) FinishMyWebAuthnRegistration(c echo.Context) error {
+	user := getRequestUser(c)
+
+	sess, err := h.SessStore.GetSession(c)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	if sess == nil {
+		return herror.BadRequest("WebAuthn registration has not been started")
+	}
+	dataI, _ := sess.Get(sessionKeyWebAuthnRegistration)
+	nameI, _ := sess.Get(sessionKeyWebAuthnRegistrationName)
+	b, ok1 := dataI.([]byte)
+	name, ok2 := nameI.(string)
+	if !ok1 || !ok2 {
+		return herror.BadRequest("WebAuthn registration has not been started")
+	}
+	var data webauthn.SessionData
+	if err := json.Unmarshal(b, &data); err != nil {
+		return herror.InternalServerError(err)
+	}
+	if err := sess.Delete(sessionKeyWebAuthnRegistration); err != nil {
+		return herror.InternalServerError(err)
+	}
+	if err := sess.Delete(sessionKeyWebAuthnRegistrationName); err != nil {
+		return herror.InternalServerError(err)
+	}
+
+	wu, err := h.getWebAuthnUser(user)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	w, err := h.webAuthn()
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	cred, err := w.FinishRegistration(wu, data, c.Request())
+	if err != nil {
+		return herror.BadRequest("invalid credential")
+	}
+
+	m := &model.WebAuthnCredential{
+		ID:              uuid.Must(uuid.NewV4()),
+		UserID:          user.GetID(),
+		Name:            name,
+		CredentialID:    cred.ID,
+		PublicKey:       cred.PublicKey,
+		AttestationType: cred.AttestationType,
+		AAGUID:          cred.Authenticator.AAGUID,
+		SignCount:       cred.Authenticator.SignCount,
+	}
+	if err := h.Repo.CreateWebAuthnCredential(m); err != nil {
+		switch err {
+		case repository.ErrAlreadyExists:
+			return herror.Conflict("this credential has already been registered")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	codes, err := h.issueRecoveryCodesIfNotExists(user.GetID())
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+
+	type response struct {
+		Credential    *webAuthnCredential `json:"credential"`
+		RecoveryCodes []string            `json:"recoveryCodes"`
+	}
+	return c.JSON(http.StatusCreated, &response{
+		Credential:    formatWebAuthnCredential(m),
+		RecoveryCodes: codes,
+	})
+}
+
+// DeleteMyWebAuthnCredential DELETE /users/me/two-factor/webauthn/:credentialID
+func (h *Handlers) DeleteMyWebAuthnCredential(c echo.Context) error {
+	user := getRequestUser(c)
+	credentialID := getParamAsUUID(c, consts.ParamCredentialID)
+
+	creds, err := h.Repo.GetWebAuthnCredentials(user.GetID())
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	if err := h.checkTwoFactorRemovable(user, twoFactorMethodWebAuthn, len(creds)-1); err != nil {
+		return err
+	}
+
+	if err := h.Repo.DeleteWebAuthnCredential(user.GetID(), credentialID); err != nil {
+		switch err {
+		case repository.ErrNotFound, repository.ErrNilID:
+			return herror.NotFound()
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	if err := h.cleanupRecoveryCodes(user.GetID()); err != nil {
+		return herror.InternalServerError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ResetUserTwoFactorAuth DELETE /users/:userID/two-factor
+func (h *Handlers) ResetUserTwoFactorAuth(c echo.Context) error {
+	userID := getParamUser(c).GetID()
+
+	if err := h.Repo.DeleteUserTOTP(userID); err != nil && err != repository.ErrNotFound {
+		return herror.InternalServerError(err)
+	}
+	creds, err := h.Repo.GetWebAuthnCredentials(userID)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	for _, cred := range creds {
+		if err := h.Repo.DeleteWebAuthnCredential(userID, cred.ID); err != nil && err != repository.ErrNotFound {
+			return herror.InternalServerError(err)
+		}
+	}
+	if err := h.Repo.ReplaceRecoveryCodes(userID, nil); err != nil {
+		return herror.InternalServerError(err)
+	}
+	h.recordAuditLog(c, model.AuditActionUserTwoFactorReset, model.AuditTargetUser, userID.String(), nil, nil)
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetTwoFactorPolicy GET /two-factor-policy
+func (h *Handlers) GetTwoFactorPolicy(c echo.Context) error {
+	roles, err := h.Repo.GetTwoFactorRequiredRoles()
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, &PutTwoFactorPolicyRequest{RequiredRoles: roles})
+}
+
+// PutTwoFactorPolicyRequest PUT /two-factor-policy リクエストボディ
+type PutTwoFactorPolicyRequest struct {
+	RequiredRoles []string `json:"requiredRoles"`
+}
+
+func (r PutTwoFactorPolicyRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.RequiredRoles, vd.NotNil, vd.Each(vd.Required, vd.RuneLength(1, 30))),
+	)
+}
+
+// PutTwoFactorPolicy PUT /two-factor-policy
+func (h *Handlers) PutTwoFactorPolicy(c echo.Context) error {
+	var req PutTwoFactorPolicyRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	roles, err := h.Repo.GetAllUserRoles()
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	exists := make(map[string]bool, len(roles))
+	for _, r := range roles {
+		exists[r.Name] = true
+	}
+	set := make(map[string]bool, len(req.RequiredRoles))
+	result := make([]string, 0, len(req.RequiredRoles))
+	for _, r := range req.RequiredRoles {
+		if !exists[r] {
+			return herror.BadRequest("unknown role: " + r)
+		}
+		if !set[r] {
+			set[r] = true
+			result = append(result, r)
+		}
+	}
+
+	current, err := h.Repo.GetTwoFactorRequiredRoles()
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	if err := h.Repo.SetTwoFactorRequiredRoles(result); err != nil {
+		return herror.InternalServerError(err)
+	}
+	h.recordAuditLog(c, model.AuditActionTwoFactorPolicyEdit, model.AuditTargetTwoFactorPolicy, "", model.JSON{"requiredRoles": current}, model.JSON{"requiredRoles": result})
+	return c.NoContent(http.StatusNoContent)
+}
+
+type recoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
@@ -0,0 +1,166 @@
+package v3
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gavv/httpexpect/v2"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/router/session"
+)
+
+const testTOTPSecret = "JBSWY3DPEHPK3PXP"
+
+func TestValidateTOTP(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	current := now.Unix() / totpPeriod
+	code := func(step int64) string {
+		c, err := totp.GenerateCode(testTOTPSecret, time.Unix(step*totpPeriod, 0))
+		require.NoError(t, err)
+		return c
+	}
+
+	step, ok := validateTOTP(testTOTPSecret, code(current), now)
+	if assert.True(t, ok) {
+		assert.Equal(t, current, step)
+	}
+	step, ok = validateTOTP(testTOTPSecret, code(current-1), now)
+	if assert.True(t, ok) {
+		assert.Equal(t, current-1, step)
+	}
+	_, ok = validateTOTP(testTOTPSecret, code(current-2), now)
+	assert.False(t, ok)
+	_, ok = validateTOTP(testTOTPSecret, "000000x", now)
+	assert.False(t, ok)
+}
+
+func TestIsSameOriginPath(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]bool{
+		"/":                     true,
+		"/channels/general":     true,
+		"/path?redirect=//evil": true,
+		"":                      false,
+		"//evil.example.com":    false,
+		"/\\evil.example.com":   false,
+		"https://evil.example":  false,
+		"channels":              false,
+	}
+	for p, want := range tests {
+		assert.Equal(t, want, isSameOriginPath(p), p)
+	}
+}
+
+func TestHandlers_LoginWithTOTP(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/login/two-factor/totp"
+	env := Setup(t, common1)
+
+	createTOTPUser := func(t *testing.T) model.UserInfo {
+		t.Helper()
+		user := env.CreateUser(t, rand)
+		require.NoError(t, env.Repository.SaveUserTOTP(user.GetID(), testTOTPSecret))
+		require.NoError(t, env.Repository.EnableUserTOTP(user.GetID()))
+		return user
+	}
+	startLogin := func(t *testing.T, e *httpexpect.Expect, user model.UserInfo) string {
+		t.Helper()
+		return e.POST("/api/v3/login").
+			WithJSON(&PostLoginRequest{Name: user.GetName(), Password: "testtesttesttest"}).
+			Expect().
+			Status(http.StatusOK).
+			Cookie(session.CookieName).
+			Value().
+			Raw()
+	}
+	currentCode := func(t *testing.T) string {
+		t.Helper()
+		c, err := totp.GenerateCode(testTOTPSecret, time.Now())
+		require.NoError(t, err)
+		return c
+	}
+
+	t.Run("no pending login", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path).
+			WithJSON(&PostTwoFactorCodeRequest{Code: "000000"}).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("code cannot be reused", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		user := createTOTPUser(t)
+		code := currentCode(t)
+
+		s := startLogin(t, e, user)
+		e.POST(path).
+			WithCookie(session.CookieName, s).
+			WithJSON(&PostTwoFactorCodeRequest{Code: code}).
+			Expect().
+			Status(http.StatusNoContent)
+
+		s = startLogin(t, e, user)
+		e.POST(path).
+			WithCookie(session.CookieName, s).
+			WithJSON(&PostTwoFactorCodeRequest{Code: code}).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("redirect to another origin is rejected", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		user := createTOTPUser(t)
+
+		s := startLogin(t, e, user)
+		e.POST(path).
+			WithCookie(session.CookieName, s).
+			WithQuery("redirect", "//evil.example.com").
+			WithJSON(&PostTwoFactorCodeRequest{Code: currentCode(t)}).
+			Expect().
+			Status(http.StatusFound).
+			Header("Location").Equal("/")
+	})
+
+	t.Run("failures are counted per user", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		user := createTOTPUser(t)
+
+		// ログインし直しても失敗回数はリセットされない
+		for i := 1; i < twoFactorMaxFailures; i++ {
+			s := startLogin(t, e, user)
+			e.POST(path).
+				WithCookie(session.CookieName, s).
+				WithJSON(&PostTwoFactorCodeRequest{Code: "invalid"}).
+				Expect().
+				Status(http.StatusUnauthorized)
+		}
+		s := startLogin(t, e, user)
+		e.POST(path).
+			WithCookie(session.CookieName, s).
+			WithJSON(&PostTwoFactorCodeRequest{Code: "invalid"}).
+			Expect().
+			Status(http.StatusTooManyRequests)
+
+		// ロック中は正しいコードでもログインできない
+		s = startLogin(t, e, user)
+		e.POST(path).
+			WithCookie(session.CookieName, s).
+			WithJSON(&PostTwoFactorCodeRequest{Code: currentCode(t)}).
+			Expect().
+			Status(http.StatusTooManyRequests)
+	})
+}
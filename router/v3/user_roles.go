@@ -0,0 +1,257 @@
+package v3
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/labstack/echo/v4"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/router/consts"
+	"github.com/traPtitech/traQ/router/extension/herror"
+	"github.com/traPtitech/traQ/service/rbac/permission"
+	"github.com/traPtitech/traQ/service/rbac/role"
+)
+
+// userRoleNameRegex カスタムロール名の正規表現
+//
+// OAuth2スコープと区別するため、":"は使用できません。
+var userRoleNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,30}$`)
+
+// validPermissions 存在する全ての権限の名前
+var validPermissions = func() []interface{} {
+	res := make([]interface{}, len(permission.List))
+	for i, p := range permission.List {
+		res[i] = p.Name()
+	}
+	return res
+}()
+
+// GetUserRoles GET /roles
+func (h *Handlers) GetUserRoles(c echo.Context) error {
+	roles, err := h.Repo.GetAllUserRoles()
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	return c.JSON(http.StatusOK, formatUserRoles(roles))
+}
+
+// PostUserRoleRequest POST /roles リクエストボディ
+type PostUserRoleRequest struct {
+	Name         string   `json:"name"`
+	Permissions  []string `json:"permissions"`
+	Inheritances []string `json:"inheritances"`
+}
+
+func (r PostUserRoleRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Name, vd.Required, vd.Match(userRoleNameRegex)),
+		vd.Field(&r.Permissions, vd.Each(vd.Required, vd.In(validPermissions...))),
+		vd.Field(&r.Inheritances, vd.Each(vd.Required)),
+	)
+}
+
+// CreateUserRole POST /roles
+func (h *Handlers) CreateUserRole(c echo.Context) error {
+	var req PostUserRoleRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	roles, err := h.Repo.GetAllUserRoles()
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	if err := checkUserRoleInheritances(roles, req.Name, req.Inheritances); err != nil {
+		return herror.BadRequest(err)
+	}
+
+	r := &model.UserRole{
+		Name:         req.Name,
+		Oauth2Scope:  false,
+		System:       false,
+		Inheritances: make([]*model.UserRole, len(req.Inheritances)),
+		Permissions:  make([]model.RolePermission, len(req.Permissions)),
+	}
+	for i, name := range req.Inheritances {
+		r.Inheritances[i] = &model.UserRole{Name: name}
+	}
+	for i, p := range req.Permissions {
+		r.Permissions[i] = model.RolePermission{Role: req.Name, Permission: p}
+	}
+	if err := h.Repo.CreateUserRole(r); err != nil {
+		switch err {
+		case repository.ErrAlreadyExists:
+			return herror.Conflict("this name has already been used")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	if err := h.RBAC.Reload(); err != nil {
+		return herror.InternalServerError(err)
+	}
+	h.recordAuditLog(c, model.AuditActionUserRoleCreate, model.AuditTargetUserRole, r.Name, nil, model.JSON{"permissions": req.Permissions, "inheritances": req.Inheritances})
+
+	created, err := h.Repo.GetUserRole(r.Name)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	return c.JSON(http.StatusCreated, formatUserRole(created))
+}
+
+// GetUserRole GET /roles/:roleName
+func (h *Handlers) GetUserRole(c echo.Context) error {
+	r, err := h.Repo.GetUserRole(c.Param(consts.ParamRoleName))
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return herror.NotFound()
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	return c.JSON(http.StatusOK, formatUserRole(r))
+}
+
+// PatchUserRoleRequest PATCH /roles/:roleName リクエストボディ
+type PatchUserRoleRequest struct {
+	Permissions  []string `json:"permissions"`
+	Inheritances []string `json:"inheritances"`
+}
+
+func (r PatchUserRoleRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.Permissions, vd.Each(vd.Required, vd.In(validPermissions...))),
+		vd.Field(&r.Inheritances, vd.Each(vd.Required)),
+	)
+}
+
+// EditUserRole PATCH /roles/:roleName
+func (h *Handlers) EditUserRole(c echo.Context) error {
+	name := c.Param(consts.ParamRoleName)
+
+	var req PatchUserRoleRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	r, err := h.Repo.GetUserRole(name)
+	if err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return herror.NotFound()
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	if r.System {
+		return herror.Forbidden("system roles cannot be edited")
+	}
+
+	if req.Inheritances != nil {
+		roles, err := h.Repo.GetAllUserRoles()
+		if err != nil {
+			return herror.InternalServerError(err)
+		}
+		if err := checkUserRoleInheritances(roles, name, req.Inheritances); err != nil {
+			return herror.BadRequest(err)
+		}
+	}
+
+	if err := h.Repo.UpdateUserRole(name, repository.UpdateUserRoleArgs{
+		Permissions:  req.Permissions,
+		Inheritances: req.Inheritances,
+	}); err != nil {
+		return herror.InternalServerError(err)
+	}
+	if err := h.RBAC.Reload(); err != nil {
+		return herror.InternalServerError(err)
+	}
+	current := formatUserRole(r)
+	before, after := model.JSON{}, model.JSON{}
+	if req.Permissions != nil {
+		before["permissions"], after["permissions"] = current.Permissions, req.Permissions
+	}
+	if req.Inheritances != nil {
+		before["inheritances"], after["inheritances"] = current.Inheritances, req.Inheritances
+	}
+	h.recordAuditLog(c, model.AuditActionUserRoleEdit, model.AuditTargetUserRole, name, before, after)
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// DeleteUserRole DELETE /roles/:roleName
+func (h *Handlers) DeleteUserRole(c echo.Context) error {
+	name := c.Param(consts.ParamRoleName)
+
+	if err := h.Repo.DeleteUserRole(name); err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return herror.NotFound()
+		case repository.ErrForbidden:
+			return herror.Forbidden("system roles and roles assigned to users cannot be deleted")
+		default:
+			return herror.InternalServerError(err)
+		}
+	}
+	if err := h.RBAC.Reload(); err != nil {
+		return herror.InternalServerError(err)
+	}
+	h.recordAuditLog(c, model.AuditActionUserRoleDelete, model.AuditTargetUserRole, name, nil, nil)
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// checkUserRoleInheritances ロールnameがinheritancesを継承できるかどうかを検証します
+func checkUserRoleInheritances(roles []*model.UserRole, name string, inheritances []string) error {
+	graph := make(map[string][]string, len(roles))
+	for _, r := range roles {
+		subs := make([]string, len(r.Inheritances))
+		for i, sub := range r.Inheritances {
+			subs[i] = sub.Name
+		}
+		graph[r.Name] = subs
+	}
+	for _, r := range roles {
+		if r.Oauth2Scope {
+			// OAuth2スコープは継承できない
+			delete(graph, r.Name)
+		}
+	}
+
+	for _, sub := range inheritances {
+		if sub == role.Admin {
+			return errors.New("admin role cannot be inherited")
+		}
+		if _, ok := graph[sub]; !ok {
+			return errors.New("invalid role: " + sub)
+		}
+	}
+	graph[name] = inheritances
+
+	// 循環検知
+	visited := map[string]bool{}
+	var reachable func(from string) bool
+	reachable = func(from string) bool {
+		for _, sub := range graph[from] {
+			if sub == name {
+				return true
+			}
+			if visited[sub] {
+				continue
+			}
+			visited[sub] = true
+			if reachable(sub) {
+				return true
+			}
+		}
+		return false
+	}
+	if reachable(name) {
+		return errors.New("circular inheritance is not allowed")
+	}
+	return nil
+}
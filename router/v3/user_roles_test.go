@@ -0,0 +1,231 @@
+package v3
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/router/session"
+	"github.com/traPtitech/traQ/service/rbac/permission"
+	"github.com/traPtitech/traQ/service/rbac/role"
+	"github.com/traPtitech/traQ/utils/optional"
+	"github.com/traPtitech/traQ/utils/random"
+)
+
+func TestPostUserRoleRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		req     PostUserRoleRequest
+		wantErr bool
+	}{
+		{"empty name", PostUserRoleRequest{Name: ""}, true},
+		{"invalid name", PostUserRoleRequest{Name: "messages:write"}, true},
+		{"invalid permission", PostUserRoleRequest{Name: "moderator", Permissions: []string{"invalid"}}, true},
+		{"success", PostUserRoleRequest{Name: "moderator", Permissions: []string{permission.GetMessage.Name()}, Inheritances: []string{role.User}}, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckUserRoleInheritances(t *testing.T) {
+	t.Parallel()
+
+	a := &model.UserRole{Name: "a"}
+	b := &model.UserRole{Name: "b", Inheritances: []*model.UserRole{a}}
+	c := &model.UserRole{Name: "c", Inheritances: []*model.UserRole{b}}
+	scope := &model.UserRole{Name: "read", Oauth2Scope: true}
+	admin := &model.UserRole{Name: role.Admin}
+	roles := []*model.UserRole{a, b, c, scope, admin}
+
+	assert.NoError(t, checkUserRoleInheritances(roles, "d", []string{"c"}))
+	assert.NoError(t, checkUserRoleInheritances(roles, "c", []string{"a"}))
+	assert.Error(t, checkUserRoleInheritances(roles, "d", []string{"unknown"}))
+	assert.Error(t, checkUserRoleInheritances(roles, "d", []string{"read"}))
+	assert.Error(t, checkUserRoleInheritances(roles, "d", []string{role.Admin}))
+	assert.Error(t, checkUserRoleInheritances(roles, "a", []string{"c"}))
+	assert.Error(t, checkUserRoleInheritances(roles, "a", []string{"a"}))
+}
+
+func TestHandlers_CreateUserRole(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/roles"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	admin := env.CreateAdmin(t, rand)
+	s := env.S(t, user.GetID())
+	adminSession := env.S(t, admin.GetID())
+
+	t.Run("not logged in", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path).
+			WithJSON(&PostUserRoleRequest{Name: random.AlphaNumeric(20)}).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path).
+			WithCookie(session.CookieName, s).
+			WithJSON(&PostUserRoleRequest{Name: random.AlphaNumeric(20)}).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path).
+			WithCookie(session.CookieName, adminSession).
+			WithJSON(&PostUserRoleRequest{Name: role.User}).
+			Expect().
+			Status(http.StatusConflict)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		name := random.AlphaNumeric(20)
+		obj := e.POST(path).
+			WithCookie(session.CookieName, adminSession).
+			WithJSON(&PostUserRoleRequest{
+				Name:         name,
+				Permissions:  []string{permission.ManageUserRole.Name()},
+				Inheritances: []string{role.User},
+			}).
+			Expect().
+			Status(http.StatusCreated).
+			JSON().
+			Object()
+
+		obj.Value("name").String().Equal(name)
+		obj.Value("system").Boolean().False()
+		obj.Value("permissions").Array().Elements(permission.ManageUserRole.Name())
+		obj.Value("inheritances").Array().Elements(role.User)
+
+		// 作成したロールを割り当てたユーザーは、継承した権限と直接付与した権限を持つ
+		moderator := env.CreateUser(t, rand)
+		require.NoError(t, env.Repository.UpdateUser(moderator.GetID(), repository.UpdateUserArgs{Role: optional.StringFrom(name)}))
+		e.GET(path).
+			WithCookie(session.CookieName, env.S(t, moderator.GetID())).
+			Expect().
+			Status(http.StatusOK)
+	})
+}
+
+func TestHandlers_EditUserRole(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/roles/{roleName}"
+	env := Setup(t, common1)
+	admin := env.CreateAdmin(t, rand)
+	s := env.S(t, admin.GetID())
+
+	name := random.AlphaNumeric(20)
+	require.NoError(t, env.Repository.CreateUserRole(&model.UserRole{Name: name}))
+
+	t.Run("system role", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PATCH(path, role.User).
+			WithCookie(session.CookieName, s).
+			WithJSON(&PatchUserRoleRequest{Permissions: []string{}}).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PATCH(path, random.AlphaNumeric(20)).
+			WithCookie(session.CookieName, s).
+			WithJSON(&PatchUserRoleRequest{Permissions: []string{}}).
+			Expect().
+			Status(http.StatusNotFound)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PATCH(path, name).
+			WithCookie(session.CookieName, s).
+			WithJSON(&PatchUserRoleRequest{
+				Permissions:  []string{permission.GetUser.Name()},
+				Inheritances: []string{role.Bot},
+			}).
+			Expect().
+			Status(http.StatusNoContent)
+
+		r, err := env.Repository.GetUserRole(name)
+		require.NoError(t, err)
+		if assert.Len(t, r.Permissions, 1) {
+			assert.Equal(t, permission.GetUser.Name(), r.Permissions[0].Permission)
+		}
+		if assert.Len(t, r.Inheritances, 1) {
+			assert.Equal(t, role.Bot, r.Inheritances[0].Name)
+		}
+	})
+}
+
+func TestHandlers_DeleteUserRole(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/roles/{roleName}"
+	env := Setup(t, common1)
+	admin := env.CreateAdmin(t, rand)
+	s := env.S(t, admin.GetID())
+
+	t.Run("system role", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.DELETE(path, role.User).
+			WithCookie(session.CookieName, s).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
+	t.Run("assigned role", func(t *testing.T) {
+		t.Parallel()
+		name := random.AlphaNumeric(20)
+		require.NoError(t, env.Repository.CreateUserRole(&model.UserRole{Name: name}))
+		user := env.CreateUser(t, rand)
+		require.NoError(t, env.Repository.UpdateUser(user.GetID(), repository.UpdateUserArgs{Role: optional.StringFrom(name)}))
+
+		e := env.R(t)
+		e.DELETE(path, name).
+			WithCookie(session.CookieName, s).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		name := random.AlphaNumeric(20)
+		require.NoError(t, env.Repository.CreateUserRole(&model.UserRole{Name: name}))
+
+		e := env.R(t)
+		e.DELETE(path, name).
+			WithCookie(session.CookieName, s).
+			Expect().
+			Status(http.StatusNoContent)
+
+		_, err := env.Repository.GetUserRole(name)
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+}
@@ -81,6 +81,7 @@ func (h *Handlers) CreateUser(c echo.Context) error {
 			return herror.InternalServerError(err)
 		}
 	}
+	h.recordAuditLog(c, model.AuditActionUserCreate, model.AuditTargetUser, user.GetID().String(), nil, model.JSON{"name": user.GetName(), "role": user.GetRole()})
 
 	return c.JSON(http.StatusCreated, formatUserDetail(user, []model.UserTag{}, []uuid.UUID{}))
 }
@@ -232,7 +233,16 @@ func (h *Handlers) GetUserIcon(c echo.Context) error {
 
 // ChangeUserIcon PUT /users/:userID/icon
 func (h *Handlers) ChangeUserIcon(c echo.Context) error {
-	return utils.ChangeUserIcon(h.Imaging, c, h.Repo, h.FileManager, getParamAsUUID(c, consts.ParamUserID))
+	user := getParamUser(c)
+	if err := utils.ChangeUserIcon(h.Imaging, c, h.Repo, h.FileManager, user.GetID()); err != nil {
+		return err
+	}
+	after := model.JSON{}
+	if updated, err := h.Repo.GetUser(user.GetID(), false); err == nil {
+		after["iconFileId"] = updated.GetIconFileID().String()
+	}
+	h.recordAuditLog(c, model.AuditActionUserIconChange, model.AuditTargetUser, user.GetID().String(), model.JSON{"iconFileId": user.GetIconFileID().String()}, after)
+	return nil
 }
 
 // GetMyIcon GET /users/me/icon
@@ -323,7 +333,12 @@ func (h *Handlers) ChangeUserPassword(c echo.Context) error {
 	if err := bindAndValidate(c, &req); err != nil {
 		return err
 	}
-	return utils.ChangeUserPassword(c, h.Repo, h.SessStore, getParamAsUUID(c, consts.ParamUserID), req.NewPassword)
+	userID := getParamAsUUID(c, consts.ParamUserID)
+	if err := utils.ChangeUserPassword(c, h.Repo, h.SessStore, userID, req.NewPassword); err != nil {
+		return err
+	}
+	h.recordAuditLog(c, model.AuditActionUserPasswordChange, model.AuditTargetUser, userID.String(), nil, nil)
+	return nil
 }
 
 // GetUser GET /users/:userID
@@ -369,6 +384,21 @@ func (h *Handlers) EditUser(c echo.Context) error {
 		return err
 	}
 
+	if req.Role.Valid {
+		r, err := h.Repo.GetUserRole(req.Role.String)
+		if err != nil {
+			switch err {
+			case repository.ErrNotFound:
+				return herror.BadRequest("invalid role")
+			default:
+				return herror.InternalServerError(err)
+			}
+		}
+		if r.Oauth2Scope {
+			return herror.BadRequest("invalid role")
+		}
+	}
+
 	args := repository.UpdateUserArgs{
 		DisplayName: req.DisplayName,
 		TwitterID:   req.TwitterID,
@@ -383,6 +413,22 @@ func (h *Handlers) EditUser(c echo.Context) error {
 		return herror.InternalServerError(err)
 	}
 
+	user := getParamUser(c)
+	before, after := model.JSON{}, model.JSON{}
+	if req.DisplayName.Valid {
+		before["displayName"], after["displayName"] = user.GetDisplayName(), req.DisplayName.String
+	}
+	if req.TwitterID.Valid {
+		before["twitterId"], after["twitterId"] = user.GetTwitterID(), req.TwitterID.String
+	}
+	if req.Role.Valid {
+		before["role"], after["role"] = user.GetRole(), req.Role.String
+	}
+	if req.State.Valid {
+		before["state"], after["state"] = user.GetState().Int(), int(req.State.Int64)
+	}
+	h.recordAuditLog(c, model.AuditActionUserEdit, model.AuditTargetUser, userID.String(), before, after)
+
 	return c.NoContent(http.StatusNoContent)
 }
 
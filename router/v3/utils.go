@@ -43,6 +43,27 @@ func getRequestUserID(c echo.Context) uuid.UUID {
 	return getRequestUser(c).GetID()
 }
 
+// getRequestRestrictedChannels リクエストのトークンで操作対象が制限されているチャンネルのIDを取得
+//
+// 制限されていない場合はrestrictedがfalseになります。
+func getRequestRestrictedChannels(c echo.Context) (channels map[uuid.UUID]struct{}, restricted bool) {
+	scopes, ok := c.Get(consts.KeyOAuth2AccessScopes).(model.AccessScopes)
+	if !ok {
+		return nil, false
+	}
+	return scopes.RestrictedChannels()
+}
+
+// isChannelAllowedForRequest リクエストのトークンで指定したチャンネルの操作が許可されているかどうか
+func isChannelAllowedForRequest(c echo.Context, channelID uuid.UUID) bool {
+	channels, restricted := getRequestRestrictedChannels(c)
+	if !restricted {
+		return true
+	}
+	_, ok := channels[channelID]
+	return ok
+}
+
 // getParamUser URLの:userIDに対応するユーザー構造体を取得
 func getParamUser(c echo.Context) model.UserInfo {
 	return c.Get(consts.KeyParamUser).(model.UserInfo)
@@ -73,6 +94,11 @@ func getParamStamp(c echo.Context) *model.Stamp {
 	return c.Get(consts.KeyParamStamp).(*model.Stamp)
 }
 
+// getParamChannelTemplate URLの:templateIDに対応するChannelTemplateを取得
+func getParamChannelTemplate(c echo.Context) *model.ChannelTemplate {
+	return c.Get(consts.KeyParamTemplate).(*model.ChannelTemplate)
+}
+
 // getParamStampPalette URLの:paletteIDに対応するStampPaletteを取得
 func getParamStampPalette(c echo.Context) *model.StampPalette {
 	return c.Get(consts.KeyParamStampPalette).(*model.StampPalette)
@@ -4,12 +4,16 @@ import (
 	"context"
 	"crypto/subtle"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"regexp"
 	"strings"
 
 	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
 	"github.com/gofrs/uuid"
 	"github.com/labstack/echo/v4"
 
@@ -21,6 +25,7 @@ import (
 	"github.com/traPtitech/traQ/service/file"
 	"github.com/traPtitech/traQ/service/message"
 	"github.com/traPtitech/traQ/service/rbac/permission"
+	"github.com/traPtitech/traQ/service/webhook/adapter"
 	"github.com/traPtitech/traQ/utils/hmac"
 	"github.com/traPtitech/traQ/utils/optional"
 	"github.com/traPtitech/traQ/utils/validator"
@@ -116,11 +121,15 @@ func (h *Handlers) GetWebhook(c echo.Context) error {
 
 // PatchWebhookRequest PATCH /webhooks/:webhookID リクエストボディ
 type PatchWebhookRequest struct {
-	Name        optional.String `json:"name"`
-	Description optional.String `json:"description"`
-	ChannelID   optional.UUID   `json:"channelId"`
-	Secret      optional.String `json:"secret"`
-	OwnerID     optional.UUID   `json:"ownerId"`
+	Name            optional.String   `json:"name"`
+	Description     optional.String   `json:"description"`
+	ChannelID       optional.UUID     `json:"channelId"`
+	Secret          optional.String   `json:"secret"`
+	OwnerID         optional.UUID     `json:"ownerId"`
+	OutgoingURL     optional.String   `json:"outgoingUrl"`
+	TriggerWords    []string          `json:"triggerWords"`
+	Templates       map[string]string `json:"templates"`
+	PayloadTemplate optional.String   `json:"payloadTemplate"`
 }
 
 func (r PatchWebhookRequest) ValidateWithContext(ctx context.Context) error {
@@ -130,9 +139,42 @@ func (r PatchWebhookRequest) ValidateWithContext(ctx context.Context) error {
 		vd.Field(&r.ChannelID, validator.NotNilUUID, utils.IsPublicChannelID),
 		vd.Field(&r.Secret, vd.RuneLength(0, 50)),
 		vd.Field(&r.OwnerID, validator.NotNilUUID, utils.IsActiveHumanUserID),
+		vd.Field(&r.OutgoingURL, is.URL, validator.NotInternalURL),
+		vd.Field(&r.TriggerWords, vd.Length(0, 10), vd.Each(vd.Required, vd.RuneLength(1, 30), vd.Match(triggerWordRegex))),
+		vd.Field(&r.Templates, vd.Length(0, 50), vd.By(validateWebhookTemplates)),
+		vd.Field(&r.PayloadTemplate, vd.RuneLength(0, adapter.MaxTemplateLength), vd.By(validatePayloadTemplate)),
 	)
 }
 
+func validatePayloadTemplate(value interface{}) error {
+	text, _ := value.(optional.String)
+	if len(text.String) == 0 {
+		return nil
+	}
+	if _, err := adapter.NewTemplate("payload", text.String); err != nil {
+		return err
+	}
+	return nil
+}
+
+var triggerWordRegex = regexp.MustCompile(`^\S+$`)
+
+func validateWebhookTemplates(value interface{}) error {
+	tmpls, _ := value.(map[string]string)
+	for key, text := range tmpls {
+		if err := adapter.ValidateTemplateKey(key); err != nil {
+			return err
+		}
+		if len(text) == 0 {
+			continue
+		}
+		if _, err := adapter.NewTemplate(key, text); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return nil
+}
+
 // EditWebhook PATCH /webhooks/:webhookID
 func (h *Handlers) EditWebhook(c echo.Context) error {
 	w := getParamWebhook(c)
@@ -143,11 +185,30 @@ func (h *Handlers) EditWebhook(c echo.Context) error {
 	}
 
 	args := repository.UpdateWebhookArgs{
-		Name:        req.Name,
-		Description: req.Description,
-		ChannelID:   req.ChannelID,
-		Secret:      req.Secret,
-		CreatorID:   req.OwnerID,
+		Name:            req.Name,
+		Description:     req.Description,
+		ChannelID:       req.ChannelID,
+		Secret:          req.Secret,
+		CreatorID:       req.OwnerID,
+		OutgoingURL:     req.OutgoingURL,
+		PayloadTemplate: req.PayloadTemplate,
+	}
+	if req.TriggerWords != nil {
+		args.TriggerWords = optional.StringFrom(strings.Join(req.TriggerWords, " "))
+	}
+	if req.Templates != nil {
+		// 空文字のテンプレートは削除 (デフォルトに戻す)
+		args.Templates = model.WebhookTemplates{}
+		for k, v := range w.GetTemplates() {
+			args.Templates[k] = v
+		}
+		for k, v := range req.Templates {
+			if len(v) == 0 {
+				delete(args.Templates, k)
+			} else {
+				args.Templates[k] = v
+			}
+		}
 	}
 	if err := h.Repo.UpdateWebhook(w.GetID(), args); err != nil {
 		switch {
@@ -165,10 +226,28 @@ func (h *Handlers) PostWebhook(c echo.Context) error {
 	w := getParamWebhook(c)
 	channelID := w.GetChannelID()
 
-	// text/plainのみ受け付ける
+	// 外部サービスのWebhook
+	if name := c.QueryParam("provider"); len(name) > 0 {
+		a, ok := adapter.Get(name)
+		if !ok {
+			return herror.BadRequest("unknown provider")
+		}
+		return h.postProviderWebhook(c, w, a)
+	}
+	if a, ok := adapter.Detect(c.Request().Header); ok {
+		return h.postProviderWebhook(c, w, a)
+	}
+
+	// text/plain, またはテンプレートが設定されている場合のみapplication/jsonを受け付ける
+	isJSON := false
 	switch strings.ToLower(c.Request().Header.Get(echo.HeaderContentType)) {
 	case echo.MIMETextPlain, strings.ToLower(echo.MIMETextPlainCharsetUTF8):
 		break
+	case echo.MIMEApplicationJSON, strings.ToLower(echo.MIMEApplicationJSONCharsetUTF8):
+		if len(w.GetPayloadTemplate()) == 0 {
+			return echo.NewHTTPError(http.StatusUnsupportedMediaType)
+		}
+		isJSON = true
 	default:
 		return echo.NewHTTPError(http.StatusUnsupportedMediaType)
 	}
@@ -201,18 +280,75 @@ func (h *Handlers) PostWebhook(c echo.Context) error {
 		channelID = id
 	}
 
-	// 投稿先チャンネル確認
-	if !h.ChannelManager.PublicChannelTree().IsChannelPresent(channelID) {
-		return herror.BadRequest("invalid channel")
+	text := string(body)
+
+	// テンプレート適用
+	if isJSON {
+		text, err = adapter.RenderPayload(w.GetPayloadTemplate(), body)
+		if err != nil {
+			return herror.BadRequest(fmt.Sprintf("failed to render message: %s", err.Error()))
+		}
+		if len(text) == 0 {
+			return c.NoContent(http.StatusNoContent)
+		}
 	}
 
 	// 埋め込み変換
 	if isTrue(c.QueryParam("embed")) {
-		body = []byte(h.Replacer.Replace(string(body)))
+		text = h.Replacer.Replace(text)
+	}
+
+	return h.postWebhookMessage(c, w, channelID, text)
+}
+
+// postProviderWebhook 外部サービスのWebhookを変換して投稿します
+func (h *Handlers) postProviderWebhook(c echo.Context, w model.Webhook, a adapter.Adapter) error {
+	header := c.Request().Header
+	body, err := ioutil.ReadAll(c.Request().Body)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+	if len(body) == 0 {
+		return herror.BadRequest("empty body")
+	}
+
+	// Webhookシークレット確認
+	if len(w.GetSecret()) > 0 {
+		if err := a.Verify(header, body, w.GetSecret()); err != nil {
+			return herror.BadRequest(fmt.Sprintf("%s: %s", a.Name(), err.Error()))
+		}
+	}
+
+	// application/x-www-form-urlencodedの場合はpayloadパラメータにJSONが入っている
+	payload := body
+	if strings.HasPrefix(strings.ToLower(header.Get(echo.HeaderContentType)), echo.MIMEApplicationForm) {
+		q, err := url.ParseQuery(string(body))
+		if err != nil {
+			return herror.BadRequest("malformed form body")
+		}
+		payload = []byte(q.Get("payload"))
+	}
+
+	text, err := adapter.Render(a, header, payload, w.GetTemplates())
+	if err != nil {
+		return herror.BadRequest(fmt.Sprintf("failed to render message: %s", err.Error()))
+	}
+	if len(text) == 0 {
+		// 通知対象外のイベント
+		return c.NoContent(http.StatusNoContent)
+	}
+
+	return h.postWebhookMessage(c, w, w.GetChannelID(), text)
+}
+
+func (h *Handlers) postWebhookMessage(c echo.Context, w model.Webhook, channelID uuid.UUID, text string) error {
+	// 投稿先チャンネル確認
+	if !h.ChannelManager.PublicChannelTree().IsChannelPresent(channelID) {
+		return herror.BadRequest("invalid channel")
 	}
 
 	// メッセージ投稿
-	if _, err := h.MessageManager.Create(channelID, w.GetBotUserID(), string(body)); err != nil {
+	if _, err := h.MessageManager.Create(channelID, w.GetBotUserID(), text); err != nil {
 		switch err {
 		case message.ErrChannelArchived:
 			return herror.BadRequest("the channel has been archived")
@@ -224,6 +360,43 @@ func (h *Handlers) PostWebhook(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+// PostWebhookDryRunRequest POST /webhooks/:webhookID/dry-run リクエストボディ
+type PostWebhookDryRunRequest struct {
+	PayloadTemplate optional.String `json:"payloadTemplate"`
+	Payload         json.RawMessage `json:"payload"`
+}
+
+func (r PostWebhookDryRunRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.PayloadTemplate, vd.RuneLength(0, adapter.MaxTemplateLength)),
+		vd.Field(&r.Payload, vd.Required),
+	)
+}
+
+// PostWebhookDryRun POST /webhooks/:webhookID/dry-run
+func (h *Handlers) PostWebhookDryRun(c echo.Context) error {
+	w := getParamWebhook(c)
+
+	var req PostWebhookDryRunRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	tmpl := w.GetPayloadTemplate()
+	if req.PayloadTemplate.Valid {
+		tmpl = req.PayloadTemplate.String
+	}
+	if len(tmpl) == 0 {
+		return herror.BadRequest("payload template is not set")
+	}
+
+	text, err := adapter.RenderPayload(tmpl, req.Payload)
+	if err != nil {
+		return herror.BadRequest(fmt.Sprintf("failed to render message: %s", err.Error()))
+	}
+	return c.JSON(http.StatusOK, echo.Map{"content": text})
+}
+
 // DeleteWebhook DELETE /webhooks/:webhookID
 func (h *Handlers) DeleteWebhook(c echo.Context) error {
 	w := getParamWebhook(c)
@@ -239,6 +412,11 @@ func (h *Handlers) DeleteWebhook(c echo.Context) error {
 func (h *Handlers) GetWebhookMessages(c echo.Context) error {
 	w := getParamWebhook(c)
 
+	// Webhookのメッセージは複数のチャンネルにまたがるため、チャンネル制限付きトークンでは取得できない
+	if _, restricted := getRequestRestrictedChannels(c); restricted {
+		return herror.Forbidden("this token is restricted to specific channels")
+	}
+
 	var req MessagesQuery
 	if err := req.bind(c); err != nil {
 		return err
@@ -246,3 +424,36 @@ func (h *Handlers) GetWebhookMessages(c echo.Context) error {
 
 	return serveMessages(c, h.MessageManager, req.convertU(w.GetBotUserID()))
 }
+
+// GetWebhookLogsRequest GET /webhooks/:webhookID/logs リクエストクエリ
+type GetWebhookLogsRequest struct {
+	Limit  int `query:"limit"`
+	Offset int `query:"offset"`
+}
+
+func (r *GetWebhookLogsRequest) Validate() error {
+	if r.Limit == 0 {
+		r.Limit = 30
+	}
+	return vd.ValidateStruct(r,
+		vd.Field(&r.Limit, vd.Min(1), vd.Max(200)),
+		vd.Field(&r.Offset, vd.Min(0)),
+	)
+}
+
+// GetWebhookLogs GET /webhooks/:webhookID/logs
+func (h *Handlers) GetWebhookLogs(c echo.Context) error {
+	w := getParamWebhook(c)
+
+	var req GetWebhookLogsRequest
+	if err := bindAndValidate(c, &req); err != nil {
+		return err
+	}
+
+	logs, err := h.Repo.GetWebhookDeliveryLogs(w.GetID(), req.Limit, req.Offset)
+	if err != nil {
+		return herror.InternalServerError(err)
+	}
+
+	return c.JSON(http.StatusOK, formatWebhookDeliveryLogs(logs))
+}
@@ -3,12 +3,14 @@ package v3
 import (
 	"crypto/hmac"
 	"crypto/sha1"
+	"crypto/sha256"
 	"encoding/hex"
 	"net/http"
 	"testing"
 
 	"github.com/gavv/httpexpect/v2"
 	"github.com/gofrs/uuid"
+	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -280,6 +282,36 @@ func TestHandlers_EditWebhook(t *testing.T) {
 			Status(http.StatusBadRequest)
 	})
 
+	t.Run("bad request (invalid template key)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PATCH(path, wh.GetID()).
+			WithCookie(session.CookieName, s).
+			WithJSON(&PatchWebhookRequest{Templates: map[string]string{"unknown.push": "test"}}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("bad request (invalid template)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PATCH(path, wh.GetID()).
+			WithCookie(session.CookieName, s).
+			WithJSON(&PatchWebhookRequest{Templates: map[string]string{"github.push": "{{ .ref "}}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("bad request (invalid payload template)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.PATCH(path, wh.GetID()).
+			WithCookie(session.CookieName, s).
+			WithJSON(&PatchWebhookRequest{PayloadTemplate: optional.StringFrom("{{ .text ")}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
 	t.Run("not found", func(t *testing.T) {
 		t.Parallel()
 		e := env.R(t)
@@ -318,6 +350,11 @@ func TestHandlers_PostWebhook(t *testing.T) {
 	archived := env.CreateChannel(t, rand)
 	require.NoError(t, env.CM.ArchiveChannel(archived.ID, user.GetID()))
 	wh := env.CreateWebhook(t, rand, user.GetID(), ch.ID)
+	ch3 := env.CreateChannel(t, rand)
+	wh2 := env.CreateWebhook(t, rand, user.GetID(), ch3.ID)
+	ch4 := env.CreateChannel(t, rand)
+	wh3 := env.CreateWebhook(t, rand, user.GetID(), ch4.ID)
+	require.NoError(t, env.Repository.UpdateWebhook(wh3.GetID(), repository.UpdateWebhookArgs{PayloadTemplate: optional.StringFrom("{{ .title }}: {{ .body }}")}))
 
 	calcHMACSHA1 := func(t *testing.T, message, secret string) string {
 		t.Helper()
@@ -325,6 +362,13 @@ func TestHandlers_PostWebhook(t *testing.T) {
 		_, _ = mac.Write([]byte(message))
 		return hex.EncodeToString(mac.Sum(nil))
 	}
+	calcHMACSHA256 := func(t *testing.T, message, secret string) string {
+		t.Helper()
+		mac := hmac.New(sha256.New, []byte(secret))
+		_, _ = mac.Write([]byte(message))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+	githubPayload := `{"action":"opened","number":1,"repository":{"full_name":"traPtitech/traQ","html_url":"https://github.com/traPtitech/traQ"},"issue":{"number":1,"title":"bug","html_url":"https://github.com/traPtitech/traQ/issues/1"},"sender":{"login":"takashi"}}`
 
 	t.Run("bad request (empty body)", func(t *testing.T) {
 		t.Parallel()
@@ -408,6 +452,93 @@ func TestHandlers_PostWebhook(t *testing.T) {
 			Status(http.StatusUnsupportedMediaType)
 	})
 
+	t.Run("bad request (github, bad signature)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path, wh2.GetID()).
+			WithHeader("X-GitHub-Event", "issues").
+			WithHeader("X-Hub-Signature-256", "sha256="+calcHMACSHA256(t, githubPayload, "wrong")).
+			WithBytes([]byte(githubPayload)).
+			WithHeader(echo.HeaderContentType, echo.MIMEApplicationJSON).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("bad request (unknown provider)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path, wh2.GetID()).
+			WithQuery("provider", "unknown").
+			WithBytes([]byte(githubPayload)).
+			WithHeader(echo.HeaderContentType, echo.MIMEApplicationJSON).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("success (github, ignored event)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		body := `{"zen":"test"}`
+		e.POST(path, wh2.GetID()).
+			WithHeader("X-GitHub-Event", "ping").
+			WithHeader("X-Hub-Signature-256", "sha256="+calcHMACSHA256(t, body, wh2.GetSecret())).
+			WithBytes([]byte(body)).
+			WithHeader(echo.HeaderContentType, echo.MIMEApplicationJSON).
+			Expect().
+			Status(http.StatusNoContent)
+	})
+
+	t.Run("success (github)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path, wh2.GetID()).
+			WithHeader("X-GitHub-Event", "issues").
+			WithHeader("X-Hub-Signature-256", "sha256="+calcHMACSHA256(t, githubPayload, wh2.GetSecret())).
+			WithBytes([]byte(githubPayload)).
+			WithHeader(echo.HeaderContentType, echo.MIMEApplicationJSON).
+			Expect().
+			Status(http.StatusNoContent)
+
+		tl, err := env.MM.GetTimeline(message.TimelineQuery{Channel: ch3.ID})
+		require.NoError(t, err)
+		if assert.Len(t, tl.Records(), 1) {
+			m := tl.Records()[0]
+			assert.EqualValues(t, wh2.GetBotUserID(), m.GetUserID())
+			assert.EqualValues(t, "### [traPtitech/traQ](https://github.com/traPtitech/traQ) Issue opened: [#1 bug](https://github.com/traPtitech/traQ/issues/1)\nby takashi", m.GetText())
+		}
+	})
+
+	t.Run("bad request (json, malformed payload)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path, wh3.GetID()).
+			WithHeader("X-TRAQ-Signature", calcHMACSHA1(t, "{", wh3.GetSecret())).
+			WithBytes([]byte("{")).
+			WithHeader(echo.HeaderContentType, echo.MIMEApplicationJSON).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("success (json)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		body := `{"title":"test","body":"po"}`
+		e.POST(path, wh3.GetID()).
+			WithHeader("X-TRAQ-Signature", calcHMACSHA1(t, body, wh3.GetSecret())).
+			WithBytes([]byte(body)).
+			WithHeader(echo.HeaderContentType, echo.MIMEApplicationJSON).
+			Expect().
+			Status(http.StatusNoContent)
+
+		tl, err := env.MM.GetTimeline(message.TimelineQuery{Channel: ch4.ID})
+		require.NoError(t, err)
+		if assert.Len(t, tl.Records(), 1) {
+			m := tl.Records()[0]
+			assert.EqualValues(t, wh3.GetBotUserID(), m.GetUserID())
+			assert.EqualValues(t, "test: po", m.GetText())
+		}
+	})
+
 	t.Run("success with X-TRAQ-Channel-Id", func(t *testing.T) {
 		t.Parallel()
 		e := env.R(t)
@@ -447,6 +578,90 @@ func TestHandlers_PostWebhook(t *testing.T) {
 	})
 }
 
+func TestHandlers_PostWebhookDryRun(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/webhooks/{webhookId}/dry-run"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	user2 := env.CreateUser(t, rand)
+	ch := env.CreateChannel(t, rand)
+	wh := env.CreateWebhook(t, rand, user.GetID(), ch.ID)
+	wh2 := env.CreateWebhook(t, rand, user.GetID(), ch.ID)
+	require.NoError(t, env.Repository.UpdateWebhook(wh2.GetID(), repository.UpdateWebhookArgs{PayloadTemplate: optional.StringFrom("{{ .title }}")}))
+	s := env.S(t, user.GetID())
+	s2 := env.S(t, user2.GetID())
+
+	t.Run("not logged in", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path, wh.GetID()).
+			WithJSON(map[string]interface{}{"payloadTemplate": "{{ .title }}", "payload": map[string]interface{}{"title": "test"}}).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path, wh.GetID()).
+			WithCookie(session.CookieName, s2).
+			WithJSON(map[string]interface{}{"payloadTemplate": "{{ .title }}", "payload": map[string]interface{}{"title": "test"}}).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
+	t.Run("bad request (template is not set)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path, wh.GetID()).
+			WithCookie(session.CookieName, s).
+			WithJSON(map[string]interface{}{"payload": map[string]interface{}{"title": "test"}}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("bad request (invalid template)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path, wh.GetID()).
+			WithCookie(session.CookieName, s).
+			WithJSON(map[string]interface{}{"payloadTemplate": "{{ .title ", "payload": map[string]interface{}{"title": "test"}}).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path, wh.GetID()).
+			WithCookie(session.CookieName, s).
+			WithJSON(map[string]interface{}{"payloadTemplate": "{{ .title }}!", "payload": map[string]interface{}{"title": "test"}}).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Object().
+			Value("content").
+			String().
+			Equal("test!")
+	})
+
+	t.Run("success (saved template)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.POST(path, wh2.GetID()).
+			WithCookie(session.CookieName, s).
+			WithJSON(map[string]interface{}{"payload": map[string]interface{}{"title": "test"}}).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Object().
+			Value("content").
+			String().
+			Equal("test")
+	})
+}
+
 func TestHandlers_DeleteWebhook(t *testing.T) {
 	t.Parallel()
 
@@ -499,6 +714,8 @@ func TestHandlers_GetWebhookMessages(t *testing.T) {
 	wh := env.CreateWebhook(t, rand, user.GetID(), ch.ID)
 	m := env.CreateMessage(t, wh.GetBotUserID(), ch.ID, "test")
 	s := env.S(t, user.GetID())
+	client := env.CreateOAuth2Client(t, rand, user.GetID())
+	token := env.IssueChannelRestrictedToken(t, client, user.GetID(), ch.ID)
 
 	t.Run("not logged in", func(t *testing.T) {
 		t.Parallel()
@@ -508,6 +725,15 @@ func TestHandlers_GetWebhookMessages(t *testing.T) {
 			Status(http.StatusUnauthorized)
 	})
 
+	t.Run("forbidden (restricted token)", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET(path, wh.GetID()).
+			WithHeader(echo.HeaderAuthorization, "Bearer "+token.AccessToken).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
 	t.Run("bad request", func(t *testing.T) {
 		t.Parallel()
 		e := env.R(t)
@@ -541,3 +767,65 @@ func TestHandlers_GetWebhookMessages(t *testing.T) {
 		messageEquals(t, m, obj.First().Object())
 	})
 }
+
+func TestHandlers_GetWebhookLogs(t *testing.T) {
+	t.Parallel()
+
+	path := "/api/v3/webhooks/{webhookId}/logs"
+	env := Setup(t, common1)
+	user := env.CreateUser(t, rand)
+	user2 := env.CreateUser(t, rand)
+	ch := env.CreateChannel(t, rand)
+	wh := env.CreateWebhook(t, rand, user.GetID(), ch.ID)
+	s := env.S(t, user.GetID())
+	s2 := env.S(t, user2.GetID())
+
+	t.Run("not logged in", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET(path, wh.GetID()).
+			Expect().
+			Status(http.StatusUnauthorized)
+	})
+
+	t.Run("bad request", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET(path, wh.GetID()).
+			WithCookie(session.CookieName, s).
+			WithQuery("limit", 500).
+			Expect().
+			Status(http.StatusBadRequest)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET(path, wh.GetID()).
+			WithCookie(session.CookieName, s2).
+			Expect().
+			Status(http.StatusForbidden)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET(path, uuid.Must(uuid.NewV4())).
+			WithCookie(session.CookieName, s).
+			Expect().
+			Status(http.StatusNotFound)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		e := env.R(t)
+		e.GET(path, wh.GetID()).
+			WithCookie(session.CookieName, s).
+			Expect().
+			Status(http.StatusOK).
+			JSON().
+			Array().
+			Length().
+			Equal(0)
+	})
+}
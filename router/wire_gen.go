@@ -10,6 +10,7 @@ import (
 	"github.com/leandro-lugaresi/hub"
 	"github.com/traPtitech/traQ/repository"
 	"github.com/traPtitech/traQ/router/oauth2"
+	"github.com/traPtitech/traQ/router/scim"
 	"github.com/traPtitech/traQ/router/session"
 	"github.com/traPtitech/traQ/router/utils"
 	"github.com/traPtitech/traQ/router/v1"
@@ -52,6 +53,9 @@ func newRouter(hub2 *hub.Hub, db *gorm.DB, repo repository.Repository, ss *servi
 	webrtcv3Manager := ss.WebRTCv3
 	processor := ss.Imaging
 	engine := ss.Search
+	rateLimitStore := ss.RateLimitStore
+	autoarchiveService := ss.AutoArchive
+	retentionService := ss.Retention
 	v3Config := provideV3Config(config)
 	v3Handlers := &v3.Handlers{
 		RBAC:           rbac,
@@ -71,6 +75,9 @@ func newRouter(hub2 *hub.Hub, db *gorm.DB, repo repository.Repository, ss *servi
 		MessageManager: messageManager,
 		FileManager:    fileManager,
 		Replacer:       replacer,
+		RateLimitStore: rateLimitStore,
+		AutoArchive:    autoarchiveService,
+		Retention:      retentionService,
 		Config:         v3Config,
 	}
 	oauth2Config := provideOAuth2Config(config)
@@ -81,12 +88,20 @@ func newRouter(hub2 *hub.Hub, db *gorm.DB, repo repository.Repository, ss *servi
 		SessStore: store,
 		Config:    oauth2Config,
 	}
+	scimConfig := provideSCIMConfig(config)
+	scimHandler := &scim.Handler{
+		Repo:        repo,
+		FileManager: fileManager,
+		Logger:      logger,
+		Config:      scimConfig,
+	}
 	router := &Router{
 		e:         echo,
 		sessStore: store,
 		v1:        handlers,
 		v3:        v3Handlers,
 		oauth2:    handler,
+		scim:      scimHandler,
 	}
 	return router
 }
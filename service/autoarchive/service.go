@@ -0,0 +1,60 @@
+package autoarchive
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/traPtitech/traQ/utils/optional"
+)
+
+// Config 自動アーカイブ設定
+type Config struct {
+	// Enabled 自動アーカイブを定期実行するかどうか
+	Enabled bool
+	// InactivePeriod この期間アクティビティの無い公開チャンネルをアーカイブの対象にします
+	InactivePeriod time.Duration
+	// GracePeriod アーカイブ予告からアーカイブまでの猶予期間
+	GracePeriod time.Duration
+	// NotifierName アーカイブ予告メッセージの投稿・アーカイブを行うユーザーの名前
+	NotifierName string
+}
+
+// Action 自動アーカイブでチャンネルに対して行う操作
+type Action string
+
+const (
+	// ActionWarn アーカイブ予告メッセージを投稿します
+	ActionWarn Action = "warn"
+	// ActionWait アーカイブ予告済みで、猶予期間の終了を待ちます
+	ActionWait Action = "wait"
+	// ActionArchive チャンネルをアーカイブします
+	ActionArchive Action = "archive"
+	// ActionCancel アーカイブ予告を取り消します
+	ActionCancel Action = "cancel"
+)
+
+// Candidate 自動アーカイブでの操作対象のチャンネル
+type Candidate struct {
+	// ChannelID チャンネルUUID
+	ChannelID uuid.UUID
+	// Action 行う操作
+	Action Action
+	// LastActivity チャンネルの最終アクティビティ日時
+	LastActivity time.Time
+	// WarnedAt アーカイブ予告日時
+	WarnedAt optional.Time
+	// ArchiveAt アーカイブ予定日時
+	ArchiveAt optional.Time
+}
+
+// Service 非アクティブチャンネルの自動アーカイブサービス
+type Service interface {
+	// Report 現時点で自動アーカイブを実行した場合の操作対象の一覧を返します
+	//
+	// 実際の操作は行いません。
+	Report() ([]*Candidate, error)
+	// Shutdown 自動アーカイブサービスをシャットダウンします
+	Shutdown(ctx context.Context) error
+}
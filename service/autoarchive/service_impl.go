@@ -0,0 +1,258 @@
+package autoarchive
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/lthibault/jitterbug/v2"
+	"go.uber.org/zap"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/service/channel"
+	"github.com/traPtitech/traQ/service/message"
+	"github.com/traPtitech/traQ/utils/optional"
+)
+
+type serviceImpl struct {
+	repo   repository.Repository
+	cm     channel.Manager
+	mm     message.Manager
+	logger *zap.Logger
+	config Config
+
+	ticker      *jitterbug.Ticker
+	serviceDone chan struct{}
+	tickerDone  chan struct{}
+}
+
+// NewService 自動アーカイブサービスを生成します
+//
+// config.Enabledがtrueの場合、定期実行を開始します。
+func NewService(repo repository.Repository, cm channel.Manager, mm message.Manager, logger *zap.Logger, config Config) Service {
+	s := &serviceImpl{
+		repo:   repo,
+		cm:     cm,
+		mm:     mm,
+		logger: logger.Named("autoarchive"),
+		config: config,
+
+		serviceDone: make(chan struct{}),
+		tickerDone:  make(chan struct{}),
+	}
+	if config.Enabled {
+		s.start()
+	} else {
+		close(s.tickerDone)
+	}
+	return s
+}
+
+func (s *serviceImpl) start() {
+	s.ticker = jitterbug.New(time.Hour, &jitterbug.Uniform{
+		Min: time.Minute * 50,
+	})
+	go func() {
+		defer close(s.tickerDone)
+		for {
+			select {
+			case _, ok := <-s.ticker.C:
+				if !ok {
+					return
+				}
+				if err := s.run(); err != nil {
+					s.logger.Error("an error occurred while archiving inactive channels", zap.Error(err))
+				}
+			case <-s.serviceDone:
+				return
+			}
+		}
+	}()
+}
+
+func (s *serviceImpl) Shutdown(ctx context.Context) error {
+	if s.ticker != nil {
+		s.ticker.Stop()
+	}
+	close(s.serviceDone)
+	select {
+	case <-s.tickerDone:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *serviceImpl) Report() ([]*Candidate, error) {
+	return s.candidates(time.Now())
+}
+
+// run 自動アーカイブを実行します
+func (s *serviceImpl) run() error {
+	notifier, err := s.repo.GetUserByName(s.config.NotifierName, false)
+	if err != nil {
+		return fmt.Errorf("failed to get notifier user: %w", err)
+	}
+	candidates, err := s.candidates(time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, c := range candidates {
+		var err error
+		switch c.Action {
+		case ActionWarn:
+			err = s.warn(c, notifier.GetID())
+		case ActionArchive:
+			if err = s.cm.ArchiveChannel(c.ChannelID, notifier.GetID()); err == nil {
+				err = s.repo.SetChannelArchivalWarnedAt(c.ChannelID, optional.Time{})
+			}
+		case ActionCancel:
+			err = s.repo.SetChannelArchivalWarnedAt(c.ChannelID, optional.Time{})
+		}
+		if err != nil {
+			s.logger.Error("failed to process inactive channel", zap.Stringer("channelID", c.ChannelID), zap.String("action", string(c.Action)), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// warn アーカイブ予告メッセージを投稿します
+func (s *serviceImpl) warn(c *Candidate, notifierID uuid.UUID) error {
+	archiveAt := time.Now().Add(s.config.GracePeriod)
+	text := fmt.Sprintf(
+		"このチャンネルには%d日以上アクティビティがありません。%sまでにメッセージが投稿されない場合、このチャンネルとその子チャンネルは自動的にアーカイブされます。",
+		int(s.config.InactivePeriod.Hours()/24),
+		archiveAt.Format("2006/01/02"),
+	)
+	m, err := s.mm.Create(c.ChannelID, notifierID, text)
+	if err != nil {
+		return fmt.Errorf("failed to post warning message: %w", err)
+	}
+	return s.repo.SetChannelArchivalWarnedAt(c.ChannelID, optional.TimeFrom(m.GetCreatedAt()))
+}
+
+// candidates 現時点での操作対象のチャンネルを取得します
+func (s *serviceImpl) candidates(now time.Time) ([]*Candidate, error) {
+	channels, err := s.repo.GetPublicChannels()
+	if err != nil {
+		return nil, fmt.Errorf("failed to GetPublicChannels: %w", err)
+	}
+	latests, err := s.repo.GetAllChannelLatestMessages()
+	if err != nil {
+		return nil, fmt.Errorf("failed to GetAllChannelLatestMessages: %w", err)
+	}
+	states, err := s.repo.GetChannelArchivalStates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to GetChannelArchivalStates: %w", err)
+	}
+	return plan(channels, latests, states, now, s.config), nil
+}
+
+// plan 各チャンネルに対して行う操作を決定します
+//
+// チャンネルをアーカイブすると子孫チャンネルも全てアーカイブされるため、
+// 子孫チャンネルを含めて全てアーカイブ可能な場合のみアーカイブの対象にします。
+// アーカイブ予告は、対象となるチャンネルのうち最も上位のチャンネルに対して行います。
+// 結果は最終アクティビティ日時の昇順です。
+func plan(channels []*model.Channel, latests []*model.ChannelLatestMessage, states []*model.ChannelArchivalState, now time.Time, config Config) []*Candidate {
+	var (
+		chMap       = make(map[uuid.UUID]*model.Channel, len(channels))
+		children    = make(map[uuid.UUID][]uuid.UUID, len(channels))
+		latestMap   = make(map[uuid.UUID]time.Time, len(latests))
+		stateMap    = make(map[uuid.UUID]*model.ChannelArchivalState, len(states))
+		eligibleMap = make(map[uuid.UUID]bool, len(channels))
+		threshold   = now.Add(-config.InactivePeriod)
+	)
+	for _, ch := range channels {
+		if ch.IsArchived() {
+			continue
+		}
+		chMap[ch.ID] = ch
+	}
+	for _, ch := range chMap {
+		if _, ok := chMap[ch.ParentID]; ok {
+			children[ch.ParentID] = append(children[ch.ParentID], ch.ID)
+		}
+	}
+	for _, l := range latests {
+		latestMap[l.ChannelID] = l.DateTime
+	}
+	for _, s := range states {
+		stateMap[s.ChannelID] = s
+	}
+
+	lastActivity := func(ch *model.Channel) time.Time {
+		if t, ok := latestMap[ch.ID]; ok && t.After(ch.UpdatedAt) {
+			return t
+		}
+		return ch.UpdatedAt
+	}
+	isInactive := func(ch *model.Channel) bool {
+		if s := stateMap[ch.ID]; s.IsWarned() {
+			// 予告メッセージ以降にアクティビティがあるかどうか
+			return !lastActivity(ch).After(s.WarnedAt.Time)
+		}
+		return lastActivity(ch).Before(threshold)
+	}
+	var isEligible func(id uuid.UUID) bool
+	isEligible = func(id uuid.UUID) bool {
+		if v, ok := eligibleMap[id]; ok {
+			return v
+		}
+		ch := chMap[id]
+		s := stateMap[id]
+		v := !ch.IsForced && (s == nil || !s.Exempt) && isInactive(ch)
+		for _, cid := range children[id] {
+			if !isEligible(cid) {
+				v = false
+			}
+		}
+		eligibleMap[id] = v
+		return v
+	}
+
+	result := make([]*Candidate, 0)
+	for id, ch := range chMap {
+		c := &Candidate{
+			ChannelID:    id,
+			LastActivity: lastActivity(ch),
+		}
+		state := stateMap[id]
+		switch {
+		case state.IsWarned():
+			c.WarnedAt = state.WarnedAt
+			archiveAt := state.WarnedAt.Time.Add(config.GracePeriod)
+			switch {
+			case !isEligible(id):
+				c.Action = ActionCancel
+			case now.Before(archiveAt):
+				c.Action = ActionWait
+				c.ArchiveAt = optional.TimeFrom(archiveAt)
+			default:
+				c.Action = ActionArchive
+				c.ArchiveAt = optional.TimeFrom(archiveAt)
+			}
+		case isEligible(id):
+			if _, ok := chMap[ch.ParentID]; ok && isEligible(ch.ParentID) {
+				continue // 親チャンネルと共にアーカイブされる
+			}
+			c.Action = ActionWarn
+			c.ArchiveAt = optional.TimeFrom(now.Add(config.GracePeriod))
+		default:
+			continue
+		}
+		result = append(result, c)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].LastActivity.Equal(result[j].LastActivity) {
+			return result[i].LastActivity.Before(result[j].LastActivity)
+		}
+		return result[i].ChannelID.String() < result[j].ChannelID.String()
+	})
+	return result
+}
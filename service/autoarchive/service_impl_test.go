@@ -0,0 +1,102 @@
+package autoarchive
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/utils/optional"
+)
+
+func TestPlan(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now    = time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)
+		config = Config{
+			InactivePeriod: time.Hour * 24 * 180,
+			GracePeriod:    time.Hour * 24 * 14,
+		}
+		old    = now.Add(-time.Hour * 24 * 365)
+		recent = now.Add(-time.Hour * 24)
+	)
+	newChannel := func(parent uuid.UUID, updatedAt time.Time) *model.Channel {
+		return &model.Channel{
+			ID:        uuid.Must(uuid.NewV4()),
+			ParentID:  parent,
+			IsPublic:  true,
+			IsVisible: true,
+			UpdatedAt: updatedAt,
+		}
+	}
+
+	var (
+		active         = newChannel(uuid.Nil, old)
+		inactive       = newChannel(uuid.Nil, old)
+		parent         = newChannel(uuid.Nil, old)
+		child          = newChannel(parent.ID, old)
+		activeParent   = newChannel(uuid.Nil, old)
+		activeChild    = newChannel(activeParent.ID, recent)
+		forced         = newChannel(uuid.Nil, old)
+		exempt         = newChannel(uuid.Nil, old)
+		archived       = newChannel(uuid.Nil, old)
+		archivedParent = newChannel(uuid.Nil, old)
+		archivedChild  = newChannel(archivedParent.ID, recent)
+		waiting        = newChannel(uuid.Nil, old)
+		expired        = newChannel(uuid.Nil, old)
+		replied        = newChannel(uuid.Nil, old)
+		exemptWarned   = newChannel(uuid.Nil, old)
+	)
+	forced.IsForced = true
+	archived.IsVisible = false
+	archivedChild.IsVisible = false
+
+	warnedAt := func(t time.Time) optional.Time { return optional.TimeFrom(t) }
+	channels := []*model.Channel{active, inactive, parent, child, activeParent, activeChild, forced, exempt, archived, archivedParent, archivedChild, waiting, expired, replied, exemptWarned}
+	latests := []*model.ChannelLatestMessage{
+		{ChannelID: active.ID, DateTime: recent},
+		{ChannelID: inactive.ID, DateTime: old.Add(time.Hour)},
+		{ChannelID: waiting.ID, DateTime: now.Add(-time.Hour * 24 * 3)},
+		{ChannelID: expired.ID, DateTime: now.Add(-time.Hour * 24 * 15)},
+		{ChannelID: replied.ID, DateTime: now.Add(-time.Hour)},
+	}
+	states := []*model.ChannelArchivalState{
+		{ChannelID: exempt.ID, Exempt: true},
+		{ChannelID: waiting.ID, WarnedAt: warnedAt(now.Add(-time.Hour * 24 * 3))},
+		{ChannelID: expired.ID, WarnedAt: warnedAt(now.Add(-time.Hour * 24 * 15))},
+		{ChannelID: replied.ID, WarnedAt: warnedAt(now.Add(-time.Hour * 24 * 3))},
+		{ChannelID: exemptWarned.ID, Exempt: true, WarnedAt: warnedAt(now.Add(-time.Hour * 24 * 3))},
+	}
+
+	result := plan(channels, latests, states, now, config)
+	actions := make(map[uuid.UUID]Action, len(result))
+	for _, c := range result {
+		actions[c.ChannelID] = c.Action
+	}
+	assert.Equal(t, map[uuid.UUID]Action{
+		inactive.ID:       ActionWarn,
+		parent.ID:         ActionWarn,
+		archivedParent.ID: ActionWarn,
+		waiting.ID:        ActionWait,
+		expired.ID:        ActionArchive,
+		replied.ID:        ActionCancel,
+		exemptWarned.ID:   ActionCancel,
+	}, actions)
+
+	for i := 1; i < len(result); i++ {
+		assert.False(t, result[i].LastActivity.Before(result[i-1].LastActivity))
+	}
+	for _, c := range result {
+		switch c.ChannelID {
+		case inactive.ID:
+			assert.Equal(t, old.Add(time.Hour), c.LastActivity)
+			assert.Equal(t, now.Add(config.GracePeriod), c.ArchiveAt.Time)
+		case waiting.ID:
+			assert.Equal(t, now.Add(-time.Hour*24*3), c.WarnedAt.Time)
+			assert.Equal(t, now.Add(time.Hour*24*11), c.ArchiveAt.Time)
+		}
+	}
+}
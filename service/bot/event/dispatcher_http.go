@@ -20,21 +20,28 @@ const (
 	ua                             = "traQ_Bot_Processor/1.0"
 )
 
+// NewHTTPClient BOTイベント配送用のHTTPクライアントを生成します
+//
+// タイムアウトは5秒で、リダイレクトには従いません。
+func NewHTTPClient() *http.Client {
+	return &http.Client{
+		Jar:     nil,
+		Timeout: 5 * time.Second,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
 type httpDispatcher struct {
-	client http.Client
+	client *http.Client
 	l      *zap.Logger
 }
 
 func newHTTPDispatcher(logger *zap.Logger) *httpDispatcher {
 	return &httpDispatcher{
-		client: http.Client{
-			Jar:     nil,
-			Timeout: 5 * time.Second,
-			CheckRedirect: func(req *http.Request, via []*http.Request) error {
-				return http.ErrUseLastResponse
-			},
-		},
-		l: logger.Named("bot.dispatcher.http"),
+		client: NewHTTPClient(),
+		l:      logger.Named("bot.dispatcher.http"),
 	}
 }
 
@@ -12,6 +12,7 @@ import (
 	"github.com/traPtitech/traQ/service/bot/event"
 	"github.com/traPtitech/traQ/service/bot/event/payload"
 	"github.com/traPtitech/traQ/utils/message"
+	"github.com/traPtitech/traQ/utils/set"
 )
 
 func MessageCreated(ctx Context, datetime time.Time, _ string, fields hub.Fields) error {
@@ -57,6 +58,24 @@ func MessageCreated(ctx Context, datetime time.Time, _ string, fields hub.Fields
 		); err != nil {
 			return fmt.Errorf("failed to unicast: %w", err)
 		}
+	} else if ch.IsGroupDMChannel() {
+		bots, err := ctx.GetChannelBots(m.ChannelID, event.DirectMessageCreated)
+		if err != nil {
+			return fmt.Errorf("failed to GetChannelBots: %w", err)
+		}
+
+		bots = filterBotUserIDNotEquals(bots, m.UserID)
+		if len(bots) == 0 {
+			return nil
+		}
+
+		if err := ctx.Multicast(
+			event.DirectMessageCreated,
+			payload.MakeDirectMessageCreated(datetime, m, user, parsed),
+			bots,
+		); err != nil {
+			return fmt.Errorf("failed to multicast: %w", err)
+		}
 	} else {
 		// 購読BOT
 		bots, err := ctx.GetChannelBots(m.ChannelID, event.MessageCreated)
@@ -65,8 +84,20 @@ func MessageCreated(ctx Context, datetime time.Time, _ string, fields hub.Fields
 		}
 
 		// メンションBOT
+		// プライベートチャンネルではメンバーのBOTのみ
+		var members set.UUID
+		if ch.IsPrivateChannel() {
+			ids, err := ctx.CM().GetPrivateChannelMembers(ch.ID)
+			if err != nil {
+				return fmt.Errorf("failed to GetPrivateChannelMembers: %w", err)
+			}
+			members = set.UUIDSetFromArray(ids)
+		}
 		done := make(map[uuid.UUID]bool)
 		for _, uid := range parsed.Mentions {
+			if members != nil && !members.Contains(uid) {
+				continue
+			}
 			if !done[uid] {
 				done[uid] = true
 				b, err := ctx.GetBotByBotUserID(uid)
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"fmt"
 	"testing"
 	"time"
 
@@ -109,6 +110,54 @@ func TestMessageCreated(t *testing.T) {
 		}))
 	})
 
+	t.Run("success (private message, mentioned bot is not a member)", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		handlerCtx, cm, repo := setup(t, ctrl)
+		mb := &model.Bot{
+			ID:              uuid.NewV3(uuid.Nil, "mb"),
+			BotUserID:       uuid.NewV3(uuid.Nil, "mbu"),
+			SubscribeEvents: model.BotEventTypesFromArray([]string{event.MentionMessageCreated.String()}),
+			State:           model.BotActive,
+		}
+		registerBot(t, handlerCtx, mb)
+
+		pch := &model.Channel{
+			ID:       uuid.NewV3(uuid.Nil, "pc"),
+			Name:     "secret",
+			ParentID: uuid.Must(uuid.FromString(model.PrivateChannelRootID)),
+		}
+		m := &model.Message{
+			ID:        uuid.NewV3(uuid.Nil, "m"),
+			UserID:    uuid.NewV3(uuid.Nil, "u"),
+			ChannelID: pch.ID,
+			Text:      fmt.Sprintf(`!{"type":"user","raw":"@bot","id":"%s"}`, mb.BotUserID),
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+		mu := &model.User{
+			ID:   m.UserID,
+			Name: "testman",
+		}
+		registerUser(repo, mu)
+		registerChannel(cm, pch)
+		cm.EXPECT().
+			GetPrivateChannelMembers(pch.ID).
+			Return([]uuid.UUID{m.UserID}, nil).
+			AnyTimes()
+
+		handlerCtx.EXPECT().
+			GetChannelBots(m.ChannelID, event.MessageCreated).
+			Return([]*model.Bot{}, nil).
+			AnyTimes()
+
+		assert.NoError(t, MessageCreated(handlerCtx, time.Now(), intevent.MessageCreated, hub.Fields{
+			"message_id":   m.ID,
+			"message":      m,
+			"parse_result": message.Parse(m.Text),
+		}))
+	})
+
 	t.Run("success (dm)", func(t *testing.T) {
 		t.Parallel()
 		ctrl := gomock.NewController(t)
@@ -160,4 +209,44 @@ func TestMessageCreated(t *testing.T) {
 			"parse_result": parsed,
 		}))
 	})
+
+	t.Run("success (group dm)", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		handlerCtx, cm, repo := setup(t, ctrl)
+
+		gdm := &model.Channel{
+			ID:       uuid.NewV3(uuid.Nil, "gdm"),
+			Name:     "dm_test",
+			ParentID: uuid.Must(uuid.FromString(model.GroupDirectMessageChannelRootID)),
+		}
+		m := &model.Message{
+			ID:        uuid.NewV3(uuid.Nil, "m"),
+			UserID:    uuid.NewV3(uuid.Nil, "u"),
+			ChannelID: gdm.ID,
+			Text:      "test message",
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+		mu := &model.User{
+			ID:   m.UserID,
+			Name: "testman",
+		}
+		registerUser(repo, mu)
+		registerChannel(cm, gdm)
+		parsed := message.Parse(m.Text)
+		et := time.Now()
+
+		handlerCtx.EXPECT().
+			GetChannelBots(m.ChannelID, event.DirectMessageCreated).
+			Return([]*model.Bot{b}, nil).
+			AnyTimes()
+
+		expectMulticast(handlerCtx, event.DirectMessageCreated, payload.MakeDirectMessageCreated(et, m, mu, parsed), []*model.Bot{b})
+		assert.NoError(t, MessageCreated(handlerCtx, et, intevent.MessageCreated, hub.Fields{
+			"message_id":   m.ID,
+			"message":      m,
+			"parse_result": parsed,
+		}))
+	})
 }
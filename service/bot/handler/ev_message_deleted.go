@@ -49,6 +49,24 @@ func MessageDeleted(ctx Context, datetime time.Time, _ string, fields hub.Fields
 		); err != nil {
 			return fmt.Errorf("failed to unicast: %w", err)
 		}
+	} else if ch.IsGroupDMChannel() {
+		bots, err := ctx.GetChannelBots(m.ChannelID, event.DirectMessageDeleted)
+		if err != nil {
+			return fmt.Errorf("failed to GetChannelBots: %w", err)
+		}
+
+		bots = filterBotUserIDNotEquals(bots, m.UserID)
+		if len(bots) == 0 {
+			return nil
+		}
+
+		if err := ctx.Multicast(
+			event.DirectMessageDeleted,
+			payload.MakeDirectMessageDeleted(datetime, m),
+			bots,
+		); err != nil {
+			return fmt.Errorf("failed to multicast: %w", err)
+		}
 	} else {
 		bots, err := ctx.GetChannelBots(m.ChannelID, event.MessageDeleted)
 		if err != nil {
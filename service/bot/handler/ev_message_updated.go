@@ -56,6 +56,24 @@ func MessageUpdated(ctx Context, datetime time.Time, _ string, fields hub.Fields
 		); err != nil {
 			return fmt.Errorf("failed to unicast: %w", err)
 		}
+	} else if ch.IsGroupDMChannel() {
+		bots, err := ctx.GetChannelBots(m.ChannelID, event.DirectMessageUpdated)
+		if err != nil {
+			return fmt.Errorf("failed to GetChannelBots: %w", err)
+		}
+
+		bots = filterBotUserIDNotEquals(bots, m.UserID)
+		if len(bots) == 0 {
+			return nil
+		}
+
+		if err := ctx.Multicast(
+			event.DirectMessageUpdated,
+			payload.MakeDirectMessageUpdated(datetime, m, user, parsed),
+			bots,
+		); err != nil {
+			return fmt.Errorf("failed to multicast: %w", err)
+		}
 	} else {
 		// 購読BOT
 		bots, err := ctx.GetChannelBots(m.ChannelID, event.MessageUpdated)
@@ -15,6 +15,7 @@ import (
 	"github.com/traPtitech/traQ/service/bot/event"
 	botWS "github.com/traPtitech/traQ/service/bot/ws"
 	"github.com/traPtitech/traQ/service/channel"
+	"github.com/traPtitech/traQ/utils/set"
 )
 
 const (
@@ -163,5 +164,33 @@ func (p *serviceImpl) GetBots(event model.BotEventType) ([]*model.Bot, error) {
 }
 
 func (p *serviceImpl) GetChannelBots(cid uuid.UUID, event model.BotEventType) ([]*model.Bot, error) {
+	if !p.cm.IsPublicChannel(cid) {
+		ch, err := p.cm.GetChannel(cid)
+		if err != nil {
+			return nil, err
+		}
+		if ch.IsPrivateChannel() || ch.IsGroupDMChannel() {
+			// プライベートチャンネル・グループDMはメンバーのBOTのみ
+			members, err := p.cm.GetPrivateChannelMembers(cid)
+			if err != nil {
+				return nil, err
+			}
+			bots, err := p.repo.GetBots(repository.BotsQuery{}.Active().Subscribe(event))
+			if err != nil {
+				return nil, err
+			}
+			return filterBotsByUserIDs(bots, set.UUIDSetFromArray(members)), nil
+		}
+	}
 	return p.repo.GetBots(repository.BotsQuery{}.Active().Subscribe(event).CMemberOf(cid))
 }
+
+func filterBotsByUserIDs(bots []*model.Bot, userIDs set.UUID) []*model.Bot {
+	result := make([]*model.Bot, 0, len(bots))
+	for _, bot := range bots {
+		if userIDs.Contains(bot.BotUserID) {
+			result = append(result, bot)
+		}
+	}
+	return result
+}
@@ -12,8 +12,10 @@ const (
 	writeWait          = 5 * time.Second
 	pongWait           = 60 * time.Second
 	pingPeriod         = (pongWait * 9) / 10
-	maxReadMessageSize = 1 << 9 // 512B
+	maxReadMessageSize = 1 << 16 // 64KB
 	messageBufferSize  = 256
+	replayBufferSize   = 100 // BOT毎の再送用バッファの大きさ
+	replayLogLimit     = 200 // イベントログから再送するイベントの最大数
 )
 
 var (
@@ -6,6 +6,9 @@ import (
 
 	"github.com/gofrs/uuid"
 	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+
+	"github.com/traPtitech/traQ/repository"
 )
 
 func (s *session) commandHandler(cmd string) {
@@ -71,6 +74,32 @@ Command:
 
 		_ = s.streamer.webrtc.SetState(s.key, s.userID, cid, sessions)
 
+	case "resume":
+		// resume:{リクエストID}
+		if len(args) != 2 {
+			// 引数が不正
+			s.sendErrorMessage(fmt.Sprintf("invalid args: %s", cmd))
+			break
+		}
+		reqID, err := uuid.FromString(args[1])
+		if err != nil {
+			// リクエストIDが不正
+			s.sendErrorMessage(fmt.Sprintf("invalid id: %s", args[1]))
+			break
+		}
+
+		if err := s.streamer.replay(s, reqID); err != nil {
+			switch err {
+			case repository.ErrNotFound:
+				s.sendErrorMessage(fmt.Sprintf("unknown reqId: %s", args[1]))
+			case ErrBufferIsFull, ErrAlreadyClosed:
+				s.sendErrorMessage("failed to resend events")
+			default:
+				s.streamer.logger.Error("failed to replay events", zap.Error(err), zap.Stringer("userID", s.userID))
+				s.sendErrorMessage("failed to resend events")
+			}
+		}
+
 	default:
 		// 不明なコマンド
 		s.sendErrorMessage(fmt.Sprintf("unknown command: %s", cmd))
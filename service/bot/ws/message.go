@@ -33,8 +33,9 @@ func (m *eventMessage) toJSON() (b []byte) {
 }
 
 type errorMessage struct {
-	Type string      `json:"type"`
-	Body interface{} `json:"body"`
+	Type  string      `json:"type"`
+	ReqID string      `json:"reqId,omitempty"`
+	Body  interface{} `json:"body"`
 }
 
 func makeErrorMessage(b interface{}) (m *errorMessage) {
@@ -48,3 +49,22 @@ func (m *errorMessage) toJSON() (b []byte) {
 	b, _ = json.Marshal(m)
 	return
 }
+
+type responseMessage struct {
+	Type  string      `json:"type"`
+	ReqID string      `json:"reqId"`
+	Body  interface{} `json:"body"`
+}
+
+func makeResponseMessage(reqID string, b interface{}) (m *responseMessage) {
+	return &responseMessage{
+		Type:  "RESPONSE",
+		ReqID: reqID,
+		Body:  b,
+	}
+}
+
+func (m *responseMessage) toJSON() (b []byte) {
+	b, _ = json.Marshal(m)
+	return
+}
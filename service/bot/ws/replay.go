@@ -0,0 +1,61 @@
+package ws
+
+import (
+	"sync"
+
+	"github.com/gofrs/uuid"
+)
+
+// replayBuffer BOT毎の再送用イベントバッファ
+//
+// 直近 replayBufferSize 件のイベントを保持するリングバッファです。
+type replayBuffer struct {
+	mu      sync.Mutex
+	entries []*replayEntry
+	next    int
+	full    bool
+}
+
+type replayEntry struct {
+	reqID uuid.UUID
+	data  []byte
+}
+
+func newReplayBuffer(size int) *replayBuffer {
+	return &replayBuffer{
+		entries: make([]*replayEntry, size),
+	}
+}
+
+// push バッファにイベントを追加します。溢れた場合は最も古いイベントが破棄されます
+func (b *replayBuffer) push(reqID uuid.UUID, data []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.entries[b.next] = &replayEntry{reqID: reqID, data: data}
+	b.next = (b.next + 1) % len(b.entries)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// after 指定したリクエストIDより後のイベントを古い順に返します
+//
+// 指定したリクエストIDのイベントがバッファに存在しない場合、okはfalseになります。
+func (b *replayBuffer) after(reqID uuid.UUID) (res [][]byte, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	start, n := 0, b.next
+	if b.full {
+		start, n = b.next, len(b.entries)
+	}
+	for i := 0; i < n; i++ {
+		e := b.entries[(start+i)%len(b.entries)]
+		if ok {
+			res = append(res, e.data)
+		} else if e.reqID == reqID {
+			ok = true
+		}
+	}
+	return res, ok
+}
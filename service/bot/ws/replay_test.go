@@ -0,0 +1,89 @@
+package ws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/traPtitech/traQ/event"
+	"github.com/traPtitech/traQ/model"
+)
+
+func TestReplayBuffer(t *testing.T) {
+	t.Parallel()
+
+	ids := make([]uuid.UUID, 5)
+	for i := range ids {
+		ids[i] = uuid.Must(uuid.NewV4())
+	}
+
+	t.Run("not full", func(t *testing.T) {
+		t.Parallel()
+		b := newReplayBuffer(4)
+		b.push(ids[0], []byte("0"))
+		b.push(ids[1], []byte("1"))
+		b.push(ids[2], []byte("2"))
+
+		res, ok := b.after(ids[0])
+		if assert.True(t, ok) {
+			assert.Equal(t, [][]byte{[]byte("1"), []byte("2")}, res)
+		}
+		res, ok = b.after(ids[2])
+		if assert.True(t, ok) {
+			assert.Empty(t, res)
+		}
+		_, ok = b.after(ids[3])
+		assert.False(t, ok)
+	})
+
+	t.Run("overflowed", func(t *testing.T) {
+		t.Parallel()
+		b := newReplayBuffer(3)
+		for i, id := range ids {
+			b.push(id, []byte{byte('0' + i)})
+		}
+
+		_, ok := b.after(ids[1])
+		assert.False(t, ok)
+		res, ok := b.after(ids[2])
+		if assert.True(t, ok) {
+			assert.Equal(t, [][]byte{[]byte("3"), []byte("4")}, res)
+		}
+	})
+}
+
+func TestStreamer_ReplayBufferCleanup(t *testing.T) {
+	t.Parallel()
+
+	var (
+		wsBot   = &model.Bot{ID: uuid.Must(uuid.NewV4()), BotUserID: uuid.Must(uuid.NewV4()), Mode: model.BotModeWebSocket}
+		httpBot = &model.Bot{ID: uuid.Must(uuid.NewV4()), BotUserID: uuid.Must(uuid.NewV4()), Mode: model.BotModeHTTP}
+		deleted = &model.Bot{ID: uuid.Must(uuid.NewV4()), BotUserID: uuid.Must(uuid.NewV4()), Mode: model.BotModeWebSocket}
+	)
+
+	h := hub.New()
+	repo := &testRepository{bots: map[uuid.UUID]*model.Bot{wsBot.ID: wsBot, httpBot.ID: httpBot}}
+	s := NewStreamer(h, nil, repo, nil, nil, nil, nil, nil, zap.NewNop())
+	hasBuffer := func(botUserID uuid.UUID) bool {
+		s.replayMu.Lock()
+		defer s.replayMu.Unlock()
+		_, ok := s.replays[botUserID]
+		return ok
+	}
+	for _, b := range []*model.Bot{wsBot, httpBot, deleted} {
+		s.getReplayBuffer(b.BotUserID).push(uuid.Must(uuid.NewV4()), []byte("{}"))
+	}
+
+	h.Publish(hub.Message{Name: event.BotUpdated, Fields: hub.Fields{"bot_id": wsBot.ID}})
+	h.Publish(hub.Message{Name: event.BotUpdated, Fields: hub.Fields{"bot_id": httpBot.ID}})
+	h.Publish(hub.Message{Name: event.BotDeleted, Fields: hub.Fields{"bot_id": deleted.ID, "bot_user_id": deleted.BotUserID}})
+
+	assert.Eventually(t, func() bool {
+		return !hasBuffer(httpBot.BotUserID) && !hasBuffer(deleted.BotUserID)
+	}, time.Second, 10*time.Millisecond)
+	assert.True(t, hasBuffer(wsBot.BotUserID))
+}
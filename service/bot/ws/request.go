@@ -0,0 +1,374 @@
+package ws
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gofrs/uuid"
+	"github.com/gorilla/websocket"
+	jsoniter "github.com/json-iterator/go"
+	"go.uber.org/zap"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/service/message"
+	"github.com/traPtitech/traQ/service/ratelimit"
+	"github.com/traPtitech/traQ/service/rbac/permission"
+	"github.com/traPtitech/traQ/utils/optional"
+)
+
+var (
+	errForbidden      = errors.New("forbidden")
+	errNotFound       = errors.New("not found")
+	errChannelArchive = errors.New("the channel has been archived")
+	errInternal       = errors.New("internal server error")
+)
+
+// request BOTからのリクエスト
+type request struct {
+	Type  string              `json:"type"`
+	ReqID string              `json:"reqId"`
+	Body  jsoniter.RawMessage `json:"body"`
+}
+
+type requestHandler func(s *session, user model.UserInfo, body []byte) (interface{}, error)
+
+var requestHandlers = map[string]requestHandler{
+	"POST_MESSAGE":      postMessageRequestHandler,
+	"EDIT_MESSAGE":      editMessageRequestHandler,
+	"ADD_MESSAGE_STAMP": addMessageStampRequestHandler,
+	"GET_MESSAGES":      getMessagesRequestHandler,
+}
+
+// requestRateLimitRules リクエストの種類毎のレート制限ルール
+//
+// 対応するHTTP APIと同じルールを使い、バケットを共有します。
+var requestRateLimitRules = map[string]ratelimit.Rule{
+	"POST_MESSAGE":      ratelimit.PostMessage,
+	"EDIT_MESSAGE":      ratelimit.PostMessage,
+	"ADD_MESSAGE_STAMP": ratelimit.AddMessageStamp,
+}
+
+func (s *session) requestHandler(data []byte) {
+	var req request
+	if err := json.Unmarshal(data, &req); err != nil {
+		s.sendErrorMessage("invalid request: malformed json")
+		return
+	}
+	if len(req.ReqID) == 0 {
+		s.sendErrorMessage("invalid request: reqId is required")
+		return
+	}
+
+	reqType := strings.ToUpper(req.Type)
+	h, ok := requestHandlers[reqType]
+	if !ok {
+		s.sendRequestErrorMessage(req.ReqID, fmt.Sprintf("unknown request type: %s", req.Type))
+		return
+	}
+
+	user, err := s.streamer.repo.GetUser(s.userID, false)
+	if err != nil {
+		s.streamer.logger.Error("failed to GetUser", zap.Error(err), zap.Stringer("userID", s.userID))
+		s.sendRequestErrorMessage(req.ReqID, errInternal.Error())
+		return
+	}
+	if !user.IsActive() {
+		s.sendRequestErrorMessage(req.ReqID, "this account is currently suspended")
+		return
+	}
+	if rule, ok := requestRateLimitRules[reqType]; ok {
+		if retryAfter, err := s.takeRateLimit(user, rule); err != nil {
+			s.streamer.logger.Error("failed to take rate limit", zap.Error(err), zap.Stringer("userID", s.userID))
+			s.sendRequestErrorMessage(req.ReqID, errInternal.Error())
+			return
+		} else if retryAfter > 0 {
+			s.sendRequestErrorMessage(req.ReqID, fmt.Sprintf("rate limit exceeded: retry after %d seconds", int64(math.Ceil(retryAfter.Seconds()))))
+			return
+		}
+	}
+
+	res, err := h(s, user, req.Body)
+	if err != nil {
+		var vErr vd.Errors
+		switch {
+		case errors.As(err, &vErr):
+			s.sendRequestErrorMessage(req.ReqID, fmt.Sprintf("invalid body: %s", vErr.Error()))
+		case err == errForbidden, err == errNotFound, err == errChannelArchive:
+			s.sendRequestErrorMessage(req.ReqID, err.Error())
+		default:
+			s.streamer.logger.Error("an error occurred while processing request", zap.Error(err), zap.String("type", req.Type), zap.Stringer("userID", s.userID))
+			s.sendRequestErrorMessage(req.ReqID, errInternal.Error())
+		}
+		return
+	}
+
+	_ = s.WriteMessage(&rawMessage{
+		t:    websocket.TextMessage,
+		data: makeResponseMessage(req.ReqID, res).toJSON(),
+	})
+}
+
+func (s *session) sendRequestErrorMessage(reqID string, error string) {
+	m := makeErrorMessage(error)
+	m.ReqID = reqID
+	_ = s.WriteMessage(&rawMessage{
+		t:    websocket.TextMessage,
+		data: m.toJSON(),
+	})
+}
+
+// takeRateLimit ユーザーのレート制限のバケットからトークンを1つ取り出します
+//
+// 制限を超えている場合、次にリクエストが可能になるまでの時間を返します。
+func (s *session) takeRateLimit(user model.UserInfo, rule ratelimit.Rule) (time.Duration, error) {
+	if s.streamer.limiter == nil {
+		return 0, nil
+	}
+	rate := rule.RateFor(user.GetRole())
+	if !rate.Valid() {
+		return 0, nil
+	}
+	res, err := s.streamer.limiter.Take(rule.Key(ratelimit.UserKey(user.GetID())), rate)
+	if err != nil {
+		return 0, err
+	}
+	if res.Allowed {
+		return 0, nil
+	}
+	return res.RetryAfter, nil
+}
+
+func (s *session) checkPermissions(user model.UserInfo, perms ...permission.Permission) error {
+	for _, p := range perms {
+		if !s.streamer.rbac.IsGranted(user.GetRole(), p) {
+			return errForbidden
+		}
+	}
+	return nil
+}
+
+func (s *session) checkChannelAccessible(channelID uuid.UUID) error {
+	ok, err := s.streamer.cm.IsChannelAccessibleToUser(s.userID, channelID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errNotFound
+	}
+	return nil
+}
+
+func (s *session) getAccessibleMessage(messageID uuid.UUID) (message.Message, error) {
+	m, err := s.streamer.mm.Get(messageID)
+	if err != nil {
+		if err == message.ErrNotFound {
+			return nil, errNotFound
+		}
+		return nil, err
+	}
+	if err := s.checkChannelAccessible(m.GetChannelID()); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func convertMessageError(err error) error {
+	switch err {
+	case message.ErrNotFound:
+		return errNotFound
+	case message.ErrChannelArchived:
+		return errChannelArchive
+	default:
+		return err
+	}
+}
+
+type postMessageRequest struct {
+	ChannelID uuid.UUID `json:"channelId"`
+	Content   string    `json:"content"`
+	Embed     bool      `json:"embed"`
+}
+
+func (r postMessageRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.ChannelID, vd.Required),
+		vd.Field(&r.Content, vd.Required, vd.RuneLength(1, 10000)),
+	)
+}
+
+// postMessageRequestHandler POST_MESSAGE POST /channels/:channelID/messages 相当
+func postMessageRequestHandler(s *session, user model.UserInfo, body []byte) (interface{}, error) {
+	var req postMessageRequest
+	if err := unmarshalAndValidate(body, &req); err != nil {
+		return nil, err
+	}
+	if err := s.checkPermissions(user, permission.PostMessage); err != nil {
+		return nil, err
+	}
+	if err := s.checkChannelAccessible(req.ChannelID); err != nil {
+		return nil, err
+	}
+
+	if req.Embed {
+		req.Content = s.streamer.replacer.Replace(req.Content)
+	}
+	m, err := s.streamer.mm.Create(req.ChannelID, user.GetID(), req.Content)
+	if err != nil {
+		return nil, convertMessageError(err)
+	}
+	return m, nil
+}
+
+type editMessageRequest struct {
+	MessageID uuid.UUID `json:"messageId"`
+	Content   string    `json:"content"`
+	Embed     bool      `json:"embed"`
+}
+
+func (r editMessageRequest) Validate() error {
+	return vd.ValidateStruct(&r,
+		vd.Field(&r.MessageID, vd.Required),
+		vd.Field(&r.Content, vd.Required, vd.RuneLength(1, 10000)),
+	)
+}
+
+// editMessageRequestHandler EDIT_MESSAGE PUT /messages/:messageID 相当
+func editMessageRequestHandler(s *session, user model.UserInfo, body []byte) (interface{}, error) {
+	var req editMessageRequest
+	if err := unmarshalAndValidate(body, &req); err != nil {
+		return nil, err
+	}
+	if err := s.checkPermissions(user, permission.EditMessage); err != nil {
+		return nil, err
+	}
+	m, err := s.getAccessibleMessage(req.MessageID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 他人のテキストは編集できない
+	if m.GetUserID() != user.GetID() {
+		return nil, errForbidden
+	}
+
+	if req.Embed {
+		req.Content = s.streamer.replacer.Replace(req.Content)
+	}
+	if err := s.streamer.mm.Edit(m.GetID(), req.Content); err != nil {
+		return nil, convertMessageError(err)
+	}
+	return nil, nil
+}
+
+type addMessageStampRequest struct {
+	MessageID uuid.UUID `json:"messageId"`
+	StampID   uuid.UUID `json:"stampId"`
+	Count     int       `json:"count"`
+}
+
+func (r *addMessageStampRequest) Validate() error {
+	if r.Count == 0 {
+		r.Count = 1
+	}
+	return vd.ValidateStruct(r,
+		vd.Field(&r.MessageID, vd.Required),
+		vd.Field(&r.StampID, vd.Required),
+		vd.Field(&r.Count, vd.Required, vd.Min(1), vd.Max(100)),
+	)
+}
+
+// addMessageStampRequestHandler ADD_MESSAGE_STAMP POST /messages/:messageID/stamps/:stampID 相当
+func addMessageStampRequestHandler(s *session, user model.UserInfo, body []byte) (interface{}, error) {
+	var req addMessageStampRequest
+	if err := unmarshalAndValidate(body, &req); err != nil {
+		return nil, err
+	}
+	if err := s.checkPermissions(user, permission.AddMessageStamp); err != nil {
+		return nil, err
+	}
+	m, err := s.getAccessibleMessage(req.MessageID)
+	if err != nil {
+		return nil, err
+	}
+	if ok, err := s.streamer.repo.StampExists(req.StampID); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, errNotFound
+	}
+
+	if _, err := s.streamer.mm.AddStamps(m.GetID(), req.StampID, user.GetID(), req.Count); err != nil {
+		return nil, convertMessageError(err)
+	}
+	return nil, nil
+}
+
+type getMessagesRequest struct {
+	ChannelID uuid.UUID     `json:"channelId"`
+	Limit     int           `json:"limit"`
+	Offset    int           `json:"offset"`
+	Since     optional.Time `json:"since"`
+	Until     optional.Time `json:"until"`
+	Inclusive bool          `json:"inclusive"`
+	Order     string        `json:"order"`
+}
+
+func (r *getMessagesRequest) Validate() error {
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
+	return vd.ValidateStruct(r,
+		vd.Field(&r.ChannelID, vd.Required),
+		vd.Field(&r.Limit, vd.Min(1), vd.Max(200)),
+		vd.Field(&r.Offset, vd.Min(0)),
+	)
+}
+
+type getMessagesResponse struct {
+	Messages []message.Message `json:"messages"`
+	HasMore  bool              `json:"hasMore"`
+}
+
+// getMessagesRequestHandler GET_MESSAGES GET /channels/:channelID/messages 相当
+func getMessagesRequestHandler(s *session, user model.UserInfo, body []byte) (interface{}, error) {
+	var req getMessagesRequest
+	if err := unmarshalAndValidate(body, &req); err != nil {
+		return nil, err
+	}
+	if err := s.checkPermissions(user, permission.GetMessage); err != nil {
+		return nil, err
+	}
+	if err := s.checkChannelAccessible(req.ChannelID); err != nil {
+		return nil, err
+	}
+
+	timeline, err := s.streamer.mm.GetTimeline(message.TimelineQuery{
+		Channel:   req.ChannelID,
+		Since:     req.Since,
+		Until:     req.Until,
+		Inclusive: req.Inclusive,
+		Limit:     req.Limit,
+		Offset:    req.Offset,
+		Asc:       strings.ToLower(req.Order) == "asc",
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &getMessagesResponse{
+		Messages: timeline.Records(),
+		HasMore:  timeline.HasMore(),
+	}, nil
+}
+
+func unmarshalAndValidate(body []byte, v vd.Validatable) error {
+	if len(body) == 0 {
+		body = []byte("{}")
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return vd.Errors{"body": vd.NewError("malformed", "malformed json")}
+	}
+	return v.Validate()
+}
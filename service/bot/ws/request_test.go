@@ -0,0 +1,365 @@
+package ws
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/service/channel/mock_channel"
+	"github.com/traPtitech/traQ/service/message"
+	"github.com/traPtitech/traQ/service/ratelimit"
+	"github.com/traPtitech/traQ/service/rbac"
+	"github.com/traPtitech/traQ/service/rbac/permission"
+	mutil "github.com/traPtitech/traQ/utils/message"
+)
+
+type testRepository struct {
+	repository.Repository
+	users map[uuid.UUID]model.UserInfo
+	bots  map[uuid.UUID]*model.Bot
+}
+
+func (r *testRepository) GetBotByID(id uuid.UUID) (*model.Bot, error) {
+	b, ok := r.bots[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return b, nil
+}
+
+func (r *testRepository) GetUser(id uuid.UUID, _ bool) (model.UserInfo, error) {
+	u, ok := r.users[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return u, nil
+}
+
+type testRBAC struct {
+	rbac.RBAC
+	perms map[string][]permission.Permission
+}
+
+func (r *testRBAC) IsGranted(role string, perm permission.Permission) bool {
+	for _, p := range r.perms[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+type testMessage struct {
+	message.Message
+	model *model.Message
+}
+
+func (m *testMessage) GetID() uuid.UUID        { return m.model.ID }
+func (m *testMessage) GetUserID() uuid.UUID    { return m.model.UserID }
+func (m *testMessage) GetChannelID() uuid.UUID { return m.model.ChannelID }
+func (m *testMessage) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{"id": m.model.ID, "content": m.model.Text})
+}
+
+type testMessageManager struct {
+	message.Manager
+	messages         map[uuid.UUID]*testMessage
+	archivedChannel  uuid.UUID
+	errorContentText string
+
+	editedMu sync.Mutex
+	edited   map[uuid.UUID]string
+}
+
+func (m *testMessageManager) Get(id uuid.UUID) (message.Message, error) {
+	msg, ok := m.messages[id]
+	if !ok {
+		return nil, message.ErrNotFound
+	}
+	return msg, nil
+}
+
+func (m *testMessageManager) Create(channelID, userID uuid.UUID, content string) (message.Message, error) {
+	switch {
+	case channelID == m.archivedChannel:
+		return nil, message.ErrChannelArchived
+	case content == m.errorContentText:
+		return nil, errors.New("db error")
+	}
+	return &testMessage{model: &model.Message{ID: uuid.Must(uuid.FromString("d6b3d7f0-0a0e-4a2b-9f4c-6b0f2c4ef1a9")), UserID: userID, ChannelID: channelID, Text: content}}, nil
+}
+
+func (m *testMessageManager) Edit(id uuid.UUID, content string) error {
+	m.editedMu.Lock()
+	defer m.editedMu.Unlock()
+	if m.edited == nil {
+		m.edited = make(map[uuid.UUID]string)
+	}
+	m.edited[id] = content
+	return nil
+}
+
+type testReplaceMapper struct {
+	users map[string]uuid.UUID
+}
+
+func (m *testReplaceMapper) Channel(string) (uuid.UUID, bool) { return uuid.Nil, false }
+func (m *testReplaceMapper) Group(string) (uuid.UUID, bool)   { return uuid.Nil, false }
+func (m *testReplaceMapper) Stamp(string) (string, bool)      { return "", false }
+func (m *testReplaceMapper) User(name string) (uuid.UUID, bool) {
+	id, ok := m.users[name]
+	return id, ok
+}
+
+type testLimiter struct {
+	throttled uuid.UUID
+}
+
+func (l *testLimiter) Take(key string, _ ratelimit.Rate) (ratelimit.Result, error) {
+	if strings.HasSuffix(key, ratelimit.UserKey(l.throttled)) {
+		return ratelimit.Result{RetryAfter: 1500 * time.Millisecond}, nil
+	}
+	return ratelimit.Result{Allowed: true}, nil
+}
+
+func TestSession_requestHandler(t *testing.T) {
+	t.Parallel()
+
+	var (
+		bot       = &model.User{ID: uuid.Must(uuid.NewV4()), Role: "bot", Status: model.UserAccountStatusActive}
+		limited   = &model.User{ID: uuid.Must(uuid.NewV4()), Role: "limited", Status: model.UserAccountStatusActive}
+		suspended = &model.User{ID: uuid.Must(uuid.NewV4()), Role: "bot", Status: model.UserAccountStatusSuspended}
+		throttled = &model.User{ID: uuid.Must(uuid.NewV4()), Role: "bot", Status: model.UserAccountStatusActive}
+		unknown   = uuid.Must(uuid.NewV4())
+
+		accessible   = uuid.Must(uuid.NewV4())
+		inaccessible = uuid.Must(uuid.NewV4())
+		archived     = uuid.Must(uuid.NewV4())
+
+		ownMessage    = &testMessage{model: &model.Message{ID: uuid.Must(uuid.NewV4()), UserID: bot.ID, ChannelID: accessible}}
+		otherMessage  = &testMessage{model: &model.Message{ID: uuid.Must(uuid.NewV4()), UserID: limited.ID, ChannelID: accessible}}
+		hiddenMessage = &testMessage{model: &model.Message{ID: uuid.Must(uuid.NewV4()), UserID: bot.ID, ChannelID: inaccessible}}
+	)
+
+	ctrl := gomock.NewController(t)
+	cm := mock_channel.NewMockManager(ctrl)
+	cm.EXPECT().IsChannelAccessibleToUser(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_, channelID uuid.UUID) (bool, error) { return channelID != inaccessible, nil }).
+		AnyTimes()
+
+	streamer := &Streamer{
+		repo: &testRepository{users: map[uuid.UUID]model.UserInfo{
+			bot.ID:       bot,
+			limited.ID:   limited,
+			suspended.ID: suspended,
+			throttled.ID: throttled,
+		}},
+		cm: cm,
+		mm: &testMessageManager{
+			messages: map[uuid.UUID]*testMessage{
+				ownMessage.model.ID:    ownMessage,
+				otherMessage.model.ID:  otherMessage,
+				hiddenMessage.model.ID: hiddenMessage,
+			},
+			archivedChannel:  archived,
+			errorContentText: "error",
+		},
+		rbac: &testRBAC{perms: map[string][]permission.Permission{
+			"bot": {permission.PostMessage, permission.EditMessage, permission.GetMessage},
+		}},
+		limiter: &testLimiter{throttled: throttled.ID},
+		logger:  zap.NewNop(),
+	}
+
+	tests := []struct {
+		name   string
+		userID uuid.UUID
+		req    string
+		want   string
+	}{
+		{
+			name:   "malformed json",
+			userID: bot.ID,
+			req:    `{"type":`,
+			want:   `{"type":"ERROR","body":"invalid request: malformed json"}`,
+		},
+		{
+			name:   "no reqId",
+			userID: bot.ID,
+			req:    `{"type":"POST_MESSAGE","body":{}}`,
+			want:   `{"type":"ERROR","body":"invalid request: reqId is required"}`,
+		},
+		{
+			name:   "unknown type",
+			userID: bot.ID,
+			req:    `{"type":"DELETE_EVERYTHING","reqId":"1"}`,
+			want:   `{"type":"ERROR","reqId":"1","body":"unknown request type: DELETE_EVERYTHING"}`,
+		},
+		{
+			name:   "unknown user",
+			userID: unknown,
+			req:    `{"type":"POST_MESSAGE","reqId":"1","body":{}}`,
+			want:   `{"type":"ERROR","reqId":"1","body":"internal server error"}`,
+		},
+		{
+			name:   "suspended user",
+			userID: suspended.ID,
+			req:    `{"type":"POST_MESSAGE","reqId":"1","body":{}}`,
+			want:   `{"type":"ERROR","reqId":"1","body":"this account is currently suspended"}`,
+		},
+		{
+			name:   "rate limited",
+			userID: throttled.ID,
+			req:    `{"type":"POST_MESSAGE","reqId":"1","body":{"channelId":"` + accessible.String() + `","content":"a"}}`,
+			want:   `{"type":"ERROR","reqId":"1","body":"rate limit exceeded: retry after 2 seconds"}`,
+		},
+		{
+			name:   "malformed body",
+			userID: bot.ID,
+			req:    `{"type":"POST_MESSAGE","reqId":"1","body":"text"}`,
+			want:   `{"type":"ERROR","reqId":"1","body":"invalid body: body: malformed json."}`,
+		},
+		{
+			name:   "invalid body",
+			userID: bot.ID,
+			req:    `{"type":"POST_MESSAGE","reqId":"1","body":{"channelId":"` + accessible.String() + `"}}`,
+			want:   `{"type":"ERROR","reqId":"1","body":"invalid body: content: cannot be blank."}`,
+		},
+		{
+			name:   "forbidden",
+			userID: limited.ID,
+			req:    `{"type":"POST_MESSAGE","reqId":"1","body":{"channelId":"` + accessible.String() + `","content":"a"}}`,
+			want:   `{"type":"ERROR","reqId":"1","body":"forbidden"}`,
+		},
+		{
+			name:   "inaccessible channel",
+			userID: bot.ID,
+			req:    `{"type":"POST_MESSAGE","reqId":"1","body":{"channelId":"` + inaccessible.String() + `","content":"a"}}`,
+			want:   `{"type":"ERROR","reqId":"1","body":"not found"}`,
+		},
+		{
+			name:   "archived channel",
+			userID: bot.ID,
+			req:    `{"type":"POST_MESSAGE","reqId":"1","body":{"channelId":"` + archived.String() + `","content":"a"}}`,
+			want:   `{"type":"ERROR","reqId":"1","body":"the channel has been archived"}`,
+		},
+		{
+			name:   "internal error",
+			userID: bot.ID,
+			req:    `{"type":"POST_MESSAGE","reqId":"1","body":{"channelId":"` + accessible.String() + `","content":"error"}}`,
+			want:   `{"type":"ERROR","reqId":"1","body":"internal server error"}`,
+		},
+		{
+			name:   "post message",
+			userID: bot.ID,
+			req:    `{"type":"post_message","reqId":"abc","body":{"channelId":"` + accessible.String() + `","content":"hello"}}`,
+			want:   `{"type":"RESPONSE","reqId":"abc","body":{"id":"d6b3d7f0-0a0e-4a2b-9f4c-6b0f2c4ef1a9","content":"hello"}}`,
+		},
+		{
+			name:   "edit own message",
+			userID: bot.ID,
+			req:    `{"type":"EDIT_MESSAGE","reqId":"1","body":{"messageId":"` + ownMessage.model.ID.String() + `","content":"a"}}`,
+			want:   `{"type":"RESPONSE","reqId":"1","body":null}`,
+		},
+		{
+			name:   "edit other's message",
+			userID: bot.ID,
+			req:    `{"type":"EDIT_MESSAGE","reqId":"1","body":{"messageId":"` + otherMessage.model.ID.String() + `","content":"a"}}`,
+			want:   `{"type":"ERROR","reqId":"1","body":"forbidden"}`,
+		},
+		{
+			name:   "edit message in inaccessible channel",
+			userID: bot.ID,
+			req:    `{"type":"EDIT_MESSAGE","reqId":"1","body":{"messageId":"` + hiddenMessage.model.ID.String() + `","content":"a"}}`,
+			want:   `{"type":"ERROR","reqId":"1","body":"not found"}`,
+		},
+		{
+			name:   "edit unknown message",
+			userID: bot.ID,
+			req:    `{"type":"EDIT_MESSAGE","reqId":"1","body":{"messageId":"` + uuid.Must(uuid.NewV4()).String() + `","content":"a"}}`,
+			want:   `{"type":"ERROR","reqId":"1","body":"not found"}`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s := newSession(tt.userID, streamer, nil)
+			s.requestHandler([]byte(tt.req))
+
+			require.Len(t, s.send, 1)
+			res := <-s.send
+			assert.JSONEq(t, tt.want, string(res.data))
+		})
+	}
+}
+
+func TestSession_requestHandler_Embed(t *testing.T) {
+	t.Parallel()
+
+	var (
+		bot     = &model.User{ID: uuid.Must(uuid.NewV4()), Name: "bot", Role: "bot", Status: model.UserAccountStatusActive}
+		channel = uuid.Must(uuid.NewV4())
+		own     = &testMessage{model: &model.Message{ID: uuid.Must(uuid.NewV4()), UserID: bot.ID, ChannelID: channel}}
+
+		embedded = `!{"type":"user","raw":"@bot","id":"` + bot.ID.String() + `"}`
+	)
+
+	ctrl := gomock.NewController(t)
+	cm := mock_channel.NewMockManager(ctrl)
+	cm.EXPECT().IsChannelAccessibleToUser(gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
+
+	mm := &testMessageManager{messages: map[uuid.UUID]*testMessage{own.model.ID: own}}
+	streamer := &Streamer{
+		repo: &testRepository{users: map[uuid.UUID]model.UserInfo{bot.ID: bot}},
+		cm:   cm,
+		mm:   mm,
+		rbac: &testRBAC{perms: map[string][]permission.Permission{
+			"bot": {permission.PostMessage, permission.EditMessage, permission.GetMessage},
+		}},
+		limiter:  &testLimiter{},
+		replacer: mutil.NewReplacer(&testReplaceMapper{users: map[string]uuid.UUID{"bot": bot.ID}}),
+		logger:   zap.NewNop(),
+	}
+	request := func(t *testing.T, req string) string {
+		t.Helper()
+		s := newSession(bot.ID, streamer, nil)
+		s.requestHandler([]byte(req))
+		require.Len(t, s.send, 1)
+		return string((<-s.send).data)
+	}
+
+	t.Run("post message", func(t *testing.T) {
+		res := request(t, `{"type":"POST_MESSAGE","reqId":"1","body":{"channelId":"`+channel.String()+`","content":"@bot","embed":true}}`)
+		resEmbed, err := json.Marshal(embedded)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"type":"RESPONSE","reqId":"1","body":{"id":"d6b3d7f0-0a0e-4a2b-9f4c-6b0f2c4ef1a9","content":`+string(resEmbed)+`}}`, res)
+	})
+
+	t.Run("post message without embed", func(t *testing.T) {
+		res := request(t, `{"type":"POST_MESSAGE","reqId":"1","body":{"channelId":"`+channel.String()+`","content":"@bot"}}`)
+		assert.JSONEq(t, `{"type":"RESPONSE","reqId":"1","body":{"id":"d6b3d7f0-0a0e-4a2b-9f4c-6b0f2c4ef1a9","content":"@bot"}}`, res)
+	})
+
+	t.Run("edit message", func(t *testing.T) {
+		res := request(t, `{"type":"EDIT_MESSAGE","reqId":"1","body":{"messageId":"`+own.model.ID.String()+`","content":"@bot","embed":true}}`)
+		assert.JSONEq(t, `{"type":"RESPONSE","reqId":"1","body":null}`, res)
+		assert.Equal(t, embedded, mm.edited[own.model.ID])
+	})
+
+	t.Run("edit message without embed", func(t *testing.T) {
+		res := request(t, `{"type":"EDIT_MESSAGE","reqId":"1","body":{"messageId":"`+own.model.ID.String()+`","content":"@bot"}}`)
+		assert.JSONEq(t, `{"type":"RESPONSE","reqId":"1","body":null}`, res)
+		assert.Equal(t, "@bot", mm.edited[own.model.ID])
+	})
+}
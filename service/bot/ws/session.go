@@ -54,7 +54,11 @@ func (s *session) ReadLoop() {
 		}
 
 		if t == websocket.TextMessage {
-			s.commandHandler(string(m))
+			if len(m) > 0 && m[0] == '{' {
+				s.requestHandler(m)
+			} else {
+				s.commandHandler(string(m))
+			}
 		}
 
 		if t == websocket.BinaryMessage {
@@ -11,8 +11,15 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/traPtitech/traQ/event"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
 	"github.com/traPtitech/traQ/router/extension/ctxkey"
+	"github.com/traPtitech/traQ/service/channel"
+	"github.com/traPtitech/traQ/service/message"
+	"github.com/traPtitech/traQ/service/ratelimit"
+	"github.com/traPtitech/traQ/service/rbac"
 	"github.com/traPtitech/traQ/service/webrtcv3"
+	mutil "github.com/traPtitech/traQ/utils/message"
 )
 
 var (
@@ -26,21 +33,59 @@ var (
 type Streamer struct {
 	hub      *hub.Hub
 	webrtc   *webrtcv3.Manager
+	repo     repository.Repository
+	cm       channel.Manager
+	mm       message.Manager
+	rbac     rbac.RBAC
+	limiter  ratelimit.Store
+	replacer *mutil.Replacer
 	logger   *zap.Logger
 	sessions map[uuid.UUID][]*session
 	closed   bool
 	mu       sync.RWMutex
+
+	replays  map[uuid.UUID]*replayBuffer
+	replayMu sync.Mutex
 }
 
 // NewStreamer WebSocketストリーマーを生成し起動します
-func NewStreamer(hub *hub.Hub, webrtc *webrtcv3.Manager, logger *zap.Logger) *Streamer {
+//
+// limiterがnilの場合、リクエストのレート制限は行われません。
+func NewStreamer(hub *hub.Hub, webrtc *webrtcv3.Manager, repo repository.Repository, cm channel.Manager, mm message.Manager, rbac rbac.RBAC, limiter ratelimit.Store, replacer *mutil.Replacer, logger *zap.Logger) *Streamer {
 	h := &Streamer{
 		hub:      hub,
 		webrtc:   webrtc,
+		repo:     repo,
+		cm:       cm,
+		mm:       mm,
+		rbac:     rbac,
+		limiter:  limiter,
+		replacer: replacer,
 		logger:   logger.Named("bot.ws"),
 		sessions: make(map[uuid.UUID][]*session),
 		closed:   false,
+		replays:  make(map[uuid.UUID]*replayBuffer),
 	}
+
+	sub := hub.Subscribe(10, event.BotDeleted, event.BotUpdated)
+	go func() {
+		for ev := range sub.Receiver {
+			switch ev.Topic() {
+			case event.BotDeleted:
+				h.deleteReplayBuffer(ev.Fields["bot_user_id"].(uuid.UUID))
+			case event.BotUpdated:
+				// WebSocket Modeでなくなった場合は再送用バッファを破棄
+				b, err := repo.GetBotByID(ev.Fields["bot_id"].(uuid.UUID))
+				if err != nil {
+					h.logger.Error("failed to GetBotByID", zap.Error(err))
+					continue
+				}
+				if b.Mode != model.BotModeWebSocket {
+					h.deleteReplayBuffer(b.BotUserID)
+				}
+			}
+		}
+	}()
 	return h
 }
 
@@ -77,6 +122,8 @@ func (s *Streamer) WriteMessage(t string, reqID uuid.UUID, body []byte, botUserI
 		t:    websocket.TextMessage,
 		data: makeEventMessage(t, reqID, body).toJSON(),
 	}
+	s.getReplayBuffer(botUserID).push(reqID, m.data)
+
 	s.mu.RLock()
 	for _, session := range s.sessions[botUserID] {
 		if err := session.WriteMessage(m); err != nil {
@@ -95,6 +142,57 @@ func (s *Streamer) WriteMessage(t string, reqID uuid.UUID, body []byte, botUserI
 	return
 }
 
+func (s *Streamer) getReplayBuffer(botUserID uuid.UUID) *replayBuffer {
+	s.replayMu.Lock()
+	defer s.replayMu.Unlock()
+	b, ok := s.replays[botUserID]
+	if !ok {
+		b = newReplayBuffer(replayBufferSize)
+		s.replays[botUserID] = b
+	}
+	return b
+}
+
+func (s *Streamer) deleteReplayBuffer(botUserID uuid.UUID) {
+	s.replayMu.Lock()
+	defer s.replayMu.Unlock()
+	delete(s.replays, botUserID)
+}
+
+// replay 指定したリクエストIDより後のイベントを指定したセッションに再送します
+//
+// 再送用バッファに指定したリクエストIDのイベントが無い場合は、BOTイベントログから再送します。
+func (s *Streamer) replay(session *session, reqID uuid.UUID) error {
+	if data, ok := s.getReplayBuffer(session.userID).after(reqID); ok {
+		for _, d := range data {
+			if err := session.WriteMessage(&rawMessage{t: websocket.TextMessage, data: d}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// バッファから溢れているのでイベントログから再送
+	b, err := s.repo.GetBotByBotUserID(session.userID)
+	if err != nil {
+		return err
+	}
+	logs, err := s.repo.GetBotEventLogsAfter(b.ID, reqID, replayLogLimit)
+	if err != nil {
+		return err
+	}
+	for _, log := range logs {
+		m := &rawMessage{
+			t:    websocket.TextMessage,
+			data: makeEventMessage(log.Event.String(), log.RequestID, []byte(log.Body)).toJSON(),
+		}
+		if err := session.WriteMessage(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ServeHTTP http.Handlerインターフェイスの実装
 func (s *Streamer) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 	s.mu.RLock()
@@ -8,6 +8,7 @@ import (
 
 	"github.com/traPtitech/traQ/model"
 	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/utils/set"
 )
 
 var (
@@ -19,11 +20,19 @@ var (
 	ErrChannelArchived      = errors.New("channel archived")
 	ErrForcedNotification   = errors.New("forced notification channel")
 	ErrInvalidChannel       = errors.New("invalid channel")
+	ErrNotChannelMember     = errors.New("not a channel member")
+	ErrInvalidDMMembers     = errors.New("invalid direct message members")
+	ErrChannelNotEmpty      = errors.New("channel not empty")
 )
 
 type Manager interface {
 	GetChannel(id uuid.UUID) (*model.Channel, error)
 	CreatePublicChannel(name string, parent, creatorID uuid.UUID) (*model.Channel, error)
+	// CreatePublicChannelsFromTemplate チャンネルテンプレートのサブツリーを指定した親チャンネルの下に一括で作成します
+	//
+	// 全てのチャンネルが作成されるか、1つも作成されないかのどちらかです。
+	// 作成されたチャンネルを親チャンネルが先になるように返します。
+	CreatePublicChannelsFromTemplate(parent uuid.UUID, channels model.ChannelTemplateNodes, creatorID uuid.UUID) ([]*model.Channel, error)
 	UpdateChannel(id uuid.UUID, args repository.UpdateChannelArgs) error
 	PublicChannelTree() Tree
 
@@ -31,14 +40,48 @@ type Manager interface {
 
 	ArchiveChannel(id uuid.UUID, updaterID uuid.UUID) error
 	UnarchiveChannel(id uuid.UUID, updaterID uuid.UUID) error
+	// MergeChannel 公開チャンネルのメッセージ・ピン留め・購読等を別の公開チャンネルに統合します
+	//
+	// 統合元の子チャンネルは統合先の子チャンネルに移動されます。統合元チャンネル自体は削除されません。
+	MergeChannel(srcID, dstID uuid.UUID, updaterID uuid.UUID) error
+	// DeleteChannel 公開チャンネルを完全に削除します
+	//
+	// 子チャンネル・メッセージ・Webhookが存在する場合はErrChannelNotEmptyを返します。
+	DeleteChannel(id uuid.UUID, deleterID uuid.UUID) error
 
 	GetDMChannel(user1, user2 uuid.UUID) (*model.Channel, error)
 	GetDMChannelMembers(id uuid.UUID) ([]uuid.UUID, error)
 	GetDMChannelMapping(userID uuid.UUID) (map[uuid.UUID]uuid.UUID, error)
 
+	// GetGroupDMChannel 指定したメンバー集合のグループDMチャンネルを取得します
+	//
+	// 存在しない場合は作成します。メンバーが3人未満または上限を超える場合はErrInvalidDMMembersを返します。
+	GetGroupDMChannel(members set.UUID) (*model.Channel, error)
+	// GetGroupDMChannelMapping 指定したユーザーが参加しているグループDMチャンネルとそのメンバーのマッピングを取得します
+	GetGroupDMChannelMapping(userID uuid.UUID) (map[uuid.UUID][]uuid.UUID, error)
+
+	// CreatePrivateChannel 招待制のプライベートチャンネルを作成します
+	//
+	// 作成者は常にメンバーに含まれます。存在しないユーザーは無視されます。
+	CreatePrivateChannel(name string, creatorID uuid.UUID, members set.UUID) (*model.Channel, error)
+	// GetPrivateChannelMembers 指定したプライベートチャンネルのメンバーのUUIDを取得します
+	GetPrivateChannelMembers(id uuid.UUID) ([]uuid.UUID, error)
+	// AddPrivateChannelMembers 指定したプライベートチャンネルにメンバーを招待します
+	AddPrivateChannelMembers(id uuid.UUID, userIDs set.UUID, updaterID uuid.UUID) error
+	// RemovePrivateChannelMember 指定したプライベートチャンネルからメンバーを削除します
+	RemovePrivateChannelMember(id, userID, updaterID uuid.UUID) error
+	// GetUserPrivateChannels 指定したユーザーがメンバーのプライベートチャンネルを取得します
+	GetUserPrivateChannels(userID uuid.UUID) ([]*model.Channel, error)
+
 	IsChannelAccessibleToUser(userID, channelID uuid.UUID) (bool, error)
 	IsPublicChannel(id uuid.UUID) bool
 
+	// GetUserChannelRoles 指定したチャンネルでユーザーに適用されるチャンネルロールの名前を取得します
+	//
+	// 祖先チャンネルで割り当てられたチャンネルロールも含まれます。
+	// 公開チャンネル以外では常に空の配列を返します。
+	GetUserChannelRoles(userID, channelID uuid.UUID) ([]string, error)
+
 	// Wait マネージャーの処理の完了を待ちます
 	Wait()
 }
@@ -17,8 +17,10 @@ import (
 )
 
 var (
-	dmChannelRootUUID  = uuid.Must(uuid.FromString(model.DirectMessageChannelRootID))
-	pubChannelRootUUID = uuid.Nil
+	dmChannelRootUUID      = uuid.Must(uuid.FromString(model.DirectMessageChannelRootID))
+	privateChannelRootUUID = uuid.Must(uuid.FromString(model.PrivateChannelRootID))
+	groupDMChannelRootUUID = uuid.Must(uuid.FromString(model.GroupDirectMessageChannelRootID))
+	pubChannelRootUUID     = uuid.Nil
 )
 
 type managerImpl struct {
@@ -27,7 +29,8 @@ type managerImpl struct {
 	T *treeImpl
 	P sync.WaitGroup
 
-	MaxChannelDepth int
+	MaxChannelDepth   int
+	MaxGroupDMMembers int
 }
 
 func InitChannelManager(repo repository.ChannelRepository, logger *zap.Logger) (Manager, error) {
@@ -37,9 +40,10 @@ func InitChannelManager(repo repository.ChannelRepository, logger *zap.Logger) (
 	}
 
 	m := &managerImpl{
-		R:               repo,
-		L:               logger.Named("channel_manager"),
-		MaxChannelDepth: 5,
+		R:                 repo,
+		L:                 logger.Named("channel_manager"),
+		MaxChannelDepth:   5,
+		MaxGroupDMMembers: 10,
 	}
 	m.T, err = makeChannelTree(channels)
 	if err != nil {
@@ -119,11 +123,93 @@ func (m *managerImpl) CreatePublicChannel(name string, parent, creatorID uuid.UU
 	return ch, nil
 }
 
+func (m *managerImpl) CreatePublicChannelsFromTemplate(parent uuid.UUID, channels model.ChannelTemplateNodes, creatorID uuid.UUID) ([]*model.Channel, error) {
+	m.T.Lock()
+	defer m.T.Unlock()
+
+	// チャンネル名の制約を確認
+	if err := channels.Validate(); err != nil {
+		return nil, ErrInvalidChannelName
+	}
+
+	// チャンネル名の重複を確認
+	for _, n := range channels {
+		if m.T.isChildPresent(n.Name, parent) {
+			return nil, ErrChannelNameConflicts
+		}
+	}
+
+	depth := channels.Depth()
+	if parent != pubChannelRootUUID {
+		// 親チャンネルの存在を確認
+		if !m.T.isChannelPresent(parent) {
+			return nil, ErrInvalidParentChannel
+		}
+		// 親チャンネルがアーカイブされているかどうか確認
+		if m.T.isArchivedChannel(parent) {
+			return nil, ErrChannelArchived
+		}
+		depth += len(m.T.getAscendantIDs(parent)) + 1
+	}
+	// 深さを検証
+	if depth > m.MaxChannelDepth {
+		return nil, ErrTooDeepChannel
+	}
+
+	args := make([]*repository.CreatePublicChannelArgs, 0, channels.Count())
+	var walk func(parent uuid.UUID, nodes model.ChannelTemplateNodes)
+	walk = func(parent uuid.UUID, nodes model.ChannelTemplateNodes) {
+		for _, n := range nodes {
+			arg := &repository.CreatePublicChannelArgs{
+				Channel: model.Channel{
+					ID:        uuid.Must(uuid.NewV4()),
+					Name:      n.Name,
+					ParentID:  parent,
+					Topic:     n.Topic,
+					CreatorID: creatorID,
+					UpdaterID: creatorID,
+					IsForced:  n.Force,
+					IsVisible: true,
+				},
+				Subscribers: set.UUIDSetFromArray(n.Subscribers),
+				Bots:        set.UUIDSetFromArray(n.Bots),
+			}
+			args = append(args, arg)
+			walk(arg.Channel.ID, n.Children)
+		}
+	}
+	walk(parent, channels)
+
+	// チャンネル作成
+	chs, err := m.R.CreatePublicChannels(args)
+	if err != nil {
+		if err == repository.ErrAlreadyExists {
+			return nil, ErrChannelNameConflicts
+		}
+		return nil, fmt.Errorf("failed to CreatePublicChannels: %w", err)
+	}
+	for _, ch := range chs {
+		m.T.add(ch)
+		if ch.ParentID != pubChannelRootUUID {
+			// ロギング
+			m.recordChannelEvent(ch.ParentID, model.ChannelEventChildCreated, model.ChannelEventDetail{
+				"userId":    ch.CreatorID,
+				"channelId": ch.ID,
+			}, ch.CreatedAt)
+		}
+		m.L.Info(fmt.Sprintf("channel #%s was created", m.T.getChannelPath(ch.ID)), zap.Stringer("cid", ch.ID))
+	}
+	return chs, nil
+}
+
 func (m *managerImpl) UpdateChannel(id uuid.UUID, args repository.UpdateChannelArgs) error {
 	ch, err := m.GetChannel(id)
 	if err != nil {
 		return ErrChannelNotFound
 	}
+	if !ch.IsPublic && (args.Parent.Valid || args.ForcedNotification.Valid) {
+		return ErrInvalidChannel // 公開チャンネル以外は親チャンネル・強制通知を変更不可
+	}
 
 	m.T.Lock()
 	defer m.T.Unlock()
@@ -170,7 +256,7 @@ func (m *managerImpl) UpdateChannel(id uuid.UUID, args repository.UpdateChannelA
 				p = ch.ParentID
 			}
 
-			if m.T.isChildPresent(n, p) {
+			if ch.IsPublic && m.T.isChildPresent(n, p) {
 				return ErrChannelNameConflicts
 			}
 		}
@@ -220,13 +306,18 @@ func (m *managerImpl) UpdateChannel(id uuid.UUID, args repository.UpdateChannelA
 
 	ch, err = m.R.UpdateChannel(id, args)
 	if err != nil {
+		if err == repository.ErrAlreadyExists {
+			return ErrChannelNameConflicts
+		}
 		return fmt.Errorf("failed to UpdateChannel: %w", err)
 	}
 
-	if args.Name.Valid || args.Parent.Valid {
-		m.T.move(id, args.Parent, args.Name)
+	if ch.IsPublic {
+		if args.Name.Valid || args.Parent.Valid {
+			m.T.move(id, args.Parent, args.Name)
+		}
+		m.T.updateSingle(id, ch)
 	}
-	m.T.updateSingle(id, ch)
 
 	updated := time.Now()
 	for eventType, detail := range eventRecords {
@@ -244,13 +335,28 @@ func (m *managerImpl) ArchiveChannel(id uuid.UUID, updaterID uuid.UUID) error {
 	if ch.IsArchived() {
 		return nil // 既にアーカイブされている
 	}
-	if ch.IsDMChannel() {
+	if ch.IsDMChannel() || ch.IsGroupDMChannel() {
 		return ErrInvalidChannel // DMチャンネルはアーカイブ不可
 	}
 
 	m.T.Lock()
 	defer m.T.Unlock()
 
+	if ch.IsPrivateChannel() {
+		// プライベートチャンネルには子チャンネルが存在しない
+		chs, err := m.R.ArchiveChannels([]uuid.UUID{id})
+		if err != nil {
+			return fmt.Errorf("failed to ArchiveChannels: %w", err)
+		}
+		for _, ch := range chs {
+			m.recordChannelEvent(ch.ID, model.ChannelEventVisibilityChanged, model.ChannelEventDetail{
+				"userId":     updaterID,
+				"visibility": ch.IsVisible,
+			}, ch.UpdatedAt)
+		}
+		return nil
+	}
+
 	var (
 		targets = []uuid.UUID{id}
 		queue   = m.T.getChildrenIDs(id)
@@ -305,7 +411,9 @@ func (m *managerImpl) UnarchiveChannel(id uuid.UUID, updaterID uuid.UUID) error
 		return fmt.Errorf("failed to UpdateChannel: %w", err)
 	}
 
-	m.T.updateSingle(id, ch)
+	if ch.IsPublic {
+		m.T.updateSingle(id, ch)
+	}
 
 	m.recordChannelEvent(ch.ID, model.ChannelEventVisibilityChanged, model.ChannelEventDetail{
 		"userId":     updaterID,
@@ -314,6 +422,105 @@ func (m *managerImpl) UnarchiveChannel(id uuid.UUID, updaterID uuid.UUID) error
 	return nil
 }
 
+func (m *managerImpl) MergeChannel(srcID, dstID uuid.UUID, updaterID uuid.UUID) error {
+	if srcID == dstID || !m.IsPublicChannel(srcID) || !m.IsPublicChannel(dstID) {
+		return ErrInvalidChannel // 公開チャンネル同士のみ統合可能
+	}
+
+	m.T.Lock()
+	defer m.T.Unlock()
+
+	if m.T.isArchivedChannel(dstID) {
+		return ErrChannelArchived
+	}
+	for _, id := range m.T.getAscendantIDs(dstID) {
+		if id == srcID {
+			return ErrInvalidParentChannel // 統合先が統合元の子孫
+		}
+	}
+
+	// 子チャンネルを統合先に移動できるか確認
+	children := m.T.getChildrenIDs(srcID)
+	for _, cid := range children {
+		if m.T.isChildPresent(m.T.nodes[cid].name, dstID) {
+			return ErrChannelNameConflicts
+		}
+		if len(m.T.getAscendantIDs(dstID))+1+m.T.getChannelDepth(cid) > m.MaxChannelDepth {
+			return ErrTooDeepChannel
+		}
+	}
+
+	movedChildren, moved, err := m.R.MergeChannel(srcID, dstID, updaterID)
+	if err != nil {
+		if err == repository.ErrAlreadyExists {
+			return ErrChannelNameConflicts
+		}
+		return fmt.Errorf("failed to MergeChannel: %w", err)
+	}
+
+	for _, ch := range movedChildren {
+		m.T.move(ch.ID, optional.UUIDFrom(dstID), optional.String{})
+		m.T.updateSingle(ch.ID, ch)
+		m.recordChannelEvent(ch.ID, model.ChannelEventParentChanged, model.ChannelEventDetail{
+			"userId": updaterID,
+			"before": srcID,
+			"after":  dstID,
+		}, ch.UpdatedAt)
+	}
+
+	detail := model.ChannelEventDetail{
+		"userId":   updaterID,
+		"from":     srcID,
+		"to":       dstID,
+		"messages": moved,
+	}
+	merged := time.Now()
+	m.recordChannelEvent(srcID, model.ChannelEventMerged, detail, merged)
+	m.recordChannelEvent(dstID, model.ChannelEventMerged, detail, merged)
+	m.L.Info(fmt.Sprintf("channel #%s was merged into #%s", m.T.getChannelPath(srcID), m.T.getChannelPath(dstID)), zap.Stringer("cid", srcID), zap.Stringer("targetCid", dstID))
+	return nil
+}
+
+func (m *managerImpl) DeleteChannel(id uuid.UUID, deleterID uuid.UUID) error {
+	if !m.IsPublicChannel(id) {
+		if _, err := m.GetChannel(id); err != nil {
+			return err
+		}
+		return ErrInvalidChannel // 公開チャンネルのみ削除可能
+	}
+
+	m.T.Lock()
+	defer m.T.Unlock()
+
+	if len(m.T.getChildrenIDs(id)) > 0 {
+		return ErrChannelNotEmpty
+	}
+
+	n := m.T.nodes[id]
+	path := m.T.getChannelPath(id)
+	if err := m.R.DeleteChannel(id); err != nil {
+		switch err {
+		case repository.ErrNotFound:
+			return ErrChannelNotFound
+		case repository.ErrForbidden:
+			return ErrChannelNotEmpty
+		default:
+			return fmt.Errorf("failed to DeleteChannel: %w", err)
+		}
+	}
+	m.T.remove(id)
+
+	if n.parent != nil {
+		m.recordChannelEvent(n.parent.id, model.ChannelEventChildDeleted, model.ChannelEventDetail{
+			"userId":    deleterID,
+			"channelId": id,
+			"name":      n.name,
+		}, time.Now())
+	}
+	m.L.Info(fmt.Sprintf("channel #%s was deleted", path), zap.Stringer("cid", id))
+	return nil
+}
+
 func (m *managerImpl) PublicChannelTree() Tree {
 	return m.T
 }
@@ -396,12 +603,165 @@ func (m *managerImpl) GetDMChannelMapping(userID uuid.UUID) (map[uuid.UUID]uuid.
 	return result, nil
 }
 
+func (m *managerImpl) GetGroupDMChannel(members set.UUID) (*model.Channel, error) {
+	if len(members) < 3 || len(members) > m.MaxGroupDMMembers || members.Contains(uuid.Nil) {
+		return nil, ErrInvalidDMMembers
+	}
+
+	ch, err := m.R.GetGroupDirectMessageChannel(members)
+	if err == nil {
+		return ch, nil
+	} else if err != repository.ErrNotFound {
+		return nil, fmt.Errorf("failed to GetGroupDirectMessageChannel: %w", err)
+	}
+
+	// 存在しなかったので作成
+	ch, err = m.R.CreateChannel(
+		model.Channel{
+			Name:      "dm_" + random.AlphaNumeric(17),
+			IsVisible: true,
+		},
+		members,
+		true,
+	)
+	if err == repository.ErrAlreadyExists {
+		// 同時に作成された
+		ch, err = m.R.GetGroupDirectMessageChannel(members)
+		if err != nil {
+			return nil, fmt.Errorf("failed to GetGroupDirectMessageChannel: %w", err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to CreateChannel: %w", err)
+	}
+	ch.ChildrenID = make([]uuid.UUID, 0)
+	return ch, nil
+}
+
+func (m *managerImpl) GetGroupDMChannelMapping(userID uuid.UUID) (map[uuid.UUID][]uuid.UUID, error) {
+	chs, err := m.R.GetGroupDirectMessageChannelsByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to GetGroupDMChannelMapping: %w", err)
+	}
+
+	result := make(map[uuid.UUID][]uuid.UUID, len(chs))
+	for _, ch := range chs {
+		members, err := m.R.GetPrivateChannelMemberIDs(ch.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to GetGroupDMChannelMapping: %w", err)
+		}
+		result[ch.ID] = members
+	}
+	return result, nil
+}
+
+func (m *managerImpl) CreatePrivateChannel(name string, creatorID uuid.UUID, members set.UUID) (*model.Channel, error) {
+	// チャンネル名の制約を確認
+	if !validator.ChannelRegex.MatchString(name) {
+		return nil, ErrInvalidChannelName
+	}
+
+	// チャンネル作成
+	ch, err := m.R.CreateChannel(model.Channel{
+		Name:      name,
+		ParentID:  privateChannelRootUUID,
+		CreatorID: creatorID,
+		UpdaterID: creatorID,
+		IsForced:  false,
+		IsVisible: true,
+	}, set.UUIDSetFromArray([]uuid.UUID{creatorID}), false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to CreateChannel: %w", err)
+	}
+	ch.ChildrenID = make([]uuid.UUID, 0)
+
+	invitees := members.Clone()
+	invitees.Remove(creatorID)
+	if len(invitees) > 0 {
+		if err := m.addPrivateChannelMembers(ch.ID, invitees, creatorID); err != nil {
+			return nil, err
+		}
+	}
+	m.L.Info(fmt.Sprintf("private channel %s was created", ch.Name), zap.Stringer("cid", ch.ID))
+	return ch, nil
+}
+
+func (m *managerImpl) GetPrivateChannelMembers(id uuid.UUID) ([]uuid.UUID, error) {
+	members, err := m.R.GetPrivateChannelMemberIDs(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to GetPrivateChannelMembers: %w", err)
+	}
+	return members, nil
+}
+
+func (m *managerImpl) AddPrivateChannelMembers(id uuid.UUID, userIDs set.UUID, updaterID uuid.UUID) error {
+	ch, err := m.GetChannel(id)
+	if err != nil {
+		return err
+	}
+	if !ch.IsPrivateChannel() {
+		return ErrInvalidChannel
+	}
+	if ch.IsArchived() {
+		return ErrChannelArchived
+	}
+	return m.addPrivateChannelMembers(id, userIDs, updaterID)
+}
+
+func (m *managerImpl) addPrivateChannelMembers(id uuid.UUID, userIDs set.UUID, updaterID uuid.UUID) error {
+	added, err := m.R.AddPrivateChannelMembers(id, userIDs)
+	if err != nil {
+		return fmt.Errorf("failed to AddPrivateChannelMembers: %w", err)
+	}
+	if len(added) > 0 {
+		m.recordChannelEvent(id, model.ChannelEventMembersChanged, model.ChannelEventDetail{
+			"userId":  updaterID,
+			"added":   added,
+			"removed": []uuid.UUID{},
+		}, time.Now())
+	}
+	return nil
+}
+
+func (m *managerImpl) RemovePrivateChannelMember(id, userID, updaterID uuid.UUID) error {
+	ch, err := m.GetChannel(id)
+	if err != nil {
+		return err
+	}
+	if !ch.IsPrivateChannel() {
+		return ErrInvalidChannel
+	}
+
+	if err := m.R.RemovePrivateChannelMember(id, userID); err != nil {
+		if err == repository.ErrNotFound {
+			return ErrNotChannelMember
+		}
+		return fmt.Errorf("failed to RemovePrivateChannelMember: %w", err)
+	}
+	m.recordChannelEvent(id, model.ChannelEventMembersChanged, model.ChannelEventDetail{
+		"userId":  updaterID,
+		"added":   []uuid.UUID{},
+		"removed": []uuid.UUID{userID},
+	}, time.Now())
+	return nil
+}
+
+func (m *managerImpl) GetUserPrivateChannels(userID uuid.UUID) ([]*model.Channel, error) {
+	chs, err := m.R.GetPrivateChannelsByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to GetUserPrivateChannels: %w", err)
+	}
+	for _, ch := range chs {
+		ch.ChildrenID = make([]uuid.UUID, 0)
+	}
+	return chs, nil
+}
+
 func (m *managerImpl) IsChannelAccessibleToUser(userID, channelID uuid.UUID) (bool, error) {
 	if m.T.IsChannelPresent(channelID) {
 		return true, nil // 公開チャンネルは全員アクセス可能
 	}
 
-	// DMチャンネル
+	// DMチャンネル・プライベートチャンネル
 	members, err := m.R.GetPrivateChannelMemberIDs(channelID)
 	if err != nil {
 		return false, fmt.Errorf("failed to IsChannelAccessibleToUser: %w", err)
@@ -418,6 +778,23 @@ func (m *managerImpl) IsPublicChannel(id uuid.UUID) bool {
 	return m.T.IsChannelPresent(id)
 }
 
+func (m *managerImpl) GetUserChannelRoles(userID, channelID uuid.UUID) ([]string, error) {
+	if !m.T.IsChannelPresent(channelID) {
+		return []string{}, nil
+	}
+
+	ids := append([]uuid.UUID{channelID}, m.T.GetAscendantIDs(channelID)...)
+	roles, err := m.R.GetUserChannelRoles(userID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to GetUserChannelRoles: %w", err)
+	}
+	result := make([]string, len(roles))
+	for i, r := range roles {
+		result[i] = r.Role
+	}
+	return result, nil
+}
+
 func (m *managerImpl) Wait() {
 	m.P.Wait()
 }
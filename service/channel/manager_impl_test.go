@@ -25,10 +25,11 @@ import (
 
 func initCM(t *testing.T, repo repository.ChannelRepository) *managerImpl {
 	return &managerImpl{
-		R:               repo,
-		L:               zap.NewNop(),
-		T:               makeTestChannelTree(t),
-		MaxChannelDepth: 5,
+		R:                 repo,
+		L:                 zap.NewNop(),
+		T:                 makeTestChannelTree(t),
+		MaxChannelDepth:   5,
+		MaxGroupDMMembers: 10,
 	}
 }
 
@@ -341,6 +342,128 @@ func TestManagerImpl_CreatePublicChannel(t *testing.T) {
 	})
 }
 
+func TestManagerImpl_CreatePublicChannelsFromTemplate(t *testing.T) {
+	t.Parallel()
+
+	template := model.ChannelTemplateNodes{
+		{Name: "general", Topic: "topic", Force: true, Subscribers: []uuid.UUID{uuid.NewV3(uuid.Nil, "u")}, Children: model.ChannelTemplateNodes{
+			{Name: "random"},
+		}},
+		{Name: "design"},
+	}
+
+	t.Run("ErrInvalidChannelName", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		_, err := cm.CreatePublicChannelsFromTemplate(cA, model.ChannelTemplateNodes{{Name: "チャンネル"}}, uuid.Nil)
+		assert.EqualError(t, err, ErrInvalidChannelName.Error())
+	})
+
+	t.Run("ErrChannelNameConflicts", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		_, err := cm.CreatePublicChannelsFromTemplate(cA, model.ChannelTemplateNodes{{Name: "x"}, {Name: "B"}}, uuid.Nil)
+		assert.EqualError(t, err, ErrChannelNameConflicts.Error())
+	})
+
+	t.Run("ErrInvalidParentChannel", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		_, err := cm.CreatePublicChannelsFromTemplate(cNotFound, template, uuid.Nil)
+		assert.EqualError(t, err, ErrInvalidParentChannel.Error())
+	})
+
+	t.Run("ErrChannelArchived", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		_, err := cm.CreatePublicChannelsFromTemplate(cABB, template, uuid.Nil)
+		assert.EqualError(t, err, ErrChannelArchived.Error())
+	})
+
+	t.Run("ErrTooDeepChannel", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		_, err := cm.CreatePublicChannelsFromTemplate(cABCD, template, uuid.Nil)
+		assert.EqualError(t, err, ErrTooDeepChannel.Error())
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		repo.EXPECT().
+			CreatePublicChannels(gomock.Len(3)).
+			Return(nil, repository.ErrAlreadyExists).
+			Times(1)
+
+		_, err := cm.CreatePublicChannelsFromTemplate(cA, template, uuid.Nil)
+		assert.EqualError(t, err, ErrChannelNameConflicts.Error())
+		assert.False(t, cm.PublicChannelTree().IsChildPresent("general", cA))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		creator := uuid.NewV3(uuid.Nil, "c")
+		repo.EXPECT().
+			CreatePublicChannels(gomock.Len(3)).
+			DoAndReturn(func(args []*repository.CreatePublicChannelArgs) ([]*model.Channel, error) {
+				assert.Equal(t, "general", args[0].Channel.Name)
+				assert.Equal(t, cA, args[0].Channel.ParentID)
+				assert.True(t, args[0].Channel.IsForced)
+				assert.Equal(t, "topic", args[0].Channel.Topic)
+				assert.Equal(t, creator, args[0].Channel.CreatorID)
+				assert.True(t, args[0].Subscribers.Contains(uuid.NewV3(uuid.Nil, "u")))
+				assert.Equal(t, "random", args[1].Channel.Name)
+				assert.Equal(t, args[0].Channel.ID, args[1].Channel.ParentID)
+				assert.Equal(t, "design", args[2].Channel.Name)
+				assert.Equal(t, cA, args[2].Channel.ParentID)
+
+				chs := make([]*model.Channel, len(args))
+				for i, arg := range args {
+					ch := arg.Channel
+					ch.IsPublic = true
+					chs[i] = &ch
+				}
+				return chs, nil
+			}).
+			Times(1)
+		repo.EXPECT().
+			RecordChannelEvent(gomock.Any(), model.ChannelEventChildCreated, gomock.Any(), gomock.Any()).
+			Return(nil).
+			Times(3)
+
+		chs, err := cm.CreatePublicChannelsFromTemplate(cA, template, creator)
+		cm.P.Wait()
+		if assert.NoError(t, err) && assert.Len(t, chs, 3) {
+			tree := cm.PublicChannelTree()
+			assert.Equal(t, "a/general/random", tree.GetChannelPath(chs[1].ID))
+			assert.Equal(t, "a/design", tree.GetChannelPath(chs[2].ID))
+			assert.True(t, tree.IsForceChannel(chs[0].ID))
+		}
+	})
+}
+
 func TestManagerImpl_UpdateChannel(t *testing.T) {
 	t.Parallel()
 
@@ -824,6 +947,227 @@ func TestManagerImpl_UnarchiveChannel(t *testing.T) {
 	})
 }
 
+func TestManagerImpl_MergeChannel(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ErrInvalidChannel", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		assert.EqualError(t, cm.MergeChannel(cA, cA, uuid.Nil), ErrInvalidChannel.Error())
+		assert.EqualError(t, cm.MergeChannel(cA, cNotFound, uuid.Nil), ErrInvalidChannel.Error())
+	})
+
+	t.Run("ErrChannelArchived", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		err := cm.MergeChannel(cAD, cABB, uuid.Nil)
+		assert.EqualError(t, err, ErrChannelArchived.Error())
+	})
+
+	t.Run("ErrInvalidParentChannel", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		err := cm.MergeChannel(cAB, cABCD, uuid.Nil)
+		assert.EqualError(t, err, ErrInvalidParentChannel.Error())
+	})
+
+	t.Run("ErrChannelNameConflicts", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		err := cm.MergeChannel(cE, cAB, uuid.Nil)
+		assert.EqualError(t, err, ErrChannelNameConflicts.Error())
+	})
+
+	t.Run("ErrTooDeepChannel", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		err := cm.MergeChannel(cE, cABCD, uuid.Nil)
+		assert.EqualError(t, err, ErrTooDeepChannel.Error())
+	})
+
+	t.Run("ErrChannelNameConflicts (repository)", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		repo.EXPECT().
+			MergeChannel(cAD, cEK, uuid.Nil).
+			Return(nil, int64(0), repository.ErrAlreadyExists).
+			Times(1)
+
+		err := cm.MergeChannel(cAD, cEK, uuid.Nil)
+		assert.EqualError(t, err, ErrChannelNameConflicts.Error())
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		mockErr := errors.New("mock error")
+		repo.EXPECT().
+			MergeChannel(cAD, cEK, uuid.Nil).
+			Return(nil, int64(0), mockErr).
+			Times(1)
+
+		err := cm.MergeChannel(cAD, cEK, uuid.Nil)
+		if assert.Error(t, err) {
+			assert.Equal(t, mockErr, errors.Unwrap(err))
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		updater := uuid.NewV3(uuid.Nil, "u")
+		repo.EXPECT().
+			MergeChannel(cABF, cAD, updater).
+			Return([]*model.Channel{{ID: cABFA, Name: "a", ParentID: cAD, Topic: "", IsForced: false, IsPublic: true, IsVisible: true}}, int64(3), nil).
+			Times(1)
+		repo.EXPECT().
+			RecordChannelEvent(cABFA, model.ChannelEventParentChanged, gomock.Any(), gomock.Any()).
+			Return(nil).
+			Times(1)
+		repo.EXPECT().
+			RecordChannelEvent(gomock.Any(), model.ChannelEventMerged, model.ChannelEventDetail{
+				"userId":   updater,
+				"from":     cABF,
+				"to":       cAD,
+				"messages": int64(3),
+			}, gomock.Any()).
+			Return(nil).
+			Times(2)
+
+		err := cm.MergeChannel(cABF, cAD, updater)
+		cm.P.Wait()
+		if assert.NoError(t, err) {
+			tree := cm.PublicChannelTree()
+			assert.True(t, tree.IsChildPresent("a", cAD))
+			assert.False(t, tree.IsChildPresent("a", cABF))
+			assert.Equal(t, "a/d/a", tree.GetChannelPath(cABFA))
+			assert.True(t, tree.IsChannelPresent(cABF))
+		}
+	})
+}
+
+func TestManagerImpl_DeleteChannel(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ErrChannelNotFound", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		repo.EXPECT().
+			GetChannel(gomock.Any()).
+			Return(nil, repository.ErrNotFound).
+			AnyTimes()
+
+		err := cm.DeleteChannel(cNotFound, uuid.Nil)
+		assert.EqualError(t, err, ErrChannelNotFound.Error())
+	})
+
+	t.Run("ErrInvalidChannel", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		dm1 := &model.Channel{
+			ID:        uuid.NewV3(uuid.Nil, "c 1-1"),
+			Name:      "a",
+			ParentID:  dmChannelRootUUID,
+			IsForced:  false,
+			IsPublic:  false,
+			IsVisible: true,
+		}
+
+		repo.EXPECT().
+			GetChannel(dm1.ID).
+			Return(dm1, nil).
+			AnyTimes()
+
+		err := cm.DeleteChannel(dm1.ID, uuid.Nil)
+		assert.EqualError(t, err, ErrInvalidChannel.Error())
+	})
+
+	t.Run("ErrChannelNotEmpty (children)", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		err := cm.DeleteChannel(cABC, uuid.Nil)
+		assert.EqualError(t, err, ErrChannelNotEmpty.Error())
+	})
+
+	t.Run("ErrChannelNotEmpty (messages)", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		repo.EXPECT().
+			DeleteChannel(cAD).
+			Return(repository.ErrForbidden).
+			Times(1)
+
+		err := cm.DeleteChannel(cAD, uuid.Nil)
+		assert.EqualError(t, err, ErrChannelNotEmpty.Error())
+		assert.True(t, cm.PublicChannelTree().IsChannelPresent(cAD))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		deleter := uuid.NewV3(uuid.Nil, "u")
+		repo.EXPECT().
+			DeleteChannel(cABCD).
+			Return(nil).
+			Times(1)
+		repo.EXPECT().
+			RecordChannelEvent(cABC, model.ChannelEventChildDeleted, model.ChannelEventDetail{
+				"userId":    deleter,
+				"channelId": cABCD,
+				"name":      "d",
+			}, gomock.Any()).
+			Return(nil).
+			Times(1)
+
+		err := cm.DeleteChannel(cABCD, deleter)
+		cm.P.Wait()
+		if assert.NoError(t, err) {
+			tree := cm.PublicChannelTree()
+			assert.False(t, tree.IsChannelPresent(cABCD))
+			assert.ElementsMatch(t, []uuid.UUID{cABCE}, tree.GetChildrenIDs(cABC))
+		}
+	})
+}
+
 func TestManagerImpl_GetDMChannel(t *testing.T) {
 	t.Parallel()
 
@@ -938,6 +1282,88 @@ func TestManagerImpl_GetDMChannel(t *testing.T) {
 	})
 }
 
+func TestManagerImpl_GetGroupDMChannel(t *testing.T) {
+	t.Parallel()
+
+	uid1 := uuid.NewV3(uuid.Nil, "u1")
+	uid2 := uuid.NewV3(uuid.Nil, "u2")
+	uid3 := uuid.NewV3(uuid.Nil, "u3")
+	members := set.UUIDSetFromArray([]uuid.UUID{uid1, uid2, uid3})
+
+	t.Run("too few members", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		_, err := cm.GetGroupDMChannel(set.UUIDSetFromArray([]uuid.UUID{uid1, uid2}))
+		assert.EqualError(t, err, ErrInvalidDMMembers.Error())
+	})
+
+	t.Run("too many members", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		many := set.UUID{}
+		for i := 0; i < cm.MaxGroupDMMembers+1; i++ {
+			many.Add(uuid.Must(uuid.NewV4()))
+		}
+		_, err := cm.GetGroupDMChannel(many)
+		assert.EqualError(t, err, ErrInvalidDMMembers.Error())
+	})
+
+	t.Run("success (found)", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		gdm := &model.Channel{
+			ID:        uuid.NewV3(uuid.Nil, "c 1-2-3"),
+			Name:      "a",
+			ParentID:  groupDMChannelRootUUID,
+			IsVisible: true,
+		}
+		repo.EXPECT().
+			GetGroupDirectMessageChannel(members).
+			Return(gdm, nil).
+			Times(1)
+
+		ch, err := cm.GetGroupDMChannel(members)
+		if assert.NoError(t, err) {
+			assert.EqualValues(t, gdm, ch)
+		}
+	})
+
+	t.Run("success (create)", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		repo.EXPECT().
+			GetGroupDirectMessageChannel(members).
+			Return(nil, repository.ErrNotFound).
+			Times(1)
+		repo.EXPECT().
+			CreateChannel(gomock.Any(), members, true).
+			Return(&model.Channel{
+				ID:        uuid.NewV3(uuid.Nil, "c 1-2-3"),
+				Name:      "dm_" + random.AlphaNumeric(17),
+				ParentID:  groupDMChannelRootUUID,
+				IsVisible: true,
+			}, nil).
+			Times(1)
+
+		ch, err := cm.GetGroupDMChannel(members)
+		if assert.NoError(t, err) {
+			assert.True(t, ch.IsGroupDMChannel())
+		}
+	})
+}
+
 func TestManagerImpl_GetDMChannelMembers(t *testing.T) {
 	t.Parallel()
 
@@ -1063,6 +1489,177 @@ func TestManagerImpl_GetDMChannelMapping(t *testing.T) {
 	})
 }
 
+func TestManagerImpl_CreatePrivateChannel(t *testing.T) {
+	t.Parallel()
+
+	creator := uuid.NewV3(uuid.Nil, "u1")
+	invitee := uuid.NewV3(uuid.Nil, "u2")
+
+	t.Run("ErrInvalidChannelName", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		_, err := cm.CreatePrivateChannel("あいうえお", creator, nil)
+		assert.EqualError(t, err, ErrInvalidChannelName.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		ch := &model.Channel{
+			ID:        uuid.NewV3(uuid.Nil, "private"),
+			Name:      "secret",
+			ParentID:  privateChannelRootUUID,
+			CreatorID: creator,
+			UpdaterID: creator,
+			IsVisible: true,
+		}
+		repo.EXPECT().
+			CreateChannel(model.Channel{
+				Name:      "secret",
+				ParentID:  privateChannelRootUUID,
+				CreatorID: creator,
+				UpdaterID: creator,
+				IsVisible: true,
+			}, set.UUIDSetFromArray([]uuid.UUID{creator}), false).
+			Return(ch, nil).
+			Times(1)
+		repo.EXPECT().
+			AddPrivateChannelMembers(ch.ID, set.UUIDSetFromArray([]uuid.UUID{invitee})).
+			Return([]uuid.UUID{invitee}, nil).
+			Times(1)
+		repo.EXPECT().
+			RecordChannelEvent(ch.ID, model.ChannelEventMembersChanged, gomock.Any(), gomock.Any()).
+			Return(nil).
+			Times(1)
+
+		created, err := cm.CreatePrivateChannel("secret", creator, set.UUIDSetFromArray([]uuid.UUID{creator, invitee}))
+		cm.P.Wait()
+		if assert.NoError(t, err) {
+			assert.Equal(t, ch.ID, created.ID)
+			assert.True(t, created.IsPrivateChannel())
+			assert.False(t, cm.IsPublicChannel(created.ID))
+		}
+	})
+}
+
+func TestManagerImpl_AddPrivateChannelMembers(t *testing.T) {
+	t.Parallel()
+
+	uid := uuid.NewV3(uuid.Nil, "u1")
+	private := &model.Channel{ID: uuid.NewV3(uuid.Nil, "private"), Name: "secret", ParentID: privateChannelRootUUID, IsVisible: true}
+	archived := &model.Channel{ID: uuid.NewV3(uuid.Nil, "private archived"), Name: "old", ParentID: privateChannelRootUUID, IsVisible: false}
+
+	t.Run("ErrInvalidChannel", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		err := cm.AddPrivateChannelMembers(cA, set.UUIDSetFromArray([]uuid.UUID{uid}), uuid.Nil)
+		assert.EqualError(t, err, ErrInvalidChannel.Error())
+	})
+
+	t.Run("ErrChannelArchived", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		repo.EXPECT().
+			GetChannel(archived.ID).
+			Return(archived, nil).
+			AnyTimes()
+
+		err := cm.AddPrivateChannelMembers(archived.ID, set.UUIDSetFromArray([]uuid.UUID{uid}), uuid.Nil)
+		assert.EqualError(t, err, ErrChannelArchived.Error())
+	})
+
+	t.Run("success (no change)", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		repo.EXPECT().
+			GetChannel(private.ID).
+			Return(private, nil).
+			AnyTimes()
+		repo.EXPECT().
+			AddPrivateChannelMembers(private.ID, set.UUIDSetFromArray([]uuid.UUID{uid})).
+			Return([]uuid.UUID{}, nil).
+			Times(1)
+
+		err := cm.AddPrivateChannelMembers(private.ID, set.UUIDSetFromArray([]uuid.UUID{uid}), uuid.Nil)
+		assert.NoError(t, err)
+	})
+}
+
+func TestManagerImpl_RemovePrivateChannelMember(t *testing.T) {
+	t.Parallel()
+
+	uid := uuid.NewV3(uuid.Nil, "u1")
+	private := &model.Channel{ID: uuid.NewV3(uuid.Nil, "private"), Name: "secret", ParentID: privateChannelRootUUID, IsVisible: true}
+
+	t.Run("ErrInvalidChannel", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		err := cm.RemovePrivateChannelMember(cA, uid, uid)
+		assert.EqualError(t, err, ErrInvalidChannel.Error())
+	})
+
+	t.Run("ErrNotChannelMember", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		repo.EXPECT().
+			GetChannel(private.ID).
+			Return(private, nil).
+			AnyTimes()
+		repo.EXPECT().
+			RemovePrivateChannelMember(private.ID, uid).
+			Return(repository.ErrNotFound).
+			Times(1)
+
+		err := cm.RemovePrivateChannelMember(private.ID, uid, uid)
+		assert.EqualError(t, err, ErrNotChannelMember.Error())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		repo.EXPECT().
+			GetChannel(private.ID).
+			Return(private, nil).
+			AnyTimes()
+		repo.EXPECT().
+			RemovePrivateChannelMember(private.ID, uid).
+			Return(nil).
+			Times(1)
+		repo.EXPECT().
+			RecordChannelEvent(private.ID, model.ChannelEventMembersChanged, gomock.Any(), gomock.Any()).
+			Return(nil).
+			Times(1)
+
+		err := cm.RemovePrivateChannelMember(private.ID, uid, uid)
+		cm.P.Wait()
+		assert.NoError(t, err)
+	})
+}
+
 func TestManagerImpl_IsChannelAccessibleToUser(t *testing.T) {
 	t.Parallel()
 
@@ -1152,3 +1749,60 @@ func TestManagerImpl_IsPublicChannel(t *testing.T) {
 	assert.True(t, cm.IsPublicChannel(cA))
 	assert.False(t, cm.IsPublicChannel(cNotFound))
 }
+
+func TestManagerImpl_GetUserChannelRoles(t *testing.T) {
+	t.Parallel()
+
+	uid := uuid.NewV3(uuid.Nil, "u1")
+
+	t.Run("public channel", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		repo.EXPECT().
+			GetUserChannelRoles(uid, gomock.Any()).
+			DoAndReturn(func(_ uuid.UUID, ids []uuid.UUID) ([]*model.ChannelRole, error) {
+				assert.Subset(t, ids, []uuid.UUID{cABC, cAB, cA})
+				assert.NotContains(t, ids, cABCD)
+				return []*model.ChannelRole{{ChannelID: cA, UserID: uid, Role: "moderator"}}, nil
+			}).
+			Times(1)
+
+		roles, err := cm.GetUserChannelRoles(uid, cABC)
+		if assert.NoError(t, err) {
+			assert.ElementsMatch(t, []string{"moderator"}, roles)
+		}
+	})
+
+	t.Run("not public channel", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		roles, err := cm.GetUserChannelRoles(uid, cNotFound)
+		if assert.NoError(t, err) {
+			assert.Empty(t, roles)
+		}
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := mock_repository.NewMockChannelRepository(ctrl)
+		cm := initCM(t, repo)
+
+		mockErr := errors.New("mock error")
+		repo.EXPECT().
+			GetUserChannelRoles(uid, gomock.Any()).
+			Return(nil, mockErr).
+			Times(1)
+
+		_, err := cm.GetUserChannelRoles(uid, cA)
+		if assert.Error(t, err) {
+			assert.Equal(t, mockErr, errors.Unwrap(err))
+		}
+	})
+}
@@ -12,6 +12,7 @@ import (
 	model "github.com/traPtitech/traQ/model"
 	repository "github.com/traPtitech/traQ/repository"
 	channel "github.com/traPtitech/traQ/service/channel"
+	set "github.com/traPtitech/traQ/utils/set"
 )
 
 // MockManager is a mock of Manager interface.
@@ -37,6 +38,20 @@ func (m *MockManager) EXPECT() *MockManagerMockRecorder {
 	return m.recorder
 }
 
+// AddPrivateChannelMembers mocks base method.
+func (m *MockManager) AddPrivateChannelMembers(id uuid.UUID, userIDs set.UUID, updaterID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPrivateChannelMembers", id, userIDs, updaterID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddPrivateChannelMembers indicates an expected call of AddPrivateChannelMembers.
+func (mr *MockManagerMockRecorder) AddPrivateChannelMembers(id, userIDs, updaterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPrivateChannelMembers", reflect.TypeOf((*MockManager)(nil).AddPrivateChannelMembers), id, userIDs, updaterID)
+}
+
 // ArchiveChannel mocks base method.
 func (m *MockManager) ArchiveChannel(id, updaterID uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -65,6 +80,21 @@ func (mr *MockManagerMockRecorder) ChangeChannelSubscriptions(channelID, subscri
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeChannelSubscriptions", reflect.TypeOf((*MockManager)(nil).ChangeChannelSubscriptions), channelID, subscriptions, keepOffLevel, updaterID)
 }
 
+// CreatePrivateChannel mocks base method.
+func (m *MockManager) CreatePrivateChannel(name string, creatorID uuid.UUID, members set.UUID) (*model.Channel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePrivateChannel", name, creatorID, members)
+	ret0, _ := ret[0].(*model.Channel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePrivateChannel indicates an expected call of CreatePrivateChannel.
+func (mr *MockManagerMockRecorder) CreatePrivateChannel(name, creatorID, members interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePrivateChannel", reflect.TypeOf((*MockManager)(nil).CreatePrivateChannel), name, creatorID, members)
+}
+
 // CreatePublicChannel mocks base method.
 func (m *MockManager) CreatePublicChannel(name string, parent, creatorID uuid.UUID) (*model.Channel, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +110,35 @@ func (mr *MockManagerMockRecorder) CreatePublicChannel(name, parent, creatorID i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePublicChannel", reflect.TypeOf((*MockManager)(nil).CreatePublicChannel), name, parent, creatorID)
 }
 
+// CreatePublicChannelsFromTemplate mocks base method.
+func (m *MockManager) CreatePublicChannelsFromTemplate(parent uuid.UUID, channels model.ChannelTemplateNodes, creatorID uuid.UUID) ([]*model.Channel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePublicChannelsFromTemplate", parent, channels, creatorID)
+	ret0, _ := ret[0].([]*model.Channel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePublicChannelsFromTemplate indicates an expected call of CreatePublicChannelsFromTemplate.
+func (mr *MockManagerMockRecorder) CreatePublicChannelsFromTemplate(parent, channels, creatorID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePublicChannelsFromTemplate", reflect.TypeOf((*MockManager)(nil).CreatePublicChannelsFromTemplate), parent, channels, creatorID)
+}
+
+// DeleteChannel mocks base method.
+func (m *MockManager) DeleteChannel(id, deleterID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteChannel", id, deleterID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteChannel indicates an expected call of DeleteChannel.
+func (mr *MockManagerMockRecorder) DeleteChannel(id, deleterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteChannel", reflect.TypeOf((*MockManager)(nil).DeleteChannel), id, deleterID)
+}
+
 // GetChannel mocks base method.
 func (m *MockManager) GetChannel(id uuid.UUID) (*model.Channel, error) {
 	m.ctrl.T.Helper()
@@ -140,6 +199,81 @@ func (mr *MockManagerMockRecorder) GetDMChannelMembers(id interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDMChannelMembers", reflect.TypeOf((*MockManager)(nil).GetDMChannelMembers), id)
 }
 
+// GetGroupDMChannel mocks base method.
+func (m *MockManager) GetGroupDMChannel(members set.UUID) (*model.Channel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupDMChannel", members)
+	ret0, _ := ret[0].(*model.Channel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupDMChannel indicates an expected call of GetGroupDMChannel.
+func (mr *MockManagerMockRecorder) GetGroupDMChannel(members interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupDMChannel", reflect.TypeOf((*MockManager)(nil).GetGroupDMChannel), members)
+}
+
+// GetGroupDMChannelMapping mocks base method.
+func (m *MockManager) GetGroupDMChannelMapping(userID uuid.UUID) (map[uuid.UUID][]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGroupDMChannelMapping", userID)
+	ret0, _ := ret[0].(map[uuid.UUID][]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGroupDMChannelMapping indicates an expected call of GetGroupDMChannelMapping.
+func (mr *MockManagerMockRecorder) GetGroupDMChannelMapping(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGroupDMChannelMapping", reflect.TypeOf((*MockManager)(nil).GetGroupDMChannelMapping), userID)
+}
+
+// GetPrivateChannelMembers mocks base method.
+func (m *MockManager) GetPrivateChannelMembers(id uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrivateChannelMembers", id)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPrivateChannelMembers indicates an expected call of GetPrivateChannelMembers.
+func (mr *MockManagerMockRecorder) GetPrivateChannelMembers(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrivateChannelMembers", reflect.TypeOf((*MockManager)(nil).GetPrivateChannelMembers), id)
+}
+
+// GetUserChannelRoles mocks base method.
+func (m *MockManager) GetUserChannelRoles(userID, channelID uuid.UUID) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserChannelRoles", userID, channelID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserChannelRoles indicates an expected call of GetUserChannelRoles.
+func (mr *MockManagerMockRecorder) GetUserChannelRoles(userID, channelID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserChannelRoles", reflect.TypeOf((*MockManager)(nil).GetUserChannelRoles), userID, channelID)
+}
+
+// GetUserPrivateChannels mocks base method.
+func (m *MockManager) GetUserPrivateChannels(userID uuid.UUID) ([]*model.Channel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserPrivateChannels", userID)
+	ret0, _ := ret[0].([]*model.Channel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserPrivateChannels indicates an expected call of GetUserPrivateChannels.
+func (mr *MockManagerMockRecorder) GetUserPrivateChannels(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserPrivateChannels", reflect.TypeOf((*MockManager)(nil).GetUserPrivateChannels), userID)
+}
+
 // IsChannelAccessibleToUser mocks base method.
 func (m *MockManager) IsChannelAccessibleToUser(userID, channelID uuid.UUID) (bool, error) {
 	m.ctrl.T.Helper()
@@ -169,6 +303,20 @@ func (mr *MockManagerMockRecorder) IsPublicChannel(id interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsPublicChannel", reflect.TypeOf((*MockManager)(nil).IsPublicChannel), id)
 }
 
+// MergeChannel mocks base method.
+func (m *MockManager) MergeChannel(srcID, dstID, updaterID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeChannel", srcID, dstID, updaterID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MergeChannel indicates an expected call of MergeChannel.
+func (mr *MockManagerMockRecorder) MergeChannel(srcID, dstID, updaterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeChannel", reflect.TypeOf((*MockManager)(nil).MergeChannel), srcID, dstID, updaterID)
+}
+
 // PublicChannelTree mocks base method.
 func (m *MockManager) PublicChannelTree() channel.Tree {
 	m.ctrl.T.Helper()
@@ -183,6 +331,20 @@ func (mr *MockManagerMockRecorder) PublicChannelTree() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublicChannelTree", reflect.TypeOf((*MockManager)(nil).PublicChannelTree))
 }
 
+// RemovePrivateChannelMember mocks base method.
+func (m *MockManager) RemovePrivateChannelMember(id, userID, updaterID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemovePrivateChannelMember", id, userID, updaterID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemovePrivateChannelMember indicates an expected call of RemovePrivateChannelMember.
+func (mr *MockManagerMockRecorder) RemovePrivateChannelMember(id, userID, updaterID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemovePrivateChannelMember", reflect.TypeOf((*MockManager)(nil).RemovePrivateChannelMember), id, userID, updaterID)
+}
+
 // UnarchiveChannel mocks base method.
 func (m *MockManager) UnarchiveChannel(id, updaterID uuid.UUID) error {
 	m.ctrl.T.Helper()
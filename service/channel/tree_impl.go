@@ -243,6 +243,25 @@ func (ct *treeImpl) move(id uuid.UUID, newParent optional.UUID, newName optional
 	ct.regenerateJSON()
 }
 
+func (ct *treeImpl) remove(id uuid.UUID) {
+	n, ok := ct.nodes[id]
+	if !ok {
+		panic("assert !ok = false")
+	}
+	if len(n.children) > 0 {
+		panic("assert len(n.children) > 0 = false")
+	}
+
+	if n.parent != nil {
+		delete(n.parent.children, n.id)
+	} else {
+		delete(ct.roots, n.id)
+	}
+	delete(ct.nodes, n.id)
+	delete(ct.paths, n.id)
+	ct.regenerateJSON()
+}
+
 func (ct *treeImpl) updateSingle(id uuid.UUID, ch *model.Channel) {
 	ct.update(id, ch)
 	ct.regenerateJSON()
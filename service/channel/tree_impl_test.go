@@ -105,6 +105,27 @@ func TestTreeImpl_move(t *testing.T) {
 
 }
 
+func TestTreeImpl_remove(t *testing.T) {
+	t.Parallel()
+	tree := makeTestChannelTree(t)
+
+	assert.Panics(t, func() { tree.remove(cNotFound) })
+	assert.Panics(t, func() { tree.remove(cABC) })
+
+	// (root)/a/b/c/dを削除
+	tree.remove(cABCD)
+	assert.False(t, tree.isChannelPresent(cABCD))
+	assert.False(t, tree.isChildPresent("d", cABC))
+	assert.ElementsMatch(t, []uuid.UUID{cABCE}, tree.getChildrenIDs(cABC))
+	assert.Equal(t, uuid.Nil, tree.getChannelIDFromPath("a/b/c/d"))
+
+	// (root)/a/b/c/eを削除し、子のなくなった(root)/a/b/cを削除
+	tree.remove(cABCE)
+	tree.remove(cABC)
+	assert.False(t, tree.isChannelPresent(cABC))
+	assert.False(t, tree.isChildPresent("c", cAB))
+}
+
 func TestChannelTreeImpl_GetChildrenIDs(t *testing.T) {
 	t.Parallel()
 	tree := makeTestChannelTree(t)
@@ -7,9 +7,11 @@ import (
 	"time"
 
 	"github.com/gofrs/uuid"
+	"github.com/leandro-lugaresi/hub"
 	"github.com/motoki317/sc"
 	"go.uber.org/zap"
 
+	"github.com/traPtitech/traQ/event"
 	"github.com/traPtitech/traQ/model"
 	"github.com/traPtitech/traQ/repository"
 	"github.com/traPtitech/traQ/service/channel"
@@ -31,8 +33,8 @@ type manager struct {
 	cache *sc.Cache[uuid.UUID, *message]
 }
 
-func NewMessageManager(repo repository.Repository, cm channel.Manager, logger *zap.Logger) (Manager, error) {
-	return &manager{
+func NewMessageManager(repo repository.Repository, cm channel.Manager, hub *hub.Hub, logger *zap.Logger) (Manager, error) {
+	m := &manager{
 		CM: cm,
 		R:  repo,
 		L:  logger.Named("message_manager"),
@@ -46,7 +48,21 @@ func NewMessageManager(repo repository.Repository, cm channel.Manager, logger *z
 			}
 			return &message{Model: m}, nil
 		}, cacheTTL, cacheTTL*2, sc.With2QBackend(cacheSize)),
-	}, nil
+	}
+	sub := hub.Subscribe(100, event.ChannelMerged, event.MessageDeleted)
+	go func() {
+		for ev := range sub.Receiver {
+			switch ev.Topic() {
+			case event.ChannelMerged:
+				// 統合元チャンネルのメッセージが統合前のチャンネルIDでキャッシュされているため破棄
+				m.cache.Purge()
+			case event.MessageDeleted:
+				// 保持ポリシー等、Managerを経由せずに削除されたメッセージのキャッシュを破棄
+				m.cache.Forget(ev.Fields["message_id"].(uuid.UUID))
+			}
+		}
+	}()
+	return m, nil
 }
 
 func (m *manager) Get(id uuid.UUID) (Message, error) {
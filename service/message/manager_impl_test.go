@@ -6,9 +6,12 @@ import (
 
 	"github.com/gofrs/uuid"
 	"github.com/golang/mock/gomock"
+	"github.com/leandro-lugaresi/hub"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
+	"github.com/traPtitech/traQ/event"
 	"github.com/traPtitech/traQ/model"
 	"github.com/traPtitech/traQ/repository"
 	"github.com/traPtitech/traQ/service/channel/mock_channel"
@@ -19,7 +22,7 @@ func setupM(ctrl *gomock.Controller) (Manager, *mock_channel.MockManager, *Repo,
 	tree := mock_channel.NewMockTree(ctrl)
 	cm.EXPECT().PublicChannelTree().Return(tree).AnyTimes()
 	repo := NewMockRepo(ctrl)
-	m, _ := NewMessageManager(repo, cm, zap.NewNop())
+	m, _ := NewMessageManager(repo, cm, hub.New(), zap.NewNop())
 	return m, cm, repo, tree
 }
 
@@ -97,6 +100,63 @@ func TestManager_Get(t *testing.T) {
 		_, err := m.Get(id)
 		assert.EqualError(t, err, ErrNotFound.Error())
 	})
+
+	t.Run("cache is purged on channel merge", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := NewMockRepo(ctrl)
+		h := hub.New()
+		m, _ := NewMessageManager(repo, mock_channel.NewMockManager(ctrl), h, zap.NewNop())
+
+		src := uuid.NewV3(uuid.Nil, "c1")
+		dst := uuid.NewV3(uuid.Nil, "c2")
+		msg := &model.Message{ID: uuid.NewV3(uuid.Nil, "m1"), ChannelID: src}
+		merged := &model.Message{ID: msg.ID, ChannelID: dst}
+		gomock.InOrder(
+			repo.MockMessageRepository.EXPECT().GetMessageByID(msg.ID).Return(msg, nil).Times(1),
+			repo.MockMessageRepository.EXPECT().GetMessageByID(msg.ID).Return(merged, nil).Times(1),
+		)
+
+		result, err := m.Get(msg.ID)
+		require.NoError(t, err)
+		assert.Equal(t, src, result.GetChannelID())
+
+		h.Publish(hub.Message{
+			Name:   event.ChannelMerged,
+			Fields: hub.Fields{"channel_id": src, "target_id": dst},
+		})
+		assert.Eventually(t, func() bool {
+			result, err := m.Get(msg.ID)
+			return err == nil && result.GetChannelID() == dst
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("cache is forgotten on message deleted", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		repo := NewMockRepo(ctrl)
+		h := hub.New()
+		m, _ := NewMessageManager(repo, mock_channel.NewMockManager(ctrl), h, zap.NewNop())
+
+		msg := &model.Message{ID: uuid.NewV3(uuid.Nil, "m1")}
+		gomock.InOrder(
+			repo.MockMessageRepository.EXPECT().GetMessageByID(msg.ID).Return(msg, nil).Times(1),
+			repo.MockMessageRepository.EXPECT().GetMessageByID(msg.ID).Return(nil, repository.ErrNotFound).Times(1),
+		)
+
+		_, err := m.Get(msg.ID)
+		require.NoError(t, err)
+
+		// Managerを経由しない削除
+		h.Publish(hub.Message{
+			Name:   event.MessageDeleted,
+			Fields: hub.Fields{"message_id": msg.ID, "message": msg},
+		})
+		assert.Eventually(t, func() bool {
+			_, err := m.Get(msg.ID)
+			return err == ErrNotFound
+		}, time.Second, 10*time.Millisecond)
+	})
 }
 
 func TestManager_Create(t *testing.T) {
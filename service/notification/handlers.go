@@ -39,6 +39,8 @@ var handlerMap = map[string]eventHandler{
 	event.ChannelRead:               channelReadHandler,
 	event.ChannelViewersChanged:     channelViewersChangedHandler,
 	event.ChannelSubscribersChanged: channelSubscribersChangedHandler,
+	event.ChannelMembersChanged:     channelMembersChangedHandler,
+	event.ChannelMerged:             channelMergedHandler,
 	event.UserCreated:               userCreatedHandler,
 	event.UserUpdated:               userUpdatedHandler,
 	event.UserIconUpdated:           userIconUpdatedHandler,
@@ -56,18 +58,24 @@ var handlerMap = map[string]eventHandler{
 	event.UserGroupMemberRemoved:    userGroupUpdatedHandler,
 	event.UserGroupAdminAdded:       userGroupUpdatedHandler,
 	event.UserGroupAdminRemoved:     userGroupUpdatedHandler,
+	event.UserRoleCreated:           userRoleUpdatedHandler,
+	event.UserRoleUpdated:           userRoleUpdatedHandler,
+	event.UserRoleDeleted:           userRoleUpdatedHandler,
 	event.StampCreated:              stampCreatedHandler,
 	event.StampUpdated:              stampUpdatedHandler,
 	event.StampDeleted:              stampDeletedHandler,
 	event.StampPaletteCreated:       stampPaletteCreatedHandler,
 	event.StampPaletteUpdated:       stampPaletteUpdatedHandler,
 	event.StampPaletteDeleted:       stampPaletteDeletedHandler,
+	event.StampPaletteSubscribed:    stampPaletteSubscribedHandler,
+	event.StampPaletteUnsubscribed:  stampPaletteUnsubscribedHandler,
 	event.UserWebRTCv3StateChanged:  userWebRTCv3StateChangedHandler,
 	event.ClipFolderCreated:         clipFolderCreatedHandler,
 	event.ClipFolderUpdated:         clipFolderUpdatedHandler,
 	event.ClipFolderDeleted:         clipFolderDeletedHandler,
 	event.ClipFolderMessageDeleted:  clipFolderMessageDeletedHandler,
 	event.ClipFolderMessageAdded:    clipFolderMessageAddedHandler,
+	event.SidebarUpdated:            sidebarUpdatedHandler,
 }
 
 func messageCreatedHandler(ns *Service, ev hub.Message) {
@@ -77,9 +85,24 @@ func messageCreatedHandler(ns *Service, ev hub.Message) {
 
 	chTree := ns.cm.PublicChannelTree()
 	chID := m.ChannelID
-	isDM := !chTree.IsChannelPresent(chID)
+	isDM := !chTree.IsChannelPresent(chID) // DM・プライベートチャンネル
 	forceNotify := chTree.IsForceChannel(chID)
 
+	var privateCh, groupDMCh *model.Channel
+	if isDM {
+		ch, err := ns.cm.GetChannel(chID)
+		if err != nil {
+			logger.Error("failed to GetChannel", zap.Error(err), zap.Stringer("channelId", chID)) // 失敗
+			return
+		}
+		switch {
+		case ch.IsPrivateChannel():
+			privateCh = ch
+		case ch.IsGroupDMChannel():
+			groupDMCh = ch
+		}
+	}
+
 	// 投稿ユーザー情報を取得
 	mUser, err := ns.repo.GetUser(m.UserID, false)
 	if err != nil {
@@ -116,6 +139,16 @@ func messageCreatedHandler(ns *Service, ev hub.Message) {
 		fcmPayload.Title = "#" + path
 		fcmPayload.Path = "/channels/" + path
 		fcmPayload.SetBodyWithEllipsis(mUser.GetResponseDisplayName() + ": " + parsed.OneLine())
+	} else if privateCh != nil {
+		// プライベートチャンネル
+		fcmPayload.Title = "#" + privateCh.Name
+		fcmPayload.Path = "/private-channels/" + privateCh.ID.String()
+		fcmPayload.SetBodyWithEllipsis(mUser.GetResponseDisplayName() + ": " + parsed.OneLine())
+	} else if groupDMCh != nil {
+		// グループDM
+		fcmPayload.Title = "@" + mUser.GetResponseDisplayName()
+		fcmPayload.Path = "/group-dm-channels/" + groupDMCh.ID.String()
+		fcmPayload.SetBodyWithEllipsis(parsed.OneLine())
 	} else {
 		// DM
 		fcmPayload.Title = "@" + mUser.GetResponseDisplayName()
@@ -144,7 +177,7 @@ func messageCreatedHandler(ns *Service, ev hub.Message) {
 		markedUsers.Add(users...)
 		noticeable.Add(users...)
 
-	case isDM: // DM
+	case isDM: // DM・プライベートチャンネル
 		users, err := ns.repo.GetUserIDs(q.CMemberOf(chID))
 		if err != nil {
 			logger.Error("failed to GetPrivateChannelMemberIDs", zap.Error(err), zap.Stringer("channelId", m.ChannelID)) // 失敗
@@ -411,6 +444,32 @@ func channelSubscribersChangedHandler(ns *Service, ev hub.Message) {
 	)
 }
 
+func channelMembersChangedHandler(ns *Service, ev hub.Message) {
+	cid := ev.Fields["channel_id"].(uuid.UUID)
+	members, err := ns.cm.GetPrivateChannelMembers(cid)
+	if err != nil {
+		ns.logger.Error("failed to GetPrivateChannelMembers", zap.Error(err), zap.Stringer("channelId", cid))
+		return
+	}
+
+	// 削除されたメンバーにも通知する
+	targets := set.UUIDSetFromArray(members)
+	targets.Add(ev.Fields["removed"].([]uuid.UUID)...)
+	go ns.ws.WriteMessage("CHANNEL_MEMBERS_CHANGED", map[string]interface{}{
+		"id": cid,
+	}, ws.TargetUserSets(targets))
+}
+
+func channelMergedHandler(ns *Service, ev hub.Message) {
+	broadcast(ns,
+		"CHANNEL_MERGED",
+		map[string]interface{}{
+			"id":        ev.Fields["channel_id"].(uuid.UUID),
+			"target_id": ev.Fields["target_id"].(uuid.UUID),
+		},
+	)
+}
+
 func userCreatedHandler(ns *Service, ev hub.Message) {
 	broadcast(ns,
 		"USER_JOINED",
@@ -517,6 +576,15 @@ func userGroupDeletedHandler(ns *Service, ev hub.Message) {
 	)
 }
 
+func userRoleUpdatedHandler(ns *Service, ev hub.Message) {
+	broadcast(ns,
+		"USER_ROLE_UPDATED",
+		map[string]interface{}{
+			"name": ev.Fields["role_name"].(string),
+		},
+	)
+}
+
 func stampCreatedHandler(ns *Service, ev hub.Message) {
 	broadcast(ns,
 		"STAMP_CREATED",
@@ -554,17 +622,33 @@ func stampPaletteCreatedHandler(ns *Service, ev hub.Message) {
 }
 
 func stampPaletteUpdatedHandler(ns *Service, ev hub.Message) {
+	// 作成者と購読者に通知
+	targets := append([]uuid.UUID{ev.Fields["user_id"].(uuid.UUID)}, ev.Fields["subscriber_ids"].([]uuid.UUID)...)
+	go ns.ws.WriteMessage("STAMP_PALETTE_UPDATED", map[string]interface{}{
+		"id": ev.Fields["stamp_palette_id"].(uuid.UUID),
+	}, ws.TargetUsers(targets...))
+}
+
+func stampPaletteDeletedHandler(ns *Service, ev hub.Message) {
+	// 作成者と購読者に通知
+	targets := append([]uuid.UUID{ev.Fields["user_id"].(uuid.UUID)}, ev.Fields["subscriber_ids"].([]uuid.UUID)...)
+	go ns.ws.WriteMessage("STAMP_PALETTE_DELETED", map[string]interface{}{
+		"id": ev.Fields["stamp_palette_id"].(uuid.UUID),
+	}, ws.TargetUsers(targets...))
+}
+
+func stampPaletteSubscribedHandler(ns *Service, ev hub.Message) {
 	userMulticast(ns, ev.Fields["user_id"].(uuid.UUID),
-		"STAMP_PALETTE_UPDATED",
+		"STAMP_PALETTE_SUBSCRIBED",
 		map[string]interface{}{
 			"id": ev.Fields["stamp_palette_id"].(uuid.UUID),
 		},
 	)
 }
 
-func stampPaletteDeletedHandler(ns *Service, ev hub.Message) {
+func stampPaletteUnsubscribedHandler(ns *Service, ev hub.Message) {
 	userMulticast(ns, ev.Fields["user_id"].(uuid.UUID),
-		"STAMP_PALETTE_DELETED",
+		"STAMP_PALETTE_UNSUBSCRIBED",
 		map[string]interface{}{
 			"id": ev.Fields["stamp_palette_id"].(uuid.UUID),
 		},
@@ -625,6 +709,16 @@ func clipFolderMessageDeletedHandler(ns *Service, ev hub.Message) {
 	)
 }
 
+func sidebarUpdatedHandler(ns *Service, ev hub.Message) {
+	userID := ev.Fields["user_id"].(uuid.UUID)
+	userMulticast(ns, userID,
+		"SIDEBAR_UPDATED",
+		map[string]interface{}{
+			"id": userID,
+		},
+	)
+}
+
 func clipFolderMessageAddedHandler(ns *Service, ev hub.Message) {
 	userMulticast(ns, ev.Fields["user_id"].(uuid.UUID),
 		"CLIP_FOLDER_MESSAGE_ADDED",
@@ -639,6 +733,23 @@ func channelHandler(ns *Service, ev hub.Message, eventType string) {
 	cid := ev.Fields["channel_id"].(uuid.UUID)
 	private := ev.Fields["private"].(bool)
 	if private {
+		ch, err := ns.cm.GetChannel(cid)
+		if err != nil {
+			ns.logger.Error("failed to GetChannel", zap.Error(err), zap.Stringer("channelId", cid))
+			return
+		}
+		if ch.IsPrivateChannel() || ch.IsGroupDMChannel() {
+			members, err := ns.cm.GetPrivateChannelMembers(cid)
+			if err != nil {
+				ns.logger.Error("failed to GetPrivateChannelMembers", zap.Error(err), zap.Stringer("channelId", cid))
+				return
+			}
+			go ns.ws.WriteMessage(eventType, map[string]interface{}{
+				"id": cid,
+			}, ws.TargetUsers(members...))
+			return
+		}
+
 		members, err := ns.cm.GetDMChannelMembers(cid)
 		if err != nil {
 			ns.logger.Error("failed to GetDMChannelMembers", zap.Error(err), zap.Stringer("channelId", cid))
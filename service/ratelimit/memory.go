@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// memoryStore インメモリのレート制限状態ストア
+type memoryStore struct {
+	buckets map[string]*tokenBucket
+	lastGC  time.Time
+	now     func() time.Time
+	sync.Mutex
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+	// full バケットが満杯に戻る時刻
+	full time.Time
+}
+
+// gcInterval 満杯になったバケットを破棄する間隔
+const gcInterval = time.Minute
+
+// NewMemoryStore インメモリのレート制限状態ストアを生成します
+func NewMemoryStore() Store {
+	return newMemoryStore(time.Now)
+}
+
+func newMemoryStore(now func() time.Time) *memoryStore {
+	return &memoryStore{
+		buckets: map[string]*tokenBucket{},
+		lastGC:  now(),
+		now:     now,
+	}
+}
+
+// Take implements Store interface.
+func (s *memoryStore) Take(key string, rate Rate) (Result, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastGC) >= gcInterval {
+		for k, b := range s.buckets {
+			if !now.Before(b.full) {
+				delete(s.buckets, k)
+			}
+		}
+		s.lastGC = now
+	}
+
+	capacity := float64(rate.Limit)
+	interval := rate.Period / time.Duration(rate.Limit) // トークン1つの補充間隔
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: capacity, last: now}
+		s.buckets[key] = b
+	} else {
+		b.tokens = math.Min(capacity, b.tokens+float64(now.Sub(b.last))/float64(interval))
+		b.last = now
+	}
+
+	res := Result{Limit: rate.Limit}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = time.Duration((1 - b.tokens) * float64(interval))
+	}
+	res.Remaining = int(b.tokens)
+	res.Reset = time.Duration((capacity - b.tokens) * float64(interval))
+	b.full = now.Add(res.Reset)
+	return res, nil
+}
@@ -0,0 +1,106 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.t
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.t = c.t.Add(d)
+}
+
+func TestMemoryStore_Take(t *testing.T) {
+	t.Parallel()
+
+	rate := Rate{Limit: 3, Period: 3 * time.Second}
+
+	t.Run("burst", func(t *testing.T) {
+		t.Parallel()
+		clock := &fakeClock{t: time.Unix(0, 0)}
+		s := newMemoryStore(clock.now)
+
+		for i := 0; i < rate.Limit; i++ {
+			res, err := s.Take("a", rate)
+			if assert.NoError(t, err) {
+				assert.True(t, res.Allowed)
+				assert.Equal(t, rate.Limit, res.Limit)
+				assert.Equal(t, rate.Limit-1-i, res.Remaining)
+			}
+		}
+
+		res, err := s.Take("a", rate)
+		if assert.NoError(t, err) {
+			assert.False(t, res.Allowed)
+			assert.Equal(t, 0, res.Remaining)
+			assert.Equal(t, time.Second, res.RetryAfter)
+			assert.Equal(t, 3*time.Second, res.Reset)
+		}
+
+		// 別のキーは影響を受けない
+		res, err = s.Take("b", rate)
+		if assert.NoError(t, err) {
+			assert.True(t, res.Allowed)
+		}
+	})
+
+	t.Run("refill", func(t *testing.T) {
+		t.Parallel()
+		clock := &fakeClock{t: time.Unix(0, 0)}
+		s := newMemoryStore(clock.now)
+
+		for i := 0; i < rate.Limit; i++ {
+			_, _ = s.Take("a", rate)
+		}
+
+		clock.advance(500 * time.Millisecond)
+		res, err := s.Take("a", rate)
+		if assert.NoError(t, err) {
+			assert.False(t, res.Allowed)
+			assert.Equal(t, 500*time.Millisecond, res.RetryAfter)
+		}
+
+		clock.advance(500 * time.Millisecond)
+		res, err = s.Take("a", rate)
+		if assert.NoError(t, err) {
+			assert.True(t, res.Allowed)
+		}
+
+		// 容量を超えて補充されない
+		clock.advance(time.Hour)
+		for i := 0; i < rate.Limit; i++ {
+			res, _ = s.Take("a", rate)
+			assert.True(t, res.Allowed)
+		}
+		res, _ = s.Take("a", rate)
+		assert.False(t, res.Allowed)
+	})
+
+	t.Run("gc", func(t *testing.T) {
+		t.Parallel()
+		clock := &fakeClock{t: time.Unix(0, 0)}
+		s := newMemoryStore(clock.now)
+
+		_, _ = s.Take("a", rate)
+		clock.advance(gcInterval - 2*time.Second)
+		for i := 0; i < rate.Limit; i++ {
+			_, _ = s.Take("b", rate)
+		}
+		clock.advance(2 * time.Second)
+		_, _ = s.Take("c", rate)
+
+		// 満杯に戻ったバケットのみ破棄される
+		assert.NotContains(t, s.buckets, "a")
+		assert.Contains(t, s.buckets, "b")
+		assert.Contains(t, s.buckets, "c")
+	})
+}
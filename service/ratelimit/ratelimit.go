@@ -0,0 +1,70 @@
+// Package ratelimit トークンバケットによるレート制限
+package ratelimit
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Rate レート制限値
+//
+// Period毎にLimit回までのリクエストを許可します(トークンバケット)。
+// バケットの容量はLimitで、トークンはPeriod/Limit毎に1つずつ補充されます。
+type Rate struct {
+	Limit  int
+	Period time.Duration
+}
+
+// Valid 有効なレート制限値かどうか
+func (r Rate) Valid() bool {
+	return r.Limit > 0 && r.Period > 0
+}
+
+// Rule 操作毎のレート制限ルール
+type Rule struct {
+	// Name ルール名 同じ名前のルールはバケットを共有します
+	Name string
+	// Default デフォルトのレート制限値
+	Default Rate
+	// Roles ユーザーロール毎のレート制限値
+	Roles map[string]Rate
+}
+
+// RateFor 指定したロールに適用されるレート制限値を返します
+func (r Rule) RateFor(role string) Rate {
+	if rate, ok := r.Roles[role]; ok {
+		return rate
+	}
+	return r.Default
+}
+
+// Key 指定したキーのこのルールでのバケットのキーを返します
+func (r Rule) Key(key string) string {
+	return r.Name + ":" + key
+}
+
+// UserKey ユーザー毎のレート制限キーを返します
+func UserKey(userID uuid.UUID) string {
+	return "user:" + userID.String()
+}
+
+// Result レート制限の判定結果
+type Result struct {
+	// Allowed リクエストが許可されたかどうか
+	Allowed bool
+	// Limit バケットの容量
+	Limit int
+	// Remaining 残りリクエスト可能回数
+	Remaining int
+	// Reset バケットが満杯に戻るまでの時間
+	Reset time.Duration
+	// RetryAfter 次にリクエストが可能になるまでの時間
+	RetryAfter time.Duration
+}
+
+// Store レート制限の状態ストア
+type Store interface {
+	// Take keyのバケットからトークンを1つ取り出します
+	Take(key string, rate Rate) (Result, error)
+}
@@ -0,0 +1,39 @@
+package ratelimit
+
+import (
+	"time"
+
+	"github.com/traPtitech/traQ/service/rbac/role"
+)
+
+// 操作毎のレート制限ルール
+//
+// HTTP APIとBOT WebSocketのリクエストで同じルールを使い、バケットを共有します。
+var (
+	// PostMessage メッセージ投稿・編集
+	PostMessage = Rule{
+		Name:    "post_message",
+		Default: Rate{Limit: 30, Period: time.Minute},
+		Roles: map[string]Rate{
+			role.Bot: {Limit: 60, Period: time.Minute},
+		},
+	}
+	// AddMessageStamp メッセージへのスタンプ追加
+	AddMessageStamp = Rule{
+		Name:    "add_message_stamp",
+		Default: Rate{Limit: 120, Period: time.Minute},
+		Roles: map[string]Rate{
+			role.Bot: {Limit: 60, Period: time.Minute},
+		},
+	}
+	// Login ログイン・ユーザー登録 (IPアドレス毎)
+	Login = Rule{
+		Name:    "login",
+		Default: Rate{Limit: 10, Period: time.Minute},
+	}
+	// PostWebhook Webhookによるメッセージ投稿 (IPアドレス毎)
+	PostWebhook = Rule{
+		Name:    "post_webhook",
+		Default: Rate{Limit: 60, Period: time.Minute},
+	}
+)
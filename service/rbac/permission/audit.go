@@ -0,0 +1,6 @@
+package permission
+
+const (
+	// GetAuditLog 監査ログ取得権限
+	GetAuditLog = Permission("get_audit_log")
+)
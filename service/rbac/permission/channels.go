@@ -13,6 +13,18 @@ const (
 	ChangeParentChannel = Permission("change_parent_channel")
 	// EditChannelTopic チャンネルトピック変更権限
 	EditChannelTopic = Permission("edit_channel_topic")
+	// ManageChannelRole チャンネルロール管理権限
+	ManageChannelRole = Permission("manage_channel_role")
+	// EditPrivateChannelMember プライベートチャンネルメンバー変更権限
+	EditPrivateChannelMember = Permission("edit_private_channel_member")
+	// GetChannelTemplate チャンネルテンプレート取得権限
+	GetChannelTemplate = Permission("get_channel_template")
+	// ManageChannelTemplate チャンネルテンプレート管理・適用権限
+	ManageChannelTemplate = Permission("manage_channel_template")
+	// ManageChannelArchival チャンネル自動アーカイブ管理権限
+	ManageChannelArchival = Permission("manage_channel_archival")
+	// ManageMessageRetention メッセージ保持ポリシー管理権限
+	ManageMessageRetention = Permission("manage_message_retention")
 	// GetChannelStar チャンネルスター取得権限
 	GetChannelStar = Permission("get_channel_star")
 	// EditChannelStar チャンネルスター編集権限
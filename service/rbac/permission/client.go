@@ -5,6 +5,8 @@ const (
 	GetMyTokens = Permission("get_my_tokens")
 	// RevokeMyToken 自トークン削除権限
 	RevokeMyToken = Permission("revoke_my_token")
+	// CreateMyToken パーソナルアクセストークン発行権限
+	CreateMyToken = Permission("create_my_token")
 	// GetClients クライアント情報取得権限
 	GetClients = Permission("get_clients")
 	// CreateClient 新規クライアント登録権限
@@ -66,9 +66,16 @@ var List = []Permission{
 	DeleteChannel,
 	ChangeParentChannel,
 	EditChannelTopic,
+	ManageChannelRole,
+	EditPrivateChannelMember,
+	GetChannelTemplate,
+	ManageChannelTemplate,
+	ManageChannelArchival,
+	ManageMessageRetention,
 
 	GetMyTokens,
 	RevokeMyToken,
+	CreateMyToken,
 	GetClients,
 	CreateClient,
 	EditMyClient,
@@ -100,6 +107,10 @@ var List = []Permission{
 	GetMyExternalAccount,
 	EditMyExternalAccount,
 
+	GetMyTwoFactorAuth,
+	EditMyTwoFactorAuth,
+	ManageTwoFactorPolicy,
+
 	GetStamp,
 	CreateStamp,
 	EditStamp,
@@ -108,6 +119,7 @@ var List = []Permission{
 	AddMessageStamp,
 	RemoveMessageStamp,
 	GetMyStampHistory,
+	ImportStampPack,
 
 	GetChannelStar,
 	EditChannelStar,
@@ -129,6 +141,7 @@ var List = []Permission{
 	EditUserGroup,
 	DeleteUserGroup,
 	AllUserGroupsAdmin,
+	ManageUserRole,
 
 	GetUserTag,
 	EditUserTag,
@@ -144,4 +157,6 @@ var List = []Permission{
 	CreateStampPalette,
 	EditStampPalette,
 	DeleteStampPalette,
+
+	GetAuditLog,
 }
@@ -17,6 +17,8 @@ const (
 	RemoveMessageStamp = Permission("remove_message_stamp")
 	// GetMyStampHistory 自分のスタンプ履歴取得権限
 	GetMyStampHistory = Permission("get_my_stamp_history")
+	// ImportStampPack スタンプパックインポート権限
+	ImportStampPack = Permission("import_stamp_pack")
 
 	// GetStampPalette スタンプパレット取得権限
 	GetStampPalette = Permission("get_stamp_palette")
@@ -43,6 +43,14 @@ const (
 	GetMyExternalAccount = Permission("get_my_external_account")
 	// EditMyExternalAccount 外部ログインアカウント情報編集権限
 	EditMyExternalAccount = Permission("edit_my_external_account")
+	// GetMyTwoFactorAuth 二要素認証設定取得権限
+	GetMyTwoFactorAuth = Permission("get_my_two_factor_auth")
+	// EditMyTwoFactorAuth 二要素認証設定編集権限
+	EditMyTwoFactorAuth = Permission("edit_my_two_factor_auth")
+	// ManageTwoFactorPolicy 二要素認証ポリシー管理権限
+	ManageTwoFactorPolicy = Permission("manage_two_factor_policy")
+	// ManageUserRole ユーザーロール管理権限
+	ManageUserRole = Permission("manage_user_role")
 	// GetUnread 未読メッセージ一覧の取得権限
 	GetUnread = Permission("get_unread")
 	// DeleteUnread メッセージ既読化権限
@@ -30,6 +30,7 @@ var botPerms = []permission.Permission{
 	permission.EditUserGroup,
 	permission.DeleteUserGroup,
 	permission.GetStamp,
+	permission.GetStampPalette,
 	permission.AddMessageStamp,
 	permission.RemoveMessageStamp,
 	permission.DownloadFile,
@@ -0,0 +1,46 @@
+package role
+
+import (
+	"github.com/traPtitech/traQ/service/rbac/permission"
+)
+
+// ChannelModerator チャンネルモデレーターロール
+//
+// 割り当てられたチャンネルとその子孫チャンネルで、他人のメッセージの削除、ピン留め、トピックの編集、購読者の管理、自動アーカイブの除外設定ができます。
+const ChannelModerator = "moderator"
+
+var channelRoles = map[string]permission.Permissions{
+	ChannelModerator: permission.PermissionsFromArray([]permission.Permission{
+		permission.DeleteMessage,
+		permission.CreateMessagePin,
+		permission.DeleteMessagePin,
+		permission.EditChannelTopic,
+		permission.EditChannelSubscription,
+		permission.ManageChannelArchival,
+	}),
+}
+
+// IsValidChannelRole 有効なチャンネルロールかどうか
+func IsValidChannelRole(role string) bool {
+	_, ok := channelRoles[role]
+	return ok
+}
+
+// ChannelRoles 全てのチャンネルロールの名前を返します
+func ChannelRoles() []string {
+	res := make([]string, 0, len(channelRoles))
+	for name := range channelRoles {
+		res = append(res, name)
+	}
+	return res
+}
+
+// IsChannelRoleGranted 指定したチャンネルロールのいずれかに権限が付与されているかどうか
+func IsChannelRoleGranted(roles []string, p permission.Permission) bool {
+	for _, r := range roles {
+		if perms, ok := channelRoles[r]; ok && perms.Contains(p) {
+			return true
+		}
+	}
+	return false
+}
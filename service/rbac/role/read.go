@@ -25,4 +25,5 @@ var readPerms = []permission.Permission{
 	permission.GetBot,
 	permission.GetClipFolder,
 	permission.GetStampPalette,
+	permission.GetChannelTemplate,
 }
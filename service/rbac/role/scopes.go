@@ -0,0 +1,81 @@
+package role
+
+import (
+	"github.com/traPtitech/traQ/service/rbac/permission"
+)
+
+// OAuth2のきめ細かいスコープ用ロール
+const (
+	// ChannelsRead チャンネル読み取りスコープ
+	ChannelsRead = "channels:read"
+	// ChannelsWrite チャンネル書き込みスコープ
+	ChannelsWrite = "channels:write"
+	// MessagesRead メッセージ読み取りスコープ
+	MessagesRead = "messages:read"
+	// MessagesWrite メッセージ書き込みスコープ
+	MessagesWrite = "messages:write"
+	// StampsRead スタンプ読み取りスコープ
+	StampsRead = "stamps:read"
+	// StampsWrite スタンプ書き込みスコープ
+	StampsWrite = "stamps:write"
+	// FilesRead ファイル読み取りスコープ
+	FilesRead = "files:read"
+	// FilesWrite ファイル書き込みスコープ
+	FilesWrite = "files:write"
+	// UsersRead ユーザー読み取りスコープ
+	UsersRead = "users:read"
+)
+
+var scopePerms = map[string][]permission.Permission{
+	ChannelsRead: {
+		permission.GetChannel,
+		permission.GetChannelSubscription,
+		permission.GetChannelStar,
+		permission.GetChannelTemplate,
+	},
+	ChannelsWrite: {
+		permission.CreateChannel,
+		permission.EditChannelTopic,
+		permission.EditPrivateChannelMember,
+		permission.EditChannelSubscription,
+		permission.EditChannelStar,
+	},
+	MessagesRead: {
+		permission.GetMessage,
+		permission.GetUnread,
+	},
+	MessagesWrite: {
+		permission.PostMessage,
+		permission.EditMessage,
+		permission.DeleteMessage,
+		permission.ReportMessage,
+		permission.CreateMessagePin,
+		permission.DeleteMessagePin,
+		permission.DeleteUnread,
+	},
+	StampsRead: {
+		permission.GetStamp,
+		permission.GetMyStampHistory,
+		permission.GetStampPalette,
+	},
+	StampsWrite: {
+		permission.AddMessageStamp,
+		permission.RemoveMessageStamp,
+		permission.CreateStampPalette,
+		permission.EditStampPalette,
+		permission.DeleteStampPalette,
+	},
+	FilesRead: {
+		permission.DownloadFile,
+	},
+	FilesWrite: {
+		permission.UploadFile,
+		permission.DeleteFile,
+	},
+	UsersRead: {
+		permission.GetUser,
+		permission.GetMe,
+		permission.GetUserTag,
+		permission.GetUserGroup,
+	},
+}
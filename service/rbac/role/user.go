@@ -15,8 +15,11 @@ var userPerms = []permission.Permission{
 	permission.DeleteMySessions,
 	permission.GetMyTokens,
 	permission.RevokeMyToken,
+	permission.CreateMyToken,
 	permission.GetMyExternalAccount,
 	permission.EditMyExternalAccount,
+	permission.GetMyTwoFactorAuth,
+	permission.EditMyTwoFactorAuth,
 	permission.GetClients,
 	permission.CreateClient,
 	permission.EditMyClient,
@@ -10,6 +10,7 @@ const Write = "write"
 var writePerms = []permission.Permission{
 	permission.CreateChannel,
 	permission.EditChannelTopic,
+	permission.EditPrivateChannelMember,
 	permission.PostMessage,
 	permission.EditMessage,
 	permission.DeleteMessage,
@@ -0,0 +1,20 @@
+package retention
+
+import (
+	"context"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/traPtitech/traQ/model"
+)
+
+// Service メッセージ保持ポリシーサービス
+type Service interface {
+	// GetEffectivePolicy 指定したチャンネルに適用されるメッセージ保持ポリシーを取得します
+	//
+	// 指定したチャンネルとその祖先チャンネルのうち、最も近いチャンネルに設定されたポリシーを返します。
+	// 適用されるポリシーが存在しない場合、nilを返します。
+	GetEffectivePolicy(channelID uuid.UUID) (*model.ChannelRetentionPolicy, error)
+	// Shutdown メッセージ保持ポリシーサービスをシャットダウンします
+	Shutdown(ctx context.Context) error
+}
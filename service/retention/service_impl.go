@@ -0,0 +1,188 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/lthibault/jitterbug/v2"
+	"go.uber.org/zap"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/service/channel"
+)
+
+const (
+	// deleteBulk 一度に削除するメッセージの数
+	deleteBulk = 500
+	// purgeDelay 論理削除から物理削除までの猶予 (検索インデックスからの削除を待つため)
+	purgeDelay = time.Hour * 24
+)
+
+type serviceImpl struct {
+	repo   repository.Repository
+	cm     channel.Manager
+	logger *zap.Logger
+
+	ticker      *jitterbug.Ticker
+	serviceDone chan struct{}
+	tickerDone  chan struct{}
+}
+
+// NewService メッセージ保持ポリシーサービスを生成します
+func NewService(repo repository.Repository, cm channel.Manager, logger *zap.Logger) Service {
+	s := &serviceImpl{
+		repo:   repo,
+		cm:     cm,
+		logger: logger.Named("retention"),
+
+		serviceDone: make(chan struct{}),
+		tickerDone:  make(chan struct{}),
+	}
+	s.start()
+	return s
+}
+
+func (s *serviceImpl) start() {
+	s.ticker = jitterbug.New(time.Hour*24, &jitterbug.Uniform{
+		Min: time.Hour * 23,
+	})
+	go func() {
+		defer close(s.tickerDone)
+		for {
+			select {
+			case _, ok := <-s.ticker.C:
+				if !ok {
+					return
+				}
+				if err := s.run(time.Now()); err != nil {
+					s.logger.Error("an error occurred while applying retention policies", zap.Error(err))
+				}
+			case <-s.serviceDone:
+				return
+			}
+		}
+	}()
+}
+
+func (s *serviceImpl) Shutdown(ctx context.Context) error {
+	s.ticker.Stop()
+	close(s.serviceDone)
+	select {
+	case <-s.tickerDone:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *serviceImpl) GetEffectivePolicy(channelID uuid.UUID) (*model.ChannelRetentionPolicy, error) {
+	policies, err := s.policies()
+	if err != nil {
+		return nil, err
+	}
+	return resolve(s.cm.PublicChannelTree(), policies, channelID), nil
+}
+
+func (s *serviceImpl) policies() (map[uuid.UUID]*model.ChannelRetentionPolicy, error) {
+	policies, err := s.repo.GetChannelRetentionPolicies()
+	if err != nil {
+		return nil, fmt.Errorf("failed to GetChannelRetentionPolicies: %w", err)
+	}
+	res := make(map[uuid.UUID]*model.ChannelRetentionPolicy, len(policies))
+	for _, p := range policies {
+		res[p.ChannelID] = p
+	}
+	return res, nil
+}
+
+// run 全てのチャンネルにメッセージ保持ポリシーを適用します
+func (s *serviceImpl) run(now time.Time) error {
+	policies, err := s.policies()
+	if err != nil {
+		return err
+	}
+	tree := s.cm.PublicChannelTree()
+	for channelID, p := range targets(tree, policies) {
+		if err := s.apply(channelID, p, now); err != nil {
+			s.logger.Error("failed to apply retention policy", zap.Stringer("channelID", channelID), zap.Stringer("policyChannelID", p.ChannelID), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// apply 指定したチャンネルにメッセージ保持ポリシーを適用します
+func (s *serviceImpl) apply(channelID uuid.UUID, p *model.ChannelRetentionPolicy, now time.Time) error {
+	before := now.Add(-p.RetentionPeriod())
+
+	// 削除したメッセージのキャッシュは、MessageDeletedイベントによりmessage.Managerから破棄される
+	deleted := 0
+	for {
+		n, err := s.repo.DeleteChannelMessagesBefore(channelID, before, p.KeepPinned, deleteBulk)
+		if err != nil {
+			return fmt.Errorf("failed to DeleteChannelMessagesBefore: %w", err)
+		}
+		deleted += n
+		if n < deleteBulk {
+			break
+		}
+	}
+
+	purged := 0
+	if p.Purge {
+		for {
+			n, err := s.repo.PurgeDeletedChannelMessages(channelID, before, now.Add(-purgeDelay), deleteBulk)
+			if err != nil {
+				return fmt.Errorf("failed to PurgeDeletedChannelMessages: %w", err)
+			}
+			purged += n
+			if n < deleteBulk {
+				break
+			}
+		}
+	}
+
+	if deleted > 0 || purged > 0 {
+		s.logger.Info("applied retention policy", zap.Stringer("channelID", channelID), zap.Int("deleted", deleted), zap.Int("purged", purged))
+	}
+	return nil
+}
+
+// ancestryTree 祖先・子孫チャンネルを取得できるチャンネル木
+type ancestryTree interface {
+	GetAscendantIDs(id uuid.UUID) []uuid.UUID
+	GetDescendantIDs(id uuid.UUID) []uuid.UUID
+}
+
+// resolve 指定したチャンネルに適用されるメッセージ保持ポリシーを返します
+func resolve(tree ancestryTree, policies map[uuid.UUID]*model.ChannelRetentionPolicy, channelID uuid.UUID) *model.ChannelRetentionPolicy {
+	if p, ok := policies[channelID]; ok {
+		return p
+	}
+	// GetAscendantIDsは近い祖先から順に返す
+	for _, id := range tree.GetAscendantIDs(channelID) {
+		if p, ok := policies[id]; ok {
+			return p
+		}
+	}
+	return nil
+}
+
+// targets メッセージを削除する必要があるチャンネルと、そのチャンネルに適用されるポリシーを返します
+func targets(tree ancestryTree, policies map[uuid.UUID]*model.ChannelRetentionPolicy) map[uuid.UUID]*model.ChannelRetentionPolicy {
+	res := make(map[uuid.UUID]*model.ChannelRetentionPolicy)
+	for channelID := range policies {
+		ids := append([]uuid.UUID{channelID}, tree.GetDescendantIDs(channelID)...)
+		for _, id := range ids {
+			if _, ok := res[id]; ok {
+				continue
+			}
+			if p := resolve(tree, policies, id); p != nil && !p.IsPreserved() {
+				res[id] = p
+			}
+		}
+	}
+	return res
+}
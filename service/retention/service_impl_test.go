@@ -0,0 +1,79 @@
+package retention
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/traPtitech/traQ/model"
+)
+
+// fakeTree 親チャンネルのマップによるチャンネル木
+type fakeTree map[uuid.UUID]uuid.UUID
+
+func (t fakeTree) GetAscendantIDs(id uuid.UUID) []uuid.UUID {
+	res := make([]uuid.UUID, 0)
+	for p, ok := t[id]; ok && p != uuid.Nil; p, ok = t[p] {
+		res = append(res, p)
+	}
+	return res
+}
+
+func (t fakeTree) GetDescendantIDs(id uuid.UUID) []uuid.UUID {
+	res := make([]uuid.UUID, 0)
+	for c, p := range t {
+		if p == id {
+			res = append(res, c)
+			res = append(res, t.GetDescendantIDs(c)...)
+		}
+	}
+	return res
+}
+
+func TestResolveAndTargets(t *testing.T) {
+	t.Parallel()
+
+	var (
+		root       = uuid.Must(uuid.NewV4())
+		child      = uuid.Must(uuid.NewV4())
+		grandchild = uuid.Must(uuid.NewV4())
+		preserved  = uuid.Must(uuid.NewV4())
+		preservedC = uuid.Must(uuid.NewV4())
+		other      = uuid.Must(uuid.NewV4())
+		tree       = fakeTree{
+			root:       uuid.Nil,
+			child:      root,
+			grandchild: child,
+			preserved:  root,
+			preservedC: preserved,
+			other:      uuid.Nil,
+		}
+		rootPolicy      = &model.ChannelRetentionPolicy{ChannelID: root, RetentionDays: 30}
+		childPolicy     = &model.ChannelRetentionPolicy{ChannelID: child, RetentionDays: 7, KeepPinned: true}
+		preservedPolicy = &model.ChannelRetentionPolicy{ChannelID: preserved, RetentionDays: 0}
+		policies        = map[uuid.UUID]*model.ChannelRetentionPolicy{
+			root:      rootPolicy,
+			child:     childPolicy,
+			preserved: preservedPolicy,
+		}
+	)
+
+	t.Run("resolve", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, rootPolicy, resolve(tree, policies, root))
+		assert.Equal(t, childPolicy, resolve(tree, policies, child))
+		assert.Equal(t, childPolicy, resolve(tree, policies, grandchild))
+		assert.Equal(t, preservedPolicy, resolve(tree, policies, preservedC))
+		assert.Nil(t, resolve(tree, policies, other))
+	})
+
+	t.Run("targets", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, map[uuid.UUID]*model.ChannelRetentionPolicy{
+			root:       rootPolicy,
+			child:      childPolicy,
+			grandchild: childPolicy,
+		}, targets(tree, policies))
+	})
+}
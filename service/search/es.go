@@ -6,9 +6,11 @@ import (
 	"time"
 
 	"github.com/gofrs/uuid"
+	"github.com/leandro-lugaresi/hub"
 	"github.com/olivere/elastic/v7"
 	"go.uber.org/zap"
 
+	"github.com/traPtitech/traQ/event"
 	"github.com/traPtitech/traQ/repository"
 	"github.com/traPtitech/traQ/service/channel"
 	"github.com/traPtitech/traQ/service/message"
@@ -37,6 +39,8 @@ type esEngine struct {
 	mm     message.Manager
 	cm     channel.Manager
 	repo   repository.Repository
+	hub    *hub.Hub
+	sub    hub.Subscription
 	l      *zap.Logger
 	done   chan<- struct{}
 }
@@ -159,7 +163,7 @@ var esSetting = m{
 }
 
 // NewESEngine Elasticsearch検索エンジンを生成します
-func NewESEngine(mm message.Manager, cm channel.Manager, repo repository.Repository, logger *zap.Logger, config ESEngineConfig) (Engine, error) {
+func NewESEngine(mm message.Manager, cm channel.Manager, repo repository.Repository, hub *hub.Hub, logger *zap.Logger, config ESEngineConfig) (Engine, error) {
 	// es接続
 	client, err := elastic.NewClient(elastic.SetURL(config.URL), elastic.SetSniff(false))
 	if err != nil {
@@ -198,6 +202,8 @@ func NewESEngine(mm message.Manager, cm channel.Manager, repo repository.Reposit
 		mm:     mm,
 		cm:     cm,
 		repo:   repo,
+		hub:    hub,
+		sub:    hub.Subscribe(10, event.ChannelMerged),
 		l:      logger.Named("search"),
 		done:   done,
 	}
@@ -306,5 +312,6 @@ func (e *esEngine) Available() bool {
 func (e *esEngine) Close() error {
 	e.client.Stop()
 	e.done <- struct{}{}
+	e.hub.Unsubscribe(e.sub)
 	return nil
 }
@@ -121,12 +121,40 @@ loop:
 
 		select {
 		case <-t.C:
+		case ev := <-e.sub.Receiver:
+			// 同期と並行して更新しないよう、同じgoroutineで処理する
+			if err := e.mergeChannel(ev.Fields["channel_id"].(uuid.UUID), ev.Fields["target_id"].(uuid.UUID)); err != nil {
+				e.l.Error(err.Error(), zap.Error(err))
+			}
 		case <-done:
 			break loop
 		}
 	}
 }
 
+// mergeChannel 統合元チャンネルのメッセージを統合先チャンネルのメッセージとしてesのインデックスを更新します
+//
+// チャンネルの統合ではメッセージのupdated_atが変わらないため、sync では反映されません。
+func (e *esEngine) mergeChannel(srcID, dstID uuid.UUID) error {
+	// 直前にindexしたメッセージも対象にするため、先にrefreshする
+	if _, err := e.client.Refresh(getIndexName(esMessageIndex)).Do(context.Background()); err != nil {
+		return err
+	}
+	res, err := e.client.UpdateByQuery(getIndexName(esMessageIndex)).
+		Query(elastic.NewTermQuery("channelId", srcID)).
+		Script(elastic.NewScript("ctx._source.channelId = params.channelId; ctx._source.isPublic = params.isPublic").
+			Param("channelId", dstID).
+			Param("isPublic", e.cm.IsPublicChannel(dstID))).
+		ProceedOnVersionConflict().
+		Do(context.Background())
+	if err != nil {
+		return err
+	}
+
+	e.l.Info(fmt.Sprintf("updated %v message(s) on index for channel merge", res.Updated), zap.Stringer("cid", srcID), zap.Stringer("targetCid", dstID))
+	return nil
+}
+
 func (e *esEngine) newUserCache() (userCache, error) {
 	users, err := e.repo.GetUsers(repository.UsersQuery{})
 	if err != nil {
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"github.com/traPtitech/traQ/service/autoarchive"
 	"github.com/traPtitech/traQ/service/bot"
 	botWS "github.com/traPtitech/traQ/service/bot/ws"
 	"github.com/traPtitech/traQ/service/channel"
@@ -12,14 +13,18 @@ import (
 	"github.com/traPtitech/traQ/service/message"
 	"github.com/traPtitech/traQ/service/notification"
 	"github.com/traPtitech/traQ/service/ogp"
+	"github.com/traPtitech/traQ/service/ratelimit"
 	"github.com/traPtitech/traQ/service/rbac"
+	"github.com/traPtitech/traQ/service/retention"
 	"github.com/traPtitech/traQ/service/search"
 	"github.com/traPtitech/traQ/service/viewer"
+	"github.com/traPtitech/traQ/service/webhook"
 	"github.com/traPtitech/traQ/service/webrtcv3"
 	"github.com/traPtitech/traQ/service/ws"
 )
 
 type Services struct {
+	AutoArchive          autoarchive.Service
 	BOT                  bot.Service
 	ChannelManager       channel.Manager
 	OnlineCounter        *counter.OnlineCounter
@@ -33,9 +38,12 @@ type Services struct {
 	MessageManager       message.Manager
 	Notification         *notification.Service
 	OGP                  ogp.Service
+	RateLimitStore       ratelimit.Store
 	RBAC                 rbac.RBAC
+	Retention            retention.Service
 	Search               search.Engine
 	ViewerManager        *viewer.Manager
+	Webhook              webhook.Service
 	WebRTCv3             *webrtcv3.Manager
 	WS                   *ws.Streamer
 	BotWS                *botWS.Streamer
@@ -8,6 +8,7 @@ import (
 )
 
 var ProviderSet = wire.NewSet(wire.FieldsOf(new(*Services),
+	"AutoArchive",
 	"BOT",
 	"ChannelManager",
 	"OnlineCounter",
@@ -21,9 +22,12 @@ var ProviderSet = wire.NewSet(wire.FieldsOf(new(*Services),
 	"MessageManager",
 	"Notification",
 	"OGP",
+	"RateLimitStore",
 	"RBAC",
+	"Retention",
 	"Search",
 	"ViewerManager",
+	"Webhook",
 	"WebRTCv3",
 	"WS",
 	"BotWS",
@@ -0,0 +1,168 @@
+package adapter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/traPtitech/traQ/model"
+)
+
+var (
+	// ErrMissingSignature 署名がありません
+	ErrMissingSignature = errors.New("missing signature")
+	// ErrInvalidSignature 署名が正しくありません
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrMalformedPayload ペイロードが不正です
+	ErrMalformedPayload = errors.New("malformed payload")
+)
+
+// Adapter 外部サービスのWebhookをメッセージに変換するアダプタ
+type Adapter interface {
+	// Name アダプタ名を返します
+	Name() string
+	// Match リクエストがこのアダプタで処理するべきものかどうかを返します
+	Match(header http.Header) bool
+	// Verify リクエストの署名を検証します
+	//
+	// secretが空の場合は呼び出されません。
+	Verify(header http.Header, body []byte, secret string) error
+	// Event リクエストのイベント名を返します
+	Event(header http.Header, payload map[string]interface{}) string
+	// DefaultTemplates イベント名とデフォルトのテンプレートのマップを返します
+	DefaultTemplates() map[string]string
+}
+
+var (
+	adapters = []Adapter{
+		&gitHub{},
+		&gitLab{},
+		&alertmanager{},
+	}
+	templateKeyRegex = regexp.MustCompile(`^([a-z]+)\.([a-z_]+)$`)
+)
+
+// Get 指定した名前のアダプタを返します
+func Get(name string) (Adapter, bool) {
+	for _, a := range adapters {
+		if a.Name() == name {
+			return a, true
+		}
+	}
+	return nil, false
+}
+
+// Detect リクエストヘッダーから対応するアダプタを返します
+func Detect(header http.Header) (Adapter, bool) {
+	for _, a := range adapters {
+		if a.Match(header) {
+			return a, true
+		}
+	}
+	return nil, false
+}
+
+// TemplateKey テンプレートキー `{アダプタ名}.{イベント名}` を返します
+func TemplateKey(a Adapter, event string) string {
+	return a.Name() + "." + event
+}
+
+// ValidateTemplateKey テンプレートキーが有効かどうかを検証します
+func ValidateTemplateKey(key string) error {
+	m := templateKeyRegex.FindStringSubmatch(key)
+	if m == nil {
+		return fmt.Errorf("invalid template key: %s", key)
+	}
+	if _, ok := Get(m[1]); !ok {
+		return fmt.Errorf("unknown adapter: %s", m[1])
+	}
+	return nil
+}
+
+// DefaultTemplate 指定したキーのデフォルトテンプレートを返します
+func DefaultTemplate(key string) (string, bool) {
+	m := templateKeyRegex.FindStringSubmatch(key)
+	if m == nil {
+		return "", false
+	}
+	a, ok := Get(m[1])
+	if !ok {
+		return "", false
+	}
+	t, ok := a.DefaultTemplates()[m[2]]
+	return t, ok
+}
+
+// Render リクエストをメッセージ本文に変換します
+//
+// templatesにイベントに対応するテンプレートがあればそれを、なければデフォルトのテンプレートを使用します。
+// 対応するテンプレートが無いイベントの場合や、テンプレートの出力が空の場合は空文字列を返します。
+// ペイロードが不正な場合はErrMalformedPayloadを返します。
+func Render(a Adapter, header http.Header, body []byte, templates model.WebhookTemplates) (string, error) {
+	payload, err := decodePayload(body)
+	if err != nil {
+		return "", err
+	}
+
+	event := a.Event(header, payload)
+	if len(event) == 0 {
+		return "", nil
+	}
+	key := TemplateKey(a, event)
+	text, ok := templates.Get(key)
+	if !ok {
+		text, ok = a.DefaultTemplates()[event]
+		if !ok {
+			return "", nil
+		}
+	}
+
+	t, err := NewTemplate(key, text)
+	if err != nil {
+		return "", err
+	}
+	return Execute(t, payload)
+}
+
+// RenderPayload 任意のJSONペイロードをテンプレートでメッセージ本文に変換します
+//
+// テンプレートの出力が空の場合は空文字列を返します。
+// ペイロードが不正な場合はErrMalformedPayloadを返します。
+func RenderPayload(text string, body []byte) (string, error) {
+	var payload interface{}
+	dec := jsoniter.ConfigFastest.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&payload); err != nil {
+		return "", ErrMalformedPayload
+	}
+
+	t, err := NewTemplate("payload", text)
+	if err != nil {
+		return "", err
+	}
+	return Execute(t, payload)
+}
+
+func decodePayload(body []byte) (map[string]interface{}, error) {
+	var payload map[string]interface{}
+	dec := jsoniter.ConfigFastest.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&payload); err != nil || payload == nil {
+		return nil, ErrMalformedPayload
+	}
+	return payload, nil
+}
+
+func getString(payload map[string]interface{}, key string) string {
+	s, _ := payload[key].(string)
+	return s
+}
+
+func trimTemplate(s string) string {
+	return strings.TrimSpace(s) + "\n"
+}
@@ -0,0 +1,291 @@
+package adapter
+
+import (
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/utils/hmac"
+)
+
+func header(kv ...string) http.Header {
+	h := http.Header{}
+	for i := 0; i < len(kv); i += 2 {
+		h.Set(kv[i], kv[i+1])
+	}
+	return h
+}
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		header http.Header
+		name   string
+	}{
+		{header("X-GitHub-Event", "push"), "github"},
+		{header("X-Gitlab-Event", "Push Hook"), "gitlab"},
+		{header("User-Agent", "Alertmanager/0.21.0"), "alertmanager"},
+	}
+	for _, c := range cases {
+		a, ok := Detect(c.header)
+		if assert.True(t, ok, c.name) {
+			assert.Equal(t, c.name, a.Name())
+		}
+	}
+
+	_, ok := Detect(header("User-Agent", "curl/7.68.0"))
+	assert.False(t, ok)
+}
+
+func TestValidateTemplateKey(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, ValidateTemplateKey("github.push"))
+	assert.NoError(t, ValidateTemplateKey("gitlab.merge_request"))
+	assert.NoError(t, ValidateTemplateKey("github.star"))
+	assert.Error(t, ValidateTemplateKey("github"))
+	assert.Error(t, ValidateTemplateKey("unknown.push"))
+	assert.Error(t, ValidateTemplateKey("github.Push"))
+}
+
+func TestGitHub_Verify(t *testing.T) {
+	t.Parallel()
+
+	a, _ := Get("github")
+	body := []byte(`{"zen":"test"}`)
+	secret := "secret"
+
+	assert.NoError(t, a.Verify(header("X-Hub-Signature-256", "sha256="+hex.EncodeToString(hmac.SHA256(body, secret))), body, secret))
+	assert.NoError(t, a.Verify(header("X-Hub-Signature", "sha1="+hex.EncodeToString(hmac.SHA1(body, secret))), body, secret))
+	assert.Equal(t, ErrInvalidSignature, a.Verify(header("X-Hub-Signature-256", "sha256="+hex.EncodeToString(hmac.SHA256(body, "wrong"))), body, secret))
+	assert.Equal(t, ErrInvalidSignature, a.Verify(header("X-Hub-Signature-256", "sha256=zz"), body, secret))
+	assert.Equal(t, ErrMissingSignature, a.Verify(header(), body, secret))
+}
+
+func TestGitLab_Verify(t *testing.T) {
+	t.Parallel()
+
+	a, _ := Get("gitlab")
+	assert.NoError(t, a.Verify(header("X-Gitlab-Token", "secret"), nil, "secret"))
+	assert.Equal(t, ErrInvalidSignature, a.Verify(header("X-Gitlab-Token", "wrong"), nil, "secret"))
+	assert.Equal(t, ErrMissingSignature, a.Verify(header(), nil, "secret"))
+}
+
+func TestAlertmanager_Verify(t *testing.T) {
+	t.Parallel()
+
+	a, _ := Get("alertmanager")
+	assert.NoError(t, a.Verify(header("Authorization", "Bearer secret"), nil, "secret"))
+	assert.NoError(t, a.Verify(header("Authorization", "Basic dXNlcjpzZWNyZXQ="), nil, "secret")) // user:secret
+	assert.Equal(t, ErrInvalidSignature, a.Verify(header("Authorization", "Bearer wrong"), nil, "secret"))
+	assert.Equal(t, ErrMissingSignature, a.Verify(header(), nil, "secret"))
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	github, _ := Get("github")
+	gitlab, _ := Get("gitlab")
+	alertmanager, _ := Get("alertmanager")
+
+	t.Run("github push", func(t *testing.T) {
+		t.Parallel()
+		body := []byte(`{
+			"ref": "refs/heads/master",
+			"repository": {"full_name": "traPtitech/traQ", "html_url": "https://github.com/traPtitech/traQ"},
+			"pusher": {"name": "takashi"},
+			"commits": [{"id": "0123456789abcdef", "url": "https://example.com/c", "message": "fix bug\n\ndetail", "author": {"name": "takashi"}}]
+		}`)
+		text, err := Render(github, header("X-GitHub-Event", "push"), body, nil)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "### [traPtitech/traQ](https://github.com/traPtitech/traQ) 1 commit(s) pushed to `master` by takashi\n\n- [`0123456`](https://example.com/c) fix bug - takashi", text)
+		}
+	})
+
+	t.Run("github pull_request merged", func(t *testing.T) {
+		t.Parallel()
+		body := []byte(`{
+			"action": "closed",
+			"number": 12,
+			"repository": {"full_name": "traPtitech/traQ", "html_url": "https://github.com/traPtitech/traQ"},
+			"pull_request": {"title": "feature", "html_url": "https://example.com/pr", "merged": true},
+			"sender": {"login": "takashi"}
+		}`)
+		text, err := Render(github, header("X-GitHub-Event", "pull_request"), body, nil)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "### [traPtitech/traQ](https://github.com/traPtitech/traQ) Pull Request merged: [#12 feature](https://example.com/pr)\nby takashi", text)
+		}
+	})
+
+	t.Run("github ignored action", func(t *testing.T) {
+		t.Parallel()
+		text, err := Render(github, header("X-GitHub-Event", "pull_request"), []byte(`{"action": "labeled"}`), nil)
+		if assert.NoError(t, err) {
+			assert.Empty(t, text)
+		}
+	})
+
+	t.Run("github ping", func(t *testing.T) {
+		t.Parallel()
+		text, err := Render(github, header("X-GitHub-Event", "ping"), []byte(`{"zen": "test"}`), nil)
+		if assert.NoError(t, err) {
+			assert.Empty(t, text)
+		}
+	})
+
+	t.Run("custom template", func(t *testing.T) {
+		t.Parallel()
+		tmpls := model.WebhookTemplates{"github.ping": "pong: {{ .zen }}"}
+		text, err := Render(github, header("X-GitHub-Event", "ping"), []byte(`{"zen": "test"}`), tmpls)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "pong: test", text)
+		}
+	})
+
+	t.Run("gitlab pipeline", func(t *testing.T) {
+		t.Parallel()
+		body := []byte(`{
+			"object_kind": "pipeline",
+			"object_attributes": {"id": 31, "ref": "master", "status": "failed"},
+			"project": {"path_with_namespace": "trap/traq", "web_url": "https://gitlab.example.com/trap/traq"}
+		}`)
+		text, err := Render(gitlab, header("X-Gitlab-Event", "Pipeline Hook"), body, nil)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "### [trap/traq](https://gitlab.example.com/trap/traq) Pipeline [#31](https://gitlab.example.com/trap/traq/-/pipelines/31) failed on `master`", text)
+		}
+	})
+
+	t.Run("alertmanager", func(t *testing.T) {
+		t.Parallel()
+		body := []byte(`{
+			"status": "firing",
+			"commonLabels": {"alertname": "HighLoad"},
+			"alerts": [{"status": "firing", "labels": {"alertname": "HighLoad", "severity": "critical"}, "annotations": {"summary": "load is high"}}]
+		}`)
+		text, err := Render(alertmanager, header("User-Agent", "Alertmanager/0.21.0"), body, nil)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "### [FIRING:1] HighLoad\n\n- **HighLoad** (critical): load is high", text)
+		}
+	})
+
+	t.Run("malformed payload", func(t *testing.T) {
+		t.Parallel()
+		_, err := Render(github, header("X-GitHub-Event", "push"), []byte(`not json`), nil)
+		assert.Equal(t, ErrMalformedPayload, err)
+	})
+}
+
+func TestRenderPayload(t *testing.T) {
+	t.Parallel()
+
+	t.Run("object", func(t *testing.T) {
+		t.Parallel()
+		text, err := RenderPayload(`{{ .title }}: {{ .count }}{{ range .tags }} #{{ . }}{{ end }}`, []byte(`{"title": "test", "count": 12345678901234567890, "tags": ["a", "b"]}`))
+		if assert.NoError(t, err) {
+			assert.Equal(t, "test: 12345678901234567890 #a #b", text)
+		}
+	})
+
+	t.Run("array", func(t *testing.T) {
+		t.Parallel()
+		text, err := RenderPayload(`{{ count . }}`, []byte(`[1, 2, 3]`))
+		if assert.NoError(t, err) {
+			assert.Equal(t, "3", text)
+		}
+	})
+
+	t.Run("malformed payload", func(t *testing.T) {
+		t.Parallel()
+		_, err := RenderPayload(`{{ . }}`, []byte(`{`))
+		assert.Equal(t, ErrMalformedPayload, err)
+	})
+
+	t.Run("bad template", func(t *testing.T) {
+		t.Parallel()
+		_, err := RenderPayload(`{{ .title `, []byte(`{}`))
+		assert.Error(t, err)
+	})
+}
+
+func TestExecute(t *testing.T) {
+	t.Parallel()
+
+	t.Run("helpers", func(t *testing.T) {
+		t.Parallel()
+		tmpl, err := NewTemplate("test", `{{ truncate 5 .a }} {{ default "x" .b }} {{ join "," .c }} {{ upper .d }} {{ count .c }} {{ count .e }}`)
+		if assert.NoError(t, err) {
+			text, err := Execute(tmpl, map[string]interface{}{
+				"a": "abcdefgh",
+				"c": []interface{}{"1", "2"},
+				"d": "abc",
+			})
+			if assert.NoError(t, err) {
+				assert.Equal(t, "abcd… x 1,2 ABC 2 0", text)
+			}
+		}
+	})
+
+	t.Run("too large output", func(t *testing.T) {
+		t.Parallel()
+		tmpl, err := NewTemplate("test", `{{ range .a }}{{ $.s }}{{ end }}`)
+		if assert.NoError(t, err) {
+			a := make([]int, 100)
+			_, err := Execute(tmpl, map[string]interface{}{"a": a, "s": strings.Repeat("a", 1000)})
+			assert.Equal(t, ErrOutputTooLarge, err)
+		}
+	})
+
+	t.Run("range with else", func(t *testing.T) {
+		t.Parallel()
+		tmpl, err := NewTemplate("test", `{{ range $i, $v := .a }}{{ $i }}{{ $v }}{{ else }}empty{{ end }}{{ range .b }}x{{ else }}none{{ end }}`)
+		if assert.NoError(t, err) {
+			text, err := Execute(tmpl, map[string]interface{}{"a": []interface{}{"a", "b"}})
+			if assert.NoError(t, err) {
+				assert.Equal(t, "0a1bnone", text)
+			}
+		}
+	})
+}
+
+func TestExecute_HostileTemplate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("integer range", func(t *testing.T) {
+		t.Parallel()
+		_, err := NewTemplate("test", `{{ range 2000000000 }}x{{ end }}`)
+		assert.Error(t, err)
+		_, err = NewTemplate("test", `{{ if true }}{{ range $i := 2000000000 }}{{ end }}{{ end }}`)
+		assert.Error(t, err)
+	})
+
+	t.Run("integer range from payload", func(t *testing.T) {
+		t.Parallel()
+		start := time.Now()
+		_, err := RenderPayload(`{{ range .n }}{{ end }}`, []byte(`{"n":2000000000}`))
+		assert.Error(t, err)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("recursive template", func(t *testing.T) {
+		t.Parallel()
+		_, err := NewTemplate("test", `{{ define "a" }}{{ template "a" }}{{ template "a" }}{{ end }}{{ template "a" }}`)
+		assert.Error(t, err)
+		_, err = NewTemplate("test", `{{ block "a" . }}x{{ end }}`)
+		assert.Error(t, err)
+	})
+
+	t.Run("nested range without output", func(t *testing.T) {
+		t.Parallel()
+		start := time.Now()
+		a := "[" + strings.TrimSuffix(strings.Repeat("0,", 1000), ",") + "]"
+		_, err := RenderPayload(`{{ range .a }}{{ range $.a }}{{ range $.a }}{{ end }}{{ end }}{{ end }}`, []byte(`{"a":`+a+`}`))
+		assert.Equal(t, ErrTooManyIterations, err)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
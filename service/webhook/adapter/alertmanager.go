@@ -0,0 +1,61 @@
+package adapter
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const alertmanagerEvent = "alert"
+
+var alertmanagerTemplates = map[string]string{
+	alertmanagerEvent: trimTemplate(`
+### {{ if eq .status "firing" }}[FIRING:{{ count .alerts }}]{{ else }}[RESOLVED]{{ end }} {{ default "Alert" .commonLabels.alertname }}
+{{ range .alerts }}
+- **{{ .labels.alertname }}**{{ with .labels.severity }} ({{ . }}){{ end }}{{ with .annotations.summary }}: {{ . }}{{ end }}{{ with .generatorURL }} [source]({{ . }}){{ end }}
+{{- end }}
+`),
+}
+
+// alertmanager Prometheus Alertmanager Webhookアダプタ
+//
+// Alertmanagerは署名を行わないため、http_configのBearerトークン、
+// またはBasic認証のパスワードにWebhookシークレットを設定してください。
+// https://prometheus.io/docs/alerting/latest/configuration/#webhook_config
+type alertmanager struct{}
+
+func (*alertmanager) Name() string {
+	return "alertmanager"
+}
+
+func (*alertmanager) Match(header http.Header) bool {
+	return strings.HasPrefix(header.Get("User-Agent"), "Alertmanager/")
+}
+
+func (*alertmanager) Verify(header http.Header, _ []byte, secret string) error {
+	var token string
+	auth := header.Get(echo.HeaderAuthorization)
+	switch {
+	case strings.HasPrefix(auth, "Bearer "):
+		token = strings.TrimPrefix(auth, "Bearer ")
+	case strings.HasPrefix(auth, "Basic "):
+		r := http.Request{Header: header}
+		_, token, _ = r.BasicAuth()
+	default:
+		return ErrMissingSignature
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (*alertmanager) Event(_ http.Header, _ map[string]interface{}) string {
+	return alertmanagerEvent
+}
+
+func (*alertmanager) DefaultTemplates() map[string]string {
+	return alertmanagerTemplates
+}
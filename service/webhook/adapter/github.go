@@ -0,0 +1,100 @@
+package adapter
+
+import (
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/traPtitech/traQ/utils/hmac"
+)
+
+const (
+	headerGitHubEvent        = "X-GitHub-Event"
+	headerGitHubSignature256 = "X-Hub-Signature-256"
+	headerGitHubSignature    = "X-Hub-Signature"
+)
+
+var gitHubTemplates = map[string]string{
+	"push": trimTemplate(`
+{{- if count .commits -}}
+### [{{ .repository.full_name }}]({{ .repository.html_url }}) {{ count .commits }} commit(s) pushed to ` + "`{{ trimPrefix \"refs/heads/\" .ref }}`" + ` by {{ .pusher.name }}
+{{ range .commits }}
+- [` + "`{{ shortSHA .id }}`" + `]({{ .url }}) {{ firstLine .message }} - {{ .author.name }}
+{{- end }}
+{{- end }}
+`),
+	"pull_request": trimTemplate(`
+{{- if or (eq .action "opened") (eq .action "closed") (eq .action "reopened") -}}
+### [{{ .repository.full_name }}]({{ .repository.html_url }}) Pull Request {{ if .pull_request.merged }}merged{{ else }}{{ .action }}{{ end }}: [#{{ .number }} {{ .pull_request.title }}]({{ .pull_request.html_url }})
+by {{ .sender.login }}
+{{- if eq .action "opened" }}{{ with .pull_request.body }}
+
+{{ truncate 1000 . }}
+{{- end }}{{ end }}
+{{- end }}
+`),
+	"issues": trimTemplate(`
+{{- if or (eq .action "opened") (eq .action "closed") (eq .action "reopened") -}}
+### [{{ .repository.full_name }}]({{ .repository.html_url }}) Issue {{ .action }}: [#{{ .issue.number }} {{ .issue.title }}]({{ .issue.html_url }})
+by {{ .sender.login }}
+{{- if eq .action "opened" }}{{ with .issue.body }}
+
+{{ truncate 1000 . }}
+{{- end }}{{ end }}
+{{- end }}
+`),
+	"issue_comment": trimTemplate(`
+{{- if eq .action "created" -}}
+### [{{ .repository.full_name }}]({{ .repository.html_url }}) New comment on [#{{ .issue.number }} {{ .issue.title }}]({{ .comment.html_url }})
+by {{ .comment.user.login }}
+
+{{ truncate 1000 .comment.body }}
+{{- end }}
+`),
+	"release": trimTemplate(`
+{{- if eq .action "published" -}}
+### [{{ .repository.full_name }}]({{ .repository.html_url }}) Release published: [{{ default .release.tag_name .release.name }}]({{ .release.html_url }})
+by {{ .sender.login }}
+{{- end }}
+`),
+}
+
+// gitHub GitHub Webhookアダプタ
+//
+// https://docs.github.com/en/developers/webhooks-and-events/webhooks
+type gitHub struct{}
+
+func (*gitHub) Name() string {
+	return "github"
+}
+
+func (*gitHub) Match(header http.Header) bool {
+	return len(header.Get(headerGitHubEvent)) > 0
+}
+
+func (*gitHub) Verify(header http.Header, body []byte, secret string) error {
+	if sig := header.Get(headerGitHubSignature256); len(sig) > 0 {
+		return verifyHexSignature(strings.TrimPrefix(sig, "sha256="), hmac.SHA256(body, secret))
+	}
+	if sig := header.Get(headerGitHubSignature); len(sig) > 0 {
+		return verifyHexSignature(strings.TrimPrefix(sig, "sha1="), hmac.SHA1(body, secret))
+	}
+	return ErrMissingSignature
+}
+
+func (*gitHub) Event(header http.Header, _ map[string]interface{}) string {
+	return header.Get(headerGitHubEvent)
+}
+
+func (*gitHub) DefaultTemplates() map[string]string {
+	return gitHubTemplates
+}
+
+func verifyHexSignature(sig string, expected []byte) error {
+	b, err := hex.DecodeString(sig)
+	if err != nil || subtle.ConstantTimeCompare(b, expected) != 1 {
+		return ErrInvalidSignature
+	}
+	return nil
+}
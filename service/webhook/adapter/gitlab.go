@@ -0,0 +1,98 @@
+package adapter
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+const (
+	headerGitLabEvent = "X-Gitlab-Event"
+	headerGitLabToken = "X-Gitlab-Token"
+)
+
+var gitLabTemplates = map[string]string{
+	"push": trimTemplate(`
+{{- if count .commits -}}
+### [{{ .project.path_with_namespace }}]({{ .project.web_url }}) {{ .total_commits_count }} commit(s) pushed to ` + "`{{ trimPrefix \"refs/heads/\" .ref }}`" + ` by {{ .user_name }}
+{{ range .commits }}
+- [` + "`{{ shortSHA .id }}`" + `]({{ .url }}) {{ firstLine .message }} - {{ .author.name }}
+{{- end }}
+{{- end }}
+`),
+	"tag_push": trimTemplate(`
+{{- if count .commits -}}
+### [{{ .project.path_with_namespace }}]({{ .project.web_url }}) Tag ` + "`{{ trimPrefix \"refs/tags/\" .ref }}`" + ` pushed by {{ .user_name }}
+{{- end }}
+`),
+	"merge_request": trimTemplate(`
+{{- with .object_attributes -}}
+{{- if or (eq .action "open") (eq .action "close") (eq .action "reopen") (eq .action "merge") -}}
+### [{{ $.project.path_with_namespace }}]({{ $.project.web_url }}) Merge Request {{ .action }}: [!{{ .iid }} {{ .title }}]({{ .url }})
+by {{ $.user.username }}
+{{- if eq .action "open" }}{{ with .description }}
+
+{{ truncate 1000 . }}
+{{- end }}{{ end }}
+{{- end }}
+{{- end }}
+`),
+	"issue": trimTemplate(`
+{{- with .object_attributes -}}
+{{- if or (eq .action "open") (eq .action "close") (eq .action "reopen") -}}
+### [{{ $.project.path_with_namespace }}]({{ $.project.web_url }}) Issue {{ .action }}: [#{{ .iid }} {{ .title }}]({{ .url }})
+by {{ $.user.username }}
+{{- if eq .action "open" }}{{ with .description }}
+
+{{ truncate 1000 . }}
+{{- end }}{{ end }}
+{{- end }}
+{{- end }}
+`),
+	"note": trimTemplate(`
+{{- with .object_attributes -}}
+### [{{ $.project.path_with_namespace }}]({{ $.project.web_url }}) [New comment]({{ .url }}) on {{ .noteable_type }}
+by {{ $.user.username }}
+
+{{ truncate 1000 .note }}
+{{- end }}
+`),
+	"pipeline": trimTemplate(`
+{{- with .object_attributes -}}
+{{- if or (eq .status "success") (eq .status "failed") -}}
+### [{{ $.project.path_with_namespace }}]({{ $.project.web_url }}) Pipeline [#{{ .id }}]({{ $.project.web_url }}/-/pipelines/{{ .id }}) {{ .status }} on ` + "`{{ .ref }}`" + `
+{{- end }}
+{{- end }}
+`),
+}
+
+// gitLab GitLab Webhookアダプタ
+//
+// https://docs.gitlab.com/ee/user/project/integrations/webhooks.html
+type gitLab struct{}
+
+func (*gitLab) Name() string {
+	return "gitlab"
+}
+
+func (*gitLab) Match(header http.Header) bool {
+	return len(header.Get(headerGitLabEvent)) > 0
+}
+
+func (*gitLab) Verify(header http.Header, _ []byte, secret string) error {
+	token := header.Get(headerGitLabToken)
+	if len(token) == 0 {
+		return ErrMissingSignature
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (*gitLab) Event(_ http.Header, payload map[string]interface{}) string {
+	return getString(payload, "object_kind")
+}
+
+func (*gitLab) DefaultTemplates() map[string]string {
+	return gitLabTemplates
+}
@@ -0,0 +1,283 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+	"unicode/utf8"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+const (
+	// MaxTemplateLength テンプレートの最大文字数
+	MaxTemplateLength = 10000
+	// maxOutputLength 生成されるメッセージ本文の最大文字数
+	maxOutputLength = 10000
+	// maxOutputSize テンプレート実行時の最大出力バイト数
+	maxOutputSize = 1 << 16 // 64KB
+	// maxRangeIterations テンプレート実行時のrangeの合計最大繰り返し回数
+	maxRangeIterations = 10000
+	// executeTimeout テンプレート実行のタイムアウト
+	executeTimeout = 1 * time.Second
+
+	// rangeGuardFunc rangeの対象を検査する内部関数の名前
+	rangeGuardFunc = "_rangeGuard"
+)
+
+var (
+	// ErrOutputTooLarge テンプレートの出力が大きすぎます
+	ErrOutputTooLarge = errors.New("template output is too large")
+	// ErrTooManyIterations テンプレートのrangeの繰り返し回数が多すぎます
+	ErrTooManyIterations = errors.New("too many range iterations in template")
+	// ErrExecuteTimeout テンプレートの実行がタイムアウトしました
+	ErrExecuteTimeout = errors.New("template execution timed out")
+
+	errRangeGuardNotBound = errors.New("range guard is not bound")
+)
+
+var funcs = template.FuncMap{
+	"default":    defaultFunc,
+	"truncate":   truncate,
+	"firstLine":  firstLine,
+	"shortSHA":   shortSHA,
+	"trimPrefix": trimPrefix,
+	"join":       join,
+	"count":      count,
+	"upper":      func(v interface{}) string { return strings.ToUpper(toString(v)) },
+	"lower":      func(v interface{}) string { return strings.ToLower(toString(v)) },
+	"trim":       func(v interface{}) string { return strings.TrimSpace(toString(v)) },
+	"json":       toJSON,
+
+	// Execute時に実行ごとの関数に置き換えられます
+	rangeGuardFunc: func(interface{}) (interface{}, error) { return nil, errRangeGuardNotBound },
+}
+
+// NewTemplate ヘルパー関数付きのテンプレートをパースします
+//
+// 実行時間を制限するため、テンプレートの定義・呼び出し(define, template, block)と
+// 整数に対するrangeは使用できません。
+func NewTemplate(name, text string) (*template.Template, error) {
+	if utf8.RuneCountInString(text) > MaxTemplateLength {
+		return nil, fmt.Errorf("template must be shorter than %d characters", MaxTemplateLength+1)
+	}
+	t, err := template.New(name).Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	if len(t.Templates()) > 1 {
+		return nil, errors.New("define and block are not allowed")
+	}
+	if t.Tree == nil {
+		return t, nil
+	}
+	if err := guardRanges(t.Tree.Root); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// guardRanges テンプレートの構文木を検査し、全てのrangeの対象をrangeGuardFuncに通すように書き換えます
+func guardRanges(node parse.Node) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, c := range n.Nodes {
+			if err := guardRanges(c); err != nil {
+				return err
+			}
+		}
+	case *parse.TemplateNode:
+		return errors.New("template action is not allowed")
+	case *parse.IfNode:
+		return guardBranch(&n.BranchNode)
+	case *parse.WithNode:
+		return guardBranch(&n.BranchNode)
+	case *parse.RangeNode:
+		cmds := n.Pipe.Cmds
+		if last := cmds[len(cmds)-1]; len(last.Args) == 1 && last.Args[0].Type() == parse.NodeNumber {
+			return errors.New("range over integer is not allowed")
+		}
+		n.Pipe.Cmds = append(cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      n.Pipe.Pos,
+			Args:     []parse.Node{&parse.IdentifierNode{NodeType: parse.NodeIdentifier, Pos: n.Pipe.Pos, Ident: rangeGuardFunc}},
+		})
+		return guardBranch(&n.BranchNode)
+	}
+	return nil
+}
+
+func guardBranch(n *parse.BranchNode) error {
+	if err := guardRanges(n.List); err != nil {
+		return err
+	}
+	return guardRanges(n.ElseList)
+}
+
+// Execute テンプレートを実行してメッセージ本文を生成します
+//
+// 前後の空白は取り除かれ、最大文字数を超える部分は切り捨てられます。
+// 出力が極端に大きい場合はErrOutputTooLarge、rangeの繰り返し回数が多すぎる場合はErrTooManyIterations、
+// 実行に時間がかかりすぎた場合はErrExecuteTimeoutを返します。
+func Execute(t *template.Template, data interface{}) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), executeTimeout)
+	defer cancel()
+
+	g := &rangeGuard{ctx: ctx, limit: maxRangeIterations}
+	t, err := t.Clone()
+	if err != nil {
+		return "", err
+	}
+	t.Funcs(template.FuncMap{rangeGuardFunc: g.check})
+
+	w := &limitedWriter{ctx: ctx, limit: maxOutputSize}
+	if err := t.Execute(w, data); err != nil {
+		switch {
+		case w.exceeded:
+			return "", ErrOutputTooLarge
+		case errors.Is(err, ErrTooManyIterations):
+			return "", ErrTooManyIterations
+		case ctx.Err() != nil:
+			return "", ErrExecuteTimeout
+		}
+		return "", err
+	}
+	return truncate(maxOutputLength, strings.TrimSpace(w.buf.String())), nil
+}
+
+// rangeGuard 1回のテンプレート実行におけるrangeの繰り返し回数と実行時間を制限します
+type rangeGuard struct {
+	ctx   context.Context
+	limit int
+	count int
+}
+
+func (g *rangeGuard) check(v interface{}) (interface{}, error) {
+	if err := g.ctx.Err(); err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		g.count += rv.Len()
+		if g.count > g.limit {
+			return nil, ErrTooManyIterations
+		}
+		return v, nil
+	default:
+		return nil, fmt.Errorf("range over %s is not allowed", rv.Kind())
+	}
+}
+
+type limitedWriter struct {
+	ctx      context.Context
+	buf      bytes.Buffer
+	limit    int
+	exceeded bool
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+	if w.buf.Len()+len(p) > w.limit {
+		w.exceeded = true
+		return 0, ErrOutputTooLarge
+	}
+	return w.buf.Write(p)
+}
+
+func toString(v interface{}) string {
+	switch s := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return s
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// defaultFunc vが空の場合にdefを返します
+func defaultFunc(def, v interface{}) interface{} {
+	if v == nil || toString(v) == "" {
+		return def
+	}
+	return v
+}
+
+// truncate 文字列をn文字に切り詰めます
+func truncate(n int, v interface{}) string {
+	s := toString(v)
+	if n < 0 || utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	if n == 0 {
+		return ""
+	}
+	return string([]rune(s)[:n-1]) + "…"
+}
+
+// firstLine 文字列の最初の行を返します
+func firstLine(v interface{}) string {
+	s := toString(v)
+	if i := strings.IndexAny(s, "\r\n"); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+// shortSHA コミットハッシュを短縮形にします
+func shortSHA(v interface{}) string {
+	s := toString(v)
+	if len(s) > 7 {
+		return s[:7]
+	}
+	return s
+}
+
+// trimPrefix 文字列から接頭辞を取り除きます
+func trimPrefix(prefix string, v interface{}) string {
+	return strings.TrimPrefix(toString(v), prefix)
+}
+
+// join 配列の要素を連結します
+func join(sep string, v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return toString(v)
+	}
+	arr := make([]string, rv.Len())
+	for i := range arr {
+		arr[i] = toString(rv.Index(i).Interface())
+	}
+	return strings.Join(arr, sep)
+}
+
+// count 配列・マップ・文字列の長さを返します (nilの場合は0)
+func count(v interface{}) int {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.String:
+		return rv.Len()
+	default:
+		return 0
+	}
+}
+
+// toJSON 値をJSON文字列に変換します
+func toJSON(v interface{}) (string, error) {
+	return jsoniter.ConfigFastest.MarshalToString(v)
+}
@@ -0,0 +1,9 @@
+package webhook
+
+import "context"
+
+// Service Outgoing Webhookサービス
+type Service interface {
+	// Shutdown Outgoing Webhookサービスをシャットダウンします
+	Shutdown(ctx context.Context) error
+}
@@ -0,0 +1,304 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofrs/uuid"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/labstack/echo/v4"
+	"github.com/leandro-lugaresi/hub"
+	"github.com/lthibault/jitterbug/v2"
+	"go.uber.org/zap"
+
+	intevent "github.com/traPtitech/traQ/event"
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/service/bot/event"
+	"github.com/traPtitech/traQ/service/message"
+	"github.com/traPtitech/traQ/utils/hmac"
+	mutil "github.com/traPtitech/traQ/utils/message"
+)
+
+const (
+	headerTRAQWebhookRequestID = "X-TRAQ-Webhook-Request-Id"
+	headerTRAQSignature        = "X-TRAQ-Signature"
+	headerUserAgent            = "User-Agent"
+	ua                         = "traQ_Webhook_Processor/1.0"
+
+	deliveryLogPurgeBefore = time.Hour * 24 * 30 // 配送ログを30日間保持
+	maxResponseBodySize    = 1 << 16             // 64KB
+	maxReplyLength         = 10000
+
+	resultOK           = "ok"
+	resultNG           = "ng"
+	resultNetworkError = "ne"
+)
+
+type serviceImpl struct {
+	repo   repository.Repository
+	mm     message.Manager
+	hub    *hub.Hub
+	client *http.Client
+	logger *zap.Logger
+
+	// webhooks チャンネルID -> Outgoing Webhookの配列
+	webhooks map[uuid.UUID][]model.Webhook
+	// botUserIDs 全WebhookのBotユーザーIDのセット
+	botUserIDs map[uuid.UUID]struct{}
+	mu         sync.RWMutex
+
+	sub         hub.Subscription
+	logPurger   *jitterbug.Ticker
+	wg          sync.WaitGroup
+	serviceDone chan struct{}
+	hubDone     chan struct{}
+	purgerDone  chan struct{}
+}
+
+// NewService Outgoing Webhookサービスを生成します
+func NewService(repo repository.Repository, mm message.Manager, hub *hub.Hub, logger *zap.Logger) (Service, error) {
+	s := &serviceImpl{
+		repo:   repo,
+		mm:     mm,
+		hub:    hub,
+		client: event.NewHTTPClient(),
+		logger: logger.Named("webhook"),
+
+		serviceDone: make(chan struct{}),
+		hubDone:     make(chan struct{}),
+		purgerDone:  make(chan struct{}),
+	}
+	if err := s.reload(); err != nil {
+		return nil, fmt.Errorf("failed to load webhooks: %w", err)
+	}
+	s.start()
+	return s, nil
+}
+
+func (s *serviceImpl) start() {
+	s.sub = s.hub.Subscribe(100,
+		intevent.MessageCreated,
+		intevent.WebhookCreated,
+		intevent.WebhookUpdated,
+		intevent.WebhookDeleted,
+	)
+
+	go func() {
+		defer close(s.hubDone)
+		for ev := range s.sub.Receiver {
+			switch ev.Name {
+			case intevent.MessageCreated:
+				s.onMessageCreated(ev.Fields["message"].(*model.Message), ev.Fields["parse_result"].(*mutil.ParseResult))
+			default:
+				if err := s.reload(); err != nil {
+					s.logger.Error("failed to reload webhooks", zap.Error(err))
+				}
+			}
+		}
+		s.wg.Wait()
+	}()
+
+	// 配送ログの定期的消去
+	s.logPurger = jitterbug.New(time.Hour*24, &jitterbug.Uniform{
+		Min: time.Hour * 23,
+	})
+	go func() {
+		defer close(s.purgerDone)
+		for {
+			select {
+			case _, ok := <-s.logPurger.C:
+				if !ok {
+					return
+				}
+				if err := s.repo.PurgeWebhookDeliveryLogs(time.Now().Add(-deliveryLogPurgeBefore)); err != nil {
+					s.logger.Error("an error occurred while puring old webhook delivery logs", zap.Error(err))
+				}
+			case <-s.serviceDone:
+				return
+			}
+		}
+	}()
+}
+
+func (s *serviceImpl) Shutdown(ctx context.Context) error {
+	s.hub.Unsubscribe(s.sub)
+	s.logPurger.Stop()
+	close(s.serviceDone)
+	<-s.hubDone
+	<-s.purgerDone
+	return nil
+}
+
+func (s *serviceImpl) reload() error {
+	ws, err := s.repo.GetAllWebhooks()
+	if err != nil {
+		return err
+	}
+
+	webhooks := map[uuid.UUID][]model.Webhook{}
+	botUserIDs := map[uuid.UUID]struct{}{}
+	for _, w := range ws {
+		botUserIDs[w.GetBotUserID()] = struct{}{}
+		if len(w.GetOutgoingURL()) > 0 {
+			webhooks[w.GetChannelID()] = append(webhooks[w.GetChannelID()], w)
+		}
+	}
+
+	s.mu.Lock()
+	s.webhooks = webhooks
+	s.botUserIDs = botUserIDs
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *serviceImpl) onMessageCreated(m *model.Message, parsed *mutil.ParseResult) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Webhookの投稿には反応しない (ループ防止)
+	if _, ok := s.botUserIDs[m.UserID]; ok {
+		return
+	}
+
+	for _, w := range s.webhooks[m.ChannelID] {
+		word, ok := matchTriggerWord(w.GetTriggerWords(), m.Text)
+		if !ok {
+			continue
+		}
+		w := w
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.deliver(w, m, parsed, word)
+		}()
+	}
+}
+
+// matchTriggerWord 本文がトリガーワードで始まるかどうかを判定します
+//
+// トリガーワードが設定されていない場合は常にtrueを返します。
+func matchTriggerWord(words []string, text string) (string, bool) {
+	if len(words) == 0 {
+		return "", true
+	}
+	text = strings.TrimSpace(text)
+	for _, w := range words {
+		if strings.HasPrefix(text, w) {
+			return w, true
+		}
+	}
+	return "", false
+}
+
+type outgoingPayload struct {
+	EventTime   time.Time              `json:"eventTime"`
+	WebhookID   uuid.UUID              `json:"webhookId"`
+	TriggerWord string                 `json:"triggerWord"`
+	Message     outgoingPayloadMessage `json:"message"`
+}
+
+type outgoingPayloadMessage struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"userId"`
+	ChannelID uuid.UUID `json:"channelId"`
+	Text      string    `json:"text"`
+	PlainText string    `json:"plainText"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (s *serviceImpl) deliver(w model.Webhook, m *model.Message, parsed *mutil.ParseResult, word string) {
+	reqID := uuid.Must(uuid.NewV4())
+	body, err := jsoniter.ConfigFastest.Marshal(&outgoingPayload{
+		EventTime:   time.Now(),
+		WebhookID:   w.GetID(),
+		TriggerWord: word,
+		Message: outgoingPayloadMessage{
+			ID:        m.ID,
+			UserID:    m.UserID,
+			ChannelID: m.ChannelID,
+			Text:      m.Text,
+			PlainText: parsed.PlainText,
+			CreatedAt: m.CreatedAt,
+		},
+	})
+	if err != nil {
+		s.logger.Error("failed to marshal payload", zap.Error(err))
+		return
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, w.GetOutgoingURL(), bytes.NewReader(body))
+	req.Header.Set(headerUserAgent, ua)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+	req.Header.Set(headerTRAQWebhookRequestID, reqID.String())
+	if secret := w.GetSecret(); len(secret) > 0 {
+		req.Header.Set(headerTRAQSignature, hex.EncodeToString(hmac.SHA1(body, secret)))
+	}
+
+	start := time.Now()
+	res, err := s.client.Do(req)
+	latency := time.Since(start)
+
+	log := &model.WebhookDeliveryLog{
+		RequestID: reqID,
+		WebhookID: w.GetID(),
+		MessageID: m.ID,
+		Body:      string(body),
+		Latency:   latency.Nanoseconds(),
+		DateTime:  start,
+	}
+	defer s.writeLog(log)
+
+	if err != nil {
+		log.Result = resultNetworkError
+		log.Error = err.Error()
+		log.Code = -1
+		return
+	}
+	defer res.Body.Close()
+
+	log.Code = res.StatusCode
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		log.Result = resultNG
+		return
+	}
+	log.Result = resultOK
+
+	// レスポンスボディがあれば返信として投稿
+	if res.StatusCode == http.StatusNoContent {
+		return
+	}
+	b, err := io.ReadAll(io.LimitReader(res.Body, maxResponseBodySize))
+	if err != nil {
+		log.Result = resultNetworkError
+		log.Error = err.Error()
+		return
+	}
+	reply := strings.TrimSpace(string(b))
+	if len(reply) == 0 {
+		return
+	}
+	if !utf8.ValidString(reply) || utf8.RuneCountInString(reply) > maxReplyLength {
+		log.Result = resultNG
+		log.Error = "invalid response body"
+		return
+	}
+	if _, err := s.mm.Create(w.GetChannelID(), w.GetBotUserID(), reply); err != nil {
+		log.Result = resultNG
+		log.Error = fmt.Sprintf("failed to post reply: %s", err.Error())
+	}
+}
+
+func (s *serviceImpl) writeLog(log *model.WebhookDeliveryLog) {
+	if err := s.repo.WriteWebhookDeliveryLog(log); err != nil {
+		s.logger.Warn("failed to write log", zap.Error(err), zap.Any("deliveryLog", log))
+	}
+}
@@ -0,0 +1,34 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchTriggerWord(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		words []string
+		text  string
+		word  string
+		ok    bool
+	}{
+		{"no trigger words", nil, "hello", "", true},
+		{"match", []string{"!deploy", "!status"}, "!status please", "!status", true},
+		{"match with leading spaces", []string{"!deploy"}, "  !deploy now", "!deploy", true},
+		{"not prefix", []string{"!deploy"}, "please !deploy", "", false},
+		{"no match", []string{"!deploy"}, "hello", "", false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			word, ok := matchTriggerWord(tt.words, tt.text)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.word, word)
+		})
+	}
+}
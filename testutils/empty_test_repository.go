@@ -24,4 +24,11 @@ type EmptyTestRepository struct {
 	repository.BotRepository
 	repository.ClipRepository
 	repository.OgpCacheRepository
+	repository.TwoFactorRepository
+	repository.PersonalAccessTokenRepository
+	repository.AuditLogRepository
+	repository.ChannelTemplateRepository
+	repository.ChannelArchivalRepository
+	repository.ChannelRetentionRepository
+	repository.SidebarRepository
 }
@@ -1368,6 +1368,22 @@ func (repo *TestRepository) UpdateWebhook(id uuid.UUID, args repository.UpdateWe
 		wb.Secret = args.Secret.String
 		wb.UpdatedAt = time.Now()
 	}
+	if args.OutgoingURL.Valid {
+		wb.OutgoingURL = args.OutgoingURL.String
+		wb.UpdatedAt = time.Now()
+	}
+	if args.TriggerWords.Valid {
+		wb.TriggerWords = args.TriggerWords.String
+		wb.UpdatedAt = time.Now()
+	}
+	if args.Templates != nil {
+		wb.Templates = args.Templates
+		wb.UpdatedAt = time.Now()
+	}
+	if args.PayloadTemplate.Valid {
+		wb.PayloadTemplate = args.PayloadTemplate.String
+		wb.UpdatedAt = time.Now()
+	}
 	if args.Name.Valid {
 		if len(args.Name.String) == 0 || utf8.RuneCountInString(args.Name.String) > 32 {
 			return repository.ArgError("args.Name", "Name must be non-empty and shorter than 33 characters")
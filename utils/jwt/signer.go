@@ -2,27 +2,149 @@ package jwt
 
 import (
 	"bytes"
-	"crypto/ecdsa"
+	"crypto"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
 
 	"github.com/golang-jwt/jwt/v4"
 )
 
 var (
-	priv *ecdsa.PrivateKey
+	priv   crypto.Signer
+	method jwt.SigningMethod
+	jwk    JWK
 )
 
+// JWK JSON Web Key (RFC 7517)
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	// EC
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+	Y     string `json:"y,omitempty"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+}
+
+// JWKSet JSON Web Key Set (RFC 7517)
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
 // SetupSigner JWTを発行・検証するためのSignerのセットアップ
+//
+// ECDSA(P-256, P-384, P-521)またはRSAの秘密鍵(PEM)に対応しています。
 func SetupSigner(privRaw []byte) error {
-	_priv, err := jwt.ParseECPrivateKeyFromPEM(bytes.TrimSpace(privRaw))
-	if err != nil {
-		return err
+	privRaw = bytes.TrimSpace(privRaw)
+
+	if k, err := jwt.ParseECPrivateKeyFromPEM(privRaw); err == nil {
+		var m *jwt.SigningMethodECDSA
+		switch k.Curve.Params().BitSize {
+		case 256:
+			m = jwt.SigningMethodES256
+		case 384:
+			m = jwt.SigningMethodES384
+		case 521:
+			m = jwt.SigningMethodES512
+		default:
+			return fmt.Errorf("unsupported curve: %s", k.Curve.Params().Name)
+		}
+		size := (k.Curve.Params().BitSize + 7) / 8
+		setKey(k, m, JWK{
+			KeyType: "EC",
+			Curve:   k.Curve.Params().Name,
+			X:       encodeBigInt(k.X, size),
+			Y:       encodeBigInt(k.Y, size),
+		})
+		return nil
+	}
+
+	if k, err := jwt.ParseRSAPrivateKeyFromPEM(privRaw); err == nil {
+		setKey(k, jwt.SigningMethodRS256, JWK{
+			KeyType: "RSA",
+			N:       encodeBigInt(k.N, 0),
+			E:       encodeBigInt(big.NewInt(int64(k.E)), 0),
+		})
+		return nil
 	}
 
-	priv = _priv
-	return nil
+	return errors.New("unsupported private key: ECDSA or RSA private key is required")
+}
+
+func setKey(k crypto.Signer, m jwt.SigningMethod, key JWK) {
+	key.Use = "sig"
+	key.Algorithm = m.Alg()
+	key.KeyID = thumbprint(key)
+
+	priv = k
+	method = m
+	jwk = key
+}
+
+// thumbprint JWK Thumbprint (RFC 7638) を計算します
+func thumbprint(key JWK) string {
+	var s string
+	switch key.KeyType {
+	case "EC":
+		s = fmt.Sprintf(`{"crv":"%s","kty":"EC","x":"%s","y":"%s"}`, key.Curve, key.X, key.Y)
+	case "RSA":
+		s = fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`, key.E, key.N)
+	}
+	h := sha256.Sum256([]byte(s))
+	return base64.RawURLEncoding.EncodeToString(h[:])
+}
+
+func encodeBigInt(n *big.Int, size int) string {
+	b := n.Bytes()
+	if len(b) < size {
+		b = append(make([]byte, size-len(b)), b...)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
 }
 
 // Sign JWTの発行を行う
 func Sign(claims jwt.Claims) (string, error) {
-	return jwt.NewWithClaims(jwt.SigningMethodES256, claims).SignedString(priv)
+	t := jwt.NewWithClaims(method, claims)
+	t.Header["kid"] = jwk.KeyID
+	return t.SignedString(priv)
+}
+
+// Parse 発行したJWTを検証し、claimsに読み込みます
+func Parse(token string, claims jwt.Claims) error {
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		if t.Method.Alg() != method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method: %s", t.Method.Alg())
+		}
+		return priv.Public(), nil
+	})
+	return err
+}
+
+// SigningAlgorithm JWTの署名アルゴリズム名を返します
+func SigningAlgorithm() string {
+	return method.Alg()
+}
+
+// HashAlgorithm JWTの署名アルゴリズムで用いるハッシュ関数を返します
+func HashAlgorithm() crypto.Hash {
+	switch m := method.(type) {
+	case *jwt.SigningMethodECDSA:
+		return m.Hash
+	case *jwt.SigningMethodRSA:
+		return m.Hash
+	default:
+		return crypto.SHA256
+	}
+}
+
+// JWKS JWTの検証に用いる公開鍵のJWK Setを返します
+func JWKS() JWKSet {
+	return JWKSet{Keys: []JWK{jwk}}
 }
@@ -0,0 +1,89 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/traPtitech/traQ/utils/random"
+)
+
+// NOTE: 鍵はパッケージ変数のため、このパッケージのテストは並列実行しない
+
+func TestSetupSigner(t *testing.T) {
+	t.Run("ECDSA", func(t *testing.T) {
+		privRaw, _ := random.GenerateECDSAKey()
+		require.NoError(t, SetupSigner(privRaw))
+
+		assert.Equal(t, "ES256", SigningAlgorithm())
+		keys := JWKS().Keys
+		if assert.Len(t, keys, 1) {
+			assert.Equal(t, "EC", keys[0].KeyType)
+			assert.Equal(t, "P-256", keys[0].Curve)
+			assert.Equal(t, "ES256", keys[0].Algorithm)
+			assert.NotEmpty(t, keys[0].KeyID)
+			assert.NotEmpty(t, keys[0].X)
+			assert.NotEmpty(t, keys[0].Y)
+		}
+	})
+
+	t.Run("RSA", func(t *testing.T) {
+		k, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		privRaw := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k)})
+		require.NoError(t, SetupSigner(privRaw))
+
+		assert.Equal(t, "RS256", SigningAlgorithm())
+		keys := JWKS().Keys
+		if assert.Len(t, keys, 1) {
+			assert.Equal(t, "RSA", keys[0].KeyType)
+			assert.Equal(t, "AQAB", keys[0].E)
+			assert.NotEmpty(t, keys[0].N)
+			assert.NotEmpty(t, keys[0].KeyID)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		assert.Error(t, SetupSigner([]byte("invalid")))
+	})
+}
+
+func TestSignAndParse(t *testing.T) {
+	privRaw, _ := random.GenerateECDSAKey()
+	require.NoError(t, SetupSigner(privRaw))
+
+	token, err := Sign(jwt.MapClaims{"sub": "test"})
+	require.NoError(t, err)
+
+	parsed, _, err := new(jwt.Parser).ParseUnverified(token, jwt.MapClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, JWKS().Keys[0].KeyID, parsed.Header["kid"])
+
+	claims := jwt.MapClaims{}
+	if assert.NoError(t, Parse(token, claims)) {
+		assert.Equal(t, "test", claims["sub"])
+	}
+
+	// 別の鍵で署名されたトークン
+	otherRaw, _ := random.GenerateECDSAKey()
+	require.NoError(t, SetupSigner(otherRaw))
+	assert.Error(t, Parse(token, jwt.MapClaims{}))
+}
+
+func TestThumbprint(t *testing.T) {
+	t.Parallel()
+
+	// RFC 7638 3.1. Example JWK Thumbprint Computation
+	key := JWK{
+		KeyType: "RSA",
+		N:       "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+		E:       "AQAB",
+	}
+	assert.Equal(t, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs", thumbprint(key))
+}
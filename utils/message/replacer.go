@@ -13,6 +13,8 @@ var (
 	mentionRegex    = regexp.MustCompile(`:?[@＠]([^\s@＠]{0,31}[^\s@＠:])`)
 	userStartsRegex = regexp.MustCompile(`^[@＠]([a-zA-Z0-9_-]{1,32})`)
 	channelRegex    = regexp.MustCompile(`[#＃]([a-zA-Z0-9_/-]+)`)
+	// スタンプ名とエフェクト(:name.effect1.effect2:)
+	stampRegex = regexp.MustCompile(`:([a-zA-Z0-9_-]{1,32})((?:\.[a-zA-Z0-9_-]+)*):`)
 )
 
 const (
@@ -29,6 +31,8 @@ type ReplaceMapper interface {
 	Group(name string) (uuid.UUID, bool)
 	// User ユーザーID(lower-case) -> ユーザーUUID
 	User(name string) (uuid.UUID, bool)
+	// Stamp スタンプのエイリアス -> スタンプ名
+	Stamp(alias string) (string, bool)
 }
 
 // Replacer メッセージ埋め込み置換機
@@ -120,7 +124,17 @@ func (re *Replacer) Replace(m string) string {
 }
 
 func (re *Replacer) replaceAll(m string) string {
-	return re.replaceMention(re.replaceChannel(m))
+	return re.replaceMention(re.replaceChannel(re.replaceStamp(m)))
+}
+
+func (re *Replacer) replaceStamp(m string) string {
+	return stampRegex.ReplaceAllStringFunc(m, func(s string) string {
+		match := stampRegex.FindStringSubmatch(s)
+		if name, ok := re.mapper.Stamp(match[1]); ok {
+			return ":" + name + match[2] + ":"
+		}
+		return s
+	})
 }
 
 func (re *Replacer) replaceMention(m string) string {
@@ -11,6 +11,7 @@ type TestReplaceMapper struct {
 	ChannelMap map[string]uuid.UUID
 	UserMap    map[string]uuid.UUID
 	GroupMap   map[string]uuid.UUID
+	StampMap   map[string]string
 }
 
 func (t *TestReplaceMapper) Channel(path string) (uuid.UUID, bool) {
@@ -28,6 +29,11 @@ func (t *TestReplaceMapper) User(name string) (uuid.UUID, bool) {
 	return v, ok
 }
 
+func (t *TestReplaceMapper) Stamp(alias string) (string, bool) {
+	v, ok := t.StampMap[alias]
+	return v, ok
+}
+
 func TestReplacer_Replace(t *testing.T) {
 	t.Parallel()
 
@@ -45,6 +51,10 @@ func TestReplacer_Replace(t *testing.T) {
 			"okあok":         uuid.Must(uuid.FromString("dfabf0c9-5de0-46ee-9721-2525e8bb3d45")),
 			"takashi_trapo": uuid.Must(uuid.FromString("dfabf0c9-5de0-46ee-9721-2525e8bb3d46")),
 		},
+		StampMap: map[string]string{
+			"thumbup":  "thumbsup",
+			"pudding2": "pudding",
+		},
 	})
 
 	tt := [][]string{
@@ -116,6 +126,18 @@ func TestReplacer_Replace(t *testing.T) {
 			"@a",
 			"!{\"type\":\"user\",\"raw\":\"@a\",\"id\":\"dfdff0c9-5de0-46ee-9721-2525e8bb3d44\"}",
 		},
+		{
+			":thumbup: :thumbsup: :thumbup.large.rotate: :unknown:",
+			":thumbsup: :thumbsup: :thumbsup.large.rotate: :unknown:",
+		},
+		{
+			":thumbup::pudding2: `:thumbup:`\n```\n:pudding2:\n```",
+			":thumbsup::pudding: `:thumbup:`\n```\n:pudding2:\n```",
+		},
+		{
+			":@takashi_trap: :thumbup",
+			":@takashi_trap: :thumbup",
+		},
 	}
 	for _, v := range tt {
 		assert.Equal(t, v[1], re.Replace(v[0]))
@@ -0,0 +1,89 @@
+package stamppack
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"path"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/service/file"
+)
+
+// imageDir エクスポート時の画像ファイルのディレクトリ
+const imageDir = "images/"
+
+// Export スタンプをスタンプパックとしてwに書き出します
+//
+// Unicode絵文字スタンプは`traQ stamp install-emojis`でインストールするものなので、含めません。
+//
+// wへの書き込みを始める前に全てのスタンプのファイル情報を取得するため、その時点でのエラーではwに何も書き込まれません。
+func Export(w io.Writer, fm file.Manager, stamps []*model.Stamp) error {
+	manifest := &Manifest{Version: ManifestVersion, Stamps: make([]*ManifestStamp, 0, len(stamps))}
+	metas := make([]model.File, 0, len(stamps))
+	for _, s := range stamps {
+		if s.IsUnicode {
+			continue
+		}
+
+		meta, err := fm.Get(s.FileID)
+		if err != nil {
+			return err
+		}
+		metas = append(metas, meta)
+		manifest.Stamps = append(manifest.Stamps, &ManifestStamp{
+			Name:     s.Name,
+			File:     imageDir + s.Name + imageExt(meta),
+			Category: s.Category,
+			Aliases:  s.Aliases,
+			Tags:     s.Tags,
+		})
+	}
+
+	zw := zip.NewWriter(w)
+	for i, s := range manifest.Stamps {
+		if err := writeImage(zw, s.File, metas[i]); err != nil {
+			return err
+		}
+	}
+
+	mw, err := zw.Create(ManifestFileName)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(mw)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(manifest); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+func writeImage(zw *zip.Writer, name string, meta model.File) error {
+	src, err := meta.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	// 画像は圧縮済みなのでそのまま格納する
+	dst, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store, Modified: meta.GetCreatedAt()})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, src)
+	return err
+}
+
+func imageExt(meta model.File) string {
+	switch meta.GetMIMEType() {
+	case mimeImagePNG:
+		return ".png"
+	case mimeImageJPEG:
+		return ".jpg"
+	case mimeImageGIF:
+		return ".gif"
+	default:
+		return path.Ext(meta.GetFileName())
+	}
+}
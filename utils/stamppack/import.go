@@ -0,0 +1,296 @@
+package stamppack
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"image/png"
+	"io"
+	"net/http"
+	"path"
+
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gofrs/uuid"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/service/file"
+	"github.com/traPtitech/traQ/service/imaging"
+	imaging2 "github.com/traPtitech/traQ/utils/imaging"
+	"github.com/traPtitech/traQ/utils/optional"
+	"github.com/traPtitech/traQ/utils/validator"
+)
+
+const (
+	// POST /stamps と同じ制限
+	maxImageFileSize = 1 << 20 // 1MB
+	maxImageSize     = 128
+
+	mimeImagePNG  = "image/png"
+	mimeImageJPEG = "image/jpeg"
+	mimeImageGIF  = "image/gif"
+)
+
+// ImportStatus スタンプのインポート結果の種類
+type ImportStatus string
+
+const (
+	// ImportStatusCreated スタンプを作成した
+	ImportStatusCreated ImportStatus = "created"
+	// ImportStatusReady スタンプを作成可能 (DryRun時のみ)
+	ImportStatusReady ImportStatus = "ready"
+	// ImportStatusConflict スタンプ名・エイリアスが既に使われているため作成しなかった
+	ImportStatusConflict ImportStatus = "conflict"
+	// ImportStatusInvalid スタンプ情報や画像が不正なため作成しなかった
+	ImportStatusInvalid ImportStatus = "invalid"
+)
+
+// ImportOptions インポートオプション
+type ImportOptions struct {
+	// CreatorID 作成するスタンプの作成者 uuid.Nilを許容します
+	CreatorID uuid.UUID
+	// DryRun trueの場合、検証のみ行いスタンプを作成しません
+	DryRun bool
+}
+
+// ImportResult スタンプ1つ分のインポート結果
+type ImportResult struct {
+	Name    string        `json:"name"`
+	Status  ImportStatus  `json:"status"`
+	StampID optional.UUID `json:"stampId"`
+	Reason  string        `json:"reason,omitempty"`
+}
+
+// invalidStampError パック内のスタンプが不正であることを表すエラー
+type invalidStampError string
+
+func (e invalidStampError) Error() string {
+	return string(e)
+}
+
+// Import スタンプパックのスタンプをインストールします
+//
+// スタンプ名・エイリアスの衝突や不正なスタンプはスキップし、マニフェストの順番で各スタンプの結果を返します。
+// パック自体が不正な場合、ErrInvalidPackをラップしたエラーを返します。
+// 途中で内部エラーが発生した場合は処理を中断し、それまでに処理したスタンプの結果とエラーを返します。
+// その場合も作成済みのスタンプは削除されないため、呼び出し側で結果を確認する必要があります。
+func Import(repo repository.Repository, fm file.Manager, p imaging.Processor, zr *zip.Reader, opts ImportOptions) ([]*ImportResult, error) {
+	manifest, err := ReadManifest(zr)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	// パック内で使われたスタンプ名・エイリアス
+	used := make(map[string]struct{}, len(manifest.Stamps))
+	results := make([]*ImportResult, len(manifest.Stamps))
+	for i, s := range manifest.Stamps {
+		result := &ImportResult{Name: s.Name}
+		results[i] = result
+
+		if err := validateStamp(s); err != nil {
+			result.Status = ImportStatusInvalid
+			result.Reason = err.Error()
+			continue
+		}
+
+		// スタンプ名・エイリアスの衝突チェック
+		conflict, err := findConflict(repo, used, s)
+		if err != nil {
+			return results[:i], err
+		}
+		for _, name := range append([]string{s.Name}, s.Aliases...) {
+			used[name] = struct{}{}
+		}
+		if len(conflict) > 0 {
+			result.Status = ImportStatusConflict
+			result.Reason = conflict
+			continue
+		}
+
+		// 画像チェック
+		f, ok := files[s.File]
+		if !ok {
+			result.Status = ImportStatusInvalid
+			result.Reason = "image file is not found"
+			continue
+		}
+		args, err := processImage(p, f)
+		if err != nil {
+			if e, ok := err.(invalidStampError); ok {
+				result.Status = ImportStatusInvalid
+				result.Reason = e.Error()
+				continue
+			}
+			return results[:i], err
+		}
+
+		if opts.DryRun {
+			result.Status = ImportStatusReady
+			continue
+		}
+
+		// スタンプ作成
+		meta, err := fm.Save(*args)
+		if err != nil {
+			return results[:i], err
+		}
+		stamp, err := repo.CreateStamp(repository.CreateStampArgs{
+			Name:      s.Name,
+			FileID:    meta.GetID(),
+			CreatorID: opts.CreatorID,
+			Category:  s.Category,
+			Aliases:   s.Aliases,
+			Tags:      s.Tags,
+		})
+		if err != nil {
+			_ = fm.Delete(meta.GetID())
+			switch {
+			case err == repository.ErrAlreadyExists:
+				result.Status = ImportStatusConflict
+				result.Reason = "name or aliases are already used"
+				continue
+			case repository.IsArgError(err):
+				result.Status = ImportStatusInvalid
+				result.Reason = err.Error()
+				continue
+			default:
+				return results[:i], err
+			}
+		}
+		result.Status = ImportStatusCreated
+		result.StampID = optional.UUIDFrom(stamp.ID)
+	}
+	return results, nil
+}
+
+func validateStamp(s *ManifestStamp) error {
+	if err := vd.Validate(s.Name, validator.StampNameRuleRequired...); err != nil {
+		return invalidStampError("name must be 1-32 characters of a-zA-Z0-9_-")
+	}
+	if err := vd.Validate(s.Category, validator.StampCategoryRule...); err != nil {
+		return invalidStampError("category must be 0-32 characters")
+	}
+	if err := vd.Validate(s.Aliases, vd.Length(0, model.MaxStampAliases), vd.Each(validator.StampNameRuleRequired...)); err != nil {
+		return invalidStampError(fmt.Sprintf("aliases must be at most %d names of 1-32 characters of a-zA-Z0-9_-", model.MaxStampAliases))
+	}
+	if err := vd.Validate(s.Tags, vd.Length(0, model.MaxStampTags), vd.Each(validator.StampTagRuleRequired...)); err != nil {
+		return invalidStampError(fmt.Sprintf("tags must be at most %d tags of 1-30 characters", model.MaxStampTags))
+	}
+	return nil
+}
+
+// findConflict スタンプ名・エイリアスが既に使われているかを確認し、使われている場合はその理由を返します
+func findConflict(repo repository.Repository, used map[string]struct{}, s *ManifestStamp) (string, error) {
+	seen := make(map[string]struct{}, len(s.Aliases)+1)
+	for _, name := range append([]string{s.Name}, s.Aliases...) {
+		if _, ok := seen[name]; ok {
+			return fmt.Sprintf("%s is duplicated in the stamp", name), nil
+		}
+		seen[name] = struct{}{}
+
+		if _, ok := used[name]; ok {
+			return fmt.Sprintf("%s is duplicated in the pack", name), nil
+		}
+		if _, err := repo.GetStampByName(name); err == nil {
+			return fmt.Sprintf("%s is already used as a stamp name", name), nil
+		} else if err != repository.ErrNotFound {
+			return "", err
+		}
+		if _, err := repo.GetStampByAlias(name); err == nil {
+			return fmt.Sprintf("%s is already used as a stamp alias", name), nil
+		} else if err != repository.ErrNotFound {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+// processImage 画像を検証・リサイズし、保存用の引数を返します
+//
+// 画像が不正な場合、invalidStampErrorを返します。
+func processImage(p imaging.Processor, f *zip.File) (*file.SaveArgs, error) {
+	const (
+		tooLargeImage = "too large image"
+		badImage      = "bad image"
+	)
+
+	if f.UncompressedSize64 > maxImageFileSize {
+		return nil, invalidStampError(tooLargeImage)
+	}
+	r, err := f.Open()
+	if err != nil {
+		return nil, invalidStampError(badImage)
+	}
+	defer r.Close()
+	b, err := io.ReadAll(io.LimitReader(r, maxImageFileSize+1))
+	if err != nil {
+		return nil, invalidStampError(badImage)
+	}
+	if len(b) > maxImageFileSize {
+		return nil, invalidStampError(tooLargeImage)
+	}
+
+	args := &file.SaveArgs{
+		FileName: path.Base(f.Name),
+		FileType: model.FileTypeStamp,
+	}
+
+	switch http.DetectContentType(b) {
+	case mimeImagePNG, mimeImageJPEG:
+		img, err := p.Fit(bytes.NewReader(b), maxImageSize, maxImageSize)
+		if err != nil {
+			switch err {
+			case imaging.ErrInvalidImageSrc:
+				return nil, invalidStampError(badImage)
+			case imaging.ErrPixelLimitExceeded:
+				return nil, invalidStampError(tooLargeImage)
+			default:
+				return nil, err
+			}
+		}
+
+		// PNGに変換
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+
+		args.Src = bytes.NewReader(buf.Bytes())
+		args.FileSize = int64(buf.Len())
+		args.MimeType = mimeImagePNG
+		args.Thumbnail = img // サムネイル画像より小さいという前提
+
+	case mimeImageGIF:
+		// リサイズ
+		resized, err := p.FitAnimationGIF(bytes.NewReader(b), maxImageSize, maxImageSize)
+		if err != nil {
+			switch err {
+			case imaging2.ErrImageMagickUnavailable:
+				return nil, invalidStampError("gif file is temporarily unsupported")
+			case imaging.ErrInvalidImageSrc, imaging.ErrTimeout:
+				return nil, invalidStampError(badImage)
+			default:
+				return nil, err
+			}
+		}
+
+		args.Src = resized
+		args.FileSize = resized.Size()
+		args.MimeType = mimeImageGIF
+
+		args.Thumbnail, err = p.Thumbnail(resized)
+		if err != nil {
+			return nil, err
+		}
+		_, _ = resized.Seek(0, io.SeekStart)
+
+	default:
+		return nil, invalidStampError(badImage)
+	}
+	return args, nil
+}
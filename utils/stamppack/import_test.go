@@ -0,0 +1,98 @@
+package stamppack
+
+import (
+	"errors"
+	"image"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/traPtitech/traQ/model"
+	"github.com/traPtitech/traQ/repository"
+	"github.com/traPtitech/traQ/service/file"
+	"github.com/traPtitech/traQ/service/imaging"
+)
+
+type testRepository struct {
+	repository.Repository
+	failName string
+	created  []string
+}
+
+func (r *testRepository) GetStampByName(string) (*model.Stamp, error) {
+	return nil, repository.ErrNotFound
+}
+
+func (r *testRepository) GetStampByAlias(string) (*model.Stamp, error) {
+	return nil, repository.ErrNotFound
+}
+
+func (r *testRepository) CreateStamp(args repository.CreateStampArgs) (*model.Stamp, error) {
+	if args.Name == r.failName {
+		return nil, errors.New("db error")
+	}
+	r.created = append(r.created, args.Name)
+	return &model.Stamp{ID: uuid.Must(uuid.NewV4()), Name: args.Name, FileID: args.FileID}, nil
+}
+
+type testFile struct {
+	model.File
+	id uuid.UUID
+}
+
+func (f *testFile) GetID() uuid.UUID { return f.id }
+
+type testFileManager struct {
+	file.Manager
+	deleted []uuid.UUID
+}
+
+func (m *testFileManager) Save(file.SaveArgs) (model.File, error) {
+	return &testFile{id: uuid.Must(uuid.NewV4())}, nil
+}
+
+func (m *testFileManager) Delete(id uuid.UUID) error {
+	m.deleted = append(m.deleted, id)
+	return nil
+}
+
+func TestImport_InternalError(t *testing.T) {
+	t.Parallel()
+
+	p := imaging.NewProcessor(imaging.Config{
+		MaxPixels:        500 * 500,
+		Concurrency:      1,
+		ThumbnailMaxSize: image.Point{X: 50, Y: 50},
+	})
+	img := makePNG(t, 64, 64)
+	zr := makeZip(t, map[string][]byte{
+		ManifestFileName: []byte(`{"version":1,"stamps":[
+			{"name":"a","file":"a.png"},
+			{"name":"b","file":"missing.png"},
+			{"name":"c","file":"c.png"},
+			{"name":"d","file":"d.png"}
+		]}`),
+		"a.png": img,
+		"c.png": img,
+		"d.png": img,
+	})
+	repo := &testRepository{failName: "c"}
+	fm := &testFileManager{}
+
+	results, err := Import(repo, fm, p, zr, ImportOptions{})
+	assert.EqualError(t, err, "db error")
+
+	// 中断するまでに処理したスタンプの結果が返る
+	require.Len(t, results, 2)
+	assert.Equal(t, "a", results[0].Name)
+	assert.Equal(t, ImportStatusCreated, results[0].Status)
+	assert.True(t, results[0].StampID.Valid)
+	assert.Equal(t, "b", results[1].Name)
+	assert.Equal(t, ImportStatusInvalid, results[1].Status)
+
+	// 作成に失敗したスタンプの画像は削除され、以降のスタンプは作成されない
+	assert.Equal(t, []string{"a"}, repo.created)
+	assert.Len(t, fm.deleted, 1)
+}
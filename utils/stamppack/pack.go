@@ -0,0 +1,137 @@
+// Package stamppack スタンプパック(スタンプの画像とメタデータをまとめたzipファイル)の読み書き
+//
+// スタンプパックは、ルートに manifest.json を含むzipファイルです。
+// manifest.json を含まないzipファイルは、Slack・Discordの絵文字エクスポートのような
+// 「ファイル名がスタンプ名の画像ファイルの集まり」として扱います。
+package stamppack
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	vd "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const (
+	// ManifestFileName マニフェストファイルのファイル名
+	ManifestFileName = "manifest.json"
+	// ManifestVersion マニフェストのバージョン
+	ManifestVersion = 1
+	// MaxStamps 1つのパックに含められるスタンプの最大数
+	MaxStamps = 1000
+
+	maxManifestSize = 1 << 20 // 1MB
+)
+
+// ErrInvalidPack 不正なスタンプパックです
+var ErrInvalidPack = errors.New("invalid stamp pack")
+
+// Manifest スタンプパックのマニフェスト
+type Manifest struct {
+	Version int              `json:"version"`
+	Stamps  []*ManifestStamp `json:"stamps"`
+}
+
+// ManifestStamp マニフェストのスタンプ情報
+type ManifestStamp struct {
+	// Name スタンプ名
+	Name string `json:"name"`
+	// File zipファイル内の画像ファイルのパス
+	File string `json:"file"`
+	// Category カテゴリー
+	Category string `json:"category,omitempty"`
+	// Aliases エイリアス
+	Aliases []string `json:"aliases,omitempty"`
+	// Tags タグ
+	Tags []string `json:"tags,omitempty"`
+}
+
+func (m Manifest) Validate() error {
+	return vd.ValidateStruct(&m,
+		vd.Field(&m.Version, vd.Required, vd.In(ManifestVersion)),
+		vd.Field(&m.Stamps, vd.Length(0, MaxStamps)),
+	)
+}
+
+// ReadManifest zipファイルからマニフェストを読み込みます
+//
+// manifest.json が含まれていない場合、画像ファイルのファイル名からマニフェストを生成します。
+// マニフェストが不正な場合、ErrInvalidPackをラップしたエラーを返します。
+// 個々のスタンプの内容は検証しません。
+func ReadManifest(zr *zip.Reader) (*Manifest, error) {
+	for _, f := range zr.File {
+		if f.Name == ManifestFileName {
+			return readManifestFile(f)
+		}
+	}
+	return deriveManifest(zr)
+}
+
+func readManifestFile(f *zip.File) (*Manifest, error) {
+	if f.UncompressedSize64 > maxManifestSize {
+		return nil, fmt.Errorf("%w: too large manifest", ErrInvalidPack)
+	}
+	r, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPack, err)
+	}
+	defer r.Close()
+
+	var m Manifest
+	if err := json.NewDecoder(io.LimitReader(r, maxManifestSize)).Decode(&m); err != nil {
+		return nil, fmt.Errorf("%w: bad manifest: %v", ErrInvalidPack, err)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: bad manifest: %v", ErrInvalidPack, err)
+	}
+	return &m, nil
+}
+
+// deriveManifest Slack・Discordの絵文字エクスポート形式のzipファイルからマニフェストを生成します
+func deriveManifest(zr *zip.Reader) (*Manifest, error) {
+	m := &Manifest{Version: ManifestVersion, Stamps: make([]*ManifestStamp, 0)}
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || isHiddenPath(f.Name) {
+			continue
+		}
+		ext := strings.ToLower(path.Ext(f.Name))
+		if !isImageExt(ext) {
+			continue
+		}
+		m.Stamps = append(m.Stamps, &ManifestStamp{
+			Name: strings.TrimSuffix(path.Base(f.Name), path.Ext(f.Name)),
+			File: f.Name,
+		})
+	}
+	if len(m.Stamps) == 0 {
+		return nil, fmt.Errorf("%w: neither manifest nor images are found", ErrInvalidPack)
+	}
+	if len(m.Stamps) > MaxStamps {
+		return nil, fmt.Errorf("%w: too many stamps", ErrInvalidPack)
+	}
+	return m, nil
+}
+
+// isHiddenPath macOSのリソースフォークなど、隠しファイル・ディレクトリのパスかどうか
+func isHiddenPath(p string) bool {
+	for _, e := range strings.Split(p, "/") {
+		if strings.HasPrefix(e, ".") || e == "__MACOSX" {
+			return true
+		}
+	}
+	return false
+}
+
+func isImageExt(ext string) bool {
+	switch ext {
+	case ".png", ".jpg", ".jpeg", ".gif":
+		return true
+	default:
+		return false
+	}
+}
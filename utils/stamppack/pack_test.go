@@ -0,0 +1,185 @@
+package stamppack
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/traPtitech/traQ/service/imaging"
+)
+
+func makeZip(t *testing.T, files map[string][]byte) *zip.Reader {
+	t.Helper()
+	var b bytes.Buffer
+	zw := zip.NewWriter(&b)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	zr, err := zip.NewReader(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	require.NoError(t, err)
+	return zr
+}
+
+func makePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	require.NoError(t, png.Encode(&b, image.NewRGBA(image.Rect(0, 0, width, height))))
+	return b.Bytes()
+}
+
+func TestReadManifest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("manifest", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		zr := makeZip(t, map[string][]byte{
+			ManifestFileName: []byte(`{"version":1,"stamps":[{"name":"a","file":"images/a.png","category":"cat","aliases":["b"],"tags":["t"]}]}`),
+			"images/a.png":   {},
+		})
+		m, err := ReadManifest(zr)
+		if assert.NoError(err) && assert.Len(m.Stamps, 1) {
+			assert.Equal(&ManifestStamp{Name: "a", File: "images/a.png", Category: "cat", Aliases: []string{"b"}, Tags: []string{"t"}}, m.Stamps[0])
+		}
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		t.Parallel()
+
+		zr := makeZip(t, map[string][]byte{ManifestFileName: []byte(`{"version":2,"stamps":[]}`)})
+		_, err := ReadManifest(zr)
+		assert.True(t, errors.Is(err, ErrInvalidPack))
+	})
+
+	t.Run("broken manifest", func(t *testing.T) {
+		t.Parallel()
+
+		zr := makeZip(t, map[string][]byte{ManifestFileName: []byte(`{`)})
+		_, err := ReadManifest(zr)
+		assert.True(t, errors.Is(err, ErrInvalidPack))
+	})
+
+	t.Run("emoji export", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		zr := makeZip(t, map[string][]byte{
+			"emojis/party_parrot.gif":         {},
+			"emojis/ok.PNG":                   {},
+			"emojis/readme.txt":               {},
+			"__MACOSX/emojis/._ok.PNG":        {},
+			"emojis/.hidden/secret_emoji.png": {},
+		})
+		m, err := ReadManifest(zr)
+		if assert.NoError(err) && assert.Len(m.Stamps, 2) {
+			names := map[string]string{}
+			for _, s := range m.Stamps {
+				names[s.Name] = s.File
+			}
+			assert.Equal(map[string]string{"party_parrot": "emojis/party_parrot.gif", "ok": "emojis/ok.PNG"}, names)
+		}
+	})
+
+	t.Run("no images", func(t *testing.T) {
+		t.Parallel()
+
+		zr := makeZip(t, map[string][]byte{"readme.txt": {}})
+		_, err := ReadManifest(zr)
+		assert.True(t, errors.Is(err, ErrInvalidPack))
+	})
+}
+
+func TestValidateStamp(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		stamp *ManifestStamp
+		valid bool
+	}{
+		{"valid", &ManifestStamp{Name: "a", Aliases: []string{"b"}, Tags: []string{"tag"}}, true},
+		{"invalid name", &ManifestStamp{Name: "+1"}, false},
+		{"empty name", &ManifestStamp{Name: ""}, false},
+		{"invalid alias", &ManifestStamp{Name: "a", Aliases: []string{"あ"}}, false},
+		{"too many aliases", &ManifestStamp{Name: "a", Aliases: []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11"}}, false},
+		{"empty tag", &ManifestStamp{Name: "a", Tags: []string{""}}, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := validateStamp(tt.stamp)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.IsType(t, invalidStampError(""), err)
+			}
+		})
+	}
+}
+
+func TestProcessImage(t *testing.T) {
+	t.Parallel()
+
+	p := imaging.NewProcessor(imaging.Config{
+		MaxPixels:        500 * 500,
+		Concurrency:      1,
+		ThumbnailMaxSize: image.Point{X: 50, Y: 50},
+	})
+	zr := makeZip(t, map[string][]byte{
+		"small.png": makePNG(t, 64, 64),
+		"large.png": makePNG(t, 256, 256),
+		"huge.png":  makePNG(t, 1000, 1000),
+		"text.png":  []byte("not an image"),
+	})
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	t.Run("resize", func(t *testing.T) {
+		t.Parallel()
+		assert := assert.New(t)
+
+		args, err := processImage(p, files["large.png"])
+		if assert.NoError(err) {
+			assert.Equal("large.png", args.FileName)
+			assert.Equal(mimeImagePNG, args.MimeType)
+			assert.Equal(image.Rect(0, 0, maxImageSize, maxImageSize), args.Thumbnail.Bounds())
+		}
+	})
+
+	t.Run("small", func(t *testing.T) {
+		t.Parallel()
+
+		args, err := processImage(p, files["small.png"])
+		if assert.NoError(t, err) {
+			assert.Equal(t, image.Rect(0, 0, 64, 64), args.Thumbnail.Bounds())
+		}
+	})
+
+	t.Run("pixel limit exceeded", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := processImage(p, files["huge.png"])
+		assert.EqualError(t, err, "too large image")
+	})
+
+	t.Run("bad image", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := processImage(p, files["text.png"])
+		assert.EqualError(t, err, "bad image")
+	})
+}
@@ -119,6 +119,7 @@ func Install(repo repository.Repository, fm file.Manager, logger *zap.Logger, up
 				FileID:    meta.GetID(),
 				CreatorID: uuid.Nil,
 				IsUnicode: true,
+				Category:  emoji.Category,
 			})
 			if err != nil {
 				return err
@@ -135,10 +136,15 @@ func Install(repo repository.Repository, fm file.Manager, logger *zap.Logger, up
 			if err != nil {
 				return err
 			}
-
-			if err := repo.UpdateStamp(s.ID, repository.UpdateStampArgs{
+			args := repository.UpdateStampArgs{
 				FileID: optional.UUIDFrom(meta.GetID()),
-			}); err != nil {
+			}
+			// 管理者が設定したカテゴリーは上書きしない
+			if len(s.Category) == 0 {
+				args.Category = optional.StringFrom(emoji.Category)
+			}
+
+			if err := repo.UpdateStamp(s.ID, args); err != nil {
 				return err
 			}
 
@@ -83,6 +83,21 @@ var StampNameRuleRequired = append([]vd.Rule{
 	vd.Required,
 }, StampNameRule...)
 
+// StampCategoryRule スタンプカテゴリーバリデーションルール
+var StampCategoryRule = []vd.Rule{
+	vd.RuneLength(0, 32),
+}
+
+// StampTagRule スタンプタグバリデーションルール
+var StampTagRule = []vd.Rule{
+	vd.RuneLength(1, 30),
+}
+
+// StampTagRuleRequired スタンプタグバリデーションルール with Required
+var StampTagRuleRequired = append([]vd.Rule{
+	vd.Required,
+}, StampTagRule...)
+
 // StampPaletteNameRule スタンプパレット名バリデーションルール
 var StampPaletteNameRule = []vd.Rule{
 	vd.RuneLength(1, 30),